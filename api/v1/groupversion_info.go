@@ -19,6 +19,10 @@ limitations under the License.
 // +groupName=e2etest.grpc.io
 package v1
 
+// The client models are generated from the CRD, which controller-gen
+// generates from these types, so "make manifests" must run first.
+//go:generate go run ../../tools/cmd/gen_models -crd ../../config/crd/bases/e2etest.grpc.io_loadtests.yaml -python ../../clients/python/grpc_test_infra/models.py -typescript ../../clients/typescript/src/models.ts
+
 import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
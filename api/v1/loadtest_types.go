@@ -18,7 +18,10 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grpc/test-infra/failure"
 )
 
 // NOTE: AFTER EDITS, YOU MUST RUN `make manifests` AND `make` TO REGENERATE
@@ -79,6 +82,23 @@ type Build struct {
 	Env []corev1.EnvVar `json:"env,omitempty"`
 }
 
+// ResourceBudget limits the resources that the run container of a component
+// may consume. The budget is enforced as limits on the container. When the
+// container exceeds its memory budget it is killed, and when it exceeds its
+// ephemeral storage budget its pod is evicted. In both cases, the load test
+// is marked as errored with the ResourceExceeded reason.
+type ResourceBudget struct {
+	// Memory is the maximum amount of memory that the run container may
+	// use, such as "4Gi".
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// EphemeralStorage is the maximum amount of local disk that the run
+	// container may use for its writable layer and logs, such as "10Gi".
+	// +optional
+	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty"`
+}
+
 // Driver defines a component that orchestrates the server and clients in the
 // test.
 type Driver struct {
@@ -99,6 +119,7 @@ type Driver struct {
 
 	// Pool specifies the name of the set of nodes where this driver should be
 	// scheduled. If unset, the controller will choose a pool based on defaults.
+	// +kubebuilder:validation:MinLength:=1
 	// +optional
 	Pool *string `json:"pool,omitempty"`
 
@@ -129,6 +150,22 @@ type Driver struct {
 	// Run describes a list of run containers. The container for the test driver is always
 	// the first container on the list.
 	Run []corev1.Container `json:"run"`
+
+	// HostAliases are entries that are added to the /etc/hosts file of the
+	// driver pod. They allow the driver to address hosts by stable names, for
+	// example to match the subject alternative names of a TLS certificate.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSConfig specifies DNS parameters for the driver pod. These parameters
+	// are merged with the ones generated from the DNS policy of the pod.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// Budget limits the memory and ephemeral storage of the run container
+	// of the driver. Values set here override the limits of the container.
+	// +optional
+	Budget *ResourceBudget `json:"budget,omitempty"`
 }
 
 // Server defines a component that receives traffic from a set of client
@@ -152,6 +189,7 @@ type Server struct {
 
 	// Pool specifies the name of the set of nodes where this server should be
 	// scheduled. If unset, the controller will choose a pool based on defaults.
+	// +kubebuilder:validation:MinLength:=1
 	// +optional
 	Pool *string `json:"pool,omitempty"`
 
@@ -182,7 +220,38 @@ type Server struct {
 	// the first container on the list.
 	Run []corev1.Container `json:"run"`
 
+	// HostAliases are entries that are added to the /etc/hosts file of the
+	// server pod. They allow the server to address hosts by stable names, for
+	// example to match the subject alternative names of a TLS certificate.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSConfig specifies DNS parameters for the server pod. These parameters
+	// are merged with the ones generated from the DNS policy of the pod.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// MetricsPort is the port where the server serves metrics. Its value
+	// is available to the run container in the $METRICS_PORT environment
+	// variable. If another container in the pod declares the same port,
+	// the next free port is used instead, and its number is recorded in the
+	// e2etest.grpc.io/metrics-port annotation of the pod.
+	// +optional
 	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// PprofPort is the port where a Go worker serves the net/http/pprof
+	// handlers, such as the port passed to the --pprof_port flag of the
+	// grpc-go benchmark worker. Its value is available to the run
+	// container in the $PPROF_PORT environment variable. When set, CPU and
+	// heap profiles can be collected from the server during the benchmark.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	PprofPort int32 `json:"pprofPort,omitempty"`
+
+	// Budget limits the memory and ephemeral storage of the run container
+	// of the server. Values set here override the limits of the container.
+	// +optional
+	Budget *ResourceBudget `json:"budget,omitempty"`
 }
 
 // Client defines a component that sends traffic to a server component.
@@ -208,9 +277,19 @@ type Client struct {
 
 	// Pool specifies the name of the set of nodes where this client should be
 	// scheduled. If unset, the controller will choose a pool based on defaults.
+	// +kubebuilder:validation:MinLength:=1
 	// +optional
 	Pool *string `json:"pool,omitempty"`
 
+	// Replicas is the number of identical client pods created from this
+	// spec. When it is greater than one, the name of each pod is suffixed
+	// with its index, so a client named "scale" with 3 replicas results in
+	// clients named "scale-0", "scale-1" and "scale-2". The driver receives
+	// the addresses of all replicas. If unset, a single client is created.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// Clone specifies the repository and snapshot where the code for the client
 	// can be found. This field should not be set if the code has been prebuilt
 	// in the run image.
@@ -238,22 +317,170 @@ type Client struct {
 	// the first container on the list.
 	Run []corev1.Container `json:"run"`
 
+	// HostAliases are entries that are added to the /etc/hosts file of the
+	// client pod. They allow the client to address hosts by stable names, for
+	// example to match the subject alternative names of a TLS certificate.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSConfig specifies DNS parameters for the client pod. These parameters
+	// are merged with the ones generated from the DNS policy of the pod.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// MetricsPort is the port where the client serves metrics. Its value
+	// is available to the run container in the $METRICS_PORT environment
+	// variable. If another container in the pod declares the same port,
+	// the next free port is used instead, and its number is recorded in the
+	// e2etest.grpc.io/metrics-port annotation of the pod.
+	// +optional
 	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// PprofPort is the port where a Go worker serves the net/http/pprof
+	// handlers, such as the port passed to the --pprof_port flag of the
+	// grpc-go benchmark worker. Its value is available to the run
+	// container in the $PPROF_PORT environment variable. When set, CPU and
+	// heap profiles can be collected from the client during the benchmark.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	PprofPort int32 `json:"pprofPort,omitempty"`
+
+	// Budget limits the memory and ephemeral storage of the run container
+	// of the client. Values set here override the limits of the container.
+	// +optional
+	Budget *ResourceBudget `json:"budget,omitempty"`
+
+	// XdsBootstrap selects how a proxyless client of a PSM test receives
+	// its xDS bootstrap configuration. When unset, the bootstrap file is
+	// written by the xds-server container to a volume mounted at /bootstrap
+	// in the run container. It has no effect on clients without an
+	// xds-server container, or with a sidecar container.
+	// +optional
+	XdsBootstrap XdsBootstrapMode `json:"xdsBootstrap,omitempty"`
+
+	// CSDSPort is the port where a proxyless client of a PSM test serves
+	// the Client Status Discovery Service (CSDS). Its value is available to
+	// the run container in the $CSDS_PORT environment variable. When set,
+	// the xds-server container fetches the xDS configuration of the client
+	// while it runs, and writes the last configuration it fetched to its
+	// log when the client stops.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	CSDSPort int32 `json:"csdsPort,omitempty"`
 }
 
 // Results defines where and how test results and artifacts should be
 // stored.
 type Results struct {
+	// Destination names one of the result destinations declared in the
+	// defaults of the controller, such as the BigQuery table of an
+	// environment. The controller fills BigQueryTable and GCSPrefix from
+	// the destination, unless they are set on the test. The test errors if
+	// no destination has this name.
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	Destination *string `json:"destination,omitempty"`
+
 	// BigQueryTable names a dataset where the results of the test
 	// should be stored. If omitted, no results are saved to BigQuery.
+	//
+	// Deprecated: Set Destination instead, so the table is declared once in
+	// the defaults. Tests that only set a table are linked to the
+	// destination with the same table and GCSPrefix, if there is one.
 	// +optional
 	BigQueryTable *string `json:"bigQueryTable,omitempty"`
+
+	// GCSPrefix is a Cloud Storage URI, such as gs://bucket/path, under
+	// which the raw JSON output of the driver should be stored. The
+	// driver uploads the output to an object named after the namespace,
+	// name and UID of the test, and the URI of the object is recorded in
+	// the status. The service account of the driver pod must be allowed
+	// to create objects in the bucket, for example through workload
+	// identity. If omitted, the raw output is not stored.
+	// +kubebuilder:validation:Pattern:=`^gs://[a-z0-9][-_.a-z0-9]*[a-z0-9](/.*)?$`
+	// +optional
+	GCSPrefix *string `json:"gcsPrefix,omitempty"`
+}
+
+// Generator defines a component that sends load to a gRPC service on its own,
+// without taking instructions from the driver. For example, a generator may
+// run ghz against a server in the test or a service that is already deployed.
+type Generator struct {
+	// Name is a string that distinguishes this generator from others in the
+	// test. When unset, the operator will assign a name to the generator.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Pool specifies the name of the set of nodes where this generator
+	// should be scheduled. If unset, the generator is scheduled in the
+	// default pool for clients.
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	Pool *string `json:"pool,omitempty"`
+
+	// Adapter is the format of the report that the generator writes to the
+	// termination message of its first run container. The report is parsed
+	// into the result summary of the test. When omitted, the report is not
+	// parsed, and only the exit code of the generator is considered.
+	// +optional
+	Adapter GeneratorAdapter `json:"adapter,omitempty"`
+
+	// Run describes a list of run containers. The container for the
+	// generator is always the first container on the list, and it must set
+	// an image.
+	// +kubebuilder:validation:MinItems:=1
+	Run []corev1.Container `json:"run"`
+
+	// HostAliases are entries that are added to the /etc/hosts file of the
+	// generator pod. They allow the generator to address the service under
+	// test by a stable name.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNSConfig specifies DNS parameters for the generator pod. These
+	// parameters are merged with the ones generated from the DNS policy of
+	// the pod.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// Budget limits the memory and ephemeral storage of the run container
+	// of the generator. Values set here override the limits of the container.
+	// +optional
+	Budget *ResourceBudget `json:"budget,omitempty"`
+}
+
+// Interop configures a test that runs the gRPC interop client suite against a
+// server, instead of a benchmark. The driver of such a test is the interop
+// client, which runs each test case in turn and reports their results.
+type Interop struct {
+	// TestCases are the names of the interop test cases that the client
+	// runs, such as "empty_unary" or "large_unary". The names are passed to
+	// the --test_case flag of the client.
+	// +kubebuilder:validation:MinItems:=1
+	TestCases []string `json:"testCases"`
+
+	// Server is the name of the server that the client connects to. If
+	// unset, the client connects to the first server of the test.
+	// +optional
+	Server *string `json:"server,omitempty"`
+
+	// UseTLS enables TLS on the connection between the client and the
+	// server. The server must be started with TLS enabled, too.
+	// +optional
+	UseTLS bool `json:"useTLS,omitempty"`
 }
 
 // LoadTestSpec defines the desired state of LoadTest
+// +kubebuilder:validation:XValidation:rule="!has(self.timeoutSeconds) || self.ttlSeconds >= self.timeoutSeconds",message="ttlSeconds must be greater than or equal to timeoutSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.killAfterSeconds) || !has(self.timeoutSeconds) || self.killAfterSeconds < self.timeoutSeconds",message="killAfterSeconds must be less than timeoutSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.terminationGracePeriodSeconds) || !has(self.timeoutSeconds) || self.terminationGracePeriodSeconds <= self.timeoutSeconds",message="terminationGracePeriodSeconds must not exceed timeoutSeconds"
+// +kubebuilder:validation:XValidation:rule="(has(self.servers) && size(self.servers) > 0) || (has(self.generators) && size(self.generators) > 0)",message="at least one server is required"
+// +kubebuilder:validation:XValidation:rule="(has(self.clients) && size(self.clients) > 0) || (has(self.generators) && size(self.generators) > 0) || has(self.interop)",message="at least one client is required"
 type LoadTestSpec struct {
 	// Driver is the component that orchestrates the test. It may be
 	// unspecified, allowing the system to choose the appropriate driver.
+	// Tests with generators do not receive a default driver, since the
+	// generators send load without instructions from a driver.
 	// +optional
 	Driver *Driver `json:"driver,omitempty"`
 
@@ -266,6 +493,20 @@ type LoadTestSpec struct {
 	// +optional
 	Clients []Client `json:"clients,omitempty"`
 
+	// Generators are a list of components that send load without the
+	// driver, such as ghz. When a test has generators and no driver, it
+	// succeeds once every generator has terminated successfully. Servers and
+	// clients are optional in such tests.
+	// +optional
+	Generators []Generator `json:"generators,omitempty"`
+
+	// Interop turns the test into a run of the gRPC interop client suite.
+	// The driver runs the interop client once for each test case against a
+	// server, and the test fails if the client fails any of them. Interop
+	// tests do not need clients or scenarios.
+	// +optional
+	Interop *Interop `json:"interop,omitempty"`
+
 	// Results configures where the results of the test should be
 	// stored. When omitted, the results will only be stored in
 	// Kubernetes for a limited time.
@@ -278,15 +519,266 @@ type LoadTestSpec struct {
 	// +optional
 	ScenariosJSON string `json:"scenariosJSON,omitempty"`
 
+	// LoadProfile sets the load that the clients offer in each scenario,
+	// replacing the load_params of the client_config in ScenariosJSON. A
+	// profile with several steps repeats each scenario once per step, so a
+	// range of loads can be measured without editing the scenarios. The
+	// timeout must be long enough to cover every step.
+	// +optional
+	LoadProfile *LoadProfile `json:"loadProfile,omitempty"`
+
 	// Timeout provides the longest running time allowed for a LoadTest.
+	// When omitted, the controller computes it from the warmup and
+	// benchmark time of the scenarios, the steps of the load profile, the
+	// number of workers and the soak hours of the test.
 	// +kubebuilder:validation:Minimum:=1
-	TimeoutSeconds int32 `json:"timeoutSeconds"`
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 
 	// TTL provides the longest time a LoadTest can live on the cluster.
 	// +kubebuilder:validation:Minimum:=1
 	TTLSeconds int32 `json:"ttlSeconds"`
+
+	// KillAfterSeconds overrides the killAfter setting of the controller
+	// for this test. It is the time allowed for the containers of the test
+	// to respond after the timeout before they are killed, and is passed to
+	// them in the KILL_AFTER environment variable. It must be less than the
+	// timeout.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	KillAfterSeconds *int32 `json:"killAfterSeconds,omitempty"`
+
+	// TerminationGracePeriodSeconds overrides the
+	// terminationGracePeriodSeconds setting of the controller for this test.
+	// It is the time given to the pods of the test to stop after the test is
+	// deleted. It must be longer than the 10 second stop delay of the
+	// workers, and must not exceed the timeout.
+	// +kubebuilder:validation:Minimum:=11
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// SoakHours enables soak testing, keeping the scenario running for many
+	// hours to detect slow resource leaks. Each time the driver succeeds
+	// before this many hours have elapsed, the pods for the test are
+	// recreated and the scenario is run again. Results of each iteration are
+	// saved as they complete, and a checkpoint is recorded in the status.
+	// The timeout must be long enough to cover the entire soak.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	SoakHours *int32 `json:"soakHours,omitempty"`
+
+	// IPFamily selects the IP family of the addresses that the components
+	// of the test use to communicate. IPv6 requires a cluster with IPv6 pod
+	// networking, and DualStack requires a dual-stack cluster. When unset,
+	// the default family of the cluster is used.
+	// +optional
+	IPFamily IPFamily `json:"ipFamily,omitempty"`
+
+	// NetworkProfile emulates a wide area network between the workers of
+	// the test. When set, the traffic that leaves each client and server
+	// pod is shaped with netem, so a round trip between a client and a
+	// server experiences twice the configured latency. When omitted, the
+	// traffic is not shaped.
+	// +optional
+	NetworkProfile *NetworkProfile `json:"networkProfile,omitempty"`
+
+	// ServiceMesh injects the sidecar proxy of a service mesh into the
+	// client and server pods, so that the traffic between them passes
+	// through the mesh. The mesh must be installed on the cluster. The
+	// driver is excluded from the mesh, and connects to the workers
+	// without passing through their proxies. When unset, no proxies are
+	// injected by the test.
+	// +optional
+	ServiceMesh ServiceMesh `json:"serviceMesh,omitempty"`
+
+	// PlacementPolicy determines whether the pods of the test require
+	// nodes of their own. Shared placement is intended for functional
+	// tests, whose results do not depend on the performance of the nodes.
+	// Isolated placement also taints the nodes of the test, so that no
+	// other workload lands on them. When unset, each pod is placed on its
+	// own node.
+	// +optional
+	PlacementPolicy PlacementPolicy `json:"placementPolicy,omitempty"`
+
+	// RestartWorkers restarts the clients and servers between the scenarios
+	// of the test, so that state left in a worker by one scenario does not
+	// skew the results of the next. The driver runs each scenario on its own
+	// and asks the workers to quit after it, and the run container of each
+	// worker starts its command again for the next scenario. The time the
+	// workers take to restart is recorded in the metadata of the results.
+	// The first run container of each client and server must set a command.
+	// It has no effect on tests with a single scenario.
+	// +optional
+	RestartWorkers bool `json:"restartWorkers,omitempty"`
 }
 
+// NetworkProfile defines the conditions that are emulated on the network
+// interface of a worker pod.
+type NetworkProfile struct {
+	// LatencyMs is the delay, in milliseconds, added to each packet.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LatencyMs int32 `json:"latencyMs,omitempty"`
+
+	// JitterMs is the random variation, in milliseconds, of the delay added
+	// to each packet. It has no effect unless LatencyMs is also set.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	JitterMs int32 `json:"jitterMs,omitempty"`
+
+	// LossPct is the percentage of packets that are dropped, such as "0.1".
+	// +kubebuilder:validation:Pattern=`^(100|[0-9]{1,2})(\.[0-9]+)?$`
+	// +optional
+	LossPct string `json:"lossPct,omitempty"`
+
+	// Rate limits the bandwidth of the network interface. It uses the
+	// units of tc, such as "100mbit" or "1gbit".
+	// +kubebuilder:validation:Pattern=`^[0-9]+(bit|kbit|mbit|gbit)$`
+	// +optional
+	Rate string `json:"rate,omitempty"`
+}
+
+// LoadProfileType is the type of the load that clients offer to servers.
+// +kubebuilder:validation:Enum=ClosedLoop;Poisson
+type LoadProfileType string
+
+const (
+	// ClosedLoopLoad sends each query as soon as the previous query on the
+	// same channel completes, measuring the throughput of the servers.
+	ClosedLoopLoad LoadProfileType = "ClosedLoop"
+
+	// PoissonLoad sends queries at random intervals that follow a Poisson
+	// process, with a mean rate set by the offered load, measuring latency
+	// under a fixed load.
+	PoissonLoad LoadProfileType = "Poisson"
+)
+
+// LoadProfile defines the load that the clients of a test offer. Profiles of
+// the Poisson type set exactly one of OfferedLoad, Steps and Ramp.
+type LoadProfile struct {
+	// Type is the type of the load.
+	Type LoadProfileType `json:"type"`
+
+	// OfferedLoad is the number of queries per second that each client
+	// offers with a Poisson load.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	OfferedLoad int32 `json:"offeredLoad,omitempty"`
+
+	// Steps lists the loads, in queries per second per client, that are
+	// offered in turn with a Poisson load. Each scenario is run once for
+	// each step.
+	// +optional
+	Steps []int32 `json:"steps,omitempty"`
+
+	// Ramp offers a Poisson load that increases linearly between two
+	// values. Each scenario is run once for each step of the ramp.
+	// +optional
+	Ramp *LoadRamp `json:"ramp,omitempty"`
+}
+
+// LoadRamp defines a series of loads that increase linearly.
+type LoadRamp struct {
+	// From is the load of the first step, in queries per second per client.
+	// +kubebuilder:validation:Minimum:=1
+	From int32 `json:"from"`
+
+	// To is the load of the last step, in queries per second per client.
+	// The last step is omitted if it is not reached by whole increments.
+	// +kubebuilder:validation:Minimum:=1
+	To int32 `json:"to"`
+
+	// Increment is the difference between the loads of consecutive steps.
+	// +kubebuilder:validation:Minimum:=1
+	Increment int32 `json:"increment"`
+}
+
+// IPFamily is the IP family of the addresses used within a load test.
+// +kubebuilder:validation:Enum=IPv4;IPv6;DualStack
+type IPFamily string
+
+const (
+	// IPv4Family indicates that components communicate over IPv4.
+	IPv4Family IPFamily = "IPv4"
+
+	// IPv6Family indicates that components communicate over IPv6.
+	IPv6Family IPFamily = "IPv6"
+
+	// DualStackFamily indicates that components are reachable over both
+	// IPv4 and IPv6. Services list IPv6 as their primary family, so that
+	// IPv6 is exercised whenever a client prefers the first address.
+	DualStackFamily IPFamily = "DualStack"
+)
+
+// ServiceMesh is a service mesh whose sidecar proxies are injected into the
+// worker pods of a load test.
+// +kubebuilder:validation:Enum=Istio;Linkerd
+type ServiceMesh string
+
+const (
+	// IstioServiceMesh injects the Envoy sidecar of Istio.
+	IstioServiceMesh ServiceMesh = "Istio"
+
+	// LinkerdServiceMesh injects the sidecar proxy of Linkerd.
+	LinkerdServiceMesh ServiceMesh = "Linkerd"
+)
+
+// XdsBootstrapMode is the way a proxyless client of a PSM test receives its
+// xDS bootstrap configuration.
+// +kubebuilder:validation:Enum=Volume;Env
+type XdsBootstrapMode string
+
+const (
+	// VolumeXdsBootstrap shares the bootstrap file written by the
+	// xds-server container with the run container through a volume mounted
+	// at /bootstrap.
+	VolumeXdsBootstrap XdsBootstrapMode = "Volume"
+
+	// EnvXdsBootstrap sets the contents of the bootstrap file in the
+	// GRPC_XDS_BOOTSTRAP_CONFIG environment variable of the run container,
+	// so the client does not need a writable /bootstrap path. The contents
+	// are generated by the controller from the flags of the xds-server
+	// container, which must not set -bootstrap-template or
+	// -path-to-bootstrap.
+	EnvXdsBootstrap XdsBootstrapMode = "Env"
+)
+
+// PlacementPolicy determines how the pods of a load test are placed on nodes.
+// +kubebuilder:validation:Enum=Exclusive;Shared;Isolated
+type PlacementPolicy string
+
+const (
+	// ExclusivePlacement places each pod on a node that runs no other pod
+	// of any load test. Each worker listens for the driver on DriverPort.
+	ExclusivePlacement PlacementPolicy = "Exclusive"
+
+	// SharedPlacement allows several pods to be placed on the same node.
+	// The driver port of each worker is allocated by the controller and
+	// published to the worker through the downward API, instead of being
+	// fixed. Pods of tests with shared placement are never placed on the
+	// same node as pods of tests with exclusive placement.
+	SharedPlacement PlacementPolicy = "Shared"
+
+	// IsolatedPlacement places each pod on a node that runs no other pod,
+	// like ExclusivePlacement, and also taints the nodes of the test for
+	// its duration, so that no other pod is placed on them, even in
+	// clusters whose pools are shared with other workloads. The pods of
+	// the test tolerate the taint and are pinned to their nodes. The
+	// taints are removed when the test terminates or is deleted.
+	IsolatedPlacement PlacementPolicy = "Isolated"
+)
+
+// GeneratorAdapter names the format of the results that a generator reports,
+// so the controller can summarize them.
+// +kubebuilder:validation:Enum=ghz
+type GeneratorAdapter string
+
+const (
+	// GhzAdapter parses the JSON report of ghz, as written by its
+	// "--format=json" option.
+	GhzAdapter GeneratorAdapter = "ghz"
+)
+
 // LoadTestState reflects the derived state of the load test from its
 // components. If any one component has errored, the load test will be marked in
 // an Errored state, too. This will occur even if the other components are
@@ -324,36 +816,84 @@ func (lts LoadTestState) IsTerminated() bool {
 	return lts == Succeeded || lts == Errored
 }
 
-// InitContainerError is the reason string when an init container has failed on
-// one of the load test's pods.
-var InitContainerError = "InitContainerError"
+// InitContainerError is the reason string when an init container other than the
+// build container has failed on one of the load test's pods.
+var InitContainerError = failure.InitContainerFailed.CRDReason()
+
+// BuildFailedError is the reason string when the build init container has
+// failed on one of the load test's pods.
+var BuildFailedError = failure.BuildFailed.CRDReason()
+
+// DriverCrashedError is the reason string when the run container of the driver
+// has failed.
+var DriverCrashedError = failure.DriverCrashed.CRDReason()
 
 // ContainerError is the reason string when a container has failed on one of the
 // load test's pods.
-var ContainerError = "ContainerError"
+var ContainerError = failure.ContainerFailed.CRDReason()
 
 // FailedSettingDefaultsError is the reason string when defaults could not be
 // set on a load test.
-var FailedSettingDefaultsError = "FailedSettingDefaults"
+var FailedSettingDefaultsError = failure.FailedSettingDefaults.CRDReason()
+
+// ImagePullError is the reason string when a container on one of the load
+// test's pods could not pull its image.
+var ImagePullError = failure.ImagePullError.CRDReason()
+
+// CrashLoopError is the reason string when a container on one of the load
+// test's pods has restarted too many times.
+var CrashLoopError = failure.CrashLoop.CRDReason()
+
+// ResourceExceededError is the reason string when a container on one of the
+// load test's pods exceeded its memory or ephemeral storage budget.
+var ResourceExceededError = failure.ResourceExceeded.CRDReason()
 
 // ConfigurationError is the reason string when a LoadTest spec is invalid.
-var ConfigurationError = "ConfigurationError"
+var ConfigurationError = failure.ConfigurationError.CRDReason()
+
+// ImageNotFoundError is the reason string when a container image required by
+// one of the load test's components does not exist in its registry.
+var ImageNotFoundError = failure.ImageNotFound.CRDReason()
+
+// IncompatibleImagesError is the reason string when the image of a client or
+// server is too old to be paired with the image of the driver.
+var IncompatibleImagesError = failure.IncompatibleImages.CRDReason()
+
+// InteropCaseFailedError is the reason string when the interop client of an
+// interop test failed one or more of its test cases.
+var InteropCaseFailedError = failure.InteropCaseFailed.CRDReason()
+
+// WorkersUnreachableError is the reason string when the driver could not
+// connect to the driver port of one or more workers before the benchmark.
+var WorkersUnreachableError = failure.WorkersUnreachable.CRDReason()
+
+// DryRun is the reason string when the pods of a load test were rendered to a
+// ConfigMap instead of being created.
+var DryRun = "DryRun"
 
 // PodsMissing is the reason string when the load test is missing pods and is still
 // in the Initializing state.
-var PodsMissing = "PodsMissing"
+var PodsMissing = failure.PodsMissing.CRDReason()
 
 // PoolError is the reason string when a driver, client or server requires nodes
 // from a nonexistent pool.
-var PoolError = "PoolError"
+var PoolError = failure.PoolError.CRDReason()
 
 // TimeoutErrored is the reason string when the load test has not yet terminated
 // but exceeded the timeout.
-var TimeoutErrored = "TimeoutErrored"
+var TimeoutErrored = failure.Timeout.CRDReason()
 
 // KubernetesError is the reason string when an issue occurs with Kubernetes
 // that is not known to be directly related to a load test.
-var KubernetesError = "KubernetesError"
+var KubernetesError = failure.KubernetesError.CRDReason()
+
+// SchedulingSLOExceededCondition is the type of the condition that is added
+// to the status of a load test when its pods took longer to be running than
+// the scheduling SLO configured in the defaults of the controller.
+const SchedulingSLOExceededCondition = "SchedulingSLOExceeded"
+
+// SlowScheduling is the reason of the SchedulingSLOExceeded condition.
+var SlowScheduling = "SlowScheduling"
 
 // LoadTestStatus defines the observed state of LoadTest
 type LoadTestStatus struct {
@@ -377,18 +917,229 @@ type LoadTestStatus struct {
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
 
+	// ScheduledTime is the time when all pods of the current run of the load
+	// test were first running.
+	// +optional
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
+
 	// StopTime is the time when the controller last entered the Succeeded,
 	// Failed or Errored states.
 	// +optional
 	StopTime *metav1.Time `json:"stopTime,omitempty"`
+
+	// SoakIteration is the zero-based index of the current iteration of a
+	// soak test. It is omitted for tests that are not soak tests.
+	// +optional
+	SoakIteration int32 `json:"soakIteration,omitempty"`
+
+	// Checkpoints record the outcome of the completed iterations of a soak
+	// test, in the order they completed. Only the last 100 checkpoints are
+	// kept, so the status of a long soak test stays small.
+	// +optional
+	Checkpoints []SoakCheckpoint `json:"checkpoints,omitempty"`
+
+	// Summary contains key numbers from the results of the test, as reported
+	// by the driver when it succeeds.
+	// +optional
+	Summary *ResultSummary `json:"summary,omitempty"`
+
+	// InteropCases are the results of the test cases of an interop test, as
+	// reported by the interop client when it terminates.
+	// +optional
+	InteropCases []InteropCaseResult `json:"interopCases,omitempty"`
+
+	// UnreachableWorkers lists the workers whose driver port could not be
+	// reached from the driver pod before the benchmark started, as reported
+	// by the ready init container of the driver.
+	// +optional
+	UnreachableWorkers []UnreachableWorker `json:"unreachableWorkers,omitempty"`
+
+	// ResultsURI is the Cloud Storage URI of the raw JSON output of the
+	// driver. It is set when the driver succeeds and the test sets a
+	// GCSPrefix in its results.
+	// +optional
+	ResultsURI string `json:"resultsURI,omitempty"`
+
+	// Cost is an approximate cost of the nodes used by the test. It is set
+	// when the test terminates, if machine prices are configured in the
+	// defaults of the controller.
+	// +optional
+	Cost *CostEstimate `json:"cost,omitempty"`
+
+	// Run is the zero-based index of the current run of the test. It is
+	// incremented each time the test is rerun with the rerun annotation.
+	// +optional
+	Run int32 `json:"run,omitempty"`
+
+	// RerunRequest is the value of the rerun annotation that started the
+	// current run. It is empty for the first run of the test.
+	// +optional
+	RerunRequest string `json:"rerunRequest,omitempty"`
+
+	// Runs record the outcome of each previous run of a test that was
+	// rerun, in the order they ran. Only the last 20 runs are kept, so the
+	// status of a test that is rerun often stays small.
+	// +optional
+	Runs []LoadTestRun `json:"runs,omitempty"`
+
+	// Conditions provide additional observations of the load test, such as
+	// the SchedulingSLOExceeded condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// LoadTestRun records the outcome of a previous run of a load test that was
+// rerun.
+type LoadTestRun struct {
+	// Run is the zero-based index of the run.
+	Run int32 `json:"run"`
+
+	// State is the state of the load test when the run terminated.
+	State LoadTestState `json:"state"`
+
+	// Reason is a camel-case string that indicates the reasoning behind the
+	// state.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human legible string that describes the outcome of the
+	// run.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time when the run started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// StopTime is the time when the run terminated.
+	// +optional
+	StopTime *metav1.Time `json:"stopTime,omitempty"`
+
+	// Summary contains key numbers from the results of the run, as reported
+	// by the driver when it succeeded.
+	// +optional
+	Summary *ResultSummary `json:"summary,omitempty"`
+
+	// ResultsURI is the Cloud Storage URI of the raw JSON output of the
+	// driver in the run.
+	// +optional
+	ResultsURI string `json:"resultsURI,omitempty"`
+}
+
+// CostEstimate is an approximate cost of the nodes used by a load test,
+// formatted for display. Each pod of a load test runs on its own node, so the
+// node hours of a test are the sum of the time each of its pods ran.
+type CostEstimate struct {
+	// NodeHours is the total time the nodes of the test were used, in
+	// hours.
+	NodeHours string `json:"nodeHours"`
+
+	// Estimate is the price of the node hours, in the currency of the
+	// machine prices in the defaults of the controller. Nodes with a machine
+	// type that has no price are not included.
+	Estimate string `json:"estimate"`
+
+	// UnpricedMachineTypes lists the machine types of the nodes that have no
+	// price. The machine type is "unknown" for nodes that could not be found.
+	// +optional
+	UnpricedMachineTypes []string `json:"unpricedMachineTypes,omitempty"`
+}
+
+// ResultSummary contains key numbers from the results of a load test,
+// formatted for display. Each field is omitted when the driver does not
+// report it.
+type ResultSummary struct {
+	// QPS is the number of queries per second.
+	// +optional
+	QPS string `json:"qps,omitempty"`
+
+	// Latency50 is the median latency.
+	// +optional
+	Latency50 string `json:"latency50,omitempty"`
+
+	// Latency99 is the 99th percentile latency.
+	// +optional
+	Latency99 string `json:"latency99,omitempty"`
+
+	// Latency999 is the 99.9th percentile latency.
+	// +optional
+	Latency999 string `json:"latency999,omitempty"`
+
+	// ClientSystemTime is the percentage of CPU time spent in the system by
+	// the clients.
+	// +optional
+	ClientSystemTime string `json:"clientSystemTime,omitempty"`
+
+	// ServerSystemTime is the percentage of CPU time spent in the system by
+	// the servers.
+	// +optional
+	ServerSystemTime string `json:"serverSystemTime,omitempty"`
+}
+
+// InteropCaseResult is the outcome of a single test case of an interop test.
+type InteropCaseResult struct {
+	// Name is the name of the test case.
+	Name string `json:"name"`
+
+	// Passed is true if the interop client passed the test case.
+	Passed bool `json:"passed"`
+
+	// Message describes why the test case failed. It is empty for test
+	// cases that passed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UnreachableWorker is a worker whose driver port could not be reached from
+// the driver pod of a load test.
+type UnreachableWorker struct {
+	// Name is the name of the pod of the worker.
+	Name string `json:"name"`
+
+	// Address is the address and driver port of the worker.
+	Address string `json:"address"`
+
+	// Message describes the last error encountered when connecting to the
+	// worker.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SoakCheckpoint records the outcome of a single iteration of a soak test.
+type SoakCheckpoint struct {
+	// Iteration is the zero-based index of the iteration.
+	Iteration int32 `json:"iteration"`
+
+	// State is the state of the load test when the iteration completed.
+	State LoadTestState `json:"state"`
+
+	// Message is a human legible string that describes the outcome of the
+	// iteration.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the controller observed that the iteration completed.
+	Time metav1.Time `json:"time"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=lt,categories=grpc
 
 // LoadTest is the Schema for the loadtests API
 // +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`
+// +kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.servers[0].pool`
+// +kubebuilder:printcolumn:name="QPS",type=string,JSONPath=`.status.summary.qps`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="P50",type=string,JSONPath=`.status.summary.latency50`,priority=1
+// +kubebuilder:printcolumn:name="P99",type=string,JSONPath=`.status.summary.latency99`,priority=1
+// +kubebuilder:printcolumn:name="P999",type=string,JSONPath=`.status.summary.latency999`,priority=1
+// +kubebuilder:printcolumn:name="Client Sys CPU",type=string,JSONPath=`.status.summary.clientSystemTime`,priority=1
+// +kubebuilder:printcolumn:name="Server Sys CPU",type=string,JSONPath=`.status.summary.serverSystemTime`,priority=1
+// +kubebuilder:printcolumn:name="Cost",type=string,JSONPath=`.status.cost.estimate`,priority=1
 type LoadTest struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
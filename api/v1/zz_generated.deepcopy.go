@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -76,6 +77,11 @@ func (in *Client) DeepCopyInto(out *Client) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Clone != nil {
 		in, out := &in.Clone, &out.Clone
 		*out = new(Clone)
@@ -93,6 +99,23 @@ func (in *Client) DeepCopyInto(out *Client) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]corev1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(ResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Client.
@@ -135,6 +158,26 @@ func (in *Clone) DeepCopy() *Clone {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	if in.UnpricedMachineTypes != nil {
+		in, out := &in.UnpricedMachineTypes, &out.UnpricedMachineTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Driver) DeepCopyInto(out *Driver) {
 	*out = *in
@@ -165,6 +208,23 @@ func (in *Driver) DeepCopyInto(out *Driver) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]corev1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(ResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Driver.
@@ -177,6 +237,135 @@ func (in *Driver) DeepCopy() *Driver {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Generator) DeepCopyInto(out *Generator) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Pool != nil {
+		in, out := &in.Pool, &out.Pool
+		*out = new(string)
+		**out = **in
+	}
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]corev1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(ResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Generator.
+func (in *Generator) DeepCopy() *Generator {
+	if in == nil {
+		return nil
+	}
+	out := new(Generator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Interop) DeepCopyInto(out *Interop) {
+	*out = *in
+	if in.TestCases != nil {
+		in, out := &in.TestCases, &out.TestCases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interop.
+func (in *Interop) DeepCopy() *Interop {
+	if in == nil {
+		return nil
+	}
+	out := new(Interop)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InteropCaseResult) DeepCopyInto(out *InteropCaseResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InteropCaseResult.
+func (in *InteropCaseResult) DeepCopy() *InteropCaseResult {
+	if in == nil {
+		return nil
+	}
+	out := new(InteropCaseResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadProfile) DeepCopyInto(out *LoadProfile) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ramp != nil {
+		in, out := &in.Ramp, &out.Ramp
+		*out = new(LoadRamp)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadProfile.
+func (in *LoadProfile) DeepCopy() *LoadProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadRamp) DeepCopyInto(out *LoadRamp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadRamp.
+func (in *LoadRamp) DeepCopy() *LoadRamp {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadRamp)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadTest) DeepCopyInto(out *LoadTest) {
 	*out = *in
@@ -236,6 +425,34 @@ func (in *LoadTestList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestRun) DeepCopyInto(out *LoadTestRun) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StopTime != nil {
+		in, out := &in.StopTime, &out.StopTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(ResultSummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestRun.
+func (in *LoadTestRun) DeepCopy() *LoadTestRun {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadTestSpec) DeepCopyInto(out *LoadTestSpec) {
 	*out = *in
@@ -258,11 +475,48 @@ func (in *LoadTestSpec) DeepCopyInto(out *LoadTestSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]Generator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Interop != nil {
+		in, out := &in.Interop, &out.Interop
+		*out = new(Interop)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Results != nil {
 		in, out := &in.Results, &out.Results
 		*out = new(Results)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LoadProfile != nil {
+		in, out := &in.LoadProfile, &out.LoadProfile
+		*out = new(LoadProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KillAfterSeconds != nil {
+		in, out := &in.KillAfterSeconds, &out.KillAfterSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SoakHours != nil {
+		in, out := &in.SoakHours, &out.SoakHours
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetworkProfile != nil {
+		in, out := &in.NetworkProfile, &out.NetworkProfile
+		*out = new(NetworkProfile)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestSpec.
@@ -282,10 +536,55 @@ func (in *LoadTestStatus) DeepCopyInto(out *LoadTestStatus) {
 		in, out := &in.StartTime, &out.StartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.ScheduledTime != nil {
+		in, out := &in.ScheduledTime, &out.ScheduledTime
+		*out = (*in).DeepCopy()
+	}
 	if in.StopTime != nil {
 		in, out := &in.StopTime, &out.StopTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Checkpoints != nil {
+		in, out := &in.Checkpoints, &out.Checkpoints
+		*out = make([]SoakCheckpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(ResultSummary)
+		**out = **in
+	}
+	if in.InteropCases != nil {
+		in, out := &in.InteropCases, &out.InteropCases
+		*out = make([]InteropCaseResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnreachableWorkers != nil {
+		in, out := &in.UnreachableWorkers, &out.UnreachableWorkers
+		*out = make([]UnreachableWorker, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(CostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]LoadTestRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestStatus.
@@ -298,14 +597,79 @@ func (in *LoadTestStatus) DeepCopy() *LoadTestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkProfile) DeepCopyInto(out *NetworkProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkProfile.
+func (in *NetworkProfile) DeepCopy() *NetworkProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBudget) DeepCopyInto(out *ResourceBudget) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EphemeralStorage != nil {
+		in, out := &in.EphemeralStorage, &out.EphemeralStorage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBudget.
+func (in *ResourceBudget) DeepCopy() *ResourceBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResultSummary) DeepCopyInto(out *ResultSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultSummary.
+func (in *ResultSummary) DeepCopy() *ResultSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ResultSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Results) DeepCopyInto(out *Results) {
 	*out = *in
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(string)
+		**out = **in
+	}
 	if in.BigQueryTable != nil {
 		in, out := &in.BigQueryTable, &out.BigQueryTable
 		*out = new(string)
 		**out = **in
 	}
+	if in.GCSPrefix != nil {
+		in, out := &in.GCSPrefix, &out.GCSPrefix
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Results.
@@ -348,6 +712,23 @@ func (in *Server) DeepCopyInto(out *Server) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HostAliases != nil {
+		in, out := &in.HostAliases, &out.HostAliases
+		*out = make([]corev1.HostAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(ResourceBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Server.
@@ -359,3 +740,34 @@ func (in *Server) DeepCopy() *Server {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SoakCheckpoint) DeepCopyInto(out *SoakCheckpoint) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoakCheckpoint.
+func (in *SoakCheckpoint) DeepCopy() *SoakCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(SoakCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnreachableWorker) DeepCopyInto(out *UnreachableWorker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnreachableWorker.
+func (in *UnreachableWorker) DeepCopy() *UnreachableWorker {
+	if in == nil {
+		return nil
+	}
+	out := new(UnreachableWorker)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// TestFileName is the name of the file where the final state of a load test
+// is saved by the DirectoryArchiver.
+const TestFileName = "loadtest.json"
+
+// ErrorsFileName is the name of the file where the DirectoryArchiver records
+// the logs that it failed to save.
+const ErrorsFileName = "errors.txt"
+
+// Archiver performs work on a load test once it terminates.
+type Archiver interface {
+	// Archive archives a terminated load test and the pods that it owns. An
+	// error is returned if the test could not be archived, in which case it
+	// may be archived again later, so archivers must tolerate repeats.
+	Archive(ctx context.Context, test *grpcv1.LoadTest, pods []*corev1.Pod) error
+}
+
+// DirectoryArchiver saves the logs of the containers of each pod and the final
+// state of the test as JSON to a directory named after the namespace and name
+// of the test. Containers that never started have no logs and are skipped. A
+// log that cannot be saved does not prevent the rest of the test from being
+// archived, and the error is recorded in a file instead.
+type DirectoryArchiver struct {
+	// Directory is the directory that contains the directories of tests.
+	Directory string
+
+	// PodsGetter is used to get the logs of pods.
+	PodsGetter corev1types.PodsGetter
+}
+
+// Archive implements the Archiver interface.
+func (a *DirectoryArchiver) Archive(ctx context.Context, test *grpcv1.LoadTest, pods []*corev1.Pod) error {
+	testDir := filepath.Join(a.Directory, test.Namespace, test.Name)
+	if err := os.MkdirAll(testDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create archive directory %s: %v", testDir, err)
+	}
+
+	var logErrors []string
+	for _, pod := range pods {
+		var containers []corev1.Container
+		containers = append(containers, pod.Spec.InitContainers...)
+		containers = append(containers, pod.Spec.Containers...)
+		for _, container := range containers {
+			if !containerStarted(pod, container.Name) {
+				continue
+			}
+			filePath := filepath.Join(testDir, fmt.Sprintf("%s-%s.log", pod.Name, container.Name))
+			if err := a.saveLog(ctx, pod, container.Name, filePath); err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("failed to archive log of container %s of pod %s: %v", container.Name, pod.Name, err)
+				}
+				logErrors = append(logErrors, fmt.Sprintf("failed to archive log of container %s of pod %s: %v", container.Name, pod.Name, err))
+			}
+		}
+	}
+
+	// Tests that are rerun are archived again, so errors from an earlier
+	// run are removed.
+	errorsPath := filepath.Join(testDir, ErrorsFileName)
+	if len(logErrors) > 0 {
+		if err := os.WriteFile(errorsPath, []byte(strings.Join(logErrors, "\n")+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", errorsPath, err)
+		}
+	} else if err := os.Remove(errorsPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %v", errorsPath, err)
+	}
+
+	testJSON, err := json.MarshalIndent(test, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode test: %v", err)
+	}
+	filePath := filepath.Join(testDir, TestFileName)
+	if err := os.WriteFile(filePath, testJSON, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// saveLog writes the log of a container to a file. Nothing is written if the
+// log is empty.
+func (a *DirectoryArchiver) saveLog(ctx context.Context, pod *corev1.Pod, containerName string, filePath string) error {
+	req := a.PodsGetter.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: containerName})
+	logs, err := req.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	logBuffer := new(bytes.Buffer)
+	if _, err := logBuffer.ReadFrom(logs); err != nil {
+		return err
+	}
+	if logBuffer.Len() == 0 {
+		return nil
+	}
+	return os.WriteFile(filePath, logBuffer.Bytes(), 0o644)
+}
+
+// containerStarted returns true if a container of a pod has run, so it may
+// have logs.
+func containerStarted(pod *corev1.Pod, containerName string) bool {
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name != containerName {
+			continue
+		}
+		return status.State.Running != nil || status.State.Terminated != nil || status.LastTerminationState.Terminated != nil
+	}
+	return false
+}
+
+// Notification is the body of the request sent by the Notifier.
+type Notification struct {
+	// Name is the name of the test.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the test.
+	Namespace string `json:"namespace"`
+
+	// Labels are the labels of the test.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Status is the final status of the test.
+	Status grpcv1.LoadTestStatus `json:"status"`
+}
+
+// Notifier sends the final state of each test as JSON in a POST request to a
+// URL. Notifications fail if the response does not have a 2xx status code.
+type Notifier struct {
+	// URL is the URL that receives the request.
+	URL string
+
+	// Client is the client used to send the request. The default client is
+	// used when it is nil.
+	Client *http.Client
+}
+
+// Archive implements the Archiver interface.
+func (n *Notifier) Archive(ctx context.Context, test *grpcv1.LoadTest, _ []*corev1.Pod) error {
+	body, err := json.Marshal(&Notification{
+		Name:      test.Name,
+		Namespace: test.Namespace,
+		Labels:    test.Labels,
+		Status:    test.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %v", n.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %v", n.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request to %s failed with status %s: %s", n.URL, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// ForConfig returns the archivers enabled by the archive settings, in the
+// order they should run. Logs are saved before notifications are sent, so
+// that notified systems can find them.
+func ForConfig(settings *config.Archive, podsGetter corev1types.PodsGetter) []Archiver {
+	if settings == nil {
+		return nil
+	}
+	var archivers []Archiver
+	if settings.Directory != "" {
+		archivers = append(archivers, &DirectoryArchiver{
+			Directory:  settings.Directory,
+			PodsGetter: podsGetter,
+		})
+	}
+	if settings.NotificationURL != "" {
+		archivers = append(archivers, &Notifier{URL: settings.NotificationURL})
+	}
+	return archivers
+}
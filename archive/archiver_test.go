@@ -0,0 +1,185 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("Archivers", func() {
+	var test *grpcv1.LoadTest
+	var pods []*corev1.Pod
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "tests",
+				Labels:    map[string]string{"team": "grpc"},
+			},
+			Status: grpcv1.LoadTestStatus{
+				State:  grpcv1.Succeeded,
+				Reason: "PodsSucceeded",
+			},
+		}
+		pods = []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-driver",
+				Namespace: "tests",
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "ready"}},
+				Containers:     []corev1.Container{{Name: "main"}},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "ready",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "main",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		}}
+	})
+
+	Describe("DirectoryArchiver", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "archive")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("saves the logs of each container and the test", func() {
+			archiver := &DirectoryArchiver{
+				Directory:  dir,
+				PodsGetter: fake.NewSimpleClientset(pods[0]).CoreV1(),
+			}
+			Expect(archiver.Archive(context.Background(), test, pods)).To(Succeed())
+
+			testDir := filepath.Join(dir, "tests", "example")
+			Expect(filepath.Join(testDir, "example-driver-ready.log")).To(BeAnExistingFile())
+			Expect(filepath.Join(testDir, "example-driver-main.log")).To(BeAnExistingFile())
+
+			data, err := os.ReadFile(filepath.Join(testDir, TestFileName))
+			Expect(err).ToNot(HaveOccurred())
+			archived := new(grpcv1.LoadTest)
+			Expect(json.Unmarshal(data, archived)).To(Succeed())
+			Expect(archived.Status.State).To(Equal(grpcv1.Succeeded))
+			Expect(filepath.Join(testDir, ErrorsFileName)).ToNot(BeAnExistingFile())
+		})
+
+		It("skips containers that never started", func() {
+			pods[0].Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+			}
+
+			archiver := &DirectoryArchiver{
+				Directory:  dir,
+				PodsGetter: fake.NewSimpleClientset(pods[0]).CoreV1(),
+			}
+			Expect(archiver.Archive(context.Background(), test, pods)).To(Succeed())
+
+			testDir := filepath.Join(dir, "tests", "example")
+			Expect(filepath.Join(testDir, "example-driver-ready.log")).To(BeAnExistingFile())
+			Expect(filepath.Join(testDir, "example-driver-main.log")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(testDir, TestFileName)).To(BeAnExistingFile())
+		})
+
+		It("records logs that cannot be saved and archives the rest of the test", func() {
+			archiver := &DirectoryArchiver{
+				Directory:  dir,
+				PodsGetter: fake.NewSimpleClientset(pods[0]).CoreV1(),
+			}
+			testDir := filepath.Join(dir, "tests", "example")
+			Expect(os.MkdirAll(filepath.Join(testDir, "example-driver-main.log"), os.ModePerm)).To(Succeed())
+			Expect(archiver.Archive(context.Background(), test, pods)).To(Succeed())
+
+			Expect(filepath.Join(testDir, "example-driver-ready.log")).To(BeAnExistingFile())
+			Expect(filepath.Join(testDir, TestFileName)).To(BeAnExistingFile())
+			data, err := os.ReadFile(filepath.Join(testDir, ErrorsFileName))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("container main of pod example-driver"))
+		})
+	})
+
+	Describe("Notifier", func() {
+		It("posts the final state of the test", func() {
+			var received Notification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			}))
+			defer server.Close()
+
+			notifier := &Notifier{URL: server.URL}
+			Expect(notifier.Archive(context.Background(), test, pods)).To(Succeed())
+			Expect(received.Name).To(Equal("example"))
+			Expect(received.Namespace).To(Equal("tests"))
+			Expect(received.Labels).To(HaveKeyWithValue("team", "grpc"))
+			Expect(received.Status.State).To(Equal(grpcv1.Succeeded))
+		})
+
+		It("returns an error when the response is not successful", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			notifier := &Notifier{URL: server.URL}
+			Expect(notifier.Archive(context.Background(), test, pods)).ToNot(Succeed())
+		})
+	})
+
+	Describe("ForConfig", func() {
+		It("returns no archivers without settings", func() {
+			Expect(ForConfig(nil, nil)).To(BeEmpty())
+		})
+
+		It("saves logs before sending notifications", func() {
+			archivers := ForConfig(&config.Archive{
+				Directory:       "/archive",
+				NotificationURL: "https://example.com",
+			}, nil)
+			Expect(archivers).To(HaveLen(2))
+			Expect(archivers[0]).To(BeAssignableToTypeOf(&DirectoryArchiver{}))
+			Expect(archivers[1]).To(BeAssignableToTypeOf(&Notifier{}))
+		})
+	})
+})
@@ -0,0 +1,1738 @@
+# Code generated by gen_models. DO NOT EDIT.
+
+from __future__ import annotations
+
+import dataclasses
+import typing
+from typing import Any, Dict, List, Literal, Optional, Union
+
+
+def _to_json(value: Any) -> Any:
+    if isinstance(value, _Model):
+        return value.to_dict()
+    if isinstance(value, list):
+        return [_to_json(v) for v in value]
+    if isinstance(value, dict):
+        return {k: _to_json(v) for k, v in value.items()}
+    return value
+
+
+def _from_json(tp: Any, value: Any) -> Any:
+    if value is None:
+        return None
+    origin = typing.get_origin(tp)
+    if origin is Union:
+        args = [a for a in typing.get_args(tp) if a is not type(None)]
+        if len(args) == 1:
+            return _from_json(args[0], value)
+        return value
+    if origin is list:
+        (elem,) = typing.get_args(tp)
+        return [_from_json(elem, v) for v in value]
+    if origin is dict:
+        _, elem = typing.get_args(tp)
+        return {k: _from_json(elem, v) for k, v in value.items()}
+    if isinstance(tp, type) and issubclass(tp, _Model):
+        return tp.from_dict(value)
+    return value
+
+
+class _Model:
+    """Base class of the models, converting them to and from dictionaries."""
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Returns the fields that are set, keyed by their names in JSON."""
+        result = {}
+        for f in dataclasses.fields(self):
+            value = getattr(self, f.name)
+            if value is not None:
+                result[f.metadata["json"]] = _to_json(value)
+        return result
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]):
+        """Creates a model from a dictionary decoded from JSON or YAML."""
+        hints = typing.get_type_hints(cls)
+        kwargs = {}
+        for f in dataclasses.fields(cls):
+            if f.metadata["json"] in data:
+                kwargs[f.name] = _from_json(hints[f.name], data[f.metadata["json"]])
+        return cls(**kwargs)
+
+
+@dataclasses.dataclass
+class LoadTest(_Model):
+    """LoadTest is the Schema for the loadtests API"""
+
+    # APIVersion defines the versioned schema of this representation of an
+    # object. Servers should convert recognized schemas to the latest internal
+    # value, and may reject unrecognized values. More info:
+    # https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+    api_version: Optional[str] = dataclasses.field(default=None, metadata={"json": "apiVersion"})
+
+    # Kind is a string value representing the REST resource this object
+    # represents. Servers may infer this from the endpoint the client submits
+    # requests to. Cannot be updated. In CamelCase. More info:
+    # https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+    kind: Optional[str] = dataclasses.field(default=None, metadata={"json": "kind"})
+
+    metadata: Optional[Dict[str, Any]] = dataclasses.field(default=None, metadata={"json": "metadata"})
+
+    # LoadTestSpec defines the desired state of LoadTest
+    spec: Optional[LoadTestSpec] = dataclasses.field(default=None, metadata={"json": "spec"})
+
+    # LoadTestStatus defines the observed state of LoadTest
+    status: Optional[LoadTestStatus] = dataclasses.field(default=None, metadata={"json": "status"})
+
+
+@dataclasses.dataclass
+class LoadTestSpec(_Model):
+    """LoadTestSpec defines the desired state of LoadTest"""
+
+    # TTL provides the longest time a LoadTest can live on the cluster.
+    ttl_seconds: int = dataclasses.field(metadata={"json": "ttlSeconds"})
+
+    # Clients are a list of components that send traffic to servers.
+    clients: Optional[List[Client]] = dataclasses.field(default=None, metadata={"json": "clients"})
+
+    # Driver is the component that orchestrates the test. It may be unspecified,
+    # allowing the system to choose the appropriate driver. Tests with
+    # generators do not receive a default driver, since the generators send load
+    # without instructions from a driver.
+    driver: Optional[Driver] = dataclasses.field(default=None, metadata={"json": "driver"})
+
+    # Generators are a list of components that send load without the driver,
+    # such as ghz. When a test has generators and no driver, it succeeds once
+    # every generator has terminated successfully. Servers and clients are
+    # optional in such tests.
+    generators: Optional[List[Generator]] = dataclasses.field(default=None, metadata={"json": "generators"})
+
+    # Interop turns the test into a run of the gRPC interop client suite. The
+    # driver runs the interop client once for each test case against a server,
+    # and the test fails if the client fails any of them. Interop tests do not
+    # need clients or scenarios.
+    interop: Optional[Interop] = dataclasses.field(default=None, metadata={"json": "interop"})
+
+    # IPFamily selects the IP family of the addresses that the components of the
+    # test use to communicate. IPv6 requires a cluster with IPv6 pod networking,
+    # and DualStack requires a dual-stack cluster. When unset, the default
+    # family of the cluster is used.
+    ip_family: Optional[Literal["IPv4", "IPv6", "DualStack"]] = dataclasses.field(default=None, metadata={"json": "ipFamily"})
+
+    # KillAfterSeconds overrides the killAfter setting of the controller for
+    # this test. It is the time allowed for the containers of the test to
+    # respond after the timeout before they are killed, and is passed to them in
+    # the KILL_AFTER environment variable. It must be less than the timeout.
+    kill_after_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "killAfterSeconds"})
+
+    # LoadProfile sets the load that the clients offer in each scenario,
+    # replacing the load_params of the client_config in ScenariosJSON. A profile
+    # with several steps repeats each scenario once per step, so a range of
+    # loads can be measured without editing the scenarios. The timeout must be
+    # long enough to cover every step.
+    load_profile: Optional[LoadProfile] = dataclasses.field(default=None, metadata={"json": "loadProfile"})
+
+    # NetworkProfile emulates a wide area network between the workers of the
+    # test. When set, the traffic that leaves each client and server pod is
+    # shaped with netem, so a round trip between a client and a server
+    # experiences twice the configured latency. When omitted, the traffic is not
+    # shaped.
+    network_profile: Optional[NetworkProfile] = dataclasses.field(default=None, metadata={"json": "networkProfile"})
+
+    # PlacementPolicy determines whether the pods of the test require nodes of
+    # their own. Shared placement is intended for functional tests, whose
+    # results do not depend on the performance of the nodes. Isolated placement
+    # also taints the nodes of the test, so that no other workload lands on
+    # them. When unset, each pod is placed on its own node.
+    placement_policy: Optional[Literal["Exclusive", "Shared", "Isolated"]] = dataclasses.field(default=None, metadata={"json": "placementPolicy"})
+
+    # RestartWorkers restarts the clients and servers between the scenarios of
+    # the test, so that state left in a worker by one scenario does not skew the
+    # results of the next. The driver runs each scenario on its own and asks the
+    # workers to quit after it, and the run container of each worker starts its
+    # command again for the next scenario. The time the workers take to restart
+    # is recorded in the metadata of the results. The first run container of
+    # each client and server must set a command. It has no effect on tests with
+    # a single scenario.
+    restart_workers: Optional[bool] = dataclasses.field(default=None, metadata={"json": "restartWorkers"})
+
+    # Results configures where the results of the test should be stored. When
+    # omitted, the results will only be stored in Kubernetes for a limited time.
+    results: Optional[Results] = dataclasses.field(default=None, metadata={"json": "results"})
+
+    # ScenariosJSON is string with the contents of a Scenarios message,
+    # formatted as JSON. See the Scenarios protobuf definition for details:
+    # https://github.com/grpc/grpc-proto/blob/master/grpc/testing/control.proto.
+    scenarios_json: Optional[str] = dataclasses.field(default=None, metadata={"json": "scenariosJSON"})
+
+    # Servers are a list of components that receive traffic from clients.
+    servers: Optional[List[Server]] = dataclasses.field(default=None, metadata={"json": "servers"})
+
+    # ServiceMesh injects the sidecar proxy of a service mesh into the client
+    # and server pods, so that the traffic between them passes through the mesh.
+    # The mesh must be installed on the cluster. The driver is excluded from the
+    # mesh, and connects to the workers without passing through their proxies.
+    # When unset, no proxies are injected by the test.
+    service_mesh: Optional[Literal["Istio", "Linkerd"]] = dataclasses.field(default=None, metadata={"json": "serviceMesh"})
+
+    # SoakHours enables soak testing, keeping the scenario running for many
+    # hours to detect slow resource leaks. Each time the driver succeeds before
+    # this many hours have elapsed, the pods for the test are recreated and the
+    # scenario is run again. Results of each iteration are saved as they
+    # complete, and a checkpoint is recorded in the status. The timeout must be
+    # long enough to cover the entire soak.
+    soak_hours: Optional[int] = dataclasses.field(default=None, metadata={"json": "soakHours"})
+
+    # TerminationGracePeriodSeconds overrides the terminationGracePeriodSeconds
+    # setting of the controller for this test. It is the time given to the pods
+    # of the test to stop after the test is deleted. It must be longer than the
+    # 10 second stop delay of the workers, and must not exceed the timeout.
+    termination_grace_period_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "terminationGracePeriodSeconds"})
+
+    # Timeout provides the longest running time allowed for a LoadTest. When
+    # omitted, the controller computes it from the warmup and benchmark time of
+    # the scenarios, the steps of the load profile, the number of workers and
+    # the soak hours of the test.
+    timeout_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "timeoutSeconds"})
+
+
+@dataclasses.dataclass
+class Client(_Model):
+    """Client defines a component that sends traffic to a server component."""
+
+    # Language is the code that identifies the programming language used by the
+    # client. For example, "go" may represent Go.
+    # Specifying a language is required. If the language is unknown to the
+    # operator, a user must manually set a run image. If the user intends for
+    # the operator to clone and build code, it must also manually set a build
+    # image.
+    language: str = dataclasses.field(metadata={"json": "language"})
+
+    # Run describes a list of run containers. The container for the test client
+    # is always the first container on the list.
+    run: List[Run] = dataclasses.field(metadata={"json": "run"})
+
+    # Budget limits the memory and ephemeral storage of the run container of the
+    # client. Values set here override the limits of the container.
+    budget: Optional[Budget] = dataclasses.field(default=None, metadata={"json": "budget"})
+
+    # Build describes how the cloned code should be built, including any
+    # compiler arguments or flags. This field is only necessary if the output
+    # from the clone container must be pre-processed before running the tests in
+    # the run container.
+    # When build is specified on a test, the operator will use the client's
+    # language to find a container with a compiler for that language. If the
+    # language is unknown to the operator, a user must include a custom docker
+    # image.
+    # Note that it does not usually make sense to include build instructions
+    # without clone instructions. If doing so, the build container must include
+    # its input and write its output into the /src/workspace directory for the
+    # run container to access it.
+    build: Optional[Build] = dataclasses.field(default=None, metadata={"json": "build"})
+
+    # Clone specifies the repository and snapshot where the code for the client
+    # can be found. This field should not be set if the code has been prebuilt
+    # in the run image.
+    clone: Optional[Clone] = dataclasses.field(default=None, metadata={"json": "clone"})
+
+    # CSDSPort is the port where a proxyless client of a PSM test serves the
+    # Client Status Discovery Service (CSDS). Its value is available to the run
+    # container in the $CSDS_PORT environment variable. When set, the xds-server
+    # container fetches the xDS configuration of the client while it runs, and
+    # writes the last configuration it fetched to its log when the client stops.
+    csds_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "csdsPort"})
+
+    # DNSConfig specifies DNS parameters for the client pod. These parameters
+    # are merged with the ones generated from the DNS policy of the pod.
+    dns_config: Optional[DnsConfig] = dataclasses.field(default=None, metadata={"json": "dnsConfig"})
+
+    # HostAliases are entries that are added to the /etc/hosts file of the
+    # client pod. They allow the client to address hosts by stable names, for
+    # example to match the subject alternative names of a TLS certificate.
+    host_aliases: Optional[List[HostAlias]] = dataclasses.field(default=None, metadata={"json": "hostAliases"})
+
+    # MetricsPort is the port where the client serves metrics. Its value is
+    # available to the run container in the $METRICS_PORT environment variable.
+    # If another container in the pod declares the same port, the next free port
+    # is used instead, and its number is recorded in the
+    # e2etest.grpc.io/metrics-port annotation of the pod.
+    metrics_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "metricsPort"})
+
+    # Name is a string that distinguishes this client from others in the test.
+    # Explicitly setting a name is recommended when it is helpful to
+    # differentiate between multiple clients. For example, a test may use
+    # clients with different settings.
+    # Most often, this field will not be set. When unset, the operator will
+    # assign a name to the client.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Pool specifies the name of the set of nodes where this client should be
+    # scheduled. If unset, the controller will choose a pool based on defaults.
+    pool: Optional[str] = dataclasses.field(default=None, metadata={"json": "pool"})
+
+    # PprofPort is the port where a Go worker serves the net/http/pprof
+    # handlers, such as the port passed to the --pprof_port flag of the grpc-go
+    # benchmark worker. Its value is available to the run container in the
+    # $PPROF_PORT environment variable. When set, CPU and heap profiles can be
+    # collected from the client during the benchmark.
+    pprof_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "pprofPort"})
+
+    # Replicas is the number of identical client pods created from this spec.
+    # When it is greater than one, the name of each pod is suffixed with its
+    # index, so a client named "scale" with 3 replicas results in clients named
+    # "scale-0", "scale-1" and "scale-2". The driver receives the addresses of
+    # all replicas. If unset, a single client is created.
+    replicas: Optional[int] = dataclasses.field(default=None, metadata={"json": "replicas"})
+
+    # XdsBootstrap selects how a proxyless client of a PSM test receives its xDS
+    # bootstrap configuration. When unset, the bootstrap file is written by the
+    # xds-server container to a volume mounted at /bootstrap in the run
+    # container. It has no effect on clients without an xds-server container, or
+    # with a sidecar container.
+    xds_bootstrap: Optional[Literal["Volume", "Env"]] = dataclasses.field(default=None, metadata={"json": "xdsBootstrap"})
+
+
+@dataclasses.dataclass
+class Budget(_Model):
+    """Budget limits the memory and ephemeral storage of the run container of
+
+    the client. Values set here override the limits of the container.
+    """
+
+    # EphemeralStorage is the maximum amount of local disk that the run
+    # container may use for its writable layer and logs, such as "10Gi".
+    ephemeral_storage: Optional[Union[int, str]] = dataclasses.field(default=None, metadata={"json": "ephemeralStorage"})
+
+    # Memory is the maximum amount of memory that the run container may use,
+    # such as "4Gi".
+    memory: Optional[Union[int, str]] = dataclasses.field(default=None, metadata={"json": "memory"})
+
+
+@dataclasses.dataclass
+class Build(_Model):
+    """Build describes how the cloned code should be built, including any
+
+    compiler arguments or flags. This field is only necessary if the output
+    from the clone container must be pre-processed before running the tests
+    in the run container.
+    When build is specified on a test, the operator will use the client's
+    language to find a container with a compiler for that language. If the
+    language is unknown to the operator, a user must include a custom docker
+    image.
+    Note that it does not usually make sense to include build instructions
+    without clone instructions. If doing so, the build container must
+    include its input and write its output into the /src/workspace directory
+    for the run container to access it.
+    """
+
+    # Args provide command line arguments to the command. If a command is not
+    # specified, these arguments will be ignored in favor of the default
+    # arguments for container's entrypoint.
+    args: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "args"})
+
+    # Command is the path to the executable that will build the code in the
+    # /src/workspace directory. If unspecified, the entrypoint for the container
+    # is used.
+    command: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "command"})
+
+    # Env are environment variables that should be set within the build
+    # container. This is provided for compilers that alter behavior due to
+    # certain environment variables.
+    env: Optional[List[Env]] = dataclasses.field(default=None, metadata={"json": "env"})
+
+    # Image is the name of the container image that can build code, placing an
+    # executable in the /src/workspace directory.
+    # This field is optional when a Language is specified on the Component. For
+    # example, a developer may specify a "java" server. Then, this image will
+    # default to the most recent gradle image.
+    image: Optional[str] = dataclasses.field(default=None, metadata={"json": "image"})
+
+
+@dataclasses.dataclass
+class Env(_Model):
+    """EnvVar represents an environment variable present in a Container."""
+
+    # Name of the environment variable. Must be a C_IDENTIFIER.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # Variable references $(VAR_NAME) are expanded using the previously defined
+    # environment variables in the container and any service environment
+    # variables. If a variable cannot be resolved, the reference in the input
+    # string will be unchanged. Double $$ are reduced to a single $, which
+    # allows for escaping the $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)" will
+    # produce the string literal "$(VAR_NAME)". Escaped references will never be
+    # expanded, regardless of whether the variable exists or not. Defaults to
+    # "".
+    value: Optional[str] = dataclasses.field(default=None, metadata={"json": "value"})
+
+    # Source for the environment variable's value. Cannot be used if value is
+    # not empty.
+    value_from: Optional[ValueFrom] = dataclasses.field(default=None, metadata={"json": "valueFrom"})
+
+
+@dataclasses.dataclass
+class ValueFrom(_Model):
+    """Source for the environment variable's value. Cannot be used if value is
+
+    not empty.
+    """
+
+    # Selects a key of a ConfigMap.
+    config_map_key_ref: Optional[ConfigMapKeyRef] = dataclasses.field(default=None, metadata={"json": "configMapKeyRef"})
+
+    # Selects a field of the pod: supports metadata.name, metadata.namespace,
+    # `metadata.labels['<KEY>']`, `metadata.annotations['<KEY>']`,
+    # spec.nodeName, spec.serviceAccountName, status.hostIP, status.podIP,
+    # status.podIPs.
+    field_ref: Optional[FieldRef] = dataclasses.field(default=None, metadata={"json": "fieldRef"})
+
+    # Selects a resource of the container: only resources limits and requests
+    # (limits.cpu, limits.memory, limits.ephemeral-storage, requests.cpu,
+    # requests.memory and requests.ephemeral-storage) are currently supported.
+    resource_field_ref: Optional[ResourceFieldRef] = dataclasses.field(default=None, metadata={"json": "resourceFieldRef"})
+
+    # Selects a key of a secret in the pod's namespace
+    secret_key_ref: Optional[SecretKeyRef] = dataclasses.field(default=None, metadata={"json": "secretKeyRef"})
+
+
+@dataclasses.dataclass
+class ConfigMapKeyRef(_Model):
+    """Selects a key of a ConfigMap."""
+
+    # The key to select.
+    key: str = dataclasses.field(metadata={"json": "key"})
+
+    # Name of the referent. More info:
+    # https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+    # TODO: Add other useful fields. apiVersion, kind, uid?
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Specify whether the ConfigMap or its key must be defined
+    optional: Optional[bool] = dataclasses.field(default=None, metadata={"json": "optional"})
+
+
+@dataclasses.dataclass
+class FieldRef(_Model):
+    """Selects a field of the pod: supports metadata.name, metadata.namespace,
+
+    `metadata.labels['<KEY>']`, `metadata.annotations['<KEY>']`,
+    spec.nodeName, spec.serviceAccountName, status.hostIP, status.podIP,
+    status.podIPs.
+    """
+
+    # Path of the field to select in the specified API version.
+    field_path: str = dataclasses.field(metadata={"json": "fieldPath"})
+
+    # Version of the schema the FieldPath is written in terms of, defaults to
+    # "v1".
+    api_version: Optional[str] = dataclasses.field(default=None, metadata={"json": "apiVersion"})
+
+
+@dataclasses.dataclass
+class ResourceFieldRef(_Model):
+    """Selects a resource of the container: only resources limits and requests
+
+    (limits.cpu, limits.memory, limits.ephemeral-storage, requests.cpu,
+    requests.memory and requests.ephemeral-storage) are currently supported.
+    """
+
+    # Required: resource to select
+    resource: str = dataclasses.field(metadata={"json": "resource"})
+
+    # Container name: required for volumes, optional for env vars
+    container_name: Optional[str] = dataclasses.field(default=None, metadata={"json": "containerName"})
+
+    # Specifies the output format of the exposed resources, defaults to "1"
+    divisor: Optional[Union[int, str]] = dataclasses.field(default=None, metadata={"json": "divisor"})
+
+
+@dataclasses.dataclass
+class SecretKeyRef(_Model):
+    """Selects a key of a secret in the pod's namespace"""
+
+    # The key of the secret to select from. Must be a valid secret key.
+    key: str = dataclasses.field(metadata={"json": "key"})
+
+    # Name of the referent. More info:
+    # https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+    # TODO: Add other useful fields. apiVersion, kind, uid?
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Specify whether the Secret or its key must be defined
+    optional: Optional[bool] = dataclasses.field(default=None, metadata={"json": "optional"})
+
+
+@dataclasses.dataclass
+class Clone(_Model):
+    """Clone specifies the repository and snapshot where the code for the
+
+    client can be found. This field should not be set if the code has been
+    prebuilt in the run image.
+    """
+
+    # GitRef is a branch, tag or commit hash to checkout after a successful
+    # clone. This will be the version of the code in the /src/workspace
+    # directory.
+    git_ref: Optional[str] = dataclasses.field(default=None, metadata={"json": "gitRef"})
+
+    # Image is the name of the container image that can clone code, placing it
+    # in a /src/workspace directory.
+    # This field is optional. When omitted, a container that can clone public
+    # GitHub repos over HTTPs is used.
+    image: Optional[str] = dataclasses.field(default=None, metadata={"json": "image"})
+
+    # Repo is the URL to clone a git repository. With GitHub, this should end in
+    # a `.git` extension.
+    repo: Optional[str] = dataclasses.field(default=None, metadata={"json": "repo"})
+
+
+@dataclasses.dataclass
+class DnsConfig(_Model):
+    """DNSConfig specifies DNS parameters for the client pod. These parameters
+
+    are merged with the ones generated from the DNS policy of the pod.
+    """
+
+    # A list of DNS name server IP addresses. This will be appended to the base
+    # nameservers generated from DNSPolicy. Duplicated nameservers will be
+    # removed.
+    nameservers: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "nameservers"})
+
+    # A list of DNS resolver options. This will be merged with the base options
+    # generated from DNSPolicy. Duplicated entries will be removed. Resolution
+    # options given in Options will override those that appear in the base
+    # DNSPolicy.
+    options: Optional[List[Option]] = dataclasses.field(default=None, metadata={"json": "options"})
+
+    # A list of DNS search domains for host-name lookup. This will be appended
+    # to the base search paths generated from DNSPolicy. Duplicated search paths
+    # will be removed.
+    searches: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "searches"})
+
+
+@dataclasses.dataclass
+class Option(_Model):
+    """PodDNSConfigOption defines DNS resolver options of a pod."""
+
+    # Required.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    value: Optional[str] = dataclasses.field(default=None, metadata={"json": "value"})
+
+
+@dataclasses.dataclass
+class HostAlias(_Model):
+    """HostAlias holds the mapping between IP and hostnames that will be
+
+    injected as an entry in the pod's hosts file.
+    """
+
+    # Hostnames for the above IP address.
+    hostnames: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "hostnames"})
+
+    # IP address of the host file entry.
+    ip: Optional[str] = dataclasses.field(default=None, metadata={"json": "ip"})
+
+
+@dataclasses.dataclass
+class Run(_Model):
+    """A single application container that you want to run within a pod."""
+
+    # Name of the container specified as a DNS_LABEL. Each container in a pod
+    # must have a unique name (DNS_LABEL). Cannot be updated.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # Arguments to the entrypoint. The container image's CMD is used if this is
+    # not provided. Variable references $(VAR_NAME) are expanded using the
+    # container's environment. If a variable cannot be resolved, the reference
+    # in the input string will be unchanged. Double $$ are reduced to a single
+    # $, which allows for escaping the $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)"
+    # will produce the string literal "$(VAR_NAME)". Escaped references will
+    # never be expanded, regardless of whether the variable exists or not.
+    # Cannot be updated. More info:
+    # https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+    args: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "args"})
+
+    # Entrypoint array. Not executed within a shell. The container image's
+    # ENTRYPOINT is used if this is not provided. Variable references
+    # $(VAR_NAME) are expanded using the container's environment. If a variable
+    # cannot be resolved, the reference in the input string will be unchanged.
+    # Double $$ are reduced to a single $, which allows for escaping the
+    # $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)" will produce the string literal
+    # "$(VAR_NAME)". Escaped references will never be expanded, regardless of
+    # whether the variable exists or not. Cannot be updated. More info:
+    # https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+    command: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "command"})
+
+    # List of environment variables to set in the container. Cannot be updated.
+    env: Optional[List[Env]] = dataclasses.field(default=None, metadata={"json": "env"})
+
+    # List of sources to populate environment variables in the container. The
+    # keys defined within a source must be a C_IDENTIFIER. All invalid keys will
+    # be reported as an event when the container is starting. When a key exists
+    # in multiple sources, the value associated with the last source will take
+    # precedence. Values defined by an Env with a duplicate key will take
+    # precedence. Cannot be updated.
+    env_from: Optional[List[EnvFrom]] = dataclasses.field(default=None, metadata={"json": "envFrom"})
+
+    # Container image name. More info:
+    # https://kubernetes.io/docs/concepts/containers/images This field is
+    # optional to allow higher level config management to default or override
+    # container images in workload controllers like Deployments and
+    # StatefulSets.
+    image: Optional[str] = dataclasses.field(default=None, metadata={"json": "image"})
+
+    # Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always
+    # if :latest tag is specified, or IfNotPresent otherwise. Cannot be updated.
+    # More info:
+    # https://kubernetes.io/docs/concepts/containers/images#updating-images
+    image_pull_policy: Optional[str] = dataclasses.field(default=None, metadata={"json": "imagePullPolicy"})
+
+    # Actions that the management system should take in response to container
+    # lifecycle events. Cannot be updated.
+    lifecycle: Optional[Lifecycle] = dataclasses.field(default=None, metadata={"json": "lifecycle"})
+
+    # Periodic probe of container liveness. Container will be restarted if the
+    # probe fails. Cannot be updated. More info:
+    # https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    liveness_probe: Optional[LivenessProbe] = dataclasses.field(default=None, metadata={"json": "livenessProbe"})
+
+    # List of ports to expose from the container. Not specifying a port here
+    # DOES NOT prevent that port from being exposed. Any port which is listening
+    # on the default "0.0.0.0" address inside a container will be accessible
+    # from the network. Modifying this array with strategic merge patch may
+    # corrupt the data. For more information See
+    # https://github.com/kubernetes/kubernetes/issues/108255. Cannot be updated.
+    ports: Optional[List[Port]] = dataclasses.field(default=None, metadata={"json": "ports"})
+
+    # Periodic probe of container service readiness. Container will be removed
+    # from service endpoints if the probe fails. Cannot be updated. More info:
+    # https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    readiness_probe: Optional[LivenessProbe] = dataclasses.field(default=None, metadata={"json": "readinessProbe"})
+
+    # Compute Resources required by this container. Cannot be updated. More
+    # info:
+    # https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+    resources: Optional[Resources] = dataclasses.field(default=None, metadata={"json": "resources"})
+
+    # SecurityContext defines the security options the container should be run
+    # with. If set, the fields of SecurityContext override the equivalent fields
+    # of PodSecurityContext. More info:
+    # https://kubernetes.io/docs/tasks/configure-pod-container/security-context/
+    security_context: Optional[SecurityContext] = dataclasses.field(default=None, metadata={"json": "securityContext"})
+
+    # StartupProbe indicates that the Pod has successfully initialized. If
+    # specified, no other probes are executed until this completes successfully.
+    # If this probe fails, the Pod will be restarted, just as if the
+    # livenessProbe failed. This can be used to provide different probe
+    # parameters at the beginning of a Pod's lifecycle, when it might take a
+    # long time to load data or warm a cache, than during steady-state
+    # operation. This cannot be updated. More info:
+    # https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    startup_probe: Optional[LivenessProbe] = dataclasses.field(default=None, metadata={"json": "startupProbe"})
+
+    # Whether this container should allocate a buffer for stdin in the container
+    # runtime. If this is not set, reads from stdin in the container will always
+    # result in EOF. Default is false.
+    stdin: Optional[bool] = dataclasses.field(default=None, metadata={"json": "stdin"})
+
+    # Whether the container runtime should close the stdin channel after it has
+    # been opened by a single attach. When stdin is true the stdin stream will
+    # remain open across multiple attach sessions. If stdinOnce is set to true,
+    # stdin is opened on container start, is empty until the first client
+    # attaches to stdin, and then remains open and accepts data until the client
+    # disconnects, at which time stdin is closed and remains closed until the
+    # container is restarted. If this flag is false, a container processes that
+    # reads from stdin will never receive an EOF. Default is false
+    stdin_once: Optional[bool] = dataclasses.field(default=None, metadata={"json": "stdinOnce"})
+
+    # Optional: Path at which the file to which the container's termination
+    # message will be written is mounted into the container's filesystem.
+    # Message written is intended to be brief final status, such as an assertion
+    # failure message. Will be truncated by the node if greater than 4096 bytes.
+    # The total message length across all containers will be limited to 12kb.
+    # Defaults to /dev/termination-log. Cannot be updated.
+    termination_message_path: Optional[str] = dataclasses.field(default=None, metadata={"json": "terminationMessagePath"})
+
+    # Indicate how the termination message should be populated. File will use
+    # the contents of terminationMessagePath to populate the container status
+    # message on both success and failure. FallbackToLogsOnError will use the
+    # last chunk of container log output if the termination message file is
+    # empty and the container exited with an error. The log output is limited to
+    # 2048 bytes or 80 lines, whichever is smaller. Defaults to File. Cannot be
+    # updated.
+    termination_message_policy: Optional[str] = dataclasses.field(default=None, metadata={"json": "terminationMessagePolicy"})
+
+    # Whether this container should allocate a TTY for itself, also requires
+    # 'stdin' to be true. Default is false.
+    tty: Optional[bool] = dataclasses.field(default=None, metadata={"json": "tty"})
+
+    # volumeDevices is the list of block devices to be used by the container.
+    volume_devices: Optional[List[VolumeDevice]] = dataclasses.field(default=None, metadata={"json": "volumeDevices"})
+
+    # Pod volumes to mount into the container's filesystem. Cannot be updated.
+    volume_mounts: Optional[List[VolumeMount]] = dataclasses.field(default=None, metadata={"json": "volumeMounts"})
+
+    # Container's working directory. If not specified, the container runtime's
+    # default will be used, which might be configured in the container image.
+    # Cannot be updated.
+    working_dir: Optional[str] = dataclasses.field(default=None, metadata={"json": "workingDir"})
+
+
+@dataclasses.dataclass
+class EnvFrom(_Model):
+    """EnvFromSource represents the source of a set of ConfigMaps"""
+
+    # The ConfigMap to select from
+    config_map_ref: Optional[ConfigMapRef] = dataclasses.field(default=None, metadata={"json": "configMapRef"})
+
+    # An optional identifier to prepend to each key in the ConfigMap. Must be a
+    # C_IDENTIFIER.
+    prefix: Optional[str] = dataclasses.field(default=None, metadata={"json": "prefix"})
+
+    # The Secret to select from
+    secret_ref: Optional[SecretRef] = dataclasses.field(default=None, metadata={"json": "secretRef"})
+
+
+@dataclasses.dataclass
+class ConfigMapRef(_Model):
+    """The ConfigMap to select from"""
+
+    # Name of the referent. More info:
+    # https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+    # TODO: Add other useful fields. apiVersion, kind, uid?
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Specify whether the ConfigMap must be defined
+    optional: Optional[bool] = dataclasses.field(default=None, metadata={"json": "optional"})
+
+
+@dataclasses.dataclass
+class SecretRef(_Model):
+    """The Secret to select from"""
+
+    # Name of the referent. More info:
+    # https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+    # TODO: Add other useful fields. apiVersion, kind, uid?
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Specify whether the Secret must be defined
+    optional: Optional[bool] = dataclasses.field(default=None, metadata={"json": "optional"})
+
+
+@dataclasses.dataclass
+class Lifecycle(_Model):
+    """Actions that the management system should take in response to container
+
+    lifecycle events. Cannot be updated.
+    """
+
+    # PostStart is called immediately after a container is created. If the
+    # handler fails, the container is terminated and restarted according to its
+    # restart policy. Other management of the container blocks until the hook
+    # completes. More info:
+    # https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+    post_start: Optional[PostStart] = dataclasses.field(default=None, metadata={"json": "postStart"})
+
+    # PreStop is called immediately before a container is terminated due to an
+    # API request or management event such as liveness/startup probe failure,
+    # preemption, resource contention, etc. The handler is not called if the
+    # container crashes or exits. The Pod's termination grace period countdown
+    # begins before the PreStop hook is executed. Regardless of the outcome of
+    # the handler, the container will eventually terminate within the Pod's
+    # termination grace period (unless delayed by finalizers). Other management
+    # of the container blocks until the hook completes or until the termination
+    # grace period is reached. More info:
+    # https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+    pre_stop: Optional[PostStart] = dataclasses.field(default=None, metadata={"json": "preStop"})
+
+
+@dataclasses.dataclass
+class PostStart(_Model):
+    """PostStart is called immediately after a container is created. If the
+
+    handler fails, the container is terminated and restarted according to
+    its restart policy. Other management of the container blocks until the
+    hook completes. More info:
+    https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+    """
+
+    # Exec specifies the action to take.
+    exec: Optional[Exec] = dataclasses.field(default=None, metadata={"json": "exec"})
+
+    # HTTPGet specifies the http request to perform.
+    http_get: Optional[HttpGet] = dataclasses.field(default=None, metadata={"json": "httpGet"})
+
+    # Deprecated. TCPSocket is NOT supported as a LifecycleHandler and kept for
+    # the backward compatibility. There are no validation of this field and
+    # lifecycle hooks will fail in runtime when tcp handler is specified.
+    tcp_socket: Optional[TcpSocket] = dataclasses.field(default=None, metadata={"json": "tcpSocket"})
+
+
+@dataclasses.dataclass
+class Exec(_Model):
+    """Exec specifies the action to take."""
+
+    # Command is the command line to execute inside the container, the working
+    # directory for the command is root ('/') in the container's filesystem. The
+    # command is simply exec'd, it is not run inside a shell, so traditional
+    # shell instructions ('|', etc) won't work. To use a shell, you need to
+    # explicitly call out to that shell. Exit status of 0 is treated as
+    # live/healthy and non-zero is unhealthy.
+    command: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "command"})
+
+
+@dataclasses.dataclass
+class HttpGet(_Model):
+    """HTTPGet specifies the http request to perform."""
+
+    # Name or number of the port to access on the container. Number must be in
+    # the range 1 to 65535. Name must be an IANA_SVC_NAME.
+    port: Union[int, str] = dataclasses.field(metadata={"json": "port"})
+
+    # Host name to connect to, defaults to the pod IP. You probably want to set
+    # "Host" in httpHeaders instead.
+    host: Optional[str] = dataclasses.field(default=None, metadata={"json": "host"})
+
+    # Custom headers to set in the request. HTTP allows repeated headers.
+    http_headers: Optional[List[HttpHeader]] = dataclasses.field(default=None, metadata={"json": "httpHeaders"})
+
+    # Path to access on the HTTP server.
+    path: Optional[str] = dataclasses.field(default=None, metadata={"json": "path"})
+
+    # Scheme to use for connecting to the host. Defaults to HTTP.
+    scheme: Optional[str] = dataclasses.field(default=None, metadata={"json": "scheme"})
+
+
+@dataclasses.dataclass
+class HttpHeader(_Model):
+    """HTTPHeader describes a custom header to be used in HTTP probes"""
+
+    # The header field name. This will be canonicalized upon output, so
+    # case-variant names will be understood as the same header.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # The header field value
+    value: str = dataclasses.field(metadata={"json": "value"})
+
+
+@dataclasses.dataclass
+class TcpSocket(_Model):
+    """Deprecated. TCPSocket is NOT supported as a LifecycleHandler and kept
+
+    for the backward compatibility. There are no validation of this field
+    and lifecycle hooks will fail in runtime when tcp handler is specified.
+    """
+
+    # Number or name of the port to access on the container. Number must be in
+    # the range 1 to 65535. Name must be an IANA_SVC_NAME.
+    port: Union[int, str] = dataclasses.field(metadata={"json": "port"})
+
+    # Optional: Host name to connect to, defaults to the pod IP.
+    host: Optional[str] = dataclasses.field(default=None, metadata={"json": "host"})
+
+
+@dataclasses.dataclass
+class LivenessProbe(_Model):
+    """Periodic probe of container liveness. Container will be restarted if the
+
+    probe fails. Cannot be updated. More info:
+    https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    """
+
+    # Exec specifies the action to take.
+    exec: Optional[Exec] = dataclasses.field(default=None, metadata={"json": "exec"})
+
+    # Minimum consecutive failures for the probe to be considered failed after
+    # having succeeded. Defaults to 3. Minimum value is 1.
+    failure_threshold: Optional[int] = dataclasses.field(default=None, metadata={"json": "failureThreshold"})
+
+    # GRPC specifies an action involving a GRPC port. This is a beta field and
+    # requires enabling GRPCContainerProbe feature gate.
+    grpc: Optional[Grpc] = dataclasses.field(default=None, metadata={"json": "grpc"})
+
+    # HTTPGet specifies the http request to perform.
+    http_get: Optional[HttpGet] = dataclasses.field(default=None, metadata={"json": "httpGet"})
+
+    # Number of seconds after the container has started before liveness probes
+    # are initiated. More info:
+    # https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    initial_delay_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "initialDelaySeconds"})
+
+    # How often (in seconds) to perform the probe. Default to 10 seconds.
+    # Minimum value is 1.
+    period_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "periodSeconds"})
+
+    # Minimum consecutive successes for the probe to be considered successful
+    # after having failed. Defaults to 1. Must be 1 for liveness and startup.
+    # Minimum value is 1.
+    success_threshold: Optional[int] = dataclasses.field(default=None, metadata={"json": "successThreshold"})
+
+    # TCPSocket specifies an action involving a TCP port.
+    tcp_socket: Optional[TcpSocket] = dataclasses.field(default=None, metadata={"json": "tcpSocket"})
+
+    # Optional duration in seconds the pod needs to terminate gracefully upon
+    # probe failure. The grace period is the duration in seconds after the
+    # processes running in the pod are sent a termination signal and the time
+    # when the processes are forcibly halted with a kill signal. Set this value
+    # longer than the expected cleanup time for your process. If this value is
+    # nil, the pod's terminationGracePeriodSeconds will be used. Otherwise, this
+    # value overrides the value provided by the pod spec. Value must be
+    # non-negative integer. The value zero indicates stop immediately via the
+    # kill signal (no opportunity to shut down). This is a beta field and
+    # requires enabling ProbeTerminationGracePeriod feature gate. Minimum value
+    # is 1. spec.terminationGracePeriodSeconds is used if unset.
+    termination_grace_period_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "terminationGracePeriodSeconds"})
+
+    # Number of seconds after which the probe times out. Defaults to 1 second.
+    # Minimum value is 1. More info:
+    # https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+    timeout_seconds: Optional[int] = dataclasses.field(default=None, metadata={"json": "timeoutSeconds"})
+
+
+@dataclasses.dataclass
+class Grpc(_Model):
+    """GRPC specifies an action involving a GRPC port. This is a beta field and
+
+    requires enabling GRPCContainerProbe feature gate.
+    """
+
+    # Port number of the gRPC service. Number must be in the range 1 to 65535.
+    port: int = dataclasses.field(metadata={"json": "port"})
+
+    # Service is the name of the service to place in the gRPC HealthCheckRequest
+    # (see https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+    # If this is not specified, the default behavior is defined by gRPC.
+    service: Optional[str] = dataclasses.field(default=None, metadata={"json": "service"})
+
+
+@dataclasses.dataclass
+class Port(_Model):
+    """ContainerPort represents a network port in a single container."""
+
+    # Number of port to expose on the pod's IP address. This must be a valid
+    # port number, 0 < x < 65536.
+    container_port: int = dataclasses.field(metadata={"json": "containerPort"})
+
+    # What host IP to bind the external port to.
+    host_ip: Optional[str] = dataclasses.field(default=None, metadata={"json": "hostIP"})
+
+    # Number of port to expose on the host. If specified, this must be a valid
+    # port number, 0 < x < 65536. If HostNetwork is specified, this must match
+    # ContainerPort. Most containers do not need this.
+    host_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "hostPort"})
+
+    # If specified, this must be an IANA_SVC_NAME and unique within the pod.
+    # Each named port in a pod must have a unique name. Name for the port that
+    # can be referred to by services.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Protocol for port. Must be UDP, TCP, or SCTP. Defaults to "TCP".
+    protocol: Optional[str] = dataclasses.field(default=None, metadata={"json": "protocol"})
+
+
+@dataclasses.dataclass
+class Resources(_Model):
+    """Compute Resources required by this container. Cannot be updated. More
+
+    info:
+    https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+    """
+
+    # Limits describes the maximum amount of compute resources allowed. More
+    # info:
+    # https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+    limits: Optional[Dict[str, Union[int, str]]] = dataclasses.field(default=None, metadata={"json": "limits"})
+
+    # Requests describes the minimum amount of compute resources required. If
+    # Requests is omitted for a container, it defaults to Limits if that is
+    # explicitly specified, otherwise to an implementation-defined value. More
+    # info:
+    # https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+    requests: Optional[Dict[str, Union[int, str]]] = dataclasses.field(default=None, metadata={"json": "requests"})
+
+
+@dataclasses.dataclass
+class SecurityContext(_Model):
+    """SecurityContext defines the security options the container should be run
+
+    with. If set, the fields of SecurityContext override the equivalent
+    fields of PodSecurityContext. More info:
+    https://kubernetes.io/docs/tasks/configure-pod-container/security-context/
+    """
+
+    # AllowPrivilegeEscalation controls whether a process can gain more
+    # privileges than its parent process. This bool directly controls if the
+    # no_new_privs flag will be set on the container process.
+    # AllowPrivilegeEscalation is true always when the container is: 1) run as
+    # Privileged 2) has CAP_SYS_ADMIN Note that this field cannot be set when
+    # spec.os.name is windows.
+    allow_privilege_escalation: Optional[bool] = dataclasses.field(default=None, metadata={"json": "allowPrivilegeEscalation"})
+
+    # The capabilities to add/drop when running containers. Defaults to the
+    # default set of capabilities granted by the container runtime. Note that
+    # this field cannot be set when spec.os.name is windows.
+    capabilities: Optional[Capabilities] = dataclasses.field(default=None, metadata={"json": "capabilities"})
+
+    # Run container in privileged mode. Processes in privileged containers are
+    # essentially equivalent to root on the host. Defaults to false. Note that
+    # this field cannot be set when spec.os.name is windows.
+    privileged: Optional[bool] = dataclasses.field(default=None, metadata={"json": "privileged"})
+
+    # procMount denotes the type of proc mount to use for the containers. The
+    # default is DefaultProcMount which uses the container runtime defaults for
+    # readonly paths and masked paths. This requires the ProcMountType feature
+    # flag to be enabled. Note that this field cannot be set when spec.os.name
+    # is windows.
+    proc_mount: Optional[str] = dataclasses.field(default=None, metadata={"json": "procMount"})
+
+    # Whether this container has a read-only root filesystem. Default is false.
+    # Note that this field cannot be set when spec.os.name is windows.
+    read_only_root_filesystem: Optional[bool] = dataclasses.field(default=None, metadata={"json": "readOnlyRootFilesystem"})
+
+    # The GID to run the entrypoint of the container process. Uses runtime
+    # default if unset. May also be set in PodSecurityContext. If set in both
+    # SecurityContext and PodSecurityContext, the value specified in
+    # SecurityContext takes precedence. Note that this field cannot be set when
+    # spec.os.name is windows.
+    run_as_group: Optional[int] = dataclasses.field(default=None, metadata={"json": "runAsGroup"})
+
+    # Indicates that the container must run as a non-root user. If true, the
+    # Kubelet will validate the image at runtime to ensure that it does not run
+    # as UID 0 (root) and fail to start the container if it does. If unset or
+    # false, no such validation will be performed. May also be set in
+    # PodSecurityContext. If set in both SecurityContext and PodSecurityContext,
+    # the value specified in SecurityContext takes precedence.
+    run_as_non_root: Optional[bool] = dataclasses.field(default=None, metadata={"json": "runAsNonRoot"})
+
+    # The UID to run the entrypoint of the container process. Defaults to user
+    # specified in image metadata if unspecified. May also be set in
+    # PodSecurityContext. If set in both SecurityContext and PodSecurityContext,
+    # the value specified in SecurityContext takes precedence. Note that this
+    # field cannot be set when spec.os.name is windows.
+    run_as_user: Optional[int] = dataclasses.field(default=None, metadata={"json": "runAsUser"})
+
+    # The SELinux context to be applied to the container. If unspecified, the
+    # container runtime will allocate a random SELinux context for each
+    # container. May also be set in PodSecurityContext. If set in both
+    # SecurityContext and PodSecurityContext, the value specified in
+    # SecurityContext takes precedence. Note that this field cannot be set when
+    # spec.os.name is windows.
+    se_linux_options: Optional[SeLinuxOptions] = dataclasses.field(default=None, metadata={"json": "seLinuxOptions"})
+
+    # The seccomp options to use by this container. If seccomp options are
+    # provided at both the pod & container level, the container options override
+    # the pod options. Note that this field cannot be set when spec.os.name is
+    # windows.
+    seccomp_profile: Optional[SeccompProfile] = dataclasses.field(default=None, metadata={"json": "seccompProfile"})
+
+    # The Windows specific settings applied to all containers. If unspecified,
+    # the options from the PodSecurityContext will be used. If set in both
+    # SecurityContext and PodSecurityContext, the value specified in
+    # SecurityContext takes precedence. Note that this field cannot be set when
+    # spec.os.name is linux.
+    windows_options: Optional[WindowsOptions] = dataclasses.field(default=None, metadata={"json": "windowsOptions"})
+
+
+@dataclasses.dataclass
+class Capabilities(_Model):
+    """The capabilities to add/drop when running containers. Defaults to the
+
+    default set of capabilities granted by the container runtime. Note that
+    this field cannot be set when spec.os.name is windows.
+    """
+
+    # Added capabilities
+    add: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "add"})
+
+    # Removed capabilities
+    drop: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "drop"})
+
+
+@dataclasses.dataclass
+class SeLinuxOptions(_Model):
+    """The SELinux context to be applied to the container. If unspecified, the
+
+    container runtime will allocate a random SELinux context for each
+    container. May also be set in PodSecurityContext. If set in both
+    SecurityContext and PodSecurityContext, the value specified in
+    SecurityContext takes precedence. Note that this field cannot be set
+    when spec.os.name is windows.
+    """
+
+    # Level is SELinux level label that applies to the container.
+    level: Optional[str] = dataclasses.field(default=None, metadata={"json": "level"})
+
+    # Role is a SELinux role label that applies to the container.
+    role: Optional[str] = dataclasses.field(default=None, metadata={"json": "role"})
+
+    # Type is a SELinux type label that applies to the container.
+    type: Optional[str] = dataclasses.field(default=None, metadata={"json": "type"})
+
+    # User is a SELinux user label that applies to the container.
+    user: Optional[str] = dataclasses.field(default=None, metadata={"json": "user"})
+
+
+@dataclasses.dataclass
+class SeccompProfile(_Model):
+    """The seccomp options to use by this container. If seccomp options are
+
+    provided at both the pod & container level, the container options
+    override the pod options. Note that this field cannot be set when
+    spec.os.name is windows.
+    """
+
+    # type indicates which kind of seccomp profile will be applied. Valid
+    # options are:
+    # Localhost - a profile defined in a file on the node should be used.
+    # RuntimeDefault - the container runtime default profile should be used.
+    # Unconfined - no profile should be applied.
+    type: str = dataclasses.field(metadata={"json": "type"})
+
+    # localhostProfile indicates a profile defined in a file on the node should
+    # be used. The profile must be preconfigured on the node to work. Must be a
+    # descending path, relative to the kubelet's configured seccomp profile
+    # location. Must only be set if type is "Localhost".
+    localhost_profile: Optional[str] = dataclasses.field(default=None, metadata={"json": "localhostProfile"})
+
+
+@dataclasses.dataclass
+class WindowsOptions(_Model):
+    """The Windows specific settings applied to all containers. If unspecified,
+
+    the options from the PodSecurityContext will be used. If set in both
+    SecurityContext and PodSecurityContext, the value specified in
+    SecurityContext takes precedence. Note that this field cannot be set
+    when spec.os.name is linux.
+    """
+
+    # GMSACredentialSpec is where the GMSA admission webhook
+    # (https://github.com/kubernetes-sigs/windows-gmsa) inlines the contents of
+    # the GMSA credential spec named by the GMSACredentialSpecName field.
+    gmsa_credential_spec: Optional[str] = dataclasses.field(default=None, metadata={"json": "gmsaCredentialSpec"})
+
+    # GMSACredentialSpecName is the name of the GMSA credential spec to use.
+    gmsa_credential_spec_name: Optional[str] = dataclasses.field(default=None, metadata={"json": "gmsaCredentialSpecName"})
+
+    # HostProcess determines if a container should be run as a 'Host Process'
+    # container. This field is alpha-level and will only be honored by
+    # components that enable the WindowsHostProcessContainers feature flag.
+    # Setting this field without the feature flag will result in errors when
+    # validating the Pod. All of a Pod's containers must have the same effective
+    # HostProcess value (it is not allowed to have a mix of HostProcess
+    # containers and non-HostProcess containers). In addition, if HostProcess is
+    # true then HostNetwork must also be set to true.
+    host_process: Optional[bool] = dataclasses.field(default=None, metadata={"json": "hostProcess"})
+
+    # The UserName in Windows to run the entrypoint of the container process.
+    # Defaults to the user specified in image metadata if unspecified. May also
+    # be set in PodSecurityContext. If set in both SecurityContext and
+    # PodSecurityContext, the value specified in SecurityContext takes
+    # precedence.
+    run_as_user_name: Optional[str] = dataclasses.field(default=None, metadata={"json": "runAsUserName"})
+
+
+@dataclasses.dataclass
+class VolumeDevice(_Model):
+    """volumeDevice describes a mapping of a raw block device within a
+
+    container.
+    """
+
+    # devicePath is the path inside of the container that the device will be
+    # mapped to.
+    device_path: str = dataclasses.field(metadata={"json": "devicePath"})
+
+    # name must match the name of a persistentVolumeClaim in the pod
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+
+@dataclasses.dataclass
+class VolumeMount(_Model):
+    """VolumeMount describes a mounting of a Volume within a container."""
+
+    # Path within the container at which the volume should be mounted. Must not
+    # contain ':'.
+    mount_path: str = dataclasses.field(metadata={"json": "mountPath"})
+
+    # This must match the Name of a Volume.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # mountPropagation determines how mounts are propagated from the host to
+    # container and the other way around. When not set, MountPropagationNone is
+    # used. This field is beta in 1.10.
+    mount_propagation: Optional[str] = dataclasses.field(default=None, metadata={"json": "mountPropagation"})
+
+    # Mounted read-only if true, read-write otherwise (false or unspecified).
+    # Defaults to false.
+    read_only: Optional[bool] = dataclasses.field(default=None, metadata={"json": "readOnly"})
+
+    # Path within the volume from which the container's volume should be
+    # mounted. Defaults to "" (volume's root).
+    sub_path: Optional[str] = dataclasses.field(default=None, metadata={"json": "subPath"})
+
+    # Expanded path within the volume from which the container's volume should
+    # be mounted. Behaves similarly to SubPath but environment variable
+    # references $(VAR_NAME) are expanded using the container's environment.
+    # Defaults to "" (volume's root). SubPathExpr and SubPath are mutually
+    # exclusive.
+    sub_path_expr: Optional[str] = dataclasses.field(default=None, metadata={"json": "subPathExpr"})
+
+
+@dataclasses.dataclass
+class Driver(_Model):
+    """Driver is the component that orchestrates the test. It may be
+
+    unspecified, allowing the system to choose the appropriate driver. Tests
+    with generators do not receive a default driver, since the generators
+    send load without instructions from a driver.
+    """
+
+    # Language is the code that identifies the programming language used by the
+    # driver. For example, "cxx" may represent C++.
+    # Specifying a language is required. If the language is unknown to the
+    # operator, a user must manually set a run image. If the user intends for
+    # the operator to clone and build code, it must also manually set a build
+    # image.
+    language: str = dataclasses.field(metadata={"json": "language"})
+
+    # Run describes a list of run containers. The container for the test driver
+    # is always the first container on the list.
+    run: List[Run] = dataclasses.field(metadata={"json": "run"})
+
+    # Budget limits the memory and ephemeral storage of the run container of the
+    # driver. Values set here override the limits of the container.
+    budget: Optional[Budget] = dataclasses.field(default=None, metadata={"json": "budget"})
+
+    # Build describes how the cloned code should be built, including any
+    # compiler arguments or flags. This field is only necessary if the output
+    # from the clone container must be pre-processed before running the tests in
+    # the run container.
+    # When build is specified on a test, the operator will use the driver's
+    # language to find a container with a compiler for that language. If the
+    # language is unknown to the operator, a user must include a custom docker
+    # image.
+    # Note that it does not usually make sense to include build instructions
+    # without clone instructions. If doing so, the build container must include
+    # its input and write its output into the /src/workspace directory for the
+    # run container to access it.
+    build: Optional[Build] = dataclasses.field(default=None, metadata={"json": "build"})
+
+    # Clone specifies the repository and snapshot where the code for the driver
+    # can be found. This is used to test alternative implementations for the
+    # driver. Most often, this will not be set. When unset, the operator will
+    # use a default driver that is prebuilt.
+    clone: Optional[Clone] = dataclasses.field(default=None, metadata={"json": "clone"})
+
+    # DNSConfig specifies DNS parameters for the driver pod. These parameters
+    # are merged with the ones generated from the DNS policy of the pod.
+    dns_config: Optional[DnsConfig] = dataclasses.field(default=None, metadata={"json": "dnsConfig"})
+
+    # HostAliases are entries that are added to the /etc/hosts file of the
+    # driver pod. They allow the driver to address hosts by stable names, for
+    # example to match the subject alternative names of a TLS certificate.
+    host_aliases: Optional[List[HostAlias]] = dataclasses.field(default=None, metadata={"json": "hostAliases"})
+
+    # Name is a string that uniquely names this driver. Since load tests only
+    # support one driver, it is not recommended to set this field. If no name is
+    # explicitly provided, the operator will assign one.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Pool specifies the name of the set of nodes where this driver should be
+    # scheduled. If unset, the controller will choose a pool based on defaults.
+    pool: Optional[str] = dataclasses.field(default=None, metadata={"json": "pool"})
+
+
+@dataclasses.dataclass
+class Generator(_Model):
+    """Generator defines a component that sends load to a gRPC service on its
+
+    own, without taking instructions from the driver. For example, a
+    generator may run ghz against a server in the test or a service that is
+    already deployed.
+    """
+
+    # Run describes a list of run containers. The container for the generator is
+    # always the first container on the list, and it must set an image.
+    run: List[Run] = dataclasses.field(metadata={"json": "run"})
+
+    # Adapter is the format of the report that the generator writes to the
+    # termination message of its first run container. The report is parsed into
+    # the result summary of the test. When omitted, the report is not parsed,
+    # and only the exit code of the generator is considered.
+    adapter: Optional[Literal["ghz"]] = dataclasses.field(default=None, metadata={"json": "adapter"})
+
+    # Budget limits the memory and ephemeral storage of the run container of the
+    # generator. Values set here override the limits of the container.
+    budget: Optional[Budget] = dataclasses.field(default=None, metadata={"json": "budget"})
+
+    # DNSConfig specifies DNS parameters for the generator pod. These parameters
+    # are merged with the ones generated from the DNS policy of the pod.
+    dns_config: Optional[DnsConfig] = dataclasses.field(default=None, metadata={"json": "dnsConfig"})
+
+    # HostAliases are entries that are added to the /etc/hosts file of the
+    # generator pod. They allow the generator to address the service under test
+    # by a stable name.
+    host_aliases: Optional[List[HostAlias]] = dataclasses.field(default=None, metadata={"json": "hostAliases"})
+
+    # Name is a string that distinguishes this generator from others in the
+    # test. When unset, the operator will assign a name to the generator.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Pool specifies the name of the set of nodes where this generator should be
+    # scheduled. If unset, the generator is scheduled in the default pool for
+    # clients.
+    pool: Optional[str] = dataclasses.field(default=None, metadata={"json": "pool"})
+
+
+@dataclasses.dataclass
+class Interop(_Model):
+    """Interop turns the test into a run of the gRPC interop client suite. The
+
+    driver runs the interop client once for each test case against a server,
+    and the test fails if the client fails any of them. Interop tests do not
+    need clients or scenarios.
+    """
+
+    # TestCases are the names of the interop test cases that the client runs,
+    # such as "empty_unary" or "large_unary". The names are passed to the
+    # --test_case flag of the client.
+    test_cases: List[str] = dataclasses.field(metadata={"json": "testCases"})
+
+    # Server is the name of the server that the client connects to. If unset,
+    # the client connects to the first server of the test.
+    server: Optional[str] = dataclasses.field(default=None, metadata={"json": "server"})
+
+    # UseTLS enables TLS on the connection between the client and the server.
+    # The server must be started with TLS enabled, too.
+    use_tls: Optional[bool] = dataclasses.field(default=None, metadata={"json": "useTLS"})
+
+
+@dataclasses.dataclass
+class LoadProfile(_Model):
+    """LoadProfile sets the load that the clients offer in each scenario,
+
+    replacing the load_params of the client_config in ScenariosJSON. A
+    profile with several steps repeats each scenario once per step, so a
+    range of loads can be measured without editing the scenarios. The
+    timeout must be long enough to cover every step.
+    """
+
+    # Type is the type of the load.
+    type: Literal["ClosedLoop", "Poisson"] = dataclasses.field(metadata={"json": "type"})
+
+    # OfferedLoad is the number of queries per second that each client offers
+    # with a Poisson load.
+    offered_load: Optional[int] = dataclasses.field(default=None, metadata={"json": "offeredLoad"})
+
+    # Ramp offers a Poisson load that increases linearly between two values.
+    # Each scenario is run once for each step of the ramp.
+    ramp: Optional[Ramp] = dataclasses.field(default=None, metadata={"json": "ramp"})
+
+    # Steps lists the loads, in queries per second per client, that are offered
+    # in turn with a Poisson load. Each scenario is run once for each step.
+    steps: Optional[List[int]] = dataclasses.field(default=None, metadata={"json": "steps"})
+
+
+@dataclasses.dataclass
+class Ramp(_Model):
+    """Ramp offers a Poisson load that increases linearly between two values.
+
+    Each scenario is run once for each step of the ramp.
+    """
+
+    # From is the load of the first step, in queries per second per client.
+    from_: int = dataclasses.field(metadata={"json": "from"})
+
+    # Increment is the difference between the loads of consecutive steps.
+    increment: int = dataclasses.field(metadata={"json": "increment"})
+
+    # To is the load of the last step, in queries per second per client. The
+    # last step is omitted if it is not reached by whole increments.
+    to: int = dataclasses.field(metadata={"json": "to"})
+
+
+@dataclasses.dataclass
+class NetworkProfile(_Model):
+    """NetworkProfile emulates a wide area network between the workers of the
+
+    test. When set, the traffic that leaves each client and server pod is
+    shaped with netem, so a round trip between a client and a server
+    experiences twice the configured latency. When omitted, the traffic is
+    not shaped.
+    """
+
+    # JitterMs is the random variation, in milliseconds, of the delay added to
+    # each packet. It has no effect unless LatencyMs is also set.
+    jitter_ms: Optional[int] = dataclasses.field(default=None, metadata={"json": "jitterMs"})
+
+    # LatencyMs is the delay, in milliseconds, added to each packet.
+    latency_ms: Optional[int] = dataclasses.field(default=None, metadata={"json": "latencyMs"})
+
+    # LossPct is the percentage of packets that are dropped, such as "0.1".
+    loss_pct: Optional[str] = dataclasses.field(default=None, metadata={"json": "lossPct"})
+
+    # Rate limits the bandwidth of the network interface. It uses the units of
+    # tc, such as "100mbit" or "1gbit".
+    rate: Optional[str] = dataclasses.field(default=None, metadata={"json": "rate"})
+
+
+@dataclasses.dataclass
+class Results(_Model):
+    """Results configures where the results of the test should be stored. When
+
+    omitted, the results will only be stored in Kubernetes for a limited
+    time.
+    """
+
+    # BigQueryTable names a dataset where the results of the test should be
+    # stored. If omitted, no results are saved to BigQuery.
+    # Deprecated: Set Destination instead, so the table is declared once in the
+    # defaults. Tests that only set a table are linked to the destination with
+    # the same table and GCSPrefix, if there is one.
+    big_query_table: Optional[str] = dataclasses.field(default=None, metadata={"json": "bigQueryTable"})
+
+    # Destination names one of the result destinations declared in the defaults
+    # of the controller, such as the BigQuery table of an environment. The
+    # controller fills BigQueryTable and GCSPrefix from the destination, unless
+    # they are set on the test. The test errors if no destination has this name.
+    destination: Optional[str] = dataclasses.field(default=None, metadata={"json": "destination"})
+
+    # GCSPrefix is a Cloud Storage URI, such as gs://bucket/path, under which
+    # the raw JSON output of the driver should be stored. The driver uploads the
+    # output to an object named after the namespace, name and UID of the test,
+    # and the URI of the object is recorded in the status. The service account
+    # of the driver pod must be allowed to create objects in the bucket, for
+    # example through workload identity. If omitted, the raw output is not
+    # stored.
+    gcs_prefix: Optional[str] = dataclasses.field(default=None, metadata={"json": "gcsPrefix"})
+
+
+@dataclasses.dataclass
+class Server(_Model):
+    """Server defines a component that receives traffic from a set of client
+
+    components.
+    """
+
+    # Language is the code that identifies the programming language used by the
+    # server. For example, "java" may represent Java.
+    # Specifying a language is required. If the language is unknown to the
+    # operator, a user must manually set a run image. If the user intends for
+    # the operator to clone and build code, it must also manually set a build
+    # image.
+    language: str = dataclasses.field(metadata={"json": "language"})
+
+    # Run describes a list of run containers. The container for the test server
+    # is always the first container on the list.
+    run: List[Run] = dataclasses.field(metadata={"json": "run"})
+
+    # Budget limits the memory and ephemeral storage of the run container of the
+    # server. Values set here override the limits of the container.
+    budget: Optional[Budget] = dataclasses.field(default=None, metadata={"json": "budget"})
+
+    # Build describes how the cloned code should be built, including any
+    # compiler arguments or flags. This field is only necessary if the output
+    # from the clone container must be pre-processed before running the tests in
+    # the run container.
+    # When build is specified on a test, the operator will use the server's
+    # language to find a container with a compiler for that language. If the
+    # language is unknown to the operator, a user must include a custom docker
+    # image.
+    # Note that it does not usually make sense to include build instructions
+    # without clone instructions. If doing so, the build container must include
+    # its input and write its output into the /src/workspace directory for the
+    # run container to access it.
+    build: Optional[Build] = dataclasses.field(default=None, metadata={"json": "build"})
+
+    # Clone specifies the repository and snapshot where the code for the server
+    # can be found. This field should not be set if the code has been prebuilt
+    # in the run image.
+    clone: Optional[Clone] = dataclasses.field(default=None, metadata={"json": "clone"})
+
+    # DNSConfig specifies DNS parameters for the server pod. These parameters
+    # are merged with the ones generated from the DNS policy of the pod.
+    dns_config: Optional[DnsConfig] = dataclasses.field(default=None, metadata={"json": "dnsConfig"})
+
+    # HostAliases are entries that are added to the /etc/hosts file of the
+    # server pod. They allow the server to address hosts by stable names, for
+    # example to match the subject alternative names of a TLS certificate.
+    host_aliases: Optional[List[HostAlias]] = dataclasses.field(default=None, metadata={"json": "hostAliases"})
+
+    # MetricsPort is the port where the server serves metrics. Its value is
+    # available to the run container in the $METRICS_PORT environment variable.
+    # If another container in the pod declares the same port, the next free port
+    # is used instead, and its number is recorded in the
+    # e2etest.grpc.io/metrics-port annotation of the pod.
+    metrics_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "metricsPort"})
+
+    # Name is a string that distinguishes this server from others in the test.
+    # Since tests are currently limited to one server, setting this field is not
+    # recommended. set this field. If no name is explicitly provided, the
+    # operator will assign one.
+    name: Optional[str] = dataclasses.field(default=None, metadata={"json": "name"})
+
+    # Pool specifies the name of the set of nodes where this server should be
+    # scheduled. If unset, the controller will choose a pool based on defaults.
+    pool: Optional[str] = dataclasses.field(default=None, metadata={"json": "pool"})
+
+    # PprofPort is the port where a Go worker serves the net/http/pprof
+    # handlers, such as the port passed to the --pprof_port flag of the grpc-go
+    # benchmark worker. Its value is available to the run container in the
+    # $PPROF_PORT environment variable. When set, CPU and heap profiles can be
+    # collected from the server during the benchmark.
+    pprof_port: Optional[int] = dataclasses.field(default=None, metadata={"json": "pprofPort"})
+
+
+@dataclasses.dataclass
+class LoadTestStatus(_Model):
+    """LoadTestStatus defines the observed state of LoadTest"""
+
+    # State identifies the current state of the load test. It is important to
+    # note that this state is level-based. This means its transition is
+    # non-deterministic.
+    state: str = dataclasses.field(metadata={"json": "state"})
+
+    # Checkpoints record the outcome of the completed iterations of a soak test,
+    # in the order they completed. Only the last 100 checkpoints are kept, so
+    # the status of a long soak test stays small.
+    checkpoints: Optional[List[Checkpoint]] = dataclasses.field(default=None, metadata={"json": "checkpoints"})
+
+    # Conditions provide additional observations of the load test, such as the
+    # SchedulingSLOExceeded condition.
+    conditions: Optional[List[Condition]] = dataclasses.field(default=None, metadata={"json": "conditions"})
+
+    # Cost is an approximate cost of the nodes used by the test. It is set when
+    # the test terminates, if machine prices are configured in the defaults of
+    # the controller.
+    cost: Optional[Cost] = dataclasses.field(default=None, metadata={"json": "cost"})
+
+    # InteropCases are the results of the test cases of an interop test, as
+    # reported by the interop client when it terminates.
+    interop_cases: Optional[List[InteropCase]] = dataclasses.field(default=None, metadata={"json": "interopCases"})
+
+    # Message is a human legible string that describes the current state.
+    message: Optional[str] = dataclasses.field(default=None, metadata={"json": "message"})
+
+    # Reason is a camel-case string that indicates the reasoning behind the
+    # current state.
+    reason: Optional[str] = dataclasses.field(default=None, metadata={"json": "reason"})
+
+    # RerunRequest is the value of the rerun annotation that started the current
+    # run. It is empty for the first run of the test.
+    rerun_request: Optional[str] = dataclasses.field(default=None, metadata={"json": "rerunRequest"})
+
+    # ResultsURI is the Cloud Storage URI of the raw JSON output of the driver.
+    # It is set when the driver succeeds and the test sets a GCSPrefix in its
+    # results.
+    results_uri: Optional[str] = dataclasses.field(default=None, metadata={"json": "resultsURI"})
+
+    # Run is the zero-based index of the current run of the test. It is
+    # incremented each time the test is rerun with the rerun annotation.
+    run: Optional[int] = dataclasses.field(default=None, metadata={"json": "run"})
+
+    # Runs record the outcome of each previous run of a test that was rerun, in
+    # the order they ran. Only the last 20 runs are kept, so the status of a
+    # test that is rerun often stays small.
+    runs: Optional[List[LoadTestStatusRun]] = dataclasses.field(default=None, metadata={"json": "runs"})
+
+    # ScheduledTime is the time when all pods of the current run of the load
+    # test were first running.
+    scheduled_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "scheduledTime"})
+
+    # SoakIteration is the zero-based index of the current iteration of a soak
+    # test. It is omitted for tests that are not soak tests.
+    soak_iteration: Optional[int] = dataclasses.field(default=None, metadata={"json": "soakIteration"})
+
+    # StartTime is the time when the controller first reconciled the load test.
+    # It is maintained in a best-attempt effort; meaning, it is not guaranteed
+    # to be correct.
+    start_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "startTime"})
+
+    # StopTime is the time when the controller last entered the Succeeded,
+    # Failed or Errored states.
+    stop_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "stopTime"})
+
+    # Summary contains key numbers from the results of the test, as reported by
+    # the driver when it succeeds.
+    summary: Optional[Summary] = dataclasses.field(default=None, metadata={"json": "summary"})
+
+    # UnreachableWorkers lists the workers whose driver port could not be
+    # reached from the driver pod before the benchmark started, as reported by
+    # the ready init container of the driver.
+    unreachable_workers: Optional[List[UnreachableWorker]] = dataclasses.field(default=None, metadata={"json": "unreachableWorkers"})
+
+
+@dataclasses.dataclass
+class Checkpoint(_Model):
+    """SoakCheckpoint records the outcome of a single iteration of a soak test."""
+
+    # Iteration is the zero-based index of the iteration.
+    iteration: int = dataclasses.field(metadata={"json": "iteration"})
+
+    # State is the state of the load test when the iteration completed.
+    state: str = dataclasses.field(metadata={"json": "state"})
+
+    # Time is when the controller observed that the iteration completed.
+    time: str = dataclasses.field(metadata={"json": "time"})
+
+    # Message is a human legible string that describes the outcome of the
+    # iteration.
+    message: Optional[str] = dataclasses.field(default=None, metadata={"json": "message"})
+
+
+@dataclasses.dataclass
+class Condition(_Model):
+    """Condition contains details for one aspect of the current state of this
+
+    API Resource.
+    """
+
+    # lastTransitionTime is the last time the condition transitioned from one
+    # status to another. This should be when the underlying condition changed.
+    # If that is not known, then using the time when the API field changed is
+    # acceptable.
+    last_transition_time: str = dataclasses.field(metadata={"json": "lastTransitionTime"})
+
+    # message is a human readable message indicating details about the
+    # transition. This may be an empty string.
+    message: str = dataclasses.field(metadata={"json": "message"})
+
+    # reason contains a programmatic identifier indicating the reason for the
+    # condition's last transition. Producers of specific condition types may
+    # define expected values and meanings for this field, and whether the values
+    # are considered a guaranteed API. The value should be a CamelCase string.
+    # This field may not be empty.
+    reason: str = dataclasses.field(metadata={"json": "reason"})
+
+    # status of the condition, one of True, False, Unknown.
+    status: Literal["True", "False", "Unknown"] = dataclasses.field(metadata={"json": "status"})
+
+    # type of condition in CamelCase or in foo.example.com/CamelCase.
+    type: str = dataclasses.field(metadata={"json": "type"})
+
+    # observedGeneration represents the .metadata.generation that the condition
+    # was set based upon. For instance, if .metadata.generation is currently 12,
+    # but the .status.conditions[x].observedGeneration is 9, the condition is
+    # out of date with respect to the current state of the instance.
+    observed_generation: Optional[int] = dataclasses.field(default=None, metadata={"json": "observedGeneration"})
+
+
+@dataclasses.dataclass
+class Cost(_Model):
+    """Cost is an approximate cost of the nodes used by the test. It is set
+
+    when the test terminates, if machine prices are configured in the
+    defaults of the controller.
+    """
+
+    # Estimate is the price of the node hours, in the currency of the machine
+    # prices in the defaults of the controller. Nodes with a machine type that
+    # has no price are not included.
+    estimate: str = dataclasses.field(metadata={"json": "estimate"})
+
+    # NodeHours is the total time the nodes of the test were used, in hours.
+    node_hours: str = dataclasses.field(metadata={"json": "nodeHours"})
+
+    # UnpricedMachineTypes lists the machine types of the nodes that have no
+    # price. The machine type is "unknown" for nodes that could not be found.
+    unpriced_machine_types: Optional[List[str]] = dataclasses.field(default=None, metadata={"json": "unpricedMachineTypes"})
+
+
+@dataclasses.dataclass
+class InteropCase(_Model):
+    """InteropCaseResult is the outcome of a single test case of an interop
+
+    test.
+    """
+
+    # Name is the name of the test case.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # Passed is true if the interop client passed the test case.
+    passed: bool = dataclasses.field(metadata={"json": "passed"})
+
+    # Message describes why the test case failed. It is empty for test cases
+    # that passed.
+    message: Optional[str] = dataclasses.field(default=None, metadata={"json": "message"})
+
+
+@dataclasses.dataclass
+class LoadTestStatusRun(_Model):
+    """LoadTestRun records the outcome of a previous run of a load test that
+
+    was rerun.
+    """
+
+    # Run is the zero-based index of the run.
+    run: int = dataclasses.field(metadata={"json": "run"})
+
+    # State is the state of the load test when the run terminated.
+    state: str = dataclasses.field(metadata={"json": "state"})
+
+    # Message is a human legible string that describes the outcome of the run.
+    message: Optional[str] = dataclasses.field(default=None, metadata={"json": "message"})
+
+    # Reason is a camel-case string that indicates the reasoning behind the
+    # state.
+    reason: Optional[str] = dataclasses.field(default=None, metadata={"json": "reason"})
+
+    # ResultsURI is the Cloud Storage URI of the raw JSON output of the driver
+    # in the run.
+    results_uri: Optional[str] = dataclasses.field(default=None, metadata={"json": "resultsURI"})
+
+    # StartTime is the time when the run started.
+    start_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "startTime"})
+
+    # StopTime is the time when the run terminated.
+    stop_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "stopTime"})
+
+    # Summary contains key numbers from the results of the run, as reported by
+    # the driver when it succeeded.
+    summary: Optional[Summary] = dataclasses.field(default=None, metadata={"json": "summary"})
+
+
+@dataclasses.dataclass
+class Summary(_Model):
+    """Summary contains key numbers from the results of the run, as reported by
+
+    the driver when it succeeded.
+    """
+
+    # ClientSystemTime is the percentage of CPU time spent in the system by the
+    # clients.
+    client_system_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "clientSystemTime"})
+
+    # Latency50 is the median latency.
+    latency50: Optional[str] = dataclasses.field(default=None, metadata={"json": "latency50"})
+
+    # Latency99 is the 99th percentile latency.
+    latency99: Optional[str] = dataclasses.field(default=None, metadata={"json": "latency99"})
+
+    # Latency999 is the 99.9th percentile latency.
+    latency999: Optional[str] = dataclasses.field(default=None, metadata={"json": "latency999"})
+
+    # QPS is the number of queries per second.
+    qps: Optional[str] = dataclasses.field(default=None, metadata={"json": "qps"})
+
+    # ServerSystemTime is the percentage of CPU time spent in the system by the
+    # servers.
+    server_system_time: Optional[str] = dataclasses.field(default=None, metadata={"json": "serverSystemTime"})
+
+
+@dataclasses.dataclass
+class UnreachableWorker(_Model):
+    """UnreachableWorker is a worker whose driver port could not be reached
+
+    from the driver pod of a load test.
+    """
+
+    # Address is the address and driver port of the worker.
+    address: str = dataclasses.field(metadata={"json": "address"})
+
+    # Name is the name of the pod of the worker.
+    name: str = dataclasses.field(metadata={"json": "name"})
+
+    # Message describes the last error encountered when connecting to the
+    # worker.
+    message: Optional[str] = dataclasses.field(default=None, metadata={"json": "message"})
@@ -0,0 +1,2117 @@
+// Code generated by gen_models. DO NOT EDIT.
+
+/**
+ * LoadTest is the Schema for the loadtests API
+ */
+export interface LoadTest {
+  /**
+   * APIVersion defines the versioned schema of this representation of an
+   * object. Servers should convert recognized schemas to the latest internal
+   * value, and may reject unrecognized values. More info:
+   * https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+   */
+  apiVersion?: string;
+
+  /**
+   * Kind is a string value representing the REST resource this object
+   * represents. Servers may infer this from the endpoint the client submits
+   * requests to. Cannot be updated. In CamelCase. More info:
+   * https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+   */
+  kind?: string;
+
+  metadata?: { [key: string]: unknown };
+
+  /**
+   * LoadTestSpec defines the desired state of LoadTest
+   */
+  spec?: LoadTestSpec;
+
+  /**
+   * LoadTestStatus defines the observed state of LoadTest
+   */
+  status?: LoadTestStatus;
+}
+
+/**
+ * LoadTestSpec defines the desired state of LoadTest
+ */
+export interface LoadTestSpec {
+  /**
+   * Clients are a list of components that send traffic to servers.
+   */
+  clients?: Client[];
+
+  /**
+   * Driver is the component that orchestrates the test. It may be unspecified,
+   * allowing the system to choose the appropriate driver. Tests with
+   * generators do not receive a default driver, since the generators send load
+   * without instructions from a driver.
+   */
+  driver?: Driver;
+
+  /**
+   * Generators are a list of components that send load without the driver,
+   * such as ghz. When a test has generators and no driver, it succeeds once
+   * every generator has terminated successfully. Servers and clients are
+   * optional in such tests.
+   */
+  generators?: Generator[];
+
+  /**
+   * Interop turns the test into a run of the gRPC interop client suite. The
+   * driver runs the interop client once for each test case against a server,
+   * and the test fails if the client fails any of them. Interop tests do not
+   * need clients or scenarios.
+   */
+  interop?: Interop;
+
+  /**
+   * IPFamily selects the IP family of the addresses that the components of the
+   * test use to communicate. IPv6 requires a cluster with IPv6 pod networking,
+   * and DualStack requires a dual-stack cluster. When unset, the default
+   * family of the cluster is used.
+   */
+  ipFamily?: "IPv4" | "IPv6" | "DualStack";
+
+  /**
+   * KillAfterSeconds overrides the killAfter setting of the controller for
+   * this test. It is the time allowed for the containers of the test to
+   * respond after the timeout before they are killed, and is passed to them in
+   * the KILL_AFTER environment variable. It must be less than the timeout.
+   */
+  killAfterSeconds?: number;
+
+  /**
+   * LoadProfile sets the load that the clients offer in each scenario,
+   * replacing the load_params of the client_config in ScenariosJSON. A profile
+   * with several steps repeats each scenario once per step, so a range of
+   * loads can be measured without editing the scenarios. The timeout must be
+   * long enough to cover every step.
+   */
+  loadProfile?: LoadProfile;
+
+  /**
+   * NetworkProfile emulates a wide area network between the workers of the
+   * test. When set, the traffic that leaves each client and server pod is
+   * shaped with netem, so a round trip between a client and a server
+   * experiences twice the configured latency. When omitted, the traffic is not
+   * shaped.
+   */
+  networkProfile?: NetworkProfile;
+
+  /**
+   * PlacementPolicy determines whether the pods of the test require nodes of
+   * their own. Shared placement is intended for functional tests, whose
+   * results do not depend on the performance of the nodes. Isolated placement
+   * also taints the nodes of the test, so that no other workload lands on
+   * them. When unset, each pod is placed on its own node.
+   */
+  placementPolicy?: "Exclusive" | "Shared" | "Isolated";
+
+  /**
+   * RestartWorkers restarts the clients and servers between the scenarios of
+   * the test, so that state left in a worker by one scenario does not skew the
+   * results of the next. The driver runs each scenario on its own and asks the
+   * workers to quit after it, and the run container of each worker starts its
+   * command again for the next scenario. The time the workers take to restart
+   * is recorded in the metadata of the results. The first run container of
+   * each client and server must set a command. It has no effect on tests with
+   * a single scenario.
+   */
+  restartWorkers?: boolean;
+
+  /**
+   * Results configures where the results of the test should be stored. When
+   * omitted, the results will only be stored in Kubernetes for a limited time.
+   */
+  results?: Results;
+
+  /**
+   * ScenariosJSON is string with the contents of a Scenarios message,
+   * formatted as JSON. See the Scenarios protobuf definition for details:
+   * https://github.com/grpc/grpc-proto/blob/master/grpc/testing/control.proto.
+   */
+  scenariosJSON?: string;
+
+  /**
+   * Servers are a list of components that receive traffic from clients.
+   */
+  servers?: Server[];
+
+  /**
+   * ServiceMesh injects the sidecar proxy of a service mesh into the client
+   * and server pods, so that the traffic between them passes through the mesh.
+   * The mesh must be installed on the cluster. The driver is excluded from the
+   * mesh, and connects to the workers without passing through their proxies.
+   * When unset, no proxies are injected by the test.
+   */
+  serviceMesh?: "Istio" | "Linkerd";
+
+  /**
+   * SoakHours enables soak testing, keeping the scenario running for many
+   * hours to detect slow resource leaks. Each time the driver succeeds before
+   * this many hours have elapsed, the pods for the test are recreated and the
+   * scenario is run again. Results of each iteration are saved as they
+   * complete, and a checkpoint is recorded in the status. The timeout must be
+   * long enough to cover the entire soak.
+   */
+  soakHours?: number;
+
+  /**
+   * TerminationGracePeriodSeconds overrides the terminationGracePeriodSeconds
+   * setting of the controller for this test. It is the time given to the pods
+   * of the test to stop after the test is deleted. It must be longer than the
+   * 10 second stop delay of the workers, and must not exceed the timeout.
+   */
+  terminationGracePeriodSeconds?: number;
+
+  /**
+   * Timeout provides the longest running time allowed for a LoadTest. When
+   * omitted, the controller computes it from the warmup and benchmark time of
+   * the scenarios, the steps of the load profile, the number of workers and
+   * the soak hours of the test.
+   */
+  timeoutSeconds?: number;
+
+  /**
+   * TTL provides the longest time a LoadTest can live on the cluster.
+   */
+  ttlSeconds: number;
+}
+
+/**
+ * Client defines a component that sends traffic to a server component.
+ */
+export interface Client {
+  /**
+   * Budget limits the memory and ephemeral storage of the run container of the
+   * client. Values set here override the limits of the container.
+   */
+  budget?: Budget;
+
+  /**
+   * Build describes how the cloned code should be built, including any
+   * compiler arguments or flags. This field is only necessary if the output
+   * from the clone container must be pre-processed before running the tests in
+   * the run container.
+   * When build is specified on a test, the operator will use the client's
+   * language to find a container with a compiler for that language. If the
+   * language is unknown to the operator, a user must include a custom docker
+   * image.
+   * Note that it does not usually make sense to include build instructions
+   * without clone instructions. If doing so, the build container must include
+   * its input and write its output into the /src/workspace directory for the
+   * run container to access it.
+   */
+  build?: Build;
+
+  /**
+   * Clone specifies the repository and snapshot where the code for the client
+   * can be found. This field should not be set if the code has been prebuilt
+   * in the run image.
+   */
+  clone?: Clone;
+
+  /**
+   * CSDSPort is the port where a proxyless client of a PSM test serves the
+   * Client Status Discovery Service (CSDS). Its value is available to the run
+   * container in the $CSDS_PORT environment variable. When set, the xds-server
+   * container fetches the xDS configuration of the client while it runs, and
+   * writes the last configuration it fetched to its log when the client stops.
+   */
+  csdsPort?: number;
+
+  /**
+   * DNSConfig specifies DNS parameters for the client pod. These parameters
+   * are merged with the ones generated from the DNS policy of the pod.
+   */
+  dnsConfig?: DnsConfig;
+
+  /**
+   * HostAliases are entries that are added to the /etc/hosts file of the
+   * client pod. They allow the client to address hosts by stable names, for
+   * example to match the subject alternative names of a TLS certificate.
+   */
+  hostAliases?: HostAlias[];
+
+  /**
+   * Language is the code that identifies the programming language used by the
+   * client. For example, "go" may represent Go.
+   * Specifying a language is required. If the language is unknown to the
+   * operator, a user must manually set a run image. If the user intends for
+   * the operator to clone and build code, it must also manually set a build
+   * image.
+   */
+  language: string;
+
+  /**
+   * MetricsPort is the port where the client serves metrics. Its value is
+   * available to the run container in the $METRICS_PORT environment variable.
+   * If another container in the pod declares the same port, the next free port
+   * is used instead, and its number is recorded in the
+   * e2etest.grpc.io/metrics-port annotation of the pod.
+   */
+  metricsPort?: number;
+
+  /**
+   * Name is a string that distinguishes this client from others in the test.
+   * Explicitly setting a name is recommended when it is helpful to
+   * differentiate between multiple clients. For example, a test may use
+   * clients with different settings.
+   * Most often, this field will not be set. When unset, the operator will
+   * assign a name to the client.
+   */
+  name?: string;
+
+  /**
+   * Pool specifies the name of the set of nodes where this client should be
+   * scheduled. If unset, the controller will choose a pool based on defaults.
+   */
+  pool?: string;
+
+  /**
+   * PprofPort is the port where a Go worker serves the net/http/pprof
+   * handlers, such as the port passed to the --pprof_port flag of the grpc-go
+   * benchmark worker. Its value is available to the run container in the
+   * $PPROF_PORT environment variable. When set, CPU and heap profiles can be
+   * collected from the client during the benchmark.
+   */
+  pprofPort?: number;
+
+  /**
+   * Replicas is the number of identical client pods created from this spec.
+   * When it is greater than one, the name of each pod is suffixed with its
+   * index, so a client named "scale" with 3 replicas results in clients named
+   * "scale-0", "scale-1" and "scale-2". The driver receives the addresses of
+   * all replicas. If unset, a single client is created.
+   */
+  replicas?: number;
+
+  /**
+   * Run describes a list of run containers. The container for the test client
+   * is always the first container on the list.
+   */
+  run: Run[];
+
+  /**
+   * XdsBootstrap selects how a proxyless client of a PSM test receives its xDS
+   * bootstrap configuration. When unset, the bootstrap file is written by the
+   * xds-server container to a volume mounted at /bootstrap in the run
+   * container. It has no effect on clients without an xds-server container, or
+   * with a sidecar container.
+   */
+  xdsBootstrap?: "Volume" | "Env";
+}
+
+/**
+ * Budget limits the memory and ephemeral storage of the run container of the
+ * client. Values set here override the limits of the container.
+ */
+export interface Budget {
+  /**
+   * EphemeralStorage is the maximum amount of local disk that the run
+   * container may use for its writable layer and logs, such as "10Gi".
+   */
+  ephemeralStorage?: number | string;
+
+  /**
+   * Memory is the maximum amount of memory that the run container may use,
+   * such as "4Gi".
+   */
+  memory?: number | string;
+}
+
+/**
+ * Build describes how the cloned code should be built, including any compiler
+ * arguments or flags. This field is only necessary if the output from the
+ * clone container must be pre-processed before running the tests in the run
+ * container.
+ * When build is specified on a test, the operator will use the client's
+ * language to find a container with a compiler for that language. If the
+ * language is unknown to the operator, a user must include a custom docker
+ * image.
+ * Note that it does not usually make sense to include build instructions
+ * without clone instructions. If doing so, the build container must include
+ * its input and write its output into the /src/workspace directory for the run
+ * container to access it.
+ */
+export interface Build {
+  /**
+   * Args provide command line arguments to the command. If a command is not
+   * specified, these arguments will be ignored in favor of the default
+   * arguments for container's entrypoint.
+   */
+  args?: string[];
+
+  /**
+   * Command is the path to the executable that will build the code in the
+   * /src/workspace directory. If unspecified, the entrypoint for the container
+   * is used.
+   */
+  command?: string[];
+
+  /**
+   * Env are environment variables that should be set within the build
+   * container. This is provided for compilers that alter behavior due to
+   * certain environment variables.
+   */
+  env?: Env[];
+
+  /**
+   * Image is the name of the container image that can build code, placing an
+   * executable in the /src/workspace directory.
+   * This field is optional when a Language is specified on the Component. For
+   * example, a developer may specify a "java" server. Then, this image will
+   * default to the most recent gradle image.
+   */
+  image?: string;
+}
+
+/**
+ * EnvVar represents an environment variable present in a Container.
+ */
+export interface Env {
+  /**
+   * Name of the environment variable. Must be a C_IDENTIFIER.
+   */
+  name: string;
+
+  /**
+   * Variable references $(VAR_NAME) are expanded using the previously defined
+   * environment variables in the container and any service environment
+   * variables. If a variable cannot be resolved, the reference in the input
+   * string will be unchanged. Double $$ are reduced to a single $, which
+   * allows for escaping the $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)" will
+   * produce the string literal "$(VAR_NAME)". Escaped references will never be
+   * expanded, regardless of whether the variable exists or not. Defaults to
+   * "".
+   */
+  value?: string;
+
+  /**
+   * Source for the environment variable's value. Cannot be used if value is
+   * not empty.
+   */
+  valueFrom?: ValueFrom;
+}
+
+/**
+ * Source for the environment variable's value. Cannot be used if value is not
+ * empty.
+ */
+export interface ValueFrom {
+  /**
+   * Selects a key of a ConfigMap.
+   */
+  configMapKeyRef?: ConfigMapKeyRef;
+
+  /**
+   * Selects a field of the pod: supports metadata.name, metadata.namespace,
+   * `metadata.labels['<KEY>']`, `metadata.annotations['<KEY>']`,
+   * spec.nodeName, spec.serviceAccountName, status.hostIP, status.podIP,
+   * status.podIPs.
+   */
+  fieldRef?: FieldRef;
+
+  /**
+   * Selects a resource of the container: only resources limits and requests
+   * (limits.cpu, limits.memory, limits.ephemeral-storage, requests.cpu,
+   * requests.memory and requests.ephemeral-storage) are currently supported.
+   */
+  resourceFieldRef?: ResourceFieldRef;
+
+  /**
+   * Selects a key of a secret in the pod's namespace
+   */
+  secretKeyRef?: SecretKeyRef;
+}
+
+/**
+ * Selects a key of a ConfigMap.
+ */
+export interface ConfigMapKeyRef {
+  /**
+   * The key to select.
+   */
+  key: string;
+
+  /**
+   * Name of the referent. More info:
+   * https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+   * TODO: Add other useful fields. apiVersion, kind, uid?
+   */
+  name?: string;
+
+  /**
+   * Specify whether the ConfigMap or its key must be defined
+   */
+  optional?: boolean;
+}
+
+/**
+ * Selects a field of the pod: supports metadata.name, metadata.namespace,
+ * `metadata.labels['<KEY>']`, `metadata.annotations['<KEY>']`, spec.nodeName,
+ * spec.serviceAccountName, status.hostIP, status.podIP, status.podIPs.
+ */
+export interface FieldRef {
+  /**
+   * Version of the schema the FieldPath is written in terms of, defaults to
+   * "v1".
+   */
+  apiVersion?: string;
+
+  /**
+   * Path of the field to select in the specified API version.
+   */
+  fieldPath: string;
+}
+
+/**
+ * Selects a resource of the container: only resources limits and requests
+ * (limits.cpu, limits.memory, limits.ephemeral-storage, requests.cpu,
+ * requests.memory and requests.ephemeral-storage) are currently supported.
+ */
+export interface ResourceFieldRef {
+  /**
+   * Container name: required for volumes, optional for env vars
+   */
+  containerName?: string;
+
+  /**
+   * Specifies the output format of the exposed resources, defaults to "1"
+   */
+  divisor?: number | string;
+
+  /**
+   * Required: resource to select
+   */
+  resource: string;
+}
+
+/**
+ * Selects a key of a secret in the pod's namespace
+ */
+export interface SecretKeyRef {
+  /**
+   * The key of the secret to select from. Must be a valid secret key.
+   */
+  key: string;
+
+  /**
+   * Name of the referent. More info:
+   * https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+   * TODO: Add other useful fields. apiVersion, kind, uid?
+   */
+  name?: string;
+
+  /**
+   * Specify whether the Secret or its key must be defined
+   */
+  optional?: boolean;
+}
+
+/**
+ * Clone specifies the repository and snapshot where the code for the client
+ * can be found. This field should not be set if the code has been prebuilt in
+ * the run image.
+ */
+export interface Clone {
+  /**
+   * GitRef is a branch, tag or commit hash to checkout after a successful
+   * clone. This will be the version of the code in the /src/workspace
+   * directory.
+   */
+  gitRef?: string;
+
+  /**
+   * Image is the name of the container image that can clone code, placing it
+   * in a /src/workspace directory.
+   * This field is optional. When omitted, a container that can clone public
+   * GitHub repos over HTTPs is used.
+   */
+  image?: string;
+
+  /**
+   * Repo is the URL to clone a git repository. With GitHub, this should end in
+   * a `.git` extension.
+   */
+  repo?: string;
+}
+
+/**
+ * DNSConfig specifies DNS parameters for the client pod. These parameters are
+ * merged with the ones generated from the DNS policy of the pod.
+ */
+export interface DnsConfig {
+  /**
+   * A list of DNS name server IP addresses. This will be appended to the base
+   * nameservers generated from DNSPolicy. Duplicated nameservers will be
+   * removed.
+   */
+  nameservers?: string[];
+
+  /**
+   * A list of DNS resolver options. This will be merged with the base options
+   * generated from DNSPolicy. Duplicated entries will be removed. Resolution
+   * options given in Options will override those that appear in the base
+   * DNSPolicy.
+   */
+  options?: Option[];
+
+  /**
+   * A list of DNS search domains for host-name lookup. This will be appended
+   * to the base search paths generated from DNSPolicy. Duplicated search paths
+   * will be removed.
+   */
+  searches?: string[];
+}
+
+/**
+ * PodDNSConfigOption defines DNS resolver options of a pod.
+ */
+export interface Option {
+  /**
+   * Required.
+   */
+  name?: string;
+
+  value?: string;
+}
+
+/**
+ * HostAlias holds the mapping between IP and hostnames that will be injected
+ * as an entry in the pod's hosts file.
+ */
+export interface HostAlias {
+  /**
+   * Hostnames for the above IP address.
+   */
+  hostnames?: string[];
+
+  /**
+   * IP address of the host file entry.
+   */
+  ip?: string;
+}
+
+/**
+ * A single application container that you want to run within a pod.
+ */
+export interface Run {
+  /**
+   * Arguments to the entrypoint. The container image's CMD is used if this is
+   * not provided. Variable references $(VAR_NAME) are expanded using the
+   * container's environment. If a variable cannot be resolved, the reference
+   * in the input string will be unchanged. Double $$ are reduced to a single
+   * $, which allows for escaping the $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)"
+   * will produce the string literal "$(VAR_NAME)". Escaped references will
+   * never be expanded, regardless of whether the variable exists or not.
+   * Cannot be updated. More info:
+   * https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+   */
+  args?: string[];
+
+  /**
+   * Entrypoint array. Not executed within a shell. The container image's
+   * ENTRYPOINT is used if this is not provided. Variable references
+   * $(VAR_NAME) are expanded using the container's environment. If a variable
+   * cannot be resolved, the reference in the input string will be unchanged.
+   * Double $$ are reduced to a single $, which allows for escaping the
+   * $(VAR_NAME) syntax: i.e. "$$(VAR_NAME)" will produce the string literal
+   * "$(VAR_NAME)". Escaped references will never be expanded, regardless of
+   * whether the variable exists or not. Cannot be updated. More info:
+   * https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell
+   */
+  command?: string[];
+
+  /**
+   * List of environment variables to set in the container. Cannot be updated.
+   */
+  env?: Env[];
+
+  /**
+   * List of sources to populate environment variables in the container. The
+   * keys defined within a source must be a C_IDENTIFIER. All invalid keys will
+   * be reported as an event when the container is starting. When a key exists
+   * in multiple sources, the value associated with the last source will take
+   * precedence. Values defined by an Env with a duplicate key will take
+   * precedence. Cannot be updated.
+   */
+  envFrom?: EnvFrom[];
+
+  /**
+   * Container image name. More info:
+   * https://kubernetes.io/docs/concepts/containers/images This field is
+   * optional to allow higher level config management to default or override
+   * container images in workload controllers like Deployments and
+   * StatefulSets.
+   */
+  image?: string;
+
+  /**
+   * Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always
+   * if :latest tag is specified, or IfNotPresent otherwise. Cannot be updated.
+   * More info:
+   * https://kubernetes.io/docs/concepts/containers/images#updating-images
+   */
+  imagePullPolicy?: string;
+
+  /**
+   * Actions that the management system should take in response to container
+   * lifecycle events. Cannot be updated.
+   */
+  lifecycle?: Lifecycle;
+
+  /**
+   * Periodic probe of container liveness. Container will be restarted if the
+   * probe fails. Cannot be updated. More info:
+   * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+   */
+  livenessProbe?: LivenessProbe;
+
+  /**
+   * Name of the container specified as a DNS_LABEL. Each container in a pod
+   * must have a unique name (DNS_LABEL). Cannot be updated.
+   */
+  name: string;
+
+  /**
+   * List of ports to expose from the container. Not specifying a port here
+   * DOES NOT prevent that port from being exposed. Any port which is listening
+   * on the default "0.0.0.0" address inside a container will be accessible
+   * from the network. Modifying this array with strategic merge patch may
+   * corrupt the data. For more information See
+   * https://github.com/kubernetes/kubernetes/issues/108255. Cannot be updated.
+   */
+  ports?: Port[];
+
+  /**
+   * Periodic probe of container service readiness. Container will be removed
+   * from service endpoints if the probe fails. Cannot be updated. More info:
+   * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+   */
+  readinessProbe?: LivenessProbe;
+
+  /**
+   * Compute Resources required by this container. Cannot be updated. More
+   * info:
+   * https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+   */
+  resources?: Resources;
+
+  /**
+   * SecurityContext defines the security options the container should be run
+   * with. If set, the fields of SecurityContext override the equivalent fields
+   * of PodSecurityContext. More info:
+   * https://kubernetes.io/docs/tasks/configure-pod-container/security-context/
+   */
+  securityContext?: SecurityContext;
+
+  /**
+   * StartupProbe indicates that the Pod has successfully initialized. If
+   * specified, no other probes are executed until this completes successfully.
+   * If this probe fails, the Pod will be restarted, just as if the
+   * livenessProbe failed. This can be used to provide different probe
+   * parameters at the beginning of a Pod's lifecycle, when it might take a
+   * long time to load data or warm a cache, than during steady-state
+   * operation. This cannot be updated. More info:
+   * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+   */
+  startupProbe?: LivenessProbe;
+
+  /**
+   * Whether this container should allocate a buffer for stdin in the container
+   * runtime. If this is not set, reads from stdin in the container will always
+   * result in EOF. Default is false.
+   */
+  stdin?: boolean;
+
+  /**
+   * Whether the container runtime should close the stdin channel after it has
+   * been opened by a single attach. When stdin is true the stdin stream will
+   * remain open across multiple attach sessions. If stdinOnce is set to true,
+   * stdin is opened on container start, is empty until the first client
+   * attaches to stdin, and then remains open and accepts data until the client
+   * disconnects, at which time stdin is closed and remains closed until the
+   * container is restarted. If this flag is false, a container processes that
+   * reads from stdin will never receive an EOF. Default is false
+   */
+  stdinOnce?: boolean;
+
+  /**
+   * Optional: Path at which the file to which the container's termination
+   * message will be written is mounted into the container's filesystem.
+   * Message written is intended to be brief final status, such as an assertion
+   * failure message. Will be truncated by the node if greater than 4096 bytes.
+   * The total message length across all containers will be limited to 12kb.
+   * Defaults to /dev/termination-log. Cannot be updated.
+   */
+  terminationMessagePath?: string;
+
+  /**
+   * Indicate how the termination message should be populated. File will use
+   * the contents of terminationMessagePath to populate the container status
+   * message on both success and failure. FallbackToLogsOnError will use the
+   * last chunk of container log output if the termination message file is
+   * empty and the container exited with an error. The log output is limited to
+   * 2048 bytes or 80 lines, whichever is smaller. Defaults to File. Cannot be
+   * updated.
+   */
+  terminationMessagePolicy?: string;
+
+  /**
+   * Whether this container should allocate a TTY for itself, also requires
+   * 'stdin' to be true. Default is false.
+   */
+  tty?: boolean;
+
+  /**
+   * volumeDevices is the list of block devices to be used by the container.
+   */
+  volumeDevices?: VolumeDevice[];
+
+  /**
+   * Pod volumes to mount into the container's filesystem. Cannot be updated.
+   */
+  volumeMounts?: VolumeMount[];
+
+  /**
+   * Container's working directory. If not specified, the container runtime's
+   * default will be used, which might be configured in the container image.
+   * Cannot be updated.
+   */
+  workingDir?: string;
+}
+
+/**
+ * EnvFromSource represents the source of a set of ConfigMaps
+ */
+export interface EnvFrom {
+  /**
+   * The ConfigMap to select from
+   */
+  configMapRef?: ConfigMapRef;
+
+  /**
+   * An optional identifier to prepend to each key in the ConfigMap. Must be a
+   * C_IDENTIFIER.
+   */
+  prefix?: string;
+
+  /**
+   * The Secret to select from
+   */
+  secretRef?: SecretRef;
+}
+
+/**
+ * The ConfigMap to select from
+ */
+export interface ConfigMapRef {
+  /**
+   * Name of the referent. More info:
+   * https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+   * TODO: Add other useful fields. apiVersion, kind, uid?
+   */
+  name?: string;
+
+  /**
+   * Specify whether the ConfigMap must be defined
+   */
+  optional?: boolean;
+}
+
+/**
+ * The Secret to select from
+ */
+export interface SecretRef {
+  /**
+   * Name of the referent. More info:
+   * https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names
+   * TODO: Add other useful fields. apiVersion, kind, uid?
+   */
+  name?: string;
+
+  /**
+   * Specify whether the Secret must be defined
+   */
+  optional?: boolean;
+}
+
+/**
+ * Actions that the management system should take in response to container
+ * lifecycle events. Cannot be updated.
+ */
+export interface Lifecycle {
+  /**
+   * PostStart is called immediately after a container is created. If the
+   * handler fails, the container is terminated and restarted according to its
+   * restart policy. Other management of the container blocks until the hook
+   * completes. More info:
+   * https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+   */
+  postStart?: PostStart;
+
+  /**
+   * PreStop is called immediately before a container is terminated due to an
+   * API request or management event such as liveness/startup probe failure,
+   * preemption, resource contention, etc. The handler is not called if the
+   * container crashes or exits. The Pod's termination grace period countdown
+   * begins before the PreStop hook is executed. Regardless of the outcome of
+   * the handler, the container will eventually terminate within the Pod's
+   * termination grace period (unless delayed by finalizers). Other management
+   * of the container blocks until the hook completes or until the termination
+   * grace period is reached. More info:
+   * https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+   */
+  preStop?: PostStart;
+}
+
+/**
+ * PostStart is called immediately after a container is created. If the handler
+ * fails, the container is terminated and restarted according to its restart
+ * policy. Other management of the container blocks until the hook completes.
+ * More info:
+ * https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/#container-hooks
+ */
+export interface PostStart {
+  /**
+   * Exec specifies the action to take.
+   */
+  exec?: Exec;
+
+  /**
+   * HTTPGet specifies the http request to perform.
+   */
+  httpGet?: HttpGet;
+
+  /**
+   * Deprecated. TCPSocket is NOT supported as a LifecycleHandler and kept for
+   * the backward compatibility. There are no validation of this field and
+   * lifecycle hooks will fail in runtime when tcp handler is specified.
+   */
+  tcpSocket?: TcpSocket;
+}
+
+/**
+ * Exec specifies the action to take.
+ */
+export interface Exec {
+  /**
+   * Command is the command line to execute inside the container, the working
+   * directory for the command is root ('/') in the container's filesystem. The
+   * command is simply exec'd, it is not run inside a shell, so traditional
+   * shell instructions ('|', etc) won't work. To use a shell, you need to
+   * explicitly call out to that shell. Exit status of 0 is treated as
+   * live/healthy and non-zero is unhealthy.
+   */
+  command?: string[];
+}
+
+/**
+ * HTTPGet specifies the http request to perform.
+ */
+export interface HttpGet {
+  /**
+   * Host name to connect to, defaults to the pod IP. You probably want to set
+   * "Host" in httpHeaders instead.
+   */
+  host?: string;
+
+  /**
+   * Custom headers to set in the request. HTTP allows repeated headers.
+   */
+  httpHeaders?: HttpHeader[];
+
+  /**
+   * Path to access on the HTTP server.
+   */
+  path?: string;
+
+  /**
+   * Name or number of the port to access on the container. Number must be in
+   * the range 1 to 65535. Name must be an IANA_SVC_NAME.
+   */
+  port: number | string;
+
+  /**
+   * Scheme to use for connecting to the host. Defaults to HTTP.
+   */
+  scheme?: string;
+}
+
+/**
+ * HTTPHeader describes a custom header to be used in HTTP probes
+ */
+export interface HttpHeader {
+  /**
+   * The header field name. This will be canonicalized upon output, so
+   * case-variant names will be understood as the same header.
+   */
+  name: string;
+
+  /**
+   * The header field value
+   */
+  value: string;
+}
+
+/**
+ * Deprecated. TCPSocket is NOT supported as a LifecycleHandler and kept for
+ * the backward compatibility. There are no validation of this field and
+ * lifecycle hooks will fail in runtime when tcp handler is specified.
+ */
+export interface TcpSocket {
+  /**
+   * Optional: Host name to connect to, defaults to the pod IP.
+   */
+  host?: string;
+
+  /**
+   * Number or name of the port to access on the container. Number must be in
+   * the range 1 to 65535. Name must be an IANA_SVC_NAME.
+   */
+  port: number | string;
+}
+
+/**
+ * Periodic probe of container liveness. Container will be restarted if the
+ * probe fails. Cannot be updated. More info:
+ * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+ */
+export interface LivenessProbe {
+  /**
+   * Exec specifies the action to take.
+   */
+  exec?: Exec;
+
+  /**
+   * Minimum consecutive failures for the probe to be considered failed after
+   * having succeeded. Defaults to 3. Minimum value is 1.
+   */
+  failureThreshold?: number;
+
+  /**
+   * GRPC specifies an action involving a GRPC port. This is a beta field and
+   * requires enabling GRPCContainerProbe feature gate.
+   */
+  grpc?: Grpc;
+
+  /**
+   * HTTPGet specifies the http request to perform.
+   */
+  httpGet?: HttpGet;
+
+  /**
+   * Number of seconds after the container has started before liveness probes
+   * are initiated. More info:
+   * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+   */
+  initialDelaySeconds?: number;
+
+  /**
+   * How often (in seconds) to perform the probe. Default to 10 seconds.
+   * Minimum value is 1.
+   */
+  periodSeconds?: number;
+
+  /**
+   * Minimum consecutive successes for the probe to be considered successful
+   * after having failed. Defaults to 1. Must be 1 for liveness and startup.
+   * Minimum value is 1.
+   */
+  successThreshold?: number;
+
+  /**
+   * TCPSocket specifies an action involving a TCP port.
+   */
+  tcpSocket?: TcpSocket;
+
+  /**
+   * Optional duration in seconds the pod needs to terminate gracefully upon
+   * probe failure. The grace period is the duration in seconds after the
+   * processes running in the pod are sent a termination signal and the time
+   * when the processes are forcibly halted with a kill signal. Set this value
+   * longer than the expected cleanup time for your process. If this value is
+   * nil, the pod's terminationGracePeriodSeconds will be used. Otherwise, this
+   * value overrides the value provided by the pod spec. Value must be
+   * non-negative integer. The value zero indicates stop immediately via the
+   * kill signal (no opportunity to shut down). This is a beta field and
+   * requires enabling ProbeTerminationGracePeriod feature gate. Minimum value
+   * is 1. spec.terminationGracePeriodSeconds is used if unset.
+   */
+  terminationGracePeriodSeconds?: number;
+
+  /**
+   * Number of seconds after which the probe times out. Defaults to 1 second.
+   * Minimum value is 1. More info:
+   * https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+   */
+  timeoutSeconds?: number;
+}
+
+/**
+ * GRPC specifies an action involving a GRPC port. This is a beta field and
+ * requires enabling GRPCContainerProbe feature gate.
+ */
+export interface Grpc {
+  /**
+   * Port number of the gRPC service. Number must be in the range 1 to 65535.
+   */
+  port: number;
+
+  /**
+   * Service is the name of the service to place in the gRPC HealthCheckRequest
+   * (see https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+   * If this is not specified, the default behavior is defined by gRPC.
+   */
+  service?: string;
+}
+
+/**
+ * ContainerPort represents a network port in a single container.
+ */
+export interface Port {
+  /**
+   * Number of port to expose on the pod's IP address. This must be a valid
+   * port number, 0 < x < 65536.
+   */
+  containerPort: number;
+
+  /**
+   * What host IP to bind the external port to.
+   */
+  hostIP?: string;
+
+  /**
+   * Number of port to expose on the host. If specified, this must be a valid
+   * port number, 0 < x < 65536. If HostNetwork is specified, this must match
+   * ContainerPort. Most containers do not need this.
+   */
+  hostPort?: number;
+
+  /**
+   * If specified, this must be an IANA_SVC_NAME and unique within the pod.
+   * Each named port in a pod must have a unique name. Name for the port that
+   * can be referred to by services.
+   */
+  name?: string;
+
+  /**
+   * Protocol for port. Must be UDP, TCP, or SCTP. Defaults to "TCP".
+   */
+  protocol?: string;
+}
+
+/**
+ * Compute Resources required by this container. Cannot be updated. More info:
+ * https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+ */
+export interface Resources {
+  /**
+   * Limits describes the maximum amount of compute resources allowed. More
+   * info:
+   * https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+   */
+  limits?: { [key: string]: number | string };
+
+  /**
+   * Requests describes the minimum amount of compute resources required. If
+   * Requests is omitted for a container, it defaults to Limits if that is
+   * explicitly specified, otherwise to an implementation-defined value. More
+   * info:
+   * https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+   */
+  requests?: { [key: string]: number | string };
+}
+
+/**
+ * SecurityContext defines the security options the container should be run
+ * with. If set, the fields of SecurityContext override the equivalent fields
+ * of PodSecurityContext. More info:
+ * https://kubernetes.io/docs/tasks/configure-pod-container/security-context/
+ */
+export interface SecurityContext {
+  /**
+   * AllowPrivilegeEscalation controls whether a process can gain more
+   * privileges than its parent process. This bool directly controls if the
+   * no_new_privs flag will be set on the container process.
+   * AllowPrivilegeEscalation is true always when the container is: 1) run as
+   * Privileged 2) has CAP_SYS_ADMIN Note that this field cannot be set when
+   * spec.os.name is windows.
+   */
+  allowPrivilegeEscalation?: boolean;
+
+  /**
+   * The capabilities to add/drop when running containers. Defaults to the
+   * default set of capabilities granted by the container runtime. Note that
+   * this field cannot be set when spec.os.name is windows.
+   */
+  capabilities?: Capabilities;
+
+  /**
+   * Run container in privileged mode. Processes in privileged containers are
+   * essentially equivalent to root on the host. Defaults to false. Note that
+   * this field cannot be set when spec.os.name is windows.
+   */
+  privileged?: boolean;
+
+  /**
+   * procMount denotes the type of proc mount to use for the containers. The
+   * default is DefaultProcMount which uses the container runtime defaults for
+   * readonly paths and masked paths. This requires the ProcMountType feature
+   * flag to be enabled. Note that this field cannot be set when spec.os.name
+   * is windows.
+   */
+  procMount?: string;
+
+  /**
+   * Whether this container has a read-only root filesystem. Default is false.
+   * Note that this field cannot be set when spec.os.name is windows.
+   */
+  readOnlyRootFilesystem?: boolean;
+
+  /**
+   * The GID to run the entrypoint of the container process. Uses runtime
+   * default if unset. May also be set in PodSecurityContext. If set in both
+   * SecurityContext and PodSecurityContext, the value specified in
+   * SecurityContext takes precedence. Note that this field cannot be set when
+   * spec.os.name is windows.
+   */
+  runAsGroup?: number;
+
+  /**
+   * Indicates that the container must run as a non-root user. If true, the
+   * Kubelet will validate the image at runtime to ensure that it does not run
+   * as UID 0 (root) and fail to start the container if it does. If unset or
+   * false, no such validation will be performed. May also be set in
+   * PodSecurityContext. If set in both SecurityContext and PodSecurityContext,
+   * the value specified in SecurityContext takes precedence.
+   */
+  runAsNonRoot?: boolean;
+
+  /**
+   * The UID to run the entrypoint of the container process. Defaults to user
+   * specified in image metadata if unspecified. May also be set in
+   * PodSecurityContext. If set in both SecurityContext and PodSecurityContext,
+   * the value specified in SecurityContext takes precedence. Note that this
+   * field cannot be set when spec.os.name is windows.
+   */
+  runAsUser?: number;
+
+  /**
+   * The SELinux context to be applied to the container. If unspecified, the
+   * container runtime will allocate a random SELinux context for each
+   * container. May also be set in PodSecurityContext. If set in both
+   * SecurityContext and PodSecurityContext, the value specified in
+   * SecurityContext takes precedence. Note that this field cannot be set when
+   * spec.os.name is windows.
+   */
+  seLinuxOptions?: SeLinuxOptions;
+
+  /**
+   * The seccomp options to use by this container. If seccomp options are
+   * provided at both the pod & container level, the container options override
+   * the pod options. Note that this field cannot be set when spec.os.name is
+   * windows.
+   */
+  seccompProfile?: SeccompProfile;
+
+  /**
+   * The Windows specific settings applied to all containers. If unspecified,
+   * the options from the PodSecurityContext will be used. If set in both
+   * SecurityContext and PodSecurityContext, the value specified in
+   * SecurityContext takes precedence. Note that this field cannot be set when
+   * spec.os.name is linux.
+   */
+  windowsOptions?: WindowsOptions;
+}
+
+/**
+ * The capabilities to add/drop when running containers. Defaults to the
+ * default set of capabilities granted by the container runtime. Note that this
+ * field cannot be set when spec.os.name is windows.
+ */
+export interface Capabilities {
+  /**
+   * Added capabilities
+   */
+  add?: string[];
+
+  /**
+   * Removed capabilities
+   */
+  drop?: string[];
+}
+
+/**
+ * The SELinux context to be applied to the container. If unspecified, the
+ * container runtime will allocate a random SELinux context for each container.
+ * May also be set in PodSecurityContext. If set in both SecurityContext and
+ * PodSecurityContext, the value specified in SecurityContext takes precedence.
+ * Note that this field cannot be set when spec.os.name is windows.
+ */
+export interface SeLinuxOptions {
+  /**
+   * Level is SELinux level label that applies to the container.
+   */
+  level?: string;
+
+  /**
+   * Role is a SELinux role label that applies to the container.
+   */
+  role?: string;
+
+  /**
+   * Type is a SELinux type label that applies to the container.
+   */
+  type?: string;
+
+  /**
+   * User is a SELinux user label that applies to the container.
+   */
+  user?: string;
+}
+
+/**
+ * The seccomp options to use by this container. If seccomp options are
+ * provided at both the pod & container level, the container options override
+ * the pod options. Note that this field cannot be set when spec.os.name is
+ * windows.
+ */
+export interface SeccompProfile {
+  /**
+   * localhostProfile indicates a profile defined in a file on the node should
+   * be used. The profile must be preconfigured on the node to work. Must be a
+   * descending path, relative to the kubelet's configured seccomp profile
+   * location. Must only be set if type is "Localhost".
+   */
+  localhostProfile?: string;
+
+  /**
+   * type indicates which kind of seccomp profile will be applied. Valid
+   * options are:
+   * Localhost - a profile defined in a file on the node should be used.
+   * RuntimeDefault - the container runtime default profile should be used.
+   * Unconfined - no profile should be applied.
+   */
+  type: string;
+}
+
+/**
+ * The Windows specific settings applied to all containers. If unspecified, the
+ * options from the PodSecurityContext will be used. If set in both
+ * SecurityContext and PodSecurityContext, the value specified in
+ * SecurityContext takes precedence. Note that this field cannot be set when
+ * spec.os.name is linux.
+ */
+export interface WindowsOptions {
+  /**
+   * GMSACredentialSpec is where the GMSA admission webhook
+   * (https://github.com/kubernetes-sigs/windows-gmsa) inlines the contents of
+   * the GMSA credential spec named by the GMSACredentialSpecName field.
+   */
+  gmsaCredentialSpec?: string;
+
+  /**
+   * GMSACredentialSpecName is the name of the GMSA credential spec to use.
+   */
+  gmsaCredentialSpecName?: string;
+
+  /**
+   * HostProcess determines if a container should be run as a 'Host Process'
+   * container. This field is alpha-level and will only be honored by
+   * components that enable the WindowsHostProcessContainers feature flag.
+   * Setting this field without the feature flag will result in errors when
+   * validating the Pod. All of a Pod's containers must have the same effective
+   * HostProcess value (it is not allowed to have a mix of HostProcess
+   * containers and non-HostProcess containers). In addition, if HostProcess is
+   * true then HostNetwork must also be set to true.
+   */
+  hostProcess?: boolean;
+
+  /**
+   * The UserName in Windows to run the entrypoint of the container process.
+   * Defaults to the user specified in image metadata if unspecified. May also
+   * be set in PodSecurityContext. If set in both SecurityContext and
+   * PodSecurityContext, the value specified in SecurityContext takes
+   * precedence.
+   */
+  runAsUserName?: string;
+}
+
+/**
+ * volumeDevice describes a mapping of a raw block device within a container.
+ */
+export interface VolumeDevice {
+  /**
+   * devicePath is the path inside of the container that the device will be
+   * mapped to.
+   */
+  devicePath: string;
+
+  /**
+   * name must match the name of a persistentVolumeClaim in the pod
+   */
+  name: string;
+}
+
+/**
+ * VolumeMount describes a mounting of a Volume within a container.
+ */
+export interface VolumeMount {
+  /**
+   * Path within the container at which the volume should be mounted. Must not
+   * contain ':'.
+   */
+  mountPath: string;
+
+  /**
+   * mountPropagation determines how mounts are propagated from the host to
+   * container and the other way around. When not set, MountPropagationNone is
+   * used. This field is beta in 1.10.
+   */
+  mountPropagation?: string;
+
+  /**
+   * This must match the Name of a Volume.
+   */
+  name: string;
+
+  /**
+   * Mounted read-only if true, read-write otherwise (false or unspecified).
+   * Defaults to false.
+   */
+  readOnly?: boolean;
+
+  /**
+   * Path within the volume from which the container's volume should be
+   * mounted. Defaults to "" (volume's root).
+   */
+  subPath?: string;
+
+  /**
+   * Expanded path within the volume from which the container's volume should
+   * be mounted. Behaves similarly to SubPath but environment variable
+   * references $(VAR_NAME) are expanded using the container's environment.
+   * Defaults to "" (volume's root). SubPathExpr and SubPath are mutually
+   * exclusive.
+   */
+  subPathExpr?: string;
+}
+
+/**
+ * Driver is the component that orchestrates the test. It may be unspecified,
+ * allowing the system to choose the appropriate driver. Tests with generators
+ * do not receive a default driver, since the generators send load without
+ * instructions from a driver.
+ */
+export interface Driver {
+  /**
+   * Budget limits the memory and ephemeral storage of the run container of the
+   * driver. Values set here override the limits of the container.
+   */
+  budget?: Budget;
+
+  /**
+   * Build describes how the cloned code should be built, including any
+   * compiler arguments or flags. This field is only necessary if the output
+   * from the clone container must be pre-processed before running the tests in
+   * the run container.
+   * When build is specified on a test, the operator will use the driver's
+   * language to find a container with a compiler for that language. If the
+   * language is unknown to the operator, a user must include a custom docker
+   * image.
+   * Note that it does not usually make sense to include build instructions
+   * without clone instructions. If doing so, the build container must include
+   * its input and write its output into the /src/workspace directory for the
+   * run container to access it.
+   */
+  build?: Build;
+
+  /**
+   * Clone specifies the repository and snapshot where the code for the driver
+   * can be found. This is used to test alternative implementations for the
+   * driver. Most often, this will not be set. When unset, the operator will
+   * use a default driver that is prebuilt.
+   */
+  clone?: Clone;
+
+  /**
+   * DNSConfig specifies DNS parameters for the driver pod. These parameters
+   * are merged with the ones generated from the DNS policy of the pod.
+   */
+  dnsConfig?: DnsConfig;
+
+  /**
+   * HostAliases are entries that are added to the /etc/hosts file of the
+   * driver pod. They allow the driver to address hosts by stable names, for
+   * example to match the subject alternative names of a TLS certificate.
+   */
+  hostAliases?: HostAlias[];
+
+  /**
+   * Language is the code that identifies the programming language used by the
+   * driver. For example, "cxx" may represent C++.
+   * Specifying a language is required. If the language is unknown to the
+   * operator, a user must manually set a run image. If the user intends for
+   * the operator to clone and build code, it must also manually set a build
+   * image.
+   */
+  language: string;
+
+  /**
+   * Name is a string that uniquely names this driver. Since load tests only
+   * support one driver, it is not recommended to set this field. If no name is
+   * explicitly provided, the operator will assign one.
+   */
+  name?: string;
+
+  /**
+   * Pool specifies the name of the set of nodes where this driver should be
+   * scheduled. If unset, the controller will choose a pool based on defaults.
+   */
+  pool?: string;
+
+  /**
+   * Run describes a list of run containers. The container for the test driver
+   * is always the first container on the list.
+   */
+  run: Run[];
+}
+
+/**
+ * Generator defines a component that sends load to a gRPC service on its own,
+ * without taking instructions from the driver. For example, a generator may
+ * run ghz against a server in the test or a service that is already deployed.
+ */
+export interface Generator {
+  /**
+   * Adapter is the format of the report that the generator writes to the
+   * termination message of its first run container. The report is parsed into
+   * the result summary of the test. When omitted, the report is not parsed,
+   * and only the exit code of the generator is considered.
+   */
+  adapter?: "ghz";
+
+  /**
+   * Budget limits the memory and ephemeral storage of the run container of the
+   * generator. Values set here override the limits of the container.
+   */
+  budget?: Budget;
+
+  /**
+   * DNSConfig specifies DNS parameters for the generator pod. These parameters
+   * are merged with the ones generated from the DNS policy of the pod.
+   */
+  dnsConfig?: DnsConfig;
+
+  /**
+   * HostAliases are entries that are added to the /etc/hosts file of the
+   * generator pod. They allow the generator to address the service under test
+   * by a stable name.
+   */
+  hostAliases?: HostAlias[];
+
+  /**
+   * Name is a string that distinguishes this generator from others in the
+   * test. When unset, the operator will assign a name to the generator.
+   */
+  name?: string;
+
+  /**
+   * Pool specifies the name of the set of nodes where this generator should be
+   * scheduled. If unset, the generator is scheduled in the default pool for
+   * clients.
+   */
+  pool?: string;
+
+  /**
+   * Run describes a list of run containers. The container for the generator is
+   * always the first container on the list, and it must set an image.
+   */
+  run: Run[];
+}
+
+/**
+ * Interop turns the test into a run of the gRPC interop client suite. The
+ * driver runs the interop client once for each test case against a server, and
+ * the test fails if the client fails any of them. Interop tests do not need
+ * clients or scenarios.
+ */
+export interface Interop {
+  /**
+   * Server is the name of the server that the client connects to. If unset,
+   * the client connects to the first server of the test.
+   */
+  server?: string;
+
+  /**
+   * TestCases are the names of the interop test cases that the client runs,
+   * such as "empty_unary" or "large_unary". The names are passed to the
+   * --test_case flag of the client.
+   */
+  testCases: string[];
+
+  /**
+   * UseTLS enables TLS on the connection between the client and the server.
+   * The server must be started with TLS enabled, too.
+   */
+  useTLS?: boolean;
+}
+
+/**
+ * LoadProfile sets the load that the clients offer in each scenario, replacing
+ * the load_params of the client_config in ScenariosJSON. A profile with
+ * several steps repeats each scenario once per step, so a range of loads can
+ * be measured without editing the scenarios. The timeout must be long enough
+ * to cover every step.
+ */
+export interface LoadProfile {
+  /**
+   * OfferedLoad is the number of queries per second that each client offers
+   * with a Poisson load.
+   */
+  offeredLoad?: number;
+
+  /**
+   * Ramp offers a Poisson load that increases linearly between two values.
+   * Each scenario is run once for each step of the ramp.
+   */
+  ramp?: Ramp;
+
+  /**
+   * Steps lists the loads, in queries per second per client, that are offered
+   * in turn with a Poisson load. Each scenario is run once for each step.
+   */
+  steps?: number[];
+
+  /**
+   * Type is the type of the load.
+   */
+  type: "ClosedLoop" | "Poisson";
+}
+
+/**
+ * Ramp offers a Poisson load that increases linearly between two values. Each
+ * scenario is run once for each step of the ramp.
+ */
+export interface Ramp {
+  /**
+   * From is the load of the first step, in queries per second per client.
+   */
+  from: number;
+
+  /**
+   * Increment is the difference between the loads of consecutive steps.
+   */
+  increment: number;
+
+  /**
+   * To is the load of the last step, in queries per second per client. The
+   * last step is omitted if it is not reached by whole increments.
+   */
+  to: number;
+}
+
+/**
+ * NetworkProfile emulates a wide area network between the workers of the test.
+ * When set, the traffic that leaves each client and server pod is shaped with
+ * netem, so a round trip between a client and a server experiences twice the
+ * configured latency. When omitted, the traffic is not shaped.
+ */
+export interface NetworkProfile {
+  /**
+   * JitterMs is the random variation, in milliseconds, of the delay added to
+   * each packet. It has no effect unless LatencyMs is also set.
+   */
+  jitterMs?: number;
+
+  /**
+   * LatencyMs is the delay, in milliseconds, added to each packet.
+   */
+  latencyMs?: number;
+
+  /**
+   * LossPct is the percentage of packets that are dropped, such as "0.1".
+   */
+  lossPct?: string;
+
+  /**
+   * Rate limits the bandwidth of the network interface. It uses the units of
+   * tc, such as "100mbit" or "1gbit".
+   */
+  rate?: string;
+}
+
+/**
+ * Results configures where the results of the test should be stored. When
+ * omitted, the results will only be stored in Kubernetes for a limited time.
+ */
+export interface Results {
+  /**
+   * BigQueryTable names a dataset where the results of the test should be
+   * stored. If omitted, no results are saved to BigQuery.
+   * Deprecated: Set Destination instead, so the table is declared once in the
+   * defaults. Tests that only set a table are linked to the destination with
+   * the same table and GCSPrefix, if there is one.
+   */
+  bigQueryTable?: string;
+
+  /**
+   * Destination names one of the result destinations declared in the defaults
+   * of the controller, such as the BigQuery table of an environment. The
+   * controller fills BigQueryTable and GCSPrefix from the destination, unless
+   * they are set on the test. The test errors if no destination has this name.
+   */
+  destination?: string;
+
+  /**
+   * GCSPrefix is a Cloud Storage URI, such as gs://bucket/path, under which
+   * the raw JSON output of the driver should be stored. The driver uploads the
+   * output to an object named after the namespace, name and UID of the test,
+   * and the URI of the object is recorded in the status. The service account
+   * of the driver pod must be allowed to create objects in the bucket, for
+   * example through workload identity. If omitted, the raw output is not
+   * stored.
+   */
+  gcsPrefix?: string;
+}
+
+/**
+ * Server defines a component that receives traffic from a set of client
+ * components.
+ */
+export interface Server {
+  /**
+   * Budget limits the memory and ephemeral storage of the run container of the
+   * server. Values set here override the limits of the container.
+   */
+  budget?: Budget;
+
+  /**
+   * Build describes how the cloned code should be built, including any
+   * compiler arguments or flags. This field is only necessary if the output
+   * from the clone container must be pre-processed before running the tests in
+   * the run container.
+   * When build is specified on a test, the operator will use the server's
+   * language to find a container with a compiler for that language. If the
+   * language is unknown to the operator, a user must include a custom docker
+   * image.
+   * Note that it does not usually make sense to include build instructions
+   * without clone instructions. If doing so, the build container must include
+   * its input and write its output into the /src/workspace directory for the
+   * run container to access it.
+   */
+  build?: Build;
+
+  /**
+   * Clone specifies the repository and snapshot where the code for the server
+   * can be found. This field should not be set if the code has been prebuilt
+   * in the run image.
+   */
+  clone?: Clone;
+
+  /**
+   * DNSConfig specifies DNS parameters for the server pod. These parameters
+   * are merged with the ones generated from the DNS policy of the pod.
+   */
+  dnsConfig?: DnsConfig;
+
+  /**
+   * HostAliases are entries that are added to the /etc/hosts file of the
+   * server pod. They allow the server to address hosts by stable names, for
+   * example to match the subject alternative names of a TLS certificate.
+   */
+  hostAliases?: HostAlias[];
+
+  /**
+   * Language is the code that identifies the programming language used by the
+   * server. For example, "java" may represent Java.
+   * Specifying a language is required. If the language is unknown to the
+   * operator, a user must manually set a run image. If the user intends for
+   * the operator to clone and build code, it must also manually set a build
+   * image.
+   */
+  language: string;
+
+  /**
+   * MetricsPort is the port where the server serves metrics. Its value is
+   * available to the run container in the $METRICS_PORT environment variable.
+   * If another container in the pod declares the same port, the next free port
+   * is used instead, and its number is recorded in the
+   * e2etest.grpc.io/metrics-port annotation of the pod.
+   */
+  metricsPort?: number;
+
+  /**
+   * Name is a string that distinguishes this server from others in the test.
+   * Since tests are currently limited to one server, setting this field is not
+   * recommended. set this field. If no name is explicitly provided, the
+   * operator will assign one.
+   */
+  name?: string;
+
+  /**
+   * Pool specifies the name of the set of nodes where this server should be
+   * scheduled. If unset, the controller will choose a pool based on defaults.
+   */
+  pool?: string;
+
+  /**
+   * PprofPort is the port where a Go worker serves the net/http/pprof
+   * handlers, such as the port passed to the --pprof_port flag of the grpc-go
+   * benchmark worker. Its value is available to the run container in the
+   * $PPROF_PORT environment variable. When set, CPU and heap profiles can be
+   * collected from the server during the benchmark.
+   */
+  pprofPort?: number;
+
+  /**
+   * Run describes a list of run containers. The container for the test server
+   * is always the first container on the list.
+   */
+  run: Run[];
+}
+
+/**
+ * LoadTestStatus defines the observed state of LoadTest
+ */
+export interface LoadTestStatus {
+  /**
+   * Checkpoints record the outcome of the completed iterations of a soak test,
+   * in the order they completed. Only the last 100 checkpoints are kept, so
+   * the status of a long soak test stays small.
+   */
+  checkpoints?: Checkpoint[];
+
+  /**
+   * Conditions provide additional observations of the load test, such as the
+   * SchedulingSLOExceeded condition.
+   */
+  conditions?: Condition[];
+
+  /**
+   * Cost is an approximate cost of the nodes used by the test. It is set when
+   * the test terminates, if machine prices are configured in the defaults of
+   * the controller.
+   */
+  cost?: Cost;
+
+  /**
+   * InteropCases are the results of the test cases of an interop test, as
+   * reported by the interop client when it terminates.
+   */
+  interopCases?: InteropCase[];
+
+  /**
+   * Message is a human legible string that describes the current state.
+   */
+  message?: string;
+
+  /**
+   * Reason is a camel-case string that indicates the reasoning behind the
+   * current state.
+   */
+  reason?: string;
+
+  /**
+   * RerunRequest is the value of the rerun annotation that started the current
+   * run. It is empty for the first run of the test.
+   */
+  rerunRequest?: string;
+
+  /**
+   * ResultsURI is the Cloud Storage URI of the raw JSON output of the driver.
+   * It is set when the driver succeeds and the test sets a GCSPrefix in its
+   * results.
+   */
+  resultsURI?: string;
+
+  /**
+   * Run is the zero-based index of the current run of the test. It is
+   * incremented each time the test is rerun with the rerun annotation.
+   */
+  run?: number;
+
+  /**
+   * Runs record the outcome of each previous run of a test that was rerun, in
+   * the order they ran. Only the last 20 runs are kept, so the status of a
+   * test that is rerun often stays small.
+   */
+  runs?: LoadTestStatusRun[];
+
+  /**
+   * ScheduledTime is the time when all pods of the current run of the load
+   * test were first running.
+   */
+  scheduledTime?: string;
+
+  /**
+   * SoakIteration is the zero-based index of the current iteration of a soak
+   * test. It is omitted for tests that are not soak tests.
+   */
+  soakIteration?: number;
+
+  /**
+   * StartTime is the time when the controller first reconciled the load test.
+   * It is maintained in a best-attempt effort; meaning, it is not guaranteed
+   * to be correct.
+   */
+  startTime?: string;
+
+  /**
+   * State identifies the current state of the load test. It is important to
+   * note that this state is level-based. This means its transition is
+   * non-deterministic.
+   */
+  state: string;
+
+  /**
+   * StopTime is the time when the controller last entered the Succeeded,
+   * Failed or Errored states.
+   */
+  stopTime?: string;
+
+  /**
+   * Summary contains key numbers from the results of the test, as reported by
+   * the driver when it succeeds.
+   */
+  summary?: Summary;
+
+  /**
+   * UnreachableWorkers lists the workers whose driver port could not be
+   * reached from the driver pod before the benchmark started, as reported by
+   * the ready init container of the driver.
+   */
+  unreachableWorkers?: UnreachableWorker[];
+}
+
+/**
+ * SoakCheckpoint records the outcome of a single iteration of a soak test.
+ */
+export interface Checkpoint {
+  /**
+   * Iteration is the zero-based index of the iteration.
+   */
+  iteration: number;
+
+  /**
+   * Message is a human legible string that describes the outcome of the
+   * iteration.
+   */
+  message?: string;
+
+  /**
+   * State is the state of the load test when the iteration completed.
+   */
+  state: string;
+
+  /**
+   * Time is when the controller observed that the iteration completed.
+   */
+  time: string;
+}
+
+/**
+ * Condition contains details for one aspect of the current state of this API
+ * Resource.
+ */
+export interface Condition {
+  /**
+   * lastTransitionTime is the last time the condition transitioned from one
+   * status to another. This should be when the underlying condition changed.
+   * If that is not known, then using the time when the API field changed is
+   * acceptable.
+   */
+  lastTransitionTime: string;
+
+  /**
+   * message is a human readable message indicating details about the
+   * transition. This may be an empty string.
+   */
+  message: string;
+
+  /**
+   * observedGeneration represents the .metadata.generation that the condition
+   * was set based upon. For instance, if .metadata.generation is currently 12,
+   * but the .status.conditions[x].observedGeneration is 9, the condition is
+   * out of date with respect to the current state of the instance.
+   */
+  observedGeneration?: number;
+
+  /**
+   * reason contains a programmatic identifier indicating the reason for the
+   * condition's last transition. Producers of specific condition types may
+   * define expected values and meanings for this field, and whether the values
+   * are considered a guaranteed API. The value should be a CamelCase string.
+   * This field may not be empty.
+   */
+  reason: string;
+
+  /**
+   * status of the condition, one of True, False, Unknown.
+   */
+  status: "True" | "False" | "Unknown";
+
+  /**
+   * type of condition in CamelCase or in foo.example.com/CamelCase.
+   */
+  type: string;
+}
+
+/**
+ * Cost is an approximate cost of the nodes used by the test. It is set when
+ * the test terminates, if machine prices are configured in the defaults of the
+ * controller.
+ */
+export interface Cost {
+  /**
+   * Estimate is the price of the node hours, in the currency of the machine
+   * prices in the defaults of the controller. Nodes with a machine type that
+   * has no price are not included.
+   */
+  estimate: string;
+
+  /**
+   * NodeHours is the total time the nodes of the test were used, in hours.
+   */
+  nodeHours: string;
+
+  /**
+   * UnpricedMachineTypes lists the machine types of the nodes that have no
+   * price. The machine type is "unknown" for nodes that could not be found.
+   */
+  unpricedMachineTypes?: string[];
+}
+
+/**
+ * InteropCaseResult is the outcome of a single test case of an interop test.
+ */
+export interface InteropCase {
+  /**
+   * Message describes why the test case failed. It is empty for test cases
+   * that passed.
+   */
+  message?: string;
+
+  /**
+   * Name is the name of the test case.
+   */
+  name: string;
+
+  /**
+   * Passed is true if the interop client passed the test case.
+   */
+  passed: boolean;
+}
+
+/**
+ * LoadTestRun records the outcome of a previous run of a load test that was
+ * rerun.
+ */
+export interface LoadTestStatusRun {
+  /**
+   * Message is a human legible string that describes the outcome of the run.
+   */
+  message?: string;
+
+  /**
+   * Reason is a camel-case string that indicates the reasoning behind the
+   * state.
+   */
+  reason?: string;
+
+  /**
+   * ResultsURI is the Cloud Storage URI of the raw JSON output of the driver
+   * in the run.
+   */
+  resultsURI?: string;
+
+  /**
+   * Run is the zero-based index of the run.
+   */
+  run: number;
+
+  /**
+   * StartTime is the time when the run started.
+   */
+  startTime?: string;
+
+  /**
+   * State is the state of the load test when the run terminated.
+   */
+  state: string;
+
+  /**
+   * StopTime is the time when the run terminated.
+   */
+  stopTime?: string;
+
+  /**
+   * Summary contains key numbers from the results of the run, as reported by
+   * the driver when it succeeded.
+   */
+  summary?: Summary;
+}
+
+/**
+ * Summary contains key numbers from the results of the run, as reported by the
+ * driver when it succeeded.
+ */
+export interface Summary {
+  /**
+   * ClientSystemTime is the percentage of CPU time spent in the system by the
+   * clients.
+   */
+  clientSystemTime?: string;
+
+  /**
+   * Latency50 is the median latency.
+   */
+  latency50?: string;
+
+  /**
+   * Latency99 is the 99th percentile latency.
+   */
+  latency99?: string;
+
+  /**
+   * Latency999 is the 99.9th percentile latency.
+   */
+  latency999?: string;
+
+  /**
+   * QPS is the number of queries per second.
+   */
+  qps?: string;
+
+  /**
+   * ServerSystemTime is the percentage of CPU time spent in the system by the
+   * servers.
+   */
+  serverSystemTime?: string;
+}
+
+/**
+ * UnreachableWorker is a worker whose driver port could not be reached from
+ * the driver pod of a load test.
+ */
+export interface UnreachableWorker {
+  /**
+   * Address is the address and driver port of the worker.
+   */
+  address: string;
+
+  /**
+   * Message describes the last error encountered when connecting to the
+   * worker.
+   */
+  message?: string;
+
+  /**
+   * Name is the name of the pod of the worker.
+   */
+  name: string;
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 )
@@ -35,6 +36,12 @@ type LoadTestGetter interface {
 	// List fetches all tests, given its options.
 	List(ctx context.Context, opts metav1.ListOptions) (*grpcv1.LoadTestList, error)
 
+	// Watch streams changes to tests, given its options.
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+	// Update saves changes to the metadata and spec of a test resource.
+	Update(ctx context.Context, test *grpcv1.LoadTest, opts metav1.UpdateOptions) (*grpcv1.LoadTest, error)
+
 	// Delete removes a new test resource, given its name.
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 }
@@ -18,8 +18,10 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 
@@ -68,6 +70,33 @@ func (l *loadTestV1Getter) List(ctx context.Context, opts metav1.ListOptions) (*
 	return tests, err
 }
 
+func (l *loadTestV1Getter) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return l.client.Get().
+		Namespace(l.ns).
+		Resource("loadtests").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+func (l *loadTestV1Getter) Update(ctx context.Context, test *grpcv1.LoadTest, opts metav1.UpdateOptions) (*grpcv1.LoadTest, error) {
+	updatedTest := &grpcv1.LoadTest{}
+	err := l.client.Put().
+		Namespace(l.ns).
+		Resource("loadtests").
+		Name(test.Name).
+		Body(test).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(ctx).
+		Into(updatedTest)
+	return updatedTest, err
+}
+
 func (l *loadTestV1Getter) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return l.client.Delete().
 		Namespace(l.ns).
@@ -20,17 +20,20 @@ import (
 	"context"
 	"errors"
 	"flag"
-	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -39,6 +42,7 @@ import (
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
 	"github.com/grpc/test-infra/controllers"
+	"github.com/grpc/test-infra/imagecheck"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -53,20 +57,53 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 }
 
+// defaultsFiles is a flag that may be repeated to name a base defaults file
+// followed by overlays. The first use of the flag replaces the default value.
+type defaultsFiles struct {
+	fileNames []string
+	set       bool
+}
+
+func (d *defaultsFiles) String() string {
+	return strings.Join(d.fileNames, ",")
+}
+
+func (d *defaultsFiles) Set(value string) error {
+	if !d.set {
+		d.fileNames = nil
+		d.set = true
+	}
+	d.fileNames = append(d.fileNames, value)
+	return nil
+}
+
 func main() {
-	var defaultsFile string
+	defaultsFiles := &defaultsFiles{fileNames: []string{"config/defaults.yaml"}}
 	var metricsAddr string
 	var probeAddr string
 	var enableLeaderElection bool
+	var readyOnlyWhenLeader bool
 	var namespace string
+	var checkImages bool
+	var archiveWorkers int
+	var drain bool
 
-	flag.StringVar(&defaultsFile, "defaults-file", "config/defaults.yaml", "Path to a YAML file with a default configuration.")
+	flag.Var(defaultsFiles, "defaults-file", "Path to a YAML file with a default configuration. "+
+		"Repeat the flag to apply overlays, which take precedence over the files before them.")
 	flag.StringVar(&namespace, "namespace", "", "Limits resources considered to a specific namespace.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&checkImages, "check-images", false, "Verify that container images exist in their registries before scheduling a test.")
+	flag.IntVar(&archiveWorkers, "archive-workers", 2, "Number of terminated tests that may be archived at the same time.")
+	flag.BoolVar(&drain, "drain", false,
+		"Start without starting new tests, while tests that already have pods are reconciled until they terminate. "+
+			"The drain can be cancelled with a DELETE request to the /drain endpoint.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&readyOnlyWhenLeader, "ready-only-when-leader", false,
+		"Report the manager as ready only while it holds the leader election lease, "+
+			"so that services such as the webhook only route traffic to the leader.")
 	opts := zap.Options{Development: true}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -78,25 +115,21 @@ func main() {
 	logger := log.FromContext(ctx).WithValues("controller", "LoadTest")
 	logger.Info("starting manager")
 
-	if defaultsFile == "" {
+	if len(defaultsFiles.fileNames) == 0 || defaultsFiles.fileNames[0] == "" {
 		logger.Error(errMissingDefaults, "cannot start without defaults")
 		os.Exit(1)
 	}
 
-	defaultsBytes, err := ioutil.ReadFile(defaultsFile)
+	defaultOptions, err := config.LoadDefaultsFiles(defaultsFiles.fileNames)
 	if err != nil {
-		logger.Error(err, "could not read defaults file")
+		logger.Error(err, "failed to start due to invalid defaults", "defaultsFiles", defaultsFiles.fileNames)
 		os.Exit(1)
 	}
+	logger.Info("loaded defaults", "defaultsFiles", defaultsFiles.fileNames)
 
-	defaultOptions := config.Defaults{}
-	if err := yaml.Unmarshal(defaultsBytes, &defaultOptions); err != nil {
-		logger.Error(err, "could not parse the defaults file contents")
-		os.Exit(1)
-	}
-
-	if err := defaultOptions.Validate(); err != nil {
-		logger.Error(err, "failed to start due to invalid defaults")
+	cacheSelectors, err := controllers.CacheSelectors()
+	if err != nil {
+		logger.Error(err, "unable to create cache selectors")
 		os.Exit(1)
 	}
 
@@ -108,27 +141,102 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "284e7070.e2etest.grpc.io",
 		Namespace:              namespace,
+		NewCache:               cache.BuilderWithOptions(cache.Options{SelectorsByObject: cacheSelectors}),
 	})
 	if err != nil {
 		logger.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.LoadTestReconciler{
-		Defaults: &defaultOptions,
+	reconciler := &controllers.LoadTestReconciler{
+		Defaults: defaultOptions,
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	reconciler.SchedulingMetrics, err = controllers.NewSchedulingMetrics(metrics.Registry)
+	if err != nil {
+		logger.Error(err, "unable to register scheduling metrics")
+		os.Exit(1)
+	}
+	reconciler.Drainer, err = controllers.NewDrainer(mgr.GetClient(), metrics.Registry)
+	if err != nil {
+		logger.Error(err, "unable to register drain metrics")
+		os.Exit(1)
+	}
+	if drain {
+		reconciler.Drainer.Drain()
+		logger.Info("draining, new tests will not be started")
+	}
+	if checkImages {
+		reconciler.ImageChecker = imagecheck.NewRegistryChecker(10*time.Second, 5*time.Minute)
+	}
+
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		logger.Error(err, "unable to create controller", "controller", "LoadTest")
 		os.Exit(1)
 	}
+
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		logger.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+	archiveReconciler := &controllers.ResultArchiveReconciler{
+		Client:                mgr.GetClient(),
+		Defaults:              defaultOptions,
+		PodsGetter:            clientset.CoreV1(),
+		MaxConcurrentArchives: archiveWorkers,
+	}
+	if err = archiveReconciler.SetupWithManager(mgr); err != nil {
+		logger.Error(err, "unable to create controller", "controller", "ResultArchive")
+		os.Exit(1)
+	}
+	if defaultOptions.NodeAgent != nil {
+		nodeAgentInstaller := &controllers.NodeAgentInstaller{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Defaults: defaultOptions,
+		}
+		if err := mgr.Add(nodeAgentInstaller); err != nil {
+			logger.Error(err, "unable to set up node agent")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	summaryHandler := &controllers.SummaryHandler{
+		Reader:   mgr.GetClient(),
+		Defaults: defaultOptions,
+	}
+	if err := mgr.AddMetricsExtraHandler(controllers.SummaryPath, summaryHandler); err != nil {
+		logger.Error(err, "unable to set up load test summary endpoint")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddMetricsExtraHandler(controllers.DrainPath, reconciler.Drainer); err != nil {
+		logger.Error(err, "unable to set up drain endpoint")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		logger.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	leaderTracker, err := controllers.NewLeaderTracker(metrics.Registry)
+	if err != nil {
+		logger.Error(err, "unable to register leader election metrics")
+		os.Exit(1)
+	}
+	if err := mgr.Add(leaderTracker); err != nil {
+		logger.Error(err, "unable to set up leader election tracking")
+		os.Exit(1)
+	}
+
+	readyzCheck := healthz.Ping
+	if readyOnlyWhenLeader {
+		readyzCheck = leaderTracker.ReadyzCheck
+	}
+	if err := mgr.AddReadyzCheck("readyz", readyzCheck); err != nil {
 		logger.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	// This side-effect import is required by GKE.
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	clientset "github.com/grpc/test-infra/clientset"
+	"github.com/grpc/test-infra/gateway"
+	"github.com/grpc/test-infra/logging"
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+)
+
+func main() {
+	var address string
+	var namespace string
+	var namespaces string
+	var tokenFile string
+	var tlsCert string
+	var tlsKey string
+
+	flag.StringVar(&address, "address", ":50052", "address that the gRPC API listens on")
+	flag.StringVar(&namespace, "namespace", "default", "namespace of the tests read by requests that do not name one")
+	flag.StringVar(&namespaces, "namespaces", "", "comma-separated list of other namespaces that requests may read (optional)")
+	flag.StringVar(&tokenFile, "token-file", "", "file listing the bearer tokens accepted from clients, one per line")
+	flag.StringVar(&tlsCert, "tls-cert", "", "certificate file of the server, which enables TLS (optional)")
+	flag.StringVar(&tlsKey, "tls-key", "", "private key file of the server certificate (optional)")
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if tokenFile == "" {
+		log.Fatalf("missing flag -token-file")
+	}
+	auth, err := gateway.LoadTokenAuth(tokenFile)
+	if err != nil {
+		log.Fatalf("failed to load tokens: %v", err)
+	}
+	opts := auth.ServerOptions()
+	otherNamespaces := splitNamespaces(namespaces)
+
+	if (tlsCert == "") != (tlsKey == "") {
+		log.Fatalf("flags -tls-cert and -tls-key must be set together")
+	}
+	if tlsCert != "" {
+		creds, err := credentials.NewServerTLSFromFile(tlsCert, tlsKey)
+		if err != nil {
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	if err := grpcv1.AddToScheme(clientgoscheme.Scheme); err != nil {
+		log.Fatalf("failed to register LoadTest types: %v", err)
+	}
+	config := ctrl.GetConfigOrDie()
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("failed to create clientset: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("failed to create Kubernetes clientset: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", address, err)
+	}
+
+	server := grpc.NewServer(opts...)
+	pb.RegisterLoadTestServiceServer(server, gateway.NewServer(client.LoadTestV1(), kubeClient.CoreV1(), namespace, otherNamespaces))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// Watch and log streams do not end on their own, so the server is
+		// stopped without waiting for them.
+		server.Stop()
+	}()
+
+	log.Printf("Serving LoadTest API on %s for namespace %s", listener.Addr(), strings.Join(append([]string{namespace}, otherNamespaces...), ", "))
+	if err := server.Serve(listener); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+
+// splitNamespaces returns the namespaces in a comma-separated list, ignoring
+// empty entries.
+func splitNamespaces(list string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(list, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
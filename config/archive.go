@@ -0,0 +1,83 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultArchiveTimeoutSeconds is the time allowed to archive a load test
+// when the archive settings do not specify a timeout.
+const DefaultArchiveTimeoutSeconds = 5 * 60
+
+// Archive configures the work that the controller performs once a load test
+// terminates, such as saving the logs of its pods and notifying an external
+// system. This work is done by a controller separate from the one that
+// schedules tests, so that it does not delay scheduling and its failures do
+// not change the state of tests.
+type Archive struct {
+	// Directory is the directory where the logs of the pods and the final
+	// state of each test are saved, under a subdirectory named after the
+	// namespace and name of the test. This is usually a mounted volume. This
+	// field is optional. When omitted, nothing is saved.
+	Directory string `json:"directory,omitempty"`
+
+	// NotificationURL is a URL that receives a POST request with the final
+	// state of each test as JSON. This field is optional. When omitted, no
+	// notification is sent.
+	NotificationURL string `json:"notificationURL,omitempty"`
+
+	// TimeoutSeconds is the time allowed to archive a test. Tests that fail
+	// to be archived are retried with a backoff. This field is optional.
+	// When omitted or zero, DefaultArchiveTimeoutSeconds is used.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// Timeout returns the time allowed to archive a test.
+func (a *Archive) Timeout() time.Duration {
+	if a.TimeoutSeconds == 0 {
+		return DefaultArchiveTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.TimeoutSeconds) * time.Second
+}
+
+// validate returns an error if the archive settings do nothing or contain
+// values out of range.
+func (a *Archive) validate() error {
+	if a.Directory == "" && a.NotificationURL == "" {
+		return errors.New("archive must specify a directory or a notificationURL")
+	}
+
+	if a.NotificationURL != "" {
+		u, err := url.Parse(a.NotificationURL)
+		if err != nil {
+			return errors.Wrap(err, "archive notificationURL is invalid")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("archive notificationURL must use http or https, not %q", u.Scheme)
+		}
+	}
+
+	if a.TimeoutSeconds < 0 {
+		return errors.New("archive timeoutSeconds must not be negative")
+	}
+
+	return nil
+}
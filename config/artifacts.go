@@ -0,0 +1,92 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaxSignedURLExpirySeconds is the longest time that a signed URL for an
+// artifact may remain valid. It is the limit of V4 signed URLs in Google Cloud
+// Storage, which is seven days.
+const MaxSignedURLExpirySeconds = 7 * 24 * 60 * 60
+
+// Artifacts configures the upload of the artifacts of load tests, such as pod
+// logs, scenario results and profiles, to a Google Cloud Storage bucket. The
+// runner uploads the artifacts of each test under a canonical layout and links
+// them from its reports.
+type Artifacts struct {
+	// Bucket is the name of the bucket that artifacts are uploaded to.
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the names of the uploaded objects. This field
+	// is optional. When omitted, objects are placed at the root of the
+	// bucket.
+	Prefix string `json:"prefix,omitempty"`
+
+	// RetentionDays is the number of days after which objects in the bucket
+	// are deleted by a lifecycle rule. The rule applies to the whole bucket,
+	// so the bucket should be dedicated to artifacts. This field is
+	// optional. When omitted or zero, the lifecycle configuration of the
+	// bucket is left unchanged.
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+
+	// SignedURLExpirySeconds is the time that signed URLs to artifacts remain
+	// valid. It must not exceed MaxSignedURLExpirySeconds. This field is
+	// optional. When omitted or zero, reports link to the gs:// URIs of the
+	// artifacts instead of signed URLs.
+	SignedURLExpirySeconds int32 `json:"signedURLExpirySeconds,omitempty"`
+
+	// SigningKeyFile is the name of a file containing the JSON key of the
+	// service account used to sign URLs. It is required when
+	// SignedURLExpirySeconds is set.
+	SigningKeyFile string `json:"signingKeyFile,omitempty"`
+}
+
+// SignedURLExpiry returns the time that signed URLs to artifacts remain
+// valid, or zero if URLs are not signed.
+func (a *Artifacts) SignedURLExpiry() time.Duration {
+	return time.Duration(a.SignedURLExpirySeconds) * time.Second
+}
+
+// validate returns an error if the artifacts settings are incomplete or
+// contain values out of range.
+func (a *Artifacts) validate() error {
+	if a.Bucket == "" {
+		return errors.New("artifacts must specify a bucket")
+	}
+
+	if a.RetentionDays < 0 {
+		return errors.New("artifacts retentionDays must not be negative")
+	}
+
+	if a.SignedURLExpirySeconds < 0 {
+		return errors.New("artifacts signedURLExpirySeconds must not be negative")
+	}
+
+	if a.SignedURLExpirySeconds > MaxSignedURLExpirySeconds {
+		return errors.Errorf("artifacts signedURLExpirySeconds must not exceed %d", MaxSignedURLExpirySeconds)
+	}
+
+	if a.SignedURLExpirySeconds > 0 && a.SigningKeyFile == "" {
+		return errors.New("artifacts signingKeyFile is required to sign URLs")
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// DriverCompatibility declares the oldest version of the worker images that
+// can be paired with a range of versions of the driver image. Versions are
+// read from the tags of the images, such as "v1.60.0".
+type DriverCompatibility struct {
+	// DriverVersion is the oldest version of the driver image that the entry
+	// applies to. The entry applies to every newer version of the driver, up
+	// to the DriverVersion of the next entry.
+	DriverVersion string `json:"driverVersion"`
+
+	// MinWorkerVersion is the oldest version of the client and server images
+	// that speak the protocol of the driver.
+	MinWorkerVersion string `json:"minWorkerVersion"`
+}
+
+// imageVersion is a version parsed from the tag of an image.
+type imageVersion []int
+
+// parseVersion parses a version such as "v1.60.0", "1.60" or "v1.60.0-rc1".
+// The leading "v" is optional, and any pre-release or build suffix is
+// ignored. False is returned if the string is not a version.
+func parseVersion(s string) (imageVersion, bool) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" {
+		return nil, false
+	}
+
+	var version imageVersion
+	for _, part := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		version = append(version, n)
+	}
+	return version, true
+}
+
+// compare returns a negative number if v is older than other, a positive
+// number if it is newer, and zero if they are the same. Missing components
+// are treated as zero, so "v1.60" is the same as "v1.60.0".
+func (v imageVersion) compare(other imageVersion) int {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a != b {
+			return a - b
+		}
+	}
+	return 0
+}
+
+// versionForImage returns the version in the tag of an image. False is
+// returned if the image has no tag or its tag is not a version, such as
+// "latest" or the name of a branch.
+func versionForImage(image string) (imageVersion, bool) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return nil, false
+	}
+	return parseVersion(image[i+1:])
+}
+
+// minWorkerVersion returns the oldest version of the worker images that can
+// be paired with a version of the driver, and false if no entry of the
+// compatibility matrix applies to it.
+func (d *Defaults) minWorkerVersion(driverVersion imageVersion) (string, bool) {
+	var found *DriverCompatibility
+	var foundVersion imageVersion
+	for i := range d.Compatibility {
+		entry := &d.Compatibility[i]
+		entryVersion, _ := parseVersion(entry.DriverVersion)
+		if entryVersion.compare(driverVersion) > 0 {
+			continue
+		}
+		if found == nil || entryVersion.compare(foundVersion) > 0 {
+			found = entry
+			foundVersion = entryVersion
+		}
+	}
+	if found == nil {
+		return "", false
+	}
+	return found.MinWorkerVersion, true
+}
+
+// CheckCompatibility returns an error if the image of a client or server of a
+// load test is older than the oldest worker version that the compatibility
+// matrix allows for the driver image of the test. Images whose tags are not
+// versions are not checked, since their versions cannot be compared. Nil is
+// returned when no compatibility matrix is declared.
+func (d *Defaults) CheckCompatibility(test *grpcv1.LoadTest) error {
+	if len(d.Compatibility) == 0 || test.Spec.Driver == nil || len(test.Spec.Driver.Run) == 0 {
+		return nil
+	}
+
+	driverImage := test.Spec.Driver.Run[0].Image
+	driverVersion, ok := versionForImage(driverImage)
+	if !ok {
+		return nil
+	}
+	minVersion, ok := d.minWorkerVersion(driverVersion)
+	if !ok {
+		return nil
+	}
+	minWorkerVersion, _ := parseVersion(minVersion)
+
+	check := func(role string, name *string, run []corev1.Container) error {
+		if len(run) == 0 {
+			return nil
+		}
+		workerVersion, ok := versionForImage(run[0].Image)
+		if !ok || workerVersion.compare(minWorkerVersion) >= 0 {
+			return nil
+		}
+		workerName := role
+		if name != nil {
+			workerName = *name
+		}
+		return errors.Errorf("%s %q uses image %q, which is older than %s, the oldest worker version compatible with driver image %q", role, workerName, run[0].Image, minVersion, driverImage)
+	}
+
+	for i := range test.Spec.Servers {
+		server := &test.Spec.Servers[i]
+		if err := check(ServerRole, server.Name, server.Run); err != nil {
+			return err
+		}
+	}
+	for i := range test.Spec.Clients {
+		client := &test.Spec.Clients[i]
+		if err := check(ClientRole, client.Name, client.Run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCompatibility returns an error if an entry of the compatibility
+// matrix is missing a version, contains a string that is not a version, or
+// declares the same driver version as another entry.
+func (d *Defaults) validateCompatibility() error {
+	var driverVersions []imageVersion
+	for i, entry := range d.Compatibility {
+		driverVersion, ok := parseVersion(entry.DriverVersion)
+		if !ok {
+			return errors.Errorf("compatibility entry (index %d) has invalid driverVersion %q", i, entry.DriverVersion)
+		}
+		if _, ok := parseVersion(entry.MinWorkerVersion); !ok {
+			return errors.Errorf("compatibility entry (index %d) has invalid minWorkerVersion %q", i, entry.MinWorkerVersion)
+		}
+
+		for j, other := range driverVersions {
+			if driverVersion.compare(other) == 0 {
+				return errors.Errorf("compatibility entry (index %d) duplicates driverVersion of entry (index %d)", i, j)
+			}
+		}
+		driverVersions = append(driverVersions, driverVersion)
+	}
+	return nil
+}
@@ -17,6 +17,21 @@ limitations under the License.
 package config
 
 const (
+	// AnomalyLabel is the key for a label that is set to "true" on a load
+	// test whose results are anomalous when compared with the results of
+	// previous runs of the same scenario.
+	AnomalyLabel = "e2etest.grpc.io/anomaly"
+
+	// AnomalyMetricsAnnotation is the key for an annotation on a load test
+	// that lists the metrics whose results are anomalous, with their
+	// scores, such as "latency50:4.21,qps:-3.80".
+	AnomalyMetricsAnnotation = "e2etest.grpc.io/anomaly-metrics"
+
+	// ArchivedAnnotation is the key for an annotation on a terminated load
+	// test, set once its logs and final state have been archived. Its value
+	// is the time of the archive in RFC 3339 format.
+	ArchivedAnnotation = "e2etest.grpc.io/archived"
+
 	// BazelCacheVolumeName holds the name of the volume which allows images to
 	// share a bazel cache.
 	BazelCacheVolumeName = "bazel-cache"
@@ -26,6 +41,10 @@ const (
 	// https://docs.bazel.build/versions/master/output_directories.html.
 	BazelCacheMountPath = "/root/.cache/bazel"
 
+	// BenchmarkSecondsAnnotation is the key for an annotation on a load test
+	// that overrides the benchmark_seconds field of its scenario.
+	BenchmarkSecondsAnnotation = "e2etest.grpc.io/benchmark-seconds"
+
 	// BigQueryTableEnv specifies the name of the env variable that holds the name
 	// of the table where results should be written.
 	BigQueryTableEnv = "BQ_RESULT_TABLE"
@@ -34,6 +53,15 @@ const (
 	// a binary or other bundle required to run the tests.
 	BuildInitContainerName = "build"
 
+	// CancelledMarkerFile is the path of the file created by the preStop hook
+	// of the driver, when the pod of the driver is deleted. The driver checks
+	// for this file to tell a cancelled test from one that timed out.
+	CancelledMarkerFile = "/tmp/cancelled"
+
+	// CancelledMarkerFileEnv specifies the name of the env variable that holds
+	// the path of the CancelledMarkerFile.
+	CancelledMarkerFileEnv = "CANCELLED_MARKER_FILE"
+
 	// ClientRole is the value the controller expects for the RoleLabel
 	// on a client component.
 	ClientRole = "client"
@@ -54,6 +82,38 @@ const (
 	// components with the same role.
 	ComponentNameLabel = "loadtest-component"
 
+	// ControllerName is the name of the load test controller. It is used as
+	// the field manager for server-side apply requests and as the value of
+	// the ManagedByLabel on the resources that the controller creates.
+	ControllerName = "loadtest-controller"
+
+	// CostPerHourAnnotation is the key of an annotation that the ready init
+	// container adds to the metadata of a load test. Its value is the total
+	// price per hour of the nodes used by the test.
+	CostPerHourAnnotation = "e2etest.grpc.io/cost-per-hour"
+
+	// CPUPlatformLabel is the key for an optional label on a node with the
+	// name of its CPU platform, such as "intel-cascade-lake". It is included
+	// in the node metadata that is uploaded with the results of a test.
+	CPUPlatformLabel = "e2etest.grpc.io/cpu-platform"
+
+	// DashboardURLAnnotation is the key for an annotation on a load test,
+	// which the controller sets to a link to the dashboard that graphs the
+	// results of the test once it terminates. The link is rendered from the
+	// dashboardURLTemplate of the defaults.
+	DashboardURLAnnotation = "e2etest.grpc.io/dashboard-url"
+
+	// DefaultTerminationGracePeriodSeconds is the time given to the pods of a
+	// load test to stop gracefully after the test is deleted, when no value is
+	// set in the defaults of the controller.
+	DefaultTerminationGracePeriodSeconds = 60
+
+	// DependsOnAnnotation is the key for an annotation on a load test
+	// configuration with a comma-separated list of the names of other
+	// configurations in the same run. The runner only creates the test once
+	// these tests have succeeded, and skips it if any of them fails.
+	DependsOnAnnotation = "e2etest.grpc.io/depends-on"
+
 	// DriverRole is the value the controller expects for the RoleLabel
 	// on a driver component.
 	DriverRole = "driver"
@@ -63,17 +123,132 @@ const (
 	// instructions and receive results from the servers and clients.
 	DriverPort = 10000
 
+	// DriverPortAnnotation is the key for an annotation on worker pods of
+	// tests with shared placement. Its value is the driver port allocated
+	// to the worker, which is published to the worker through the downward
+	// API.
+	DriverPortAnnotation = "e2etest.grpc.io/driver-port"
+
 	// DriverPortEnv specifies the name of the env variable that contains driver port.
 	DriverPortEnv = "DRIVER_PORT"
 
+	// DryRunAnnotation is the key for an annotation on a load test. When its
+	// value is "true", the controller renders the pods of the test to a
+	// ConfigMap instead of creating them.
+	DryRunAnnotation = "e2etest.grpc.io/dry-run"
+
+	// DryRunConfigMapSuffix is appended to the name of a load test to name
+	// the ConfigMap with its rendered pods in dry-run mode.
+	DryRunConfigMapSuffix = "-dry-run"
+
 	// EnablePrometheusEnv specifies the name of the env variable that indicates
 	// if the collection of Prometheus data is enabled.
 	EnablePrometheusEnv = "ENABLE_PROMETHEUS"
 
+	// GeneratorRole is the value the controller expects for the RoleLabel
+	// on a generator component.
+	GeneratorRole = "generator"
+
+	// InteropBinaryPath is the path where the interop init container copies
+	// the program that runs the test cases of an interop test.
+	InteropBinaryPath = "/interop/interop"
+
+	// InteropInitContainerName is the name of the init container that copies
+	// the interop program into the driver pod of an interop test.
+	InteropInitContainerName = "interop"
+
+	// InteropMountPath is the path where the volume shared between the
+	// interop init container and the run container is mounted.
+	InteropMountPath = "/interop"
+
+	// InteropServerHostEnv is the name of the environment variable with the
+	// host name of the server that the interop client connects to.
+	InteropServerHostEnv = "INTEROP_SERVER_HOST"
+
+	// InteropServerPortEnv is the name of the environment variable with the
+	// port where the server of an interop test listens.
+	InteropServerPortEnv = "INTEROP_SERVER_PORT"
+
+	// InteropTestCasesEnv is the name of the environment variable with the
+	// comma-separated names of the test cases of an interop test.
+	InteropTestCasesEnv = "INTEROP_TEST_CASES"
+
+	// InteropUseTLSEnv is the name of the environment variable that is
+	// "true" when the interop client should connect to the server over TLS.
+	InteropUseTLSEnv = "INTEROP_USE_TLS"
+
+	// InteropVolumeName is the name of the volume shared between the interop
+	// init container and the run container.
+	InteropVolumeName = "interop"
+
+	// IsolationTaintKey is the key of a taint that the controller sets on
+	// the nodes it reserves for a test with isolated placement. Its value is
+	// the UID of the test, and its effect is NoSchedule, so only the pods of
+	// the test, which tolerate it, are scheduled on the nodes.
+	IsolationTaintKey = "e2etest.grpc.io/isolated-for"
+
+	// KeepAnnotation is the key for an annotation on a load test. When its
+	// value is "true", the test and its pods are kept for the retention window
+	// in the defaults instead of being deleted when their TTL expires, so that
+	// failures can be investigated.
+	KeepAnnotation = "e2etest.grpc.io/keep"
+
+	// LoadTestLabel is a label with the name of the load test that owns a
+	// pod. It allows other resources, such as a PodDisruptionBudget, to
+	// select all pods for a single test.
+	LoadTestLabel = "loadtest"
+
+	// MachineHourlyPricesEnv specifies the name of the env variable that holds
+	// the machine prices from the defaults of the controller, encoded as JSON.
+	MachineHourlyPricesEnv = "MACHINE_HOURLY_PRICES"
+
+	// ManagedByLabel is a label that identifies the resources created by the
+	// load test controller. Its value is always ControllerName.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+
+	// MetricsPortAnnotation is the key for an annotation on a worker pod with
+	// the number of the port where the worker serves metrics. The port may
+	// differ from the one requested in the load test, when that port is
+	// already declared by another container in the pod.
+	MetricsPortAnnotation = "e2etest.grpc.io/metrics-port"
+
+	// MetricsPortEnv specifies the name of the env variable that contains the
+	// port where a worker should serve metrics.
+	MetricsPortEnv = "METRICS_PORT"
+
+	// MetricsPortName is the name of the container port where a worker
+	// serves metrics.
+	MetricsPortName = "metrics"
+
+	// NetemInitContainerName holds the name of the init container that
+	// configures network emulation on the interface of a worker pod.
+	NetemInitContainerName = "netem"
+
+	// NodeAgentName is the name of the DaemonSet that samples system metrics
+	// of nodes, and the value of the "app" label on its pods.
+	NodeAgentName = "node-agent"
+
+	// NodeAgentPortName is the name of the container port where the node
+	// agent serves its samples.
+	NodeAgentPortName = "samples"
+
+	// OwnerLabel is the key for a label on a load test with the name of the
+	// user or team that owns the test. It is used to share capacity between
+	// owners when the fair share scheduling policy is enabled.
+	OwnerLabel = "e2etest.grpc.io/owner"
+
 	// PoolLabel is the key for a label which will have the name of a pool as
 	// the value.
 	PoolLabel = "pool"
 
+	// PprofPortEnv specifies the name of the env variable that contains the
+	// port where a worker should serve pprof profiles.
+	PprofPortEnv = "PPROF_PORT"
+
+	// PprofPortName is the name of the container port where a worker serves
+	// pprof profiles.
+	PprofPortName = "pprof"
+
 	// ReadyInitContainerName holds the name of the init container that blocks a
 	// driver from running until all worker pods are ready.
 	ReadyInitContainerName = "ready"
@@ -98,6 +273,21 @@ const (
 	// between the ready init container and the driver's run container.
 	ReadyVolumeName = "worker-addresses"
 
+	// RerunAnnotation is the key for an annotation on a load test. When a
+	// terminated test is annotated with a value that differs from the value
+	// that started its current run, such as a timestamp, the controller
+	// records the outcome of the run in the status of the test and runs it
+	// again with new pods, keeping its name.
+	RerunAnnotation = "e2etest.grpc.io/rerun"
+
+	// RestartWorkersEnv specifies the name of the env variable that is set on
+	// the driver when the workers should be restarted between scenarios.
+	RestartWorkersEnv = "RESTART_WORKERS"
+
+	// ResultsURIEnv specifies the name of the env variable that holds the
+	// Cloud Storage URI where the driver should upload its raw JSON output.
+	ResultsURIEnv = "RESULTS_URI"
+
 	// RoleLabel is a label with the role  of a test component. For
 	// example, "loadtest-role=server" indicates a server component.
 	RoleLabel = "loadtest-role"
@@ -107,6 +297,16 @@ const (
 	// The main container is always the first container on the list.
 	RunContainerName = "main"
 
+	// RunLabel is a label with the index of the run of a load test that
+	// created a pod. It is only set on pods created after the test was
+	// rerun.
+	RunLabel = "loadtest-run"
+
+	// RunNamespaceLabel is the key for a label on the namespaces that the
+	// runner creates for a single run of tests, so that namespaces left
+	// behind by interrupted runs can be found and deleted.
+	RunNamespaceLabel = "e2etest.grpc.io/run-namespace"
+
 	// ScenariosFileEnv specifies the name of an env variable that specifies the
 	// path to a JSON file with scenarios.
 	ScenariosFileEnv = "SCENARIOS_FILE"
@@ -123,6 +323,65 @@ const (
 	// to run test.
 	ServerPort = 10010
 
+	// ShardOfAnnotation is the key for an annotation on a load test that
+	// was split from a test with several scenarios. Its value is the name of
+	// the original test.
+	ShardOfAnnotation = "e2etest.grpc.io/shard-of"
+
+	// SpecHashAnnotation is the key for an annotation on a load test with
+	// the canonical hash of its spec, set by the runner when it submits the
+	// test.
+	SpecHashAnnotation = "e2etest.grpc.io/spec-hash"
+
+	// SpecHashLabel is the key for a label on a load test with the same
+	// value as the SpecHashAnnotation, so tests with identical specs can be
+	// listed with a label selector.
+	SpecHashLabel = "e2etest.grpc.io/spec-hash"
+
+	// SoakIterationLabel is a label with the index of the soak iteration
+	// that created a pod. It is only set on the pods of soak tests.
+	SoakIterationLabel = "loadtest-soak-iteration"
+
+	// SupervisorBinaryPath is the path where the supervisor init container
+	// copies the program that enforces the timeout on the run container.
+	SupervisorBinaryPath = "/supervisor/supervisor"
+
+	// SupervisorInitContainerName is the name of the init container that
+	// copies the supervisor program into the pods of clients and servers.
+	SupervisorInitContainerName = "supervisor"
+
+	// SupervisorMountPath is the path where the volume shared between the
+	// supervisor init container and the run container is mounted.
+	SupervisorMountPath = "/supervisor"
+
+	// SupervisorVolumeName is the name of the volume shared between the
+	// supervisor init container and the run container.
+	SupervisorVolumeName = "supervisor"
+
+	// TestKindLabel is the key for a label on a load test with the kind of
+	// the test. Declared node pools may restrict the kinds of tests that run
+	// in them.
+	TestKindLabel = "e2etest.grpc.io/kind"
+
+	// WarmupSecondsAnnotation is the key for an annotation on a load test
+	// that overrides the warmup_seconds field of its scenario.
+	WarmupSecondsAnnotation = "e2etest.grpc.io/warmup-seconds"
+
+	// WorkerLabel is a label that is set to "true" on the pods of servers and
+	// clients. It allows the headless Service of a load test to select all of
+	// its workers.
+	WorkerLabel = "loadtest-worker"
+
+	// WorkerRunsEnv specifies the name of the env variable that holds the
+	// number of times the run container of a worker starts its command when
+	// the workers are restarted between scenarios.
+	WorkerRunsEnv = "WORKER_RUNS"
+
+	// WorkerStopDelaySeconds is the time the preStop hook of a client or
+	// server waits before the worker receives SIGTERM. This gives the driver
+	// time to ask the workers to quit after the test is deleted.
+	WorkerStopDelaySeconds = 10
+
 	// WorkspaceMountPath contains the path to mount the volume identified by
 	// `workspaceVolume`.
 	WorkspaceMountPath = "/src/workspace"
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// DashboardURL renders links from load tests to the dashboards that graph
+// their results, such as a Grafana dashboard showing the time range of the
+// test. Links are rendered from a Go template, which is given the fields of
+// DashboardURLData.
+type DashboardURL struct {
+	template *template.Template
+}
+
+// DashboardURLData is the data a dashboard URL template is rendered with.
+type DashboardURLData struct {
+	// Name is the name of the test.
+	Name string
+
+	// Namespace is the namespace of the test.
+	Namespace string
+
+	// Labels are the labels of the test.
+	Labels map[string]string
+
+	// Annotations are the annotations of the test.
+	Annotations map[string]string
+
+	// State is the state of the test, such as Succeeded or Errored.
+	State string
+
+	// Start is the time the test started.
+	Start time.Time
+
+	// Stop is the time the test terminated, or the time the link was
+	// rendered if it has not terminated.
+	Stop time.Time
+
+	// From is Start in milliseconds since the epoch, as used in the time
+	// range of Grafana dashboards.
+	From int64
+
+	// To is Stop in milliseconds since the epoch.
+	To int64
+}
+
+// ParseDashboardURL parses a dashboard URL template. For example, the
+// template
+//
+//	https://grafana.example.com/d/abc?var-test={{.Name}}&from={{.From}}&to={{.To}}
+//
+// links to a Grafana dashboard showing the time range of each test. Values
+// that may contain special characters can be escaped with urlquery.
+func ParseDashboardURL(text string) (*DashboardURL, error) {
+	tmpl, err := template.New("dashboardURL").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "dashboard URL template is invalid")
+	}
+	d := &DashboardURL{template: tmpl}
+
+	// Render a sample test, so that references to fields that do not exist
+	// are reported now rather than when a test terminates.
+	sample := &grpcv1.LoadTest{ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"}}
+	link, err := d.Render(sample, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, errors.Wrap(err, "dashboard URL template does not render a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("dashboard URL must use http or https, not %q", u.Scheme)
+	}
+	return d, nil
+}
+
+// Render returns the dashboard URL of a test. The time range of a test that
+// has not terminated ends at the given time.
+func (d *DashboardURL) Render(test *grpcv1.LoadTest, now time.Time) (string, error) {
+	data := &DashboardURLData{
+		Name:        test.Name,
+		Namespace:   test.Namespace,
+		Labels:      test.Labels,
+		Annotations: test.Annotations,
+		State:       string(test.Status.State),
+		Start:       now,
+		Stop:        now,
+	}
+	if test.Status.StartTime != nil {
+		data.Start = test.Status.StartTime.Time
+	}
+	if test.Status.StopTime != nil {
+		data.Stop = test.Status.StopTime.Time
+	}
+	data.From = data.Start.UnixMilli()
+	data.To = data.Stop.UnixMilli()
+
+	var b strings.Builder
+	if err := d.template.Execute(&b, data); err != nil {
+		return "", errors.Wrap(err, "failed to render dashboard URL")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
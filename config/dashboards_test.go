@@ -0,0 +1,97 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("DashboardURL", func() {
+	var test *grpcv1.LoadTest
+	start := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	stop := start.Add(5 * time.Minute)
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cxx-example",
+				Namespace:   "benchmarks",
+				Labels:      map[string]string{"language": "cxx"},
+				Annotations: map[string]string{"scenario": "cpp protobuf"},
+			},
+			Status: grpcv1.LoadTestStatus{
+				State:     grpcv1.Succeeded,
+				StartTime: &metav1.Time{Time: start},
+				StopTime:  &metav1.Time{Time: stop},
+			},
+		}
+	})
+
+	It("renders the name and time range of a test", func() {
+		d, err := ParseDashboardURL("https://grafana.example.com/d/abc?var-test={{.Name}}&from={{.From}}&to={{.To}}")
+		Expect(err).ToNot(HaveOccurred())
+		link, err := d.Render(test, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(link).To(Equal("https://grafana.example.com/d/abc?var-test=cxx-example&from=1646136000000&to=1646136300000"))
+	})
+
+	It("renders labels and annotations", func() {
+		d, err := ParseDashboardURL(`https://grafana.example.com/d/abc?var-language={{.Labels.language}}&var-scenario={{index .Annotations "scenario" | urlquery}}`)
+		Expect(err).ToNot(HaveOccurred())
+		link, err := d.Render(test, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(link).To(Equal("https://grafana.example.com/d/abc?var-language=cxx&var-scenario=cpp+protobuf"))
+	})
+
+	It("ends the time range of a running test at the given time", func() {
+		test.Status.StopTime = nil
+		d, err := ParseDashboardURL("https://grafana.example.com/d/abc?from={{.From}}&to={{.To}}")
+		Expect(err).ToNot(HaveOccurred())
+		link, err := d.Render(test, stop.Add(time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(link).To(Equal("https://grafana.example.com/d/abc?from=1646136000000&to=1646136360000"))
+	})
+
+	It("rejects invalid templates", func() {
+		for _, text := range []string{
+			"https://grafana.example.com/d/abc?var-test={{.Name}",
+			"https://grafana.example.com/d/abc?var-test={{.Missing}}",
+			"ftp://grafana.example.com/{{.Name}}",
+		} {
+			_, err := ParseDashboardURL(text)
+			Expect(err).To(HaveOccurred(), "template %q", text)
+		}
+	})
+
+	It("is validated with the defaults", func() {
+		defaults := &Defaults{
+			CloneImage:           "gcr.io/grpc-fake-project/test-infra/clone",
+			ReadyImage:           "gcr.io/grpc-fake-project/test-infra/ready",
+			DriverImage:          "gcr.io/grpc-fake-project/test-infra/driver",
+			DashboardURLTemplate: "https://grafana.example.com/d/abc?var-test={{.Name}",
+		}
+		Expect(defaults.Validate()).ToNot(Succeed())
+		defaults.DashboardURLTemplate = "https://grafana.example.com/d/abc?var-test={{.Name}}"
+		Expect(defaults.Validate()).To(Succeed())
+	})
+})
@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/uuid"
 	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/languages"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -44,6 +45,23 @@ type Defaults struct {
 	// starting before all worker pods are ready.
 	ReadyImage string `json:"readyImage"`
 
+	// NetemImage specifies the container image to use to configure network
+	// emulation on worker pods. This field is optional. When omitted, load
+	// tests with a network profile cannot be run.
+	NetemImage string `json:"netemImage,omitempty"`
+
+	// InteropImage specifies the container image that provides the program
+	// that runs the test cases of interop tests. This field is optional.
+	// When omitted, interop tests cannot be run.
+	InteropImage string `json:"interopImage,omitempty"`
+
+	// SupervisorImage specifies the container image that provides the
+	// program that stops the run containers of clients and servers when the
+	// timeout of their load test is exceeded. This field is optional. When
+	// omitted, workers must honor the POD_TIMEOUT and KILL_AFTER environment
+	// variables themselves.
+	SupervisorImage string `json:"supervisorImage,omitempty"`
+
 	// DriverImage specifies a default driver image. This image will
 	// be used to orchestrate a test.
 	DriverImage string `json:"driverImage"`
@@ -54,8 +72,142 @@ type Defaults struct {
 
 	// KillAfter is the duration allowed for pods to respond after timeout.
 	KillAfter float64 `json:"killAfter"`
+
+	// ImagePullTimeoutSeconds is the longest time a pod may wait for the image
+	// of one of its containers to be pulled before the load test is marked as
+	// errored. This field is optional. When omitted or zero, image pull
+	// failures are only caught by the timeout of the test.
+	ImagePullTimeoutSeconds int32 `json:"imagePullTimeoutSeconds,omitempty"`
+
+	// TimeoutSeconds is the timeout of load tests that do not set one and
+	// have no scenarios to compute it from, such as interop tests. This
+	// field is optional. When omitted or zero, DefaultTimeoutSeconds is used.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// WorkerSetupSeconds is the time allowed for each server and client pod
+	// of a load test to start, when the timeout of the test is computed from
+	// its scenarios. This field is optional. When omitted or zero,
+	// DefaultWorkerSetupSeconds is used.
+	WorkerSetupSeconds int32 `json:"workerSetupSeconds,omitempty"`
+
+	// MaxContainerRestarts is the number of restarts of a container at which
+	// the load test is marked as errored. This field is optional. When
+	// omitted or zero, restarts are not limited.
+	//
+	// The pods of load tests use the Never restart policy, so their own
+	// containers are not restarted. The limit only applies to containers that
+	// Kubernetes restarts regardless of the policy of the pod, such as sidecar
+	// proxies injected by a service mesh as init containers with a restart
+	// policy of their own.
+	MaxContainerRestarts int32 `json:"maxContainerRestarts,omitempty"`
+
+	// SchedulingSLOSeconds is the longest time that the pods of a load test
+	// are expected to take to be running, measured from the creation of the
+	// test. When the pods of a test take longer, the SchedulingSLOExceeded
+	// condition is added to its status. This field is optional. When omitted
+	// or zero, the condition is never added.
+	SchedulingSLOSeconds int32 `json:"schedulingSLOSeconds,omitempty"`
+
+	// PriorityClassName is the name of a PriorityClass that is assigned to
+	// all driver, client and server pods. This field is optional. When
+	// omitted, pods use the default priority of the cluster.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// NodePools declares the pools of nodes that run load tests. This field
+	// is optional. When omitted, the controller counts the nodes with a pool
+	// label to determine the capacity of each pool, and uses the
+	// DefaultPoolLabels to find the default pools.
+	NodePools []NodePool `json:"nodePools,omitempty"`
+
+	// SchedulingPolicy determines the order in which pending load tests
+	// claim the capacity of their pools. This field is optional. When
+	// omitted, the FIFOSchedulingPolicy is used.
+	SchedulingPolicy SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
+	// KeepRetentionSeconds is the longest time a load test with the
+	// KeepAnnotation can live on the cluster, measured from its start like
+	// its TTL. The TTL of the test is used if it is longer. This field is
+	// optional. When omitted or zero, kept tests are not deleted by the
+	// controller and must be deleted manually.
+	KeepRetentionSeconds int32 `json:"keepRetentionSeconds,omitempty"`
+
+	// KeepFirstFailures enables setting the KeepAnnotation automatically on
+	// a load test that errors, when no other errored test in its namespace
+	// with the same reason is kept. This retains one example of each kind of
+	// failure without keeping every test when many fail the same way.
+	KeepFirstFailures bool `json:"keepFirstFailures,omitempty"`
+
+	// TerminationGracePeriodSeconds is the time given to the pods of a load
+	// test to stop after the test is deleted. Within this time, the driver
+	// asks the workers to quit and flushes partial results. It must be
+	// longer than WorkerStopDelaySeconds. This field is optional. When
+	// omitted or zero, DefaultTerminationGracePeriodSeconds is used.
+	TerminationGracePeriodSeconds int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// MachineHourlyPrices maps machine types, as found in the
+	// node.kubernetes.io/instance-type label of nodes, to their price per
+	// hour. The prices are used to estimate the cost of each load test. This
+	// field is optional. When omitted, costs are not estimated.
+	MachineHourlyPrices map[string]float64 `json:"machineHourlyPrices,omitempty"`
+
+	// Artifacts configures the upload of the logs, results and profiles of
+	// load tests to Google Cloud Storage by the runner. This field is
+	// optional. When omitted, artifacts are only saved locally.
+	Artifacts *Artifacts `json:"artifacts,omitempty"`
+
+	// Archive configures the archive of the logs and final state of load
+	// tests by the controller once they terminate. This field is optional.
+	// When omitted, terminated tests are not archived.
+	Archive *Archive `json:"archive,omitempty"`
+
+	// NodeAgent configures a DaemonSet that samples system metrics of the
+	// nodes that run load tests, which the runner aggregates for each test.
+	// This field is optional. When omitted, node metrics are not collected.
+	NodeAgent *NodeAgent `json:"nodeAgent,omitempty"`
+
+	// Compatibility lists the oldest version of the worker images that can
+	// be paired with each range of versions of the driver image. Tests that
+	// pair a driver with older workers fail before their pods are created.
+	// This field is optional. When omitted, images are not checked.
+	Compatibility []DriverCompatibility `json:"compatibility,omitempty"`
+
+	// DashboardURLTemplate is a Go template for a link to the dashboard that
+	// graphs the results of a load test, which the controller renders into
+	// the DashboardURLAnnotation of each test once it terminates. The
+	// template is given the fields of DashboardURLData. This field is
+	// optional. When omitted, no link is added.
+	DashboardURLTemplate string `json:"dashboardURLTemplate,omitempty"`
+
+	// ResultDestinations declares named places where the results of load
+	// tests are stored, such as the BigQuery tables of the production and
+	// staging environments. Tests select one with the destination field of
+	// their results. This field is optional. When omitted, tests must set
+	// the BigQuery table and GCSPrefix of their results directly.
+	ResultDestinations []ResultDestination `json:"resultDestinations,omitempty"`
+
+	// Tenants declares the namespaces that run load tests in isolation from
+	// other namespaces, with node pools and defaults of their own. This
+	// field is optional. When omitted, all namespaces share the node pools
+	// and defaults.
+	Tenants []Tenant `json:"tenants,omitempty"`
 }
 
+// SchedulingPolicy determines the order in which pending load tests claim the
+// capacity of their pools.
+type SchedulingPolicy string
+
+const (
+	// FIFOSchedulingPolicy schedules tests as soon as there is capacity for
+	// them, which is effectively in the order they are reconciled.
+	FIFOSchedulingPolicy SchedulingPolicy = "FIFO"
+
+	// FairShareSchedulingPolicy makes pending tests from different owners
+	// take turns, so that a large batch of tests from one owner does not
+	// delay the tests of others. The owner of a test is the value of its
+	// OwnerLabel, or its namespace when the label is not set.
+	FairShareSchedulingPolicy SchedulingPolicy = "FairShare"
+)
+
 // Validate ensures that the required fields are present and an acceptable
 // value. If an issue is encountered, an error is returned. If the defaults are
 // valid, nil is returned.
@@ -72,11 +224,18 @@ func (d *Defaults) Validate() error {
 		return errors.New("missing image for driver container")
 	}
 
+	languageIndices := make(map[string]int)
 	for i, ld := range d.Languages {
 		if ld.Language == "" {
 			return errors.Errorf("language (index %d) unnamed", i)
 		}
 
+		canonical := languages.Canonical(ld.Language)
+		if j, ok := languageIndices[canonical]; ok {
+			return errors.Errorf("language %q (index %d) duplicates language %q (index %d)", ld.Language, i, d.Languages[j].Language, j)
+		}
+		languageIndices[canonical] = i
+
 		if ld.BuildImage == "" {
 			return errors.Errorf("language %q (index %d) missing image for build init container", ld.Language, i)
 		}
@@ -90,6 +249,90 @@ func (d *Defaults) Validate() error {
 		return errors.Errorf("killAfter must not be negative")
 	}
 
+	if d.ImagePullTimeoutSeconds < 0 {
+		return errors.Errorf("imagePullTimeoutSeconds must not be negative")
+	}
+
+	if d.TimeoutSeconds < 0 {
+		return errors.Errorf("timeoutSeconds must not be negative")
+	}
+
+	if d.WorkerSetupSeconds < 0 {
+		return errors.Errorf("workerSetupSeconds must not be negative")
+	}
+
+	if d.MaxContainerRestarts < 0 {
+		return errors.Errorf("maxContainerRestarts must not be negative")
+	}
+
+	if d.SchedulingSLOSeconds < 0 {
+		return errors.Errorf("schedulingSLOSeconds must not be negative")
+	}
+
+	if d.KeepRetentionSeconds < 0 {
+		return errors.Errorf("keepRetentionSeconds must not be negative")
+	}
+
+	for machineType, price := range d.MachineHourlyPrices {
+		if price < 0 {
+			return errors.Errorf("price of machine type %q must not be negative", machineType)
+		}
+	}
+
+	if d.TerminationGracePeriodSeconds < 0 {
+		return errors.Errorf("terminationGracePeriodSeconds must not be negative")
+	}
+
+	if d.TerminationGracePeriodSeconds > 0 && d.TerminationGracePeriodSeconds <= WorkerStopDelaySeconds {
+		return errors.Errorf("terminationGracePeriodSeconds must be longer than the worker stop delay of %ds", WorkerStopDelaySeconds)
+	}
+
+	if err := d.validateNodePools(); err != nil {
+		return err
+	}
+
+	if d.Artifacts != nil {
+		if err := d.Artifacts.validate(); err != nil {
+			return err
+		}
+	}
+
+	if d.Archive != nil {
+		if err := d.Archive.validate(); err != nil {
+			return err
+		}
+	}
+
+	if d.NodeAgent != nil {
+		if err := d.NodeAgent.validate(); err != nil {
+			return err
+		}
+	}
+
+	if d.DashboardURLTemplate != "" {
+		if _, err := ParseDashboardURL(d.DashboardURLTemplate); err != nil {
+			return err
+		}
+	}
+
+	if err := d.validateCompatibility(); err != nil {
+		return err
+	}
+
+	if err := d.validateResultDestinations(); err != nil {
+		return err
+	}
+
+	if err := d.validateTenants(); err != nil {
+		return err
+	}
+
+	switch d.SchedulingPolicy {
+	case "", FIFOSchedulingPolicy, FairShareSchedulingPolicy:
+	default:
+		return errors.Errorf("unknown scheduling policy %q", d.SchedulingPolicy)
+	}
+
 	return nil
 }
 
@@ -107,6 +350,18 @@ func (d *Defaults) SetLoadTestDefaults(test *grpcv1.LoadTest) error {
 		test.Namespace = d.ComponentNamespace
 	}
 
+	if testSpec.TimeoutSeconds == 0 {
+		timeout, err := d.TimeoutSecondsForTest(test)
+		if err != nil {
+			return errors.Wrap(err, "could not compute timeout")
+		}
+		testSpec.TimeoutSeconds = timeout
+	}
+
+	if err := d.setResultsDefaults(testSpec.Results); err != nil {
+		return errors.Wrap(err, "could not set defaults for results")
+	}
+
 	if err := d.setDriverDefaults(im, testSpec); err != nil {
 		return errors.Wrap(err, "could not set defaults for driver")
 	}
@@ -123,6 +378,12 @@ func (d *Defaults) SetLoadTestDefaults(test *grpcv1.LoadTest) error {
 		}
 	}
 
+	for i := range testSpec.Generators {
+		if err := d.setGeneratorDefaults(&testSpec.Generators[i]); err != nil {
+			return errors.Wrapf(err, "could not set defaults for generator at index %d", i)
+		}
+	}
+
 	return nil
 }
 
@@ -173,9 +434,13 @@ func (d *Defaults) setRunOrDefault(im *imageMap, language string, run []corev1.C
 }
 
 // setDriverDefaults sets default name, pool and container images for a driver.
-// An error is returned if a default could not be inferred for a field.
+// An error is returned if a default could not be inferred for a field. Tests
+// with generators are left without a driver, unless they specify one.
 func (d *Defaults) setDriverDefaults(im *imageMap, testSpec *grpcv1.LoadTestSpec) error {
 	if testSpec.Driver == nil {
+		if len(testSpec.Generators) > 0 {
+			return nil
+		}
 		testSpec.Driver = new(grpcv1.Driver)
 	}
 
@@ -190,7 +455,17 @@ func (d *Defaults) setDriverDefaults(im *imageMap, testSpec *grpcv1.LoadTestSpec
 	}
 
 	if driver.Run[0].Image == "" {
-		driver.Run[0].Image = d.DriverImage
+		if testSpec.Interop != nil {
+			// The driver of an interop test is the interop client, which
+			// runs in the same image as the workers of its language.
+			runImage, err := im.runImage(driver.Language)
+			if err != nil {
+				return errors.Wrap(err, "could not infer default run image for the interop client")
+			}
+			driver.Run[0].Image = runImage
+		} else {
+			driver.Run[0].Image = d.DriverImage
+		}
 	}
 
 	driver.Name = unwrapStrOrUUID(driver.Name)
@@ -245,6 +520,23 @@ func (d *Defaults) setServerDefaults(im *imageMap, server *grpcv1.Server) error
 	return nil
 }
 
+// setGeneratorDefaults sets a default name for a generator. Generators do not
+// have a language, so an error is returned if the image of the first run
+// container is unset.
+func (d *Defaults) setGeneratorDefaults(generator *grpcv1.Generator) error {
+	if generator == nil {
+		return errors.New("cannot set defaults on a nil generator")
+	}
+
+	generator.Name = unwrapStrOrUUID(generator.Name)
+
+	if len(generator.Run) == 0 || generator.Run[0].Image == "" {
+		return errors.New("generator must specify the image of its run container")
+	}
+
+	return nil
+}
+
 // unwrapStrOrUUID returns the string pointer if the pointer is not nil;
 // otherwise, it returns a pointer to a UUID string. This method can be used to
 // assign a unique name to a client, driver or server if one is not already set.
@@ -261,8 +553,9 @@ func unwrapStrOrUUID(namePtr *string) *string {
 // default container images.
 type LanguageDefault struct {
 	// Language uniquely identifies a programming language. When the
-	// system encounters this name, it will select the build image and
-	// run image as the defaults.
+	// system encounters this name, or an alias of the language known to
+	// the languages package, it will select the build image and run
+	// image as the defaults.
 	Language string `json:"language"`
 
 	// BuildImage specifies the default container image for building or
@@ -18,11 +18,14 @@ package config
 
 import (
 	"fmt"
+
+	"github.com/grpc/test-infra/languages"
 )
 
 // imageMap is a structure with a map that allows internal code to efficiently
-// find the default build and runtime container images for a language. It is
-// not intended to be a public API.
+// find the default build and runtime container images for a language. Names
+// are resolved to canonical names by the languages package, so a language can
+// be referred to by any of its aliases. It is not intended to be a public API.
 type imageMap struct {
 	m map[string]*LanguageDefault
 }
@@ -33,7 +36,7 @@ func newImageMap(lds []LanguageDefault) *imageMap {
 
 	for i := range lds {
 		ld := &lds[i]
-		m[ld.Language] = ld
+		m[languages.Canonical(ld.Language)] = ld
 	}
 
 	return &imageMap{m}
@@ -42,9 +45,9 @@ func newImageMap(lds []LanguageDefault) *imageMap {
 // buildImage returns the default build container image for a language. If the
 // language has no default, an error is returned.
 func (im *imageMap) buildImage(language string) (string, error) {
-	ld, ok := im.m[language]
+	ld, ok := im.m[languages.Canonical(language)]
 	if !ok {
-		return "", fmt.Errorf("cannot find image for language %q", language)
+		return "", missingImageError(language)
 	}
 
 	return ld.BuildImage, nil
@@ -53,10 +56,19 @@ func (im *imageMap) buildImage(language string) (string, error) {
 // runImage returns the default runtime container image for a language. If the
 // language has no default, an error is returned.
 func (im *imageMap) runImage(language string) (string, error) {
-	ld, ok := im.m[language]
+	ld, ok := im.m[languages.Canonical(language)]
 	if !ok {
-		return "", fmt.Errorf("cannot find image for language %q", language)
+		return "", missingImageError(language)
 	}
 
 	return ld.RunImage, nil
 }
+
+// missingImageError returns the error for a language without default images.
+// For known languages, the error tells how the code of the language is built.
+func missingImageError(language string) error {
+	if l, ok := languages.Lookup(language); ok && l.BuildHint != "" {
+		return fmt.Errorf("cannot find image for language %q, which is built with %s", language, l.BuildHint)
+	}
+	return fmt.Errorf("cannot find image for language %q", language)
+}
@@ -86,6 +86,12 @@ var _ = Describe("Defaults", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("returns an error when two language defaults name the same language", func() {
+			defaults.Languages[1].Language = "c++"
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("returns an error when a language default lacks a build image", func() {
 			defaults.Languages[1].BuildImage = ""
 			err := defaults.Validate()
@@ -98,12 +104,223 @@ var _ = Describe("Defaults", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("returns an error when the image pull timeout is negative", func() {
+			defaults.ImagePullTimeoutSeconds = -1
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the timeout or worker setup time is negative", func() {
+			defaults.TimeoutSeconds = -1
+			Expect(defaults.Validate()).ToNot(Succeed())
+
+			defaults.TimeoutSeconds = 0
+			defaults.WorkerSetupSeconds = -1
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when the max container restarts is negative", func() {
+			defaults.MaxContainerRestarts = -1
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the keep retention is negative", func() {
+			defaults.KeepRetentionSeconds = -1
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when a machine price is negative", func() {
+			defaults.MachineHourlyPrices = map[string]float64{"e2-standard-2": -1}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the termination grace period is negative", func() {
+			defaults.TerminationGracePeriodSeconds = -1
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the termination grace period does not exceed the worker stop delay", func() {
+			defaults.TerminationGracePeriodSeconds = WorkerStopDelaySeconds
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the scheduling policy is unknown", func() {
+			defaults.SchedulingPolicy = "Random"
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when artifacts do not specify a bucket", func() {
+			defaults.Artifacts = &Artifacts{RetentionDays: 30}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the artifact retention is negative", func() {
+			defaults.Artifacts = &Artifacts{Bucket: "artifacts", RetentionDays: -1}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the signed URL expiry is too long", func() {
+			defaults.Artifacts = &Artifacts{
+				Bucket:                 "artifacts",
+				SignedURLExpirySeconds: MaxSignedURLExpirySeconds + 1,
+				SigningKeyFile:         "key.json",
+			}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when URLs are signed without a signing key", func() {
+			defaults.Artifacts = &Artifacts{Bucket: "artifacts", SignedURLExpirySeconds: 3600}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns nil for valid artifacts settings", func() {
+			defaults.Artifacts = &Artifacts{
+				Bucket:                 "artifacts",
+				Prefix:                 "loadtests",
+				RetentionDays:          30,
+				SignedURLExpirySeconds: 3600,
+				SigningKeyFile:         "key.json",
+			}
+			err := defaults.Validate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error when the archive does nothing", func() {
+			defaults.Archive = &Archive{TimeoutSeconds: 60}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the notification URL is not http", func() {
+			defaults.Archive = &Archive{NotificationURL: "ftp://example.com/loadtests"}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns nil for valid archive settings", func() {
+			defaults.Archive = &Archive{
+				Directory:       "/archive",
+				NotificationURL: "https://example.com/loadtests",
+				TimeoutSeconds:  60,
+			}
+			err := defaults.Validate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error when the node agent has no image", func() {
+			defaults.NodeAgent = &NodeAgent{SampleIntervalSeconds: 5}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the node agent port is out of range", func() {
+			defaults.NodeAgent = &NodeAgent{Image: "node-agent", Port: 70000}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns nil for valid node agent settings", func() {
+			defaults.NodeAgent = &NodeAgent{
+				Image:                 "node-agent",
+				Port:                  9102,
+				SampleIntervalSeconds: 5,
+				PoolLabels:            []string{"pool-a"},
+			}
+			err := defaults.Validate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error when a compatibility entry has an invalid version", func() {
+			defaults.Compatibility = []DriverCompatibility{
+				{DriverVersion: "latest", MinWorkerVersion: "v1.40.0"},
+			}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when compatibility entries duplicate a driver version", func() {
+			defaults.Compatibility = []DriverCompatibility{
+				{DriverVersion: "v1.60", MinWorkerVersion: "v1.40.0"},
+				{DriverVersion: "v1.60.0", MinWorkerVersion: "v1.50.0"},
+			}
+			err := defaults.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("returns nil for valid defaults", func() {
 			err := defaults.Validate()
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
 
+	Describe("CheckCompatibility", func() {
+		var loadtest *grpcv1.LoadTest
+
+		BeforeEach(func() {
+			defaults.Compatibility = []DriverCompatibility{
+				{DriverVersion: "v1.50.0", MinWorkerVersion: "v1.40.0"},
+				{DriverVersion: "v1.60.0", MinWorkerVersion: "v1.55.0"},
+			}
+			loadtest = completeLoadTest.DeepCopy()
+			loadtest.Spec.Driver.Run[0].Image = "gcr.io/grpc-fake-project/test-infra/driver:v1.62.1"
+			loadtest.Spec.Servers[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.60.0"
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.55.0"
+		})
+
+		It("returns nil when the workers are compatible with the driver", func() {
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+		})
+
+		It("returns an error when a worker is older than the driver allows", func() {
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.54.2"
+			err := defaults.CheckCompatibility(loadtest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("go:v1.54.2"))
+		})
+
+		It("uses the entry of the newest driver version that is not newer than the driver", func() {
+			loadtest.Spec.Driver.Run[0].Image = "gcr.io/grpc-fake-project/test-infra/driver:v1.55.0"
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.40.0"
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+		})
+
+		It("does not check drivers older than every entry", func() {
+			loadtest.Spec.Driver.Run[0].Image = "gcr.io/grpc-fake-project/test-infra/driver:v1.30.0"
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.0.0"
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+		})
+
+		It("does not check images whose tags are not versions", func() {
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:latest"
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+
+			loadtest.Spec.Driver.Run[0].Image = "localhost:5000/driver"
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.0.0"
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+		})
+
+		It("reads the version of images with a digest from their tag", func() {
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.50.0@sha256:abcdef"
+			Expect(defaults.CheckCompatibility(loadtest)).ToNot(Succeed())
+		})
+
+		It("returns nil when no compatibility matrix is declared", func() {
+			defaults.Compatibility = nil
+			loadtest.Spec.Clients[0].Run[0].Image = "gcr.io/grpc-fake-project/test-infra/go:v1.0.0"
+			Expect(defaults.CheckCompatibility(loadtest)).To(Succeed())
+		})
+	})
+
 	Describe("SetLoadTestDefaults", func() {
 		var loadtest *grpcv1.LoadTest
 		var defaultImageMap *imageMap
@@ -113,6 +330,26 @@ var _ = Describe("Defaults", func() {
 			defaultImageMap = newImageMap(defaults.Languages)
 		})
 
+		Context("timeout", func() {
+			It("computes the timeout from the scenarios when unset", func() {
+				loadtest.Spec.TimeoutSeconds = 0
+				loadtest.Spec.ScenariosJSON = `{"scenarios": {"warmup_seconds": 5, "benchmark_seconds": 30}}`
+				defaults.WorkerSetupSeconds = 10
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(loadtest.Spec.TimeoutSeconds).To(BeEquivalentTo(5 + 30 + 2*10))
+			})
+
+			It("does not override the timeout when set", func() {
+				loadtest.Spec.TimeoutSeconds = 900
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(loadtest.Spec.TimeoutSeconds).To(BeEquivalentTo(900))
+			})
+		})
+
 		Context("metadata", func() {
 			It("sets default namespace when unset", func() {
 				loadtest.Namespace = ""
@@ -152,6 +389,17 @@ var _ = Describe("Defaults", func() {
 				Expect(loadtest.Spec.Driver).ToNot(BeNil())
 			})
 
+			It("does not set a driver when nil and the test has generators", func() {
+				loadtest.Spec.Driver = nil
+				loadtest.Spec.Generators = []grpcv1.Generator{{
+					Run: []corev1.Container{{Image: "ghz"}},
+				}}
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(loadtest.Spec.Driver).To(BeNil())
+			})
+
 			It("does not override driver when set", func() {
 				driver := new(grpcv1.Driver)
 				loadtest.Spec.Driver = driver
@@ -213,6 +461,23 @@ var _ = Describe("Defaults", func() {
 				Expect(*driver.Build.Image).To(Equal(expectedBuildImage))
 			})
 
+			It("sets missing image for build init container for a language alias", func() {
+				build := new(grpcv1.Build)
+				build.Command = []string{"bazel"}
+
+				driver.Language = "c++"
+				driver.Build = build
+
+				expectedBuildImage, err := defaultImageMap.buildImage("cxx")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(driver.Build.Image).ToNot(BeNil())
+				Expect(*driver.Build.Image).To(Equal(expectedBuildImage))
+			})
+
 			It("errors if image for build init container cannot be inferred", func() {
 				build := new(grpcv1.Build)
 				build.Image = nil // no explicit image
@@ -251,6 +516,25 @@ var _ = Describe("Defaults", func() {
 				Expect(driver.Run[0].Image).To(Equal(defaults.DriverImage))
 			})
 
+			It("sets the run image of its language for interop tests", func() {
+				loadtest.Spec.Interop = &grpcv1.Interop{TestCases: []string{"empty_unary"}}
+				driver.Language = "go"
+				driver.Run[0].Image = ""
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(driver.Run[0].Image).To(Equal("gcr.io/grpc-fake-project/test-infra/go"))
+			})
+
+			It("errors if the run image for an interop test cannot be inferred", func() {
+				loadtest.Spec.Interop = &grpcv1.Interop{TestCases: []string{"empty_unary"}}
+				driver.Language = "fortran"
+				driver.Run[0].Image = ""
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).To(HaveOccurred())
+			})
+
 			It("sets run container if the run container is nil", func() {
 				driver.Run = nil
 				err := defaults.SetLoadTestDefaults(loadtest)
@@ -410,6 +694,27 @@ var _ = Describe("Defaults", func() {
 			})
 		})
 
+		Context("generator", func() {
+			BeforeEach(func() {
+				loadtest.Spec.Generators = []grpcv1.Generator{{
+					Run: []corev1.Container{{Image: "ghz"}},
+				}}
+			})
+
+			It("sets default name when unspecified", func() {
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(loadtest.Spec.Generators[0].Name).ToNot(BeNil())
+			})
+
+			It("errors if the run container does not set an image", func() {
+				loadtest.Spec.Generators[0].Run[0].Image = ""
+
+				err := defaults.SetLoadTestDefaults(loadtest)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		Context("client", func() {
 			var client *grpcv1.Client
 
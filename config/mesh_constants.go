@@ -0,0 +1,41 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+const (
+	// IstioInjectLabel is the key for the label that enables or disables the
+	// injection of the Istio sidecar into a pod.
+	IstioInjectLabel = "sidecar.istio.io/inject"
+
+	// IstioProxyConfigAnnotation is the key for the annotation that overrides
+	// the configuration of the Istio sidecar of a pod.
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+
+	// IstioExcludeInboundPortsAnnotation is the key for the annotation with a
+	// comma-separated list of ports whose inbound traffic bypasses the Istio
+	// sidecar of a pod.
+	IstioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
+
+	// LinkerdInjectAnnotation is the key for the annotation that enables or
+	// disables the injection of the Linkerd proxy into a pod.
+	LinkerdInjectAnnotation = "linkerd.io/inject"
+
+	// LinkerdSkipInboundPortsAnnotation is the key for the annotation with a
+	// comma-separated list of ports whose inbound traffic bypasses the Linkerd
+	// proxy of a pod.
+	LinkerdSkipInboundPortsAnnotation = "config.linkerd.io/skip-inbound-ports"
+)
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultNodeAgentPort is the port that the node agent serves its samples on
+// when the node agent settings do not specify a port.
+const DefaultNodeAgentPort = 9102
+
+// DefaultNodeAgentSampleIntervalSeconds is the time between samples taken by
+// the node agent when the node agent settings do not specify an interval.
+const DefaultNodeAgentSampleIntervalSeconds = 5
+
+// NodeAgent configures a DaemonSet that samples system metrics of each node,
+// such as softirqs, context switches and NIC counters. The controller
+// creates the DaemonSet in the component namespace, and the runner aggregates
+// the samples from the nodes of each test over its benchmark window, to help
+// explain variance between runs.
+type NodeAgent struct {
+	// Image is the container image of the node agent.
+	Image string `json:"image"`
+
+	// Port is the port that the node agent serves its samples on. Since the
+	// agent uses the network of the node, the port must be free on every
+	// node. This field is optional. When omitted or zero,
+	// DefaultNodeAgentPort is used.
+	Port int32 `json:"port,omitempty"`
+
+	// SampleIntervalSeconds is the time between samples. This field is
+	// optional. When omitted or zero, DefaultNodeAgentSampleIntervalSeconds
+	// is used.
+	SampleIntervalSeconds int32 `json:"sampleIntervalSeconds,omitempty"`
+
+	// PoolLabels lists the labels that identify the nodes the agent runs
+	// on. A node matches if any of the labels is set to "true" on it. This
+	// field is optional. When omitted, the agent runs on every node.
+	PoolLabels []string `json:"poolLabels,omitempty"`
+}
+
+// AgentPort returns the port that the node agent serves its samples on.
+func (n *NodeAgent) AgentPort() int32 {
+	if n.Port == 0 {
+		return DefaultNodeAgentPort
+	}
+	return n.Port
+}
+
+// SampleInterval returns the time between samples taken by the node agent.
+func (n *NodeAgent) SampleInterval() time.Duration {
+	if n.SampleIntervalSeconds == 0 {
+		return DefaultNodeAgentSampleIntervalSeconds * time.Second
+	}
+	return time.Duration(n.SampleIntervalSeconds) * time.Second
+}
+
+// validate returns an error if the node agent settings are incomplete or
+// contain values out of range.
+func (n *NodeAgent) validate() error {
+	if n.Image == "" {
+		return errors.New("nodeAgent must specify an image")
+	}
+
+	if n.Port < 0 || n.Port > 65535 {
+		return errors.Errorf("nodeAgent port %d is out of range", n.Port)
+	}
+
+	if n.SampleIntervalSeconds < 0 {
+		return errors.New("nodeAgent sampleIntervalSeconds must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// NodePool declares a pool of nodes that runs load tests. When node pools are
+// declared in the defaults, the controller uses them to validate load tests
+// and to determine the capacity of each pool, instead of counting the nodes
+// with a pool label.
+type NodePool struct {
+	// Name is the name of the pool. It must match the value of the pool label
+	// on the nodes of the pool.
+	Name string `json:"name"`
+
+	// Purpose is a human legible description of what the pool is used for.
+	// This field is informational.
+	Purpose string `json:"purpose,omitempty"`
+
+	// MachineType is the type of machine of the nodes in the pool. This field
+	// is informational.
+	MachineType string `json:"machineType,omitempty"`
+
+	// Capacity is the number of nodes in the pool.
+	Capacity int `json:"capacity"`
+
+	// Reserved is the number of nodes in the pool that are held back from
+	// load tests. It allows part of a pool to be set aside, for example for
+	// manual experiments, without resizing the pool. This field is optional.
+	Reserved int `json:"reserved,omitempty"`
+
+	// AllowedKinds lists the kinds of tests that may run in the pool. The kind
+	// of a test is the value of its TestKindLabel. When omitted, tests of any
+	// kind may run in the pool.
+	AllowedKinds []string `json:"allowedKinds,omitempty"`
+
+	// DefaultFor lists the roles (client, driver or server) of components
+	// that run in the pool when they do not specify a pool. At most one pool
+	// may be the default for each role among the pools that are not dedicated
+	// to a tenant, and among the pools of each tenant.
+	DefaultFor []string `json:"defaultFor,omitempty"`
+}
+
+// Available returns the number of nodes in the pool that may be used by load
+// tests, which is the capacity of the pool minus the reserved nodes.
+func (p *NodePool) Available() int {
+	return p.Capacity - p.Reserved
+}
+
+// AllowsKind returns true if tests of the given kind may run in the pool.
+func (p *NodePool) AllowsKind(kind string) bool {
+	if len(p.AllowedKinds) == 0 {
+		return true
+	}
+	for _, allowedKind := range p.AllowedKinds {
+		if allowedKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NodePoolForName returns the declared node pool with the given name, or nil
+// if there is no such pool.
+func (d *Defaults) NodePoolForName(name string) *NodePool {
+	for i := range d.NodePools {
+		if d.NodePools[i].Name == name {
+			return &d.NodePools[i]
+		}
+	}
+	return nil
+}
+
+// DefaultNodePoolName returns the name of the declared node pool that is the
+// default for components with the given role, or an empty string if no pool is
+// the default for the role.
+func (d *Defaults) DefaultNodePoolName(role string) string {
+	for _, pool := range d.NodePools {
+		for _, defaultRole := range pool.DefaultFor {
+			if defaultRole == role {
+				return pool.Name
+			}
+		}
+	}
+	return ""
+}
+
+// TestKind returns the kind of a load test, which is the value of its
+// TestKindLabel. An empty string is returned if the label is not set.
+func TestKind(test *grpcv1.LoadTest) string {
+	return test.Labels[TestKindLabel]
+}
+
+// validateNodePools ensures that the declared node pools are well-formed. If an
+// issue is encountered, an error is returned.
+func (d *Defaults) validateNodePools() error {
+	names := make(map[string]bool)
+	defaults := make(map[string]string)
+
+	for i, pool := range d.NodePools {
+		if pool.Name == "" {
+			return errors.Errorf("node pool (index %d) unnamed", i)
+		}
+
+		if names[pool.Name] {
+			return errors.Errorf("node pool %q declared more than once", pool.Name)
+		}
+		names[pool.Name] = true
+
+		if pool.Capacity <= 0 {
+			return errors.Errorf("node pool %q must have a positive capacity", pool.Name)
+		}
+
+		if pool.Reserved < 0 || pool.Reserved > pool.Capacity {
+			return errors.Errorf("node pool %q must reserve between 0 and %d nodes", pool.Name, pool.Capacity)
+		}
+
+		for _, role := range pool.DefaultFor {
+			if role != ClientRole && role != DriverRole && role != ServerRole {
+				return errors.Errorf("node pool %q is the default for unknown role %q", pool.Name, role)
+			}
+
+			// Pools dedicated to a tenant are only defaults for the
+			// tests of the tenant, which are validated separately.
+			if d.TenantForPool(pool.Name) != nil {
+				continue
+			}
+
+			if other, ok := defaults[role]; ok {
+				return errors.Errorf("node pools %q and %q are both the default for role %q", other, pool.Name, role)
+			}
+			defaults[role] = pool.Name
+		}
+	}
+
+	return nil
+}
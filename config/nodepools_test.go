@@ -0,0 +1,152 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("NodePools", func() {
+	var defaults *Defaults
+
+	BeforeEach(func() {
+		defaults = &Defaults{
+			CloneImage:  "gcr.io/grpc-fake-project/test-infra/clone",
+			ReadyImage:  "gcr.io/grpc-fake-project/test-infra/ready",
+			DriverImage: "gcr.io/grpc-fake-project/test-infra/driver",
+			NodePools: []NodePool{
+				{
+					Name:       "drivers",
+					Capacity:   8,
+					DefaultFor: []string{DriverRole},
+				},
+				{
+					Name:         "workers-8core",
+					MachineType:  "e2-standard-8",
+					Capacity:     8,
+					Reserved:     2,
+					AllowedKinds: []string{"adhoc"},
+					DefaultFor:   []string{ClientRole, ServerRole},
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("returns nil for valid node pools", func() {
+			Expect(defaults.Validate()).To(Succeed())
+		})
+
+		It("returns an error when a node pool is unnamed", func() {
+			defaults.NodePools[0].Name = ""
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a node pool is declared more than once", func() {
+			defaults.NodePools[1].Name = defaults.NodePools[0].Name
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a node pool has no capacity", func() {
+			defaults.NodePools[0].Capacity = 0
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a node pool reserves more nodes than its capacity", func() {
+			defaults.NodePools[1].Reserved = 9
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a node pool reserves a negative number of nodes", func() {
+			defaults.NodePools[1].Reserved = -1
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a node pool is the default for an unknown role", func() {
+			defaults.NodePools[0].DefaultFor = []string{"observer"}
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when two node pools are the default for the same role", func() {
+			defaults.NodePools[0].DefaultFor = []string{DriverRole, ServerRole}
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("NodePoolForName", func() {
+		It("returns the node pool with the name", func() {
+			pool := defaults.NodePoolForName("workers-8core")
+			Expect(pool).ToNot(BeNil())
+			Expect(pool.MachineType).To(Equal("e2-standard-8"))
+		})
+
+		It("returns nil when no node pool has the name", func() {
+			Expect(defaults.NodePoolForName("workers-32core")).To(BeNil())
+		})
+	})
+
+	Describe("DefaultNodePoolName", func() {
+		It("returns the name of the default node pool for each role", func() {
+			Expect(defaults.DefaultNodePoolName(ClientRole)).To(Equal("workers-8core"))
+			Expect(defaults.DefaultNodePoolName(DriverRole)).To(Equal("drivers"))
+			Expect(defaults.DefaultNodePoolName(ServerRole)).To(Equal("workers-8core"))
+		})
+
+		It("returns an empty string when no node pool is the default for a role", func() {
+			defaults.NodePools[0].DefaultFor = nil
+			Expect(defaults.DefaultNodePoolName(DriverRole)).To(BeEmpty())
+		})
+	})
+
+	Describe("Available", func() {
+		It("excludes reserved nodes", func() {
+			Expect(defaults.NodePools[1].Available()).To(Equal(6))
+		})
+	})
+
+	Describe("AllowsKind", func() {
+		It("allows any kind when no kinds are listed", func() {
+			Expect(defaults.NodePools[0].AllowsKind("")).To(BeTrue())
+			Expect(defaults.NodePools[0].AllowsKind("ci")).To(BeTrue())
+		})
+
+		It("allows only the listed kinds", func() {
+			Expect(defaults.NodePools[1].AllowsKind("adhoc")).To(BeTrue())
+			Expect(defaults.NodePools[1].AllowsKind("ci")).To(BeFalse())
+			Expect(defaults.NodePools[1].AllowsKind("")).To(BeFalse())
+		})
+	})
+
+	Describe("TestKind", func() {
+		It("returns the value of the kind label", func() {
+			test := &grpcv1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{TestKindLabel: "adhoc"},
+				},
+			}
+			Expect(TestKind(test)).To(Equal("adhoc"))
+		})
+
+		It("returns an empty string when the kind label is not set", func() {
+			Expect(TestKind(&grpcv1.LoadTest{})).To(BeEmpty())
+		})
+	})
+})
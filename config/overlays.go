@@ -0,0 +1,170 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// overlayListKeys maps the fields of the defaults that contain lists of
+// objects to the field that identifies each object. When an overlay sets one
+// of these lists, its entries are merged with the entries of the base that
+// have the same identifier, and entries with new identifiers are appended.
+// Other lists in an overlay replace the list in the base.
+var overlayListKeys = map[string]string{
+	"languages": "language",
+	"nodePools": "name",
+	"tenants":   "namespace",
+}
+
+// LoadDefaultsFiles reads the defaults from a base file followed by any number
+// of overlay files, and returns the validated result. Each overlay takes
+// precedence over the files before it:
+//
+//   - Objects, such as defaultPoolLabels, are merged field by field.
+//   - Languages, node pools and tenants are merged with the entries of the
+//     same language, name or namespace, and new entries are appended.
+//   - Other values, including other lists, replace the value in the base.
+//   - A field set to null in an overlay is removed from the base.
+//
+// Overlays are decoded strictly, so that misspelled fields are reported
+// instead of being ignored. Only the merged result is validated, so overlays
+// only need to contain the fields that they override.
+func LoadDefaultsFiles(fileNames []string) (*Defaults, error) {
+	if len(fileNames) == 0 {
+		return nil, errors.New("no defaults files provided")
+	}
+
+	var merged map[string]interface{}
+	for i, fileName := range fileNames {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read defaults file %q", fileName)
+		}
+
+		if i > 0 {
+			if err := yaml.UnmarshalStrict(data, new(Defaults)); err != nil {
+				return nil, errors.Wrapf(err, "could not parse defaults overlay %q", fileName)
+			}
+		}
+
+		var layer map[string]interface{}
+		if err := yaml.Unmarshal(data, &layer); err != nil {
+			return nil, errors.Wrapf(err, "could not parse defaults file %q", fileName)
+		}
+
+		if merged == nil {
+			merged = layer
+			if merged == nil {
+				merged = make(map[string]interface{})
+			}
+			continue
+		}
+		if err := mergeObjects(merged, layer, ""); err != nil {
+			return nil, errors.Wrapf(err, "could not apply defaults overlay %q", fileName)
+		}
+	}
+
+	mergedJSON, err := json.Marshal(merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode merged defaults")
+	}
+	defaults := new(Defaults)
+	if err := json.Unmarshal(mergedJSON, defaults); err != nil {
+		return nil, errors.Wrap(err, "could not decode merged defaults")
+	}
+	if err := defaults.Validate(); err != nil {
+		return nil, errors.Wrap(err, "merged defaults are invalid")
+	}
+	return defaults, nil
+}
+
+// mergeObjects merges the fields of an overlay into a base object. The path
+// of the object within the defaults is used in error messages.
+func mergeObjects(base, overlay map[string]interface{}, path string) error {
+	for key, value := range overlay {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if baseObject, ok := base[key].(map[string]interface{}); ok {
+				if err := mergeObjects(baseObject, v, fieldPath); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if idKey, ok := overlayListKeys[fieldPath]; ok {
+				baseList, _ := base[key].([]interface{})
+				mergedList, err := mergeKeyedLists(baseList, v, idKey, fieldPath)
+				if err != nil {
+					return err
+				}
+				base[key] = mergedList
+				continue
+			}
+		}
+		base[key] = value
+	}
+	return nil
+}
+
+// mergeKeyedLists merges the entries of an overlay list into a base list.
+// Entries are matched by the value of their identifying field.
+func mergeKeyedLists(base, overlay []interface{}, idKey, path string) ([]interface{}, error) {
+	merged := append([]interface{}{}, base...)
+	for i, entry := range overlay {
+		overlayEntry, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] is not an object", path, i)
+		}
+		id, ok := overlayEntry[idKey]
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] is missing %q", path, i, idKey)
+		}
+
+		found := false
+		for _, baseEntry := range merged {
+			baseObject, ok := baseEntry.(map[string]interface{})
+			if !ok || baseObject[idKey] != id {
+				continue
+			}
+			if err := mergeObjects(baseObject, overlayEntry, fmt.Sprintf("%s[%v]", path, id)); err != nil {
+				return nil, err
+			}
+			found = true
+			break
+		}
+		if !found {
+			merged = append(merged, overlayEntry)
+		}
+	}
+	return merged, nil
+}
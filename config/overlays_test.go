@@ -0,0 +1,191 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const baseDefaultsYAML = `
+defaultPoolLabels:
+  client: default-client-pool
+  driver: default-driver-pool
+  server: default-server-pool
+cloneImage: example.registry/clone:v1
+readyImage: example.registry/ready:v1
+driverImage: example.registry/driver:v1
+killAfter: 20
+priorityClassName: load-tests
+terminationGracePeriodSeconds: 1000000
+languages:
+- language: cxx
+  buildImage: example.registry/bazel:v1
+  runImage: example.registry/cxx:v1
+- language: go
+  buildImage: golang:1.20
+  runImage: example.registry/go:v1
+nodePools:
+- name: workers-8core
+  capacity: 10
+`
+
+var _ = Describe("LoadDefaultsFiles", func() {
+	var dir string
+
+	writeFile := func(name, content string) string {
+		fileName := filepath.Join(dir, name)
+		Expect(os.WriteFile(fileName, []byte(content), 0644)).To(Succeed())
+		return fileName
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "defaults")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("loads a single file", func() {
+		defaults, err := LoadDefaultsFiles([]string{writeFile("base.yaml", baseDefaultsYAML)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.CloneImage).To(Equal("example.registry/clone:v1"))
+		Expect(defaults.TerminationGracePeriodSeconds).To(BeEquivalentTo(1000000))
+		Expect(defaults.Languages).To(HaveLen(2))
+	})
+
+	It("gives precedence to later overlays", func() {
+		defaults, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "cloneImage: dev.registry/clone:v2\nkillAfter: 5\n"),
+			writeFile("local.yaml", "cloneImage: local.registry/clone:v3\n"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.CloneImage).To(Equal("local.registry/clone:v3"))
+		Expect(defaults.KillAfter).To(BeEquivalentTo(5))
+		Expect(defaults.ReadyImage).To(Equal("example.registry/ready:v1"))
+	})
+
+	It("merges objects field by field", func() {
+		defaults, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "defaultPoolLabels:\n  client: dev-pool\n"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.DefaultPoolLabels).To(Equal(&PoolLabelMap{
+			Client: "dev-pool",
+			Driver: "default-driver-pool",
+			Server: "default-server-pool",
+		}))
+	})
+
+	It("merges languages and node pools by name", func() {
+		defaults, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", `
+languages:
+- language: go
+  runImage: dev.registry/go:v2
+- language: java
+  buildImage: gradle:jdk8
+  runImage: dev.registry/java:v2
+nodePools:
+- name: workers-8core
+  capacity: 2
+`),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.Languages).To(Equal([]LanguageDefault{
+			{Language: "cxx", BuildImage: "example.registry/bazel:v1", RunImage: "example.registry/cxx:v1"},
+			{Language: "go", BuildImage: "golang:1.20", RunImage: "dev.registry/go:v2"},
+			{Language: "java", BuildImage: "gradle:jdk8", RunImage: "dev.registry/java:v2"},
+		}))
+		Expect(defaults.NodePools).To(HaveLen(1))
+		Expect(defaults.NodePools[0].Capacity).To(Equal(2))
+	})
+
+	It("merges tenants by namespace", func() {
+		defaults, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML+`
+tenants:
+- namespace: team-a
+  overrides:
+    killAfter: 30
+- namespace: team-b
+`),
+			writeFile("dev.yaml", `
+tenants:
+- namespace: team-a
+  overrides:
+    keepFirstFailures: true
+- namespace: team-c
+`),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.Tenants).To(HaveLen(3))
+		Expect(defaults.Tenants[0].Namespace).To(Equal("team-a"))
+		Expect(defaults.Tenants[0].Overrides).To(HaveKeyWithValue("killAfter", BeNumerically("==", 30)))
+		Expect(defaults.Tenants[0].Overrides).To(HaveKeyWithValue("keepFirstFailures", true))
+		Expect(defaults.Tenants[2].Namespace).To(Equal("team-c"))
+	})
+
+	It("removes fields set to null", func() {
+		defaults, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "priorityClassName: null\n"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(defaults.PriorityClassName).To(BeEmpty())
+	})
+
+	It("rejects unknown fields in overlays", func() {
+		_, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "cloneImag: dev.registry/clone:v2\n"),
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("dev.yaml"))
+	})
+
+	It("rejects language overlays without a language", func() {
+		_, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "languages:\n- runImage: dev.registry/go:v2\n"),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("validates the merged defaults", func() {
+		_, err := LoadDefaultsFiles([]string{
+			writeFile("base.yaml", baseDefaultsYAML),
+			writeFile("dev.yaml", "cloneImage: null\n"),
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("merged defaults are invalid"))
+	})
+
+	It("returns an error when no files are given", func() {
+		_, err := LoadDefaultsFiles(nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -17,6 +17,25 @@ limitations under the License.
 package config
 
 const (
+	// CSDSPortEnv specifies the name of the env variable that holds the port
+	// where a proxyless client serves the Client Status Discovery Service. It
+	// is set on the run container and on the xds-server container of the
+	// client.
+	CSDSPortEnv = "CSDS_PORT"
+
+	// DefaultXdsNodeID is the node ID that the xds-server container uses
+	// to serve its configuration when neither the -node-ID flag nor the
+	// $XDS_NODE_ID env variable is set.
+	DefaultXdsNodeID = "test_id"
+
+	// DefaultXdsServerPort is the port that the xds-server container listens
+	// on for xDS clients when the -xds-server-port flag is not set.
+	DefaultXdsServerPort = 18000
+
+	// EnvoyAdminPort is the port where the Envoy sidecar of a proxied PSM
+	// test serves its admin endpoint.
+	EnvoyAdminPort = 9901
+
 	// ServerUpdatePort is the port on the xDS server to listen to
 	// configuration for PSM test only.
 	ServerUpdatePort = 18005
@@ -28,4 +47,20 @@ const (
 	// SidecarContainerName holds the name of the sidecar
 	// container for a proxied PSM test only.
 	SidecarContainerName = "sidecar"
+
+	// XdsBootstrapConfigEnv specifies the name of the env variable that holds
+	// the contents of the xDS bootstrap file of a proxyless client.
+	XdsBootstrapConfigEnv = "GRPC_XDS_BOOTSTRAP_CONFIG"
+
+	// XdsNodeIDEnv specifies the name of the env variable that holds the
+	// node IDs that the xds-server container serves its configuration to,
+	// as a comma-separated list. The first ID is also the node ID of the
+	// client. It is set by the controller on the xds-server and sidecar
+	// containers of each client, so that concurrent tests do not share
+	// configuration.
+	XdsNodeIDEnv = "XDS_NODE_ID"
+
+	// XdsBootstrapEnv specifies the name of the env variable that holds the
+	// path of the xDS bootstrap file of a proxyless client.
+	XdsBootstrapEnv = "GRPC_XDS_BOOTSTRAP"
 )
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+// gcsPrefixPattern matches the Cloud Storage URIs accepted as the GCSPrefix
+// of the results of a load test.
+var gcsPrefixPattern = regexp.MustCompile(`^gs://[a-z0-9][-_.a-z0-9]*[a-z0-9](/.*)?$`)
+
+// errUnknownResultDestination is the base error when a load test names a
+// result destination that is not declared in the defaults.
+var errUnknownResultDestination = errors.New("unknown result destination")
+
+// ResultDestination declares a named place where the results of load tests
+// are stored, such as the BigQuery table of the production or staging
+// environment. Tests refer to a destination by name, instead of repeating
+// the table in each test.
+type ResultDestination struct {
+	// Name is the name that load tests use to select the destination.
+	Name string `json:"name"`
+
+	// BigQueryTable names the dataset and table where the results are
+	// stored. This field is optional. When omitted, results are not saved
+	// to BigQuery.
+	BigQueryTable string `json:"bigQueryTable,omitempty"`
+
+	// GCSPrefix is a Cloud Storage URI, such as gs://bucket/path, under
+	// which the raw JSON output of the driver is stored. This field is
+	// optional. When omitted, the raw output is not stored.
+	GCSPrefix string `json:"gcsPrefix,omitempty"`
+}
+
+// ResultDestinationForName returns the declared result destination with the
+// given name, or nil if there is no such destination.
+func (d *Defaults) ResultDestinationForName(name string) *ResultDestination {
+	for i := range d.ResultDestinations {
+		if d.ResultDestinations[i].Name == name {
+			return &d.ResultDestinations[i]
+		}
+	}
+	return nil
+}
+
+// resultDestinationForResults returns the declared result destination that
+// stores results in the same BigQuery table and under the same GCSPrefix as
+// the results of a load test, or nil if there is no such destination.
+func (d *Defaults) resultDestinationForResults(results *grpcv1.Results) *ResultDestination {
+	for i := range d.ResultDestinations {
+		destination := &d.ResultDestinations[i]
+		if destination.BigQueryTable == stringValue(results.BigQueryTable) && destination.GCSPrefix == stringValue(results.GCSPrefix) {
+			return destination
+		}
+	}
+	return nil
+}
+
+// setResultsDefaults fills the BigQuery table and GCSPrefix of the results of
+// a load test from the result destination that the test names. Values set on
+// the test take precedence over the destination. An error wrapping
+// errUnknownResultDestination is returned if the destination is not declared.
+//
+// Tests written before destinations were declared set the BigQuery table
+// directly. Such a test is linked to the destination that stores results in
+// the same places, so it can be found by the name of the destination. Since
+// the destination matches the test exactly, linking the test does not change
+// where its results are stored.
+func (d *Defaults) setResultsDefaults(results *grpcv1.Results) error {
+	if results == nil {
+		return nil
+	}
+
+	if results.Destination == nil {
+		if results.BigQueryTable == nil {
+			return nil
+		}
+		if destination := d.resultDestinationForResults(results); destination != nil {
+			results.Destination = optional.StringPtr(destination.Name)
+		}
+		return nil
+	}
+
+	destination := d.ResultDestinationForName(*results.Destination)
+	if destination == nil {
+		return errors.Wrapf(errUnknownResultDestination, "no result destination is named %q", *results.Destination)
+	}
+	if results.BigQueryTable == nil && destination.BigQueryTable != "" {
+		results.BigQueryTable = optional.StringPtr(destination.BigQueryTable)
+	}
+	if results.GCSPrefix == nil && destination.GCSPrefix != "" {
+		results.GCSPrefix = optional.StringPtr(destination.GCSPrefix)
+	}
+	return nil
+}
+
+// stringValue returns the string that a pointer points to, or an empty string
+// if the pointer is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// validateResultDestinations ensures that the declared result destinations
+// have unique names and well-formed values. If an issue is encountered, an
+// error is returned.
+func (d *Defaults) validateResultDestinations() error {
+	names := make(map[string]bool)
+
+	for i, destination := range d.ResultDestinations {
+		if destination.Name == "" {
+			return errors.Errorf("result destination (index %d) has no name", i)
+		}
+
+		if names[destination.Name] {
+			return errors.Errorf("result destination %q declared more than once", destination.Name)
+		}
+		names[destination.Name] = true
+
+		if destination.GCSPrefix != "" && !gcsPrefixPattern.MatchString(destination.GCSPrefix) {
+			return errors.Errorf("result destination %q has invalid gcsPrefix %q", destination.Name, destination.GCSPrefix)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("Result destinations", func() {
+	var defaults *Defaults
+
+	BeforeEach(func() {
+		defaults = &Defaults{
+			CloneImage:  "gcr.io/grpc-fake-project/test-infra/clone",
+			ReadyImage:  "gcr.io/grpc-fake-project/test-infra/ready",
+			DriverImage: "gcr.io/grpc-fake-project/test-infra/driver",
+			Languages: []LanguageDefault{
+				{
+					Language:   "cxx",
+					BuildImage: "l.gcr.io/google/bazel:latest",
+					RunImage:   "gcr.io/grpc-fake-project/test-infra/cxx",
+				},
+				{
+					Language:   "go",
+					BuildImage: "golang:1.20",
+					RunImage:   "gcr.io/grpc-fake-project/test-infra/go",
+				},
+				{
+					Language:   "java",
+					BuildImage: "java:jdk8",
+					RunImage:   "gcr.io/grpc-fake-project/test-infra/java",
+				},
+			},
+			ResultDestinations: []ResultDestination{
+				{
+					Name:          "prod",
+					BigQueryTable: "e2e_benchmarks.prod_results",
+					GCSPrefix:     "gs://grpc-fake-results/prod",
+				},
+				{
+					Name:          "staging",
+					BigQueryTable: "e2e_benchmarks.staging_results",
+				},
+				{
+					Name: "local",
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("accepts valid destinations", func() {
+			Expect(defaults.Validate()).To(Succeed())
+		})
+
+		It("returns an error when a destination has no name", func() {
+			defaults.ResultDestinations[2].Name = ""
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a destination is declared twice", func() {
+			defaults.ResultDestinations[1].Name = "prod"
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when the GCSPrefix of a destination is invalid", func() {
+			defaults.ResultDestinations[0].GCSPrefix = "s3://grpc-fake-results"
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("SetLoadTestDefaults", func() {
+		var loadtest *grpcv1.LoadTest
+
+		BeforeEach(func() {
+			loadtest = completeLoadTest.DeepCopy()
+			loadtest.Spec.Results = &grpcv1.Results{}
+		})
+
+		It("fills the results from the named destination", func() {
+			loadtest.Spec.Results.Destination = optional.StringPtr("prod")
+
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest.Spec.Results.BigQueryTable).To(Equal(optional.StringPtr("e2e_benchmarks.prod_results")))
+			Expect(loadtest.Spec.Results.GCSPrefix).To(Equal(optional.StringPtr("gs://grpc-fake-results/prod")))
+		})
+
+		It("does not override the values set on the test", func() {
+			loadtest.Spec.Results.Destination = optional.StringPtr("prod")
+			loadtest.Spec.Results.BigQueryTable = optional.StringPtr("e2e_benchmarks.experiment")
+
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest.Spec.Results.BigQueryTable).To(Equal(optional.StringPtr("e2e_benchmarks.experiment")))
+			Expect(loadtest.Spec.Results.GCSPrefix).To(Equal(optional.StringPtr("gs://grpc-fake-results/prod")))
+		})
+
+		It("stores results nowhere else for a local destination", func() {
+			loadtest.Spec.Results.Destination = optional.StringPtr("local")
+
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest.Spec.Results.BigQueryTable).To(BeNil())
+			Expect(loadtest.Spec.Results.GCSPrefix).To(BeNil())
+		})
+
+		It("returns an error when the destination is unknown", func() {
+			loadtest.Spec.Results.Destination = optional.StringPtr("qa")
+
+			err := defaults.SetLoadTestDefaults(loadtest)
+			Expect(errors.Is(err, errUnknownResultDestination)).To(BeTrue())
+		})
+
+		It("links a test that sets a table to the matching destination", func() {
+			loadtest.Spec.Results.BigQueryTable = optional.StringPtr("e2e_benchmarks.staging_results")
+
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest.Spec.Results.Destination).To(Equal(optional.StringPtr("staging")))
+			Expect(loadtest.Spec.Results.GCSPrefix).To(BeNil())
+
+			// Applying the defaults again leaves the test unchanged.
+			linked := loadtest.DeepCopy()
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest).To(Equal(linked))
+		})
+
+		It("does not link a test that stores results in other places", func() {
+			loadtest.Spec.Results.BigQueryTable = optional.StringPtr("e2e_benchmarks.prod_results")
+
+			Expect(defaults.SetLoadTestDefaults(loadtest)).To(Succeed())
+			Expect(loadtest.Spec.Results.Destination).To(BeNil())
+			Expect(loadtest.Spec.Results.GCSPrefix).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,28 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tenant declares the permissions that the runner needs in the
+// namespace of a tenant. The Role in config/tenant is generated from the RBAC
+// markers in this package, and is bound to the service account of the runner
+// by the manifests in the same directory. Use kustomize to set the namespace
+// of the manifests to the namespace of the tenant.
+package tenant
+
+// +kubebuilder:rbac:groups=e2etest.grpc.io,resources=loadtests,verbs=get;list;watch;create;delete,namespace=tenant
+// +kubebuilder:rbac:groups=e2etest.grpc.io,resources=loadtests/status,verbs=get,namespace=tenant
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch,namespace=tenant
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get,namespace=tenant
+// +kubebuilder:rbac:groups="",resources=pods/proxy,verbs=get,namespace=tenant
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Tenant declares a namespace that runs load tests in isolation from the
+// tests in other namespaces. A tenant may have node pools of its own and
+// override the defaults that apply to its tests.
+type Tenant struct {
+	// Namespace is the namespace of the tenant.
+	Namespace string `json:"namespace"`
+
+	// Pools lists the node pools that are dedicated to the tenant. Tests in
+	// the namespace of the tenant may only run in these pools, and tests in
+	// other namespaces may not run in them. This field is optional. When
+	// omitted, the tests of the tenant run in the pools that are not
+	// dedicated to any tenant.
+	Pools []string `json:"pools,omitempty"`
+
+	// Overrides contains fields of the defaults that apply to the tests of
+	// the tenant instead of the values for the cluster. It is merged with the
+	// defaults in the same way as a defaults overlay. The tenants and node
+	// pools cannot be overridden. This field is optional.
+	Overrides map[string]interface{} `json:"overrides,omitempty"`
+}
+
+// tenantFixedFields lists the fields of the defaults that apply to the whole
+// cluster, so tenants cannot override them.
+var tenantFixedFields = []string{"tenants", "nodePools"}
+
+// TenantForNamespace returns the declared tenant with the given namespace, or
+// nil if there is no such tenant.
+func (d *Defaults) TenantForNamespace(namespace string) *Tenant {
+	for i := range d.Tenants {
+		if d.Tenants[i].Namespace == namespace {
+			return &d.Tenants[i]
+		}
+	}
+	return nil
+}
+
+// TenantForPool returns the declared tenant that a node pool is dedicated to,
+// or nil if the pool is not dedicated to any tenant.
+func (d *Defaults) TenantForPool(pool string) *Tenant {
+	for i := range d.Tenants {
+		for _, name := range d.Tenants[i].Pools {
+			if name == pool {
+				return &d.Tenants[i]
+			}
+		}
+	}
+	return nil
+}
+
+// PoolAllowedInNamespace returns true if tests in the given namespace may run
+// in a node pool. Tests of a tenant with dedicated pools may only run in those
+// pools. Tests in other namespaces may run in any pool that is not dedicated
+// to a tenant.
+func (d *Defaults) PoolAllowedInNamespace(pool string, namespace string) bool {
+	if tenant := d.TenantForNamespace(namespace); tenant != nil && len(tenant.Pools) > 0 {
+		for _, name := range tenant.Pools {
+			if name == pool {
+				return true
+			}
+		}
+		return false
+	}
+	return d.TenantForPool(pool) == nil
+}
+
+// ForNamespace returns the defaults that apply to the tests in a namespace.
+// The overrides of the tenant with the namespace are merged with the defaults,
+// and only the node pools that the tests in the namespace may run in are kept.
+// The returned defaults declare no tenants. If no tenants are declared, the
+// defaults are returned unchanged.
+func (d *Defaults) ForNamespace(namespace string) (*Defaults, error) {
+	if len(d.Tenants) == 0 {
+		return d, nil
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode defaults")
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, errors.Wrap(err, "could not decode defaults")
+	}
+	delete(merged, "tenants")
+
+	if tenant := d.TenantForNamespace(namespace); tenant != nil && tenant.Overrides != nil {
+		// The overrides are copied, because merging modifies nested
+		// objects of the overlay in place.
+		data, err := json.Marshal(tenant.Overrides)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not encode overrides of tenant %q", namespace)
+		}
+		var overrides map[string]interface{}
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, errors.Wrapf(err, "could not decode overrides of tenant %q", namespace)
+		}
+		if err := mergeObjects(merged, overrides, ""); err != nil {
+			return nil, errors.Wrapf(err, "could not merge overrides of tenant %q", namespace)
+		}
+	}
+
+	data, err = json.Marshal(merged)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not encode defaults for namespace %q", namespace)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	namespaced := new(Defaults)
+	if err := decoder.Decode(namespaced); err != nil {
+		return nil, errors.Wrapf(err, "could not decode defaults for namespace %q", namespace)
+	}
+
+	var nodePools []NodePool
+	for _, nodePool := range namespaced.NodePools {
+		if d.PoolAllowedInNamespace(nodePool.Name, namespace) {
+			nodePools = append(nodePools, nodePool)
+		}
+	}
+	namespaced.NodePools = nodePools
+
+	return namespaced, nil
+}
+
+// validateTenants ensures that the declared tenants are well-formed, and that
+// the defaults of each tenant are valid. If an issue is encountered, an error
+// is returned.
+func (d *Defaults) validateTenants() error {
+	namespaces := make(map[string]bool)
+	dedicated := make(map[string]string)
+
+	for i, tenant := range d.Tenants {
+		if tenant.Namespace == "" {
+			return errors.Errorf("tenant (index %d) has no namespace", i)
+		}
+
+		if namespaces[tenant.Namespace] {
+			return errors.Errorf("tenant %q declared more than once", tenant.Namespace)
+		}
+		namespaces[tenant.Namespace] = true
+
+		for _, pool := range tenant.Pools {
+			if other, ok := dedicated[pool]; ok {
+				return errors.Errorf("node pool %q is dedicated to both tenants %q and %q", pool, other, tenant.Namespace)
+			}
+			dedicated[pool] = tenant.Namespace
+
+			if len(d.NodePools) > 0 && d.NodePoolForName(pool) == nil {
+				return errors.Errorf("tenant %q has undeclared node pool %q", tenant.Namespace, pool)
+			}
+		}
+
+		for _, field := range tenantFixedFields {
+			if _, ok := tenant.Overrides[field]; ok {
+				return errors.Errorf("tenant %q cannot override %s", tenant.Namespace, field)
+			}
+		}
+
+		namespaced, err := d.ForNamespace(tenant.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := namespaced.Validate(); err != nil {
+			return errors.Wrapf(err, "defaults of tenant %q are invalid", tenant.Namespace)
+		}
+	}
+
+	return nil
+}
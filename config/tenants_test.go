@@ -0,0 +1,203 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tenants", func() {
+	var defaults *Defaults
+
+	BeforeEach(func() {
+		defaults = &Defaults{
+			CloneImage:           "gcr.io/grpc-fake-project/test-infra/clone",
+			ReadyImage:           "gcr.io/grpc-fake-project/test-infra/ready",
+			DriverImage:          "gcr.io/grpc-fake-project/test-infra/driver",
+			KeepRetentionSeconds: 3600,
+			Languages: []LanguageDefault{
+				{
+					Language:   "go",
+					BuildImage: "golang:1.23",
+					RunImage:   "gcr.io/grpc-fake-project/test-infra/go",
+				},
+			},
+			NodePools: []NodePool{
+				{
+					Name:       "drivers",
+					Capacity:   8,
+					DefaultFor: []string{DriverRole},
+				},
+				{
+					Name:       "workers",
+					Capacity:   8,
+					DefaultFor: []string{ClientRole, ServerRole},
+				},
+				{
+					Name:       "team-a-drivers",
+					Capacity:   2,
+					DefaultFor: []string{DriverRole},
+				},
+				{
+					Name:       "team-a-workers",
+					Capacity:   4,
+					DefaultFor: []string{ClientRole, ServerRole},
+				},
+			},
+			Tenants: []Tenant{
+				{
+					Namespace: "team-a",
+					Pools:     []string{"team-a-drivers", "team-a-workers"},
+					Overrides: map[string]interface{}{
+						"keepRetentionSeconds": 60,
+						"languages": []interface{}{
+							map[string]interface{}{
+								"language": "go",
+								"runImage": "gcr.io/team-a/go",
+							},
+						},
+					},
+				},
+				{
+					Namespace: "team-b",
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("returns nil for valid tenants", func() {
+			Expect(defaults.Validate()).To(Succeed())
+		})
+
+		It("returns an error when a tenant has no namespace", func() {
+			defaults.Tenants[1].Namespace = ""
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a tenant is declared more than once", func() {
+			defaults.Tenants[1].Namespace = "team-a"
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a pool is dedicated to two tenants", func() {
+			defaults.Tenants[1].Pools = []string{"team-a-workers"}
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a tenant has an undeclared pool", func() {
+			defaults.Tenants[0].Pools = append(defaults.Tenants[0].Pools, "team-a-gpus")
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a tenant overrides the node pools", func() {
+			defaults.Tenants[0].Overrides["nodePools"] = []interface{}{}
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when a tenant overrides an unknown field", func() {
+			defaults.Tenants[0].Overrides["keepRetention"] = 60
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when the defaults of a tenant are invalid", func() {
+			defaults.Tenants[0].Overrides["keepRetentionSeconds"] = -1
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+
+		It("returns an error when the pools of a tenant have two defaults for a role", func() {
+			defaults.NodePools[3].DefaultFor = append(defaults.NodePools[3].DefaultFor, DriverRole)
+			Expect(defaults.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("PoolAllowedInNamespace", func() {
+		It("allows the tests of a tenant to run in its pools", func() {
+			Expect(defaults.PoolAllowedInNamespace("team-a-workers", "team-a")).To(BeTrue())
+		})
+
+		It("does not allow the tests of a tenant to run in other pools", func() {
+			Expect(defaults.PoolAllowedInNamespace("workers", "team-a")).To(BeFalse())
+		})
+
+		It("does not allow other namespaces to run in the pools of a tenant", func() {
+			Expect(defaults.PoolAllowedInNamespace("team-a-workers", "team-b")).To(BeFalse())
+			Expect(defaults.PoolAllowedInNamespace("team-a-workers", "default")).To(BeFalse())
+		})
+
+		It("allows tenants without pools to run in shared pools", func() {
+			Expect(defaults.PoolAllowedInNamespace("workers", "team-b")).To(BeTrue())
+		})
+
+		It("allows other namespaces to run in shared pools", func() {
+			Expect(defaults.PoolAllowedInNamespace("workers", "default")).To(BeTrue())
+		})
+	})
+
+	Describe("ForNamespace", func() {
+		It("returns the defaults unchanged when no tenants are declared", func() {
+			defaults.Tenants = nil
+			namespaced, err := defaults.ForNamespace("team-a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaced).To(BeIdenticalTo(defaults))
+		})
+
+		It("applies the overrides of the tenant", func() {
+			namespaced, err := defaults.ForNamespace("team-a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaced.KeepRetentionSeconds).To(BeEquivalentTo(60))
+			Expect(namespaced.Languages).To(ConsistOf(LanguageDefault{
+				Language:   "go",
+				BuildImage: "golang:1.23",
+				RunImage:   "gcr.io/team-a/go",
+			}))
+		})
+
+		It("keeps only the pools of the tenant", func() {
+			namespaced, err := defaults.ForNamespace("team-a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaced.NodePools).To(HaveLen(2))
+			Expect(namespaced.DefaultNodePoolName(DriverRole)).To(Equal("team-a-drivers"))
+			Expect(namespaced.DefaultNodePoolName(ClientRole)).To(Equal("team-a-workers"))
+		})
+
+		It("keeps only the shared pools for other namespaces", func() {
+			namespaced, err := defaults.ForNamespace("default")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaced.KeepRetentionSeconds).To(BeEquivalentTo(3600))
+			Expect(namespaced.NodePools).To(HaveLen(2))
+			Expect(namespaced.DefaultNodePoolName(DriverRole)).To(Equal("drivers"))
+			Expect(namespaced.DefaultNodePoolName(ServerRole)).To(Equal("workers"))
+		})
+
+		It("does not declare tenants", func() {
+			namespaced, err := defaults.ForNamespace("team-a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespaced.Tenants).To(BeEmpty())
+		})
+
+		It("does not modify the defaults or the overrides", func() {
+			_, err := defaults.ForNamespace("team-a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaults.KeepRetentionSeconds).To(BeEquivalentTo(3600))
+			Expect(defaults.Languages[0].RunImage).To(Equal("gcr.io/grpc-fake-project/test-infra/go"))
+			Expect(defaults.NodePools).To(HaveLen(4))
+			Expect(defaults.Tenants[0].Overrides["keepRetentionSeconds"]).To(Equal(60))
+		})
+	})
+})
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// DefaultTimeoutSeconds is the timeout of load tests that do not set one and
+// have no scenarios to compute it from, when the defaults do not specify a
+// timeout.
+const DefaultTimeoutSeconds = 900
+
+// DefaultWorkerSetupSeconds is the time allowed for each server and client
+// pod of a load test to start, when the defaults do not specify one.
+const DefaultWorkerSetupSeconds = 60
+
+// scenarioDuration holds the fields of a scenario that determine how long it
+// runs. Scenarios may be written with the original field names of the
+// protobuf or with their JSON names.
+type scenarioDuration struct {
+	WarmupSeconds        int64 `json:"warmup_seconds"`
+	WarmupSecondsJSON    int64 `json:"warmupSeconds"`
+	BenchmarkSeconds     int64 `json:"benchmark_seconds"`
+	BenchmarkSecondsJSON int64 `json:"benchmarkSeconds"`
+}
+
+// seconds returns the time that the scenario runs, in seconds.
+func (s *scenarioDuration) seconds() int64 {
+	warmup := s.WarmupSeconds
+	if warmup == 0 {
+		warmup = s.WarmupSecondsJSON
+	}
+	benchmark := s.BenchmarkSeconds
+	if benchmark == 0 {
+		benchmark = s.BenchmarkSecondsJSON
+	}
+	return warmup + benchmark
+}
+
+// TimeoutSecondsForTest computes a timeout for a load test that does not set
+// one. The timeout allows each scenario to warm up and run its benchmark once
+// for each step of the load profile, each server and client pod to start, and
+// a soak test to keep running for all of its hours. The warmup-seconds and
+// benchmark-seconds annotations of the test replace the durations of its
+// scenarios. The timeout is raised to satisfy the killAfterSeconds and
+// terminationGracePeriodSeconds of the test, and lowered to its ttlSeconds.
+//
+// Tests without scenarios, such as interop tests, are given the TimeoutSeconds
+// of the defaults. An error is returned if the ScenariosJSON cannot be parsed.
+func (d *Defaults) TimeoutSecondsForTest(test *grpcv1.LoadTest) (int32, error) {
+	scenarios, err := scenarioDurations(test.Spec.ScenariosJSON)
+	if err != nil {
+		return 0, err
+	}
+	if len(scenarios) == 0 {
+		if d.TimeoutSeconds > 0 {
+			return d.TimeoutSeconds, nil
+		}
+		return DefaultTimeoutSeconds, nil
+	}
+	overrideScenarioDurations(test.Annotations, scenarios)
+
+	var timeout int64
+	steps := int64(loadProfileStepCount(test.Spec.LoadProfile))
+	for _, scenario := range scenarios {
+		timeout += scenario.seconds() * steps
+	}
+
+	setup := int64(d.WorkerSetupSeconds)
+	if setup == 0 {
+		setup = DefaultWorkerSetupSeconds
+	}
+	timeout += setup * int64(workerCount(&test.Spec))
+
+	if test.Spec.SoakHours != nil {
+		timeout += int64(*test.Spec.SoakHours) * 60 * 60
+	}
+
+	if killAfter := test.Spec.KillAfterSeconds; killAfter != nil && timeout <= int64(*killAfter) {
+		timeout = int64(*killAfter) + 1
+	}
+	if gracePeriod := test.Spec.TerminationGracePeriodSeconds; gracePeriod != nil && timeout < *gracePeriod {
+		timeout = *gracePeriod
+	}
+	if ttl := int64(test.Spec.TTLSeconds); ttl > 0 && timeout > ttl {
+		timeout = ttl
+	}
+	if timeout > math.MaxInt32 {
+		timeout = math.MaxInt32
+	}
+	if timeout < 1 {
+		timeout = 1
+	}
+	return int32(timeout), nil
+}
+
+// scenarioDurations parses the scenarios in a ScenariosJSON string. The
+// scenarios field may hold a single scenario object or a list of scenarios.
+func scenarioDurations(scenariosJSON string) ([]scenarioDuration, error) {
+	if scenariosJSON == "" {
+		return nil, nil
+	}
+
+	var jsonScenarioMap map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(scenariosJSON), &jsonScenarioMap); err != nil {
+		return nil, errors.Wrap(err, "could not parse scenarios")
+	}
+	rawScenarios, ok := jsonScenarioMap["scenarios"]
+	if !ok {
+		return nil, nil
+	}
+
+	var scenarios []scenarioDuration
+	if err := json.Unmarshal(rawScenarios, &scenarios); err == nil {
+		return scenarios, nil
+	}
+	var scenario scenarioDuration
+	if err := json.Unmarshal(rawScenarios, &scenario); err != nil {
+		return nil, errors.Wrap(err, "could not parse scenarios")
+	}
+	return []scenarioDuration{scenario}, nil
+}
+
+// overrideScenarioDurations replaces the durations of scenarios with those set
+// by the warmup-seconds and benchmark-seconds annotations of a test, as they
+// are when the scenarios are prepared. Invalid values are rejected when the
+// scenarios are prepared, so they are ignored here.
+func overrideScenarioDurations(annotations map[string]string, scenarios []scenarioDuration) {
+	if value, ok := annotations[WarmupSecondsAnnotation]; ok {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			for i := range scenarios {
+				scenarios[i].WarmupSeconds = seconds
+				scenarios[i].WarmupSecondsJSON = seconds
+			}
+		}
+	}
+	if value, ok := annotations[BenchmarkSecondsAnnotation]; ok {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 1 {
+			for i := range scenarios {
+				scenarios[i].BenchmarkSeconds = seconds
+				scenarios[i].BenchmarkSecondsJSON = seconds
+			}
+		}
+	}
+}
+
+// loadProfileStepCount returns the number of times each scenario runs with a
+// load profile. Invalid profiles are rejected when the scenarios are prepared,
+// so they are counted as a single step here.
+func loadProfileStepCount(profile *grpcv1.LoadProfile) int {
+	if profile == nil {
+		return 1
+	}
+	if len(profile.Steps) > 0 {
+		return len(profile.Steps)
+	}
+	if ramp := profile.Ramp; ramp != nil && ramp.Increment > 0 && ramp.From <= ramp.To {
+		return int((ramp.To-ramp.From)/ramp.Increment) + 1
+	}
+	return 1
+}
+
+// workerCount returns the number of server and client pods of a load test.
+func workerCount(spec *grpcv1.LoadTestSpec) int {
+	count := len(spec.Servers)
+	for _, client := range spec.Clients {
+		if client.Replicas != nil {
+			count += int(*client.Replicas)
+		} else {
+			count++
+		}
+	}
+	return count
+}
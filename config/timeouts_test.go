@@ -0,0 +1,131 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("TimeoutSecondsForTest", func() {
+	var defaults *Defaults
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		defaults = &Defaults{}
+		test = &grpcv1.LoadTest{
+			Spec: grpcv1.LoadTestSpec{
+				Servers: []grpcv1.Server{{}},
+				Clients: []grpcv1.Client{{}},
+				ScenariosJSON: `{"scenarios": [
+					{"name": "first", "warmup_seconds": 5, "benchmark_seconds": 30},
+					{"name": "second", "warmupSeconds": 10, "benchmarkSeconds": 60}
+				]}`,
+				TTLSeconds: 86400,
+			},
+		}
+	})
+
+	It("allows each scenario to run and each worker to start", func() {
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(5 + 30 + 10 + 60 + 2*DefaultWorkerSetupSeconds))
+
+		defaults.WorkerSetupSeconds = 10
+		test.Spec.Clients[0].Replicas = optional.Int32Ptr(3)
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(5 + 30 + 10 + 60 + 4*10))
+	})
+
+	It("accepts a single scenario object", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": {"warmup_seconds": 5, "benchmark_seconds": 30}}`
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(5 + 30 + 2*DefaultWorkerSetupSeconds))
+	})
+
+	It("runs each scenario once for each step of the load profile", func() {
+		defaults.WorkerSetupSeconds = 1
+		test.Spec.LoadProfile = &grpcv1.LoadProfile{
+			Type:  grpcv1.PoissonLoad,
+			Steps: []int32{100, 200, 300},
+		}
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(3*(5+30+10+60) + 2))
+
+		test.Spec.LoadProfile = &grpcv1.LoadProfile{
+			Type: grpcv1.PoissonLoad,
+			Ramp: &grpcv1.LoadRamp{From: 100, To: 1000, Increment: 100},
+		}
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(10*(5+30+10+60) + 2))
+	})
+
+	It("uses the durations set by the annotations of the test", func() {
+		defaults.WorkerSetupSeconds = 1
+		test.Annotations = map[string]string{
+			WarmupSecondsAnnotation:    "0",
+			BenchmarkSecondsAnnotation: "600",
+		}
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(2*600 + 2))
+
+		test.Spec.LoadProfile = &grpcv1.LoadProfile{
+			Type:  grpcv1.PoissonLoad,
+			Steps: []int32{100, 200},
+		}
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(2*2*600 + 2))
+
+		test.Annotations[BenchmarkSecondsAnnotation] = "invalid"
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(2*(30+60) + 2))
+	})
+
+	It("covers the soak hours of the test", func() {
+		defaults.WorkerSetupSeconds = 1
+		test.Spec.SoakHours = optional.Int32Ptr(2)
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(2*3600 + 5 + 30 + 10 + 60 + 2))
+	})
+
+	It("satisfies the kill after and grace period of the test", func() {
+		defaults.WorkerSetupSeconds = 1
+		test.Spec.ScenariosJSON = `{"scenarios": {"warmup_seconds": 1, "benchmark_seconds": 1}}`
+		test.Spec.KillAfterSeconds = optional.Int32Ptr(30)
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(31))
+
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(120)
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(120))
+	})
+
+	It("does not exceed the TTL of the test", func() {
+		test.Spec.TTLSeconds = 60
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(60))
+	})
+
+	It("uses the default timeout for tests without scenarios", func() {
+		test.Spec.ScenariosJSON = ""
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(DefaultTimeoutSeconds))
+
+		test.Spec.ScenariosJSON = `{"scenarios": []}`
+		defaults.TimeoutSeconds = 300
+		Expect(defaults.TimeoutSecondsForTest(test)).To(BeEquivalentTo(300))
+	})
+
+	It("returns an error when the scenarios cannot be parsed", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": [`
+		_, err := defaults.TimeoutSecondsForTest(test)
+		Expect(err).To(HaveOccurred())
+
+		test.Spec.ScenariosJSON = `{"scenarios": "first"}`
+		_, err = defaults.TimeoutSecondsForTest(test)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command interop runs the gRPC interop client once for each test case of an
+// interop test and reports the result of every case.
+//
+// The controller copies this program into the driver pod of an interop test
+// and wraps the command of the interop client with it:
+//
+//	interop -- <client command> [client args...]
+//
+// The server, the port and the test cases are read from the environment of the
+// container. The standard --server_host, --server_port, --test_case and
+// --use_tls flags are appended to the client command for each case. The
+// results are written as JSON to the termination message of the container,
+// and the program exits with a non-zero code if any case failed.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/logging"
+)
+
+// ReportFileEnv is the optional name of the environment variable with the path
+// of the file where the results are written. If it is unset,
+// DefaultReportFile is used.
+const ReportFileEnv = "INTEROP_REPORT_FILE"
+
+// DefaultReportFile is the file where the results are written by default. It
+// is the file that Kubernetes reads the termination message from.
+const DefaultReportFile = "/dev/termination-log"
+
+// maxMessageLength limits the length of the message of a failed case, so the
+// results of all cases fit in the termination message.
+const maxMessageLength = 200
+
+// Report is the content of the report file.
+type Report struct {
+	// InteropCases are the results of the test cases, in the order they
+	// were run.
+	InteropCases []grpcv1.InteropCaseResult `json:"interopCases"`
+}
+
+// Target describes the server that the client connects to.
+type Target struct {
+	Host   string
+	Port   string
+	UseTLS bool
+}
+
+// flags returns the flags of the interop client that select the server and
+// the test case.
+func (t *Target) flags(testCase string) []string {
+	return []string{
+		"--server_host=" + t.Host,
+		"--server_port=" + t.Port,
+		"--test_case=" + testCase,
+		fmt.Sprintf("--use_tls=%t", t.UseTLS),
+	}
+}
+
+// RunCase runs the client command for a single test case. The output of the
+// command is copied to out. The case passes if the command exits with a zero
+// code. Otherwise, the message of the result contains the exit code and the
+// last line of output.
+func RunCase(ctx context.Context, command []string, target *Target, testCase string, out io.Writer) grpcv1.InteropCaseResult {
+	result := grpcv1.InteropCaseResult{Name: testCase}
+
+	args := append(append([]string{}, command[1:]...), target.flags(testCase)...)
+	cmd := exec.CommandContext(ctx, command[0], args...)
+	output := new(bytes.Buffer)
+	cmd.Stdout = io.MultiWriter(out, output)
+	cmd.Stderr = io.MultiWriter(out, output)
+
+	err := cmd.Run()
+	if err == nil {
+		result.Passed = true
+		return result
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.Message = fmt.Sprintf("exit code %d", exitErr.ExitCode())
+	} else {
+		result.Message = err.Error()
+	}
+	if line := lastLine(output.String()); line != "" {
+		result.Message += ": " + line
+	}
+	if len(result.Message) > maxMessageLength {
+		result.Message = result.Message[:maxMessageLength-3] + "..."
+	}
+	return result
+}
+
+// RunCases runs the client command for each test case in turn, and returns
+// their results. Cases are not run after the context is cancelled.
+func RunCases(ctx context.Context, command []string, target *Target, testCases []string, out io.Writer) []grpcv1.InteropCaseResult {
+	var results []grpcv1.InteropCaseResult
+	for _, testCase := range testCases {
+		if ctx.Err() != nil {
+			break
+		}
+		fmt.Fprintf(out, "Running interop test case %q\n", testCase)
+		result := RunCase(ctx, command, target, testCase, out)
+		if result.Passed {
+			fmt.Fprintf(out, "Passed interop test case %q\n", testCase)
+		} else {
+			fmt.Fprintf(out, "Failed interop test case %q: %s\n", testCase, result.Message)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// WriteReport writes the results of the test cases as JSON to a file.
+func WriteReport(path string, results []grpcv1.InteropCaseResult) error {
+	data, err := json.Marshal(&Report{InteropCases: results})
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results to %s: %v", path, err)
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of the output of a command.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// parseTestCases splits a comma-separated list of test cases, ignoring empty
+// entries.
+func parseTestCases(value string) []string {
+	var testCases []string
+	for _, testCase := range strings.Split(value, ",") {
+		if testCase = strings.TrimSpace(testCase); testCase != "" {
+			testCases = append(testCases, testCase)
+		}
+	}
+	return testCases
+}
+
+func main() {
+	logger, err := logging.Setup(logging.OptionsFromEnv())
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	command := os.Args[1:]
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		log.Fatalf("usage: %s -- <client command> [client args...]", os.Args[0])
+	}
+
+	testCases := parseTestCases(os.Getenv(config.InteropTestCasesEnv))
+	if len(testCases) == 0 {
+		log.Fatalf("no test cases in $%s", config.InteropTestCasesEnv)
+	}
+	target := &Target{
+		Host:   os.Getenv(config.InteropServerHostEnv),
+		Port:   os.Getenv(config.InteropServerPortEnv),
+		UseTLS: os.Getenv(config.InteropUseTLSEnv) == "true",
+	}
+	if target.Host == "" || target.Port == "" {
+		log.Fatalf("$%s and $%s must be set", config.InteropServerHostEnv, config.InteropServerPortEnv)
+	}
+
+	reportFile := os.Getenv(ReportFileEnv)
+	if reportFile == "" {
+		reportFile = DefaultReportFile
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	results := RunCases(ctx, command, target, testCases, os.Stdout)
+	if err := WriteReport(reportFile, results); err != nil {
+		log.Printf("Could not write report: %v", err)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	if failed > 0 || len(results) < len(testCases) {
+		log.Fatalf("%d of %d interop test cases failed", failed+len(testCases)-len(results), len(testCases))
+	}
+	log.Printf("All %d interop test cases passed", len(testCases))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// fakeClient is a command that behaves like an interop client. It prints its
+// arguments, and fails the test cases whose names start with "bad".
+var fakeClient = []string{"sh", "-c", `echo "$@"; case "$*" in *--test_case=bad*) echo "assertion failed"; exit 3;; esac`, "interop_client"}
+
+var _ = Describe("RunCases", func() {
+	var target *Target
+	var output *bytes.Buffer
+
+	BeforeEach(func() {
+		target = &Target{Host: "server-a.test", Port: "10010"}
+		output = new(bytes.Buffer)
+	})
+
+	It("passes the server and test case to the client", func() {
+		results := RunCases(context.Background(), fakeClient, target, []string{"large_unary"}, output)
+
+		Expect(results).To(Equal([]grpcv1.InteropCaseResult{{Name: "large_unary", Passed: true}}))
+		Expect(output.String()).To(ContainSubstring("--server_host=server-a.test --server_port=10010 --test_case=large_unary --use_tls=false"))
+	})
+
+	It("reports the exit code and last line of failed cases", func() {
+		results := RunCases(context.Background(), fakeClient, target, []string{"empty_unary", "bad_case"}, output)
+
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Passed).To(BeTrue())
+		Expect(results[1]).To(Equal(grpcv1.InteropCaseResult{
+			Name:    "bad_case",
+			Passed:  false,
+			Message: "exit code 3: assertion failed",
+		}))
+	})
+
+	It("reports commands that cannot be started", func() {
+		results := RunCases(context.Background(), []string{"/nonexistent/interop_client"}, target, []string{"empty_unary"}, output)
+
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Passed).To(BeFalse())
+		Expect(results[0].Message).ToNot(BeEmpty())
+	})
+
+	It("truncates long messages", func() {
+		client := []string{"sh", "-c", `echo "` + strings.Repeat("x", 500) + `"; exit 1`}
+
+		results := RunCases(context.Background(), client, target, []string{"empty_unary"}, output)
+
+		Expect(len(results[0].Message)).To(Equal(maxMessageLength))
+	})
+
+	It("stops running cases after the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := RunCases(ctx, fakeClient, target, []string{"empty_unary"}, output)
+		Expect(results).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WriteReport", func() {
+	It("writes the results as JSON", func() {
+		dir, err := os.MkdirTemp("", "interop")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "report.json")
+
+		results := []grpcv1.InteropCaseResult{
+			{Name: "empty_unary", Passed: true},
+			{Name: "large_unary", Message: "exit code 1"},
+		}
+		Expect(WriteReport(path, results)).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		var report Report
+		Expect(json.Unmarshal(data, &report)).To(Succeed())
+		Expect(report.InteropCases).To(Equal(results))
+	})
+})
+
+var _ = Describe("parseTestCases", func() {
+	It("ignores empty entries and spaces", func() {
+		Expect(parseTestCases(" empty_unary,,large_unary ,")).To(Equal([]string{"empty_unary", "large_unary"}))
+	})
+})
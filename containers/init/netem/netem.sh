@@ -0,0 +1,29 @@
+#!/bin/bash
+# Copyright 2022 gRPC authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     https://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -ex
+
+# The arguments of this script are passed to netem unchanged, for example:
+# delay 50ms 5ms loss 0.1% rate 100mbit. The qdisc is attached to the network
+# interface of the pod, so it remains in place after this container exits and
+# shapes the traffic of every container in the pod.
+
+if [ "$#" -eq 0 ]; then
+  echo "no netem arguments were provided" >&2
+  exit 1
+fi
+
+tc qdisc replace dev "${NETEM_DEVICE:-eth0}" root netem "$@"
+tc qdisc show dev "${NETEM_DEVICE:-eth0}"
@@ -20,7 +20,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net"
 	"os"
@@ -31,10 +30,14 @@ import (
 	grpcclientset "github.com/grpc/test-infra/clientset"
 	testconfig "github.com/grpc/test-infra/config"
 	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/logging"
 	pb "github.com/grpc/test-infra/proto/endpointupdater"
 	"github.com/grpc/test-infra/status"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	grpcstatus "google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -91,11 +94,60 @@ const DefaultDriverPort int32 = 10000
 // defaultConnectionTimeout specifies the default maximum allowed duration of a RPC call .
 const defaultConnectionTimeout = 20 * time.Second
 
+// DefaultUpdateTimeout specifies the amount of time to keep retrying to send
+// the backend endpoints to the xds server of a client.
+const DefaultUpdateTimeout = 2 * time.Minute
+
+// maxUpdateBackoff specifies the maximum amount of time between attempts to
+// send the backend endpoints to the xds server of a client.
+const maxUpdateBackoff = 5 * time.Second
+
+// UpdaterTLSCertEnv, UpdaterTLSKeyEnv and UpdaterTLSCAEnv are the names of the
+// environment variables that may contain paths to PEM files with the client
+// certificate, the client key and the CAs used to connect to the test update
+// server of the xds server of each client. The connection is insecure when
+// UpdaterTLSCAEnv is not set.
+const (
+	UpdaterTLSCertEnv = "UPDATER_TLS_CERT"
+	UpdaterTLSKeyEnv  = "UPDATER_TLS_KEY"
+	UpdaterTLSCAEnv   = "UPDATER_TLS_CA"
+)
+
+// UpdaterTLSServerNameEnv is the name of the environment variable that may
+// contain the name expected in the certificate of the test update server. The
+// IP address of the client pod is expected when it is not set.
+const UpdaterTLSServerNameEnv = "UPDATER_TLS_SERVER_NAME"
+
 // KubeConfigEnv is the name of the environment variable that may contain a
 // path to a kubeconfig file. This environment variable does not need to be set
 // when the container runs on a node in a Kubernetes cluster.
 const KubeConfigEnv = "KUBE_CONFIG"
 
+// NamespaceFile is the file where Kubernetes places the namespace of the pod,
+// along with the credentials of its service account. The load test and its
+// pods are in this namespace.
+const NamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// ConnectivityTimeoutEnv is the name of the environment variable that may
+// contain the maximum amount of time to wait for the driver port of every
+// worker to accept connections, once all pods are ready. The check is skipped
+// when it is set to 0.
+const ConnectivityTimeoutEnv = "CONNECTIVITY_TIMEOUT"
+
+// DefaultConnectivityTimeout specifies the amount of time to wait for the
+// driver port of every worker to accept connections if the environment
+// variable specified by the ConnectivityTimeoutEnv constant is not set.
+const DefaultConnectivityTimeout = 2 * time.Minute
+
+// TerminationMessageFile is the file where Kubernetes reads the termination
+// message of the container. The workers that cannot be reached are reported
+// in this file, so they are included in the status of the load test.
+const TerminationMessageFile = "/dev/termination-log"
+
+// maxTerminationMessageSize is the maximum size of a termination message that
+// Kubernetes keeps.
+const maxTerminationMessageSize = 4096
+
 // pollInterval specifies the amount of time between subsequent requests to the
 // Kubernetes API for a list of pods.
 const pollInterval = 3 * time.Second
@@ -110,11 +162,22 @@ type LoadTestGetter interface {
 	Get(context.Context, string, metav1.GetOptions) (*grpcv1.LoadTest, error)
 }
 
-// NodeInfo contains pod name, pod IP and node name in which the pod reside for one worker or driver.
+// NodeGetter fetches a node with a specific name.
+type NodeGetter interface {
+	Get(context.Context, string, metav1.GetOptions) (*corev1.Node, error)
+}
+
+// NodeInfo contains pod name, pod IP and node name in which the pod reside for
+// one worker or driver. It also contains metadata about the node, so that
+// results obtained on different hardware can be told apart.
 type NodeInfo struct {
-	Name     string
-	PodIP    string
-	NodeName string
+	Name          string
+	PodIP         string
+	NodeName      string
+	MachineType   string
+	Zone          string
+	KernelVersion string
+	CPUPlatform   string
 }
 
 // NodesInfo contains NodeInfo for all pods included in a load test.
@@ -162,10 +225,24 @@ func findDriverPort(pod *corev1.Pod) int32 {
 	return DefaultDriverPort
 }
 
+// workerAddress returns the address that the driver should use to connect to a
+// worker pod. The host is the DNS name of the pod, which is stable across pod
+// restarts, when the pod is exposed by the headless Service of the test.
+// Otherwise, the IP address of the pod in the IP family of the test is used.
+// IPv6 addresses are enclosed in brackets.
+func workerAddress(pod *corev1.Pod, family grpcv1.IPFamily) string {
+	host := kubehelpers.PodDNSName(pod)
+	if host == "" {
+		host = kubehelpers.PodIPForFamily(pod, family)
+	}
+	return net.JoinHostPort(host, fmt.Sprint(findDriverPort(pod)))
+}
+
 // WaitForReadyPods blocks until all worker pods within the load test are ready.
 // It accepts a context, allowing a timeout or deadline to be specified. When
-// all pods are ready, it returns a slice of strings with the IP address and
+// all pods are ready, it returns a slice of strings with the address and
 // driver port for each matching pod. server pod would come before client pod.
+// The address is the DNS name of the pod when it has one, or its IP address.
 //
 // The driver port is determined by searching the pod for a container with a TCP
 // port named "driver". If there is no port named "driver" exposed on any of the
@@ -202,7 +279,7 @@ func WaitForReadyPods(ctx context.Context, ltg LoadTestGetter, pl PodLister, tes
 				continue
 			}
 			loadtest = l
-			for range loadtest.Spec.Clients {
+			for i := 0; i < kubehelpers.ClientCount(loadtest.Spec.Clients); i++ {
 				clientPodAddresses = append(clientPodAddresses, "")
 			}
 			for range loadtest.Spec.Servers {
@@ -219,7 +296,7 @@ func WaitForReadyPods(ctx context.Context, ltg LoadTestGetter, pl PodLister, tes
 				if !driverMatched && pod.Status.PodIP != "" {
 					nodesInfo.Driver = NodeInfo{
 						Name:     pod.Name,
-						PodIP:    pod.Status.PodIP,
+						PodIP:    kubehelpers.PodIPForFamily(pod, loadtest.Spec.IPFamily),
 						NodeName: pod.Spec.NodeName,
 					}
 					driverMatched = true
@@ -233,10 +310,10 @@ func WaitForReadyPods(ctx context.Context, ltg LoadTestGetter, pl PodLister, tes
 				continue
 			}
 			matchingPods[pod.Name] = true
-			ip := pod.Status.PodIP
-			driverPort := findDriverPort(pod)
+			ip := kubehelpers.PodIPForFamily(pod, loadtest.Spec.IPFamily)
+			address := workerAddress(pod, loadtest.Spec.IPFamily)
 			if pod.Labels[testconfig.RoleLabel] == testconfig.ServerRole {
-				serverPodAddresses[serverMatchCount] = net.JoinHostPort(ip, fmt.Sprint(driverPort))
+				serverPodAddresses[serverMatchCount] = address
 				nodesInfo.Servers = append(nodesInfo.Servers, NodeInfo{
 					Name:     pod.Name,
 					PodIP:    ip,
@@ -244,7 +321,7 @@ func WaitForReadyPods(ctx context.Context, ltg LoadTestGetter, pl PodLister, tes
 				})
 				serverMatchCount++
 			} else {
-				clientPodAddresses[clientMatchCount] = net.JoinHostPort(ip, fmt.Sprint(driverPort))
+				clientPodAddresses[clientMatchCount] = address
 				nodesInfo.Clients = append(nodesInfo.Clients, NodeInfo{
 					Name:     pod.Name,
 					PodIP:    ip,
@@ -264,46 +341,298 @@ func WaitForReadyPods(ctx context.Context, ltg LoadTestGetter, pl PodLister, tes
 	return podAddresses, &nodesInfo, nil
 }
 
+// setNodeMetadata copies the machine type, zone, kernel version and CPU
+// platform of a node into a NodeInfo. Labels that are not set on the node are
+// left empty.
+func setNodeMetadata(info *NodeInfo, node *corev1.Node) {
+	info.MachineType = node.Labels[corev1.LabelInstanceTypeStable]
+	info.Zone = node.Labels[corev1.LabelTopologyZone]
+	info.KernelVersion = node.Status.NodeInfo.KernelVersion
+	info.CPUPlatform = node.Labels[testconfig.CPUPlatformLabel]
+}
+
+// AddNodeMetadata fetches the node of each pod in nodesInfo and records its
+// machine type, zone, kernel version and CPU platform. Each node is fetched
+// once, even when it hosts several pods. If a node cannot be fetched, an error
+// is returned and the metadata of the remaining pods is left empty.
+func AddNodeMetadata(ctx context.Context, ng NodeGetter, nodesInfo *NodesInfo) error {
+	nodes := make(map[string]*corev1.Node)
+
+	infos := []*NodeInfo{&nodesInfo.Driver}
+	for i := range nodesInfo.Servers {
+		infos = append(infos, &nodesInfo.Servers[i])
+	}
+	for i := range nodesInfo.Clients {
+		infos = append(infos, &nodesInfo.Clients[i])
+	}
+
+	for _, info := range infos {
+		if info.NodeName == "" {
+			continue
+		}
+		node, ok := nodes[info.NodeName]
+		if !ok {
+			var err error
+			node, err = ng.Get(ctx, info.NodeName, metav1.GetOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to fetch node %q", info.NodeName)
+			}
+			nodes[info.NodeName] = node
+		}
+		setNodeMetadata(info, node)
+	}
+
+	return nil
+}
+
+// AddCostAnnotation sets the CostPerHourAnnotation in the metadata of a load
+// test to the total price per hour of the nodes of its pods. The prices are
+// passed as a JSON object that maps machine types to their prices. Nodes with
+// an unknown machine type, or a machine type without a price, are logged and
+// left out of the total.
+func AddCostAnnotation(meta *metav1.ObjectMeta, nodesInfo *NodesInfo, pricesJSON string) error {
+	prices := make(map[string]float64)
+	if err := json.Unmarshal([]byte(pricesJSON), &prices); err != nil {
+		return errors.Wrap(err, "failed to parse machine prices")
+	}
+
+	machineTypes := []string{nodesInfo.Driver.MachineType}
+	for _, info := range nodesInfo.Servers {
+		machineTypes = append(machineTypes, info.MachineType)
+	}
+	for _, info := range nodesInfo.Clients {
+		machineTypes = append(machineTypes, info.MachineType)
+	}
+	for i := range machineTypes {
+		if machineTypes[i] == "" {
+			machineTypes[i] = status.UnknownMachineType
+		}
+	}
+
+	costPerHour, unpriced := status.HourlyCost(machineTypes, prices)
+	if len(unpriced) > 0 {
+		log.Printf("no price for machine types: %s", strings.Join(unpriced, ", "))
+	}
+
+	annotations := make(map[string]string, len(meta.Annotations)+1)
+	for key, value := range meta.Annotations {
+		annotations[key] = value
+	}
+	annotations[testconfig.CostPerHourAnnotation] = fmt.Sprintf("%.4f", costPerHour)
+	meta.Annotations = annotations
+	return nil
+}
+
+// DialFunc connects to an address on a network.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// CheckConnectivity verifies that a TCP connection can be established with the
+// driver port of each worker, retrying until the timeout is exceeded. The
+// names and addresses of the workers are given in the same order. The workers
+// that could not be reached before the timeout are returned, along with the
+// last error encountered for each of them. Nil is returned when every worker
+// was reached.
+func CheckConnectivity(ctx context.Context, dial DialFunc, names []string, addresses []string, timeout time.Duration) []grpcv1.UnreachableWorker {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lastErrors := make(map[int]error, len(addresses))
+	for i := range addresses {
+		lastErrors[i] = nil
+	}
+
+	for {
+		for i := range lastErrors {
+			dialCtx, dialCancel := context.WithTimeout(ctx, pollInterval)
+			conn, err := dial(dialCtx, "tcp", addresses[i])
+			dialCancel()
+			if err != nil {
+				lastErrors[i] = err
+				continue
+			}
+			conn.Close()
+			delete(lastErrors, i)
+		}
+		if len(lastErrors) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			var unreachable []grpcv1.UnreachableWorker
+			for i := range addresses {
+				err, ok := lastErrors[i]
+				if !ok {
+					continue
+				}
+				worker := grpcv1.UnreachableWorker{
+					Name:    names[i],
+					Address: addresses[i],
+				}
+				if err != nil {
+					worker.Message = err.Error()
+				}
+				unreachable = append(unreachable, worker)
+			}
+			return unreachable
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// WriteConnectivityReport writes the workers that could not be reached to a
+// file as a JSON status.ConnectivityReport. Workers are dropped from the end
+// of the report until it fits in a termination message.
+func WriteConnectivityReport(file string, workers []grpcv1.UnreachableWorker) error {
+	for n := len(workers); n > 0; n-- {
+		body, err := json.Marshal(status.ConnectivityReport{UnreachableWorkers: workers[:n]})
+		if err != nil {
+			return errors.Wrap(err, "failed to encode connectivity report")
+		}
+		if len(body) <= maxTerminationMessageSize || n == 1 {
+			return os.WriteFile(file, body, 0644)
+		}
+	}
+	return nil
+}
+
+// unreachableWorkerNames returns a comma-separated list with the name and
+// address of each unreachable worker.
+func unreachableWorkerNames(workers []grpcv1.UnreachableWorker) string {
+	var names []string
+	for _, worker := range workers {
+		names = append(names, fmt.Sprintf("%s (%s)", worker.Name, worker.Address))
+	}
+	return strings.Join(names, ", ")
+}
+
+// isRetryableUpdateError returns true if an RPC to a test update server
+// failed for a reason that may be transient, such as a network interruption.
+func isRetryableUpdateError(err error) bool {
+	switch grpcstatus.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// retryUpdateRPC calls an RPC to a test update server until it succeeds, fails
+// with an error that is not transient or the context is done. Each attempt is
+// limited to defaultConnectionTimeout, and attempts are separated by an
+// exponential backoff starting at backoff.
+func retryUpdateRPC(ctx context.Context, backoff time.Duration, name string, rpc func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		rpcCtx, cancel := context.WithTimeout(ctx, defaultConnectionTimeout)
+		err := rpc(rpcCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if !isRetryableUpdateError(err) {
+			return errors.Wrapf(err, "%s failed", name)
+		}
+		log.Printf("attempt %d of %s failed, retrying in %v: %v", attempt, name, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(err, "%s failed after %d attempt(s)", name, attempt)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxUpdateBackoff {
+			backoff = maxUpdateBackoff
+		}
+	}
+}
+
+// SendTestUpdate sends the backend endpoints and the type of the test to a
+// test update server and commits them, retrying each step until the context is
+// done. Both steps are idempotent, so an update that reached the server before
+// the connection was interrupted is not applied twice. The target string
+// returned by the server is returned.
+func SendTestUpdate(ctx context.Context, c pb.TestUpdaterClient, targets []*pb.Endpoint, isProxied bool, backoff time.Duration) (string, error) {
+	request := &pb.TestUpdateRequest{Endpoints: targets, IsProxied: isProxied}
+	err := retryUpdateRPC(ctx, backoff, "UpdateTest", func(ctx context.Context) error {
+		_, err := c.UpdateTest(ctx, request, grpc.WaitForReady(true))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var reply *pb.TestUpdateReply
+	err = retryUpdateRPC(ctx, backoff, "Commit", func(ctx context.Context) error {
+		var err error
+		reply, err = c.Commit(ctx, &pb.Void{}, grpc.WaitForReady(true))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return reply.PsmServerTargetOverride, nil
+}
+
+// UpdaterCredentials returns the transport credentials used to connect to
+// the test update servers, which are configured by the environment variables
+// UpdaterTLSCertEnv, UpdaterTLSKeyEnv, UpdaterTLSCAEnv and
+// UpdaterTLSServerNameEnv. Insecure credentials are returned when
+// UpdaterTLSCAEnv is not set.
+func UpdaterCredentials() (credentials.TransportCredentials, error) {
+	caFile := os.Getenv(UpdaterTLSCAEnv)
+	if caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	return pb.ClientCredentials(os.Getenv(UpdaterTLSCertEnv), os.Getenv(UpdaterTLSKeyEnv), caFile, os.Getenv(UpdaterTLSServerNameEnv))
+}
+
 // communicateWithEachClient takes a client IP, a list of server IP plus its
 // test port and a boolean value indicates if the test is a proxied test. The
 // function communicates with the given client's xds server through a RPC
 // including information such as the full list of the server IP plus its test
-// port and the boolean value indicates the PSM test type. In the response of
-// the RPC, communicateWithEachClient gets back the target string will be used
-// in the loadtest.After the communication, the function starts a separate
-// goroutine to close the test update server on the xds server container. Then
-// the function returns the target string as an return value.
-func communicateWithEachClient(clientIP string, targets []*pb.Endpoint, isProxied bool) (string, error) {
-	var psmServerTargetOverride string
+// port and the boolean value indicates the PSM test type, and commits it. The
+// RPCs are retried until the context is done. In the response of the RPC,
+// communicateWithEachClient gets back the target string will be used in the
+// loadtest. After the communication, the function stops the test update server
+// on the xds server container and returns the target string.
+func communicateWithEachClient(ctx context.Context, clientIP string, targets []*pb.Endpoint, isProxied bool, creds credentials.TransportCredentials) (string, error) {
 	dialTarget := net.JoinHostPort(clientIP, fmt.Sprint(testconfig.ServerUpdatePort))
-	conn, err := grpc.Dial(dialTarget, grpc.WithInsecure())
+	conn, err := grpc.Dial(dialTarget, grpc.WithTransportCredentials(creds))
 	if err != nil {
-		log.Fatalf("did not connect: %v", err)
+		return "", errors.Wrapf(err, "failed to connect to %s", dialTarget)
 	}
 	defer conn.Close()
 	c := pb.NewTestUpdaterClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectionTimeout)
-	defer cancel()
-	reply, err := c.UpdateTest(ctx, &pb.TestUpdateRequest{Endpoints: targets, IsProxied: isProxied})
+
+	psmServerTargetOverride, err := SendTestUpdate(ctx, c, targets, isProxied, time.Second)
 	if err != nil {
-		statusCode, _ := grpcstatus.FromError(err)
-		log.Print(statusCode.Details()...)
-		log.Fatalf("could not connect to test update server: %v", err)
+		return "", err
 	}
-	psmServerTargetOverride = reply.PsmServerTargetOverride
-
 	log.Printf("all backend targets has been communicated to client %v", clientIP)
 
-	go func() {
-		log.Printf("stopping test update server on client %v", clientIP)
-		if _, err := c.QuitTestUpdateServer(ctx, &pb.Void{}); err != nil {
-			statusCode, _ := grpcstatus.FromError(err)
-			log.Print(statusCode.Details()...)
-		}
-	}()
+	// The update has been committed, so the update server is not needed
+	// anymore, and failing to stop it does not affect the test.
+	log.Printf("stopping test update server on client %v", clientIP)
+	quitCtx, cancel := context.WithTimeout(ctx, defaultConnectionTimeout)
+	defer cancel()
+	if _, err := c.QuitTestUpdateServer(quitCtx, &pb.Void{}); err != nil {
+		log.Printf("failed to stop test update server on client %v: %v", clientIP, err)
+	}
 	return psmServerTargetOverride, nil
 }
 
+// PodNamespace returns the namespace written to a file, which is the namespace
+// of the pod when the file is NamespaceFile. The default namespace is returned
+// if the file cannot be read, such as when running outside of a cluster.
+func PodNamespace(namespaceFile string) string {
+	data, err := os.ReadFile(namespaceFile)
+	if err != nil {
+		return corev1.NamespaceDefault
+	}
+	if namespace := strings.TrimSpace(string(data)); namespace != "" {
+		return namespace
+	}
+	return corev1.NamespaceDefault
+}
+
 func buildEndpoints(serverNodes []NodeInfo, psmTestServerPort uint32) []*pb.Endpoint {
 	var targets []*pb.Endpoint
 	for _, serverNode := range serverNodes {
@@ -316,7 +645,12 @@ func buildEndpoints(serverNodes []NodeInfo, psmTestServerPort uint32) []*pb.Endp
 }
 
 func main() {
-	var err error
+	logger, err := logging.Setup(logging.OptionsFromEnv())
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
 	timeout := DefaultTimeout
 	timeoutStr, ok := os.LookupEnv(TimeoutEnv)
 	if ok {
@@ -381,17 +715,43 @@ func main() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	namespace := PodNamespace(NamespaceFile)
 	log.Printf("Waiting for ready pods")
-	podIPs, nodesInfo, err := WaitForReadyPods(ctx, grpcClientset.LoadTestV1().LoadTests(corev1.NamespaceDefault), clientset.CoreV1().Pods(metav1.NamespaceAll), os.Args[1])
+	podIPs, nodesInfo, err := WaitForReadyPods(ctx, grpcClientset.LoadTestV1().LoadTests(namespace), clientset.CoreV1().Pods(namespace), os.Args[1])
 	if err != nil {
 		log.Fatalf("failed to wait for ready pods: %v", err)
 	}
 
 	log.Printf("all pods ready")
+
+	connectivityTimeout := DefaultConnectivityTimeout
+	if connectivityTimeoutStr, ok := os.LookupEnv(ConnectivityTimeoutEnv); ok {
+		connectivityTimeout, err = time.ParseDuration(connectivityTimeoutStr)
+		if err != nil {
+			log.Fatalf("failed to parse $%s: %v", ConnectivityTimeoutEnv, err)
+		}
+	}
+	if connectivityTimeout > 0 {
+		var workerNames []string
+		for _, info := range nodesInfo.Servers {
+			workerNames = append(workerNames, info.Name)
+		}
+		for _, info := range nodesInfo.Clients {
+			workerNames = append(workerNames, info.Name)
+		}
+		var dialer net.Dialer
+		if unreachable := CheckConnectivity(ctx, dialer.DialContext, workerNames, podIPs, connectivityTimeout); len(unreachable) > 0 {
+			if err := WriteConnectivityReport(TerminationMessageFile, unreachable); err != nil {
+				log.Printf("failed to write connectivity report: %v", err)
+			}
+			log.Fatalf("driver could not reach %d worker(s): %s", len(unreachable), unreachableWorkerNames(unreachable))
+		}
+		log.Printf("all workers reachable")
+	}
 	workerFileBody := strings.Join(podIPs, ",")
-	ioutil.WriteFile(outputFile, []byte(workerFileBody), 0777)
+	os.WriteFile(outputFile, []byte(workerFileBody), 0777)
 
-	test, err := grpcClientset.LoadTestV1().LoadTests(corev1.NamespaceDefault).Get(ctx, os.Args[1], metav1.GetOptions{})
+	test, err := grpcClientset.LoadTestV1().LoadTests(namespace).Get(ctx, os.Args[1], metav1.GetOptions{})
 	if err != nil {
 		log.Fatalf("failed to fetch loadtest: %v", err)
 	}
@@ -412,10 +772,16 @@ func main() {
 			log.Printf("running proxyless test")
 		}
 
+		creds, err := UpdaterCredentials()
+		if err != nil {
+			log.Fatalf("failed to load credentials for test update servers: %v", err)
+		}
+
 		psmTargetOverride := ""
 		for _, clientNode := range nodesInfo.Clients {
-
-			currentPSMTargetOverride, err := communicateWithEachClient(clientNode.PodIP, endpoints, isProxied)
+			updateCtx, updateCancel := context.WithTimeout(ctx, DefaultUpdateTimeout)
+			currentPSMTargetOverride, err := communicateWithEachClient(updateCtx, clientNode.PodIP, endpoints, isProxied, creds)
+			updateCancel()
 			if err != nil {
 				log.Fatalf("failed to communicate backend endpoints to client %v: %v", clientNode.Name, err)
 			}
@@ -430,7 +796,7 @@ func main() {
 		}
 
 		if psmTargetOverride != "" {
-			if err := ioutil.WriteFile(DefaultServerTargetOverrideOutputFile, []byte(psmTargetOverride), 0777); err != nil {
+			if err := os.WriteFile(DefaultServerTargetOverrideOutputFile, []byte(psmTargetOverride), 0777); err != nil {
 				log.Fatalf("failed to write PSM server target: %v to %v", psmTargetOverride, DefaultServerTargetOverrideOutputFile)
 			}
 			log.Printf("write PSM server target: %v to %v\n", psmTargetOverride, DefaultServerTargetOverrideOutputFile)
@@ -483,16 +849,25 @@ func main() {
 		outputMetadataFile = outputMetadataFileOverride
 	}
 
+	if err := AddNodeMetadata(ctx, clientset.CoreV1().Nodes(), nodesInfo); err != nil {
+		log.Printf("failed to add node metadata for loadtest %s: %v", test.Name, err)
+	}
+
 	metaDataSet := test.ObjectMeta
+	if prices, ok := os.LookupEnv(testconfig.MachineHourlyPricesEnv); ok {
+		if err := AddCostAnnotation(&metaDataSet, nodesInfo, prices); err != nil {
+			log.Printf("failed to estimate cost for loadtest %s: %v", test.Name, err)
+		}
+	}
 	metaDataBody, err := json.Marshal(metaDataSet)
 	if err != nil {
 		log.Fatalf("failed to marshal metaData for loadtest %s: %v", test.Name, err)
 	}
-	ioutil.WriteFile(outputMetadataFile, metaDataBody, 0777)
+	os.WriteFile(outputMetadataFile, metaDataBody, 0777)
 
 	nodeInfoFileBody, err := json.Marshal(*nodesInfo)
 	if err != nil {
 		log.Fatalf("failed to marshal nodes information for loadtest %s: %v", test.Name, err)
 	}
-	ioutil.WriteFile(outputNodeInfoFile, nodeInfoFileBody, 0777)
+	os.WriteFile(outputNodeInfoFile, nodeInfoFileBody, 0777)
 }
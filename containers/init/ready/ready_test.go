@@ -19,6 +19,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +31,10 @@ import (
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
 	"github.com/grpc/test-infra/kubehelpers"
+	pb "github.com/grpc/test-infra/proto/endpointupdater"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -228,6 +235,74 @@ var _ = Describe("WaitForReadyPods", func() {
 		}))
 	})
 
+	It("returns DNS names for pods exposed by the headless service", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), slowDuration)
+		defer cancel()
+
+		client2Pod := newTestPod("client")
+		client2Pod.Name = "client-2"
+		client2Pod.Namespace = "example-namespace"
+		client2Pod.Spec.Hostname = "client-2"
+		client2Pod.Spec.Subdomain = "example-test"
+
+		podListerMock := &PodListerMock{
+			PodList: &corev1.PodList{
+				Items: []corev1.Pod{
+					clientPod,
+					client2Pod,
+					driverPod,
+				},
+			},
+		}
+
+		loadTestGetterMock := &LoadTestGetterMock{
+			Loadtest: newLoadTestWithMultipleClientsAndServers(2, 0),
+		}
+
+		podAddresses, _, err := WaitForReadyPods(ctx, loadTestGetterMock, podListerMock, "test name")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podAddresses).To(Equal([]string{
+			fmt.Sprintf("%s:%d", clientPod.Status.PodIP, DefaultDriverPort),
+			fmt.Sprintf("client-2.example-test.example-namespace.svc:%d", DefaultDriverPort),
+		}))
+	})
+
+	It("returns IP addresses from the IP family of the test", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), slowDuration)
+		defer cancel()
+
+		client2Pod := newTestPod("client")
+		client2Pod.Name = "client-2"
+		client2Pod.Status.PodIP = "127.0.0.3"
+		client2Pod.Status.PodIPs = []corev1.PodIP{
+			{IP: "127.0.0.3"},
+			{IP: "fd00::3"},
+		}
+
+		podListerMock := &PodListerMock{
+			PodList: &corev1.PodList{
+				Items: []corev1.Pod{
+					clientPod,
+					client2Pod,
+					driverPod,
+				},
+			},
+		}
+
+		loadTest := newLoadTestWithMultipleClientsAndServers(2, 0)
+		loadTest.Spec.IPFamily = grpcv1.IPv6Family
+		loadTestGetterMock := &LoadTestGetterMock{
+			Loadtest: loadTest,
+		}
+
+		podAddresses, _, err := WaitForReadyPods(ctx, loadTestGetterMock, podListerMock, "test name")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podAddresses).To(Equal([]string{
+			fmt.Sprintf("%s:%d", clientPod.Status.PodIP, DefaultDriverPort),
+			fmt.Sprintf("[fd00::3]:%d", DefaultDriverPort),
+		}))
+	})
+
 	It("returns error if timeout exceeded", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), fastDuration)
 		defer cancel()
@@ -245,6 +320,84 @@ var _ = Describe("WaitForReadyPods", func() {
 	})
 })
 
+var _ = Describe("AddNodeMetadata", func() {
+	var node *corev1.Node
+
+	BeforeEach(func() {
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+				Labels: map[string]string{
+					corev1.LabelInstanceTypeStable: "c2-standard-8",
+					corev1.LabelTopologyZone:       "us-central1-b",
+					config.CPUPlatformLabel:        "intel-cascade-lake",
+				},
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{
+					KernelVersion: "5.10.133+",
+				},
+			},
+		}
+	})
+
+	It("sets the metadata of the node of each pod", func() {
+		nodeGetterMock := &NodeGetterMock{
+			Nodes: map[string]*corev1.Node{node.Name: node},
+		}
+		nodesInfo := &NodesInfo{
+			Driver:  NodeInfo{Name: "driver", NodeName: node.Name},
+			Servers: []NodeInfo{{Name: "server", NodeName: node.Name}},
+			Clients: []NodeInfo{{Name: "client", NodeName: node.Name}},
+		}
+
+		err := AddNodeMetadata(context.Background(), nodeGetterMock, nodesInfo)
+		Expect(err).ToNot(HaveOccurred())
+
+		expected := NodeInfo{
+			NodeName:      node.Name,
+			MachineType:   "c2-standard-8",
+			Zone:          "us-central1-b",
+			KernelVersion: "5.10.133+",
+			CPUPlatform:   "intel-cascade-lake",
+		}
+		for _, info := range []NodeInfo{nodesInfo.Driver, nodesInfo.Servers[0], nodesInfo.Clients[0]} {
+			info.Name = ""
+			Expect(info).To(Equal(expected))
+		}
+		Expect(nodeGetterMock.invocation).To(Equal(1))
+	})
+
+	It("leaves metadata empty when labels are missing", func() {
+		node.Labels = nil
+		nodeGetterMock := &NodeGetterMock{
+			Nodes: map[string]*corev1.Node{node.Name: node},
+		}
+		nodesInfo := &NodesInfo{
+			Driver: NodeInfo{Name: "driver", NodeName: node.Name},
+		}
+
+		err := AddNodeMetadata(context.Background(), nodeGetterMock, nodesInfo)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodesInfo.Driver.MachineType).To(BeEmpty())
+		Expect(nodesInfo.Driver.Zone).To(BeEmpty())
+		Expect(nodesInfo.Driver.CPUPlatform).To(BeEmpty())
+		Expect(nodesInfo.Driver.KernelVersion).To(Equal("5.10.133+"))
+	})
+
+	It("returns an error when a node cannot be fetched", func() {
+		nodeGetterMock := &NodeGetterMock{
+			Error: fmt.Errorf("fake error"),
+		}
+		nodesInfo := &NodesInfo{
+			Driver: NodeInfo{Name: "driver", NodeName: node.Name},
+		}
+
+		err := AddNodeMetadata(context.Background(), nodeGetterMock, nodesInfo)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 type PodListerMock struct {
 	PodList       *corev1.PodList
 	SleepDuration time.Duration
@@ -282,3 +435,260 @@ func (lgm *LoadTestGetterMock) Get(_ context.Context, testName string, opts meta
 
 	return lgm.Loadtest, nil
 }
+
+type NodeGetterMock struct {
+	Nodes      map[string]*corev1.Node
+	Error      error
+	invocation int
+}
+
+var _ NodeGetter = &NodeGetterMock{}
+
+func (ngm *NodeGetterMock) Get(_ context.Context, name string, opts metav1.GetOptions) (*corev1.Node, error) {
+	ngm.invocation++
+
+	if ngm.Error != nil {
+		return nil, ngm.Error
+	}
+
+	return ngm.Nodes[name], nil
+}
+
+var _ = Describe("AddCostAnnotation", func() {
+	var nodesInfo *NodesInfo
+
+	BeforeEach(func() {
+		nodesInfo = &NodesInfo{
+			Driver:  NodeInfo{Name: "driver", MachineType: "e2-standard-2"},
+			Servers: []NodeInfo{{Name: "server", MachineType: "c2-standard-8"}},
+			Clients: []NodeInfo{{Name: "client", MachineType: "c2-standard-8"}},
+		}
+	})
+
+	It("sets the total price per hour of the nodes", func() {
+		meta := &metav1.ObjectMeta{
+			Annotations: map[string]string{"scenario": "go_ping_pong"},
+		}
+
+		err := AddCostAnnotation(meta, nodesInfo, `{"e2-standard-2": 0.067, "c2-standard-8": 0.334}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meta.Annotations).To(HaveKeyWithValue(config.CostPerHourAnnotation, "0.7350"))
+		Expect(meta.Annotations).To(HaveKeyWithValue("scenario", "go_ping_pong"))
+	})
+
+	It("leaves out nodes without a price", func() {
+		nodesInfo.Driver.MachineType = ""
+		meta := &metav1.ObjectMeta{}
+
+		err := AddCostAnnotation(meta, nodesInfo, `{"c2-standard-8": 0.334}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meta.Annotations).To(HaveKeyWithValue(config.CostPerHourAnnotation, "0.6680"))
+	})
+
+	It("returns an error when the prices cannot be parsed", func() {
+		err := AddCostAnnotation(&metav1.ObjectMeta{}, nodesInfo, `not json`)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PodNamespace", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "ready")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns the namespace in the file", func() {
+		namespaceFile := filepath.Join(dir, "namespace")
+		Expect(os.WriteFile(namespaceFile, []byte("team-a\n"), 0644)).To(Succeed())
+		Expect(PodNamespace(namespaceFile)).To(Equal("team-a"))
+	})
+
+	It("returns the default namespace when the file does not exist", func() {
+		Expect(PodNamespace(filepath.Join(dir, "namespace"))).To(Equal(corev1.NamespaceDefault))
+	})
+
+	It("returns the default namespace when the file is empty", func() {
+		namespaceFile := filepath.Join(dir, "namespace")
+		Expect(os.WriteFile(namespaceFile, nil, 0644)).To(Succeed())
+		Expect(PodNamespace(namespaceFile)).To(Equal(corev1.NamespaceDefault))
+	})
+})
+
+var _ = Describe("CheckConnectivity", func() {
+	var listener net.Listener
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("returns nil when every worker can be reached", func() {
+		var dialer net.Dialer
+		unreachable := CheckConnectivity(context.Background(), dialer.DialContext,
+			[]string{"server-0"}, []string{listener.Addr().String()}, time.Second)
+		Expect(unreachable).To(BeNil())
+	})
+
+	It("returns the workers that cannot be reached", func() {
+		dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+			if address == "client-0:10000" {
+				return nil, fmt.Errorf("connection refused")
+			}
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, listener.Addr().String())
+		}
+
+		unreachable := CheckConnectivity(context.Background(), dial,
+			[]string{"server-0", "client-0"}, []string{"server-0:10000", "client-0:10000"}, 100*time.Millisecond)
+		Expect(unreachable).To(Equal([]grpcv1.UnreachableWorker{
+			{Name: "client-0", Address: "client-0:10000", Message: "connection refused"},
+		}))
+	})
+})
+
+var _ = Describe("WriteConnectivityReport", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "ready")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("writes the unreachable workers as JSON", func() {
+		file := filepath.Join(dir, "termination-log")
+		workers := []grpcv1.UnreachableWorker{{Name: "client-0", Address: "client-0:10000", Message: "connection refused"}}
+
+		Expect(WriteConnectivityReport(file, workers)).To(Succeed())
+
+		body, err := os.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(MatchJSON(`{"unreachableWorkers": [{"name": "client-0", "address": "client-0:10000", "message": "connection refused"}]}`))
+	})
+
+	It("drops workers that do not fit in a termination message", func() {
+		file := filepath.Join(dir, "termination-log")
+		var workers []grpcv1.UnreachableWorker
+		for i := 0; i < 200; i++ {
+			workers = append(workers, grpcv1.UnreachableWorker{
+				Name:    fmt.Sprintf("client-%d", i),
+				Address: fmt.Sprintf("client-%d:10000", i),
+				Message: "connection refused",
+			})
+		}
+
+		Expect(WriteConnectivityReport(file, workers)).To(Succeed())
+
+		body, err := os.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(body)).To(BeNumerically("<=", maxTerminationMessageSize))
+		Expect(string(body)).To(ContainSubstring(`"client-0"`))
+	})
+})
+
+// fakeTestUpdaterClient records the RPCs to a test update server, and fails
+// each RPC with the errors queued for it before succeeding.
+type fakeTestUpdaterClient struct {
+	updateErrors []error
+	commitErrors []error
+	updates      []*pb.TestUpdateRequest
+	commits      int
+}
+
+func (c *fakeTestUpdaterClient) UpdateTest(ctx context.Context, in *pb.TestUpdateRequest, opts ...grpc.CallOption) (*pb.TestUpdateReply, error) {
+	c.updates = append(c.updates, in)
+	if len(c.updateErrors) > 0 {
+		err := c.updateErrors[0]
+		c.updateErrors = c.updateErrors[1:]
+		return nil, err
+	}
+	return &pb.TestUpdateReply{PsmServerTargetOverride: "xds:///target"}, nil
+}
+
+func (c *fakeTestUpdaterClient) Commit(ctx context.Context, in *pb.Void, opts ...grpc.CallOption) (*pb.TestUpdateReply, error) {
+	c.commits++
+	if len(c.commitErrors) > 0 {
+		err := c.commitErrors[0]
+		c.commitErrors = c.commitErrors[1:]
+		return nil, err
+	}
+	return &pb.TestUpdateReply{PsmServerTargetOverride: "xds:///target"}, nil
+}
+
+func (c *fakeTestUpdaterClient) QuitTestUpdateServer(ctx context.Context, in *pb.Void, opts ...grpc.CallOption) (*pb.Void, error) {
+	return &pb.Void{}, nil
+}
+
+var _ = Describe("SendTestUpdate", func() {
+	var endpoints []*pb.Endpoint
+
+	BeforeEach(func() {
+		endpoints = []*pb.Endpoint{{IpAddress: "10.0.0.1", Port: 10010}}
+	})
+
+	It("sends and commits the update", func() {
+		c := &fakeTestUpdaterClient{}
+
+		target, err := SendTestUpdate(context.Background(), c, endpoints, true, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("xds:///target"))
+		Expect(c.updates).To(HaveLen(1))
+		Expect(c.updates[0].Endpoints).To(Equal(endpoints))
+		Expect(c.updates[0].IsProxied).To(BeTrue())
+		Expect(c.commits).To(Equal(1))
+	})
+
+	It("retries each step after transient errors", func() {
+		unavailable := grpcstatus.Error(codes.Unavailable, "connection reset")
+		c := &fakeTestUpdaterClient{
+			updateErrors: []error{unavailable, unavailable},
+			commitErrors: []error{grpcstatus.Error(codes.DeadlineExceeded, "timed out")},
+		}
+
+		target, err := SendTestUpdate(context.Background(), c, endpoints, false, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("xds:///target"))
+		Expect(c.updates).To(HaveLen(3))
+		Expect(c.commits).To(Equal(2))
+	})
+
+	It("does not retry other errors", func() {
+		c := &fakeTestUpdaterClient{
+			commitErrors: []error{grpcstatus.Error(codes.FailedPrecondition, "no update to commit")},
+		}
+
+		_, err := SendTestUpdate(context.Background(), c, endpoints, false, time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(c.commits).To(Equal(1))
+	})
+
+	It("returns an error when the context is done", func() {
+		unavailable := grpcstatus.Error(codes.Unavailable, "connection refused")
+		c := &fakeTestUpdaterClient{}
+		for i := 0; i < 1000; i++ {
+			c.updateErrors = append(c.updateErrors, unavailable)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := SendTestUpdate(ctx, c, endpoints, false, time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(c.commits).To(Equal(0))
+	})
+})
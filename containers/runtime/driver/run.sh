@@ -24,10 +24,160 @@ if [ -f /var/data/qps_workers/server_target_override ]; then
   SERVER_TARGET_OVERRIDE=$(cat /var/data/qps_workers/server_target_override)
 fi
 
-/src/code/bazel-bin/test/cpp/qps/qps_json_driver --scenarios_file="${SCENARIOS_FILE}" \
-  --scenario_result_file=scenario_result.json --qps_server_target_override="${SERVER_TARGET_OVERRIDE}"
+declare -r QPS_JSON_DRIVER=/src/code/bazel-bin/test/cpp/qps/qps_json_driver
+declare -r CANCELLED_MARKER_FILE="${CANCELLED_MARKER_FILE:-/tmp/cancelled}"
 
-/src/code/bazel-bin/test/cpp/qps/qps_json_driver --quit=true
+# When the load test is deleted, the preStop hook of this container creates the
+# cancelled marker file before SIGTERM is sent. The driver is then stopped, the
+# workers are asked to quit while their preStop hooks keep them running, and
+# any partial results are uploaded with "cancelled" set to true. A SIGTERM
+# without the marker file comes from the timeout of the pod, in which case the
+# workers are still asked to quit, but no results are uploaded.
+cancel() {
+  trap - TERM
+  set +e
+  kill -TERM "${DRIVER_PID}"
+  wait "${DRIVER_PID}"
+  "${QPS_JSON_DRIVER}" --quit=true
+  if [ -f "${CANCELLED_MARKER_FILE}" ]; then
+    python3 - qps_result.json <<'EOF'
+import json
+import os
+import sys
+
+result = {}
+if os.path.exists(sys.argv[1]):
+    try:
+        with open(sys.argv[1]) as f:
+            result = json.load(f)
+    except ValueError:
+        pass
+result['cancelled'] = True
+with open(sys.argv[1], 'w') as f:
+    json.dump(result, f)
+EOF
+    if [ -n "${RESULTS_URI}" ]; then
+      gsutil cp qps_result.json "${RESULTS_URI}"
+    fi
+  fi
+  exit 143
+}
+
+# The driver runs in the background, so that SIGTERM is handled as soon as it
+# is received rather than when the driver exits.
+run_driver() {
+  "${QPS_JSON_DRIVER}" --scenarios_file="$1" \
+    --scenario_result_file=scenario_result.json --json_file_out=qps_result.json \
+    --qps_server_target_override="${SERVER_TARGET_OVERRIDE}" &
+  DRIVER_PID=$!
+  trap cancel TERM
+  wait "${DRIVER_PID}"
+  trap - TERM
+}
+
+declare -r WORKER_RESTARTS_FILE=worker_restarts.json
+
+if [ -n "${RESTART_WORKERS}" ]; then
+  # Each scenario runs on its own, and the workers are asked to quit after
+  # each scenario but the last. The run container of each worker starts it
+  # again, and the time until every worker accepts connections again is
+  # recorded, so that it can be added to the metadata of the results.
+  SCENARIO_COUNT=$(python3 - "${SCENARIOS_FILE}" <<'EOF'
+import json
+import sys
+
+with open(sys.argv[1]) as f:
+    scenarios = json.load(f)
+entries = scenarios.get('scenarios')
+if not isinstance(entries, list):
+    entries = [entries]
+for i, entry in enumerate(entries):
+    scenarios['scenarios'] = entry
+    with open('scenario_%d.json' % i, 'w') as f:
+        json.dump(scenarios, f)
+print(len(entries))
+EOF
+)
+  echo '[]' > "${WORKER_RESTARTS_FILE}"
+  for ((i = 0; i < SCENARIO_COUNT; i++)); do
+    run_driver "scenario_${i}.json"
+    if ((i + 1 < SCENARIO_COUNT)); then
+      "${QPS_JSON_DRIVER}" --quit=true
+      python3 - "${WORKER_RESTARTS_FILE}" <<'EOF'
+import json
+import os
+import socket
+import sys
+import time
+
+def accepts(address):
+    host, port = address.rsplit(':', 1)
+    try:
+        socket.create_connection((host.strip('[]'), int(port)), timeout=1).close()
+        return True
+    except OSError:
+        return False
+
+start = time.time()
+workers = [w for w in os.environ.get('QPS_WORKERS', '').split(',') if w]
+# Give each worker a few seconds to stop listening, so that the process that
+# was asked to quit is not mistaken for the restarted one.
+for worker in workers:
+    deadline = time.time() + 5
+    while accepts(worker) and time.time() < deadline:
+        time.sleep(0.1)
+for worker in workers:
+    while not accepts(worker):
+        time.sleep(0.1)
+
+with open(sys.argv[1]) as f:
+    restarts = json.load(f)
+restarts.append(round(time.time() - start, 3))
+with open(sys.argv[1], 'w') as f:
+    json.dump(restarts, f)
+EOF
+    fi
+  done
+else
+  run_driver "${SCENARIOS_FILE}"
+fi
+
+"${QPS_JSON_DRIVER}" --quit=true
+
+# Upload the raw output of the driver, so that it remains available after the
+# pods of the test are deleted. The controller records the URI in the status.
+if [ -n "${RESULTS_URI}" ] && [ -r qps_result.json ]; then
+  gsutil cp qps_result.json "${RESULTS_URI}"
+fi
+
+# Print the scenario result between markers, so that the test runner can
+# retrieve it from the log of this container. Tracing is disabled so that
+# commands are not printed between the markers.
+if [ -r scenario_result.json ]; then
+  set +x
+  echo "==== BEGIN SCENARIO RESULT ===="
+  cat scenario_result.json
+  echo
+  echo "==== END SCENARIO RESULT ===="
+  set -x
+fi
+
+# Report key numbers from the results to the controller, which reads them from
+# the termination message of this container and adds them to the test status.
+declare -r TERMINATION_MESSAGE_FILE="${TERMINATION_MESSAGE_FILE:-/dev/termination-log}"
+
+if [ -r scenario_result.json ]; then
+  python3 - scenario_result.json > "${TERMINATION_MESSAGE_FILE}" <<'EOF' || true
+import json
+import sys
+
+with open(sys.argv[1]) as f:
+    summary = json.load(f).get('summary', {})
+keys = ('qps', 'latency50', 'latency99', 'latency999', 'clientSystemTime',
+        'serverSystemTime')
+print(json.dumps({key: summary[key] for key in keys if key in summary}))
+EOF
+fi
 declare -r PROMETHEUS_QUERY_RESULT_FILE=prometheus_query_result.json
 
 if [ -n "${SERVER_TARGET_OVERRIDE}" ] || [ -n "${ENABLE_PROMETHEUS}" ]; then
@@ -43,6 +193,31 @@ fi
 if [ -n "${BQ_RESULT_TABLE}" ]; then
   if [ -r "${METADATA_OUTPUT_FILE}" ]; then
     cp "${METADATA_OUTPUT_FILE}" metadata.json
+    python3 - metadata.json "${WORKER_RESTARTS_FILE}" <<'EOF'
+import datetime
+import json
+import os
+import sys
+
+with open(sys.argv[1]) as f:
+    metadata = json.load(f)
+annotations = metadata.get("annotations") or {}
+rate = annotations.get("e2etest.grpc.io/cost-per-hour")
+created = metadata.get("creationTimestamp")
+if rate and created:
+    start = datetime.datetime.strptime(created, "%Y-%m-%dT%H:%M:%SZ")
+    hours = (datetime.datetime.utcnow() - start).total_seconds() / 3600
+    annotations["e2etest.grpc.io/estimated-cost"] = "%.4f" % (float(rate) * hours)
+    metadata["annotations"] = annotations
+if os.path.exists(sys.argv[2]):
+    with open(sys.argv[2]) as f:
+        restarts = json.load(f)
+    annotations["e2etest.grpc.io/worker-restart-seconds"] = ",".join(
+        "%.3f" % seconds for seconds in restarts)
+    metadata["annotations"] = annotations
+with open(sys.argv[1], "w") as f:
+    json.dump(metadata, f)
+EOF
   fi
   if [ -r "${NODE_INFO_OUTPUT_FILE}" ]; then
     cp "${NODE_INFO_OUTPUT_FILE}" node_info.json
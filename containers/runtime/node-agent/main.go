@@ -0,0 +1,91 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Node agent samples system metrics of the node that it runs on, such as
+// softirqs, context switches and NIC counters, and serves the samples taken
+// within a retention period as JSON over HTTP.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/nodemetrics"
+)
+
+func main() {
+	var port int
+	var procDir string
+	var interval time.Duration
+	var retention time.Duration
+
+	flag.IntVar(&port, "port", config.DefaultNodeAgentPort, "port where samples are served")
+	flag.StringVar(&procDir, "proc-dir", "/proc", "directory where the proc filesystem is mounted")
+	flag.DurationVar(&interval, "interval", config.DefaultNodeAgentSampleIntervalSeconds*time.Second, "time between samples")
+	flag.DurationVar(&retention, "retention", 2*time.Hour, "time that samples are kept")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up logging: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+	l := logger.Sugar()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sampler := &nodemetrics.Sampler{
+		ProcDir:   procDir,
+		Interval:  interval,
+		Retention: retention,
+	}
+	go sampler.Run(ctx, func(err error) {
+		l.Warnf("failed to take sample: %v", err)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(nodemetrics.SamplesPath, sampler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Addr:    net.JoinHostPort("", fmt.Sprint(port)),
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	l.Infof("sampling %s every %v, serving samples on port %d", procDir, interval, port)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		l.Fatalf("failed to serve samples: %v", err)
+	}
+}
@@ -22,7 +22,9 @@ term() {
 }
 trap term TERM
 
-envoy -c /etc/envoy/envoy.yaml &
+# The node ID is set by the controller for each test, so that concurrent
+# tests served by one xDS server do not share configuration.
+envoy -c /etc/envoy/envoy.yaml --service-node "${XDS_NODE_ID:-test_id}" &
 
 PROXY=$!
 wait "$PROXY"
@@ -0,0 +1,243 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command supervisor runs the command of a worker and stops it when the
+// timeout of its load test is exceeded.
+//
+// The controller copies this program into the pods of clients and servers and
+// wraps the command of their run containers with it:
+//
+//	supervisor -- <worker command> [worker args...]
+//
+// The timeout and the time allowed to respond after the timeout are read from
+// the POD_TIMEOUT and KILL_AFTER environment variables, in seconds. When the
+// timeout is exceeded, the command receives SIGTERM, and it receives SIGKILL if
+// it is still running after KILL_AFTER. The action is then written as JSON to
+// the termination message of the container, and the program exits with code
+// 124. Otherwise, the program exits with the code of the command.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/logging"
+)
+
+// ReportFileEnv is the optional name of the environment variable with the path
+// of the file where the action is reported. If it is unset, DefaultReportFile
+// is used.
+const ReportFileEnv = "SUPERVISOR_REPORT_FILE"
+
+// DefaultReportFile is the file where the action is reported by default. It
+// is the file that Kubernetes reads the termination message from.
+const DefaultReportFile = "/dev/termination-log"
+
+// TimeoutExitCode is the exit code of the program when it stopped the command
+// after the timeout. It matches the exit code of the timeout utility.
+const TimeoutExitCode = 124
+
+const (
+	// ActionTerminated is the action when the command exited after it
+	// received SIGTERM.
+	ActionTerminated = "terminated"
+
+	// ActionKilled is the action when the command did not exit after it
+	// received SIGTERM, and was killed with SIGKILL.
+	ActionKilled = "killed"
+)
+
+// Report is the content of the report file.
+type Report struct {
+	// TimeoutAction is the action taken to stop the command.
+	TimeoutAction string `json:"timeoutAction"`
+
+	// TimeoutSeconds is the timeout that was exceeded.
+	TimeoutSeconds float64 `json:"timeoutSeconds"`
+
+	// KillAfterSeconds is the time the command was allowed to respond to
+	// SIGTERM before it was killed.
+	KillAfterSeconds float64 `json:"killAfterSeconds"`
+}
+
+// Limits are the deadlines enforced on the command.
+type Limits struct {
+	// Timeout is the time after which the command receives SIGTERM. The
+	// command is not stopped if it is zero.
+	Timeout time.Duration
+
+	// KillAfter is the time after SIGTERM at which the command receives
+	// SIGKILL.
+	KillAfter time.Duration
+}
+
+// Run starts a command and waits for it to exit, stopping it when the limits
+// are exceeded. Signals received on the channel are forwarded to the command.
+// Run returns the action taken to stop the command, or an empty string if the
+// command exited on its own, and the exit code of the command. An error is
+// returned if the command cannot be started.
+func Run(command []string, limits Limits, signals <-chan os.Signal) (string, int, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return "", 0, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var timeout, kill <-chan time.Time
+	if limits.Timeout > 0 {
+		timer := time.NewTimer(limits.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	action := ""
+	for {
+		select {
+		case err := <-done:
+			return action, exitCode(cmd, err), nil
+		case sig := <-signals:
+			cmd.Process.Signal(sig)
+		case <-timeout:
+			log.Printf("Timeout of %v exceeded, sending SIGTERM to the command", limits.Timeout)
+			action = ActionTerminated
+			cmd.Process.Signal(syscall.SIGTERM)
+			timer := time.NewTimer(limits.KillAfter)
+			defer timer.Stop()
+			kill = timer.C
+			timeout = nil
+		case <-kill:
+			log.Printf("Command still running %v after SIGTERM, sending SIGKILL", limits.KillAfter)
+			action = ActionKilled
+			cmd.Process.Kill()
+			kill = nil
+		}
+	}
+}
+
+// exitCode returns the exit code of a command that has exited. A command that
+// was stopped by a signal has the exit code that a shell would report.
+func exitCode(cmd *exec.Cmd, err error) int {
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return 1
+	}
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return cmd.ProcessState.ExitCode()
+}
+
+// WriteReport writes the action taken to stop the command as JSON to a file.
+func WriteReport(path string, action string, limits Limits) error {
+	data, err := json.Marshal(&Report{
+		TimeoutAction:    action,
+		TimeoutSeconds:   limits.Timeout.Seconds(),
+		KillAfterSeconds: limits.KillAfter.Seconds(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report to %s: %v", path, err)
+	}
+	return nil
+}
+
+// parseSeconds parses the number of seconds in an environment variable. Zero
+// is returned if the variable is unset.
+func parseSeconds(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("$%s must be a non-negative number of seconds, got %q", name, value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseLimits reads the limits from the environment of the container.
+func parseLimits() (Limits, error) {
+	timeout, err := parseSeconds(config.PodTimeoutEnv)
+	if err != nil {
+		return Limits{}, err
+	}
+	killAfter, err := parseSeconds(config.KillAfterEnv)
+	if err != nil {
+		return Limits{}, err
+	}
+	return Limits{Timeout: timeout, KillAfter: killAfter}, nil
+}
+
+func main() {
+	logger, err := logging.Setup(logging.OptionsFromEnv())
+	if err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	command := os.Args[1:]
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		log.Fatalf("usage: %s -- <worker command> [worker args...]", os.Args[0])
+	}
+
+	limits, err := parseLimits()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reportFile := os.Getenv(ReportFileEnv)
+	if reportFile == "" {
+		reportFile = DefaultReportFile
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	action, code, err := Run(command, limits, signals)
+	if err != nil {
+		log.Fatalf("Could not start command: %v", err)
+	}
+	if action == "" {
+		os.Exit(code)
+	}
+
+	if err := WriteReport(reportFile, action, limits); err != nil {
+		log.Printf("Could not write report: %v", err)
+	}
+	log.Printf("Command %s after the timeout of %v", action, limits.Timeout)
+	os.Exit(TimeoutExitCode)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// stuckWorker is a command that ignores SIGTERM, like a worker that is stuck.
+var stuckWorker = []string{"sh", "-c", `trap "" TERM; while :; do sleep 0.1; done`}
+
+var _ = Describe("Run", func() {
+	It("returns the exit code of the command", func() {
+		action, code, err := Run([]string{"sh", "-c", "exit 3"}, Limits{Timeout: time.Minute}, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(BeEmpty())
+		Expect(code).To(Equal(3))
+	})
+
+	It("does not stop the command without a timeout", func() {
+		action, code, err := Run([]string{"sh", "-c", "sleep 0.2"}, Limits{}, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(BeEmpty())
+		Expect(code).To(Equal(0))
+	})
+
+	It("sends SIGTERM to the command after the timeout", func() {
+		action, code, err := Run([]string{"sleep", "30"}, Limits{Timeout: 100 * time.Millisecond, KillAfter: time.Minute}, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(ActionTerminated))
+		Expect(code).To(Equal(128 + int(syscall.SIGTERM)))
+	})
+
+	It("kills the command when it ignores SIGTERM", func() {
+		start := time.Now()
+		action, code, err := Run(stuckWorker, Limits{Timeout: 100 * time.Millisecond, KillAfter: 200 * time.Millisecond}, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(ActionKilled))
+		Expect(code).To(Equal(128 + int(syscall.SIGKILL)))
+		Expect(time.Since(start)).To(BeNumerically(">=", 300*time.Millisecond))
+	})
+
+	It("forwards signals to the command", func() {
+		signals := make(chan os.Signal)
+		go func() {
+			// Give the command time to set its trap before the signal.
+			time.Sleep(200 * time.Millisecond)
+			signals <- syscall.SIGTERM
+		}()
+
+		action, code, err := Run([]string{"sh", "-c", `trap "exit 7" TERM; while :; do sleep 0.1; done`}, Limits{Timeout: time.Minute}, signals)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(BeEmpty())
+		Expect(code).To(Equal(7))
+	})
+
+	It("returns an error when the command cannot be started", func() {
+		_, _, err := Run([]string{"/nonexistent/worker"}, Limits{}, nil)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("WriteReport", func() {
+	It("writes the action and limits as JSON", func() {
+		dir, err := os.MkdirTemp("", "supervisor")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "termination-log")
+
+		Expect(WriteReport(path, ActionKilled, Limits{Timeout: 15 * time.Minute, KillAfter: 30 * time.Second})).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		var report Report
+		Expect(json.Unmarshal(data, &report)).To(Succeed())
+		Expect(report).To(Equal(Report{TimeoutAction: ActionKilled, TimeoutSeconds: 900, KillAfterSeconds: 30}))
+	})
+})
+
+var _ = Describe("parseLimits", func() {
+	AfterEach(func() {
+		os.Unsetenv(config.PodTimeoutEnv)
+		os.Unsetenv(config.KillAfterEnv)
+	})
+
+	It("reads the limits from the environment", func() {
+		os.Setenv(config.PodTimeoutEnv, "900")
+		os.Setenv(config.KillAfterEnv, "2.500000")
+
+		limits, err := parseLimits()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limits).To(Equal(Limits{Timeout: 15 * time.Minute, KillAfter: 2500 * time.Millisecond}))
+	})
+
+	It("disables the timeout when it is unset", func() {
+		limits, err := parseLimits()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(limits.Timeout).To(BeZero())
+	})
+
+	It("rejects invalid values", func() {
+		for _, value := range []string{"soon", "-1"} {
+			os.Setenv(config.PodTimeoutEnv, value)
+
+			_, err := parseLimits()
+
+			Expect(err).To(HaveOccurred(), "value %q", value)
+		}
+	})
+})
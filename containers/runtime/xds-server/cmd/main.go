@@ -17,10 +17,13 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
@@ -30,6 +33,8 @@ import (
 	grpcv1config "github.com/grpc/test-infra/config"
 	xds "github.com/grpc/test-infra/containers/runtime/xds-server"
 	config "github.com/grpc/test-infra/containers/runtime/xds-server/config"
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/proto/endpointupdater"
 
 	_ "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 )
@@ -41,33 +46,92 @@ func main() {
 	var defaultConfigPath string
 	var customConfigPath string
 	var testUpdatePort uint
+	var testUpdateTLSCert string
+	var testUpdateTLSKey string
+	var testUpdateTLSClientCA string
 	var validationOnly bool
 	var pathToBootstrap string
+	var bootstrapTemplatePath string
+	var bootstrapOutputPath string
+	var bootstrapServerHost string
+	var bootstrapServerFeatures string
+	var syntheticEndpoints int
+	var syntheticEndpointMode string
+	var csdsPort uint
+	var csdsInterval time.Duration
+	var watchConfig bool
 
 	// The port that this xDS server listens on
-	flag.UintVar(&xdsServerPort, "xds-server-port", 18000, "xDS management server port, this is where Envoy/gRPC client gets update")
+	flag.UintVar(&xdsServerPort, "xds-server-port", grpcv1config.DefaultXdsServerPort, "xDS management server port, this is where Envoy/gRPC client gets update")
 
 	// The port that endpoint updater server listens on
 	flag.UintVar(&testUpdatePort, "test-update-port", grpcv1config.ServerUpdatePort, "test update server port, this is where test updater pass the endpoints and test type to xds server")
 
-	// Tell Envoy/xDS client to use this Node ID, it is important to match what provided in the bootstrap files
-	flag.StringVar(&nodeID, "node-ID", "test_id", "Node ID")
+	// The endpoint updater server accepts TLS connections when a certificate is set, and requires client certificates when a client CA is also set
+	flag.StringVar(&testUpdateTLSCert, "test-update-tls-cert", "", "path to the PEM certificate of the test update server, the server is insecure if not set")
+	flag.StringVar(&testUpdateTLSKey, "test-update-tls-key", "", "path to the PEM private key of the test update server")
+	flag.StringVar(&testUpdateTLSClientCA, "test-update-tls-client-ca", "", "path to the PEM certificates of the CAs that sign the certificates of test updaters, client certificates are not required if not set")
 
-	// Default configuration path, the path is relative path using ./containers/runtime/xds
-	flag.StringVar(&defaultConfigPath, "default-config-path", "containers/runtime/xds/config/default_config.json", "The path of default configuration file, the path is relative path the root of test-infra repo")
+	// Tell Envoy/xDS client to use this Node ID, it is important to match what provided in the bootstrap files.
+	// The configuration is served to each of the node IDs, so concurrent tests can share one xDS server.
+	defaultNodeID := os.Getenv(grpcv1config.XdsNodeIDEnv)
+	if defaultNodeID == "" {
+		defaultNodeID = grpcv1config.DefaultXdsNodeID
+	}
+	flag.StringVar(&nodeID, "node-ID", defaultNodeID, "comma-separated list of node IDs that the configuration is served to, the first one is written in the bootstrap file, defaults to $XDS_NODE_ID or test_id")
+
+	// Default configuration path, the path is relative path using ./containers/runtime/xds-server
+	flag.StringVar(&defaultConfigPath, "default-config-path", "containers/runtime/xds-server/config/default_config.json", "The path of default configuration file, the path is relative path the root of test-infra repo")
 
-	// User supplied configuration path, the path is relative path using ./containers/runtime/xds
+	// User supplied configuration path, the path is relative path using ./containers/runtime/xds-server
 	flag.StringVar(&customConfigPath, "custom-config-path", "custom-config-path", "The path of user supplied configuration file, the path is relative path the root of test-infra repo")
 
 	// This sets if running validation only
 	flag.BoolVar(&validationOnly, "validate-only", false, "This sets if we are running for the validation only")
 
-	// This set the path to the original bootstrap file in xds container image, if not set the bootstrap will not be moved
-	flag.StringVar(&pathToBootstrap, "path-to-bootstrap", "", "This sets the original path to bootstrap")
+	// This set the path to a pre-built bootstrap file in xds container image, if set the bootstrap is copied instead of generated
+	flag.StringVar(&pathToBootstrap, "path-to-bootstrap", "", "Deprecated: path to a pre-built bootstrap file that is copied instead of generating one")
+
+	// The bootstrap file for proxyless clients is generated from a template, so its values can change without rebuilding the image
+	flag.StringVar(&bootstrapTemplatePath, "bootstrap-template", "", "path to a Go template for the bootstrap file of proxyless clients, the default template is used if not set")
+	flag.StringVar(&bootstrapOutputPath, "bootstrap-output", "/bootstrap/bootstrap.json", "path where the bootstrap file for proxyless clients is written, no bootstrap file is written if empty")
+	flag.StringVar(&bootstrapServerHost, "bootstrap-server-host", "localhost", "host of the xDS server in the bootstrap file, the port is set by -xds-server-port")
+	flag.StringVar(&bootstrapServerFeatures, "bootstrap-server-features", "xds_v3", "comma-separated list of xDS server features in the bootstrap file")
+
+	// Synthetic endpoints are added to the actual backends to benchmark EDS handling and LB policies at scale
+	flag.IntVar(&syntheticEndpoints, "synthetic-endpoints", 0, "number of synthetic endpoints added to the actual backends")
+	flag.StringVar(&syntheticEndpointMode, "synthetic-endpoint-mode", string(config.BackendSyntheticEndpoints), "addresses of synthetic endpoints, either backend to reuse the actual backends or blackhole to use unreachable addresses")
+
+	// The xDS configuration of a proxyless client is fetched through CSDS, so it can be inspected after the test
+	defaultCSDSPort, _ := strconv.ParseUint(os.Getenv(grpcv1config.CSDSPortEnv), 10, 16)
+	flag.UintVar(&csdsPort, "csds-port", uint(defaultCSDSPort), "port where the proxyless client serves CSDS, the configuration of the client is not fetched if zero, defaults to $CSDS_PORT")
+	flag.DurationVar(&csdsInterval, "csds-interval", 5*time.Second, "interval between fetches of the configuration of the proxyless client through CSDS")
 
+	// The configuration files are always reloaded on SIGHUP, and also whenever they change if watched
+	flag.BoolVar(&watchConfig, "watch-config", false, "reload the configuration files whenever they change, they are only reloaded on SIGHUP if not set")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	l := xds.Logger{}
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up logging: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	l := xds.Logger{Sugar: logger.Sugar()}
+
+	var nodeIDs []string
+	for _, id := range strings.Split(nodeID, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			nodeIDs = append(nodeIDs, id)
+		}
+	}
+	if len(nodeIDs) == 0 {
+		nodeIDs = []string{grpcv1config.DefaultXdsNodeID}
+	}
 
 	// Create and validate the configuration of the xDS server first
 	snapshot, err := config.GenerateSnapshotFromConfigFiles(defaultConfigPath, customConfigPath)
@@ -85,28 +149,43 @@ func main() {
 	if validationOnly {
 		return
 	}
-	// Move the bootstrap file for proxyless client. The bootstrap files need to be
-	// accessible to the proxyless client, this logic helps move the bootstrap.json stored
-	// in xds server image to a shared volume between xds server and proxyless client at
-	// /bootstrap/bootstrap.json. From the root of the test-infra
-	// repo, the bootstrap file was originally stored in the path of
-	// contaners/runtime/xds/bootstrap.json, user can provide other path within
-	// test-infra, any changes related to this requires building a new xds server image.
-	if pathToBootstrap != "" {
-		bootstrapBytes, err := ioutil.ReadFile(pathToBootstrap)
-		if err != nil {
-			l.Errorf("fail to read bootstrap: %v", err)
-		}
-		//Copy all the contents to the desitination file
-		err = ioutil.WriteFile(fmt.Sprintf("%v/bootstrap.json", "/bootstrap"), bootstrapBytes, 0755)
-		if err != nil {
-			l.Errorf("fail to output bootstrap.json to /bootstrap: %v", err)
+	// Write the bootstrap file for proxyless client. The bootstrap file needs to be
+	// accessible to the proxyless client, so it is written to a shared volume between
+	// xds server and proxyless client at /bootstrap/bootstrap.json by default. The
+	// bootstrap file is generated from a template, filling in the node ID and the
+	// address of this xDS server. A pre-built bootstrap file can still be copied with
+	// -path-to-bootstrap, but any changes to that file require a new xds server image.
+	if bootstrapOutputPath != "" {
+		if pathToBootstrap != "" {
+			bootstrapBytes, err := os.ReadFile(pathToBootstrap)
+			if err != nil {
+				l.Errorf("fail to read bootstrap: %v", err)
+			}
+			//Copy all the contents to the desitination file
+			err = os.WriteFile(bootstrapOutputPath, bootstrapBytes, 0755)
+			if err != nil {
+				l.Errorf("fail to output bootstrap.json to %v: %v", bootstrapOutputPath, err)
+			}
+			l.Infof("bootstrap file for non-proxied clients are moved from %v to %v successfully", pathToBootstrap, bootstrapOutputPath)
+		} else {
+			var serverFeatures []string
+			if bootstrapServerFeatures != "" {
+				serverFeatures = strings.Split(bootstrapServerFeatures, ",")
+			}
+			bootstrapValues := &config.BootstrapValues{
+				NodeID:         nodeIDs[0],
+				ServerURI:      net.JoinHostPort(bootstrapServerHost, fmt.Sprint(xdsServerPort)),
+				ServerFeatures: serverFeatures,
+			}
+			if err := config.GenerateBootstrapFile(bootstrapTemplatePath, bootstrapOutputPath, bootstrapValues); err != nil {
+				l.Errorf("fail to generate bootstrap for non-proxied clients: %v", err)
+			}
+			l.Infof("bootstrap file for non-proxied clients is generated at %v with values %+v", bootstrapOutputPath, *bootstrapValues)
 		}
-		l.Infof("bootstrap file for non-proxied clients are moved from %v to %v/bootstrap.json successfully", pathToBootstrap, "/bootstrap")
 	}
 
 	// Create a cache
-	cache := cache.NewSnapshotCache(false, cache.IDHash{}, l)
+	snapshotCache := cache.NewSnapshotCache(false, cache.IDHash{}, l)
 
 	// Start the endpoint update server
 	testChannel := make(chan xds.TestInfo)
@@ -114,40 +193,106 @@ func main() {
 	// Don't need to handle this server since if the test was terminated
 	// at this stage there must be something wrong with the test, no need
 	// for grace termination.
-	go xds.RunUpdateServer(testChannel, testUpdatePort, &snapshot)
+	var updateServerOpts []grpc.ServerOption
+	if testUpdateTLSCert != "" {
+		creds, err := endpointupdater.ServerCredentials(testUpdateTLSCert, testUpdateTLSKey, testUpdateTLSClientCA)
+		if err != nil {
+			l.Errorf("fail to load credentials for the test update server: %v", err)
+		}
+		updateServerOpts = append(updateServerOpts, grpc.Creds(creds))
+	} else if testUpdateTLSClientCA != "" {
+		l.Errorf("a client CA for the test update server requires a certificate and key")
+	}
+	go xds.RunUpdateServer(testChannel, testUpdatePort, &snapshot, updateServerOpts...)
 
 	var testInfo xds.TestInfo
 	testInfo, ok := <-testChannel
 	if ok {
-		// Update test endpoint and type for the snapshot resource
-		endpoints := testInfo.Endpoints
-		if err := config.UpdateEndpoint(&snapshot, endpoints); err != nil {
-			l.Errorf("fail to update endpoint for xDS server: %v", err)
-		}
+		// The information of the test is applied to the snapshot generated
+		// at startup, and again to each snapshot reloaded from the
+		// configuration files
+		prepare := func(snapshot *cache.Snapshot) error {
+			// Update test endpoint and type for the snapshot resource
+			endpoints := testInfo.Endpoints
+			if err := config.UpdateEndpoint(snapshot, endpoints); err != nil {
+				return fmt.Errorf("fail to update endpoint for xDS server: %v", err)
+			}
 
-		// Check the type of the test
-		if testInfo.IsProxied {
-			l.Infof("running a proxied test, only leave socket listeners for validation reason, api_listeners are not presented to proxies")
-			if err := config.IncludeSocketListenerOnly(&snapshot); err != nil {
-				l.Errorf("fail to filter listener based on test type: %v", err)
+			// Add synthetic endpoints after the actual backends
+			if syntheticEndpoints > 0 {
+				synthetic, err := config.GenerateSyntheticEndpoints(endpoints, syntheticEndpoints, config.SyntheticEndpointMode(syntheticEndpointMode))
+				if err != nil {
+					return fmt.Errorf("fail to generate synthetic endpoints for xDS server: %v", err)
+				}
+				if err := config.AddEndpoints(snapshot, synthetic); err != nil {
+					return fmt.Errorf("fail to add synthetic endpoints for xDS server: %v", err)
+				}
+				l.Infof("added %d synthetic endpoints in %v mode", len(synthetic), syntheticEndpointMode)
 			}
-			if err := snapshot.Consistent(); err != nil {
-				l.Errorf("fail to validate snapshot after leave only socket listeners: %v", err)
+
+			// Check the type of the test
+			if testInfo.IsProxied {
+				l.Infof("running a proxied test, only leave socket listeners for validation reason, api_listeners are not presented to proxies")
+				if err := config.IncludeSocketListenerOnly(snapshot); err != nil {
+					return fmt.Errorf("fail to filter listener based on test type: %v", err)
+				}
+				if err := snapshot.Consistent(); err != nil {
+					return fmt.Errorf("fail to validate snapshot after leave only socket listeners: %v", err)
+				}
 			}
+			return nil
+		}
+		if err := prepare(&snapshot); err != nil {
+			l.Errorf("%v", err)
 		}
 
 		l.Infof("will serve snapshot %+v", snapshot)
 
-		// Add the snapshot to the cache
-		if err := cache.SetSnapshot(context.Background(), nodeID, snapshot); err != nil {
-			l.Errorf("snapshot error %q for %+v", err, snapshot)
+		// Add the snapshot to the cache for each node ID
+		for _, id := range nodeIDs {
+			if err := snapshotCache.SetSnapshot(context.Background(), id, &snapshot); err != nil {
+				l.Errorf("snapshot error %q for node %v: %+v", err, id, snapshot)
+			}
 		}
+		l.Infof("serving snapshot to node IDs %v", nodeIDs)
 		ctx := context.Background()
 		cb := &test.Callbacks{Debug: true}
-		srv := server.NewServer(ctx, cache, cb)
+		srv := server.NewServer(ctx, snapshotCache, cb)
 
 		grpcServer := grpc.NewServer()
 
+		// The configuration of a proxyless client is dumped to the log when
+		// the client stops, or when this server is shut down
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		watchDone := make(chan struct{})
+		if csdsPort != 0 && !testInfo.IsProxied {
+			go func() {
+				defer close(watchDone)
+				xds.WatchClientStatus(watchCtx, net.JoinHostPort("localhost", fmt.Sprint(csdsPort)), csdsInterval, os.Stdout, l)
+			}()
+		} else {
+			close(watchDone)
+		}
+
+		// The configuration files are reloaded on SIGHUP, so changes to the
+		// resources are served without restarting the pod
+		reloadCtx, stopReload := context.WithCancel(ctx)
+		reloadSigs := make(chan os.Signal, 1)
+		signal.Notify(reloadSigs, syscall.SIGHUP)
+		reloader := &xds.Reloader{
+			DefaultConfigPath: defaultConfigPath,
+			CustomConfigPath:  customConfigPath,
+			Prepare:           prepare,
+			Cache:             snapshotCache,
+			NodeIDs:           nodeIDs,
+			Logger:            l,
+		}
+		go func() {
+			if err := reloader.Watch(reloadCtx, reloadSigs, watchConfig); err != nil {
+				l.Warnf("configuration is not reloaded: %v", err)
+			}
+		}()
+
 		// This is to gracefully shutdown the xds server
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGTERM)
@@ -155,10 +300,15 @@ func main() {
 			sig, ok := <-sigs
 			l.Infof("test complete, gracefully shutting down xds server, shutting down on %v", sig)
 			if ok {
+				stopWatch()
+				stopReload()
 				grpcServer.GracefulStop()
 			}
 		}()
 
 		xds.RunxDSServer(ctx, srv, xdsServerPort, grpcServer)
+		stopWatch()
+		stopReload()
+		<-watchDone
 	}
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBootstrapTemplate is the template used to generate the bootstrap file
+// for proxyless clients when no template file is supplied. It points the
+// client at an xDS server over an insecure channel.
+const DefaultBootstrapTemplate = `{
+  "xds_servers": [
+    {
+      "server_uri": {{ json .ServerURI }},
+      "channel_creds": [
+        {
+          "type": "insecure"
+        }
+      ],
+      "server_features": {{ json .ServerFeatures }}
+    }
+  ],
+  "node": {
+    "id": {{ json .NodeID }}
+  }
+}
+`
+
+// BootstrapValues holds the values that are substituted into a bootstrap
+// template.
+type BootstrapValues struct {
+	// NodeID is the ID of the node, which must match the node ID used by the
+	// xDS server to serve its snapshot.
+	NodeID string
+
+	// ServerURI is the address of the xDS server, such as localhost:18000.
+	ServerURI string
+
+	// ServerFeatures lists the features supported by the xDS server.
+	ServerFeatures []string
+}
+
+// GenerateBootstrap executes a bootstrap template with the given values and
+// returns the resulting bootstrap file. Values are inserted into the template
+// with the json function, which quotes and escapes them. An error is returned
+// if the template is invalid or does not produce valid JSON.
+func GenerateBootstrap(templateText string, values *BootstrapValues) ([]byte, error) {
+	tmpl, err := template.New("bootstrap").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(templateText)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse bootstrap template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, errors.Wrap(err, "failed to execute bootstrap template")
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.New("bootstrap template did not produce valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateBootstrapFile generates a bootstrap file from the template at
+// templatePath, or from DefaultBootstrapTemplate if templatePath is empty,
+// and writes it to outputPath.
+func GenerateBootstrapFile(templatePath string, outputPath string, values *BootstrapValues) error {
+	templateText := DefaultBootstrapTemplate
+	if templatePath != "" {
+		templateBytes, err := os.ReadFile(templatePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read bootstrap template %s", templatePath)
+		}
+		templateText = string(templateBytes)
+	}
+
+	bootstrap, err := GenerateBootstrap(templateText, values)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputPath, bootstrap, 0755); err != nil {
+		return errors.Wrapf(err, "failed to write bootstrap to %s", outputPath)
+	}
+	return nil
+}
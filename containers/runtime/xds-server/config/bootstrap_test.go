@@ -0,0 +1,131 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GenerateBootstrap", func() {
+	var values *BootstrapValues
+
+	BeforeEach(func() {
+		values = &BootstrapValues{
+			NodeID:         "test_id",
+			ServerURI:      "localhost:18000",
+			ServerFeatures: []string{"xds_v3"},
+		}
+	})
+
+	It("fills in the values in the default template", func() {
+		bootstrap, err := GenerateBootstrap(DefaultBootstrapTemplate, values)
+		Expect(err).ToNot(HaveOccurred())
+
+		var actual map[string]interface{}
+		Expect(json.Unmarshal(bootstrap, &actual)).To(Succeed())
+
+		servers := actual["xds_servers"].([]interface{})
+		Expect(servers).To(HaveLen(1))
+		server := servers[0].(map[string]interface{})
+		Expect(server["server_uri"]).To(Equal("localhost:18000"))
+		Expect(server["server_features"]).To(Equal([]interface{}{"xds_v3"}))
+		Expect(actual["node"]).To(Equal(map[string]interface{}{"id": "test_id"}))
+	})
+
+	It("escapes values that contain quotes", func() {
+		values.NodeID = `node "1"`
+
+		bootstrap, err := GenerateBootstrap(DefaultBootstrapTemplate, values)
+		Expect(err).ToNot(HaveOccurred())
+
+		var actual map[string]interface{}
+		Expect(json.Unmarshal(bootstrap, &actual)).To(Succeed())
+		Expect(actual["node"]).To(Equal(map[string]interface{}{"id": `node "1"`}))
+	})
+
+	It("returns an error when the template is invalid", func() {
+		_, err := GenerateBootstrap(`{"node": {"id": {{ json .NodeID }}}`, values)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the template refers to an unknown value", func() {
+		_, err := GenerateBootstrap(`{"node": {"id": {{ json .Unknown }}}}`, values)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the template does not produce JSON", func() {
+		_, err := GenerateBootstrap(`node: {{ .NodeID }}`, values)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GenerateBootstrapFile", func() {
+	var dir string
+	var values *BootstrapValues
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "bootstrap")
+		Expect(err).ToNot(HaveOccurred())
+
+		values = &BootstrapValues{
+			NodeID:         "test_id",
+			ServerURI:      "localhost:18000",
+			ServerFeatures: []string{"xds_v3"},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("writes a bootstrap file from a template file", func() {
+		templatePath := filepath.Join(dir, "bootstrap.json.tmpl")
+		outputPath := filepath.Join(dir, "bootstrap.json")
+		Expect(os.WriteFile(templatePath, []byte(`{"node": {"id": {{ json .NodeID }}, "cluster": "test"}}`), 0644)).To(Succeed())
+
+		Expect(GenerateBootstrapFile(templatePath, outputPath, values)).To(Succeed())
+
+		bootstrap, err := os.ReadFile(outputPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(MatchJSON(`{"node": {"id": "test_id", "cluster": "test"}}`))
+	})
+
+	It("writes a bootstrap file from the default template", func() {
+		outputPath := filepath.Join(dir, "bootstrap.json")
+
+		Expect(GenerateBootstrapFile("", outputPath, values)).To(Succeed())
+
+		bootstrap, err := os.ReadFile(outputPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(MatchJSON(`{
+			"xds_servers": [{
+				"server_uri": "localhost:18000",
+				"channel_creds": [{"type": "insecure"}],
+				"server_features": ["xds_v3"]
+			}],
+			"node": {"id": "test_id"}
+		}`))
+	})
+
+	It("returns an error when the template file does not exist", func() {
+		err := GenerateBootstrapFile(filepath.Join(dir, "missing"), filepath.Join(dir, "bootstrap.json"), values)
+		Expect(err).To(HaveOccurred())
+	})
+})
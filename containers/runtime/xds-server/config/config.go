@@ -77,34 +77,48 @@ func (cs customSnapshot) MarshalJSON() ([]byte, error) {
 
 // MarshalJSON is custom MarshalJSON() for customResource struct
 func (cr customResource) MarshalJSON() ([]byte, error) {
-	anydata, _ := anypb.New(cr.Resource)
+	anydata, err := anypb.New(cr.Resource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert resource to proto.any message")
+	}
 	return protojson.Marshal(anydata)
 }
 
-// UnmarshalJSON is custom UnmarshalJSON() for CustomSnapshot struct
+// UnmarshalJSON is custom UnmarshalJSON() for CustomSnapshot struct. The
+// snapshot is usually read from a configuration supplied by users, so every
+// malformed part of it is reported as an error rather than skipped.
 func (cs *customSnapshot) UnmarshalJSON(data []byte) error {
 	var values map[string]json.RawMessage
-	json.Unmarshal(data, &values)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal snapshot")
+	}
+	if values == nil {
+		return errors.New("snapshot must be a JSON object")
+	}
 
 	// unmarshal VersionMap
 	versionMap := make(map[string]map[string]string)
-	if err := json.Unmarshal(values["VersionMap"], &versionMap); err != nil {
-		return errors.Wrapf(err, "failed to unmarshal VersionMap")
+	if versionMapContent, ok := values["VersionMap"]; ok {
+		if err := json.Unmarshal(versionMapContent, &versionMap); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal VersionMap")
+		}
 	}
 	cs.VersionMap = versionMap
 
 	// unmarshal data to cache.Resources
-	var allResourcesData [types.UnknownType]json.RawMessage
+	var allResourcesData []json.RawMessage
 	if resourcesContent, ok := values["Resources"]; ok {
 		if err := json.Unmarshal(resourcesContent, &allResourcesData); err != nil {
 			return errors.Wrapf(err, "failed to obtain json.RawMessage of the caches.Resources")
 		}
 	}
+	if len(allResourcesData) > int(types.UnknownType) {
+		return errors.Errorf("snapshot has %d lists of resources, at most %d are supported", len(allResourcesData), types.UnknownType)
+	}
 
 	var constructedResources [types.UnknownType]cache.Resources
 
-	for _, typedResourceData := range allResourcesData {
-		var resourceType types.ResponseType
+	for index, typedResourceData := range allResourcesData {
 		var typedResources map[string]json.RawMessage
 
 		if typedResourceData == nil {
@@ -112,39 +126,53 @@ func (cs *customSnapshot) UnmarshalJSON(data []byte) error {
 		}
 
 		if err := json.Unmarshal(typedResourceData, &typedResources); err != nil {
-			return errors.Wrapf(err, "failed to obtain json.RawMessage of the types.Resource")
+			return errors.Wrapf(err, "failed to obtain json.RawMessage of the types.Resource at index %d", index)
 		}
 
 		itemsData := make(map[string]json.RawMessage)
 		if itemsContent, ok := typedResources["Items"]; ok {
 			if err := json.Unmarshal(itemsContent, &itemsData); err != nil {
-				return errors.Wrapf(err, "failed to obtain json.RawMessage of the list of individual types.Resource")
+				return errors.Wrapf(err, "failed to obtain json.RawMessage of the list of individual types.Resource at index %d", index)
 			}
 		}
 
+		// skip placeholders
+		if len(itemsData) == 0 {
+			continue
+		}
+
+		resourceType := types.UnknownType
 		constructedItems := make(map[string]types.ResourceWithTTL)
-		i := 0
 		for resourceWithTTLName, resourceWithTTLData := range itemsData {
 			var resourceWithTTL map[string]json.RawMessage
 			if err := json.Unmarshal(resourceWithTTLData, &resourceWithTTL); err != nil {
-				return errors.Wrapf(err, "failed to obtain json.RawMessage of the individual types.ResourceWithTTL")
+				return errors.Wrapf(err, "failed to obtain json.RawMessage of the individual types.ResourceWithTTL %q", resourceWithTTLName)
 			}
 
 			// get Resource
-			customeResource := customResource{}
-			if resourceContent, ok := resourceWithTTL["Resource"]; ok {
-				if i == 0 {
-					// check the actual type of the current resource regardles of the order
-					var rt anypb.Any
-					if err := protojson.Unmarshal(resourceContent, &rt); err != nil {
-						return errors.Wrapf(err, "failed to unmarshal proto.any message to determine the resource type")
-					}
-					resourceType = cache.GetResponseType(rt.TypeUrl)
-				}
+			resourceContent, ok := resourceWithTTL["Resource"]
+			if !ok || string(resourceContent) == "null" {
+				return errors.Errorf("resource %q has no Resource", resourceWithTTLName)
+			}
 
-				if err := json.Unmarshal(resourceContent, &customeResource); err != nil {
-					return errors.Wrapf(err, "failed to unmarshal customeResource")
-				}
+			// check the actual type of each resource, since all resources in
+			// a list must have the same type
+			var rt anypb.Any
+			if err := protojson.Unmarshal(resourceContent, &rt); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal proto.any message to determine the type of resource %q", resourceWithTTLName)
+			}
+			itemType := cache.GetResponseType(rt.TypeUrl)
+			if itemType == types.UnknownType {
+				return errors.Errorf("resource %q has unsupported type %q", resourceWithTTLName, rt.TypeUrl)
+			}
+			if resourceType != types.UnknownType && itemType != resourceType {
+				return errors.Errorf("resource %q has type %q, which differs from the other resources at index %d", resourceWithTTLName, rt.TypeUrl, index)
+			}
+			resourceType = itemType
+
+			customeResource := customResource{}
+			if err := json.Unmarshal(resourceContent, &customeResource); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal customeResource %q", resourceWithTTLName)
 			}
 
 			// get TTL
@@ -152,9 +180,9 @@ func (cs *customSnapshot) UnmarshalJSON(data []byte) error {
 			if ttlContent, ok := resourceWithTTL["TTL"]; ok {
 				if string(ttlContent) != "null" {
 					var tmpTTL *time.Duration
-					err := json.Unmarshal(resourceWithTTL["TTL"], &tmpTTL)
+					err := json.Unmarshal(ttlContent, &tmpTTL)
 					if err != nil {
-						return errors.Wrapf(err, "failed to unmarshal TTL")
+						return errors.Wrapf(err, "failed to unmarshal TTL of resource %q", resourceWithTTLName)
 					}
 					ttl = tmpTTL
 				} else {
@@ -167,19 +195,17 @@ func (cs *customSnapshot) UnmarshalJSON(data []byte) error {
 				TTL:      ttl,
 				Resource: customeResource.Resource,
 			}
-			i++
 		}
 
-		// skip placeholders
-		if len(constructedItems) == 0 {
-			continue
+		if len(constructedResources[resourceType].Items) > 0 {
+			return errors.Errorf("resources of type %v are listed more than once", resourceType)
 		}
 
 		// construct typedResources
 		var version string
 		if versionContent, ok := typedResources["Version"]; ok {
 			if err := json.Unmarshal(versionContent, &version); err != nil {
-				return errors.Wrapf(err, "failed to unmarshal version")
+				return errors.Wrapf(err, "failed to unmarshal version at index %d", index)
 			}
 		}
 		constructedResources[resourceType] = cache.Resources{
@@ -274,6 +300,8 @@ func (cr *customResource) UnmarshalJSON(data []byte) error {
 			return errors.Wrapf(err, "failed to validate the parsed resource: %v", resource.ExtensionConfigType)
 		}
 		cr.Resource = &parsedExtensionConfig
+	default:
+		return errors.Errorf("unsupported resource type %q", a.TypeUrl)
 	}
 	return nil
 }
@@ -320,12 +348,7 @@ func GenerateSnapshotFromConfigFiles(defaultConfigPath string, userSuppliedConfi
 	// compare default config and user supplied config, if user have supplied
 	// the resouce the xDS server will server user supplied config, otherwise
 	// the default config will be supplied
-	var resources *[types.UnknownType]cache.Resources
-	snap := customSnapshot{
-		cache.Snapshot{
-			Resources: *resources,
-		},
-	}
+	snap := customSnapshot{}
 	for resourceType := range snap.Resources {
 		items := make(map[string]types.ResourceWithTTL)
 		// check if user have supplied config for this resource type
@@ -358,66 +381,114 @@ func GenerateSnapshotFromConfigFiles(defaultConfigPath string, userSuppliedConfi
 
 // UpdateEndpoint takes a list of endpoints to updated the Endpoint resources in the snapshot
 func UpdateEndpoint(snap *cache.Snapshot, endpoints []TestEndpoint) error {
-	// currently we only support one cluster, get the endpointName from the cluster resource
-	// break after the d first cluster
+	endpointName, endpointService, err := clusterLoadAssignment(snap)
+	if err != nil {
+		return err
+	}
+	if endpointService == nil {
+		return nil
+	}
+
+	// check if endpoint number is correct
+	allConfiguredBackends := 0
+	for _, localityLbEndpoints := range endpointService.GetEndpoints() {
+		allConfiguredBackends += len(localityLbEndpoints.LbEndpoints)
+	}
+
+	if len(endpoints) != allConfiguredBackends {
+		return errors.New(fmt.Sprintf("number of endpoint supplied from config : %v is different from the actual number of backends: %v \n", allConfiguredBackends, len(endpoints)))
+	}
+	if len(endpointService.GetEndpoints()) == 0 {
+		return errors.Errorf("no locality found in endpoint resource %q to update", endpointName)
+	}
+
+	// update the endpoints, so far all actual backends are supplied to the same locality group
+	updatedEndpoints := []*endpoint.LbEndpoint{}
+	for _, eachBackend := range endpoints {
+		updatedEndpoints = append(updatedEndpoints, newLbEndpoint(eachBackend))
+	}
+	endpointService.GetEndpoints()[0].LbEndpoints = updatedEndpoints
+	snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[endpointName] = types.ResourceWithTTL{Resource: endpointService}
+	return nil
+}
+
+// AddEndpoints takes a list of endpoints and appends them to the Endpoint
+// resources in the snapshot, in the same locality group as the actual
+// backends. Unlike UpdateEndpoint, the number of endpoints does not need to
+// match the number of configured backends.
+func AddEndpoints(snap *cache.Snapshot, endpoints []TestEndpoint) error {
+	endpointName, endpointService, err := clusterLoadAssignment(snap)
+	if err != nil {
+		return err
+	}
+	if endpointService == nil {
+		return errors.New("no cluster found to add endpoints to")
+	}
+	if len(endpointService.GetEndpoints()) == 0 {
+		return errors.Errorf("no locality found in endpoint resource %q to add endpoints to", endpointName)
+	}
+
+	localityLbEndpoints := endpointService.GetEndpoints()[0]
+	for _, eachEndpoint := range endpoints {
+		localityLbEndpoints.LbEndpoints = append(localityLbEndpoints.LbEndpoints, newLbEndpoint(eachEndpoint))
+	}
+	snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[endpointName] = types.ResourceWithTTL{Resource: endpointService}
+	return nil
+}
+
+// clusterLoadAssignment returns the name and a copy of the Endpoint resource
+// associated with the cluster in the snapshot. Currently we only support one
+// cluster, so the first cluster is used. A nil resource is returned if there
+// is no cluster.
+func clusterLoadAssignment(snap *cache.Snapshot) (string, *endpoint.ClusterLoadAssignment, error) {
 	for _, clusterResource := range snap.Resources[int(cache.GetResponseType(resource.ClusterType))].Items {
 		// get the cluster resource to obtain the endpoint name associated with the cluster
 		clusterData, err := protojson.Marshal(clusterResource.Resource)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 		curCluster := cluster.Cluster{}
 		if err := protojson.Unmarshal(clusterData, &curCluster); err != nil {
-			return err
+			return "", nil, err
 		}
 
-		// check if endpoint number is correct
-		endpointResource := snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[curCluster.GetEdsClusterConfig().ServiceName].Resource
+		endpointName := curCluster.GetEdsClusterConfig().GetServiceName()
+		endpointResource := snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[endpointName].Resource
+		if endpointResource == nil {
+			return "", nil, errors.Errorf("cluster %q refers to endpoint resource %q, which is not configured", curCluster.GetName(), endpointName)
+		}
 		endpointData, err := protojson.Marshal(endpointResource)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
 		endpointService := endpoint.ClusterLoadAssignment{}
 		if err := protojson.Unmarshal(endpointData, &endpointService); err != nil {
-			return err
-		}
-
-		allConfiguredBackends := 0
-		for _, localityLbEndpoints := range endpointService.GetEndpoints() {
-			allConfiguredBackends += len(localityLbEndpoints.LbEndpoints)
-		}
-
-		if len(endpoints) != allConfiguredBackends {
-			return errors.New(fmt.Sprintf("number of endpoint supplied from config : %v is different from the actual number of backends: %v \n", allConfiguredBackends, len(endpoints)))
+			return "", nil, err
 		}
+		return endpointName, &endpointService, nil
+	}
+	return "", nil, nil
+}
 
-		// update the endpoints, so far all actual backends are supplied to the same locality group
-		updatedEndpoints := []*endpoint.LbEndpoint{}
-		for _, eachBackend := range endpoints {
-			curEndpoint := endpoint.LbEndpoint{
-				HostIdentifier: &endpoint.LbEndpoint_Endpoint{
-					Endpoint: &endpoint.Endpoint{
-						Address: &core.Address{
-							Address: &core.Address_SocketAddress{
-								SocketAddress: &core.SocketAddress{
-									Protocol: core.SocketAddress_TCP,
-									Address:  eachBackend.TestUpstreamHost,
-									PortSpecifier: &core.SocketAddress_PortValue{
-										PortValue: eachBackend.TestUpstreamPort,
-									},
-								},
+// newLbEndpoint returns an Endpoint resource for a backend.
+func newLbEndpoint(backend TestEndpoint) *endpoint.LbEndpoint {
+	return &endpoint.LbEndpoint{
+		HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+			Endpoint: &endpoint.Endpoint{
+				Address: &core.Address{
+					Address: &core.Address_SocketAddress{
+						SocketAddress: &core.SocketAddress{
+							Protocol: core.SocketAddress_TCP,
+							Address:  backend.TestUpstreamHost,
+							PortSpecifier: &core.SocketAddress_PortValue{
+								PortValue: backend.TestUpstreamPort,
 							},
 						},
 					},
 				},
-			}
-			updatedEndpoints = append(updatedEndpoints, &curEndpoint)
-		}
-		endpointService.GetEndpoints()[0].LbEndpoints = updatedEndpoints
-		snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[curCluster.GetEdsClusterConfig().ServiceName] = types.ResourceWithTTL{Resource: &endpointService}
-		break
+			},
+		},
 	}
-	return nil
 }
 
 // IncludeSocketListenerOnly takes a pointer of a snapshot, and returns only the socket listeners.
@@ -437,7 +508,7 @@ func IncludeSocketListenerOnly(snap *cache.Snapshot) error {
 		if err := protojson.Unmarshal(listenerData, &curlistener); err != nil {
 			return err
 		}
-		if curlistener.GetApiListener() == nil && curlistener.GetAddress().Address != nil {
+		if curlistener.GetApiListener() == nil && curlistener.GetAddress().GetAddress() != nil {
 			socketListenerOnly[listenerName] = types.ResourceWithTTL{
 				Resource: &curlistener,
 				TTL:      listenerResource.TTL,
@@ -488,8 +559,8 @@ func ConstructProxiedTestTarget(snap *cache.Snapshot) (string, error) {
 		if err := protojson.Unmarshal(listenerData, &curlistener); err != nil {
 			return "", err
 		}
-		if curlistener.GetApiListener() == nil && curlistener.GetAddress().Address != nil {
-			envoyPort := curlistener.Address.GetSocketAddress().GetPortValue()
+		if curlistener.GetApiListener() == nil && curlistener.GetAddress().GetAddress() != nil {
+			envoyPort := curlistener.GetAddress().GetSocketAddress().GetPortValue()
 			constructedServerTarget := "localhost:" + fmt.Sprint(envoyPort)
 			return constructedServerTarget, nil
 		}
@@ -16,7 +16,10 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -66,7 +69,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{endpointOnly}
+		originalConfig = customSnapshot{*endpointOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -97,7 +100,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{routeConfigOnly}
+		originalConfig = customSnapshot{*routeConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -128,7 +131,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{clusterConfigOnly}
+		originalConfig = customSnapshot{*clusterConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -164,7 +167,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{listenerOnly}
+		originalConfig = customSnapshot{*listenerOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -208,7 +211,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{runtimeConfigOnly}
+		originalConfig = customSnapshot{*runtimeConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -245,7 +248,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{secretConfigOnly}
+		originalConfig = customSnapshot{*secretConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -277,7 +280,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{extensionConfigOnly}
+		originalConfig = customSnapshot{*extensionConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -303,13 +306,13 @@ var _ = Describe("config marshal and unmarshal", func() {
 		scopedRouteConfigOnly, err := cache.NewSnapshotWithTTLs(currentVersion, map[resource.Type][]types.ResourceWithTTL{
 			currentResourceType: {
 				types.ResourceWithTTL{
-					Resource: testres.MakeScopedRoute("scopedRouteName", testRouteName, []string{"1.2.3.4"}),
+					Resource: testres.MakeScopedRouteConfig("scopedRouteName", testRouteName, []string{"1.2.3.4"}),
 					TTL:      &testTTL,
 				},
 			}})
 		Expect(err).ToNot(HaveOccurred())
 
-		originalConfig = customSnapshot{scopedRouteConfigOnly}
+		originalConfig = customSnapshot{*scopedRouteConfigOnly}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -340,7 +343,7 @@ var _ = Describe("config marshal and unmarshal", func() {
 				resource.EndpointType: {makeEndpoint(testEndpointName, endpoints[0].TestUpstreamHost, endpoints[0].TestUpstreamPort)},
 			})
 
-		originalConfig = customSnapshot{fullSet}
+		originalConfig = customSnapshot{*fullSet}
 		marshalConfig, err := json.Marshal(originalConfig)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -353,9 +356,106 @@ var _ = Describe("config marshal and unmarshal", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 })
+var _ = Describe("config unmarshal of malformed configurations", func() {
+	endpointResource := `{"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment", "clusterName": "cluster"}`
+	clusterResource := `{"@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster", "name": "cluster"}`
+
+	It("accepts an empty configuration", func() {
+		var config customSnapshot
+		Expect(json.Unmarshal([]byte(`{}`), &config)).To(Succeed())
+		for _, resources := range config.Resources {
+			Expect(resources.Items).To(BeEmpty())
+		}
+	})
+
+	It("accepts resources without a TTL", func() {
+		var config customSnapshot
+		data := fmt.Sprintf(`{"Resources": [{"Version": "1", "Items": {"cluster": {"Resource": %s}}}]}`, endpointResource)
+		Expect(json.Unmarshal([]byte(data), &config)).To(Succeed())
+		items := config.Resources[int(cache.GetResponseType(resource.EndpointType))].Items
+		Expect(items).To(HaveKey("cluster"))
+		Expect(items["cluster"].Resource).ToNot(BeNil())
+		Expect(items["cluster"].TTL).To(BeNil())
+	})
+
+	It("returns an error instead of panicking for malformed configurations", func() {
+		malformed := map[string]string{
+			"invalid JSON":                             `{"Resources": [`,
+			"null":                                     `null`,
+			"a list instead of an object":              `[]`,
+			"a VersionMap of the wrong type":           `{"VersionMap": []}`,
+			"Resources of the wrong type":              `{"Resources": {}}`,
+			"too many lists of resources":              `{"Resources": [` + strings.Repeat(`null, `, int(types.UnknownType)) + `null]}`,
+			"Items of the wrong type":                  `{"Resources": [{"Items": []}]}`,
+			"a null resource":                          `{"Resources": [{"Items": {"cluster": null}}]}`,
+			"a resource without a Resource":            `{"Resources": [{"Items": {"cluster": {"TTL": null}}}]}`,
+			"a resource without a type":                `{"Resources": [{"Items": {"cluster": {"Resource": {}}}}]}`,
+			"a resource of an unsupported type":        `{"Resources": [{"Items": {"cluster": {"Resource": {"@type": "type.googleapis.com/google.protobuf.Duration", "value": "1s"}}}}]}`,
+			"an invalid resource":                      `{"Resources": [{"Items": {"cluster": {"Resource": {"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"}}}}]}`,
+			"resources of different types in one list": fmt.Sprintf(`{"Resources": [{"Items": {"a": {"Resource": %s}, "b": {"Resource": %s}}}]}`, endpointResource, clusterResource),
+			"resources of one type in several lists":   fmt.Sprintf(`{"Resources": [{"Items": {"a": {"Resource": %s}}}, {"Items": {"b": {"Resource": %s}}}]}`, endpointResource, endpointResource),
+			"an invalid TTL":                           fmt.Sprintf(`{"Resources": [{"Items": {"cluster": {"Resource": %s, "TTL": "1h"}}}]}`, endpointResource),
+			"an invalid version":                       fmt.Sprintf(`{"Resources": [{"Version": 1, "Items": {"cluster": {"Resource": %s}}}]}`, endpointResource),
+		}
+		for name, data := range malformed {
+			var config customSnapshot
+			Expect(json.Unmarshal([]byte(data), &config)).ToNot(Succeed(), name)
+		}
+	})
+})
+
+var _ = Describe("GenerateSnapshotFromConfigFiles", func() {
+	var dir, userConfigPath string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "xds-config")
+		Expect(err).ToNot(HaveOccurred())
+		userConfigPath = filepath.Join(dir, "user_config.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("uses the default configuration when there is no user configuration", func() {
+		snap, err := GenerateSnapshotFromConfigFiles("default_config.json", userConfigPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snap.Consistent()).To(Succeed())
+	})
+
+	It("replaces the default resources of the types that users supply", func() {
+		data := `{"Resources": [{"Version": "2", "Items": {"defaultTestServiceCluster": {"Resource": {"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment", "clusterName": "defaultTestServiceCluster"}}}}]}`
+		Expect(os.WriteFile(userConfigPath, []byte(data), 0644)).To(Succeed())
+
+		snap, err := GenerateSnapshotFromConfigFiles("default_config.json", userConfigPath)
+		Expect(err).ToNot(HaveOccurred())
+		endpoints := snap.Resources[int(cache.GetResponseType(resource.EndpointType))]
+		Expect(endpoints.Version).To(Equal("2"))
+		Expect(endpoints.Items).To(HaveKey("defaultTestServiceCluster"))
+		Expect(endpoints.Items["defaultTestServiceCluster"].Resource.(*endpoint.ClusterLoadAssignment).GetEndpoints()).To(BeEmpty())
+		Expect(snap.Resources[int(cache.GetResponseType(resource.ClusterType))].Items).To(HaveKey("defaultTestServiceCluster"))
+	})
+
+	It("returns an error for a malformed user configuration", func() {
+		Expect(os.WriteFile(userConfigPath, []byte(`{"Resources": [{"Items": {"cluster": {}}}]}`), 0644)).To(Succeed())
+		_, err := GenerateSnapshotFromConfigFiles("default_config.json", userConfigPath)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when a cluster refers to a missing endpoint", func() {
+		data := `{"Resources": [{"Version": "2", "Items": {"otherCluster": {"Resource": {"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment", "clusterName": "otherCluster"}}}}]}`
+		Expect(os.WriteFile(userConfigPath, []byte(data), 0644)).To(Succeed())
+
+		snap, err := GenerateSnapshotFromConfigFiles("default_config.json", userConfigPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(UpdateEndpoint(&snap, nil)).ToNot(Succeed())
+	})
+})
+
 var _ = Describe("Update Endpoint", func() {
 
-	var snap cache.Snapshot
+	var snap *cache.Snapshot
 	var endpoints []TestEndpoint
 
 	currentVersion := "testVersion"
@@ -388,7 +488,7 @@ var _ = Describe("Update Endpoint", func() {
 			TestUpstreamPort: 2,
 		}}
 
-		err := UpdateEndpoint(&snap, endpoints)
+		err := UpdateEndpoint(snap, endpoints)
 
 		Expect(err).To(HaveOccurred())
 	})
@@ -398,7 +498,7 @@ var _ = Describe("Update Endpoint", func() {
 			TestUpstreamPort: uint32(1),
 		}}
 
-		err := UpdateEndpoint(&snap, endpoints)
+		err := UpdateEndpoint(snap, endpoints)
 
 		endpointResource := snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[testEndpointName].Resource
 		endpointData, err := protojson.Marshal(endpointResource)
@@ -414,7 +514,7 @@ var _ = Describe("Update Endpoint", func() {
 })
 
 var _ = Describe("SocketListenerOnly", func() {
-	var snap cache.Snapshot
+	var snap *cache.Snapshot
 
 	currentVersion := "testVersion"
 	testServiceClusterName := "defaultTestServiceClusterName"
@@ -438,7 +538,7 @@ var _ = Describe("SocketListenerOnly", func() {
 			})
 	})
 	It("leaves only the socket listeners", func() {
-		err := IncludeSocketListenerOnly(&snap)
+		err := IncludeSocketListenerOnly(snap)
 		Expect(err).ToNot(HaveOccurred())
 
 		_, grpcListenerExist := snap.Resources[int(cache.GetResponseType(resource.ListenerType))].Items[testGrpcListenerName]
@@ -451,7 +551,7 @@ var _ = Describe("SocketListenerOnly", func() {
 })
 
 var _ = Describe("ConstructProxylessTestTarget", func() {
-	var snap cache.Snapshot
+	var snap *cache.Snapshot
 
 	currentVersion := "testVersion"
 	testServiceClusterName := "defaultTestServiceClusterName"
@@ -475,7 +575,7 @@ var _ = Describe("ConstructProxylessTestTarget", func() {
 			})
 	})
 	It("finds the proxyless test server target ", func() {
-		target, err := ConstructProxylessTestTarget(&snap)
+		target, err := ConstructProxylessTestTarget(snap)
 
 		Expect(err).ToNot(HaveOccurred())
 
@@ -486,7 +586,7 @@ var _ = Describe("ConstructProxylessTestTarget", func() {
 })
 
 var _ = Describe("ConstructProxiedTestTarget", func() {
-	var snap cache.Snapshot
+	var snap *cache.Snapshot
 
 	currentVersion := "testVersion"
 	testServiceClusterName := "defaultTestServiceClusterName"
@@ -510,7 +610,7 @@ var _ = Describe("ConstructProxiedTestTarget", func() {
 			})
 	})
 	It("finds the proxied test server target ", func() {
-		target, err := ConstructProxiedTestTarget(&snap)
+		target, err := ConstructProxiedTestTarget(snap)
 		Expect(err).ToNot(HaveOccurred())
 
 		expected := "localhost:" + fmt.Sprint(testEnvoyListenerPort)
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+)
+
+// snapshotSeeds returns the seed corpus of the fuzz targets: the default
+// configuration, and malformed configurations that used to be ignored or
+// cause panics.
+func snapshotSeeds(f *testing.F) [][]byte {
+	defaultConfig, err := os.ReadFile("default_config.json")
+	if err != nil {
+		f.Fatalf("failed to read default configuration: %v", err)
+	}
+	return [][]byte{
+		defaultConfig,
+		[]byte(`{}`),
+		[]byte(`null`),
+		[]byte(`{"Resources": [null, {"Items": {}}]}`),
+		[]byte(`{"Resources": [{"Items": {"cluster": {}}}]}`),
+		[]byte(`{"Resources": [{"Items": {"cluster": {"Resource": {"@type": "type.googleapis.com/google.protobuf.Duration"}}}}]}`),
+		[]byte(`{"Resources": [{"Items": {"cluster": {"Resource": {"@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster", "name": "cluster", "edsClusterConfig": {"serviceName": "missing"}}}}}]}`),
+		[]byte(`{"Resources": [{"Items": {"listener": {"Resource": {"@type": "type.googleapis.com/envoy.config.listener.v3.Listener", "name": "listener"}}}}]}`),
+	}
+}
+
+// checkSnapshot exercises the functions that read a snapshot, which must
+// return errors rather than panic for any snapshot that was unmarshalled.
+func checkSnapshot(t *testing.T, snap cache.Snapshot) {
+	for _, resources := range snap.Resources {
+		for name, item := range resources.Items {
+			if item.Resource == nil {
+				t.Fatalf("resource %q was unmarshalled without a value", name)
+			}
+		}
+	}
+	UpdateEndpoint(&snap, []TestEndpoint{{TestUpstreamHost: "localhost", TestUpstreamPort: 10000}})
+	AddEndpoints(&snap, []TestEndpoint{{TestUpstreamHost: "localhost", TestUpstreamPort: 10001}})
+	ConstructProxylessTestTarget(&snap)
+	ConstructProxiedTestTarget(&snap)
+	IncludeSocketListenerOnly(&snap)
+}
+
+func FuzzCustomSnapshotUnmarshalJSON(f *testing.F) {
+	for _, seed := range snapshotSeeds(f) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var config customSnapshot
+		if err := json.Unmarshal(data, &config); err != nil {
+			return
+		}
+
+		// A snapshot that was unmarshalled must survive a round trip.
+		marshalled, err := json.Marshal(config)
+		if err != nil {
+			t.Fatalf("failed to marshal unmarshalled snapshot: %v", err)
+		}
+		var roundTrip customSnapshot
+		if err := json.Unmarshal(marshalled, &roundTrip); err != nil {
+			t.Fatalf("failed to unmarshal marshalled snapshot: %v", err)
+		}
+		checkSnapshot(t, config.Snapshot)
+	})
+}
+
+func FuzzGenerateSnapshotFromConfigFiles(f *testing.F) {
+	for _, seed := range snapshotSeeds(f) {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		userConfigPath := filepath.Join(t.TempDir(), "user_config.json")
+		if err := os.WriteFile(userConfigPath, data, 0644); err != nil {
+			t.Fatalf("failed to write user configuration: %v", err)
+		}
+		snap, err := GenerateSnapshotFromConfigFiles("default_config.json", userConfigPath)
+		if err != nil {
+			return
+		}
+		checkSnapshot(t, snap)
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// SyntheticEndpointMode selects the addresses of synthetic endpoints.
+type SyntheticEndpointMode string
+
+const (
+	// BackendSyntheticEndpoints point each synthetic endpoint at one of the
+	// actual backends, in turn. Every synthetic endpoint can serve traffic.
+	BackendSyntheticEndpoints SyntheticEndpointMode = "backend"
+
+	// BlackholeSyntheticEndpoints point synthetic endpoints at distinct
+	// addresses in 198.18.0.0/15, the range reserved for benchmarking
+	// network devices. Connections to these addresses are not expected to
+	// succeed, so they only exercise the handling of endpoints by clients.
+	BlackholeSyntheticEndpoints SyntheticEndpointMode = "blackhole"
+)
+
+// blackholeNetwork is the range of addresses used by blackhole endpoints.
+var blackholeNetwork = net.IPNet{
+	IP:   net.IPv4(198, 18, 0, 0).To4(),
+	Mask: net.CIDRMask(15, 32),
+}
+
+// GenerateSyntheticEndpoints fabricates a number of endpoints, so that the
+// handling of EDS updates and the scaling of LB policies by clients can be
+// benchmarked with many endpoints, without provisioning a pod for each of
+// them. The actual backends are used to choose the addresses of endpoints in
+// BackendSyntheticEndpoints mode, and to choose their ports in
+// BlackholeSyntheticEndpoints mode.
+func GenerateSyntheticEndpoints(backends []TestEndpoint, count int, mode SyntheticEndpointMode) ([]TestEndpoint, error) {
+	if count < 0 {
+		return nil, errors.Errorf("number of synthetic endpoints must not be negative: %d", count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("at least one backend is required to generate synthetic endpoints")
+	}
+
+	endpoints := make([]TestEndpoint, 0, count)
+	switch mode {
+	case BackendSyntheticEndpoints:
+		for i := 0; i < count; i++ {
+			endpoints = append(endpoints, backends[i%len(backends)])
+		}
+	case BlackholeSyntheticEndpoints:
+		ones, bits := blackholeNetwork.Mask.Size()
+		if size := 1 << (bits - ones); count > size {
+			return nil, errors.Errorf("number of blackhole endpoints must not exceed %d: %d", size, count)
+		}
+		base := binary.BigEndian.Uint32(blackholeNetwork.IP)
+		for i := 0; i < count; i++ {
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, base+uint32(i))
+			endpoints = append(endpoints, TestEndpoint{
+				TestUpstreamHost: ip.String(),
+				TestUpstreamPort: backends[i%len(backends)].TestUpstreamPort,
+			})
+		}
+	default:
+		return nil, errors.Errorf("unknown synthetic endpoint mode %q", mode)
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var _ = Describe("GenerateSyntheticEndpoints", func() {
+	backends := []TestEndpoint{{
+		TestUpstreamHost: "10.0.0.1",
+		TestUpstreamPort: 10010,
+	}, {
+		TestUpstreamHost: "10.0.0.2",
+		TestUpstreamPort: 10011,
+	}}
+
+	It("returns no endpoints when the count is zero", func() {
+		endpoints, err := GenerateSyntheticEndpoints(backends, 0, BackendSyntheticEndpoints)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoints).To(BeEmpty())
+	})
+
+	It("points endpoints at the backends in turn in backend mode", func() {
+		endpoints, err := GenerateSyntheticEndpoints(backends, 3, BackendSyntheticEndpoints)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoints).To(Equal([]TestEndpoint{backends[0], backends[1], backends[0]}))
+	})
+
+	It("points endpoints at distinct blackhole addresses in blackhole mode", func() {
+		endpoints, err := GenerateSyntheticEndpoints(backends, 300, BlackholeSyntheticEndpoints)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoints).To(HaveLen(300))
+		Expect(endpoints[0]).To(Equal(TestEndpoint{TestUpstreamHost: "198.18.0.0", TestUpstreamPort: 10010}))
+		Expect(endpoints[1]).To(Equal(TestEndpoint{TestUpstreamHost: "198.18.0.1", TestUpstreamPort: 10011}))
+		Expect(endpoints[299]).To(Equal(TestEndpoint{TestUpstreamHost: "198.18.1.43", TestUpstreamPort: 10011}))
+	})
+
+	It("returns an error when there are more blackhole endpoints than addresses", func() {
+		_, err := GenerateSyntheticEndpoints(backends, 1<<17+1, BlackholeSyntheticEndpoints)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when there are no backends", func() {
+		_, err := GenerateSyntheticEndpoints(nil, 10, BackendSyntheticEndpoints)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the count is negative", func() {
+		_, err := GenerateSyntheticEndpoints(backends, -1, BackendSyntheticEndpoints)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the mode is unknown", func() {
+		_, err := GenerateSyntheticEndpoints(backends, 10, SyntheticEndpointMode("unknown"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("AddEndpoints", func() {
+	var snap *cache.Snapshot
+
+	testServiceClusterName := "defaultTestServiceClusterName"
+	testEndpointName := "defaultTestEndpointName"
+
+	BeforeEach(func() {
+		snap, _ = cache.NewSnapshot("testVersion",
+			map[resource.Type][]types.Resource{
+				resource.ClusterType:  {makeCluster(testServiceClusterName, testEndpointName)},
+				resource.EndpointType: {makeEndpoint(testEndpointName, "test-host-1", 1)},
+			})
+	})
+
+	It("appends the endpoints to the actual backends", func() {
+		err := AddEndpoints(snap, []TestEndpoint{{
+			TestUpstreamHost: "198.18.0.0",
+			TestUpstreamPort: 1,
+		}, {
+			TestUpstreamHost: "198.18.0.1",
+			TestUpstreamPort: 1,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		endpointResource := snap.Resources[int(cache.GetResponseType(resource.EndpointType))].Items[testEndpointName].Resource
+		endpointData, err := protojson.Marshal(endpointResource)
+		Expect(err).ToNot(HaveOccurred())
+		endpointService := endpoint.ClusterLoadAssignment{}
+		Expect(protojson.Unmarshal(endpointData, &endpointService)).To(Succeed())
+
+		lbEndpoints := endpointService.Endpoints[0].LbEndpoints
+		Expect(lbEndpoints).To(HaveLen(3))
+		Expect(lbEndpoints[0].GetEndpoint().Address.GetSocketAddress().Address).To(Equal("test-host-1"))
+		Expect(lbEndpoints[2].GetEndpoint().Address.GetSocketAddress().Address).To(Equal("198.18.0.1"))
+	})
+
+	It("returns an error when there is no cluster", func() {
+		snap, _ = cache.NewSnapshot("testVersion", map[resource.Type][]types.Resource{})
+
+		err := AddEndpoints(snap, []TestEndpoint{{TestUpstreamHost: "198.18.0.0", TestUpstreamPort: 1}})
+		Expect(err).To(HaveOccurred())
+	})
+})
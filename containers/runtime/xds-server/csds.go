@@ -0,0 +1,89 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	statusv3 "github.com/envoyproxy/go-control-plane/envoy/service/status/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// CSDSDumpBegin marks the start of the CSDS dump in the log of the
+	// xds-server container.
+	CSDSDumpBegin = "==== BEGIN CSDS DUMP ===="
+
+	// CSDSDumpEnd marks the end of the CSDS dump in the log of the
+	// xds-server container.
+	CSDSDumpEnd = "==== END CSDS DUMP ===="
+)
+
+// WatchClientStatus fetches the xDS configuration of a proxyless client from
+// its Client Status Discovery Service at the given target, once per interval,
+// and keeps the latest response. Fetches fail until the client starts serving
+// CSDS, so the client is considered stopped once a fetch fails after a
+// successful one. The latest response is then written to out as JSON, between
+// CSDSDumpBegin and CSDSDumpEnd, so it can be found in the log of the
+// container. The latest response is also written when the context is
+// cancelled. Nothing is written if no fetch succeeded.
+func WatchClientStatus(ctx context.Context, target string, interval time.Duration, out io.Writer, l Logger) {
+	conn, err := grpc.DialContext(ctx, target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		l.Warnf("fail to connect to CSDS of client at %v: %v", target, err)
+		return
+	}
+	defer conn.Close()
+	client := statusv3.NewClientStatusDiscoveryServiceClient(conn)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var latest *statusv3.ClientStatusResponse
+watch:
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, interval)
+		response, err := client.FetchClientStatus(fetchCtx, &statusv3.ClientStatusRequest{})
+		cancel()
+		switch {
+		case err == nil:
+			latest = response
+		case latest != nil && ctx.Err() == nil:
+			l.Infof("client stopped serving CSDS: %v", err)
+			break watch
+		}
+
+		select {
+		case <-ctx.Done():
+			break watch
+		case <-ticker.C:
+		}
+	}
+
+	if latest == nil {
+		l.Warnf("no CSDS dump was fetched from client at %v", target)
+		return
+	}
+	dump, err := protojson.MarshalOptions{Multiline: true}.Marshal(latest)
+	if err != nil {
+		l.Warnf("fail to encode CSDS dump: %v", err)
+		return
+	}
+	fmt.Fprintf(out, "%s\n%s\n%s\n", CSDSDumpBegin, dump, CSDSDumpEnd)
+}
@@ -14,29 +14,42 @@ limitations under the License.
 package xds
 
 import (
-	"log"
+	"go.uber.org/zap"
 )
 
-// Logger implements the Logger interface required.
+// Logger implements the Logger interface required by the snapshot cache. It
+// writes to the shared zap logger, so its messages have the same format as
+// those from the other components.
 type Logger struct {
+	// Sugar is the logger where messages are written. The global zap logger
+	// is used when it is nil.
+	Sugar *zap.SugaredLogger
+}
+
+// sugar returns the logger where messages are written.
+func (logger Logger) sugar() *zap.SugaredLogger {
+	if logger.Sugar != nil {
+		return logger.Sugar
+	}
+	return zap.S()
 }
 
 // Debugf prints out debug information.
 func (logger Logger) Debugf(format string, args ...interface{}) {
-	log.Printf(format+"\n", args...)
+	logger.sugar().Debugf(format, args...)
 }
 
 // Infof prints out useful information.
 func (logger Logger) Infof(format string, args ...interface{}) {
-	log.Printf(format+"\n", args...)
+	logger.sugar().Infof(format, args...)
 }
 
 // Warnf prints out warnings.
 func (logger Logger) Warnf(format string, args ...interface{}) {
-	log.Printf(format+"\n", args...)
+	logger.sugar().Warnf(format, args...)
 }
 
 // Errorf prints out the error message and stops the process.
 func (logger Logger) Errorf(format string, args ...interface{}) {
-	log.Fatalf(format+"\n", args...)
+	logger.sugar().Fatalf(format, args...)
 }
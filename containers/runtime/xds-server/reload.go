@@ -0,0 +1,193 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/fsnotify/fsnotify"
+
+	config "github.com/grpc/test-infra/containers/runtime/xds-server/config"
+)
+
+// reloadDebounce is the time to wait for further changes after a
+// configuration file changes, since editors and Kubernetes replace files
+// with several operations.
+const reloadDebounce = 500 * time.Millisecond
+
+// Reloader regenerates the snapshot served by the xDS server from its
+// configuration files, so that changes to the resources are served without
+// restarting the server. Reloads are triggered by a signal, such as SIGHUP,
+// or by changes to the files.
+type Reloader struct {
+	// DefaultConfigPath is the path of the default configuration file.
+	DefaultConfigPath string
+
+	// CustomConfigPath is the path of the user supplied configuration file.
+	CustomConfigPath string
+
+	// Prepare applies the information of the running test, such as the
+	// endpoints of its backends, to each regenerated snapshot. It may be
+	// nil, in which case the snapshot is served as generated.
+	Prepare func(snapshot *cache.Snapshot) error
+
+	// Cache is the cache of the xDS server, where snapshots are set.
+	Cache cache.SnapshotCache
+
+	// NodeIDs lists the node IDs that snapshots are served to.
+	NodeIDs []string
+
+	// Logger is where reloads and their failures are logged.
+	Logger Logger
+
+	reloads int
+}
+
+// Reload regenerates the snapshot from the configuration files and sets it
+// in the cache for each node ID. The version of every type of resource is
+// changed, so that clients receive the new resources even when the versions
+// in the files did not change. The served snapshot is left unchanged when
+// the new one cannot be generated or is inconsistent.
+func (r *Reloader) Reload(ctx context.Context) error {
+	snapshot, err := config.GenerateSnapshotFromConfigFiles(r.DefaultConfigPath, r.CustomConfigPath)
+	if err != nil {
+		return err
+	}
+	if r.Prepare != nil {
+		if err := r.Prepare(&snapshot); err != nil {
+			return err
+		}
+	}
+	if err := snapshot.Consistent(); err != nil {
+		return fmt.Errorf("reloaded snapshot is inconsistent: %v", err)
+	}
+
+	r.reloads++
+	for i := range snapshot.Resources {
+		snapshot.Resources[i].Version = fmt.Sprintf("%s-reload-%d", snapshot.Resources[i].Version, r.reloads)
+	}
+	for _, id := range r.NodeIDs {
+		if err := r.Cache.SetSnapshot(ctx, id, &snapshot); err != nil {
+			return fmt.Errorf("failed to set reloaded snapshot for node %v: %v", id, err)
+		}
+	}
+	r.Logger.Infof("reloaded configuration %d from %v and %v, serving to node IDs %v", r.reloads, r.DefaultConfigPath, r.CustomConfigPath, r.NodeIDs)
+	return nil
+}
+
+// Watch reloads the snapshot each time a value is received on the signals
+// channel and, when watchFiles is true, each time a configuration file
+// changes, until the context is done. The directories of the files are
+// watched rather than the files, so that files replaced by a rename, as
+// editors and Kubernetes ConfigMap volumes do, are still watched. Failed
+// reloads are logged, and the previous snapshot is kept.
+func (r *Reloader) Watch(ctx context.Context, signals <-chan os.Signal, watchFiles bool) error {
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if watchFiles {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to watch configuration files: %v", err)
+		}
+		defer watcher.Close()
+		for _, dir := range r.configDirs() {
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch configuration directory %v: %v", dir, err)
+			}
+			r.Logger.Infof("watching %v for changes to the configuration", dir)
+		}
+		events = watcher.Events
+		errs = watcher.Errors
+	}
+
+	var debounce <-chan time.Time
+	reload := func(reason string) {
+		r.Logger.Infof("reloading configuration after %v", reason)
+		if err := r.Reload(ctx); err != nil {
+			r.Logger.Warnf("failed to reload configuration, still serving the previous snapshot: %v", err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig, ok := <-signals:
+			if !ok {
+				signals = nil
+				continue
+			}
+			reload(fmt.Sprintf("signal %v", sig))
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if r.isConfigEvent(event) {
+				debounce = time.After(reloadDebounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			r.Logger.Warnf("error watching configuration files: %v", err)
+		case <-debounce:
+			debounce = nil
+			reload("a change to the configuration files")
+		}
+	}
+}
+
+// configDirs returns the directories of the configuration files that exist.
+func (r *Reloader) configDirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, path := range []string{r.DefaultConfigPath, r.CustomConfigPath} {
+		dir := filepath.Dir(path)
+		if path == "" || seen[dir] {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// isConfigEvent returns true for events that may change the content of a
+// configuration file. Kubernetes updates ConfigMap volumes by replacing the
+// ..data symlink, so events for it are included.
+func (r *Reloader) isConfigEvent(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Clean(event.Name)
+	for _, path := range []string{r.DefaultConfigPath, r.CustomConfigPath} {
+		if path == "" {
+			continue
+		}
+		if name == filepath.Clean(path) || filepath.Base(name) == filepath.Base(path) {
+			return true
+		}
+		if filepath.Dir(name) == filepath.Dir(filepath.Clean(path)) && filepath.Base(name) == "..data" {
+			return true
+		}
+	}
+	return false
+}
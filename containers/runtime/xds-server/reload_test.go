@@ -0,0 +1,148 @@
+/*
+Copyright 2022 gRPC authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reloader", func() {
+	var dir string
+	var configPath string
+	var snapshotCache cache.SnapshotCache
+	var reloader *Reloader
+
+	listenerVersion := func(nodeID string) string {
+		snapshot, err := snapshotCache.GetSnapshot(nodeID)
+		if err != nil {
+			return ""
+		}
+		return snapshot.GetVersion(resource.ListenerType)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "xds-reload")
+		Expect(err).ToNot(HaveOccurred())
+		content, err := os.ReadFile("config/default_config.json")
+		Expect(err).ToNot(HaveOccurred())
+		configPath = filepath.Join(dir, "default_config.json")
+		Expect(os.WriteFile(configPath, content, 0644)).To(Succeed())
+
+		snapshotCache = cache.NewSnapshotCache(false, cache.IDHash{}, Logger{})
+		reloader = &Reloader{
+			DefaultConfigPath: configPath,
+			Cache:             snapshotCache,
+			NodeIDs:           []string{"node-a", "node-b"},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("Reload", func() {
+		It("sets a new version of the snapshot for each node ID", func() {
+			Expect(reloader.Reload(context.Background())).To(Succeed())
+			first := listenerVersion("node-a")
+			Expect(first).To(HaveSuffix("-reload-1"))
+			Expect(listenerVersion("node-b")).To(Equal(first))
+
+			Expect(reloader.Reload(context.Background())).To(Succeed())
+			Expect(listenerVersion("node-a")).To(HaveSuffix("-reload-2"))
+			Expect(listenerVersion("node-b")).To(HaveSuffix("-reload-2"))
+		})
+
+		It("applies the test information to the snapshot", func() {
+			prepared := 0
+			reloader.Prepare = func(snapshot *cache.Snapshot) error {
+				prepared++
+				return nil
+			}
+			Expect(reloader.Reload(context.Background())).To(Succeed())
+			Expect(prepared).To(Equal(1))
+		})
+
+		It("keeps the previous snapshot when the configuration is malformed", func() {
+			Expect(reloader.Reload(context.Background())).To(Succeed())
+			Expect(os.WriteFile(configPath, []byte(`{"resources": [`), 0644)).To(Succeed())
+			Expect(reloader.Reload(context.Background())).ToNot(Succeed())
+			Expect(listenerVersion("node-a")).To(HaveSuffix("-reload-1"))
+		})
+
+		It("keeps the previous snapshot when the test information cannot be applied", func() {
+			Expect(reloader.Reload(context.Background())).To(Succeed())
+			reloader.Prepare = func(snapshot *cache.Snapshot) error {
+				snapshot.Resources[types.Listener].Items = nil
+				return os.ErrInvalid
+			}
+			Expect(reloader.Reload(context.Background())).ToNot(Succeed())
+			Expect(listenerVersion("node-a")).To(HaveSuffix("-reload-1"))
+		})
+	})
+
+	Describe("Watch", func() {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		var done chan error
+
+		watch := func(signals <-chan os.Signal, watchFiles bool) {
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan error, 1)
+			go func() {
+				done <- reloader.Watch(ctx, signals, watchFiles)
+			}()
+		}
+
+		AfterEach(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		It("reloads the configuration on a signal", func() {
+			signals := make(chan os.Signal, 1)
+			watch(signals, false)
+			signals <- syscall.SIGHUP
+			Eventually(func() string { return listenerVersion("node-a") }).Should(HaveSuffix("-reload-1"))
+		})
+
+		It("reloads the configuration when the file changes", func() {
+			watch(nil, true)
+			// Give the watcher time to start before changing the file.
+			time.Sleep(100 * time.Millisecond)
+			content, err := os.ReadFile(configPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(configPath, []byte(strings.TrimSpace(string(content))+"\n"), 0644)).To(Succeed())
+			Eventually(func() string { return listenerVersion("node-a") }, 5*time.Second).Should(HaveSuffix("-reload-1"))
+		})
+
+		It("ignores changes to other files", func() {
+			watch(nil, true)
+			time.Sleep(100 * time.Millisecond)
+			Expect(os.WriteFile(filepath.Join(dir, "other.json"), []byte("{}"), 0644)).To(Succeed())
+			Consistently(func() string { return listenerVersion("node-a") }, 2*reloadDebounce).Should(BeEmpty())
+		})
+	})
+})
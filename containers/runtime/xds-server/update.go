@@ -18,19 +18,34 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"sync"
 
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	config "github.com/grpc/test-infra/containers/runtime/xds-server/config"
 	pb "github.com/grpc/test-infra/proto/endpointupdater"
 	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // UpdateServer is used to implement testupdater.TestUpdater.
+//
+// Updates are applied in two steps, so that test updaters can retry each step
+// when the connection is interrupted. UpdateTest records an update, replacing
+// any update that was recorded before, and Commit sends the last recorded
+// update on TestInfoChannel. Once an update is committed, the same update can
+// still be sent and committed again, and the reply sent when it was committed
+// is returned.
 type UpdateServer struct {
 	pb.UnimplementedTestUpdaterServer
 	TestInfoChannel chan TestInfo
 	Srv             *grpc.Server
 	Snapshot        *cache.Snapshot
+
+	mu        sync.Mutex
+	pending   *pb.TestUpdateRequest
+	committed *pb.TestUpdateReply
 }
 
 // TestInfo contains the information such as backend's pod address,
@@ -40,18 +55,85 @@ type TestInfo struct {
 	IsProxied bool
 }
 
+// NewUpdateServer creates an UpdateServer with a gRPC server that it is
+// registered with. Options such as credentials are passed to the gRPC server.
+func NewUpdateServer(testUpdateChannel chan TestInfo, snapshot *cache.Snapshot, opts ...grpc.ServerOption) *UpdateServer {
+	us := &UpdateServer{
+		TestInfoChannel: testUpdateChannel,
+		Srv:             grpc.NewServer(opts...),
+		Snapshot:        snapshot,
+	}
+	pb.RegisterTestUpdaterServer(us.Srv, us)
+	return us
+}
+
 // UpdateTest implements testupdater.UpdateTest
 func (us *UpdateServer) UpdateTest(ctx context.Context, in *pb.TestUpdateRequest) (*pb.TestUpdateReply, error) {
-	var testEndpoints []config.TestEndpoint
+	us.mu.Lock()
+	defer us.mu.Unlock()
 
-	log.Printf("Running proxied test: %v", in.IsProxied)
+	if us.committed != nil {
+		if !proto.Equal(in, us.pending) {
+			return nil, status.Errorf(codes.FailedPrecondition, "a different update has already been committed")
+		}
+		return us.committed, nil
+	}
 
+	log.Printf("Running proxied test: %v", in.IsProxied)
 	for _, c := range in.GetEndpoints() {
-		testEndpoints = append(testEndpoints, config.TestEndpoint{TestUpstreamHost: c.IpAddress, TestUpstreamPort: c.Port})
 		log.Printf("Received endpoint: %v:%v", c.IpAddress, c.Port)
 	}
-	us.TestInfoChannel <- TestInfo{Endpoints: testEndpoints, IsProxied: in.IsProxied}
 
+	response, err := us.reply(in)
+	if err != nil {
+		return nil, err
+	}
+	us.pending = proto.Clone(in).(*pb.TestUpdateRequest)
+	return response, nil
+}
+
+// Commit implements testupdater.Commit
+func (us *UpdateServer) Commit(ctx context.Context, in *pb.Void) (*pb.TestUpdateReply, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	return us.commit(ctx)
+}
+
+// commit sends the pending update on the channel, unless it has already been
+// committed. The caller must hold the lock.
+func (us *UpdateServer) commit(ctx context.Context) (*pb.TestUpdateReply, error) {
+	if us.committed != nil {
+		return us.committed, nil
+	}
+	if us.pending == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no update to commit")
+	}
+
+	// The reply is constructed before the update is sent, since the
+	// snapshot is modified once the update is received.
+	response, err := us.reply(us.pending)
+	if err != nil {
+		return nil, err
+	}
+
+	var testEndpoints []config.TestEndpoint
+	for _, c := range us.pending.GetEndpoints() {
+		testEndpoints = append(testEndpoints, config.TestEndpoint{TestUpstreamHost: c.IpAddress, TestUpstreamPort: c.Port})
+	}
+	select {
+	case us.TestInfoChannel <- TestInfo{Endpoints: testEndpoints, IsProxied: us.pending.IsProxied}:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	log.Printf("Committed update with %d endpoint(s)", len(testEndpoints))
+	us.committed = response
+	return response, nil
+}
+
+// reply constructs the reply to an update, which contains the target string
+// for the type of the test.
+func (us *UpdateServer) reply(in *pb.TestUpdateRequest) (*pb.TestUpdateReply, error) {
 	response := &pb.TestUpdateReply{}
 	if in.IsProxied {
 		target, err := config.ConstructProxiedTestTarget(us.Snapshot)
@@ -66,29 +148,37 @@ func (us *UpdateServer) UpdateTest(ctx context.Context, in *pb.TestUpdateRequest
 		}
 		response.PsmServerTargetOverride = target
 	}
-
 	return response, nil
 }
 
-// QuitTestUpdateServer stop the UpdateServer.
-func (us *UpdateServer) QuitTestUpdateServer(context.Context, *pb.Void) (*pb.Void, error) {
+// QuitTestUpdateServer stop the UpdateServer. An update that was sent but not
+// committed is committed first, for test updaters that do not call Commit.
+func (us *UpdateServer) QuitTestUpdateServer(ctx context.Context, in *pb.Void) (*pb.Void, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if us.pending != nil {
+		if _, err := us.commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	log.Printf("Shutting down the test update server")
 	go us.Srv.GracefulStop()
 
 	return &pb.Void{}, nil
 }
 
-// RunUpdateServer start a gRPC server listening to test server address and port
-func RunUpdateServer(testUpdateChannel chan TestInfo, updatePort uint, snapshot *cache.Snapshot) {
+// RunUpdateServer start a gRPC server listening to test server address and
+// port. Options such as credentials are passed to the gRPC server.
+func RunUpdateServer(testUpdateChannel chan TestInfo, updatePort uint, snapshot *cache.Snapshot, opts ...grpc.ServerOption) {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", updatePort))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	srv := grpc.NewServer()
+	us := NewUpdateServer(testUpdateChannel, snapshot, opts...)
 
-	pb.RegisterTestUpdaterServer(srv, &UpdateServer{TestInfoChannel: testUpdateChannel, Srv: srv, Snapshot: snapshot})
 	log.Printf("Endpoint update server listening at %v", lis.Addr())
-	if err := srv.Serve(lis); err != nil {
+	if err := us.Srv.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 
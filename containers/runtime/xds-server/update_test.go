@@ -0,0 +1,199 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xds
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	config "github.com/grpc/test-infra/containers/runtime/xds-server/config"
+	pb "github.com/grpc/test-infra/proto/endpointupdater"
+)
+
+var _ = Describe("UpdateServer", func() {
+	var testChannel chan TestInfo
+	var us *UpdateServer
+	var conns []*grpc.ClientConn
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	start := func(opts ...grpc.ServerOption) string {
+		snapshot, err := config.GenerateSnapshotFromConfigFiles("config/default_config.json", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		us = NewUpdateServer(testChannel, &snapshot, opts...)
+		go us.Srv.Serve(lis)
+		return lis.Addr().String()
+	}
+
+	dial := func(address string, creds credentials.TransportCredentials) pb.TestUpdaterClient {
+		conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+		Expect(err).ToNot(HaveOccurred())
+		conns = append(conns, conn)
+		return pb.NewTestUpdaterClient(conn)
+	}
+
+	request := func(ip string, isProxied bool) *pb.TestUpdateRequest {
+		return &pb.TestUpdateRequest{
+			Endpoints: []*pb.Endpoint{{IpAddress: ip, Port: 10010}},
+			IsProxied: isProxied,
+		}
+	}
+
+	BeforeEach(func() {
+		testChannel = make(chan TestInfo, 1)
+		conns = nil
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	})
+
+	AfterEach(func() {
+		cancel()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		us.Srv.Stop()
+	})
+
+	It("applies only the last update when it is committed", func() {
+		c := dial(start(), insecure.NewCredentials())
+
+		_, err := c.UpdateTest(ctx, request("10.0.0.1", false))
+		Expect(err).ToNot(HaveOccurred())
+		reply, err := c.UpdateTest(ctx, request("10.0.0.2", false))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.PsmServerTargetOverride).ToNot(BeEmpty())
+		Consistently(testChannel, 100*time.Millisecond).ShouldNot(Receive())
+
+		commitReply, err := c.Commit(ctx, &pb.Void{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(commitReply.PsmServerTargetOverride).To(Equal(reply.PsmServerTargetOverride))
+
+		var testInfo TestInfo
+		Eventually(testChannel).Should(Receive(&testInfo))
+		Expect(testInfo.IsProxied).To(BeFalse())
+		Expect(testInfo.Endpoints).To(Equal([]config.TestEndpoint{{TestUpstreamHost: "10.0.0.2", TestUpstreamPort: 10010}}))
+	})
+
+	It("applies an update once when it is sent and committed again", func() {
+		c := dial(start(), insecure.NewCredentials())
+
+		_, err := c.UpdateTest(ctx, request("10.0.0.1", true))
+		Expect(err).ToNot(HaveOccurred())
+		reply, err := c.Commit(ctx, &pb.Void{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testChannel).Should(Receive())
+
+		retriedUpdateReply, err := c.UpdateTest(ctx, request("10.0.0.1", true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retriedUpdateReply.PsmServerTargetOverride).To(Equal(reply.PsmServerTargetOverride))
+		retriedCommitReply, err := c.Commit(ctx, &pb.Void{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retriedCommitReply.PsmServerTargetOverride).To(Equal(reply.PsmServerTargetOverride))
+		Consistently(testChannel, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("rejects a different update once an update is committed", func() {
+		c := dial(start(), insecure.NewCredentials())
+
+		_, err := c.UpdateTest(ctx, request("10.0.0.1", false))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = c.Commit(ctx, &pb.Void{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = c.UpdateTest(ctx, request("10.0.0.2", false))
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+	})
+
+	It("rejects a commit without an update", func() {
+		c := dial(start(), insecure.NewCredentials())
+
+		_, err := c.Commit(ctx, &pb.Void{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Consistently(testChannel, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("commits the last update when it is stopped", func() {
+		c := dial(start(), insecure.NewCredentials())
+
+		_, err := c.UpdateTest(ctx, request("10.0.0.1", false))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = c.QuitTestUpdateServer(ctx, &pb.Void{})
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testChannel).Should(Receive())
+	})
+
+	Describe("with mutual TLS", func() {
+		var dir string
+		var certs *testCertificates
+		var address string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "update-server")
+			Expect(err).ToNot(HaveOccurred())
+			certs = writeTestCertificates(dir)
+
+			creds, err := pb.ServerCredentials(certs.ServerCert, certs.ServerKey, certs.CA)
+			Expect(err).ToNot(HaveOccurred())
+			address = start(grpc.Creds(creds))
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("accepts updates from clients with a certificate", func() {
+			creds, err := pb.ClientCredentials(certs.ClientCert, certs.ClientKey, certs.CA, "localhost")
+			Expect(err).ToNot(HaveOccurred())
+			c := dial(address, creds)
+
+			_, err = c.UpdateTest(ctx, request("10.0.0.1", false))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = c.Commit(ctx, &pb.Void{})
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(testChannel).Should(Receive())
+		})
+
+		It("rejects clients without a certificate", func() {
+			creds, err := pb.ClientCredentials("", "", certs.CA, "localhost")
+			Expect(err).ToNot(HaveOccurred())
+			c := dial(address, creds)
+
+			_, err = c.UpdateTest(ctx, request("10.0.0.1", false))
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		})
+
+		It("rejects insecure clients", func() {
+			c := dial(address, insecure.NewCredentials())
+
+			_, err := c.UpdateTest(ctx, request("10.0.0.1", false))
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		})
+	})
+})
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/status"
+)
+
+// DrainPath is the path of the endpoint that drains the manager and reports
+// the progress of the drain on its metrics server.
+const DrainPath = "/drain"
+
+// drainRequeueInterval is the interval at which tests that are held back by a
+// drain are reconciled again, so they start soon after the drain is
+// cancelled.
+const drainRequeueInterval = 30 * time.Second
+
+// DrainProgress reports the progress of a drain.
+type DrainProgress struct {
+	// Draining is true while the manager does not start new tests.
+	Draining bool `json:"draining"`
+
+	// ActiveTests is the number of tests that have not terminated and have
+	// pods, which the manager keeps reconciling until they terminate.
+	ActiveTests int `json:"activeTests"`
+
+	// QueuedTests is the number of tests that have not terminated and have
+	// no pods yet. Their pods are not created while the manager drains.
+	QueuedTests int `json:"queuedTests"`
+
+	// Drained is true when the manager drains and no test is active, so
+	// the manager can be replaced without interrupting any test.
+	Drained bool `json:"drained"`
+}
+
+// Drainer holds back new tests while the manager drains, and reports the
+// progress of the drain. Tests that already have pods keep being reconciled
+// until they terminate, while the pods of other tests are not created. The
+// tests that are held back are started by the next manager that does not
+// drain, which allows the controller to be upgraded on a busy cluster without
+// interrupting tests.
+//
+// The drainer serves the DrainPath endpoint. A GET request returns the
+// progress as JSON, a POST request starts the drain and a DELETE request
+// cancels it.
+type Drainer struct {
+	// Reader reads the load tests and pods, usually from the cache of the
+	// manager.
+	Reader client.Reader
+
+	draining atomic.Bool
+	gauge    prometheus.Gauge
+}
+
+// NewDrainer creates a drainer that reads tests and pods with the given
+// reader, and registers its metrics with the given registerer, usually the
+// metrics registry of controller-runtime.
+func NewDrainer(reader client.Reader, registerer prometheus.Registerer) (*Drainer, error) {
+	d := &Drainer{
+		Reader: reader,
+		gauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "loadtest_controller_draining",
+			Help: "Whether this manager drains and does not start new tests (1) or not (0).",
+		}),
+	}
+	if err := registerer.Register(d.gauge); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Drain stops the manager from starting new tests.
+func (d *Drainer) Drain() {
+	d.draining.Store(true)
+	d.gauge.Set(1)
+}
+
+// Resume allows the manager to start new tests again.
+func (d *Drainer) Resume() {
+	d.draining.Store(false)
+	d.gauge.Set(0)
+}
+
+// IsDraining returns true while the manager drains. It returns false if the
+// drainer is nil.
+func (d *Drainer) IsDraining() bool {
+	return d != nil && d.draining.Load()
+}
+
+// Progress returns the progress of the drain.
+func (d *Drainer) Progress(ctx context.Context) (*DrainProgress, error) {
+	tests := new(grpcv1.LoadTestList)
+	if err := d.Reader.List(ctx, tests); err != nil {
+		return nil, err
+	}
+	pods := new(corev1.PodList)
+	if err := d.Reader.List(ctx, pods, client.HasLabels{config.LoadTestLabel}); err != nil {
+		return nil, err
+	}
+
+	summary := status.SummarizeCluster(tests.Items, pods.Items, nil, time.Now())
+	progress := &DrainProgress{
+		Draining:    d.IsDraining(),
+		QueuedTests: summary.PendingTests,
+	}
+	for state, count := range summary.States {
+		if !state.IsTerminated() {
+			progress.ActiveTests += count
+		}
+	}
+	progress.ActiveTests -= summary.PendingTests
+	progress.Drained = progress.Draining && progress.ActiveTests == 0
+	return progress, nil
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (d *Drainer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !d.IsDraining() {
+			logger.Info("draining, new tests will not be started")
+		}
+		d.Drain()
+	case http.MethodDelete:
+		if d.IsDraining() {
+			logger.Info("drain cancelled, new tests will be started")
+		}
+		d.Resume()
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	progress, err := d.Progress(r.Context())
+	if err != nil {
+		logger.Error(err, "failed to compute drain progress")
+		http.Error(w, "failed to compute drain progress", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(progress); err != nil {
+		logger.Error(err, "failed to write drain progress")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("Drainer", func() {
+	var drainer *Drainer
+
+	BeforeEach(func() {
+		running := newLoadTest()
+		running.UID = "running-test"
+		running.Status.State = grpcv1.Running
+		pod := newIndexedPod(running, "server-0", "workers")
+		pod.Labels[config.LoadTestLabel] = running.Name
+
+		queued := newLoadTest()
+		queued.UID = "queued-test"
+
+		succeeded := newLoadTest()
+		succeeded.UID = "succeeded-test"
+		succeeded.Status.State = grpcv1.Succeeded
+
+		reader := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(running, pod, queued, succeeded).
+			Build()
+
+		var err error
+		drainer, err = NewDrainer(reader, prometheus.NewRegistry())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	serve := func(method string) *DrainProgress {
+		recorder := httptest.NewRecorder()
+		drainer.ServeHTTP(recorder, httptest.NewRequest(method, DrainPath, nil))
+		ExpectWithOffset(1, recorder.Code).To(Equal(http.StatusOK))
+
+		progress := new(DrainProgress)
+		ExpectWithOffset(1, json.Unmarshal(recorder.Body.Bytes(), progress)).To(Succeed())
+		return progress
+	}
+
+	It("reports progress without draining on GET", func() {
+		progress := serve(http.MethodGet)
+		Expect(progress.Draining).To(BeFalse())
+		Expect(progress.ActiveTests).To(Equal(1))
+		Expect(progress.QueuedTests).To(Equal(1))
+		Expect(progress.Drained).To(BeFalse())
+		Expect(drainer.IsDraining()).To(BeFalse())
+	})
+
+	It("starts draining on POST and resumes on DELETE", func() {
+		progress := serve(http.MethodPost)
+		Expect(progress.Draining).To(BeTrue())
+		Expect(progress.Drained).To(BeFalse())
+		Expect(drainer.IsDraining()).To(BeTrue())
+		Expect(testutil.ToFloat64(drainer.gauge)).To(Equal(1.0))
+
+		progress = serve(http.MethodDelete)
+		Expect(progress.Draining).To(BeFalse())
+		Expect(drainer.IsDraining()).To(BeFalse())
+		Expect(testutil.ToFloat64(drainer.gauge)).To(Equal(0.0))
+	})
+
+	It("reports that it is drained once no test is active", func() {
+		reader := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(newLoadTest()).
+			Build()
+		drainer.Reader = reader
+		drainer.Drain()
+
+		progress := serve(http.MethodGet)
+		Expect(progress.ActiveTests).To(Equal(0))
+		Expect(progress.QueuedTests).To(Equal(1))
+		Expect(progress.Drained).To(BeTrue())
+	})
+
+	It("rejects other methods", func() {
+		recorder := httptest.NewRecorder()
+		drainer.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, DrainPath, nil))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("never drains when nil", func() {
+		var nilDrainer *Drainer
+		Expect(nilDrainer.IsDraining()).To(BeFalse())
+	})
+})
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+const (
+	// ownerUIDIndexField is the name of a field index on pods, which maps the
+	// UID of a load test to the pods that it owns.
+	ownerUIDIndexField = ".metadata.ownerReferences.loadTestUID"
+
+	// activePoolIndexField is the name of a field index on pods, which maps
+	// the name of a pool to the pods that occupy one of its nodes. Pods that
+	// have succeeded or failed do not occupy a node, so they are not indexed.
+	activePoolIndexField = ".metadata.labels.activePool"
+
+	// activeNodeIndexField is the name of a field index on pods, which maps
+	// the name of a node to the pods that run on it. Like the pool index, it
+	// omits pods that have terminated, and it also omits pods of daemon
+	// sets, which run on every node.
+	activeNodeIndexField = ".spec.activeNodeName"
+)
+
+// indexPodByOwnerUID returns the UIDs of the load tests that own a pod.
+func indexPodByOwnerUID(obj client.Object) []string {
+	var uids []string
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind == "LoadTest" && owner.APIVersion == grpcv1.GroupVersion.String() {
+			uids = append(uids, string(owner.UID))
+		}
+	}
+	return uids
+}
+
+// indexPodByActivePool returns the pool of a pod, if the pod has a pool label
+// and has not terminated.
+func indexPodByActivePool(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+	pool, ok := pod.Labels[config.PoolLabel]
+	if !ok {
+		return nil
+	}
+	return []string{pool}
+}
+
+// indexPodByActiveNode returns the node of a pod, if the pod has been placed
+// on a node, has not terminated and is not owned by a daemon set.
+func indexPodByActiveNode(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return nil
+		}
+	}
+	return []string{pod.Spec.NodeName}
+}
+
+// setupIndexes registers the field indexes that the reconciler uses to list
+// pods from the cache, so that a reconciliation only visits the pods of its
+// test and of the pools it schedules on, instead of every pod in the
+// namespace.
+func setupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &corev1.Pod{}, ownerUIDIndexField, indexPodByOwnerUID); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &corev1.Pod{}, activePoolIndexField, indexPodByActivePool); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &corev1.Pod{}, activeNodeIndexField, indexPodByActiveNode)
+}
+
+// CacheSelectors returns the selectors that restrict the objects held in the
+// cache of the manager. Only nodes with a pool label are cached, since other
+// nodes are never used to run tests. This avoids watching every node in large
+// clusters that run other workloads.
+func CacheSelectors() (cache.SelectorsByObject, error) {
+	poolExists, err := labels.NewRequirement(config.PoolLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	return cache.SelectorsByObject{
+		&corev1.Node{}: {
+			Label: labels.NewSelector().Add(*poolExists),
+		},
+	}, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/status"
+)
+
+// newIndexedPod returns a pod that belongs to a test and runs in a pool.
+func newIndexedPod(test *grpcv1.LoadTest, name, pool string) *corev1.Pod {
+	kind := reflect.TypeOf(grpcv1.LoadTest{}).Name()
+	controllerRef := metav1.NewControllerRef(test.GetObjectMeta(), grpcv1.GroupVersion.WithKind(kind))
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       corev1.NamespaceDefault,
+			Labels:          map[string]string{config.PoolLabel: pool},
+			OwnerReferences: []metav1.OwnerReference{*controllerRef},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+var _ = Describe("Indexes", func() {
+	var test *grpcv1.LoadTest
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				UID:  types.UID("test-uid"),
+			},
+		}
+		pod = newIndexedPod(test, "test-client-0", "workers")
+	})
+
+	Describe("indexPodByOwnerUID", func() {
+		It("returns the UID of the owning load test", func() {
+			Expect(indexPodByOwnerUID(pod)).To(ConsistOf("test-uid"))
+		})
+
+		It("ignores owners that are not load tests", func() {
+			pod.OwnerReferences[0].Kind = "ReplicaSet"
+			Expect(indexPodByOwnerUID(pod)).To(BeEmpty())
+		})
+	})
+
+	Describe("indexPodByActivePool", func() {
+		It("returns the pool of a running pod", func() {
+			Expect(indexPodByActivePool(pod)).To(ConsistOf("workers"))
+		})
+
+		It("ignores pods that have terminated", func() {
+			pod.Status.Phase = corev1.PodSucceeded
+			Expect(indexPodByActivePool(pod)).To(BeEmpty())
+
+			pod.Status.Phase = corev1.PodFailed
+			Expect(indexPodByActivePool(pod)).To(BeEmpty())
+		})
+
+		It("ignores pods without a pool label", func() {
+			delete(pod.Labels, config.PoolLabel)
+			Expect(indexPodByActivePool(pod)).To(BeEmpty())
+		})
+	})
+
+	Describe("indexPodByActiveNode", func() {
+		BeforeEach(func() {
+			pod.Spec.NodeName = "node-1"
+		})
+
+		It("returns the node of a running pod", func() {
+			Expect(indexPodByActiveNode(pod)).To(ConsistOf("node-1"))
+		})
+
+		It("ignores pods that have not been placed or have terminated", func() {
+			pod.Status.Phase = corev1.PodSucceeded
+			Expect(indexPodByActiveNode(pod)).To(BeEmpty())
+
+			pod.Status.Phase = corev1.PodPending
+			pod.Spec.NodeName = ""
+			Expect(indexPodByActiveNode(pod)).To(BeEmpty())
+		})
+
+		It("ignores pods of daemon sets", func() {
+			pod.OwnerReferences[0].Kind = "DaemonSet"
+			Expect(indexPodByActiveNode(pod)).To(BeEmpty())
+		})
+	})
+
+	Describe("CacheSelectors", func() {
+		It("restricts nodes to those with a pool label", func() {
+			selectors, err := CacheSelectors()
+			Expect(err).ToNot(HaveOccurred())
+
+			var nodeSelector string
+			for obj, selector := range selectors {
+				if _, ok := obj.(*corev1.Node); ok {
+					nodeSelector = selector.Label.String()
+				}
+			}
+			Expect(nodeSelector).To(Equal(config.PoolLabel))
+		})
+	})
+})
+
+// benchmarkPools are the pools used by the pods in the benchmarks.
+var benchmarkPools = []string{"drivers", "workers-8core", "workers-32core"}
+
+// newBenchmarkIndexer returns an indexer, like the one that backs the cache of
+// the manager, holding 500 pods that belong to 100 tests. Each test has a
+// driver, two servers and two clients, and the pods of half of the tests have
+// succeeded.
+func newBenchmarkIndexer(b *testing.B) (toolscache.Indexer, []*grpcv1.LoadTest) {
+	indexFunc := func(extract client.IndexerFunc) toolscache.IndexFunc {
+		return func(obj interface{}) ([]string, error) {
+			return extract(obj.(client.Object)), nil
+		}
+	}
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{
+		ownerUIDIndexField:   indexFunc(indexPodByOwnerUID),
+		activePoolIndexField: indexFunc(indexPodByActivePool),
+	})
+
+	var tests []*grpcv1.LoadTest
+	for i := 0; i < 100; i++ {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("test-%d", i),
+				UID:  types.UID(fmt.Sprintf("test-uid-%d", i)),
+			},
+		}
+		tests = append(tests, test)
+
+		workerPool := benchmarkPools[1+i%2]
+		pods := []*corev1.Pod{
+			newIndexedPod(test, test.Name+"-driver", benchmarkPools[0]),
+			newIndexedPod(test, test.Name+"-server-0", workerPool),
+			newIndexedPod(test, test.Name+"-server-1", workerPool),
+			newIndexedPod(test, test.Name+"-client-0", workerPool),
+			newIndexedPod(test, test.Name+"-client-1", workerPool),
+		}
+		for _, pod := range pods {
+			if i%4 < 2 {
+				pod.Status.Phase = corev1.PodSucceeded
+			}
+			if err := indexer.Add(pod); err != nil {
+				b.Fatalf("failed to add pod to indexer: %v", err)
+			}
+		}
+	}
+
+	return indexer, tests
+}
+
+// toPodList copies objects from an indexer into a list of pods. The cache of
+// the manager copies every object that a list returns in the same way, so the
+// number of copied pods is a measure of the work done by each list.
+func toPodList(objs []interface{}) []corev1.Pod {
+	pods := make([]corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pods = append(pods, *obj.(*corev1.Pod).DeepCopy())
+	}
+	return pods
+}
+
+// BenchmarkListPodsWithoutIndexes lists the pods that are needed to schedule
+// a test by listing every pod in the namespace and filtering them, which is
+// how the reconciler worked before the indexes were added.
+func BenchmarkListPodsWithoutIndexes(b *testing.B) {
+	indexer, tests := newBenchmarkIndexer(b)
+	copiedPods := 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		test := tests[i%len(tests)]
+
+		allPods := toPodList(indexer.List())
+		copiedPods += len(allPods)
+		_ = status.PodsForLoadTest(test, allPods)
+
+		activePods := make(map[string]int)
+		for j := range allPods {
+			for _, pool := range indexPodByActivePool(&allPods[j]) {
+				activePods[pool]++
+			}
+		}
+	}
+	b.ReportMetric(float64(copiedPods)/float64(b.N), "pods/op")
+}
+
+// BenchmarkListPodsWithIndexes lists the pods that are needed to schedule a
+// test using the owner and pool indexes. Only the pods of the test and the
+// active pods in the pools that the test requests are listed.
+func BenchmarkListPodsWithIndexes(b *testing.B) {
+	indexer, tests := newBenchmarkIndexer(b)
+	copiedPods := 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		test := tests[i%len(tests)]
+
+		objs, err := indexer.ByIndex(ownerUIDIndexField, string(test.UID))
+		if err != nil {
+			b.Fatalf("failed to list pods by owner: %v", err)
+		}
+		ownedPods := toPodList(objs)
+		copiedPods += len(ownedPods)
+		_ = status.PodsForLoadTest(test, ownedPods)
+
+		activePods := make(map[string]int)
+		for _, pool := range []string{benchmarkPools[0], benchmarkPools[1+i%2]} {
+			objs, err := indexer.ByIndex(activePoolIndexField, pool)
+			if err != nil {
+				b.Fatalf("failed to list pods by pool: %v", err)
+			}
+			poolPods := toPodList(objs)
+			copiedPods += len(poolPods)
+			activePods[pool] = len(poolPods)
+		}
+	}
+	b.ReportMetric(float64(copiedPods)/float64(b.N), "pods/op")
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// isolationTaint returns the taint that reserves a node for a test with
+// isolated placement.
+func isolationTaint(test *grpcv1.LoadTest) corev1.Taint {
+	return corev1.Taint{
+		Key:    config.IsolationTaintKey,
+		Value:  string(test.UID),
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+}
+
+// isolatedFor returns the UID of the test that a node is reserved for, and
+// whether the node is reserved at all.
+func isolatedFor(node *corev1.Node) (string, bool) {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == config.IsolationTaintKey {
+			return taint.Value, true
+		}
+	}
+	return "", false
+}
+
+// withoutIsolationTaint returns the taints of a node, except the isolation
+// taint.
+func withoutIsolationTaint(taints []corev1.Taint) []corev1.Taint {
+	var kept []corev1.Taint
+	for _, taint := range taints {
+		if taint.Key != config.IsolationTaintKey {
+			kept = append(kept, taint)
+		}
+	}
+	return kept
+}
+
+// nodeReady returns true if a node is ready and accepts new pods.
+func nodeReady(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeFreeForIsolation returns true if a node may be reserved for a test. The
+// node must be ready and must have no taint that keeps the pods of the test
+// away, which includes the isolation taint of any test.
+func nodeFreeForIsolation(node *corev1.Node) bool {
+	if !nodeReady(node) {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	return true
+}
+
+// isolatedNode returns the name of the node that a pod is pinned to, or an
+// empty string if the pod is not pinned.
+func isolatedNode(pod *corev1.Pod) string {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return ""
+	}
+	selector := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if selector == nil {
+		return ""
+	}
+	for _, term := range selector.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// pinToIsolatedNode makes a pod tolerate the isolation taint of its test and
+// requires it to be placed on a node reserved for the test. The affinity that
+// the pod builder set is kept, so the pod still avoids the pods of other
+// tests.
+func pinToIsolatedNode(pod *corev1.Pod, test *grpcv1.LoadTest, nodeName string) {
+	taint := isolationTaint(test)
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      taint.Key,
+		Operator: corev1.TolerationOpEqual,
+		Value:    taint.Value,
+		Effect:   taint.Effect,
+	})
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = new(corev1.Affinity)
+	}
+	pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchFields: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "metadata.name",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{nodeName},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// selectIsolatedNodes chooses the nodes of a pool that a test uses for its
+// missing pods. Nodes that are already reserved for the test and are not
+// claimed by one of its pods are chosen first, followed by free nodes, in the
+// order of their names. The free nodes that were chosen are returned
+// separately, since they must still be tainted. The boolean is false if the
+// pool does not have enough nodes.
+func selectIsolatedNodes(nodes []corev1.Node, test *grpcv1.LoadTest, busy map[string]bool, claimed map[string]bool, count int) ([]string, []*corev1.Node, bool) {
+	sorted := make([]*corev1.Node, 0, len(nodes))
+	for i := range nodes {
+		sorted = append(sorted, &nodes[i])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var chosen []string
+	for _, node := range sorted {
+		if len(chosen) == count {
+			break
+		}
+		if uid, ok := isolatedFor(node); ok && uid == string(test.UID) && !claimed[node.Name] {
+			chosen = append(chosen, node.Name)
+		}
+	}
+
+	var toTaint []*corev1.Node
+	for _, node := range sorted {
+		if len(chosen) == count {
+			break
+		}
+		if !nodeFreeForIsolation(node) || busy[node.Name] {
+			continue
+		}
+		chosen = append(chosen, node.Name)
+		toTaint = append(toTaint, node)
+	}
+
+	return chosen, toTaint, len(chosen) == count
+}
+
+// reserveIsolatedNodes taints the nodes that a test with isolated placement
+// requires for its missing pods, and returns the names of the nodes reserved
+// for each pool. Nodes are only tainted if every pool has enough nodes that
+// run no other pods, so a test that must wait does not hold nodes that
+// other tests could use. The boolean is false if the test must wait.
+func (r *LoadTestReconciler) reserveIsolatedNodes(ctx context.Context, test *grpcv1.LoadTest, nodeCountByPool map[string]int, ownedPods []*corev1.Pod) (map[string][]string, bool, error) {
+	claimed := make(map[string]bool)
+	for _, pod := range ownedPods {
+		if nodeName := isolatedNode(pod); nodeName != "" {
+			claimed[nodeName] = true
+		}
+	}
+
+	reserved := make(map[string][]string)
+	var toTaint []*corev1.Node
+	for pool, count := range nodeCountByPool {
+		if count == 0 {
+			continue
+		}
+
+		nodes := new(corev1.NodeList)
+		if err := r.List(ctx, nodes, client.MatchingLabels{config.PoolLabel: pool}); err != nil {
+			return nil, false, fmt.Errorf("failed to list nodes in pool %q: %v", pool, err)
+		}
+
+		busy := make(map[string]bool)
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			if !nodeFreeForIsolation(node) {
+				continue
+			}
+			pods := new(corev1.PodList)
+			if err := r.List(ctx, pods, client.MatchingFields{activeNodeIndexField: node.Name}); err != nil {
+				return nil, false, fmt.Errorf("failed to list pods on node %q: %v", node.Name, err)
+			}
+			busy[node.Name] = len(pods.Items) > 0
+		}
+
+		names, poolToTaint, ok := selectIsolatedNodes(nodes.Items, test, busy, claimed, count)
+		if !ok {
+			return nil, false, nil
+		}
+		reserved[pool] = names
+		toTaint = append(toTaint, poolToTaint...)
+	}
+
+	for _, node := range toTaint {
+		patched := node.DeepCopy()
+		patched.Spec.Taints = append(patched.Spec.Taints, isolationTaint(test))
+		if err := r.Patch(ctx, patched, client.MergeFromWithOptions(node, client.MergeFromWithOptimisticLock{})); err != nil {
+			return nil, false, fmt.Errorf("failed to taint node %q: %v", node.Name, err)
+		}
+	}
+
+	return reserved, true, nil
+}
+
+// releaseIsolatedNodes removes the isolation taints of a test from the nodes
+// that were reserved for it.
+func (r *LoadTestReconciler) releaseIsolatedNodes(ctx context.Context, uid string) error {
+	return r.releaseIsolatedNodesIf(ctx, func(nodeUID string) bool {
+		return nodeUID == uid
+	})
+}
+
+// releaseOrphanedIsolatedNodes removes the isolation taints of tests that no
+// longer exist, such as tests that were deleted before they terminated.
+func (r *LoadTestReconciler) releaseOrphanedIsolatedNodes(ctx context.Context) error {
+	tests := new(grpcv1.LoadTestList)
+	if err := r.List(ctx, tests); err != nil {
+		return fmt.Errorf("failed to list tests: %v", err)
+	}
+	existing := make(map[string]bool)
+	for i := range tests.Items {
+		existing[string(tests.Items[i].UID)] = true
+	}
+	return r.releaseIsolatedNodesIf(ctx, func(nodeUID string) bool {
+		return !existing[nodeUID]
+	})
+}
+
+// releaseIsolatedNodesIf removes the isolation taint from each node whose
+// taint holds a UID that matches a predicate.
+func (r *LoadTestReconciler) releaseIsolatedNodesIf(ctx context.Context, matches func(uid string) bool) error {
+	nodes := new(corev1.NodeList)
+	if err := r.List(ctx, nodes, client.HasLabels{config.PoolLabel}); err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if uid, ok := isolatedFor(node); !ok || !matches(uid) {
+			continue
+		}
+		patched := node.DeepCopy()
+		patched.Spec.Taints = withoutIsolationTaint(node.Spec.Taints)
+		if err := r.Patch(ctx, patched, client.MergeFromWithOptions(node, client.MergeFromWithOptimisticLock{})); err != nil {
+			return fmt.Errorf("failed to remove isolation taint from node %q: %v", node.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// newIsolationNode returns a ready node with a given name and taints.
+func newIsolationNode(name string, taints ...corev1.Taint) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+var _ = Describe("Isolation", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				UID:  types.UID("test-uid"),
+			},
+		}
+	})
+
+	Describe("pinToIsolatedNode", func() {
+		It("pins the pod to the node and tolerates the taint of the test", func() {
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}},
+				},
+			}
+			pinToIsolatedNode(pod, test, "node-1")
+
+			Expect(isolatedNode(pod)).To(Equal("node-1"))
+			Expect(pod.Spec.Affinity.PodAntiAffinity).ToNot(BeNil())
+			Expect(pod.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+				Key:      config.IsolationTaintKey,
+				Operator: corev1.TolerationOpEqual,
+				Value:    "test-uid",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}))
+		})
+
+		It("reports no node for pods that are not pinned", func() {
+			Expect(isolatedNode(&corev1.Pod{})).To(BeEmpty())
+		})
+	})
+
+	Describe("selectIsolatedNodes", func() {
+		It("chooses free nodes in the order of their names", func() {
+			nodes := []corev1.Node{newIsolationNode("c"), newIsolationNode("a"), newIsolationNode("b")}
+			chosen, toTaint, ok := selectIsolatedNodes(nodes, test, nil, nil, 2)
+			Expect(ok).To(BeTrue())
+			Expect(chosen).To(Equal([]string{"a", "b"}))
+			Expect(toTaint).To(HaveLen(2))
+		})
+
+		It("prefers nodes already reserved for the test", func() {
+			nodes := []corev1.Node{newIsolationNode("a"), newIsolationNode("b", isolationTaint(test))}
+			chosen, toTaint, ok := selectIsolatedNodes(nodes, test, nil, nil, 1)
+			Expect(ok).To(BeTrue())
+			Expect(chosen).To(Equal([]string{"b"}))
+			Expect(toTaint).To(BeEmpty())
+		})
+
+		It("skips reserved nodes that are claimed by a pod of the test", func() {
+			nodes := []corev1.Node{newIsolationNode("a"), newIsolationNode("b", isolationTaint(test))}
+			chosen, _, ok := selectIsolatedNodes(nodes, test, nil, map[string]bool{"b": true}, 1)
+			Expect(ok).To(BeTrue())
+			Expect(chosen).To(Equal([]string{"a"}))
+		})
+
+		It("skips busy, tainted and unready nodes", func() {
+			other := &grpcv1.LoadTest{ObjectMeta: metav1.ObjectMeta{UID: types.UID("other-uid")}}
+			unready := newIsolationNode("d")
+			unready.Status.Conditions[0].Status = corev1.ConditionFalse
+			nodes := []corev1.Node{
+				newIsolationNode("a"),
+				newIsolationNode("b", isolationTaint(other)),
+				newIsolationNode("c", corev1.Taint{Key: "dedicated", Effect: corev1.TaintEffectNoExecute}),
+				unready,
+			}
+			_, _, ok := selectIsolatedNodes(nodes, test, map[string]bool{"a": true}, nil, 1)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("withoutIsolationTaint", func() {
+		It("keeps other taints", func() {
+			other := corev1.Taint{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}
+			Expect(withoutIsolationTaint([]corev1.Taint{other, isolationTaint(test)})).To(ConsistOf(other))
+		})
+	})
+})
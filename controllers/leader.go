@@ -0,0 +1,105 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// errNotLeader is returned by the readiness check of a manager that does not
+// hold the leader election lease.
+var errNotLeader = errors.New("manager is not the leader")
+
+// LeaderTracker records whether a manager holds the leader election lease,
+// and exposes its leadership as metrics and as a readiness check. This allows
+// deployments with several replicas to be monitored during failover, and
+// allows traffic such as webhook requests to be routed to the leader only.
+//
+// The tracker is added to a manager as a runnable that requires leader
+// election. The manager starts it once the lease is acquired, and cancels its
+// context when the manager stops. When leader election is disabled, the
+// manager starts the tracker immediately.
+type LeaderTracker struct {
+	isLeader    atomic.Bool
+	leader      prometheus.Gauge
+	transitions *prometheus.CounterVec
+}
+
+// NewLeaderTracker creates a tracker and registers its metrics with the given
+// registerer, usually the metrics registry of controller-runtime.
+func NewLeaderTracker(registerer prometheus.Registerer) (*LeaderTracker, error) {
+	t := &LeaderTracker{
+		leader: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "loadtest_controller_leader",
+			Help: "Whether this manager holds the leader election lease (1) or not (0).",
+		}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loadtest_controller_leader_transitions_total",
+			Help: "Number of times this manager acquired or released the leader election lease.",
+		}, []string{"transition"}),
+	}
+	for _, c := range []prometheus.Collector{t.leader, t.transitions} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Start marks the manager as the leader until the context is cancelled.
+func (t *LeaderTracker) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	t.isLeader.Store(true)
+	t.leader.Set(1)
+	t.transitions.WithLabelValues("acquired").Inc()
+	logger.Info("acquired leadership")
+
+	<-ctx.Done()
+
+	t.isLeader.Store(false)
+	t.leader.Set(0)
+	t.transitions.WithLabelValues("released").Inc()
+	logger.Info("released leadership")
+	return nil
+}
+
+// NeedLeaderElection ensures the tracker is only started once the manager
+// holds the leader election lease.
+func (t *LeaderTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// IsLeader returns true while the manager holds the leader election lease.
+func (t *LeaderTracker) IsLeader() bool {
+	return t.isLeader.Load()
+}
+
+// ReadyzCheck is a readiness check that only succeeds while the manager holds
+// the leader election lease.
+func (t *LeaderTracker) ReadyzCheck(_ *http.Request) error {
+	if !t.IsLeader() {
+		return errNotLeader
+	}
+	return nil
+}
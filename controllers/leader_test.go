@@ -0,0 +1,100 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// startLeaderManager starts a manager that competes for the given leader
+// election lease, and returns its tracker and a function that stops it.
+func startLeaderManager(leaderElectionID string) (*LeaderTracker, context.CancelFunc) {
+	leaseDuration := 2 * time.Second
+	renewDeadline := 1 * time.Second
+	retryPeriod := 200 * time.Millisecond
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                        scheme.Scheme,
+		MetricsBindAddress:            "0",
+		HealthProbeBindAddress:        "0",
+		LeaderElection:                true,
+		LeaderElectionID:              leaderElectionID,
+		LeaderElectionNamespace:       corev1.NamespaceDefault,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
+	})
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	tracker, err := NewLeaderTracker(prometheus.NewRegistry())
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, mgr.Add(tracker)).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		defer close(done)
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+
+	return tracker, func() {
+		cancel()
+		<-done
+	}
+}
+
+var _ = Describe("LeaderTracker", func() {
+	It("hands off leadership when the leader stops", func() {
+		leaderElectionID := uuid.New().String()
+
+		first, stopFirst := startLeaderManager(leaderElectionID)
+		defer stopFirst()
+		Eventually(first.IsLeader, 10*time.Second).Should(BeTrue())
+		Expect(first.ReadyzCheck(nil)).To(Succeed())
+
+		second, stopSecond := startLeaderManager(leaderElectionID)
+		defer stopSecond()
+		Consistently(second.IsLeader, time.Second).Should(BeFalse())
+		Expect(second.ReadyzCheck(nil)).ToNot(Succeed())
+		Expect(testutil.ToFloat64(second.leader)).To(Equal(0.0))
+
+		stopFirst()
+		Expect(first.IsLeader()).To(BeFalse())
+		Expect(first.ReadyzCheck(nil)).ToNot(Succeed())
+		Expect(testutil.ToFloat64(first.leader)).To(Equal(0.0))
+		Expect(testutil.ToFloat64(first.transitions.WithLabelValues("acquired"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(first.transitions.WithLabelValues("released"))).To(Equal(1.0))
+
+		Eventually(second.IsLeader, 10*time.Second).Should(BeTrue())
+		Expect(second.ReadyzCheck(nil)).To(Succeed())
+		Expect(testutil.ToFloat64(second.leader)).To(Equal(1.0))
+		Expect(testutil.ToFloat64(second.transitions.WithLabelValues("acquired"))).To(Equal(1.0))
+	})
+})
@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +36,9 @@ import (
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/imagecheck"
 	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/optional"
 	"github.com/grpc/test-infra/podbuilder"
 	"github.com/grpc/test-infra/status"
 )
@@ -44,12 +48,31 @@ var (
 	errNonexistentPool = errors.New("pool does not exist")
 )
 
+// managedLabels are the labels that the controller sets on every resource it
+// creates. A resource without these labels has been changed by someone else.
+var managedLabels = map[string]string{
+	config.ManagedByLabel: config.ControllerName,
+}
+
 // LoadTestReconciler reconciles a LoadTest object
 type LoadTestReconciler struct {
 	client.Client
 	mgr      ctrl.Manager
 	Defaults *config.Defaults
 	Scheme   *runtime.Scheme
+
+	// ImageChecker verifies that the container images for a test exist
+	// before its pods are created. When nil, no check is performed and
+	// missing images surface as pods that cannot start.
+	ImageChecker imagecheck.Checker
+
+	// SchedulingMetrics records the time the pods of each test take to be
+	// running in each pool. When nil, no metrics are recorded.
+	SchedulingMetrics *SchedulingMetrics
+
+	// Drainer holds back tests that have no pods yet while the manager
+	// drains. When nil, tests are always started.
+	Drainer *Drainer
 }
 
 // +kubebuilder:rbac:groups=e2etest.grpc.io,resources=loadtests,verbs=get;list;watch;create;update;patch;delete
@@ -57,8 +80,10 @@ type LoadTestReconciler struct {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=nodes/status,verbs=get
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 
@@ -72,35 +97,156 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	rawTest := new(grpcv1.LoadTest)
 	if err = r.Get(ctx, req.NamespacedName, rawTest); err != nil {
 		logger.Error(err, "failed to get test", "name", req.NamespacedName)
+		if kerrors.IsNotFound(err) {
+			// A test that is deleted before it terminates leaves the
+			// isolation taints on its nodes, so remove them here.
+			if releaseErr := r.releaseOrphanedIsolatedNodes(ctx); releaseErr != nil {
+				logger.Error(releaseErr, "failed to release nodes of deleted tests")
+				return ctrl.Result{Requeue: true}, releaseErr
+			}
+		}
 		err = client.IgnoreNotFound(err)
 		return ctrl.Result{Requeue: err != nil}, err
 	}
 
+	// Tenants may override the defaults and have node pools of their own,
+	// so the defaults for the namespace of the test are used throughout.
+	defaults, err := r.Defaults.ForNamespace(req.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to get defaults for namespace", "namespace", req.Namespace)
+		return ctrl.Result{Requeue: false}, nil
+	}
+
 	testTTL := time.Duration(rawTest.Spec.TTLSeconds) * time.Second
 	testTimeout := time.Duration(rawTest.Spec.TimeoutSeconds) * time.Second
 
+	// Tests that omit the timeout are given one computed from their
+	// scenarios when the defaults are applied, so these checks are skipped.
 	if testTimeout > testTTL {
 		logger.Info("testTTL is less than testTimeout", "testTimeout", testTimeout, "testTTL", testTTL)
 	}
 
+	if soakDuration := status.SoakDuration(rawTest); testTimeout > 0 && soakDuration > testTimeout {
+		logger.Info("testTimeout is less than soakDuration", "soakDuration", soakDuration, "testTimeout", testTimeout)
+	}
+
+	if status.RerunRequested(rawTest) {
+		// The outcome of the run is kept in the status of the test, and the
+		// pods of the next run are created when the test is reconciled
+		// again, as they would be for a new test.
+		test := rawTest.DeepCopy()
+		status.StartRerun(test)
+		if err = r.Status().Update(ctx, test); err != nil {
+			logger.Error(err, "failed to update status to rerun test")
+			return ctrl.Result{Requeue: true}, err
+		}
+		logger.Info("rerunning test", "run", test.Status.Run, "rerunRequest", test.Status.RerunRequest)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	if rawTest.Status.State.IsTerminated() {
-		if time.Since(rawTest.Status.StartTime.Time) >= testTTL {
-			logger.Info("test expired, deleting", "startTime", rawTest.Status.StartTime, "testTTL", testTTL)
-			if err = r.Delete(ctx, rawTest); err != nil {
-				logger.Error(err, "fail to delete test")
+		// The pods of a terminated test no longer need protection from
+		// voluntary disruptions, so release the budget immediately rather than
+		// waiting for garbage collection when the test expires.
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: req.Namespace,
+			},
+		}
+		if err = r.Delete(ctx, pdb); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "failed to delete pod disruption budget for terminated test")
+			return ctrl.Result{Requeue: true}, err
+		}
+
+		if rawTest.Spec.PlacementPolicy == grpcv1.IsolatedPlacement {
+			if err = r.releaseIsolatedNodes(ctx, string(rawTest.UID)); err != nil {
+				logger.Error(err, "failed to release nodes of terminated test")
 				return ctrl.Result{Requeue: true}, err
 			}
 		}
+
+		if _, ok := rawTest.Annotations[config.KeepAnnotation]; !ok && defaults.KeepFirstFailures && rawTest.Status.State == grpcv1.Errored {
+			tests := new(grpcv1.LoadTestList)
+			if err = r.List(ctx, tests, client.InNamespace(req.Namespace)); err != nil {
+				logger.Error(err, "failed to list tests", "namespace", req.Namespace)
+				return ctrl.Result{Requeue: true}, err
+			}
+
+			// Record the decision on the test, so that it is not revisited
+			// when another kept test with the same reason is deleted.
+			keep := status.IsFirstFailureOfItsKind(rawTest, tests.Items)
+			if rawTest.Annotations == nil {
+				rawTest.Annotations = make(map[string]string)
+			}
+			rawTest.Annotations[config.KeepAnnotation] = strconv.FormatBool(keep)
+			if err = r.Update(ctx, rawTest); err != nil {
+				logger.Error(err, "failed to update keep annotation of errored test")
+				return ctrl.Result{Requeue: true}, err
+			}
+			if keep {
+				logger.Info("keeping test as the first of its kind to fail", "reason", rawTest.Status.Reason)
+			}
+		}
+
+		if _, ok := rawTest.Annotations[config.DashboardURLAnnotation]; !ok && defaults.DashboardURLTemplate != "" {
+			// The template is validated with the defaults, so a test that
+			// cannot be rendered is logged rather than retried.
+			link, err := renderDashboardURL(defaults.DashboardURLTemplate, rawTest)
+			if err != nil {
+				logger.Error(err, "failed to render dashboard URL")
+			} else {
+				if rawTest.Annotations == nil {
+					rawTest.Annotations = make(map[string]string)
+				}
+				rawTest.Annotations[config.DashboardURLAnnotation] = link
+				if err = r.Update(ctx, rawTest); err != nil {
+					logger.Error(err, "failed to update dashboard URL annotation of terminated test")
+					return ctrl.Result{Requeue: true}, err
+				}
+			}
+		}
+
+		if status.IsKept(rawTest) {
+			if defaults.KeepRetentionSeconds == 0 {
+				logger.Info("test is kept, skipping deletion")
+				return ctrl.Result{Requeue: false}, nil
+			}
+			if retention := time.Duration(defaults.KeepRetentionSeconds) * time.Second; retention > testTTL {
+				testTTL = retention
+			}
+		}
+
+		// Tests that errored before they started were not given a start
+		// time by older versions of the controller, so their age is
+		// measured from their creation instead.
+		startTime := rawTest.CreationTimestamp.Time
+		if rawTest.Status.StartTime != nil {
+			startTime = rawTest.Status.StartTime.Time
+		}
+		if age := time.Since(startTime); age < testTTL {
+			if status.IsKept(rawTest) {
+				return ctrl.Result{RequeueAfter: testTTL - age}, nil
+			}
+			return ctrl.Result{Requeue: false}, nil
+		}
+
+		logger.Info("test expired, deleting", "startTime", startTime, "testTTL", testTTL)
+		if err = r.Delete(ctx, rawTest); err != nil {
+			logger.Error(err, "fail to delete test")
+			return ctrl.Result{Requeue: true}, err
+		}
 		return ctrl.Result{Requeue: false}, nil
 	}
 
 	// TODO(codeblooded): Consider moving this to a mutating webhook
 	test := rawTest.DeepCopy()
-	if err = r.Defaults.SetLoadTestDefaults(test); err != nil {
+	if err = defaults.SetLoadTestDefaults(test); err != nil {
 		logger.Error(err, "failed to clone test with defaults")
 		test.Status.State = grpcv1.Errored
 		test.Status.Reason = grpcv1.FailedSettingDefaultsError
 		test.Status.Message = fmt.Sprintf("failed to reconcile tests with defaults: %v", err)
+		markStopped(test)
 		if err = r.Status().Update(ctx, test); err != nil {
 			logger.Error(err, "failed to update test status when setting defaults failed")
 		}
@@ -113,33 +259,67 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	}
 
-	cfgMap := new(corev1.ConfigMap)
-	if err = r.Get(ctx, req.NamespacedName, cfgMap); err != nil {
-		logger.Info("failed to find existing scenarios ConfigMap")
+	if test.Annotations[config.DryRunAnnotation] == "true" {
+		return r.dryRun(ctx, defaults, test, logger)
+	}
 
-		if client.IgnoreNotFound(err) != nil {
-			// The ConfigMap existence was not at issue, so this is likely an
-			// issue with the Kubernetes API. So, we'll update the status, retry
-			// with exponential backoff and allow the timeout to catch it.
-			test.Status.State = grpcv1.Unknown
-			test.Status.Reason = grpcv1.KubernetesError
-			test.Status.Message = fmt.Sprintf("kubernetes error (retrying): failed to get scenarios ConfigMap: %v", err)
-			if updateErr := r.Status().Update(ctx, test); updateErr != nil {
-				logger.Error(updateErr, "failed to update status after failure to get scenarios ConfigMap: %v", err)
-			}
-			return ctrl.Result{Requeue: true}, err
+	cfgMap := new(corev1.ConfigMap)
+	if err = r.Get(ctx, req.NamespacedName, cfgMap); client.IgnoreNotFound(err) != nil {
+		// The ConfigMap existence was not at issue, so this is likely an
+		// issue with the Kubernetes API. So, we'll update the status, retry
+		// with exponential backoff and allow the timeout to catch it.
+		logger.Info("failed to get scenarios ConfigMap")
+		test.Status.State = grpcv1.Unknown
+		test.Status.Reason = grpcv1.KubernetesError
+		test.Status.Message = fmt.Sprintf("kubernetes error (retrying): failed to get scenarios ConfigMap: %v", err)
+		if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+			logger.Error(updateErr, "failed to update status after failure to get scenarios ConfigMap: %v", err)
 		}
+		return ctrl.Result{Requeue: true}, err
+	}
+	cfgMapMissing := kerrors.IsNotFound(err)
 
-		scenariosJSON := test.Spec.ScenariosJSON
+	scenariosJSON, err := kubehelpers.UpdateConfigMapWithServerPort(fmt.Sprint(config.ServerPort), test.Spec.ScenariosJSON)
+	if err != nil {
+		logger.Error(err, "failed to update ConfigMap with test server port")
+		return ctrl.Result{Requeue: true}, err
+	}
 
-		testServerPort := config.ServerPort
-		scenariosJSON, err = kubehelpers.UpdateConfigMapWithServerPort(fmt.Sprint(testServerPort), test.Spec.ScenariosJSON)
-		if err != nil {
-			logger.Error(err, "failed to update ConfigMap with test server port")
-			return ctrl.Result{Requeue: true}, err
+	scenariosJSON, err = kubehelpers.UpdateConfigMapWithScenarioOverrides(test.Annotations, scenariosJSON)
+	if err != nil {
+		logger.Error(err, "failed to apply scenario overrides from annotations")
+		test.Status.State = grpcv1.Errored
+		test.Status.Reason = grpcv1.ConfigurationError
+		test.Status.Message = fmt.Sprintf("failed to apply scenario overrides: %v", err)
+		markStopped(test)
+		if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+			logger.Error(updateErr, "failed to update status after failure to apply scenario overrides")
 		}
+		return ctrl.Result{Requeue: false}, nil
+	}
 
-		logger.Info(fmt.Sprintf("using %v as test server port", config.ServerPort))
+	scenariosJSON, err = kubehelpers.UpdateConfigMapWithLoadProfile(test.Spec.LoadProfile, scenariosJSON)
+	if err != nil {
+		logger.Error(err, "failed to apply load profile")
+		test.Status.State = grpcv1.Errored
+		test.Status.Reason = grpcv1.ConfigurationError
+		test.Status.Message = fmt.Sprintf("failed to apply load profile: %v", err)
+		markStopped(test)
+		if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+			logger.Error(updateErr, "failed to update status after failure to apply load profile")
+		}
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	// Reapply the ConfigMap when it is missing or was changed by someone
+	// else, so the driver always reads the scenario of the test.
+	if cfgMapMissing || cfgMap.Data["scenarios.json"] != scenariosJSON || kubehelpers.LabelsDrifted(managedLabels, cfgMap.Labels) {
+		if cfgMapMissing {
+			logger.Info("failed to find existing scenarios ConfigMap")
+			logger.Info(fmt.Sprintf("using %v as test server port", config.ServerPort))
+		} else {
+			logger.Info("scenarios ConfigMap has drifted, reapplying")
+		}
 
 		cfgMap = &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
@@ -169,21 +349,88 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{Requeue: true}, refError
 		}
 
-		if createErr := r.Create(ctx, cfgMap); createErr != nil {
-			logger.Error(err, "failed to create scenarios ConfigMap")
-			return ctrl.Result{Requeue: true}, createErr
+		if applyErr := r.apply(ctx, cfgMap); applyErr != nil {
+			logger.Error(applyErr, "failed to apply scenarios ConfigMap")
+			return ctrl.Result{Requeue: true}, applyErr
+		}
+	}
+
+	pdb := new(policyv1.PodDisruptionBudget)
+	if err = r.Get(ctx, req.NamespacedName, pdb); client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "failed to get pod disruption budget")
+		return ctrl.Result{Requeue: true}, err
+	}
+	if kerrors.IsNotFound(err) || kubehelpers.LabelsDrifted(managedLabels, pdb.Labels) {
+		pdb = kubehelpers.PodDisruptionBudgetForLoadTest(test)
+		if refError := ctrl.SetControllerReference(test, pdb, r.Scheme); refError != nil {
+			logger.Error(refError, "could not set controller reference on pod disruption budget")
+			return ctrl.Result{Requeue: true}, refError
+		}
+
+		if applyErr := r.apply(ctx, pdb); applyErr != nil {
+			logger.Error(applyErr, "failed to apply pod disruption budget")
+			return ctrl.Result{Requeue: true}, applyErr
+		}
+	}
+
+	services := []*corev1.Service{kubehelpers.HeadlessServiceForLoadTest(test)}
+	if driverSvc := kubehelpers.DriverServiceForLoadTest(test); driverSvc != nil {
+		services = append(services, driverSvc)
+	}
+	for _, desiredSvc := range services {
+		logWithService := logger.WithValues("service", desiredSvc.Name)
+
+		svc := new(corev1.Service)
+		if err = r.Get(ctx, client.ObjectKeyFromObject(desiredSvc), svc); client.IgnoreNotFound(err) != nil {
+			logWithService.Error(err, "failed to get service")
+			return ctrl.Result{Requeue: true}, err
+		}
+		if !kerrors.IsNotFound(err) && !kubehelpers.LabelsDrifted(managedLabels, svc.Labels) {
+			continue
+		}
+
+		if refError := ctrl.SetControllerReference(test, desiredSvc, r.Scheme); refError != nil {
+			logWithService.Error(refError, "could not set controller reference on service")
+			return ctrl.Result{Requeue: true}, refError
+		}
+
+		if applyErr := r.apply(ctx, desiredSvc); applyErr != nil {
+			if kerrors.IsInvalid(applyErr) {
+				// Services are derived from the name and spec of the test,
+				// so retrying will not help.
+				logWithService.Error(applyErr, "service is invalid")
+				test.Status.State = grpcv1.Errored
+				test.Status.Reason = grpcv1.ConfigurationError
+				test.Status.Message = fmt.Sprintf("failed to create service %q: %v", desiredSvc.Name, applyErr)
+				markStopped(test)
+				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+					logWithService.Error(updateErr, "failed to update status after failure to create service")
+				}
+				return ctrl.Result{Requeue: false}, nil
+			}
+			logWithService.Error(applyErr, "failed to apply service")
+			return ctrl.Result{Requeue: true}, applyErr
 		}
 	}
 
 	pods := new(corev1.PodList)
-	if err = r.List(ctx, pods, client.InNamespace(req.Namespace)); err != nil {
+	if err = r.List(ctx, pods, client.InNamespace(req.Namespace), client.MatchingFields{ownerUIDIndexField: string(test.UID)}); err != nil {
 		logger.Error(err, "failed to list pods", "namespace", req.Namespace)
 		return ctrl.Result{Requeue: true}, err
 	}
 	ownedPods := status.PodsForLoadTest(test, pods.Items)
 
 	previousStatus := test.Status
-	test.Status = status.ForLoadTest(test, ownedPods)
+	test.Status = status.ForLoadTest(test, ownedPods, &status.WaitingLimits{
+		ImagePullTimeout: time.Duration(defaults.ImagePullTimeoutSeconds) * time.Second,
+		MaxRestarts:      defaults.MaxContainerRestarts,
+	})
+	if test.Status.State.IsTerminated() && len(defaults.MachineHourlyPrices) > 0 {
+		test.Status.Cost = status.CostForLoadTest(test, ownedPods, r.machineTypesForPods(ctx, ownedPods), defaults.MachineHourlyPrices)
+	}
+	schedulingSLO := time.Duration(defaults.SchedulingSLOSeconds) * time.Second
+	schedulingLatencies := status.RecordScheduling(test, ownedPods, schedulingSLO)
+	soakContinued := status.ContinueSoak(test, time.Now())
 	if err = r.Status().Update(ctx, test); err != nil {
 		// Racing conditions arises when multiple threads tried to update the status
 		// of the same object. Since Kubernetes' control loop is edge-triggered and
@@ -201,24 +448,54 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		logger.Error(err, "failed to update test status")
 		return ctrl.Result{Requeue: true}, err
 	}
+	// The latencies are only recorded once the status is updated, so they
+	// are not recorded again when the update conflicts.
+	r.SchedulingMetrics.Observe(schedulingLatencies, schedulingSLO)
+
+	if status.SoakDuration(test) > 0 {
+		// Keep the pods of the previous iteration, so their logs remain
+		// available while the next iteration runs.
+		for _, pod := range status.ExpiredSoakPods(test, pods.Items, 1) {
+			if err = r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+				logger.Error(err, "failed to delete pod from expired soak iteration", "pod", pod.Name)
+			}
+		}
+
+		if soakContinued {
+			logger.Info("soak iteration completed, starting next iteration", "soakIteration", test.Status.SoakIteration)
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	r.reapplyDriftedPods(ctx, defaults, test, ownedPods, logger)
 
 	missingPods := status.CheckMissingPods(test, ownedPods)
+	if !missingPods.IsEmpty() && len(ownedPods) == 0 && r.Drainer.IsDraining() {
+		// Tests that have started keep their missing pods, but new tests
+		// wait for a manager that does not drain.
+		logger.Info("not starting test while the controller drains")
+		return ctrl.Result{RequeueAfter: drainRequeueInterval}, nil
+	}
 	if !missingPods.IsEmpty() {
 		if !r.mgr.GetCache().WaitForCacheSync(ctx) {
 			logger.Error(errCacheSync, "could not invalidate the cache which is required to gang schedule")
 			return ctrl.Result{Requeue: true}, errCacheSync
 		}
 
+		// When node pools are declared, their capacity is read from the
+		// defaults, so there is no need to count the nodes in each pool.
 		nodes := new(corev1.NodeList)
-		if err = r.List(ctx, nodes); err != nil {
-			logger.Error(err, "failed to list nodes")
-			return ctrl.Result{Requeue: true}, err
+		if len(defaults.NodePools) == 0 {
+			if err = r.List(ctx, nodes, client.HasLabels{config.PoolLabel}); err != nil {
+				logger.Error(err, "failed to list nodes")
+				return ctrl.Result{Requeue: true}, err
+			}
 		}
 
 		// since we are attempting to schedule and have invalidated the cache,
 		// we need to reload the pods for any missed changes
 		pods = new(corev1.PodList)
-		if err = r.List(ctx, pods, client.InNamespace(req.Namespace)); err != nil {
+		if err = r.List(ctx, pods, client.InNamespace(req.Namespace), client.MatchingFields{ownerUIDIndexField: string(test.UID)}); err != nil {
 			logger.Error(err, "failed to list pods", "namespace", req.Namespace)
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -229,18 +506,32 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			goto setRequeueTime
 		}
 
+		// Pairing a driver with workers that do not speak its protocol fails
+		// in the middle of the run, so such tests fail before their pods are
+		// created instead.
+		if err = defaults.CheckCompatibility(test); err != nil {
+			logger.Info("images are not compatible, failing test", "error", err.Error())
+			test.Status.State = grpcv1.Errored
+			test.Status.Reason = grpcv1.IncompatibleImagesError
+			test.Status.Message = err.Error()
+			markStopped(test)
+			if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+				logger.Error(updateErr, "failed to update status after finding incompatible images")
+			}
+			return ctrl.Result{Requeue: false}, nil
+		}
+
 		var defaultClientPool string
 		var defaultDriverPool string
 		var defaultServerPool string
 		poolCapacities := make(map[string]int)
 		for _, node := range nodes.Items {
-			pool, ok := node.Labels[config.PoolLabel]
-			if !ok {
-				logger.Info("encountered a node without a pool label", "nodeName", node.Name)
+			pool := node.Labels[config.PoolLabel]
+			if !r.Defaults.PoolAllowedInNamespace(pool, req.Namespace) {
 				continue
 			}
 
-			if defaultPoolLabels := r.Defaults.DefaultPoolLabels; defaultPoolLabels != nil {
+			if defaultPoolLabels := defaults.DefaultPoolLabels; defaultPoolLabels != nil {
 				if defaultClientPool == "" {
 					if _, ok := node.Labels[defaultPoolLabels.Client]; ok {
 						defaultClientPool = pool
@@ -257,27 +548,20 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					}
 				}
 
-				if _, ok = poolCapacities[pool]; !ok {
+				if _, ok := poolCapacities[pool]; !ok {
 					poolCapacities[pool] = 0
 				}
 			}
 
 			poolCapacities[pool]++
 		}
-
-		poolAvailabilities := make(map[string]int)
-		for pool, capacity := range poolCapacities {
-			poolAvailabilities[pool] = capacity
-		}
-		for _, pod := range pods.Items {
-			pool, ok := pod.Labels[config.PoolLabel]
-			if !ok {
-				logger.Info("encountered a pod without a pool label", "pod", pod)
-				continue
-			}
-			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
-				poolAvailabilities[pool]--
+		if len(defaults.NodePools) > 0 {
+			for _, nodePool := range defaults.NodePools {
+				poolCapacities[nodePool.Name] = nodePool.Available()
 			}
+			defaultClientPool = defaults.DefaultNodePoolName(config.ClientRole)
+			defaultDriverPool = defaults.DefaultNodePoolName(config.DriverRole)
+			defaultServerPool = defaults.DefaultNodePoolName(config.ServerRole)
 		}
 
 		adjustAvailabilityForDefaults := func(defaultPoolKey, defaultPoolName string) bool {
@@ -287,6 +571,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					test.Status.State = grpcv1.Errored
 					test.Status.Reason = grpcv1.PoolError
 					test.Status.Message = fmt.Sprintf("default pool %q is not defined or does not existed in the cluster", defaultPoolKey)
+					markStopped(test)
 					if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 						logger.Error(updateErr, "failed to update status after failure due to requesting nodes from a nonexistent pool")
 					}
@@ -307,33 +592,164 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{Requeue: false}, nil
 		}
 
+		// Pods of tests with shared placement may be placed on the same
+		// node, so the number of missing pods does not count nodes and is
+		// not compared with the capacity of the pool.
+		sharedPlacement := test.Spec.PlacementPolicy == grpcv1.SharedPlacement
+
+		for pool := range missingPods.NodeCountByPool {
+			if r.Defaults.PoolAllowedInNamespace(pool, req.Namespace) {
+				continue
+			}
+			logger.Info("cannot schedule test: pool is not available to its namespace", "pool", pool)
+			test.Status.State = grpcv1.Errored
+			test.Status.Reason = grpcv1.PoolError
+			test.Status.Message = fmt.Sprintf("pool %q is not available to tests in namespace %q", pool, req.Namespace)
+			markStopped(test)
+			if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+				logger.Error(updateErr, "failed to update status after failure due to a pool of another namespace")
+			}
+			return ctrl.Result{Requeue: false}, nil
+		}
+
+		testKind := config.TestKind(test)
+		for pool, requiredNodeCount := range missingPods.NodeCountByPool {
+			nodePool := defaults.NodePoolForName(pool)
+			if nodePool == nil {
+				continue
+			}
+
+			var poolMessage string
+			if !nodePool.AllowsKind(testKind) {
+				poolMessage = fmt.Sprintf("pool %q does not allow tests of kind %q", pool, testKind)
+			} else if !sharedPlacement && requiredNodeCount > nodePool.Available() {
+				poolMessage = fmt.Sprintf("test requires %d nodes from pool %q, which has %d available", requiredNodeCount, pool, nodePool.Available())
+			}
+			if poolMessage != "" {
+				logger.Info("cannot schedule test: pool does not accept test", "pool", pool, "testKind", testKind, "requiredNodeCount", requiredNodeCount)
+				test.Status.State = grpcv1.Errored
+				test.Status.Reason = grpcv1.PoolError
+				test.Status.Message = poolMessage
+				markStopped(test)
+				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+					logger.Error(updateErr, "failed to update status after failure due to a pool that does not accept the test")
+				}
+				return ctrl.Result{Requeue: false}, nil
+			}
+		}
+
+		if defaults.SchedulingPolicy == config.FairShareSchedulingPolicy {
+			// Tenants with pools of their own do not compete with other
+			// namespaces, so they only take turns within their namespace.
+			var listOpts []client.ListOption
+			if tenant := r.Defaults.TenantForNamespace(req.Namespace); tenant != nil && len(tenant.Pools) > 0 {
+				listOpts = append(listOpts, client.InNamespace(req.Namespace))
+			}
+
+			allTests := new(grpcv1.LoadTestList)
+			if err = r.List(ctx, allTests, listOpts...); err != nil {
+				logger.Error(err, "failed to list tests")
+				return ctrl.Result{Requeue: true}, err
+			}
+
+			allPods := new(corev1.PodList)
+			if err = r.List(ctx, allPods, listOpts...); err != nil {
+				logger.Error(err, "failed to list pods")
+				return ctrl.Result{Requeue: true}, err
+			}
+
+			if !status.IsFairShareTurn(test, allTests.Items, allPods.Items) {
+				logger.Info("cannot schedule test: waiting for tests of other owners", "owner", status.OwnerForLoadTest(test))
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+		}
+
 		for pool, requiredNodeCount := range missingPods.NodeCountByPool {
-			availableNodeCount, ok := poolAvailabilities[pool]
+			capacity, ok := poolCapacities[pool]
 			if !ok {
 				logger.Error(errNonexistentPool, "requested pool does not exist and cannot be considered when scheduling", "requestedPool", pool)
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.PoolError
 				test.Status.Message = fmt.Sprintf("requested pool %q does not exist", pool)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logger.Error(updateErr, "failed to update status after failure due to requesting nodes from a nonexistent pool")
 				}
 				return ctrl.Result{Requeue: false}, nil
 			}
 
+			if sharedPlacement {
+				continue
+			}
+
+			// Only the pods that have not terminated occupy a node, so only
+			// those are counted against the capacity of the pool.
+			activePods := new(corev1.PodList)
+			if err = r.List(ctx, activePods, client.InNamespace(req.Namespace), client.MatchingFields{activePoolIndexField: pool}); err != nil {
+				logger.Error(err, "failed to list pods", "namespace", req.Namespace, "pool", pool)
+				return ctrl.Result{Requeue: true}, err
+			}
+			availableNodeCount := capacity - len(activePods.Items)
+
 			if requiredNodeCount > availableNodeCount {
 				logger.Info("cannot schedule test: inadequate availability for pool", "pool", pool, "requiredNodeCount", requiredNodeCount, "availableNodeCount", availableNodeCount)
 				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 			}
 		}
 
-		builder := podbuilder.New(r.Defaults, test)
+		if r.ImageChecker != nil {
+			for _, image := range imagesForMissingPods(missingPods) {
+				exists, checkErr := r.ImageChecker.Exists(ctx, image)
+				if checkErr != nil {
+					// The registry may be unreachable or require credentials
+					// the controller does not have, so allow the kubelet to
+					// make the final decision.
+					logger.Info("could not check if image exists, continuing", "image", image, "error", checkErr.Error())
+					continue
+				}
+				if !exists {
+					logger.Info("image does not exist, failing test", "image", image)
+					test.Status.State = grpcv1.Errored
+					test.Status.Reason = grpcv1.ImageNotFoundError
+					test.Status.Message = fmt.Sprintf("container image %q does not exist", image)
+					markStopped(test)
+					if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+						logger.Error(updateErr, "failed to update status after finding a nonexistent image")
+					}
+					return ctrl.Result{Requeue: false}, nil
+				}
+			}
+		}
+
+		// Tests with isolated placement taint the nodes of their pods, so
+		// that nothing else is placed on them while they run.
+		var isolatedNodes map[string][]string
+		if test.Spec.PlacementPolicy == grpcv1.IsolatedPlacement {
+			var reserved bool
+			isolatedNodes, reserved, err = r.reserveIsolatedNodes(ctx, test, missingPods.NodeCountByPool, status.PodsForLoadTest(test, pods.Items))
+			if err != nil {
+				logger.Error(err, "failed to reserve nodes for isolated test")
+				return ctrl.Result{Requeue: true}, err
+			}
+			if !reserved {
+				logger.Info("cannot schedule test: not enough free nodes to isolate test")
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+		}
+
+		builder := podbuilder.New(defaults, test)
 		createPod := func(pod *corev1.Pod) (*ctrl.Result, error) {
+			if nodes := isolatedNodes[pod.Labels[config.PoolLabel]]; len(nodes) > 0 {
+				pinToIsolatedNode(pod, test, nodes[0])
+				isolatedNodes[pod.Labels[config.PoolLabel]] = nodes[1:]
+			}
+
 			if err = ctrl.SetControllerReference(test, pod, r.Scheme); err != nil {
 				logger.Error(err, "could not set controller reference on pod, pod will not be garbage collected", "pod", pod)
 				return &ctrl.Result{Requeue: true}, err
 			}
 
-			if err = r.Create(ctx, pod); err != nil {
+			if err = r.apply(ctx, pod); err != nil {
 				logger.Error(err, "could not create new pod", "pod", pod)
 				return &ctrl.Result{Requeue: true}, err
 			}
@@ -350,6 +766,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.ConfigurationError
 				test.Status.Message = fmt.Sprintf("failed to construct a pod for server at index %d: %v", i, err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithServer.Error(updateErr, "failed to update status after failure to construct a pod for server")
 				}
@@ -368,6 +785,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.KubernetesError
 				test.Status.Message = fmt.Sprintf("failed to create pod for server at index %d: %v", i, err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithServer.Error(updateErr, "failed to update status after failure to create pod for server")
 				}
@@ -383,6 +801,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.ConfigurationError
 				test.Status.Message = fmt.Sprintf("failed to construct a pod for client at index %d: %v", i, err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithClient.Error(updateErr, "failed to update status after failure to construct a pod for client")
 				}
@@ -401,12 +820,48 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.KubernetesError
 				test.Status.Message = fmt.Sprintf("failed to create pod for client at index %d: %v", i, err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithClient.Error(updateErr, "failed to update status after failure to create pod for client")
 				}
 				return *result, err
 			}
 		}
+		for i := range missingPods.Generators {
+			logWithGenerator := logger.WithValues("generator", missingPods.Generators[i])
+
+			pod, err := builder.PodForGenerator(&missingPods.Generators[i])
+			if err != nil {
+				logWithGenerator.Error(err, "failed to construct a pod struct for supplied generator struct")
+				test.Status.State = grpcv1.Errored
+				test.Status.Reason = grpcv1.ConfigurationError
+				test.Status.Message = fmt.Sprintf("failed to construct a pod for generator at index %d: %v", i, err)
+				markStopped(test)
+				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+					logWithGenerator.Error(updateErr, "failed to update status after failure to construct a pod for generator")
+				}
+				return ctrl.Result{Requeue: false}, nil
+			}
+
+			if missingPods.Generators[i].Pool == nil {
+				pod.Labels[config.PoolLabel] = defaultClientPool
+			} else {
+				pod.Labels[config.PoolLabel] = *missingPods.Generators[i].Pool
+			}
+
+			result, err := createPod(pod)
+			if result != nil && !kerrors.IsAlreadyExists(err) {
+				logWithGenerator.Error(err, "failed to create pod for generator")
+				test.Status.State = grpcv1.Errored
+				test.Status.Reason = grpcv1.KubernetesError
+				test.Status.Message = fmt.Sprintf("failed to create pod for generator at index %d: %v", i, err)
+				markStopped(test)
+				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+					logWithGenerator.Error(updateErr, "failed to update status after failure to create pod for generator")
+				}
+				return *result, err
+			}
+		}
 		if missingPods.Driver != nil {
 			logWithDriver := logger.WithValues("driver", missingPods.Driver)
 
@@ -416,6 +871,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.ConfigurationError
 				test.Status.Message = fmt.Sprintf("failed to construct a pod for driver: %v", err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithDriver.Error(updateErr, "failed to update status after failure to construct a pod for driver")
 				}
@@ -434,6 +890,7 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				test.Status.State = grpcv1.Errored
 				test.Status.Reason = grpcv1.KubernetesError
 				test.Status.Message = fmt.Sprintf("failed to create pod for driver: %v", err)
+				markStopped(test)
 				if updateErr := r.Status().Update(ctx, test); updateErr != nil {
 					logWithDriver.Error(updateErr, "failed to update status after failure to create pod for driver")
 				}
@@ -451,6 +908,234 @@ setRequeueTime:
 	return ctrl.Result{Requeue: false}, nil
 }
 
+// markStopped records the stop time of a test that the controller terminates
+// with an error. Tests may fail before their pods are created, so the start
+// time is also recorded if it is missing, since the TTL of a terminated test
+// is measured from its start.
+func markStopped(test *grpcv1.LoadTest) {
+	if test.Status.StartTime == nil {
+		test.Status.StartTime = optional.CurrentTimePtr()
+	}
+	if test.Status.StopTime == nil {
+		test.Status.StopTime = optional.CurrentTimePtr()
+	}
+}
+
+// dryRun renders the pods of a load test to a ConfigMap instead of creating
+// them, and marks the test as succeeded. This allows the pods that the
+// controller would create to be inspected without running the test.
+func (r *LoadTestReconciler) dryRun(ctx context.Context, defaults *config.Defaults, test *grpcv1.LoadTest, logger logr.Logger) (ctrl.Result, error) {
+	if test.Status.StartTime == nil {
+		test.Status.StartTime = optional.CurrentTimePtr()
+	}
+
+	pods, err := podbuilder.New(defaults, test).RenderAll()
+	var rendered map[string]string
+	if err == nil {
+		rendered, err = podbuilder.RenderPods(pods)
+	}
+	if err != nil {
+		logger.Error(err, "failed to render pods for dry run")
+		test.Status.State = grpcv1.Errored
+		test.Status.Reason = grpcv1.ConfigurationError
+		test.Status.Message = fmt.Sprintf("failed to render pods for dry run: %v", err)
+		test.Status.StopTime = optional.CurrentTimePtr()
+		if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+			logger.Error(updateErr, "failed to update status after failure to render pods for dry run")
+		}
+		return ctrl.Result{Requeue: false}, nil
+	}
+
+	cfgMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.Name + config.DryRunConfigMapSuffix,
+			Namespace: test.Namespace,
+		},
+		Data: rendered,
+	}
+	if refError := ctrl.SetControllerReference(test, cfgMap, r.Scheme); refError != nil {
+		logger.Error(refError, "could not set controller reference on dry-run ConfigMap")
+		return ctrl.Result{Requeue: true}, refError
+	}
+	if applyErr := r.apply(ctx, cfgMap); applyErr != nil {
+		logger.Error(applyErr, "failed to apply dry-run ConfigMap")
+		return ctrl.Result{Requeue: true}, applyErr
+	}
+
+	test.Status.State = grpcv1.Succeeded
+	test.Status.Reason = grpcv1.DryRun
+	test.Status.Message = fmt.Sprintf("rendered %d pod(s) to ConfigMap %q", len(pods), cfgMap.Name)
+	test.Status.StopTime = optional.CurrentTimePtr()
+	if updateErr := r.Status().Update(ctx, test); updateErr != nil {
+		logger.Error(updateErr, "failed to update status after dry run")
+		return ctrl.Result{Requeue: true}, updateErr
+	}
+
+	return ctrl.Result{RequeueAfter: time.Duration(test.Spec.TTLSeconds) * time.Second}, nil
+}
+
+// apply creates or updates an object with a server-side apply request. The
+// controller owns the fields that it sets, so reapplying an object reverts
+// changes that others made to those fields.
+func (r *LoadTestReconciler) apply(ctx context.Context, obj client.Object) error {
+	if err := kubehelpers.PrepareForApply(obj, r.Scheme); err != nil {
+		return err
+	}
+	return r.Patch(ctx, obj, client.Apply, kubehelpers.ApplyPatchOptions()...)
+}
+
+// machineTypesForPods returns a map from the name of the node of each pod to
+// the machine type of the node. Nodes that cannot be fetched are omitted, so
+// their machine type is unknown.
+func (r *LoadTestReconciler) machineTypesForPods(ctx context.Context, pods []*corev1.Pod) map[string]string {
+	machineTypes := make(map[string]string)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		if _, ok := machineTypes[nodeName]; ok {
+			continue
+		}
+		node := new(corev1.Node)
+		if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			continue
+		}
+		machineTypes[nodeName] = node.Labels[corev1.LabelInstanceTypeStable]
+	}
+	return machineTypes
+}
+
+// reapplyDriftedPods restores the labels of the pods of a test when they no
+// longer match the labels the controller set when it created them. The
+// controller relies on these labels to match pods to the components of a test,
+// so pods with edited labels would otherwise be considered missing. Only the
+// labels and the owner reference are patched, since the spec of a pod cannot
+// be changed once it is created.
+func (r *LoadTestReconciler) reapplyDriftedPods(ctx context.Context, defaults *config.Defaults, test *grpcv1.LoadTest, ownedPods []*corev1.Pod, logger logr.Logger) {
+	existingPods := make(map[string]*corev1.Pod)
+	for _, pod := range ownedPods {
+		existingPods[pod.Name] = pod
+	}
+
+	reapply := func(pod *corev1.Pod, err error, pool *string) {
+		if err != nil {
+			return
+		}
+		existing, ok := existingPods[pod.Name]
+		if !ok {
+			return
+		}
+
+		// Pods in default pools are labeled with a pool that is resolved
+		// when they are scheduled, so keep the existing value.
+		if pool != nil {
+			pod.Labels[config.PoolLabel] = *pool
+		} else if existingPool, ok := existing.Labels[config.PoolLabel]; ok {
+			pod.Labels[config.PoolLabel] = existingPool
+		}
+		pod.Labels[config.ManagedByLabel] = config.ControllerName
+
+		if !kubehelpers.LabelsDrifted(pod.Labels, existing.Labels) {
+			return
+		}
+
+		logger.Info("pod labels have drifted, reapplying", "pod", pod.Name)
+		updated := existing.DeepCopy()
+		if updated.Labels == nil {
+			updated.Labels = make(map[string]string)
+		}
+		for key, value := range pod.Labels {
+			updated.Labels[key] = value
+		}
+		if err := ctrl.SetControllerReference(test, updated, r.Scheme); err != nil {
+			logger.Error(err, "could not set controller reference on pod", "pod", pod.Name)
+			return
+		}
+		if err := r.Patch(ctx, updated, client.MergeFrom(existing)); err != nil {
+			logger.Error(err, "failed to reapply pod labels", "pod", pod.Name)
+		}
+	}
+
+	builder := podbuilder.New(defaults, test)
+	if driver := test.Spec.Driver; driver != nil {
+		pod, err := builder.PodForDriver(driver)
+		reapply(pod, err, driver.Pool)
+	}
+	for i := range test.Spec.Servers {
+		server := &test.Spec.Servers[i]
+		pod, err := builder.PodForServer(server)
+		reapply(pod, err, server.Pool)
+	}
+	clients := kubehelpers.ExpandClients(test.Spec.Clients)
+	for i := range clients {
+		client := &clients[i]
+		pod, err := builder.PodForClient(client)
+		reapply(pod, err, client.Pool)
+	}
+	for i := range test.Spec.Generators {
+		generator := &test.Spec.Generators[i]
+		pod, err := builder.PodForGenerator(generator)
+		reapply(pod, err, generator.Pool)
+	}
+}
+
+// renderDashboardURL renders the dashboard URL of a terminated test from a
+// template.
+func renderDashboardURL(text string, test *grpcv1.LoadTest) (string, error) {
+	d, err := config.ParseDashboardURL(text)
+	if err != nil {
+		return "", err
+	}
+	return d.Render(test, time.Now())
+}
+
+// imagesForMissingPods returns the unique container images that are required
+// to create the missing pods, including the clone and build init containers.
+func imagesForMissingPods(missing *status.LoadTestMissing) []string {
+	var images []string
+	seen := make(map[string]bool)
+
+	add := func(clone *grpcv1.Clone, build *grpcv1.Build, run []corev1.Container) {
+		var candidates []string
+		if clone != nil && clone.Image != nil {
+			candidates = append(candidates, *clone.Image)
+		}
+		if build != nil && build.Image != nil {
+			candidates = append(candidates, *build.Image)
+		}
+		for _, container := range run {
+			candidates = append(candidates, container.Image)
+		}
+
+		for _, image := range candidates {
+			if image == "" || seen[image] {
+				continue
+			}
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	if driver := missing.Driver; driver != nil {
+		add(driver.Clone, driver.Build, driver.Run)
+	}
+	for i := range missing.Servers {
+		server := &missing.Servers[i]
+		add(server.Clone, server.Build, server.Run)
+	}
+	for i := range missing.Clients {
+		client := &missing.Clients[i]
+		add(client.Clone, client.Build, client.Run)
+	}
+	for i := range missing.Generators {
+		generator := &missing.Generators[i]
+		add(nil, nil, generator.Run)
+	}
+
+	return images
+}
+
 // getRequeueTime takes a LoadTest and its previous status, compares the
 // previous status of the load test with its updated status, and returns a
 // calculated requeue time. If the test has just been assigned a start time
@@ -480,9 +1165,14 @@ func getRequeueTime(updatedLoadTest *grpcv1.LoadTest, previousStatus grpcv1.Load
 // SetupWithManager configures a controller-runtime manager.
 func (r *LoadTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.mgr = mgr
+	if err := setupIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&grpcv1.LoadTest{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
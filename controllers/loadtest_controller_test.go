@@ -32,6 +32,7 @@ import (
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
 	"github.com/grpc/test-infra/podbuilder"
 	"github.com/grpc/test-infra/status"
 )
@@ -119,6 +120,82 @@ var _ = Describe("LoadTest controller", func() {
 		Consistently(getTestStatus).Should(Equal(test.Status))
 	})
 
+	It("does not fail on a terminated test without a start time", func() {
+		test.Status = grpcv1.LoadTestStatus{
+			State:  grpcv1.Errored,
+			Reason: grpcv1.ConfigurationError,
+		}
+		Expect(k8sClient.Create(context.Background(), test)).To(Succeed())
+		Expect(k8sClient.Status().Update(context.Background(), test)).To(Succeed())
+
+		getTestStatus := func() (grpcv1.LoadTestStatus, error) {
+			fetchedTest := new(grpcv1.LoadTest)
+			err := k8sClient.Get(context.Background(), namespacedName, fetchedTest)
+			if err != nil {
+				return grpcv1.LoadTestStatus{}, err
+			}
+			return fetchedTest.Status, nil
+		}
+
+		By("ensuring we can eventually get the created status")
+		Eventually(getTestStatus).Should(Equal(test.Status))
+
+		By("checking that the test is kept until its TTL expires")
+		Consistently(getTestStatus).Should(Equal(test.Status))
+	})
+
+	It("records the start and stop times of tests that error before starting", func() {
+		test.Annotations = map[string]string{
+			config.BenchmarkSecondsAnnotation: "invalid",
+		}
+		Expect(k8sClient.Create(context.Background(), test)).To(Succeed())
+
+		getTestStatus := func() (grpcv1.LoadTestStatus, error) {
+			fetchedTest := new(grpcv1.LoadTest)
+			err := k8sClient.Get(context.Background(), namespacedName, fetchedTest)
+			if err != nil {
+				return grpcv1.LoadTestStatus{}, err
+			}
+			return fetchedTest.Status, nil
+		}
+
+		Eventually(getTestStatus).Should(And(
+			HaveField("State", grpcv1.Errored),
+			HaveField("StartTime", Not(BeNil())),
+			HaveField("StopTime", Not(BeNil())),
+		))
+	})
+
+	It("fails tests that select an unknown result destination", func() {
+		test.Spec.Results = &grpcv1.Results{
+			Destination: optional.StringPtr("unknown-destination"),
+		}
+		Expect(k8sClient.Create(context.Background(), test)).To(Succeed())
+
+		getTestStatus := func() (grpcv1.LoadTestStatus, error) {
+			fetchedTest := new(grpcv1.LoadTest)
+			err := k8sClient.Get(context.Background(), namespacedName, fetchedTest)
+			if err != nil {
+				return grpcv1.LoadTestStatus{}, err
+			}
+			return fetchedTest.Status, nil
+		}
+
+		Eventually(getTestStatus).Should(And(
+			HaveField("State", grpcv1.Errored),
+			HaveField("Reason", grpcv1.FailedSettingDefaultsError),
+			HaveField("StartTime", Not(BeNil())),
+			HaveField("StopTime", Not(BeNil())),
+		))
+
+		By("checking that no pods were created for the test")
+		Consistently(func() (int, error) {
+			pods := new(corev1.PodList)
+			err := k8sClient.List(context.Background(), pods, client.InNamespace(test.Namespace), client.MatchingLabels{config.LoadTestLabel: test.Name})
+			return len(pods.Items), err
+		}).Should(Equal(0))
+	})
+
 	It("creates a scenarios ConfigMap", func() {
 		Expect(k8sClient.Create(context.Background(), test)).To(Succeed())
 
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/nodemetrics"
+)
+
+// nodeAgentReapplyInterval is the time between applications of the DaemonSet
+// of the node agent, which revert changes made to it by others and recreate it
+// if it was deleted.
+const nodeAgentReapplyInterval = 10 * time.Minute
+
+// NodeAgentInstaller creates and updates the DaemonSet of the node agent, which
+// samples system metrics of the nodes that run load tests, as configured by
+// the NodeAgent settings in the defaults.
+//
+// The installer is added to a manager as a runnable that requires leader
+// election, so that only the leader applies the DaemonSet. It applies the
+// DaemonSet when it starts and periodically afterwards, until its context is
+// cancelled. Failures are logged and retried, since node metrics are
+// informational and should not stop the manager.
+type NodeAgentInstaller struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Defaults *config.Defaults
+}
+
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;create;update;patch
+
+// Start applies the DaemonSet of the node agent until the context is
+// cancelled.
+func (n *NodeAgentInstaller) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("daemonset", config.NodeAgentName)
+
+	ticker := time.NewTicker(nodeAgentReapplyInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := n.apply(ctx); err != nil {
+			logger.Error(err, "failed to apply node agent")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply creates or updates the DaemonSet of the node agent with a server-side
+// apply request.
+func (n *NodeAgentInstaller) apply(ctx context.Context) error {
+	daemonSet := nodemetrics.DaemonSet(n.Defaults.ComponentNamespace, n.Defaults.NodeAgent)
+	if err := kubehelpers.PrepareForApply(daemonSet, n.Scheme); err != nil {
+		return err
+	}
+	return n.Patch(ctx, daemonSet, client.Apply, kubehelpers.ApplyPatchOptions()...)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/archive"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/status"
+)
+
+// ResultArchiveReconciler archives load tests once they terminate, by saving
+// the logs of their pods and notifying external systems of their final state,
+// as configured by the Archive field of the defaults.
+//
+// This work is slow and depends on external systems, so it is kept out of the
+// LoadTestReconciler, where it would delay the scheduling of other tests. The
+// ResultArchiveReconciler never changes the status of a test. Once a test has
+// been archived, the ArchivedAnnotation is set on it. Tests that fail to be
+// archived are retried with a backoff until they are archived or deleted.
+type ResultArchiveReconciler struct {
+	client.Client
+	Defaults *config.Defaults
+
+	// PodsGetter is used to get the logs of pods, which cannot be read
+	// through the controller-runtime client.
+	PodsGetter corev1types.PodsGetter
+
+	// MaxConcurrentArchives is the number of tests that may be archived at
+	// the same time. When zero, tests are archived one at a time.
+	MaxConcurrentArchives int
+}
+
+// +kubebuilder:rbac:groups=e2etest.grpc.io,resources=loadtests,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// Reconcile archives a terminated load test that has not been archived yet.
+func (r *ResultArchiveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("loadtest", req.NamespacedName)
+
+	test := new(grpcv1.LoadTest)
+	if err := r.Get(ctx, req.NamespacedName, test); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !needsArchive(test) {
+		return ctrl.Result{}, nil
+	}
+
+	defaults, err := r.Defaults.ForNamespace(req.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to get defaults for namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, nil
+	}
+	archivers := archive.ForConfig(defaults.Archive, r.PodsGetter)
+	if len(archivers) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	pods := new(corev1.PodList)
+	if err := r.List(ctx, pods, client.InNamespace(req.Namespace), client.MatchingFields{ownerUIDIndexField: string(test.UID)}); err != nil {
+		logger.Error(err, "failed to list pods of test")
+		return ctrl.Result{}, err
+	}
+	ownedPods := status.PodsForLoadTest(test, pods.Items)
+
+	archiveCtx, cancel := context.WithTimeout(ctx, defaults.Archive.Timeout())
+	defer cancel()
+	for _, archiver := range archivers {
+		if err := archiver.Archive(archiveCtx, test, ownedPods); err != nil {
+			logger.Error(err, "failed to archive test, will retry")
+			return ctrl.Result{}, fmt.Errorf("failed to archive test: %v", err)
+		}
+	}
+
+	// A patch only changes the annotation, so it does not conflict with
+	// updates to the test by the LoadTestReconciler.
+	patch := client.MergeFrom(test.DeepCopy())
+	if test.Annotations == nil {
+		test.Annotations = make(map[string]string)
+	}
+	test.Annotations[config.ArchivedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Patch(ctx, test, patch); err != nil {
+		logger.Error(err, "failed to mark test as archived")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logger.Info("archived test", "state", test.Status.State)
+	return ctrl.Result{}, nil
+}
+
+// needsArchive returns true if a test has terminated and has not been
+// archived. A test that was archived before its current run started, because
+// it was rerun, is archived again.
+func needsArchive(test *grpcv1.LoadTest) bool {
+	if !test.Status.State.IsTerminated() {
+		return false
+	}
+	value, archived := test.Annotations[config.ArchivedAnnotation]
+	if !archived {
+		return true
+	}
+	archivedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil || test.Status.Run == 0 || test.Status.StartTime == nil {
+		return false
+	}
+	return !archivedAt.After(test.Status.StartTime.Time)
+}
+
+// SetupWithManager configures a controller-runtime manager. The controller
+// only receives the tests that need to be archived. It relies on the field
+// index of pods registered by the LoadTestReconciler, so it must be set up
+// after the LoadTestReconciler.
+func (r *ResultArchiveReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("resultarchive").
+		For(&grpcv1.LoadTest{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			test, ok := obj.(*grpcv1.LoadTest)
+			return ok && needsArchive(test)
+		}))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentArchives}).
+		Complete(r)
+}
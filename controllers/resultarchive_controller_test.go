@@ -0,0 +1,63 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("needsArchive", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = newLoadTest()
+	})
+
+	It("returns false for a test that has not terminated", func() {
+		test.Status.State = grpcv1.Running
+		Expect(needsArchive(test)).To(BeFalse())
+	})
+
+	It("returns true for a terminated test", func() {
+		test.Status.State = grpcv1.Errored
+		Expect(needsArchive(test)).To(BeTrue())
+	})
+
+	It("returns false for a test that was archived", func() {
+		test.Status.State = grpcv1.Succeeded
+		test.Annotations = map[string]string{config.ArchivedAnnotation: "2022-01-01T00:00:00Z"}
+		Expect(needsArchive(test)).To(BeFalse())
+	})
+
+	It("returns true for a rerun test that was archived before its run started", func() {
+		test.Status.State = grpcv1.Succeeded
+		test.Status.Run = 1
+		test.Status.StartTime = &metav1.Time{Time: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)}
+		test.Annotations = map[string]string{config.ArchivedAnnotation: "2022-01-01T00:00:00Z"}
+		Expect(needsArchive(test)).To(BeTrue())
+
+		test.Annotations[config.ArchivedAnnotation] = "2022-01-03T00:00:00Z"
+		Expect(needsArchive(test)).To(BeFalse())
+	})
+})
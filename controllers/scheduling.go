@@ -0,0 +1,69 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SchedulingMetrics records the scheduling latency of load tests in each
+// pool, which is the time from the creation of a test until all of its pods in
+// the pool are running. A pool with latencies that keep growing, or that often
+// exceeds the scheduling SLO, is likely undersized.
+type SchedulingMetrics struct {
+	latency     *prometheus.HistogramVec
+	sloExceeded *prometheus.CounterVec
+}
+
+// NewSchedulingMetrics creates the metrics and registers them with the given
+// registerer, usually the metrics registry of controller-runtime.
+func NewSchedulingMetrics(registerer prometheus.Registerer) (*SchedulingMetrics, error) {
+	m := &SchedulingMetrics{
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "loadtest_scheduling_latency_seconds",
+			Help:    "Time from the creation of a load test until all of its pods in a pool were running.",
+			Buckets: prometheus.ExponentialBuckets(5, 2, 10),
+		}, []string{"pool"}),
+		sloExceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loadtest_scheduling_slo_exceeded_total",
+			Help: "Number of load tests whose pods in a pool took longer than the scheduling SLO to be running.",
+		}, []string{"pool"}),
+	}
+	for _, c := range []prometheus.Collector{m.latency, m.sloExceeded} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Observe records the scheduling latency of a load test in each pool. Pools
+// with a latency above the SLO are counted, unless the SLO is zero. It does
+// nothing if the metrics are nil.
+func (m *SchedulingMetrics) Observe(latencies map[string]time.Duration, slo time.Duration) {
+	if m == nil {
+		return
+	}
+	for pool, latency := range latencies {
+		m.latency.WithLabelValues(pool).Observe(latency.Seconds())
+		if slo > 0 && latency > slo {
+			m.sloExceeded.WithLabelValues(pool).Inc()
+		}
+	}
+}
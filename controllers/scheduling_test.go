@@ -0,0 +1,67 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("SchedulingMetrics", func() {
+	var registry *prometheus.Registry
+	var metrics *SchedulingMetrics
+
+	BeforeEach(func() {
+		registry = prometheus.NewRegistry()
+		var err error
+		metrics, err = NewSchedulingMetrics(registry)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("records the latency of each pool", func() {
+		metrics.Observe(map[string]time.Duration{
+			"drivers": 10 * time.Second,
+			"workers": 2 * time.Minute,
+		}, 0)
+		metrics.Observe(map[string]time.Duration{"workers": time.Minute}, 0)
+
+		Expect(testutil.CollectAndCount(metrics.latency)).To(Equal(2))
+		Expect(testutil.CollectAndCount(metrics.sloExceeded)).To(Equal(0))
+	})
+
+	It("counts the pools that exceed the SLO", func() {
+		metrics.Observe(map[string]time.Duration{
+			"drivers": 10 * time.Second,
+			"workers": 2 * time.Minute,
+		}, time.Minute)
+
+		Expect(testutil.ToFloat64(metrics.sloExceeded.WithLabelValues("workers"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.sloExceeded.WithLabelValues("drivers"))).To(Equal(0.0))
+	})
+
+	It("does nothing when the metrics are nil", func() {
+		var nilMetrics *SchedulingMetrics
+		Expect(func() {
+			nilMetrics.Observe(map[string]time.Duration{"workers": time.Minute}, 0)
+		}).ToNot(Panic())
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/status"
+)
+
+// SummaryPath is the path of the endpoint that serves the summary of the load
+// tests on the metrics server of the manager.
+const SummaryPath = "/summary"
+
+// SummaryHandler serves a JSON summary of the load tests in the cluster, with
+// the number of tests in each state, the age of the oldest pending test and
+// the usage of each pool. The summary is computed from the cache of the
+// manager, so dashboards and tools can poll it without listing tests and pods
+// from the API server.
+type SummaryHandler struct {
+	// Reader reads the load tests, pods and nodes, usually from the cache
+	// of the manager.
+	Reader client.Reader
+
+	// Defaults contains the node pools, whose capacity is used instead of
+	// the number of nodes in each pool when they are declared.
+	Defaults *config.Defaults
+}
+
+// Summarize returns the summary of the load tests in the cluster.
+func (h *SummaryHandler) Summarize(ctx context.Context) (*status.ClusterSummary, error) {
+	tests := new(grpcv1.LoadTestList)
+	if err := h.Reader.List(ctx, tests); err != nil {
+		return nil, err
+	}
+	pods := new(corev1.PodList)
+	if err := h.Reader.List(ctx, pods, client.HasLabels{config.PoolLabel}); err != nil {
+		return nil, err
+	}
+
+	capacities := make(map[string]int)
+	if len(h.Defaults.NodePools) > 0 {
+		for _, nodePool := range h.Defaults.NodePools {
+			capacities[nodePool.Name] = nodePool.Available()
+		}
+	} else {
+		nodes := new(corev1.NodeList)
+		if err := h.Reader.List(ctx, nodes, client.HasLabels{config.PoolLabel}); err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			capacities[node.Labels[config.PoolLabel]]++
+		}
+	}
+
+	return status.SummarizeCluster(tests.Items, pods.Items, capacities, time.Now()), nil
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *SummaryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.Summarize(r.Context())
+	if err != nil {
+		log.FromContext(r.Context()).Error(err, "failed to summarize load tests")
+		http.Error(w, "failed to summarize load tests", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		log.FromContext(r.Context()).Error(err, "failed to write summary of load tests")
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/status"
+)
+
+var _ = Describe("SummaryHandler", func() {
+	var test *grpcv1.LoadTest
+	var nodes []*corev1.Node
+
+	BeforeEach(func() {
+		test = newLoadTest()
+		test.UID = "summary-test"
+		test.Status.State = grpcv1.Running
+		nodes = nil
+		for _, name := range []string{"node-1", "node-2"} {
+			nodes = append(nodes, &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{config.PoolLabel: "workers"},
+				},
+			})
+		}
+	})
+
+	serve := func(handler *SummaryHandler) *status.ClusterSummary {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SummaryPath, nil))
+		ExpectWithOffset(1, recorder.Code).To(Equal(http.StatusOK))
+		ExpectWithOffset(1, recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		summary := new(status.ClusterSummary)
+		ExpectWithOffset(1, json.Unmarshal(recorder.Body.Bytes(), summary)).To(Succeed())
+		return summary
+	}
+
+	It("counts the nodes of each pool when no pools are declared", func() {
+		pod := newIndexedPod(test, "server-0", "workers")
+		reader := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(test, pod, nodes[0], nodes[1]).
+			Build()
+
+		summary := serve(&SummaryHandler{Reader: reader, Defaults: newDefaults()})
+		Expect(summary.Tests).To(Equal(1))
+		Expect(summary.States).To(HaveKeyWithValue(grpcv1.Running, 1))
+		Expect(summary.Pools).To(HaveKey("workers"))
+		Expect(*summary.Pools["workers"].Capacity).To(Equal(2))
+		Expect(summary.Pools["workers"].ActivePods).To(Equal(1))
+		Expect(summary.Pools["workers"].ActiveTests).To(Equal(1))
+	})
+
+	It("uses the capacity of declared pools", func() {
+		defaults := newDefaults()
+		defaults.NodePools = []config.NodePool{{Name: "workers", Capacity: 10, Reserved: 2}}
+		reader := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(nodes[0]).
+			Build()
+
+		summary := serve(&SummaryHandler{Reader: reader, Defaults: defaults})
+		Expect(summary.Tests).To(BeZero())
+		Expect(*summary.Pools["workers"].Capacity).To(Equal(8))
+	})
+})
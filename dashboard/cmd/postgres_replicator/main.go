@@ -9,12 +9,14 @@ import (
 	"os"
 
 	pgr "github.com/grpc/test-infra/dashboard/postgres_replicator"
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/grpc/test-infra/logging"
 )
 
 func main() {
 	var c string
 	flag.StringVar(&c, "c", "", "filepath to config")
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	if c == "" {
@@ -22,21 +24,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Error setting up logging: %v", err)
+	}
+	defer logger.Sync()
+
 	config, err := pgr.NewConfig(c)
 	if err != nil {
 		log.Fatalf("Error getting config: %s", err)
 	}
 
 	var (
-		postgresConfig = config.Postgres
-		bigqueryConfig = config.BigQuery
-		transferConfig = config.Transfer
+		postgresConfig  = config.Postgres
+		bigqueryConfig  = config.BigQuery
+		transferConfig  = config.Transfer
+		retentionConfig = config.Retention
 	)
 
 	pgdb, err := pgr.NewPostgresClient(postgresConfig)
 	if err != nil {
 		log.Fatalf("Error initializing PostgreSQL client: %v", err)
 	}
+	defer pgdb.Close()
 	log.Println("Initialized PostgreSQL client")
 
 	bqdb, err := pgr.NewBigQueryClient(context.Background(), bigqueryConfig)
@@ -45,14 +55,16 @@ func main() {
 	}
 	log.Println("Initialized BigQuery client")
 
-	dbTransfer := pgr.NewTransfer(bqdb, pgdb, &transferConfig)
+	dbTransfer := pgr.NewTransfer(bqdb, pgdb, &transferConfig, &retentionConfig)
+	pruner := pgr.NewPruner(bqdb, pgdb, &transferConfig, &retentionConfig, dbTransfer.Metrics())
 	finished := make(chan bool)
-	go serveHTTP(dbTransfer, finished)
+	resultsHandler := pgr.NewResultsHandler(pgdb, &transferConfig)
+	go serveHTTP(dbTransfer, pruner, resultsHandler, finished)
 
 	<-finished
 }
 
-func serveHTTP(dbTransfer *pgr.Transfer, finished chan bool) {
+func serveHTTP(dbTransfer *pgr.Transfer, pruner *pgr.Pruner, resultsHandler http.Handler, finished chan bool) {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -66,6 +78,12 @@ func serveHTTP(dbTransfer *pgr.Transfer, finished chan bool) {
 		fmt.Fprintf(w, "Request received")
 		go dbTransfer.Run()
 	})
+	http.HandleFunc("/prune", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Request received")
+		go pruner.Run()
+	})
+	http.Handle("/api/results", resultsHandler)
+	http.Handle("/metrics", dbTransfer.MetricsHandler())
 	http.HandleFunc("/kill", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, "Server killed")
 		finished <- true
@@ -55,12 +55,17 @@ func (bqc *BigQueryClient) ListTables() error {
 // Golang's client library for BigQuery supports automatic paging, meaning that
 // this function can be called without worrying about how much data is being
 // returned. See https://cloud.google.com/bigquery/docs/paging-results.
-func (bqc *BigQueryClient) GetDataAfterDatetime(dataset, table, dateField, datetime string, bqSchema *BigQuerySchema) (*bigquery.RowIterator, error) {
+// When the table is partitioned, the partition field limits the query to the
+// partitions that may hold rows after the datetime.
+func (bqc *BigQueryClient) GetDataAfterDatetime(dataset, table, dateField, partitionField, datetime string, bqSchema *BigQuerySchema) (*bigquery.RowIterator, error) {
 	sqlf.SetDialect(sqlf.PostgreSQL)
 	sqlBuilder := sqlf.New("SELECT").From(fmt.Sprintf("%s.%s", dataset, table))
 	if datetime != "" {
 		sqlBuilder.Where(fmt.Sprintf("%s > '%s'", dateField, datetime))
 	}
+	if partitionField != "" {
+		sqlBuilder.Where(bigQueryPartitionCondition(partitionField, datetime))
+	}
 	for columnName, dataType := range bqSchema.schema {
 		if strings.Contains(dataType, "STRUCT") {
 			sqlBuilder.Select(fmt.Sprintf("TO_JSON_STRING(%s) AS %s", columnName, columnName))
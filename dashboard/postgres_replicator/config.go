@@ -2,8 +2,9 @@ package transfer
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -38,7 +39,7 @@ func overwriteEnvVars(conf *YAMLConfig) {
 }
 
 func readYAML(yamlFile string) (*YAMLConfig, error) {
-	file, err := ioutil.ReadFile(yamlFile)
+	file, err := os.ReadFile(yamlFile)
 	if err != nil {
 		return nil, err
 	}
@@ -68,14 +69,42 @@ func validateYAMLConfig(yConfig *YAMLConfig) error {
 			tableSet[table.Name] = true
 		}
 	}
+
+	postgres := yConfig.Postgres
+	if postgres.MaxConns < 0 {
+		return fmt.Errorf("postgres maxConns must not be negative: %d", postgres.MaxConns)
+	}
+	if postgres.QueryTimeout < 0 {
+		return fmt.Errorf("postgres queryTimeout must not be negative: %s", postgres.QueryTimeout)
+	}
+	if postgres.TransactionTimeout < 0 {
+		return fmt.Errorf("postgres transactionTimeout must not be negative: %s", postgres.TransactionTimeout)
+	}
+	if postgres.SerializationRetries < 0 {
+		return fmt.Errorf("postgres serializationRetries must not be negative: %d", postgres.SerializationRetries)
+	}
+
+	retention := yConfig.Retention
+	if retention.MaxAgeDays < 0 {
+		return fmt.Errorf("retention maxAgeDays must not be negative: %d", retention.MaxAgeDays)
+	}
+	if retention.ArchiveURI != "" {
+		if retention.MaxAgeDays == 0 {
+			return fmt.Errorf("retention archiveURI requires maxAgeDays to be set")
+		}
+		if !strings.HasPrefix(retention.ArchiveURI, "gs://") {
+			return fmt.Errorf("retention archiveURI must be a gs:// URI: %s", retention.ArchiveURI)
+		}
+	}
 	return nil
 }
 
 // YAMLConfig stores the configuration of the application.
 type YAMLConfig struct {
-	BigQuery BigQueryConfig `yaml:"bigQuery"`
-	Postgres PostgresConfig `yaml:"postgres"`
-	Transfer TableConfig    `yaml:"transfer"`
+	BigQuery  BigQueryConfig  `yaml:"bigQuery"`
+	Postgres  PostgresConfig  `yaml:"postgres"`
+	Transfer  TableConfig     `yaml:"transfer"`
+	Retention RetentionConfig `yaml:"retention"`
 }
 
 // BigQueryConfig stores configuration needed to connect to the BigQuery
@@ -92,6 +121,17 @@ type PostgresConfig struct {
 	DbUser string `yaml:"dbUser"`
 	DbPass string `yaml:"dbPass"`
 	DbName string `yaml:"dbName"`
+	// MaxConns is the maximum number of connections in the pool.
+	MaxConns int32 `yaml:"maxConns"`
+	// QueryTimeout limits the duration of each query made outside of the
+	// transaction of a transfer.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+	// TransactionTimeout limits the duration of the transaction of a
+	// transfer, including its retries.
+	TransactionTimeout time.Duration `yaml:"transactionTimeout"`
+	// SerializationRetries is the number of times the transaction of a
+	// transfer is retried after a serialization failure or deadlock.
+	SerializationRetries int `yaml:"serializationRetries"`
 }
 
 // TableConfig stores configuration about which BigQuery datasets and tables
@@ -102,6 +142,23 @@ type TableConfig struct {
 		Tables []struct {
 			Name      string `yaml:"name"`
 			DateField string `yaml:"dateField"`
+			// PartitionField is the TIMESTAMP column a BigQuery table is
+			// partitioned by. When set, queries of the table filter on it
+			// so that they only scan the partitions they need.
+			PartitionField string `yaml:"partitionField"`
 		} `yaml:"tables"`
 	} `yaml:"datasets"`
 }
+
+// RetentionConfig stores configuration about how long results are kept.
+// Results are only pruned when MaxAgeDays is set.
+type RetentionConfig struct {
+	// MaxAgeDays is the number of days that rows are kept in PostgreSQL.
+	MaxAgeDays int `yaml:"maxAgeDays"`
+	// DryRun counts the rows that would be pruned, without deleting them.
+	DryRun bool `yaml:"dryRun"`
+	// ArchiveURI is a Cloud Storage prefix, such as gs://bucket/archive.
+	// When set, rows older than MaxAgeDays are also exported from BigQuery
+	// to this prefix and then deleted from BigQuery.
+	ArchiveURI string `yaml:"archiveURI"`
+}
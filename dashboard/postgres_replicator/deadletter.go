@@ -0,0 +1,114 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/jackc/pgx/v5"
+)
+
+// DeadLetterTable is the name of the Postgres table that stores rows that
+// could not be transferred, together with the error. Rows in this table are
+// retried on each run, and removed once they are transferred. Rows that reach
+// MaxDeadLetterAttempts are kept in the table for inspection, but are no
+// longer retried.
+const DeadLetterTable = "replicator_dead_letters"
+
+// MaxDeadLetterAttempts is the number of attempts to transfer a row, including
+// the first, after which it is no longer retried.
+const MaxDeadLetterAttempts = 10
+
+// deadLetter is a row that could not be transferred.
+type deadLetter struct {
+	id       int64
+	rowData  string
+	attempts int
+}
+
+// CreateDeadLetterTable creates the dead-letter table, if it does not exist.
+func (pc *PostgresClient) CreateDeadLetterTable() error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+		id BIGSERIAL PRIMARY KEY,
+		table_name TEXT NOT NULL,
+		row_data TEXT NOT NULL,
+		error TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 1,
+		created TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated TIMESTAMPTZ NOT NULL DEFAULT now()
+	);`, DeadLetterTable)
+
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	_, err := pc.Exec(ctx, query)
+	return err
+}
+
+// addDeadLetter adds the raw contents of a row and the error that prevented
+// its transfer to the dead-letter table.
+func addDeadLetter(ctx context.Context, tx pgx.Tx, tableName string, row map[string]bigquery.Value, rowErr error) error {
+	rowData, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("could not encode row: %v", err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO "%s" (table_name, row_data, error) VALUES ($1, $2, $3);`, DeadLetterTable)
+	_, err = tx.Exec(ctx, query, tableName, string(rowData), rowErr.Error())
+	return err
+}
+
+// getDeadLetters returns the rows in the dead-letter table for a table that
+// have not reached MaxDeadLetterAttempts, in the order they were added.
+func getDeadLetters(ctx context.Context, tx pgx.Tx, tableName string) ([]deadLetter, error) {
+	query := fmt.Sprintf(`SELECT id, row_data, attempts FROM "%s" WHERE table_name = $1 AND attempts < $2 ORDER BY id;`, DeadLetterTable)
+	rows, err := tx.Query(ctx, query, tableName, MaxDeadLetterAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []deadLetter
+	for rows.Next() {
+		var dl deadLetter
+		if err := rows.Scan(&dl.id, &dl.rowData, &dl.attempts); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, dl)
+	}
+	return deadLetters, rows.Err()
+}
+
+// retryDeadLetter removes a row from the dead-letter table after it has been
+// transferred, or records another failed attempt.
+func retryDeadLetter(ctx context.Context, tx pgx.Tx, dl deadLetter, rowErr error) error {
+	if rowErr == nil {
+		query := fmt.Sprintf(`DELETE FROM "%s" WHERE id = $1;`, DeadLetterTable)
+		_, err := tx.Exec(ctx, query, dl.id)
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE "%s" SET error = $1, attempts = attempts + 1, updated = now() WHERE id = $2;`, DeadLetterTable)
+	_, err := tx.Exec(ctx, query, rowErr.Error(), dl.id)
+	return err
+}
+
+// exhausted returns true if a failed retry of a row in the dead-letter table
+// was its last attempt.
+func (dl deadLetter) exhausted() bool {
+	return dl.attempts+1 >= MaxDeadLetterAttempts
+}
+
+// decodeDeadLetterRow decodes the raw contents of a row in the dead-letter
+// table. Numbers are kept as json.Number to preserve their precision.
+func decodeDeadLetterRow(rowData string) (map[string]bigquery.Value, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(rowData))
+	decoder.UseNumber()
+
+	var row map[string]bigquery.Value
+	if err := decoder.Decode(&row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
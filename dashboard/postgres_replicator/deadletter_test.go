@@ -0,0 +1,76 @@
+package transfer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeDeadLetterRow(t *testing.T) {
+	created := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	row := map[string]bigquery.Value{
+		"created": created,
+		"qps":     1234567890.5,
+		"scenario": map[string]bigquery.Value{
+			"name": "cpp_unary",
+		},
+	}
+	rowData, err := json.Marshal(row)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	got, err := decodeDeadLetterRow(string(rowData))
+	if err != nil {
+		t.Fatalf("decodeDeadLetterRow() error: %v", err)
+	}
+	want := map[string]bigquery.Value{
+		"created": created.Format(time.RFC3339),
+		"qps":     json.Number("1234567890.5"),
+		"scenario": map[string]interface{}{
+			"name": "cpp_unary",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("decodeDeadLetterRow() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDecodeDeadLetterRowInvalid(t *testing.T) {
+	if _, err := decodeDeadLetterRow("{not json"); err == nil {
+		t.Errorf("decodeDeadLetterRow() returned no error for invalid JSON")
+	}
+}
+
+func TestPrepareInsertSQLFormattedTimestamp(t *testing.T) {
+	pgSchema := &PostgresSchema{map[string]string{"created": "TIMESTAMPTZ"}}
+	row := map[string]bigquery.Value{"created": "2022-03-01T12:00:00Z"}
+
+	template, args, err := prepareInsertSQL("results", pgSchema, row)
+	if err != nil {
+		t.Fatalf("prepareInsertSQL() error: %v", err)
+	}
+	if !strings.HasPrefix(template, "INSERT INTO results") {
+		t.Errorf("prepareInsertSQL() template = %q, want an insert into results", template)
+	}
+	if diff := cmp.Diff([]interface{}{"2022-03-01T12:00:00Z"}, args); diff != "" {
+		t.Errorf("prepareInsertSQL() args diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeadLetterExhausted(t *testing.T) {
+	for attempts, want := range map[int]bool{
+		1:                         false,
+		MaxDeadLetterAttempts - 2: false,
+		MaxDeadLetterAttempts - 1: true,
+	} {
+		dl := deadLetter{attempts: attempts}
+		if got := dl.exhausted(); got != want {
+			t.Errorf("deadLetter{attempts: %d}.exhausted() = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package transfer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics counts the rows handled by transfers and pruning, labeled by table,
+// so the replicator can be monitored.
+type Metrics struct {
+	registry     *prometheus.Registry
+	transferred  *prometheus.CounterVec
+	failed       *prometheus.CounterVec
+	deadLettered *prometheus.CounterVec
+	abandoned    *prometheus.CounterVec
+	expired      *prometheus.GaugeVec
+	pruned       *prometheus.CounterVec
+}
+
+// NewMetrics creates a new Metrics with its own registry.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		transferred: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "replicator_rows_transferred_total",
+			Help: "Number of rows transferred to Postgres, including retried rows.",
+		}, []string{"table"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "replicator_rows_failed_total",
+			Help: "Number of attempts to transfer a row that failed, including retries.",
+		}, []string{"table"}),
+		deadLettered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "replicator_rows_deadlettered_total",
+			Help: "Number of rows added to the dead-letter table.",
+		}, []string{"table"}),
+		abandoned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "replicator_rows_abandoned_total",
+			Help: "Number of dead-letter rows that are no longer retried because they reached the maximum number of attempts.",
+		}, []string{"table"}),
+		expired: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "replicator_rows_expired",
+			Help: "Number of rows older than the retention period found by the last prune, including dry runs.",
+		}, []string{"table", "database"}),
+		pruned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "replicator_rows_pruned_total",
+			Help: "Number of rows deleted because they were older than the retention period.",
+		}, []string{"table", "database"}),
+	}
+	m.registry.MustRegister(m.transferred, m.failed, m.deadLettered, m.abandoned, m.expired, m.pruned)
+	return m
+}
+
+// Handler returns an HTTP handler that serves the metrics in the Prometheus
+// text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// transferStats counts the rows handled by a single transfer. The counts are
+// added to the metrics only when the transfer is committed.
+type transferStats struct {
+	transferred  int
+	failed       int
+	deadLettered int
+	abandoned    int
+}
+
+// add adds the counts of a committed transfer for a table.
+func (m *Metrics) add(table string, stats *transferStats) {
+	m.transferred.WithLabelValues(table).Add(float64(stats.transferred))
+	m.failed.WithLabelValues(table).Add(float64(stats.failed))
+	m.deadLettered.WithLabelValues(table).Add(float64(stats.deadLettered))
+	m.abandoned.WithLabelValues(table).Add(float64(stats.abandoned))
+}
+
+// addPruned records the rows of a table found to be older than the retention
+// period in a database, and the rows that were deleted.
+func (m *Metrics) addPruned(table, database string, expired, pruned int64) {
+	m.expired.WithLabelValues(table, database).Set(float64(expired))
+	m.pruned.WithLabelValues(table, database).Add(float64(pruned))
+}
+
+// poolStat contains the statistics of a connection pool. It is implemented by
+// pgxpool.Stat.
+type poolStat interface {
+	AcquiredConns() int32
+	IdleConns() int32
+	TotalConns() int32
+	MaxConns() int32
+	AcquireCount() int64
+	AcquireDuration() time.Duration
+	EmptyAcquireCount() int64
+	CanceledAcquireCount() int64
+}
+
+// poolCollector exports the statistics of a connection pool, which are read
+// each time the metrics are collected.
+type poolCollector struct {
+	stat func() poolStat
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+// newPoolCollector creates a collector for the statistics returned by stat.
+func newPoolCollector(stat func() poolStat) *poolCollector {
+	return &poolCollector{
+		stat:                 stat,
+		acquiredConns:        prometheus.NewDesc("replicator_pool_acquired_conns", "Number of connections currently in use.", nil, nil),
+		idleConns:            prometheus.NewDesc("replicator_pool_idle_conns", "Number of idle connections in the pool.", nil, nil),
+		totalConns:           prometheus.NewDesc("replicator_pool_total_conns", "Number of connections in the pool, including those being opened.", nil, nil),
+		maxConns:             prometheus.NewDesc("replicator_pool_max_conns", "Maximum number of connections in the pool.", nil, nil),
+		acquireCount:         prometheus.NewDesc("replicator_pool_acquires_total", "Number of connections acquired from the pool.", nil, nil),
+		acquireDuration:      prometheus.NewDesc("replicator_pool_acquire_seconds_total", "Total time spent waiting to acquire connections.", nil, nil),
+		emptyAcquireCount:    prometheus.NewDesc("replicator_pool_empty_acquires_total", "Number of acquires that waited because the pool had no idle connection.", nil, nil),
+		canceledAcquireCount: prometheus.NewDesc("replicator_pool_canceled_acquires_total", "Number of acquires cancelled by their context.", nil, nil),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+// addPool exports the statistics of the connection pool of a client.
+func (m *Metrics) addPool(pc *PostgresClient) {
+	m.registry.MustRegister(newPoolCollector(func() poolStat {
+		return pc.Stat()
+	}))
+}
@@ -0,0 +1,58 @@
+package transfer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakePoolStat struct{}
+
+func (fakePoolStat) AcquiredConns() int32           { return 2 }
+func (fakePoolStat) IdleConns() int32               { return 1 }
+func (fakePoolStat) TotalConns() int32              { return 3 }
+func (fakePoolStat) MaxConns() int32                { return 4 }
+func (fakePoolStat) AcquireCount() int64            { return 10 }
+func (fakePoolStat) AcquireDuration() time.Duration { return 1500 * time.Millisecond }
+func (fakePoolStat) EmptyAcquireCount() int64       { return 5 }
+func (fakePoolStat) CanceledAcquireCount() int64    { return 1 }
+
+func TestPoolCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newPoolCollector(func() poolStat {
+		return fakePoolStat{}
+	}))
+
+	want := `
+# HELP replicator_pool_acquire_seconds_total Total time spent waiting to acquire connections.
+# TYPE replicator_pool_acquire_seconds_total counter
+replicator_pool_acquire_seconds_total 1.5
+# HELP replicator_pool_acquired_conns Number of connections currently in use.
+# TYPE replicator_pool_acquired_conns gauge
+replicator_pool_acquired_conns 2
+# HELP replicator_pool_acquires_total Number of connections acquired from the pool.
+# TYPE replicator_pool_acquires_total counter
+replicator_pool_acquires_total 10
+# HELP replicator_pool_canceled_acquires_total Number of acquires cancelled by their context.
+# TYPE replicator_pool_canceled_acquires_total counter
+replicator_pool_canceled_acquires_total 1
+# HELP replicator_pool_empty_acquires_total Number of acquires that waited because the pool had no idle connection.
+# TYPE replicator_pool_empty_acquires_total counter
+replicator_pool_empty_acquires_total 5
+# HELP replicator_pool_idle_conns Number of idle connections in the pool.
+# TYPE replicator_pool_idle_conns gauge
+replicator_pool_idle_conns 1
+# HELP replicator_pool_max_conns Maximum number of connections in the pool.
+# TYPE replicator_pool_max_conns gauge
+replicator_pool_max_conns 4
+# HELP replicator_pool_total_conns Number of connections in the pool, including those being opened.
+# TYPE replicator_pool_total_conns gauge
+replicator_pool_total_conns 3
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want)); err != nil {
+		t.Errorf("GatherAndCompare() error: %v", err)
+	}
+}
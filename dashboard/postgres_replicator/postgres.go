@@ -2,18 +2,45 @@ package transfer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Defaults for the connection pool and timeouts, used when they are not set
+// in the configuration.
+const (
+	defaultMaxConns             = 4
+	defaultQueryTimeout         = 30 * time.Second
+	defaultTransactionTimeout   = 10 * time.Minute
+	defaultSerializationRetries = 3
+)
+
+// serializationRetryBackoff is the delay before the first retry of a
+// transaction after a serialization failure. It doubles after each retry.
+const serializationRetryBackoff = 500 * time.Millisecond
+
+// Error codes of PostgreSQL for transactions that are aborted because of
+// concurrent transactions. These transactions succeed when they are retried.
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
 )
 
 // PostgresClient interacts with an instance of PostgreSQL.
 type PostgresClient struct {
 	ctx context.Context
 	*pgxpool.Pool
+	queryTimeout         time.Duration
+	transactionTimeout   time.Duration
+	serializationRetries int
 }
 
 // PostgresSchema is a map of column names to Postgres datatypes.
@@ -39,22 +66,90 @@ func NewPostgresClient(config PostgresConfig) (*PostgresClient, error) {
 		dbURI = fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", user, pass, host, port, name)
 	}
 
+	poolConfig, err := pgxpool.ParseConfig(dbURI)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse connection string: %v", err)
+	}
+	poolConfig.MaxConns = defaultMaxConns
+	if config.MaxConns > 0 {
+		poolConfig.MaxConns = config.MaxConns
+	}
+
 	ctx := context.Background()
-	dbPool, err := pgxpool.Connect(ctx, dbURI)
+	dbPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
-		return nil, fmt.Errorf("sql.Open: %v", err)
+		return nil, fmt.Errorf("could not create connection pool: %v", err)
+	}
+
+	pc := &PostgresClient{
+		ctx:                  ctx,
+		Pool:                 dbPool,
+		queryTimeout:         defaultQueryTimeout,
+		transactionTimeout:   defaultTransactionTimeout,
+		serializationRetries: defaultSerializationRetries,
+	}
+	if config.QueryTimeout > 0 {
+		pc.queryTimeout = config.QueryTimeout
+	}
+	if config.TransactionTimeout > 0 {
+		pc.transactionTimeout = config.TransactionTimeout
+	}
+	if config.SerializationRetries > 0 {
+		pc.serializationRetries = config.SerializationRetries
 	}
 
-	pc := &PostgresClient{ctx, dbPool}
 	err = pc.testConnection()
 	if err != nil {
+		dbPool.Close()
 		return nil, fmt.Errorf("error testing connection: %v", err)
 	}
 	return pc, nil
 }
 
+// queryContext returns a context that limits the duration of a single query.
+func (pc *PostgresClient) queryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(pc.ctx, pc.queryTimeout)
+}
+
+// inTransaction runs a function in a transaction, which is committed if the
+// function succeeds and rolled back otherwise. The transaction is retried with
+// a backoff when it is aborted because of a concurrent transaction.
+func (pc *PostgresClient) inTransaction(fn func(ctx context.Context, tx pgx.Tx) error) error {
+	ctx, cancel := context.WithTimeout(pc.ctx, pc.transactionTimeout)
+	defer cancel()
+
+	backoff := serializationRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := pgx.BeginFunc(ctx, pc.Pool, func(tx pgx.Tx) error {
+			return fn(ctx, tx)
+		})
+		if err == nil || !isSerializationFailure(err) || attempt >= pc.serializationRetries {
+			return err
+		}
+		log.Printf("Retrying transaction after serialization failure: %v", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isSerializationFailure returns true for errors that abort a transaction
+// because of a concurrent transaction.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+}
+
 func (pc *PostgresClient) testConnection() error {
-	return pc.Ping(pc.ctx)
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	return pc.Ping(ctx)
 }
 
 // TableExists returns whether a table with the given name exists.
@@ -85,7 +180,9 @@ func (pc *PostgresClient) CreateTableFromSchema(tableName string, pgSchema *Post
 	query := fmt.Sprintf(`CREATE TABLE "%s" (%s);`, tableName, sqlSchema)
 	log.Printf("Creating Postgres table: %s", query)
 
-	_, err := pc.Exec(pc.ctx, query)
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	_, err := pc.Exec(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -98,11 +195,13 @@ func (pc *PostgresClient) GetMostRecentEntry(table, datetimeField string) (strin
 	datetimeField = JSONDotAccessorToArrowAccessor(datetimeField)
 	query := "SELECT " + datetimeField + " as date FROM " + table + " ORDER BY date DESC LIMIT 1;"
 
-	rows, err := pc.Query(pc.ctx, query)
-	defer rows.Close()
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	rows, err := pc.Query(ctx, query)
 	if err != nil {
 		return "", err
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var date string
 		err := rows.Scan(&date)
@@ -111,7 +210,7 @@ func (pc *PostgresClient) GetMostRecentEntry(table, datetimeField string) (strin
 		}
 		return date, nil
 	}
-	return "", nil
+	return "", rows.Err()
 }
 
 // GetExistingTableNames returns a list of public tables.
@@ -119,11 +218,13 @@ func (pc *PostgresClient) GetExistingTableNames() ([]string, error) {
 	var tableNames []string
 
 	query := "select table_name from information_schema.tables WHERE table_schema='public';"
-	rows, err := pc.Query(pc.ctx, query)
-	defer rows.Close()
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	rows, err := pc.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var tableName string
 		err := rows.Scan(&tableName)
@@ -133,7 +234,7 @@ func (pc *PostgresClient) GetExistingTableNames() ([]string, error) {
 		tableNames = append(tableNames, tableName)
 	}
 
-	return tableNames, nil
+	return tableNames, rows.Err()
 }
 
 // JSONDotAccessorToArrowAccessor converts StandardSQL's dot operator for
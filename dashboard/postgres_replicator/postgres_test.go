@@ -0,0 +1,31 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsSerializationFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "other error", err: errors.New("connection refused"), want: false},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("insert failed: %w", &pgconn.PgError{Code: "40001"}), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSerializationFailure(tt.err); got != tt.want {
+				t.Errorf("isSerializationFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultResultsDays is the number of days of results returned when the
+	// days parameter is not specified.
+	defaultResultsDays = 30
+
+	// maxResultsDays is the largest number of days of results that can be
+	// requested at once.
+	maxResultsDays = 365
+)
+
+// ResultPoint is a single result of a scenario, normalized to a timestamp and
+// a set of numeric summary metrics.
+type ResultPoint struct {
+	Time    time.Time          `json:"time"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// ResultSeries is the list of results of a scenario in a table, ordered by
+// time.
+type ResultSeries struct {
+	Table    string        `json:"table"`
+	Scenario string        `json:"scenario"`
+	Points   []ResultPoint `json:"points"`
+}
+
+// ResultsQuerier gets the results of a scenario from a table, starting at the
+// given time.
+type ResultsQuerier interface {
+	GetResults(table, dateField, scenario string, since time.Time) ([]ResultPoint, error)
+}
+
+// GetResults returns the results of a scenario from a replicated table,
+// starting at the given time. The numeric fields of the summary column of each
+// row are returned as metrics.
+func (pc *PostgresClient) GetResults(table, dateField, scenario string, since time.Time) ([]ResultPoint, error) {
+	dateField = JSONDotAccessorToArrowAccessor(dateField)
+	query := fmt.Sprintf(`SELECT (%s)::timestamptz AS date, summary::text FROM "%s" WHERE scenario->>'name' = $1 AND (%s)::timestamptz >= $2 ORDER BY date;`, dateField, table, dateField)
+
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	rows, err := pc.Query(ctx, query, scenario, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []ResultPoint{}
+	for rows.Next() {
+		var date time.Time
+		var summary *string
+		if err := rows.Scan(&date, &summary); err != nil {
+			return nil, err
+		}
+		metrics, err := parseSummaryMetrics(summary)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse summary of result at %v: %v", date, err)
+		}
+		points = append(points, ResultPoint{Time: date, Metrics: metrics})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// parseSummaryMetrics returns the numeric fields of a JSON summary.
+func parseSummaryMetrics(summary *string) (map[string]float64, error) {
+	metrics := make(map[string]float64)
+	if summary == nil {
+		return metrics, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(*summary), &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		if number, ok := value.(float64); ok {
+			metrics[name] = number
+		}
+	}
+	return metrics, nil
+}
+
+// ResultsHandler serves recent results as JSON. It is read-only and only
+// serves tables listed in the transfer configuration, so teams can build
+// custom graphs without direct database credentials.
+type ResultsHandler struct {
+	querier    ResultsQuerier
+	dateFields map[string]string
+	tables     []string
+}
+
+// NewResultsHandler creates a new ResultsHandler, which serves results for
+// the tables in the transfer configuration.
+func NewResultsHandler(querier ResultsQuerier, config *TableConfig) *ResultsHandler {
+	h := &ResultsHandler{
+		querier:    querier,
+		dateFields: make(map[string]string),
+	}
+	for _, dataset := range config.Datasets {
+		for _, table := range dataset.Tables {
+			h.dateFields[table.Name] = table.DateField
+			h.tables = append(h.tables, table.Name)
+		}
+	}
+	return h
+}
+
+// ServeHTTP handles requests of the form
+// /api/results?scenario=<name>[&table=<name>][&days=<n>]. The table defaults
+// to the first table in the configuration and days defaults to 30.
+func (h *ResultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	scenario := query.Get("scenario")
+	if scenario == "" {
+		http.Error(w, "missing required parameter: scenario", http.StatusBadRequest)
+		return
+	}
+
+	table := query.Get("table")
+	if table == "" && len(h.tables) > 0 {
+		table = h.tables[0]
+	}
+	dateField, ok := h.dateFields[table]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown table: %q", table), http.StatusNotFound)
+		return
+	}
+
+	days := defaultResultsDays
+	if value := query.Get("days"); value != "" {
+		var err error
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxResultsDays {
+			http.Error(w, fmt.Sprintf("days must be an integer between 1 and %d", maxResultsDays), http.StatusBadRequest)
+			return
+		}
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	points, err := h.querier.GetResults(table, dateField, scenario, since)
+	if err != nil {
+		log.Printf("Error getting results for scenario %q from table %q: %v", scenario, table, err)
+		http.Error(w, "could not get results", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(&ResultSeries{
+		Table:    table,
+		Scenario: scenario,
+		Points:   points,
+	})
+	if err != nil {
+		log.Printf("Error writing results response: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeResultsQuerier struct {
+	table     string
+	dateField string
+	scenario  string
+	since     time.Time
+	points    []ResultPoint
+	err       error
+}
+
+func (f *fakeResultsQuerier) GetResults(table, dateField, scenario string, since time.Time) ([]ResultPoint, error) {
+	f.table = table
+	f.dateField = dateField
+	f.scenario = scenario
+	f.since = since
+	return f.points, f.err
+}
+
+func newTestTableConfig() *TableConfig {
+	var config TableConfig
+	config.Datasets = append(config.Datasets, struct {
+		Name   string `yaml:"name"`
+		Tables []struct {
+			Name           string `yaml:"name"`
+			DateField      string `yaml:"dateField"`
+			PartitionField string `yaml:"partitionField"`
+		} `yaml:"tables"`
+	}{
+		Name: "e2e_benchmarks",
+		Tables: []struct {
+			Name           string `yaml:"name"`
+			DateField      string `yaml:"dateField"`
+			PartitionField string `yaml:"partitionField"`
+		}{
+			{Name: "results_8core", DateField: "metadata.created"},
+			{Name: "results_32core", DateField: "metadata.created"},
+		},
+	})
+	return &config
+}
+
+func TestResultsHandler(t *testing.T) {
+	created := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	querier := &fakeResultsQuerier{
+		points: []ResultPoint{
+			{Time: created, Metrics: map[string]float64{"qps": 1000, "latency50": 250}},
+		},
+	}
+	handler := NewResultsHandler(querier, newTestTableConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/results?scenario=cpp_unary&table=results_32core&days=7", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ResultsHandler.ServeHTTP() status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if querier.table != "results_32core" || querier.dateField != "metadata.created" || querier.scenario != "cpp_unary" {
+		t.Errorf("ResultsHandler.ServeHTTP() queried table %q, date field %q, scenario %q", querier.table, querier.dateField, querier.scenario)
+	}
+	if since := time.Since(querier.since); since < 7*24*time.Hour || since > 8*24*time.Hour {
+		t.Errorf("ResultsHandler.ServeHTTP() queried results since %v, want 7 days ago", querier.since)
+	}
+
+	var got ResultSeries
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("ResultsHandler.ServeHTTP() returned invalid JSON: %v", err)
+	}
+	want := ResultSeries{
+		Table:    "results_32core",
+		Scenario: "cpp_unary",
+		Points:   querier.points,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResultsHandler.ServeHTTP() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestResultsHandlerDefaultTable(t *testing.T) {
+	querier := &fakeResultsQuerier{}
+	handler := NewResultsHandler(querier, newTestTableConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/results?scenario=cpp_unary", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ResultsHandler.ServeHTTP() status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if querier.table != "results_8core" {
+		t.Errorf("ResultsHandler.ServeHTTP() queried table %q, want %q", querier.table, "results_8core")
+	}
+}
+
+func TestResultsHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		err    error
+		want   int
+	}{
+		{"missing scenario", http.MethodGet, "/api/results", nil, http.StatusBadRequest},
+		{"unknown table", http.MethodGet, "/api/results?scenario=s&table=secrets", nil, http.StatusNotFound},
+		{"invalid days", http.MethodGet, "/api/results?scenario=s&days=abc", nil, http.StatusBadRequest},
+		{"too many days", http.MethodGet, "/api/results?scenario=s&days=1000", nil, http.StatusBadRequest},
+		{"wrong method", http.MethodPost, "/api/results?scenario=s", nil, http.StatusMethodNotAllowed},
+		{"query error", http.MethodGet, "/api/results?scenario=s", errors.New("connection lost"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewResultsHandler(&fakeResultsQuerier{err: tt.err}, newTestTableConfig())
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("ResultsHandler.ServeHTTP() status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSummaryMetrics(t *testing.T) {
+	summary := `{"qps": 1500.5, "latency99": 1200, "name": "ignored"}`
+	got, err := parseSummaryMetrics(&summary)
+	if err != nil {
+		t.Fatalf("parseSummaryMetrics() error: %v", err)
+	}
+	want := map[string]float64{"qps": 1500.5, "latency99": 1200}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseSummaryMetrics() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,284 @@
+package transfer
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// bigQueryTimestampFormat is the layout of a BigQuery TIMESTAMP literal.
+const bigQueryTimestampFormat = "2006-01-02 15:04:05.000000-07:00"
+
+// partitionSlack widens the conditions on the partition field of a table
+// beyond the conditions on its date field. Partitions are a day long, so the
+// slack costs at most one more partition, and it tolerates partition fields
+// that were derived from the date field with a different precision or time
+// zone.
+const partitionSlack = 24 * time.Hour
+
+// Pruner removes rows that are older than the retention period, so the
+// databases of the dashboard do not grow without bound.
+type Pruner struct {
+	bq        *BigQueryClient
+	pg        *PostgresClient
+	config    *TableConfig
+	retention *RetentionConfig
+	metrics   *Metrics
+	ready     chan bool
+}
+
+// NewPruner returns a new Pruner. The counts of pruned rows are recorded in
+// the given metrics.
+func NewPruner(bq *BigQueryClient, pg *PostgresClient, config *TableConfig, retention *RetentionConfig, metrics *Metrics) *Pruner {
+	pruner := &Pruner{
+		bq:        bq,
+		pg:        pg,
+		config:    config,
+		retention: retention,
+		metrics:   metrics,
+		ready:     make(chan bool, 1),
+	}
+	pruner.ready <- true
+	return pruner
+}
+
+// Run prunes the rows of each table that are older than the retention period.
+// Rows are deleted from PostgreSQL and, when an archive URI is configured,
+// exported from BigQuery to Cloud Storage and deleted from BigQuery. In dry
+// run mode, rows are only counted.
+func (p *Pruner) Run() {
+	if p.retention.MaxAgeDays == 0 {
+		log.Println("Retention is not configured, skipping prune")
+		return
+	}
+
+	select {
+	case <-p.ready:
+		log.Println("Beginning prune")
+	default:
+		log.Println("Prune already in progress, skipping")
+		return
+	}
+
+	cutoff := retentionCutoff(time.Now(), p.retention.MaxAgeDays)
+	for _, dataset := range p.config.Datasets {
+		for _, table := range dataset.Tables {
+			p.pruneTable(dataset.Name, table.Name, table.DateField, table.PartitionField, cutoff)
+		}
+	}
+
+	log.Println("Prune complete")
+	p.ready <- true
+}
+
+func (p *Pruner) pruneTable(dataset, table, dateField, partitionField string, cutoff time.Time) {
+	logger := NewLogger(table)
+
+	if err := p.prunePostgres(table, dateField, cutoff, logger); err != nil {
+		logger.Errorf("Could not prune Postgres table: %v", err)
+	}
+
+	if p.retention.ArchiveURI == "" {
+		return
+	}
+	if err := p.pruneBigQuery(dataset, table, dateField, partitionField, cutoff, logger); err != nil {
+		logger.Errorf("Could not prune BigQuery table: %v", err)
+	}
+}
+
+func (p *Pruner) prunePostgres(table, dateField string, cutoff time.Time, logger *Logger) error {
+	tableExists, err := p.pg.TableExists(table)
+	if err != nil {
+		return err
+	}
+	if !tableExists {
+		return nil
+	}
+
+	expired, err := p.pg.CountRowsBefore(table, dateField, cutoff)
+	if err != nil {
+		return err
+	}
+	if p.retention.DryRun {
+		logger.Printf("Dry run: %d Postgres rows older than %s would be pruned", expired, cutoff.Format(time.RFC3339))
+		p.metrics.addPruned(table, "postgres", expired, 0)
+		return nil
+	}
+
+	pruned, err := p.pg.DeleteRowsBefore(table, dateField, cutoff)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Pruned %d Postgres rows older than %s", pruned, cutoff.Format(time.RFC3339))
+	p.metrics.addPruned(table, "postgres", expired, pruned)
+	return nil
+}
+
+func (p *Pruner) pruneBigQuery(dataset, table, dateField, partitionField string, cutoff time.Time, logger *Logger) error {
+	expired, err := p.bq.CountRowsBefore(dataset, table, dateField, partitionField, cutoff)
+	if err != nil {
+		return err
+	}
+	if expired == 0 || p.retention.DryRun {
+		if expired > 0 {
+			logger.Printf("Dry run: %d BigQuery rows older than %s would be archived and pruned", expired, cutoff.Format(time.RFC3339))
+		}
+		p.metrics.addPruned(table, "bigquery", expired, 0)
+		return nil
+	}
+
+	uri := archiveURI(p.retention.ArchiveURI, dataset, table, cutoff)
+	if err := p.bq.ExportRowsBefore(dataset, table, dateField, partitionField, cutoff, uri); err != nil {
+		return fmt.Errorf("could not archive rows to %s: %v", uri, err)
+	}
+	logger.Printf("Archived %d BigQuery rows to %s", expired, uri)
+
+	pruned, err := p.bq.DeleteRowsBefore(dataset, table, dateField, partitionField, cutoff)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Pruned %d BigQuery rows older than %s", pruned, cutoff.Format(time.RFC3339))
+	p.metrics.addPruned(table, "bigquery", expired, pruned)
+	return nil
+}
+
+// CountRowsBefore returns the number of rows of a table with a date older
+// than the cutoff.
+func (pc *PostgresClient) CountRowsBefore(table, dateField string, cutoff time.Time) (int64, error) {
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	var count int64
+	err := pc.QueryRow(ctx, postgresPruneSQL("SELECT COUNT(*) FROM", table, dateField), cutoff).Scan(&count)
+	return count, err
+}
+
+// DeleteRowsBefore deletes the rows of a table with a date older than the
+// cutoff, and returns the number of rows deleted.
+func (pc *PostgresClient) DeleteRowsBefore(table, dateField string, cutoff time.Time) (int64, error) {
+	ctx, cancel := pc.queryContext()
+	defer cancel()
+	tag, err := pc.Exec(ctx, postgresPruneSQL("DELETE FROM", table, dateField), cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CountRowsBefore returns the number of rows of a table with a date older
+// than the cutoff.
+func (bqc *BigQueryClient) CountRowsBefore(dataset, table, dateField, partitionField string, cutoff time.Time) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s WHERE %s", dataset, table, bigQueryPruneCondition(dateField, partitionField, cutoff))
+	rows, err := bqc.bqClient.Query(query).Read(bqc.ctx)
+	if err != nil {
+		return 0, err
+	}
+	var row []bigquery.Value
+	if err := rows.Next(&row); err != nil {
+		if err == iterator.Done {
+			return 0, nil
+		}
+		return 0, err
+	}
+	count, ok := row[0].(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count %v", row[0])
+	}
+	return count, nil
+}
+
+// ExportRowsBefore exports the rows of a table with a date older than the
+// cutoff to Cloud Storage, as newline-delimited JSON files. The URI must
+// contain a single * wildcard.
+func (bqc *BigQueryClient) ExportRowsBefore(dataset, table, dateField, partitionField string, cutoff time.Time, uri string) error {
+	query := fmt.Sprintf("EXPORT DATA OPTIONS(uri='%s', format='JSON', overwrite=true) AS SELECT * FROM %s.%s WHERE %s", uri, dataset, table, bigQueryPruneCondition(dateField, partitionField, cutoff))
+	_, err := bqc.runQuery(query)
+	return err
+}
+
+// DeleteRowsBefore deletes the rows of a table with a date older than the
+// cutoff, and returns the number of rows deleted.
+func (bqc *BigQueryClient) DeleteRowsBefore(dataset, table, dateField, partitionField string, cutoff time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s.%s WHERE %s", dataset, table, bigQueryPruneCondition(dateField, partitionField, cutoff))
+	status, err := bqc.runQuery(query)
+	if err != nil {
+		return 0, err
+	}
+	if stats, ok := status.Statistics.Details.(*bigquery.QueryStatistics); ok {
+		return stats.NumDMLAffectedRows, nil
+	}
+	return 0, nil
+}
+
+// runQuery runs a query job and waits for it to complete.
+func (bqc *BigQueryClient) runQuery(query string) (*bigquery.JobStatus, error) {
+	job, err := bqc.bqClient.Query(query).Run(bqc.ctx)
+	if err != nil {
+		return nil, err
+	}
+	status, err := job.Wait(bqc.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := status.Err(); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// retentionCutoff returns the time before which rows are pruned.
+func retentionCutoff(now time.Time, maxAgeDays int) time.Time {
+	return now.UTC().AddDate(0, 0, -maxAgeDays)
+}
+
+// postgresPruneSQL constructs a statement that applies to the rows of a table
+// with a date older than the cutoff, passed as the $1 parameter. The date
+// field is cast, since fields nested in JSON columns are read as text.
+func postgresPruneSQL(statement, table, dateField string) string {
+	return fmt.Sprintf(`%s "%s" WHERE (%s)::timestamptz < $1;`, statement, table, JSONDotAccessorToArrowAccessor(dateField))
+}
+
+// bigQueryPruneCondition returns a condition that matches the rows with a
+// date older than the cutoff. When the table is partitioned, the condition
+// also filters on the partition field, so that newer partitions are not
+// scanned.
+func bigQueryPruneCondition(dateField, partitionField string, cutoff time.Time) string {
+	condition := fmt.Sprintf("%s < TIMESTAMP '%s'", dateField, cutoff.UTC().Format(bigQueryTimestampFormat))
+	if partitionField == "" {
+		return condition
+	}
+	end := cutoff.Add(partitionSlack)
+	return fmt.Sprintf("%s AND %s < TIMESTAMP '%s'", condition, partitionField, end.UTC().Format(bigQueryTimestampFormat))
+}
+
+// bigQueryPartitionCondition returns a condition on the partition field of a
+// table that matches the partitions which may hold rows with a date after
+// the datetime. All partitions are matched when the datetime is empty or
+// cannot be parsed, since tables may require a filter on the partition field.
+func bigQueryPartitionCondition(partitionField, datetime string) string {
+	start := time.Unix(0, 0)
+	if t, ok := parseDatetime(datetime); ok {
+		start = t.Add(-partitionSlack)
+	}
+	return fmt.Sprintf("%s >= TIMESTAMP '%s'", partitionField, start.UTC().Format(bigQueryTimestampFormat))
+}
+
+// parseDatetime parses a datetime read from a date field, or formatted as a
+// BigQuery TIMESTAMP literal.
+func parseDatetime(datetime string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, datetime); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// archiveURI returns the Cloud Storage URI where the rows of a table that are
+// older than the cutoff are archived.
+func archiveURI(prefix, dataset, table string, cutoff time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/%s/*.json", strings.TrimSuffix(prefix, "/"), dataset, table, cutoff.Format("20060102T150405Z"))
+}
@@ -0,0 +1,127 @@
+package transfer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2022, 3, 31, 12, 0, 0, 0, time.UTC)
+	want := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := retentionCutoff(now, 30); !got.Equal(want) {
+		t.Errorf("retentionCutoff() = %v, want %v", got, want)
+	}
+}
+
+func TestPostgresPruneSQL(t *testing.T) {
+	got := postgresPruneSQL("DELETE FROM", "results", "metadata.created")
+	want := `DELETE FROM "results" WHERE (metadata->>'created')::timestamptz < $1;`
+	if got != want {
+		t.Errorf("postgresPruneSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestBigQueryPruneCondition(t *testing.T) {
+	cutoff := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	got := bigQueryPruneCondition("metadata.created", "", cutoff)
+	want := "metadata.created < TIMESTAMP '2022-03-01 12:00:00.000000+00:00'"
+	if got != want {
+		t.Errorf("bigQueryPruneCondition() = %q, want %q", got, want)
+	}
+}
+
+func TestBigQueryPruneConditionPartitioned(t *testing.T) {
+	cutoff := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	got := bigQueryPruneCondition("metadata.created", "created", cutoff)
+	want := "metadata.created < TIMESTAMP '2022-03-01 12:00:00.000000+00:00' AND created < TIMESTAMP '2022-03-02 12:00:00.000000+00:00'"
+	if got != want {
+		t.Errorf("bigQueryPruneCondition() = %q, want %q", got, want)
+	}
+}
+
+func TestBigQueryPartitionCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		datetime string
+		want     string
+	}{
+		{
+			name: "full transfer",
+			want: "created >= TIMESTAMP '1970-01-01 00:00:00.000000+00:00'",
+		},
+		{
+			name:     "date field",
+			datetime: "2022-03-01T12:00:00.123456789Z",
+			want:     "created >= TIMESTAMP '2022-02-28 12:00:00.123456+00:00'",
+		},
+		{
+			name:     "retention cutoff",
+			datetime: "2022-03-01 12:00:00.000000+00:00",
+			want:     "created >= TIMESTAMP '2022-02-28 12:00:00.000000+00:00'",
+		},
+		{
+			name:     "unparsable",
+			datetime: "yesterday",
+			want:     "created >= TIMESTAMP '1970-01-01 00:00:00.000000+00:00'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bigQueryPartitionCondition("created", tt.datetime); got != tt.want {
+				t.Errorf("bigQueryPartitionCondition(%q) = %q, want %q", tt.datetime, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveURI(t *testing.T) {
+	cutoff := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	got := archiveURI("gs://bucket/archive/", "e2e_benchmarks", "results", cutoff)
+	want := "gs://bucket/archive/e2e_benchmarks/results/20220301T120000Z/*.json"
+	if got != want {
+		t.Errorf("archiveURI() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateYAMLConfigRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention RetentionConfig
+		wantErr   bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:      "max age",
+			retention: RetentionConfig{MaxAgeDays: 365},
+		},
+		{
+			name:      "negative max age",
+			retention: RetentionConfig{MaxAgeDays: -1},
+			wantErr:   true,
+		},
+		{
+			name:      "archive",
+			retention: RetentionConfig{MaxAgeDays: 365, ArchiveURI: "gs://bucket/archive"},
+		},
+		{
+			name:      "archive without max age",
+			retention: RetentionConfig{ArchiveURI: "gs://bucket/archive"},
+			wantErr:   true,
+		},
+		{
+			name:      "archive outside cloud storage",
+			retention: RetentionConfig{MaxAgeDays: 365, ArchiveURI: "/tmp/archive"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateYAMLConfig(&YAMLConfig{Retention: tt.retention})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("validateYAMLConfig() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,32 +1,43 @@
 package transfer
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	"github.com/jackc/pgx/v5"
 	"github.com/leporo/sqlf"
 	"google.golang.org/api/iterator"
 )
 
 // Transfer provides functions to transfer data from BigQuery to PostgreSQL.
 type Transfer struct {
-	bq     *BigQueryClient
-	pg     *PostgresClient
-	config *TableConfig
-	ready  chan bool
+	bq        *BigQueryClient
+	pg        *PostgresClient
+	config    *TableConfig
+	retention *RetentionConfig
+	metrics   *Metrics
+	ready     chan bool
 }
 
-// NewTransfer returns a new Transfer.
-func NewTransfer(bq *BigQueryClient, pg *PostgresClient, config *TableConfig) *Transfer {
+// NewTransfer returns a new Transfer. Rows older than the retention period
+// are not transferred to empty tables. The metrics of the transfer include the
+// statistics of the connection pool of the PostgreSQL client.
+func NewTransfer(bq *BigQueryClient, pg *PostgresClient, config *TableConfig, retention *RetentionConfig) *Transfer {
 	transfer := &Transfer{
-		bq:     bq,
-		pg:     pg,
-		config: config,
-		ready:  make(chan bool, 1),
+		bq:        bq,
+		pg:        pg,
+		config:    config,
+		retention: retention,
+		metrics:   NewMetrics(),
+		ready:     make(chan bool, 1),
+	}
+	if pg != nil {
+		transfer.metrics.addPool(pg)
 	}
 	transfer.ready <- true
 	return transfer
@@ -42,12 +53,16 @@ func (t *Transfer) Run() {
 		return
 	}
 
+	if err := t.pg.CreateDeadLetterTable(); err != nil {
+		log.Printf("Error: Could not create dead-letter table: %v", err)
+	}
+
 	activeTransfers := 0
 	done := make(chan bool)
 
 	for _, dataset := range t.config.Datasets {
 		for _, table := range dataset.Tables {
-			go t.transferTable(dataset.Name, table.Name, table.DateField, done)
+			go t.transferTable(dataset.Name, table.Name, table.DateField, table.PartitionField, done)
 			activeTransfers++
 		}
 	}
@@ -60,6 +75,18 @@ func (t *Transfer) Run() {
 	t.ready <- true
 }
 
+// Metrics returns the metrics of the transfer, so they can be shared with a
+// Pruner.
+func (t *Transfer) Metrics() *Metrics {
+	return t.metrics
+}
+
+// MetricsHandler returns an HTTP handler that serves counters of the rows
+// transferred, failed and added to the dead-letter table.
+func (t *Transfer) MetricsHandler() http.Handler {
+	return t.metrics.Handler()
+}
+
 // RunContinuously continuously runs Transfer.Run, with sleepTimeInSecs between
 // transfers.
 func (t *Transfer) RunContinuously(sleepAfterTransferInSecs int) {
@@ -70,7 +97,7 @@ func (t *Transfer) RunContinuously(sleepAfterTransferInSecs int) {
 	}
 }
 
-func (t *Transfer) transferTable(bigQueryDataset, tableName, dateField string, done chan bool) {
+func (t *Transfer) transferTable(bigQueryDataset, tableName, dateField, partitionField string, done chan bool) {
 	logger := NewLogger(tableName)
 
 	// Get the BigQuery table schema
@@ -98,7 +125,7 @@ func (t *Transfer) transferTable(bigQueryDataset, tableName, dateField string, d
 	}
 
 	// Get rows to transfer
-	rows, err := t.getBigQueryRows(bigQueryDataset, tableName, dateField, bqSchema)
+	rows, err := t.getBigQueryRows(bigQueryDataset, tableName, dateField, partitionField, bqSchema)
 	if err != nil {
 		logger.Errorf("Could not get data from BigQuery: %v", err)
 		done <- true
@@ -159,15 +186,21 @@ func (t *Transfer) prepareTable(tableName string, pgSchema *PostgresSchema) erro
 	return nil
 }
 
-func (t *Transfer) getBigQueryRows(bigQueryDataset, tableName, dateField string, bqSchema *BigQuerySchema) (*bigquery.RowIterator, error) {
+func (t *Transfer) getBigQueryRows(bigQueryDataset, tableName, dateField, partitionField string, bqSchema *BigQuerySchema) (*bigquery.RowIterator, error) {
 	// Get most recent entry from Postgres table
 	timestamp, err := t.pg.GetMostRecentEntry(tableName, dateField)
 	if err != nil {
 		return nil, fmt.Errorf("Could not get most recent Postgres timestamp: %s", err)
 	}
 
+	// Pruned rows must not be transferred again when a table is emptied by
+	// pruning, so rows older than the retention period are skipped.
+	if timestamp == "" && t.retention != nil && t.retention.MaxAgeDays > 0 {
+		timestamp = retentionCutoff(time.Now(), t.retention.MaxAgeDays).Format(bigQueryTimestampFormat)
+	}
+
 	// Get data after this time, or all data if last timestamp doesn't exist
-	rows, err := t.bq.GetDataAfterDatetime(bigQueryDataset, tableName, dateField, timestamp, bqSchema)
+	rows, err := t.bq.GetDataAfterDatetime(bigQueryDataset, tableName, dateField, partitionField, timestamp, bqSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -175,47 +208,115 @@ func (t *Transfer) getBigQueryRows(bigQueryDataset, tableName, dateField string,
 }
 
 func (t *Transfer) transferToPostgres(tableName string, pgSchema *PostgresSchema, rows *bigquery.RowIterator, logger *Logger) error {
-	// Begin transaction
-	ctx := t.pg.ctx
-	tx, err := t.pg.Begin(ctx)
-	defer tx.Rollback(ctx)
-	if err != nil {
-		return errors.New("Could not begin transaction")
-	}
-
-	// Transfer rows to Postgres
-	rowsPrinted := false
+	// Read all rows first, so the transaction can be retried
+	var bqRows []map[string]bigquery.Value
 	for {
 		row := make(map[string]bigquery.Value)
-		err = rows.Next(&row)
-		if !rowsPrinted {
-			logger.Printf("Rows to transfer: %d", rows.TotalRows)
-			rowsPrinted = true
-		}
+		err := rows.Next(&row)
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
 			return fmt.Errorf("Big query row error: %s", err)
 		}
-		template, args, err := prepareInsertSQL(tableName, pgSchema, row)
+		bqRows = append(bqRows, row)
+	}
+	logger.Printf("Rows to transfer: %d", len(bqRows))
+
+	var stats *transferStats
+	err := t.pg.inTransaction(func(ctx context.Context, tx pgx.Tx) error {
+		stats = &transferStats{}
+
+		// Retry rows that failed in previous runs
+		err := retryDeadLetters(ctx, tx, tableName, pgSchema, stats, logger)
 		if err != nil {
-			return fmt.Errorf("Could not construct insert SQL: %s", err)
+			return fmt.Errorf("Could not retry dead-letter rows: %w", err)
 		}
-		_, err = tx.Exec(ctx, template, args...)
-		if err != nil {
-			return fmt.Errorf("Transaction exec error: %s, %s, %s", err, template, args)
+
+		// Transfer rows to Postgres
+		for _, row := range bqRows {
+			rowErr := insertRow(ctx, tx, tableName, pgSchema, row)
+			if rowErr == nil {
+				stats.transferred++
+				continue
+			}
+			if isSerializationFailure(rowErr) {
+				return rowErr
+			}
+			stats.failed++
+			logger.Errorf("Could not transfer row, adding it to the dead-letter table: %v", rowErr)
+			if err := addDeadLetter(ctx, tx, tableName, row, rowErr); err != nil {
+				return fmt.Errorf("Could not add row to dead-letter table: %w", err)
+			}
+			stats.deadLettered++
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Transaction error: %s", err)
 	}
+	t.metrics.add(tableName, stats)
+	logger.Printf("Rows transferred: %d, failed: %d, added to dead-letter table: %d, abandoned: %d", stats.transferred, stats.failed, stats.deadLettered, stats.abandoned)
+	return nil
+}
 
-	// Commit transaction
-	err = tx.Commit(ctx)
+// retryDeadLetters attempts to transfer the rows in the dead-letter table for
+// a table. Rows that are transferred are removed from the dead-letter table,
+// and rows that fail for the last time are counted as abandoned.
+func retryDeadLetters(ctx context.Context, tx pgx.Tx, tableName string, pgSchema *PostgresSchema, stats *transferStats, logger *Logger) error {
+	deadLetters, err := getDeadLetters(ctx, tx, tableName)
 	if err != nil {
-		return fmt.Errorf("Transaction commit error: %s", err)
+		return err
+	}
+	if len(deadLetters) == 0 {
+		return nil
+	}
+	logger.Printf("Dead-letter rows to retry: %d", len(deadLetters))
+
+	for _, dl := range deadLetters {
+		row, rowErr := decodeDeadLetterRow(dl.rowData)
+		if rowErr == nil {
+			rowErr = insertRow(ctx, tx, tableName, pgSchema, row)
+		}
+		if isSerializationFailure(rowErr) {
+			return rowErr
+		}
+		if rowErr == nil {
+			stats.transferred++
+		} else {
+			stats.failed++
+			logger.Errorf("Could not transfer dead-letter row %d after %d attempts: %v", dl.id, dl.attempts+1, rowErr)
+			if dl.exhausted() {
+				stats.abandoned++
+				logger.Errorf("Dead-letter row %d reached the limit of %d attempts, it will not be retried", dl.id, MaxDeadLetterAttempts)
+			}
+		}
+		if err := retryDeadLetter(ctx, tx, dl, rowErr); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// insertRow inserts a row in a table. The insert is wrapped in a savepoint, so
+// that a failure does not abort the enclosing transaction.
+func insertRow(ctx context.Context, tx pgx.Tx, tableName string, pgSchema *PostgresSchema, row map[string]bigquery.Value) error {
+	template, args, err := prepareInsertSQL(tableName, pgSchema, row)
+	if err != nil {
+		return fmt.Errorf("Could not construct insert SQL: %s", err)
+	}
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Could not create savepoint: %w", err)
+	}
+	_, err = savepoint.Exec(ctx, template, args...)
+	if err != nil {
+		savepoint.Rollback(ctx)
+		return fmt.Errorf("Transaction exec error: %w, %s, %s", err, template, args)
+	}
+	return savepoint.Commit(ctx)
+}
+
 func prepareInsertSQL(tableName string, pgSchema *PostgresSchema, row map[string]bigquery.Value) (string, []interface{}, error) {
 	sqlf.SetDialect(sqlf.PostgreSQL)
 	sqlBuilder := sqlf.InsertInto(tableName)
@@ -225,7 +326,11 @@ func prepareInsertSQL(tableName string, pgSchema *PostgresSchema, row map[string
 			continue
 		}
 		if pgSchema.schema[colName] == "TIMESTAMPTZ" {
-			value = value.(time.Time).Format(time.RFC3339)
+			// Rows retried from the dead-letter table contain timestamps
+			// that are already formatted.
+			if timestamp, ok := value.(time.Time); ok {
+				value = timestamp.Format(time.RFC3339)
+			}
 		}
 		sqlBuilder.Set(colName, value)
 	}
@@ -0,0 +1,23 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failure enumerates the reasons why a load test can fail. The same
+// reasons are shared by the controller, which records them in the status of
+// load tests, and the runner, which records them in xunit reports and turns
+// them into exit codes. Each reason belongs to a category, which tells whether
+// the failure is caused by the code under test, by the configuration of the
+// test, by the cluster, or by the test being cancelled.
+package failure
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failure
+
+// Category groups reasons by the party that is likely responsible for a
+// failure.
+type Category string
+
+const (
+	// TestCategory includes failures of the code under test, such as a
+	// worker that crashes or fails to build.
+	TestCategory Category = "Test"
+
+	// ConfigurationCategory includes failures caused by an invalid load
+	// test, such as a missing image or an unknown pool.
+	ConfigurationCategory Category = "Configuration"
+
+	// InfrastructureCategory includes failures caused by the cluster, such
+	// as nodes that are missing or a Kubernetes API that is unavailable.
+	InfrastructureCategory Category = "Infrastructure"
+
+	// CancelledCategory includes tests that did not complete because they
+	// were deleted or the run was interrupted.
+	CancelledCategory Category = "Cancelled"
+)
+
+// Reason identifies why a load test failed.
+type Reason string
+
+const (
+	// Unknown is the reason of failures that cannot be classified.
+	Unknown Reason = "Unknown"
+
+	// ConfigurationError is the reason when a load test spec is invalid.
+	ConfigurationError Reason = "ConfigurationError"
+
+	// FailedSettingDefaults is the reason when defaults could not be set on
+	// a load test.
+	FailedSettingDefaults Reason = "FailedSettingDefaults"
+
+	// ImageNotFound is the reason when a container image required by one of
+	// the components of a load test does not exist in its registry.
+	ImageNotFound Reason = "ImageNotFound"
+
+	// IncompatibleImages is the reason when the image of a client or server
+	// of a load test is too old to be paired with the image of its driver.
+	IncompatibleImages Reason = "IncompatibleImages"
+
+	// PoolError is the reason when a driver, client or server requires nodes
+	// from a nonexistent pool.
+	PoolError Reason = "PoolError"
+
+	// PodsMissing is the reason when some of the pods of a load test have not
+	// been created.
+	PodsMissing Reason = "PodsMissing"
+
+	// ImagePullError is the reason when a container on one of the pods of a
+	// load test could not pull its image.
+	ImagePullError Reason = "ImagePullError"
+
+	// KubernetesError is the reason when an issue occurs with Kubernetes that
+	// is not known to be directly related to a load test.
+	KubernetesError Reason = "KubernetesError"
+
+	// BuildFailed is the reason when the build init container on one of the
+	// pods of a load test has failed.
+	BuildFailed Reason = "BuildFailed"
+
+	// InitContainerFailed is the reason when an init container other than
+	// the build container has failed on one of the pods of a load test.
+	InitContainerFailed Reason = "InitContainerFailed"
+
+	// DriverCrashed is the reason when the driver of a load test has failed.
+	DriverCrashed Reason = "DriverCrashed"
+
+	// ContainerFailed is the reason when a container of a client or server
+	// has failed.
+	ContainerFailed Reason = "ContainerFailed"
+
+	// InteropCaseFailed is the reason when the interop client of an interop
+	// test failed one or more of its test cases.
+	InteropCaseFailed Reason = "InteropCaseFailed"
+
+	// CrashLoop is the reason when a container on one of the pods of a load
+	// test has restarted too many times.
+	CrashLoop Reason = "CrashLoop"
+
+	// ResourceExceeded is the reason when a container on one of the pods of
+	// a load test was killed for exceeding its memory limit, or the pod was
+	// evicted for exceeding its ephemeral storage limit.
+	ResourceExceeded Reason = "ResourceExceeded"
+
+	// Timeout is the reason when a load test has not terminated within its
+	// timeout.
+	Timeout Reason = "Timeout"
+
+	// Cancelled is the reason when a load test was deleted or its run was
+	// interrupted before it terminated.
+	Cancelled Reason = "Cancelled"
+
+	// HookFailed is the reason when a hook that the runner invokes before
+	// creating a load test has failed, so the test was not created.
+	HookFailed Reason = "HookFailed"
+
+	// WorkersUnreachable is the reason when the driver of a load test could
+	// not connect to the driver port of one or more of its workers before
+	// the benchmark started.
+	WorkersUnreachable Reason = "WorkersUnreachable"
+
+	// DependencyFailed is the reason when a load test was not created,
+	// because a test that it depends on did not succeed.
+	DependencyFailed Reason = "DependencyFailed"
+)
+
+// reasonInfo holds the properties of a reason.
+type reasonInfo struct {
+	crdReason string
+	category  Category
+}
+
+// reasons maps each reason to its properties. The CRD reasons of existing
+// reasons are kept unchanged, so that reports and queries based on them
+// continue to work.
+var reasons = map[Reason]reasonInfo{
+	ConfigurationError:    {"ConfigurationError", ConfigurationCategory},
+	FailedSettingDefaults: {"FailedSettingDefaults", ConfigurationCategory},
+	ImageNotFound:         {"ImageNotFound", ConfigurationCategory},
+	IncompatibleImages:    {"IncompatibleImages", ConfigurationCategory},
+	PoolError:             {"PoolError", InfrastructureCategory},
+	PodsMissing:           {"PodsMissing", InfrastructureCategory},
+	ImagePullError:        {"ImagePullError", InfrastructureCategory},
+	KubernetesError:       {"KubernetesError", InfrastructureCategory},
+	BuildFailed:           {"BuildFailed", TestCategory},
+	InitContainerFailed:   {"InitContainerError", TestCategory},
+	DriverCrashed:         {"DriverCrashed", TestCategory},
+	ContainerFailed:       {"ContainerError", TestCategory},
+	InteropCaseFailed:     {"InteropCaseFailed", TestCategory},
+	CrashLoop:             {"CrashLoop", TestCategory},
+	ResourceExceeded:      {"ResourceExceeded", TestCategory},
+	Timeout:               {"TimeoutErrored", TestCategory},
+	Cancelled:             {"Cancelled", CancelledCategory},
+	HookFailed:            {"HookFailed", InfrastructureCategory},
+	DependencyFailed:      {"DependencyFailed", CancelledCategory},
+	WorkersUnreachable:    {"WorkersUnreachable", InfrastructureCategory},
+}
+
+// exitCodes maps each category to the exit code of a run of tests that failed
+// for a reason in the category.
+var exitCodes = map[Category]int{
+	TestCategory:           1,
+	ConfigurationCategory:  2,
+	InfrastructureCategory: 3,
+	CancelledCategory:      4,
+}
+
+// categoryPriority lists the categories in the order used to choose the exit
+// code of a run with several failures. Failures of the code under test come
+// first, since they are the failures that the tests are meant to catch.
+var categoryPriority = []Category{
+	TestCategory,
+	ConfigurationCategory,
+	InfrastructureCategory,
+	CancelledCategory,
+}
+
+// FromCRDReason returns the reason that corresponds to the reason string in
+// the status of a load test. Unknown is returned if the string does not match
+// any reason.
+func FromCRDReason(crdReason string) Reason {
+	for reason, info := range reasons {
+		if info.crdReason == crdReason {
+			return reason
+		}
+	}
+	return Unknown
+}
+
+// CRDReason returns the string used as the reason in the status of a load
+// test that failed for this reason.
+func (r Reason) CRDReason() string {
+	if info, ok := reasons[r]; ok {
+		return info.crdReason
+	}
+	return string(r)
+}
+
+// Category returns the category of the reason. Unknown reasons belong to the
+// TestCategory, so they are not mistaken for transient failures.
+func (r Reason) Category() Category {
+	if info, ok := reasons[r]; ok {
+		return info.category
+	}
+	return TestCategory
+}
+
+// XUnitType returns the type of the error recorded in an xunit report for a
+// test that failed for this reason, such as "Infrastructure/PoolError".
+func (r Reason) XUnitType() string {
+	return string(r.Category()) + "/" + string(r)
+}
+
+// ExitCode returns the exit code of a run of tests with a failure for this
+// reason.
+func (r Reason) ExitCode() int {
+	return exitCodes[r.Category()]
+}
+
+// ExitCode returns the exit code of a run of tests that failed for the given
+// reasons. When the reasons belong to several categories, the category that
+// comes first in priority determines the exit code. Zero is returned if there
+// are no reasons.
+func ExitCode(failureReasons []Reason) int {
+	found := make(map[Category]bool)
+	for _, reason := range failureReasons {
+		found[reason.Category()] = true
+	}
+	for _, category := range categoryPriority {
+		if found[category] {
+			return exitCodes[category]
+		}
+	}
+	return 0
+}
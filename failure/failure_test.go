@@ -0,0 +1,76 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failure
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reason", func() {
+	It("round trips through CRD reasons", func() {
+		for reason := range reasons {
+			Expect(FromCRDReason(reason.CRDReason())).To(Equal(reason))
+		}
+	})
+
+	It("keeps existing CRD reasons", func() {
+		Expect(Timeout.CRDReason()).To(Equal("TimeoutErrored"))
+		Expect(InitContainerFailed.CRDReason()).To(Equal("InitContainerError"))
+		Expect(ContainerFailed.CRDReason()).To(Equal("ContainerError"))
+	})
+
+	It("returns Unknown for unrecognized CRD reasons", func() {
+		Expect(FromCRDReason("")).To(Equal(Unknown))
+		Expect(FromCRDReason("SomethingElse")).To(Equal(Unknown))
+	})
+
+	It("assigns a category to every reason", func() {
+		Expect(PoolError.Category()).To(Equal(InfrastructureCategory))
+		Expect(ImageNotFound.Category()).To(Equal(ConfigurationCategory))
+		Expect(IncompatibleImages.Category()).To(Equal(ConfigurationCategory))
+		Expect(BuildFailed.Category()).To(Equal(TestCategory))
+		Expect(Cancelled.Category()).To(Equal(CancelledCategory))
+		Expect(HookFailed.Category()).To(Equal(InfrastructureCategory))
+		Expect(DependencyFailed.Category()).To(Equal(CancelledCategory))
+		Expect(WorkersUnreachable.Category()).To(Equal(InfrastructureCategory))
+		Expect(Unknown.Category()).To(Equal(TestCategory))
+	})
+
+	It("combines the category and reason in xunit types", func() {
+		Expect(PoolError.XUnitType()).To(Equal("Infrastructure/PoolError"))
+	})
+})
+
+var _ = Describe("ExitCode", func() {
+	It("returns zero when there are no failures", func() {
+		Expect(ExitCode(nil)).To(Equal(0))
+	})
+
+	It("returns the exit code of the reason", func() {
+		Expect(ExitCode([]Reason{ConfigurationError})).To(Equal(ConfigurationError.ExitCode()))
+		Expect(ExitCode([]Reason{KubernetesError, PoolError})).To(Equal(3))
+	})
+
+	It("prefers failures of the code under test", func() {
+		Expect(ExitCode([]Reason{Cancelled, PoolError, DriverCrashed, ConfigurationError})).To(Equal(1))
+	})
+
+	It("prefers configuration failures over infrastructure failures", func() {
+		Expect(ExitCode([]Reason{PoolError, ImageNotFound})).To(Equal(2))
+	})
+})
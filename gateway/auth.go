@@ -0,0 +1,120 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenAuth authenticates requests that carry one of a set of bearer tokens
+// in their authorization metadata.
+type TokenAuth struct {
+	tokens [][]byte
+}
+
+// NewTokenAuth creates a TokenAuth that accepts the given tokens. Empty tokens
+// are ignored.
+func NewTokenAuth(tokens []string) *TokenAuth {
+	a := &TokenAuth{}
+	for _, token := range tokens {
+		if token != "" {
+			a.tokens = append(a.tokens, []byte(token))
+		}
+	}
+	return a
+}
+
+// LoadTokenAuth creates a TokenAuth that accepts the tokens listed in a
+// file, one per line. Blank lines and lines starting with # are ignored. An
+// error is returned if the file cannot be read or lists no tokens.
+func LoadTokenAuth(fileName string) (*TokenAuth, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file: %v", err)
+	}
+	defer file.Close()
+
+	var tokens []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read token file: %v", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens in %s", fileName)
+	}
+	return NewTokenAuth(tokens), nil
+}
+
+// Authenticate returns an Unauthenticated error unless the metadata of a
+// context carries an accepted token, in the form "Bearer <token>".
+func (a *TokenAuth) Authenticate(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if !strings.HasPrefix(value, "Bearer ") {
+			continue
+		}
+		token := strings.TrimPrefix(value, "Bearer ")
+		for _, accepted := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), accepted) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+// UnaryInterceptor rejects unary calls that are not authenticated.
+func (a *TokenAuth) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.Authenticate(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor rejects streaming calls that are not authenticated.
+func (a *TokenAuth) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.Authenticate(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// ServerOptions returns the options that install the interceptors of a
+// TokenAuth on a gRPC server.
+func (a *TokenAuth) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(a.UnaryInterceptor),
+		grpc.StreamInterceptor(a.StreamInterceptor),
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadTokenAuth", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "gateway")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads one token per line, skipping blank lines and comments", func() {
+		fileName := filepath.Join(dir, "tokens")
+		Expect(os.WriteFile(fileName, []byte("# dashboard\nfirst\n\n  second  \n"), 0600)).To(Succeed())
+
+		auth, err := LoadTokenAuth(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(auth.tokens).To(Equal([][]byte{[]byte("first"), []byte("second")}))
+	})
+
+	It("fails when the file lists no tokens", func() {
+		fileName := filepath.Join(dir, "tokens")
+		Expect(os.WriteFile(fileName, []byte("# nothing\n"), 0600)).To(Succeed())
+
+		_, err := LoadTokenAuth(fileName)
+		Expect(err).To(HaveOccurred())
+	})
+})
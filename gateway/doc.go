@@ -0,0 +1,22 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gateway implements a read-only gRPC API for LoadTest resources. It
+// serves summaries of tests and streams changes to their status to clients
+// such as dashboards and bots, which authenticate with a bearer token instead
+// of Kubernetes credentials and do not need to know about the LoadTest custom
+// resource.
+package gateway
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+	teststatus "github.com/grpc/test-infra/status"
+)
+
+// logChunkSize is the largest amount of log content sent in one message.
+const logChunkSize = 32 * 1024
+
+// StreamDriverLogs streams the log of the run container of the driver of a
+// test. A FailedPrecondition error is returned if the driver has not started,
+// so clients can retry once it is running.
+func (s *Server) StreamDriverLogs(req *pb.StreamDriverLogsRequest, stream pb.LoadTestService_StreamDriverLogsServer) error {
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "missing test name")
+	}
+	if req.GetTailLines() < 0 {
+		return status.Error(codes.InvalidArgument, "tail lines must not be negative")
+	}
+
+	loadTests, err := s.loadTests(req.GetNamespace())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	test, err := loadTests.Get(ctx, req.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return statusFromAPIError(err)
+	}
+	pod, err := s.driverPod(ctx, test)
+	if err != nil {
+		return err
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container: config.RunContainerName,
+		Follow:    req.GetFollow(),
+	}
+	if tailLines := req.GetTailLines(); tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+	logs, err := s.pods.Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return statusFromAPIError(err)
+	}
+	defer logs.Close()
+
+	buf := make([]byte, logChunkSize)
+	for {
+		n, err := logs.Read(buf)
+		if n > 0 {
+			content := make([]byte, n)
+			copy(content, buf[:n])
+			if err := stream.Send(&pb.LogChunk{Pod: pod.Name, Content: content}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return status.Errorf(codes.Unavailable, "failed to read log of pod %s: %v", pod.Name, err)
+		}
+	}
+}
+
+// driverPod returns the driver pod of the current run of a test. A
+// FailedPrecondition error is returned if the pod does not exist or its run
+// container has not started.
+func (s *Server) driverPod(ctx context.Context, test *grpcv1.LoadTest) (*corev1.Pod, error) {
+	list, err := s.pods.Pods(test.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", config.LoadTestLabel, test.Name),
+	})
+	if err != nil {
+		return nil, statusFromAPIError(err)
+	}
+	for _, pod := range teststatus.PodsForLoadTest(test, list.Items) {
+		if pod.Labels[config.RoleLabel] != config.DriverRole {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == config.RunContainerName && (cs.State.Running != nil || cs.State.Terminated != nil) {
+				return pod, nil
+			}
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "driver of test %s has not started", test.Name)
+	}
+	return nil, status.Errorf(codes.FailedPrecondition, "test %s has no driver pod", test.Name)
+}
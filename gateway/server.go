@@ -0,0 +1,327 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	clientset "github.com/grpc/test-infra/clientset"
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+)
+
+// Server implements the LoadTestService by reading LoadTest resources and
+// the logs of their pods from the Kubernetes API.
+type Server struct {
+	pb.UnimplementedLoadTestServiceServer
+
+	client     clientset.LoadTestInterface
+	pods       corev1types.PodsGetter
+	namespace  string
+	namespaces map[string]bool
+}
+
+// NewServer creates a server that reads tests with a client, and the logs of
+// their pods with a pods client. Requests that do not name a namespace read
+// the tests in the given namespace. Requests may also name one of the other
+// namespaces given; requests for any other namespace are denied, so tokens
+// cannot read tests outside the namespaces the gateway is meant to expose.
+func NewServer(client clientset.LoadTestInterface, pods corev1types.PodsGetter, namespace string, namespaces []string) *Server {
+	allowed := map[string]bool{namespace: true}
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return &Server{
+		client:     client,
+		pods:       pods,
+		namespace:  namespace,
+		namespaces: allowed,
+	}
+}
+
+// GetLoadTest returns the summary of a test.
+func (s *Server) GetLoadTest(ctx context.Context, req *pb.GetLoadTestRequest) (*pb.LoadTestSummary, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing test name")
+	}
+	loadTests, err := s.loadTests(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	test, err := loadTests.Get(ctx, req.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, statusFromAPIError(err)
+	}
+	return Summarize(test), nil
+}
+
+// ListLoadTests returns the summaries of the tests that match the label
+// selector of a request.
+func (s *Server) ListLoadTests(ctx context.Context, req *pb.ListLoadTestsRequest) (*pb.ListLoadTestsResponse, error) {
+	opts, err := listOptions(req)
+	if err != nil {
+		return nil, err
+	}
+	loadTests, err := s.loadTests(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	list, err := loadTests.List(ctx, opts)
+	if err != nil {
+		return nil, statusFromAPIError(err)
+	}
+	resp := &pb.ListLoadTestsResponse{}
+	for i := range list.Items {
+		resp.LoadTests = append(resp.LoadTests, Summarize(&list.Items[i]))
+	}
+	return resp, nil
+}
+
+// WatchLoadTests streams the summaries of the tests that match the label
+// selector of a request. The tests are listed first and sent as ADDED events.
+// After that, an event is sent each time the summary of a test changes;
+// changes that do not affect the summary are not sent. The watch is restarted
+// when the Kubernetes API closes it, and the tests are listed again when the
+// watch has fallen too far behind, so the stream continues until the client
+// cancels it.
+func (s *Server) WatchLoadTests(req *pb.ListLoadTestsRequest, stream pb.LoadTestService_WatchLoadTestsServer) error {
+	opts, err := listOptions(req)
+	if err != nil {
+		return err
+	}
+	loadTests, err := s.loadTests(req.GetNamespace())
+	if err != nil {
+		return err
+	}
+	w := &watcher{
+		loadTests: loadTests,
+		opts:      opts,
+		stream:    stream,
+		summaries: make(map[string]*pb.LoadTestSummary),
+	}
+	return w.run(stream.Context())
+}
+
+// loadTests returns a client for the tests in a namespace, or in the default
+// namespace of the server when it is empty. A PermissionDenied error is
+// returned if the server does not expose the namespace.
+func (s *Server) loadTests(namespace string) (clientset.LoadTestGetter, error) {
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	if !s.namespaces[namespace] {
+		return nil, status.Errorf(codes.PermissionDenied, "namespace %q is not served by this gateway", namespace)
+	}
+	return s.client.LoadTests(namespace), nil
+}
+
+// watcher streams the changes to the summaries of a set of tests.
+type watcher struct {
+	loadTests clientset.LoadTestGetter
+	opts      metav1.ListOptions
+	stream    pb.LoadTestService_WatchLoadTestsServer
+
+	// summaries holds the last summary sent for each test.
+	summaries map[string]*pb.LoadTestSummary
+}
+
+// run lists the tests and watches them until the context is cancelled or an
+// error occurs.
+func (w *watcher) run(ctx context.Context) error {
+	if err := w.relist(ctx); err != nil {
+		return err
+	}
+	for {
+		events, err := w.loadTests.Watch(ctx, w.opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return statusFromAPIError(err)
+		}
+		expired, err := w.forward(ctx, events)
+		events.Stop()
+		if err != nil {
+			return err
+		}
+		if expired {
+			if err := w.relist(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// relist lists the tests and sends an event for each test that was added,
+// modified or deleted since the last summaries were sent.
+func (w *watcher) relist(ctx context.Context) error {
+	opts := w.opts
+	opts.ResourceVersion = ""
+	list, err := w.loadTests.List(ctx, opts)
+	if err != nil {
+		return statusFromAPIError(err)
+	}
+	seen := make(map[string]bool)
+	for i := range list.Items {
+		seen[list.Items[i].Name] = true
+		if err := w.update(&list.Items[i]); err != nil {
+			return err
+		}
+	}
+	for name, summary := range w.summaries {
+		if !seen[name] {
+			if err := w.delete(summary); err != nil {
+				return err
+			}
+		}
+	}
+	w.opts.ResourceVersion = list.ResourceVersion
+	return nil
+}
+
+// forward sends the events of a watch until it is closed. It reports whether
+// the watch was closed because its resource version expired, in which case
+// the tests must be listed again.
+func (w *watcher) forward(ctx context.Context, events watch.Interface) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-events.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				test, ok := event.Object.(*grpcv1.LoadTest)
+				if !ok {
+					continue
+				}
+				w.opts.ResourceVersion = test.ResourceVersion
+				if err := w.update(test); err != nil {
+					return false, err
+				}
+			case watch.Deleted:
+				test, ok := event.Object.(*grpcv1.LoadTest)
+				if !ok {
+					continue
+				}
+				w.opts.ResourceVersion = test.ResourceVersion
+				if summary, ok := w.summaries[test.Name]; ok {
+					if err := w.delete(summary); err != nil {
+						return false, err
+					}
+				}
+			case watch.Bookmark:
+				if test, ok := event.Object.(*grpcv1.LoadTest); ok {
+					w.opts.ResourceVersion = test.ResourceVersion
+				}
+			case watch.Error:
+				err := apierrors.FromObject(event.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					return true, nil
+				}
+				return false, statusFromAPIError(err)
+			}
+		}
+	}
+}
+
+// update sends an ADDED or MODIFIED event for a test, unless its summary has
+// not changed since the last event.
+func (w *watcher) update(test *grpcv1.LoadTest) error {
+	summary := Summarize(test)
+	eventType := pb.LoadTestEvent_MODIFIED
+	if last, ok := w.summaries[test.Name]; !ok {
+		eventType = pb.LoadTestEvent_ADDED
+	} else if proto.Equal(last, summary) {
+		return nil
+	}
+	w.summaries[test.Name] = summary
+	return w.stream.Send(&pb.LoadTestEvent{Type: eventType, LoadTest: summary})
+}
+
+// delete sends a DELETED event for a test.
+func (w *watcher) delete(summary *pb.LoadTestSummary) error {
+	delete(w.summaries, summary.Name)
+	return w.stream.Send(&pb.LoadTestEvent{Type: pb.LoadTestEvent_DELETED, LoadTest: summary})
+}
+
+// Summarize returns the summary of a test.
+func Summarize(test *grpcv1.LoadTest) *pb.LoadTestSummary {
+	summary := &pb.LoadTestSummary{
+		Name:          test.Name,
+		Namespace:     test.Namespace,
+		Labels:        test.Labels,
+		State:         string(test.Status.State),
+		Reason:        test.Status.Reason,
+		Message:       test.Status.Message,
+		StartTime:     timestamp(test.Status.StartTime),
+		ScheduledTime: timestamp(test.Status.ScheduledTime),
+		StopTime:      timestamp(test.Status.StopTime),
+	}
+	if !test.CreationTimestamp.IsZero() {
+		summary.CreationTime = timestamppb.New(test.CreationTimestamp.Time)
+	}
+	return summary
+}
+
+// timestamp converts an optional Kubernetes time to a timestamp.
+func timestamp(t *metav1.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// listOptions returns the options used to list the tests of a request. An
+// InvalidArgument error is returned if the label selector cannot be parsed.
+func listOptions(req *pb.ListLoadTestsRequest) (metav1.ListOptions, error) {
+	if _, err := labels.Parse(req.GetLabelSelector()); err != nil {
+		return metav1.ListOptions{}, status.Errorf(codes.InvalidArgument, "invalid label selector: %v", err)
+	}
+	return metav1.ListOptions{LabelSelector: req.GetLabelSelector()}, nil
+}
+
+// statusFromAPIError converts an error returned by the Kubernetes API to a
+// gRPC status error.
+func statusFromAPIError(err error) error {
+	var code codes.Code
+	switch {
+	case apierrors.IsNotFound(err):
+		code = codes.NotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		code = codes.PermissionDenied
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		code = codes.InvalidArgument
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err), apierrors.IsServiceUnavailable(err):
+		code = codes.Unavailable
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
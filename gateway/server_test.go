@@ -0,0 +1,314 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	clientset "github.com/grpc/test-infra/clientset"
+	"github.com/grpc/test-infra/config"
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+)
+
+// fakeLoadTests serves a fixed list of tests and a fake watch. Only the
+// methods used by the server are implemented.
+type fakeLoadTests struct {
+	clientset.LoadTestGetter
+
+	namespace string
+	tests     []grpcv1.LoadTest
+	watches   chan *watch.FakeWatcher
+	lists     int
+}
+
+func (f *fakeLoadTests) LoadTests(namespace string) clientset.LoadTestGetter {
+	f.namespace = namespace
+	return f
+}
+
+func (f *fakeLoadTests) Get(ctx context.Context, name string, opts metav1.GetOptions) (*grpcv1.LoadTest, error) {
+	for i := range f.tests {
+		if f.tests[i].Name == name {
+			return &f.tests[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: "e2etest.grpc.io", Resource: "loadtests"}, name)
+}
+
+func (f *fakeLoadTests) List(ctx context.Context, opts metav1.ListOptions) (*grpcv1.LoadTestList, error) {
+	f.lists++
+	list := &grpcv1.LoadTestList{Items: f.tests}
+	list.ResourceVersion = "1"
+	return list, nil
+}
+
+func (f *fakeLoadTests) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w := watch.NewFakeWithChanSize(10, false)
+	f.watches <- w
+	return w, nil
+}
+
+func newTest(name string, state grpcv1.LoadTestState) grpcv1.LoadTest {
+	return grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "tests",
+			UID:       types.UID(name),
+			Labels:    map[string]string{"pool": "workers"},
+		},
+		Status: grpcv1.LoadTestStatus{State: state},
+	}
+}
+
+// newDriverPod returns a driver pod of a test, with its run container in the
+// given state.
+func newDriverPod(test *grpcv1.LoadTest, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.Name + "-driver",
+			Namespace: test.Namespace,
+			Labels: map[string]string{
+				config.LoadTestLabel: test.Name,
+				config.RoleLabel:     config.DriverRole,
+			},
+			OwnerReferences: []metav1.OwnerReference{{Name: test.Name, UID: test.UID}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: config.RunContainerName, State: state}},
+		},
+	}
+}
+
+var _ = Describe("Server", func() {
+	const token = "secret"
+
+	var fake *fakeLoadTests
+	var pods *kubefake.Clientset
+	var server *grpc.Server
+	var conn *grpc.ClientConn
+	var client pb.LoadTestServiceClient
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	BeforeEach(func() {
+		fake = &fakeLoadTests{
+			tests: []grpcv1.LoadTest{
+				newTest("first", grpcv1.Running),
+				newTest("second", grpcv1.Succeeded),
+			},
+			watches: make(chan *watch.FakeWatcher, 10),
+		}
+		pods = kubefake.NewSimpleClientset()
+
+		listener := bufconn.Listen(1 << 20)
+		server = grpc.NewServer(NewTokenAuth([]string{token}).ServerOptions()...)
+		pb.RegisterLoadTestServiceServer(server, NewServer(fake, pods.CoreV1(), "tests", []string{"other"}))
+		go server.Serve(listener)
+
+		var err error
+		conn, err = grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).ToNot(HaveOccurred())
+		client = pb.NewLoadTestServiceClient(conn)
+
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	})
+
+	AfterEach(func() {
+		cancel()
+		conn.Close()
+		server.Stop()
+	})
+
+	It("rejects calls without a valid token", func() {
+		_, err := client.ListLoadTests(context.Background(), &pb.ListLoadTestsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+
+		badCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+		stream, err := client.WatchLoadTests(badCtx, &pb.ListLoadTestsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+
+	It("lists summaries of tests", func() {
+		resp, err := client.ListLoadTests(ctx, &pb.ListLoadTestsRequest{LabelSelector: "pool=workers"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.LoadTests).To(HaveLen(2))
+		Expect(resp.LoadTests[0].Name).To(Equal("first"))
+		Expect(resp.LoadTests[0].State).To(Equal(string(grpcv1.Running)))
+		Expect(resp.LoadTests[0].Labels).To(HaveKeyWithValue("pool", "workers"))
+		Expect(fake.namespace).To(Equal("tests"))
+	})
+
+	It("rejects invalid label selectors", func() {
+		_, err := client.ListLoadTests(ctx, &pb.ListLoadTestsRequest{LabelSelector: "pool in (workers"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("gets a test in the requested namespace", func() {
+		summary, err := client.GetLoadTest(ctx, &pb.GetLoadTestRequest{Namespace: "other", Name: "second"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary.State).To(Equal(string(grpcv1.Succeeded)))
+		Expect(fake.namespace).To(Equal("other"))
+
+		_, err = client.GetLoadTest(ctx, &pb.GetLoadTestRequest{Name: "missing"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("denies requests for namespaces that are not served", func() {
+		_, err := client.GetLoadTest(ctx, &pb.GetLoadTestRequest{Namespace: "private", Name: "second"})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		_, err = client.ListLoadTests(ctx, &pb.ListLoadTestsRequest{Namespace: "private"})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		stream, err := client.WatchLoadTests(ctx, &pb.ListLoadTestsRequest{Namespace: "private"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		logs, err := client.StreamDriverLogs(ctx, &pb.StreamDriverLogsRequest{Namespace: "private", Name: "second"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = logs.Recv()
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		Expect(fake.namespace).To(BeEmpty())
+	})
+
+	It("streams existing tests and changes to their summaries", func() {
+		stream, err := client.WatchLoadTests(ctx, &pb.ListLoadTestsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, name := range []string{"first", "second"} {
+			event, err := stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(event.Type).To(Equal(pb.LoadTestEvent_ADDED))
+			Expect(event.LoadTest.Name).To(Equal(name))
+		}
+
+		var w *watch.FakeWatcher
+		Eventually(fake.watches).Should(Receive(&w))
+
+		unchanged := newTest("first", grpcv1.Running)
+		unchanged.Annotations = map[string]string{"note": "ignored"}
+		w.Modify(&unchanged)
+		finished := newTest("first", grpcv1.Succeeded)
+		w.Modify(&finished)
+		deleted := newTest("second", grpcv1.Succeeded)
+		w.Delete(&deleted)
+
+		event, err := stream.Recv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Type).To(Equal(pb.LoadTestEvent_MODIFIED))
+		Expect(event.LoadTest.State).To(Equal(string(grpcv1.Succeeded)))
+
+		event, err = stream.Recv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Type).To(Equal(pb.LoadTestEvent_DELETED))
+		Expect(event.LoadTest.Name).To(Equal("second"))
+	})
+
+	It("lists tests again when the watch expires", func() {
+		stream, err := client.WatchLoadTests(ctx, &pb.ListLoadTestsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		for i := 0; i < 2; i++ {
+			_, err := stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		var w *watch.FakeWatcher
+		Eventually(fake.watches).Should(Receive(&w))
+		fake.tests = []grpcv1.LoadTest{newTest("first", grpcv1.Errored)}
+		expired := apierrors.NewResourceExpired("too old resource version")
+		w.Error(&expired.ErrStatus)
+
+		event, err := stream.Recv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Type).To(Equal(pb.LoadTestEvent_MODIFIED))
+		Expect(event.LoadTest.State).To(Equal(string(grpcv1.Errored)))
+
+		event, err = stream.Recv()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Type).To(Equal(pb.LoadTestEvent_DELETED))
+		Expect(event.LoadTest.Name).To(Equal("second"))
+		Expect(fake.lists).To(Equal(2))
+	})
+
+	It("streams the log of the driver of a test", func() {
+		driver := newDriverPod(&fake.tests[0], corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+		_, err := pods.CoreV1().Pods("tests").Create(ctx, driver, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		stream, err := client.StreamDriverLogs(ctx, &pb.StreamDriverLogsRequest{Name: "first", Follow: true, TailLines: 10})
+		Expect(err).ToNot(HaveOccurred())
+		var content []byte
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(chunk.Pod).To(Equal(driver.Name))
+			content = append(content, chunk.Content...)
+		}
+		Expect(string(content)).To(Equal("fake logs"))
+	})
+
+	It("returns an error when the driver has not started", func() {
+		stream, err := client.StreamDriverLogs(ctx, &pb.StreamDriverLogsRequest{Name: "first"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		driver := newDriverPod(&fake.tests[0], corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}})
+		_, err = pods.CoreV1().Pods("tests").Create(ctx, driver, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		stream, err = client.StreamDriverLogs(ctx, &pb.StreamDriverLogsRequest{Name: "first"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		stream, err = client.StreamDriverLogs(ctx, &pb.StreamDriverLogsRequest{Name: "missing"})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = stream.Recv()
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+})
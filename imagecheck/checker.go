@@ -0,0 +1,282 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// manifestMediaTypes lists the manifest formats accepted when querying a
+// registry. Registries may return a 404 for a manifest that exists if its
+// media type is not listed.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// Checker determines if container images exist.
+type Checker interface {
+	// Exists returns true if the image can be found in its registry. It
+	// returns false if the registry reports that the image does not exist.
+	// An error is returned if the existence of the image could not be
+	// determined, which includes authorization failures.
+	Exists(ctx context.Context, image string) (bool, error)
+}
+
+// DefaultErrorTTL is how long a failure to check an image is reused by
+// default. It is short, so an unreachable registry does not delay every
+// reconcile, but the image is checked again soon after the registry recovers.
+const DefaultErrorTTL = 30 * time.Second
+
+// RegistryChecker is a Checker that sends HEAD requests for image manifests
+// using the Docker Registry HTTP API V2. It supports anonymous bearer token
+// authorization, which is sufficient for public images.
+//
+// Registries may hide private images from anonymous clients, so an image is
+// only reported as missing when the registry answers without requiring a
+// token. A 401, 403 or 404 response to a request with an anonymous token is
+// reported as an error, since the image may exist in a private repository.
+//
+// Results are cached for the duration of the TTL, so repeated checks of the
+// same image do not contact the registry. Errors are cached for the duration
+// of the ErrorTTL.
+type RegistryChecker struct {
+	// Client is the HTTP client used to contact registries.
+	Client *http.Client
+
+	// TTL is how long the result of a check is reused.
+	TTL time.Duration
+
+	// ErrorTTL is how long an error from a check is reused.
+	ErrorTTL time.Duration
+
+	mux   sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// cacheEntry records the existence of an image, or the error that prevented
+// it from being checked, and when it was checked.
+type cacheEntry struct {
+	exists    bool
+	err       error
+	checkedAt time.Time
+}
+
+// NewRegistryChecker creates a RegistryChecker with a client that times out
+// after the specified duration. Errors are cached for DefaultErrorTTL.
+func NewRegistryChecker(timeout time.Duration, ttl time.Duration) *RegistryChecker {
+	return &RegistryChecker{
+		Client:   &http.Client{Timeout: timeout},
+		TTL:      ttl,
+		ErrorTTL: DefaultErrorTTL,
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+// Exists implements the Checker interface.
+func (rc *RegistryChecker) Exists(ctx context.Context, image string) (bool, error) {
+	if entry, ok := rc.cached(image); ok {
+		return entry.exists, entry.err
+	}
+
+	exists, err := rc.check(ctx, image)
+	if ctx.Err() == nil {
+		// A cancelled check says nothing about the registry.
+		rc.store(image, exists, err)
+	}
+	return exists, err
+}
+
+// check contacts the registry of an image to determine if the image exists.
+func (rc *RegistryChecker) check(ctx context.Context, image string) (bool, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return false, err
+	}
+
+	manifestURL := "https://" + ref.String()
+
+	resp, err := rc.head(ctx, manifestURL, "")
+	if err != nil {
+		return false, err
+	}
+
+	authorized := false
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := rc.token(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return false, fmt.Errorf("could not authorize with registry %q: %v", ref.Registry, err)
+		}
+
+		resp, err = rc.head(ctx, manifestURL, token)
+		if err != nil {
+			return false, err
+		}
+		authorized = true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		if authorized {
+			return false, fmt.Errorf("registry did not find image %q with an anonymous token, it may be private", image)
+		}
+		return false, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, fmt.Errorf("not authorized to check image %q, it may be private: %s", image, resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status from registry for image %q: %s", image, resp.Status)
+	}
+}
+
+// head sends a HEAD request for a manifest, optionally using a bearer token.
+func (rc *RegistryChecker) head(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := rc.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request for manifest %q failed: %v", manifestURL, err)
+	}
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// token requests an anonymous bearer token, using the realm, service and scope
+// from a WWW-Authenticate challenge.
+func (rc *RegistryChecker) token(ctx context.Context, challenge string) (string, error) {
+	params, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %v", params["realm"], err)
+	}
+
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := rc.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned status %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("could not decode token response: %v", err)
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// cached returns the cached existence of an image, or the cached error from
+// checking it, if it has not expired.
+func (rc *RegistryChecker) cached(image string) (cacheEntry, bool) {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	entry, ok := rc.cache[image]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	ttl := rc.TTL
+	if entry.err != nil {
+		ttl = rc.ErrorTTL
+	}
+	if time.Since(entry.checkedAt) > ttl {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store records the existence of an image, or the error from checking it, in
+// the cache.
+func (rc *RegistryChecker) store(image string, exists bool, err error) {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	if rc.cache == nil {
+		rc.cache = make(map[string]cacheEntry)
+	}
+	rc.cache[image] = cacheEntry{exists: exists, err: err, checkedAt: time.Now()}
+}
+
+// parseBearerChallenge parses the parameters of a WWW-Authenticate header that
+// uses the Bearer scheme, such as:
+//
+//	Bearer realm="https://auth.example.com/token",service="example.com"
+func parseBearerChallenge(challenge string) (map[string]string, error) {
+	const scheme = "bearer "
+	if len(challenge) < len(scheme) || !strings.EqualFold(challenge[:len(scheme)], scheme) {
+		return nil, fmt.Errorf("unsupported authorization challenge %q", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, part := range strings.Split(challenge[len(scheme):], ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+
+	if params["realm"] == "" {
+		return nil, fmt.Errorf("authorization challenge %q is missing a realm", challenge)
+	}
+	return params, nil
+}
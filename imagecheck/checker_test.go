@@ -0,0 +1,141 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RegistryChecker", func() {
+	var server *httptest.Server
+	var checker *RegistryChecker
+	var registry string
+	var requireToken bool
+	var manifestStatus int
+	var manifestRequests int
+
+	BeforeEach(func() {
+		requireToken = false
+		manifestStatus = 0
+		manifestRequests = 0
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"token": "secret"}`)
+		})
+		mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+			manifestRequests++
+			if requireToken && r.Header.Get("Authorization") != "Bearer secret" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="test"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if manifestStatus != 0 {
+				w.WriteHeader(manifestStatus)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/manifests/present") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		server = httptest.NewTLSServer(mux)
+		registry = strings.TrimPrefix(server.URL, "https://")
+
+		checker = NewRegistryChecker(time.Second, time.Minute)
+		checker.Client = server.Client()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reports images that exist", func() {
+		exists, err := checker.Exists(context.Background(), registry+"/repo/image:present")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("reports images that do not exist", func() {
+		exists, err := checker.Exists(context.Background(), registry+"/repo/image:absent")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("requests a token when the registry requires one", func() {
+		requireToken = true
+
+		exists, err := checker.Exists(context.Background(), registry+"/repo/image:present")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("caches results", func() {
+		image := registry + "/repo/image:present"
+		_, err := checker.Exists(context.Background(), image)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = checker.Exists(context.Background(), image)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifestRequests).To(Equal(1))
+	})
+
+	It("does not report images as missing when a token was required", func() {
+		requireToken = true
+
+		_, err := checker.Exists(context.Background(), registry+"/repo/image:absent")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the registry denies access", func() {
+		for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+			manifestStatus = status
+			checker = NewRegistryChecker(time.Second, time.Minute)
+			checker.Client = server.Client()
+
+			_, err := checker.Exists(context.Background(), registry+"/repo/image:present")
+			Expect(err).To(HaveOccurred(), "status %d", status)
+		}
+	})
+
+	It("caches errors for the error TTL", func() {
+		manifestStatus = http.StatusInternalServerError
+		image := registry + "/repo/image:present"
+
+		_, err := checker.Exists(context.Background(), image)
+		Expect(err).To(HaveOccurred())
+		_, err = checker.Exists(context.Background(), image)
+		Expect(err).To(HaveOccurred())
+		Expect(manifestRequests).To(Equal(1))
+
+		checker.ErrorTTL = 0
+		manifestStatus = 0
+		exists, err := checker.Exists(context.Background(), image)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		Expect(manifestRequests).To(Equal(2))
+	})
+})
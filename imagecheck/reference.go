@@ -0,0 +1,99 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dockerHubRegistry is the host that serves the registry API for images
+// without an explicit registry, such as "golang:1.17".
+const dockerHubRegistry = "registry-1.docker.io"
+
+// Reference is a parsed container image name.
+type Reference struct {
+	// Registry is the host (and optional port) of the registry that serves
+	// the image.
+	Registry string
+
+	// Repository is the path of the image within the registry.
+	Repository string
+
+	// Reference is the tag or digest that identifies a specific manifest.
+	Reference string
+}
+
+// String returns the URL of the manifest for the image.
+func (r *Reference) String() string {
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", r.Registry, r.Repository, r.Reference)
+}
+
+// ParseReference accepts the name of a container image, as it would appear on
+// a container spec, and returns its parts. Images without a registry are
+// assumed to be on Docker Hub. Images without a tag or digest are assumed to
+// use the "latest" tag.
+func ParseReference(image string) (*Reference, error) {
+	if image == "" {
+		return nil, fmt.Errorf("image name is empty")
+	}
+
+	ref := &Reference{Registry: dockerHubRegistry}
+	name := image
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+		if ref.Reference == "" {
+			return nil, fmt.Errorf("image %q has an empty digest", image)
+		}
+	}
+
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			name = name[i+1:]
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		tag := name[i+1:]
+		name = name[:i]
+		if tag == "" {
+			return nil, fmt.Errorf("image %q has an empty tag", image)
+		}
+		if ref.Reference == "" {
+			ref.Reference = tag
+		}
+	}
+
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("image %q has an empty repository", image)
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+
+	return ref, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagecheck
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseReference", func() {
+	It("errors on an empty image", func() {
+		_, err := ParseReference("")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("defaults to docker hub and the latest tag", func() {
+		ref, err := ParseReference("golang")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ref).To(Equal(&Reference{
+			Registry:   dockerHubRegistry,
+			Repository: "library/golang",
+			Reference:  "latest",
+		}))
+	})
+
+	It("keeps the namespace of docker hub images", func() {
+		ref, err := ParseReference("gradle/custom:jdk8")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ref.Registry).To(Equal(dockerHubRegistry))
+		Expect(ref.Repository).To(Equal("gradle/custom"))
+		Expect(ref.Reference).To(Equal("jdk8"))
+	})
+
+	It("parses an explicit registry with a port", func() {
+		ref, err := ParseReference("localhost:5000/test-infra/driver:v1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ref.Registry).To(Equal("localhost:5000"))
+		Expect(ref.Repository).To(Equal("test-infra/driver"))
+		Expect(ref.Reference).To(Equal("v1"))
+	})
+
+	It("prefers a digest over a tag", func() {
+		ref, err := ParseReference("us-docker.pkg.dev/grpc-testing/bazel:abc@sha256:1234")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ref.Registry).To(Equal("us-docker.pkg.dev"))
+		Expect(ref.Repository).To(Equal("grpc-testing/bazel"))
+		Expect(ref.Reference).To(Equal("sha256:1234"))
+	})
+
+	It("errors on an empty tag", func() {
+		_, err := ParseReference("gcr.io/project/image:")
+		Expect(err).To(HaveOccurred())
+	})
+})
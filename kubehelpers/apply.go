@@ -0,0 +1,72 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// ApplyPatchOptions returns the options for server-side apply requests made
+// by the controller. The controller forces ownership of the fields it sets,
+// so that changes made to them by other field managers are reverted.
+func ApplyPatchOptions() []client.PatchOption {
+	return []client.PatchOption{
+		client.FieldOwner(config.ControllerName),
+		client.ForceOwnership,
+	}
+}
+
+// PrepareForApply accepts an object and a scheme, and prepares the object to
+// be sent in a server-side apply request. It sets the type metadata of the
+// object, which apply requests require, and adds the ManagedByLabel. Fields
+// that must not be part of an apply request, such as the resource version,
+// are cleared.
+func PrepareForApply(obj client.Object, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[config.ManagedByLabel] = config.ControllerName
+	obj.SetLabels(labels)
+
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	return nil
+}
+
+// LabelsDrifted accepts the desired labels of an object and its actual
+// labels. It returns true if any desired label is missing or has a different
+// value in the actual labels. Additional labels are not considered drift,
+// since other field managers may add them.
+func LabelsDrifted(desired, actual map[string]string) bool {
+	for key, value := range desired {
+		if actualValue, ok := actual[key]; !ok || actualValue != value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("PrepareForApply", func() {
+	It("sets the type metadata and the managed-by label", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-server-0",
+				Namespace:       "default",
+				ResourceVersion: "42",
+				Labels: map[string]string{
+					config.LoadTestLabel: "test",
+				},
+			},
+		}
+
+		Expect(PrepareForApply(pod, clientgoscheme.Scheme)).To(Succeed())
+		Expect(pod.APIVersion).To(Equal("v1"))
+		Expect(pod.Kind).To(Equal("Pod"))
+		Expect(pod.ResourceVersion).To(BeEmpty())
+		Expect(pod.Labels).To(Equal(map[string]string{
+			config.LoadTestLabel:  "test",
+			config.ManagedByLabel: config.ControllerName,
+		}))
+	})
+
+	It("initializes missing labels", func() {
+		cfgMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+		}
+
+		Expect(PrepareForApply(cfgMap, clientgoscheme.Scheme)).To(Succeed())
+		Expect(cfgMap.Kind).To(Equal("ConfigMap"))
+		Expect(cfgMap.Labels).To(HaveKeyWithValue(config.ManagedByLabel, config.ControllerName))
+	})
+})
+
+var _ = Describe("LabelsDrifted", func() {
+	desired := map[string]string{
+		config.LoadTestLabel: "test",
+		config.RoleLabel:     config.ServerRole,
+	}
+
+	It("returns false when all desired labels are present", func() {
+		actual := map[string]string{
+			config.LoadTestLabel: "test",
+			config.RoleLabel:     config.ServerRole,
+			"extra":              "label",
+		}
+		Expect(LabelsDrifted(desired, actual)).To(BeFalse())
+	})
+
+	It("returns true when a desired label is missing", func() {
+		actual := map[string]string{
+			config.LoadTestLabel: "test",
+		}
+		Expect(LabelsDrifted(desired, actual)).To(BeTrue())
+	})
+
+	It("returns true when a desired label has a different value", func() {
+		actual := map[string]string{
+			config.LoadTestLabel: "test",
+			config.RoleLabel:     config.ClientRole,
+		}
+		Expect(LabelsDrifted(desired, actual)).To(BeTrue())
+	})
+})
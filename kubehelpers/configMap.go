@@ -18,6 +18,11 @@ package kubehelpers
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grpc/test-infra/config"
 )
 
 // UpdateConfigMapWithServerPort accepts a server port string and a scenarioString string.
@@ -47,3 +52,87 @@ func UpdateConfigMapWithServerPort(port string, scenarioString string) (string,
 	updatedScenarios = string(scenariosJSONByte)
 	return updatedScenarios, nil
 }
+
+// UpdateConfigMapWithScenarioOverrides accepts the annotations of a load test
+// and a scenarioString string. It returns an updated scenarioString where the
+// warmup_seconds and benchmark_seconds fields are replaced by the values of
+// the WarmupSecondsAnnotation and BenchmarkSecondsAnnotation annotations, when
+// present. This allows the same scenario to be used for quick smoke runs and
+// long soak runs. Currently only supports single scenario.
+func UpdateConfigMapWithScenarioOverrides(annotations map[string]string, scenarioString string) (string, error) {
+	overrides := []struct {
+		annotation string
+		field      string
+		min        int
+	}{
+		{config.WarmupSecondsAnnotation, "warmup_seconds", 0},
+		{config.BenchmarkSecondsAnnotation, "benchmark_seconds", 1},
+	}
+
+	var jsonScenarioMap map[string]map[string]json.RawMessage
+	updated := false
+	for _, o := range overrides {
+		value, ok := annotations[o.annotation]
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("annotation %q has invalid value %q: %v", o.annotation, value, err)
+		}
+		if seconds < o.min {
+			return "", fmt.Errorf("annotation %q has invalid value %q: must be at least %d", o.annotation, value, o.min)
+		}
+		if jsonScenarioMap == nil {
+			if err := json.Unmarshal([]byte(scenarioString), &jsonScenarioMap); err != nil {
+				return "", err
+			}
+			if jsonScenarioMap["scenarios"] == nil {
+				return "", fmt.Errorf("no scenario found to override %q", o.field)
+			}
+		}
+		jsonScenarioMap["scenarios"][o.field] = json.RawMessage(strconv.Itoa(seconds))
+		updated = true
+	}
+	if !updated {
+		return scenarioString, nil
+	}
+
+	scenariosJSONByte, err := json.Marshal(jsonScenarioMap)
+	if err != nil {
+		return "", err
+	}
+	return string(scenariosJSONByte), nil
+}
+
+// ScenarioDurations accepts the annotations of a load test and a
+// scenarioString string. It returns the warmup and benchmark durations of the
+// scenario, after the overrides from the WarmupSecondsAnnotation and
+// BenchmarkSecondsAnnotation annotations are applied. Together, these
+// durations describe the window in which the workers are under load.
+// Currently only supports single scenario.
+func ScenarioDurations(annotations map[string]string, scenarioString string) (warmup time.Duration, benchmark time.Duration, err error) {
+	scenarioString, err = UpdateConfigMapWithScenarioOverrides(annotations, scenarioString)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var jsonScenarioMap map[string]struct {
+		WarmupSeconds    int `json:"warmup_seconds"`
+		BenchmarkSeconds int `json:"benchmark_seconds"`
+	}
+	if err := json.Unmarshal([]byte(scenarioString), &jsonScenarioMap); err != nil {
+		return 0, 0, err
+	}
+	scenario, ok := jsonScenarioMap["scenarios"]
+	if !ok {
+		return 0, 0, fmt.Errorf("no scenario found")
+	}
+	if scenario.BenchmarkSeconds < 1 {
+		return 0, 0, fmt.Errorf("scenario has invalid benchmark_seconds %d: must be at least 1", scenario.BenchmarkSeconds)
+	}
+
+	warmup = time.Duration(scenario.WarmupSeconds) * time.Second
+	benchmark = time.Duration(scenario.BenchmarkSeconds) * time.Second
+	return warmup, benchmark, nil
+}
@@ -21,6 +21,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"strings"
+	"time"
+
+	"github.com/grpc/test-infra/config"
 )
 
 var _ = Describe("IsPSMTest", func() {
@@ -36,3 +39,90 @@ var _ = Describe("IsPSMTest", func() {
 	})
 
 })
+
+var _ = Describe("UpdateConfigMapWithScenarioOverrides", func() {
+	var scenarios string
+
+	BeforeEach(func() {
+		scenarios = "{\"scenarios\":{\"name\":\"scenario-1\",\"warmup_seconds\":5,\"benchmark_seconds\":30}}"
+	})
+
+	It("returns the scenarios unchanged when there are no annotations", func() {
+		actual, err := UpdateConfigMapWithScenarioOverrides(nil, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(scenarios))
+	})
+
+	It("overrides warmup and benchmark seconds", func() {
+		annotations := map[string]string{
+			config.WarmupSecondsAnnotation:    "0",
+			config.BenchmarkSecondsAnnotation: "1800",
+		}
+		actual, err := UpdateConfigMapWithScenarioOverrides(annotations, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(ContainSubstring("\"warmup_seconds\":0"))
+		Expect(actual).To(ContainSubstring("\"benchmark_seconds\":1800"))
+		Expect(actual).To(ContainSubstring("\"name\":\"scenario-1\""))
+	})
+
+	It("overrides only the annotated field", func() {
+		annotations := map[string]string{
+			config.BenchmarkSecondsAnnotation: "5",
+		}
+		actual, err := UpdateConfigMapWithScenarioOverrides(annotations, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(ContainSubstring("\"warmup_seconds\":5"))
+		Expect(actual).To(ContainSubstring("\"benchmark_seconds\":5"))
+	})
+
+	It("returns an error when an annotation is not a number", func() {
+		annotations := map[string]string{
+			config.WarmupSecondsAnnotation: "five",
+		}
+		_, err := UpdateConfigMapWithScenarioOverrides(annotations, scenarios)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when benchmark seconds is not positive", func() {
+		annotations := map[string]string{
+			config.BenchmarkSecondsAnnotation: "0",
+		}
+		_, err := UpdateConfigMapWithScenarioOverrides(annotations, scenarios)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ScenarioDurations", func() {
+	var scenarios string
+
+	BeforeEach(func() {
+		scenarios = "{\"scenarios\":{\"name\":\"scenario-1\",\"warmup_seconds\":5,\"benchmark_seconds\":30}}"
+	})
+
+	It("returns the durations of the scenario", func() {
+		warmup, benchmark, err := ScenarioDurations(nil, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warmup).To(Equal(5 * time.Second))
+		Expect(benchmark).To(Equal(30 * time.Second))
+	})
+
+	It("applies the overrides from annotations", func() {
+		annotations := map[string]string{
+			config.BenchmarkSecondsAnnotation: "120",
+		}
+		warmup, benchmark, err := ScenarioDurations(annotations, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warmup).To(Equal(5 * time.Second))
+		Expect(benchmark).To(Equal(120 * time.Second))
+	})
+
+	It("returns an error when the scenario has no benchmark duration", func() {
+		_, _, err := ScenarioDurations(nil, "{\"scenarios\":{\"name\":\"scenario-1\"}}")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when there is no scenario", func() {
+		_, _, err := ScenarioDurations(nil, "{}")
+		Expect(err).To(HaveOccurred())
+	})
+})
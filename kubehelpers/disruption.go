@@ -0,0 +1,52 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// PodDisruptionBudgetForLoadTest accepts a load test and returns a
+// PodDisruptionBudget that matches all of its pods. The budget does not allow
+// any pods to be voluntarily evicted, which prevents node drains and the
+// cluster autoscaler from interrupting a test while it is running.
+//
+// The returned budget shares the name and namespace of the load test. The
+// caller is responsible for setting an owner reference on it.
+func PodDisruptionBudgetForLoadTest(test *grpcv1.LoadTest) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(0)
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.Name,
+			Namespace: test.Namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					config.LoadTestLabel: test.Name,
+				},
+			},
+		},
+	}
+}
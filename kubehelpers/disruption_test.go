@@ -0,0 +1,59 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("PodDisruptionBudgetForLoadTest", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+			},
+		}
+	})
+
+	It("shares the name and namespace of the test", func() {
+		pdb := PodDisruptionBudgetForLoadTest(test)
+		Expect(pdb.Name).To(Equal(test.Name))
+		Expect(pdb.Namespace).To(Equal(test.Namespace))
+	})
+
+	It("selects pods by the test name label", func() {
+		pdb := PodDisruptionBudgetForLoadTest(test)
+		Expect(pdb.Spec.Selector).ToNot(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{
+			config.LoadTestLabel: test.Name,
+		}))
+	})
+
+	It("does not allow any pods to be unavailable", func() {
+		pdb := PodDisruptionBudgetForLoadTest(test)
+		Expect(pdb.Spec.MaxUnavailable).ToNot(BeNil())
+		Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(0))
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// PodIPForFamily accepts a pod and an IP family, and returns the IP address of
+// the pod in that family. Pods in dual-stack clusters have an address in each
+// family. When the family is IPv4 or IPv6 and the pod has an address in that
+// family, it is returned. Otherwise, the primary IP address of the pod is
+// returned.
+func PodIPForFamily(pod *corev1.Pod, family grpcv1.IPFamily) string {
+	if family == grpcv1.IPv4Family || family == grpcv1.IPv6Family {
+		for _, podIP := range pod.Status.PodIPs {
+			ip := net.ParseIP(podIP.IP)
+			if ip == nil {
+				continue
+			}
+			if isIPv4 := ip.To4() != nil; isIPv4 == (family == grpcv1.IPv4Family) {
+				return podIP.IP
+			}
+		}
+	}
+	return pod.Status.PodIP
+}
+
+// setServiceIPFamilies accepts the spec of a Service and an IP family. It sets
+// the IP families and IP family policy of the Service to match the family.
+// When the family is empty, the spec is not changed and the defaults of the
+// cluster apply.
+func setServiceIPFamilies(spec *corev1.ServiceSpec, family grpcv1.IPFamily) {
+	var families []corev1.IPFamily
+	policy := corev1.IPFamilyPolicySingleStack
+
+	switch family {
+	case grpcv1.IPv4Family:
+		families = []corev1.IPFamily{corev1.IPv4Protocol}
+	case grpcv1.IPv6Family:
+		families = []corev1.IPFamily{corev1.IPv6Protocol}
+	case grpcv1.DualStackFamily:
+		families = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+		policy = corev1.IPFamilyPolicyRequireDualStack
+	default:
+		return
+	}
+
+	spec.IPFamilies = families
+	spec.IPFamilyPolicy = &policy
+}
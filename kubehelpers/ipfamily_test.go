@@ -0,0 +1,97 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("PodIPForFamily", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			Status: corev1.PodStatus{
+				PodIP: "10.0.0.5",
+				PodIPs: []corev1.PodIP{
+					{IP: "10.0.0.5"},
+					{IP: "fd00::5"},
+				},
+			},
+		}
+	})
+
+	It("returns the IPv6 address of a dual-stack pod when IPv6 is requested", func() {
+		Expect(PodIPForFamily(pod, grpcv1.IPv6Family)).To(Equal("fd00::5"))
+	})
+
+	It("returns the IPv4 address of a dual-stack pod when IPv4 is requested", func() {
+		pod.Status.PodIP = "fd00::5"
+		pod.Status.PodIPs = []corev1.PodIP{{IP: "fd00::5"}, {IP: "10.0.0.5"}}
+		Expect(PodIPForFamily(pod, grpcv1.IPv4Family)).To(Equal("10.0.0.5"))
+	})
+
+	It("returns the primary address when no family is requested", func() {
+		Expect(PodIPForFamily(pod, "")).To(Equal("10.0.0.5"))
+		Expect(PodIPForFamily(pod, grpcv1.DualStackFamily)).To(Equal("10.0.0.5"))
+	})
+
+	It("returns the primary address when the pod has no address in the family", func() {
+		pod.Status.PodIPs = []corev1.PodIP{{IP: "10.0.0.5"}}
+		Expect(PodIPForFamily(pod, grpcv1.IPv6Family)).To(Equal("10.0.0.5"))
+	})
+})
+
+var _ = Describe("Service IP families", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+			},
+		}
+	})
+
+	It("uses the defaults of the cluster when the family is unset", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.IPFamilies).To(BeEmpty())
+		Expect(svc.Spec.IPFamilyPolicy).To(BeNil())
+	})
+
+	It("requests a single IPv6 family", func() {
+		test.Spec.IPFamily = grpcv1.IPv6Family
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol}))
+		Expect(svc.Spec.IPFamilyPolicy).ToNot(BeNil())
+		Expect(*svc.Spec.IPFamilyPolicy).To(Equal(corev1.IPFamilyPolicySingleStack))
+	})
+
+	It("requires dual-stack with IPv6 as the primary family", func() {
+		test.Spec.IPFamily = grpcv1.DualStackFamily
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}))
+		Expect(svc.Spec.IPFamilyPolicy).ToNot(BeNil())
+		Expect(*svc.Spec.IPFamilyPolicy).To(Equal(corev1.IPFamilyPolicyRequireDualStack))
+	})
+})
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// MaxLoadSteps is the largest number of steps that a load profile may have.
+// It keeps a ramp with a small increment from multiplying the scenarios of a
+// test beyond what could run within its timeout.
+const MaxLoadSteps = 100
+
+// LoadSteps returns the loads, in queries per second per client, that a
+// Poisson load profile offers in turn. An error is returned if the profile is
+// not valid.
+func LoadSteps(profile *grpcv1.LoadProfile) ([]int32, error) {
+	switch profile.Type {
+	case grpcv1.ClosedLoopLoad:
+		if profile.OfferedLoad != 0 || len(profile.Steps) > 0 || profile.Ramp != nil {
+			return nil, fmt.Errorf("closed loop load does not accept an offered load, steps or a ramp")
+		}
+		return nil, nil
+	case grpcv1.PoissonLoad:
+	default:
+		return nil, fmt.Errorf("unknown load profile type %q", profile.Type)
+	}
+
+	set := 0
+	var steps []int32
+	if profile.OfferedLoad != 0 {
+		set++
+		steps = []int32{profile.OfferedLoad}
+	}
+	if len(profile.Steps) > 0 {
+		set++
+		steps = profile.Steps
+	}
+	if ramp := profile.Ramp; ramp != nil {
+		set++
+		if ramp.Increment < 1 {
+			return nil, fmt.Errorf("ramp increment must be positive")
+		}
+		if ramp.From > ramp.To {
+			return nil, fmt.Errorf("ramp starts at %d, above its end at %d", ramp.From, ramp.To)
+		}
+		if count := (ramp.To-ramp.From)/ramp.Increment + 1; count > MaxLoadSteps {
+			return nil, fmt.Errorf("ramp has %d steps, more than the limit of %d", count, MaxLoadSteps)
+		}
+		steps = nil
+		for load := ramp.From; load <= ramp.To; load += ramp.Increment {
+			steps = append(steps, load)
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("poisson load requires exactly one of an offered load, steps or a ramp")
+	}
+	if len(steps) > MaxLoadSteps {
+		return nil, fmt.Errorf("load profile has %d steps, more than the limit of %d", len(steps), MaxLoadSteps)
+	}
+	for _, load := range steps {
+		if load < 1 {
+			return nil, fmt.Errorf("offered load %d must be positive", load)
+		}
+	}
+	return steps, nil
+}
+
+// UpdateConfigMapWithLoadProfile accepts a load profile and a scenarioString
+// string. It returns an updated scenarioString where the load_params of the
+// client_config of each scenario are replaced according to the profile. With a
+// Poisson profile of several steps, each scenario is repeated once per step,
+// and the name of each copy is suffixed with its load, such as "-qps1000". The
+// scenarios field then holds a list, which the driver runs in order. A single
+// scenario object is kept as an object when the profile has one step. The
+// scenarioString is returned unchanged if the profile is nil. An error is
+// returned if the profile is not valid or the scenarios cannot be parsed.
+func UpdateConfigMapWithLoadProfile(profile *grpcv1.LoadProfile, scenarioString string) (string, error) {
+	if profile == nil {
+		return scenarioString, nil
+	}
+	steps, err := LoadSteps(profile)
+	if err != nil {
+		return "", err
+	}
+
+	var jsonScenarioMap map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(scenarioString), &jsonScenarioMap); err != nil {
+		return "", err
+	}
+	rawScenarios, ok := jsonScenarioMap["scenarios"]
+	if !ok {
+		return "", fmt.Errorf("no scenario found to set load profile")
+	}
+	var scenarios []map[string]json.RawMessage
+	isList := true
+	if err := json.Unmarshal(rawScenarios, &scenarios); err != nil {
+		isList = false
+		var scenario map[string]json.RawMessage
+		if err := json.Unmarshal(rawScenarios, &scenario); err != nil {
+			return "", err
+		}
+		scenarios = []map[string]json.RawMessage{scenario}
+	}
+
+	var updated []map[string]json.RawMessage
+	for i, scenario := range scenarios {
+		if len(steps) == 0 {
+			if err := setLoadParams(scenario, json.RawMessage(`{"closed_loop":{}}`)); err != nil {
+				return "", fmt.Errorf("scenario %d: %v", i, err)
+			}
+			updated = append(updated, scenario)
+			continue
+		}
+
+		var name string
+		if rawName, ok := scenario["name"]; ok {
+			if err := json.Unmarshal(rawName, &name); err != nil {
+				return "", fmt.Errorf("scenario %d has an invalid name: %v", i, err)
+			}
+		}
+		for _, load := range steps {
+			stepScenario := make(map[string]json.RawMessage, len(scenario))
+			for key, value := range scenario {
+				stepScenario[key] = value
+			}
+			loadParams := json.RawMessage(fmt.Sprintf(`{"poisson":{"offered_load":%d}}`, load))
+			if err := setLoadParams(stepScenario, loadParams); err != nil {
+				return "", fmt.Errorf("scenario %d: %v", i, err)
+			}
+			if len(steps) > 1 && name != "" {
+				stepName, err := json.Marshal(fmt.Sprintf("%s-qps%d", name, load))
+				if err != nil {
+					return "", err
+				}
+				stepScenario["name"] = stepName
+			}
+			updated = append(updated, stepScenario)
+		}
+	}
+
+	var scenariosJSON []byte
+	if len(updated) == 1 && !isList {
+		scenariosJSON, err = json.Marshal(updated[0])
+	} else {
+		scenariosJSON, err = json.Marshal(updated)
+	}
+	if err != nil {
+		return "", err
+	}
+	jsonScenarioMap["scenarios"] = scenariosJSON
+
+	scenariosJSONByte, err := json.Marshal(jsonScenarioMap)
+	if err != nil {
+		return "", err
+	}
+	return string(scenariosJSONByte), nil
+}
+
+// setLoadParams replaces the load_params of the client_config of a scenario.
+func setLoadParams(scenario map[string]json.RawMessage, loadParams json.RawMessage) error {
+	clientConfig := make(map[string]json.RawMessage)
+	if rawClientConfig, ok := scenario["client_config"]; ok {
+		if err := json.Unmarshal(rawClientConfig, &clientConfig); err != nil {
+			return fmt.Errorf("invalid client_config: %v", err)
+		}
+	}
+	clientConfig["load_params"] = loadParams
+	clientConfigBytes, err := json.Marshal(clientConfig)
+	if err != nil {
+		return err
+	}
+	scenario["client_config"] = clientConfigBytes
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// scenarioLoads parses the names and load_params of the scenarios in a
+// scenarioString.
+func scenarioLoads(scenarioString string) ([]string, []string) {
+	var jsonScenarioMap map[string]json.RawMessage
+	Expect(json.Unmarshal([]byte(scenarioString), &jsonScenarioMap)).To(Succeed())
+
+	type scenario struct {
+		Name         string `json:"name"`
+		ClientConfig struct {
+			ClientType string          `json:"client_type"`
+			LoadParams json.RawMessage `json:"load_params"`
+		} `json:"client_config"`
+	}
+	var scenarios []scenario
+	if err := json.Unmarshal(jsonScenarioMap["scenarios"], &scenarios); err != nil {
+		var single scenario
+		Expect(json.Unmarshal(jsonScenarioMap["scenarios"], &single)).To(Succeed())
+		scenarios = []scenario{single}
+	}
+
+	var names, loads []string
+	for _, s := range scenarios {
+		Expect(s.ClientConfig.ClientType).To(Equal("ASYNC_CLIENT"))
+		names = append(names, s.Name)
+		loads = append(loads, string(s.ClientConfig.LoadParams))
+	}
+	return names, loads
+}
+
+var _ = Describe("UpdateConfigMapWithLoadProfile", func() {
+	var scenarios string
+
+	BeforeEach(func() {
+		scenarios = `{"scenarios":{"name":"scenario-1","client_config":{"client_type":"ASYNC_CLIENT","load_params":{"closed_loop":{}}}}}`
+	})
+
+	It("returns the scenarios unchanged without a profile", func() {
+		actual, err := UpdateConfigMapWithLoadProfile(nil, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(scenarios))
+	})
+
+	It("sets a poisson load with an offered load", func() {
+		profile := &grpcv1.LoadProfile{Type: grpcv1.PoissonLoad, OfferedLoad: 1000}
+		actual, err := UpdateConfigMapWithLoadProfile(profile, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+
+		names, loads := scenarioLoads(actual)
+		Expect(names).To(Equal([]string{"scenario-1"}))
+		Expect(loads).To(Equal([]string{`{"poisson":{"offered_load":1000}}`}))
+	})
+
+	It("sets a closed loop load", func() {
+		scenarios = `{"scenarios":[{"name":"scenario-1","client_config":{"client_type":"ASYNC_CLIENT","load_params":{"poisson":{"offered_load":5}}}}]}`
+		profile := &grpcv1.LoadProfile{Type: grpcv1.ClosedLoopLoad}
+		actual, err := UpdateConfigMapWithLoadProfile(profile, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(HavePrefix(`{"scenarios":[`))
+
+		_, loads := scenarioLoads(actual)
+		Expect(loads).To(Equal([]string{`{"closed_loop":{}}`}))
+	})
+
+	It("repeats the scenario for each step", func() {
+		profile := &grpcv1.LoadProfile{Type: grpcv1.PoissonLoad, Steps: []int32{100, 500}}
+		actual, err := UpdateConfigMapWithLoadProfile(profile, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+
+		names, loads := scenarioLoads(actual)
+		Expect(names).To(Equal([]string{"scenario-1-qps100", "scenario-1-qps500"}))
+		Expect(loads).To(Equal([]string{
+			`{"poisson":{"offered_load":100}}`,
+			`{"poisson":{"offered_load":500}}`,
+		}))
+	})
+
+	It("repeats the scenario for each step of a ramp", func() {
+		profile := &grpcv1.LoadProfile{
+			Type: grpcv1.PoissonLoad,
+			Ramp: &grpcv1.LoadRamp{From: 100, To: 350, Increment: 100},
+		}
+		actual, err := UpdateConfigMapWithLoadProfile(profile, scenarios)
+		Expect(err).ToNot(HaveOccurred())
+
+		names, _ := scenarioLoads(actual)
+		Expect(names).To(Equal([]string{"scenario-1-qps100", "scenario-1-qps200", "scenario-1-qps300"}))
+	})
+
+	It("returns an error for invalid profiles", func() {
+		for _, profile := range []*grpcv1.LoadProfile{
+			{Type: grpcv1.PoissonLoad},
+			{Type: grpcv1.PoissonLoad, OfferedLoad: 100, Steps: []int32{200}},
+			{Type: grpcv1.PoissonLoad, Steps: []int32{0}},
+			{Type: grpcv1.PoissonLoad, Ramp: &grpcv1.LoadRamp{From: 200, To: 100, Increment: 10}},
+			{Type: grpcv1.PoissonLoad, Ramp: &grpcv1.LoadRamp{From: 1, To: 1000, Increment: 1}},
+			{Type: grpcv1.ClosedLoopLoad, OfferedLoad: 100},
+			{Type: "Bursty"},
+		} {
+			_, err := UpdateConfigMapWithLoadProfile(profile, scenarios)
+			Expect(err).To(HaveOccurred(), "profile %+v", profile)
+		}
+	})
+})
@@ -14,7 +14,12 @@ limitations under the License.
 package kubehelpers
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
@@ -83,3 +88,116 @@ func IsClientsSpecValid(clients *[]grpcv1.Client) (bool, error) {
 
 	return true, nil
 }
+
+// xdsServerFlag returns the value of a flag in the arguments of an xds-server
+// container. Flags may be given as "-name value", "-name=value", or with two
+// dashes. The second return value is false if the flag is not set.
+func xdsServerFlag(container *corev1.Container, name string) (string, bool) {
+	args := container.Args
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimPrefix(strings.TrimPrefix(args[i], "-"), "-")
+		if arg == args[i] {
+			continue
+		}
+		if arg == name {
+			if i+1 < len(args) {
+				return args[i+1], true
+			}
+			return "", true
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			return strings.TrimPrefix(arg, name+"="), true
+		}
+	}
+	return "", false
+}
+
+// XdsNodeID returns the node ID of the clients of a PSM test. It is derived
+// from the namespace and the name of the test, so that concurrent tests get
+// distinct node IDs and do not share configuration, even when they are served
+// by the same xDS server.
+func XdsNodeID(test *grpcv1.LoadTest) string {
+	if test.Namespace == "" {
+		return test.Name
+	}
+	return test.Namespace + "/" + test.Name
+}
+
+// xdsServerNodeID returns the node ID that an xds-server container writes in
+// the bootstrap file of proxyless clients. It is the first ID of the -node-ID
+// flag, falling back to the first ID of the $XDS_NODE_ID env variable and
+// then to the default of the xds-server.
+func xdsServerNodeID(xdsServer *corev1.Container) string {
+	nodeIDs, ok := xdsServerFlag(xdsServer, "node-ID")
+	if !ok {
+		for _, env := range xdsServer.Env {
+			if env.Name == config.XdsNodeIDEnv {
+				nodeIDs, ok = env.Value, true
+			}
+		}
+	}
+	if !ok || nodeIDs == "" {
+		return config.DefaultXdsNodeID
+	}
+	return strings.TrimSpace(strings.Split(nodeIDs, ",")[0])
+}
+
+// XdsBootstrapConfig returns the contents of the bootstrap file that an
+// xds-server container writes for proxyless clients with its default
+// template. The node ID, the address of the xDS server and its features are
+// read from the flags of the container, falling back to the $XDS_NODE_ID env
+// variable for the node ID and to the defaults of the xds-server. An error is
+// returned if the container uses a custom template or copies a pre-built
+// bootstrap file, since its contents cannot be known in advance.
+func XdsBootstrapConfig(xdsServer *corev1.Container) (string, error) {
+	for _, name := range []string{"bootstrap-template", "path-to-bootstrap"} {
+		if _, ok := xdsServerFlag(xdsServer, name); ok {
+			return "", fmt.Errorf("cannot generate bootstrap for xds-server container with -%s flag", name)
+		}
+	}
+
+	nodeID := xdsServerNodeID(xdsServer)
+	host := "localhost"
+	if value, ok := xdsServerFlag(xdsServer, "bootstrap-server-host"); ok {
+		host = value
+	}
+	port := fmt.Sprint(config.DefaultXdsServerPort)
+	if value, ok := xdsServerFlag(xdsServer, "xds-server-port"); ok {
+		port = value
+	}
+	serverFeatures := []string{"xds_v3"}
+	if value, ok := xdsServerFlag(xdsServer, "bootstrap-server-features"); ok {
+		serverFeatures = nil
+		if value != "" {
+			serverFeatures = strings.Split(value, ",")
+		}
+	}
+
+	type channelCreds struct {
+		Type string `json:"type"`
+	}
+	type xdsServerConfig struct {
+		ServerURI      string         `json:"server_uri"`
+		ChannelCreds   []channelCreds `json:"channel_creds"`
+		ServerFeatures []string       `json:"server_features"`
+	}
+	bootstrap := struct {
+		XdsServers []xdsServerConfig `json:"xds_servers"`
+		Node       struct {
+			ID string `json:"id"`
+		} `json:"node"`
+	}{
+		XdsServers: []xdsServerConfig{{
+			ServerURI:      net.JoinHostPort(host, port),
+			ChannelCreds:   []channelCreds{{Type: "insecure"}},
+			ServerFeatures: serverFeatures,
+		}},
+	}
+	bootstrap.Node.ID = nodeID
+
+	bootstrapJSON, err := json.Marshal(bootstrap)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode bootstrap: %v", err)
+	}
+	return string(bootstrapJSON), nil
+}
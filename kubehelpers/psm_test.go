@@ -541,3 +541,94 @@ var _ = Describe("IsClientsSpecValid", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 })
+
+var _ = Describe("XdsBootstrapConfig", func() {
+	It("uses the defaults of the xds-server", func() {
+		bootstrap, err := XdsBootstrapConfig(&corev1.Container{Name: "xds-server"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(MatchJSON(`{
+			"xds_servers": [{
+				"server_uri": "localhost:18000",
+				"channel_creds": [{"type": "insecure"}],
+				"server_features": ["xds_v3"]
+			}],
+			"node": {"id": "test_id"}
+		}`))
+	})
+
+	It("uses the values of the flags of the xds-server", func() {
+		bootstrap, err := XdsBootstrapConfig(&corev1.Container{
+			Name: "xds-server",
+			Args: []string{
+				"-default-config-path", "config.json",
+				"--node-ID=node",
+				"-xds-server-port", "18001",
+				"-bootstrap-server-host", "xds",
+				"-bootstrap-server-features=xds_v3,ignore_resource_deletion",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(MatchJSON(`{
+			"xds_servers": [{
+				"server_uri": "xds:18001",
+				"channel_creds": [{"type": "insecure"}],
+				"server_features": ["xds_v3", "ignore_resource_deletion"]
+			}],
+			"node": {"id": "node"}
+		}`))
+	})
+
+	It("uses the first node ID from the environment of the xds-server", func() {
+		bootstrap, err := XdsBootstrapConfig(&corev1.Container{
+			Name: "xds-server",
+			Env: []corev1.EnvVar{
+				{Name: "XDS_NODE_ID", Value: "default/test,shared"},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(ContainSubstring(`"node":{"id":"default/test"}`))
+	})
+
+	It("prefers the node ID flag over the environment of the xds-server", func() {
+		bootstrap, err := XdsBootstrapConfig(&corev1.Container{
+			Name: "xds-server",
+			Args: []string{"-node-ID", "node"},
+			Env: []corev1.EnvVar{
+				{Name: "XDS_NODE_ID", Value: "default/test"},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bootstrap).To(ContainSubstring(`"node":{"id":"node"}`))
+	})
+
+	It("returns an error when the xds-server uses a bootstrap template", func() {
+		_, err := XdsBootstrapConfig(&corev1.Container{
+			Name: "xds-server",
+			Args: []string{"-bootstrap-template", "bootstrap.tmpl"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the xds-server copies a pre-built bootstrap", func() {
+		_, err := XdsBootstrapConfig(&corev1.Container{
+			Name: "xds-server",
+			Args: []string{"-path-to-bootstrap=bootstrap.json"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("XdsNodeID", func() {
+	It("is derived from the namespace and name of the test", func() {
+		test := &grpcv1.LoadTest{}
+		test.Namespace = "default"
+		test.Name = "psm-test"
+		Expect(XdsNodeID(test)).To(Equal("default/psm-test"))
+	})
+
+	It("is the name of the test when it has no namespace", func() {
+		test := &grpcv1.LoadTest{}
+		test.Name = "psm-test"
+		Expect(XdsNodeID(test)).To(Equal("psm-test"))
+	})
+})
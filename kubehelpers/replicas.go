@@ -0,0 +1,68 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"fmt"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// ReplicaName returns the name of the replica of a client at the given index.
+func ReplicaName(name string, index int) string {
+	return fmt.Sprintf("%s-%d", name, index)
+}
+
+// ExpandClients returns the clients of a test with each client that sets
+// replicas expanded into that number of identical clients. The name of each
+// expanded client is suffixed with its index and its replicas field is unset.
+// Clients without replicas, or with a single replica, are returned unchanged.
+// Names must be set on the clients before they are expanded.
+func ExpandClients(clients []grpcv1.Client) []grpcv1.Client {
+	var expanded []grpcv1.Client
+	for i := range clients {
+		client := &clients[i]
+		if client.Replicas == nil || *client.Replicas <= 1 {
+			expanded = append(expanded, *client)
+			continue
+		}
+		for r := 0; r < int(*client.Replicas); r++ {
+			replica := client.DeepCopy()
+			replica.Replicas = nil
+			if client.Name != nil {
+				name := ReplicaName(*client.Name, r)
+				replica.Name = &name
+			}
+			expanded = append(expanded, *replica)
+		}
+	}
+	return expanded
+}
+
+// ClientCount returns the number of client pods of a test, counting each
+// replica of a client.
+func ClientCount(clients []grpcv1.Client) int {
+	count := 0
+	for i := range clients {
+		if replicas := clients[i].Replicas; replicas != nil && *replicas > 1 {
+			count += int(*replicas)
+		} else {
+			count++
+		}
+	}
+	return count
+}
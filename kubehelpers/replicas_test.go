@@ -0,0 +1,87 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ExpandClients", func() {
+	var replicas int32
+	var clients []grpcv1.Client
+
+	BeforeEach(func() {
+		replicas = 3
+		clients = []grpcv1.Client{
+			{
+				Name:     optional.StringPtr("single"),
+				Language: "go",
+			},
+			{
+				Name:     optional.StringPtr("scale"),
+				Language: "java",
+				Pool:     optional.StringPtr("workers"),
+				Replicas: &replicas,
+			},
+		}
+	})
+
+	It("suffixes the names of the replicas with their index", func() {
+		expanded := ExpandClients(clients)
+		Expect(expanded).To(HaveLen(4))
+
+		var names []string
+		for _, client := range expanded {
+			names = append(names, *client.Name)
+		}
+		Expect(names).To(Equal([]string{"single", "scale-0", "scale-1", "scale-2"}))
+	})
+
+	It("copies the spec into each replica", func() {
+		expanded := ExpandClients(clients)
+		for _, client := range expanded[1:] {
+			Expect(client.Language).To(Equal("java"))
+			Expect(client.Pool).To(Equal(optional.StringPtr("workers")))
+			Expect(client.Replicas).To(BeNil())
+		}
+
+		*expanded[1].Pool = "other"
+		Expect(*clients[1].Pool).To(Equal("workers"))
+	})
+
+	It("leaves clients with a single replica unchanged", func() {
+		replicas = 1
+		expanded := ExpandClients(clients)
+		Expect(expanded).To(HaveLen(2))
+		Expect(*expanded[1].Name).To(Equal("scale"))
+	})
+})
+
+var _ = Describe("ClientCount", func() {
+	It("counts each replica", func() {
+		replicas := int32(5)
+		clients := []grpcv1.Client{
+			{Name: optional.StringPtr("single")},
+			{Name: optional.StringPtr("scale"), Replicas: &replicas},
+		}
+		Expect(ClientCount(clients)).To(Equal(6))
+	})
+})
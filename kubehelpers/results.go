@@ -0,0 +1,38 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"fmt"
+	"strings"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// ResultsURIForLoadTest accepts a load test and returns the Cloud Storage URI
+// where the raw JSON output of its driver is stored. The URI is built from the
+// GCSPrefix of the test results, followed by the namespace, name and UID of the
+// test. An empty string is returned if the test does not set a GCSPrefix.
+func ResultsURIForLoadTest(test *grpcv1.LoadTest) string {
+	results := test.Spec.Results
+	if results == nil || results.GCSPrefix == nil || *results.GCSPrefix == "" {
+		return ""
+	}
+
+	prefix := strings.TrimSuffix(*results.GCSPrefix, "/")
+	return fmt.Sprintf("%s/%s/%s/%s/qps_result.json", prefix, test.Namespace, test.Name, test.UID)
+}
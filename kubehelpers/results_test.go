@@ -0,0 +1,65 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ResultsURIForLoadTest", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+				UID:       "1234",
+			},
+		}
+	})
+
+	It("returns an empty string when results are not configured", func() {
+		Expect(ResultsURIForLoadTest(test)).To(BeEmpty())
+	})
+
+	It("returns an empty string when the GCS prefix is not set", func() {
+		test.Spec.Results = &grpcv1.Results{
+			BigQueryTable: optional.StringPtr("example-dataset.example-table"),
+		}
+		Expect(ResultsURIForLoadTest(test)).To(BeEmpty())
+	})
+
+	It("places the output under the namespace, name and UID of the test", func() {
+		test.Spec.Results = &grpcv1.Results{
+			GCSPrefix: optional.StringPtr("gs://example-bucket/results"),
+		}
+		Expect(ResultsURIForLoadTest(test)).To(Equal("gs://example-bucket/results/example-namespace/example-test/1234/qps_result.json"))
+	})
+
+	It("ignores a trailing slash in the GCS prefix", func() {
+		test.Spec.Results = &grpcv1.Results{
+			GCSPrefix: optional.StringPtr("gs://example-bucket/"),
+		}
+		Expect(ResultsURIForLoadTest(test)).To(Equal("gs://example-bucket/example-namespace/example-test/1234/qps_result.json"))
+	})
+})
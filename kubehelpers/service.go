@@ -0,0 +1,155 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// ComponentHostname accepts the role and name of a test component and returns
+// the hostname of its pod. Combined with the headless Service for the test,
+// the hostname gives the pod the stable DNS name
+// <hostname>.<test name>.<namespace>.svc.
+func ComponentHostname(role string, name string) string {
+	return fmt.Sprintf("%s-%s", role, name)
+}
+
+// PodDNSName accepts a pod and returns its DNS name, which is derived from
+// the hostname and subdomain of the pod. If the pod does not have both a
+// hostname and a subdomain, it has no DNS name and an empty string is
+// returned.
+func PodDNSName(pod *corev1.Pod) string {
+	if pod.Spec.Hostname == "" || pod.Spec.Subdomain == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, pod.Spec.Subdomain, pod.Namespace)
+}
+
+// HeadlessServiceForLoadTest accepts a load test and returns a headless
+// Service that selects its worker pods. Worker pods use the Service as their
+// subdomain, so each of them can be addressed by a stable DNS name instead of
+// its IP address. This allows the driver to reach workers that were
+// restarted, as well as workers in other namespaces.
+//
+// The IP families of the Service match the IP family of the load test, which
+// determines the type of DNS records that are published for the pods.
+//
+// When the load test uses a service mesh, the benchmark port declares gRPC as
+// its application protocol, so the mesh proxies it as gRPC traffic rather than
+// as opaque TCP.
+//
+// The returned Service shares the name and namespace of the load test. The
+// caller is responsible for setting an owner reference on it.
+func HeadlessServiceForLoadTest(test *grpcv1.LoadTest) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      test.Name,
+			Namespace: test.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector: map[string]string{
+				config.LoadTestLabel: test.Name,
+				config.WorkerLabel:   "true",
+			},
+			// Publish the names of pods before they are ready, since the
+			// driver waits for the workers to become ready itself.
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "driver",
+					Protocol: corev1.ProtocolTCP,
+					Port:     config.DriverPort,
+				},
+				{
+					Name:     "benchmark",
+					Protocol: corev1.ProtocolTCP,
+					Port:     config.ServerPort,
+				},
+			},
+		},
+	}
+	if test.Spec.ServiceMesh != "" {
+		for i := range svc.Spec.Ports {
+			if svc.Spec.Ports[i].Port == config.ServerPort {
+				svc.Spec.Ports[i].AppProtocol = optional.StringPtr("grpc")
+			}
+		}
+	}
+	setServiceIPFamilies(&svc.Spec, test.Spec.IPFamily)
+	return svc
+}
+
+// DriverServiceName accepts a load test and returns the name of the Service
+// that exposes its driver.
+func DriverServiceName(test *grpcv1.LoadTest) string {
+	return test.Name + "-driver"
+}
+
+// DriverServiceForLoadTest accepts a load test and returns a ClusterIP
+// Service that exposes the ports of its driver, such as a metrics port. The
+// Service gives the driver a stable address that other namespaces can use.
+// If the driver does not declare any container ports, there is nothing to
+// expose and nil is returned.
+//
+// The caller is responsible for setting an owner reference on the returned
+// Service.
+func DriverServiceForLoadTest(test *grpcv1.LoadTest) *corev1.Service {
+	if test.Spec.Driver == nil {
+		return nil
+	}
+
+	var ports []corev1.ServicePort
+	for _, container := range test.Spec.Driver.Run {
+		for _, port := range container.Ports {
+			ports = append(ports, corev1.ServicePort{
+				Name:       port.Name,
+				Protocol:   port.Protocol,
+				Port:       port.ContainerPort,
+				TargetPort: intstr.FromInt(int(port.ContainerPort)),
+			})
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DriverServiceName(test),
+			Namespace: test.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				config.LoadTestLabel: test.Name,
+				config.RoleLabel:     config.DriverRole,
+			},
+			Ports: ports,
+		},
+	}
+	setServiceIPFamilies(&svc.Spec, test.Spec.IPFamily)
+	return svc
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ComponentHostname", func() {
+	It("prefixes the component name with its role", func() {
+		Expect(ComponentHostname(config.ServerRole, "tls")).To(Equal("server-tls"))
+	})
+})
+
+var _ = Describe("PodDNSName", func() {
+	It("combines the hostname, subdomain and namespace of the pod", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "example-namespace",
+			},
+			Spec: corev1.PodSpec{
+				Hostname:  "server-0",
+				Subdomain: "example-test",
+			},
+		}
+		Expect(PodDNSName(pod)).To(Equal("server-0.example-test.example-namespace.svc"))
+	})
+
+	It("returns an empty string for pods without a subdomain", func() {
+		pod := &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Hostname: "server-0",
+			},
+		}
+		Expect(PodDNSName(pod)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("HeadlessServiceForLoadTest", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+			},
+		}
+	})
+
+	It("shares the name and namespace of the test", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Name).To(Equal(test.Name))
+		Expect(svc.Namespace).To(Equal(test.Namespace))
+	})
+
+	It("is headless", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+	})
+
+	It("selects the worker pods of the test", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{
+			config.LoadTestLabel: test.Name,
+			config.WorkerLabel:   "true",
+		}))
+	})
+
+	It("publishes pods before they are ready", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		Expect(svc.Spec.PublishNotReadyAddresses).To(BeTrue())
+	})
+
+	It("does not set an application protocol without a service mesh", func() {
+		svc := HeadlessServiceForLoadTest(test)
+		for _, port := range svc.Spec.Ports {
+			Expect(port.AppProtocol).To(BeNil())
+		}
+	})
+
+	It("declares gRPC as the protocol of the benchmark port with a service mesh", func() {
+		test.Spec.ServiceMesh = grpcv1.IstioServiceMesh
+		svc := HeadlessServiceForLoadTest(test)
+		for _, port := range svc.Spec.Ports {
+			if port.Port == config.ServerPort {
+				Expect(port.AppProtocol).To(Equal(optional.StringPtr("grpc")))
+			} else {
+				Expect(port.AppProtocol).To(BeNil())
+			}
+		}
+	})
+})
+
+var _ = Describe("DriverServiceForLoadTest", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver: &grpcv1.Driver{
+					Run: []corev1.Container{
+						{
+							Name: config.RunContainerName,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "metrics",
+									Protocol:      corev1.ProtocolTCP,
+									ContainerPort: 9090,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("exposes the ports of the driver", func() {
+		svc := DriverServiceForLoadTest(test)
+		Expect(svc).ToNot(BeNil())
+		Expect(svc.Name).To(Equal(DriverServiceName(test)))
+		Expect(svc.Namespace).To(Equal(test.Namespace))
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(svc.Spec.Ports).To(HaveLen(1))
+		Expect(svc.Spec.Ports[0].Name).To(Equal("metrics"))
+		Expect(svc.Spec.Ports[0].Port).To(BeEquivalentTo(9090))
+	})
+
+	It("selects the driver pod of the test", func() {
+		svc := DriverServiceForLoadTest(test)
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{
+			config.LoadTestLabel: test.Name,
+			config.RoleLabel:     config.DriverRole,
+		}))
+	})
+
+	It("returns nil when the driver has no ports", func() {
+		test.Spec.Driver.Run[0].Ports = nil
+		Expect(DriverServiceForLoadTest(test)).To(BeNil())
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// ShardName returns the name of the shard of a test at the given index.
+func ShardName(name string, index int) string {
+	return fmt.Sprintf("%s-%d", name, index)
+}
+
+// ShardByScenario splits a test whose ScenariosJSON lists several scenarios
+// into one test per scenario. Each shard shares the component specs of the
+// original test, so each scenario runs on its own set of clean workers, and a
+// failure can be attributed to a single scenario.
+//
+// The name of each shard is suffixed with the index of its scenario, and the
+// shard is annotated with the name of its scenario and the name of the
+// original test. Tests with a single scenario are returned unchanged. An error
+// is returned if the ScenariosJSON cannot be parsed.
+func ShardByScenario(test *grpcv1.LoadTest) ([]*grpcv1.LoadTest, error) {
+	if test.Spec.ScenariosJSON == "" {
+		return []*grpcv1.LoadTest{test}, nil
+	}
+
+	var jsonScenarioMap map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(test.Spec.ScenariosJSON), &jsonScenarioMap); err != nil {
+		return nil, fmt.Errorf("failed to parse scenarios of test %s: %v", test.Name, err)
+	}
+
+	var scenarios []json.RawMessage
+	if err := json.Unmarshal(jsonScenarioMap["scenarios"], &scenarios); err != nil || len(scenarios) <= 1 {
+		// The scenarios field holds a single scenario object, or a list
+		// with at most one entry, so there is nothing to split.
+		return []*grpcv1.LoadTest{test}, nil
+	}
+
+	var shards []*grpcv1.LoadTest
+	for i, scenario := range scenarios {
+		var fields struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(scenario, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %d of test %s: %v", i, test.Name, err)
+		}
+
+		jsonScenarioMap["scenarios"] = scenario
+		scenariosJSON, err := json.Marshal(jsonScenarioMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode scenario %d of test %s: %v", i, test.Name, err)
+		}
+
+		shard := test.DeepCopy()
+		shard.Name = ShardName(test.Name, i)
+		shard.Spec.ScenariosJSON = string(scenariosJSON)
+		if shard.Annotations == nil {
+			shard.Annotations = make(map[string]string)
+		}
+		shard.Annotations[config.ShardOfAnnotation] = test.Name
+		if fields.Name != "" {
+			shard.Annotations["scenario"] = fields.Name
+		} else {
+			delete(shard.Annotations, "scenario")
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
+
+// ScenarioCount returns the number of scenarios in the ScenariosJSON of a
+// test. The scenarios field may hold a single scenario object or a list of
+// scenarios. Zero is returned for tests without scenarios, such as interop
+// tests. An error is returned if the ScenariosJSON cannot be parsed.
+func ScenarioCount(test *grpcv1.LoadTest) (int, error) {
+	if test.Spec.ScenariosJSON == "" {
+		return 0, nil
+	}
+
+	var jsonScenarioMap map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(test.Spec.ScenariosJSON), &jsonScenarioMap); err != nil {
+		return 0, fmt.Errorf("failed to parse scenarios of test %s: %v", test.Name, err)
+	}
+
+	rawScenarios, ok := jsonScenarioMap["scenarios"]
+	if !ok {
+		return 0, nil
+	}
+	var scenarios []json.RawMessage
+	if err := json.Unmarshal(rawScenarios, &scenarios); err != nil {
+		return 1, nil
+	}
+	return len(scenarios), nil
+}
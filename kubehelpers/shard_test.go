@@ -0,0 +1,157 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ShardByScenario", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ping-pong",
+				Annotations: map[string]string{
+					"pool":     "workers",
+					"scenario": "all",
+				},
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Clients: []grpcv1.Client{{Name: optional.StringPtr("client-1")}},
+				Servers: []grpcv1.Server{{Name: optional.StringPtr("server-1")}},
+				ScenariosJSON: `{
+					"scenarios": [
+						{"name": "unary", "benchmark_seconds": 30},
+						{"name": "streaming", "benchmark_seconds": 30}
+					]
+				}`,
+			},
+		}
+	})
+
+	It("returns a test with a single scenario object unchanged", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": {"name": "unary"}}`
+		shards, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shards).To(Equal([]*grpcv1.LoadTest{test}))
+	})
+
+	It("returns a test with a single scenario in a list unchanged", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": [{"name": "unary"}]}`
+		shards, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shards).To(Equal([]*grpcv1.LoadTest{test}))
+	})
+
+	It("returns a test without scenarios unchanged", func() {
+		test.Spec.ScenariosJSON = ""
+		shards, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shards).To(Equal([]*grpcv1.LoadTest{test}))
+	})
+
+	It("returns one test per scenario", func() {
+		shards, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shards).To(HaveLen(2))
+
+		Expect(shards[0].Name).To(Equal("ping-pong-0"))
+		Expect(shards[1].Name).To(Equal("ping-pong-1"))
+		Expect(shards[0].Annotations["scenario"]).To(Equal("unary"))
+		Expect(shards[1].Annotations["scenario"]).To(Equal("streaming"))
+		for _, shard := range shards {
+			Expect(shard.Annotations[config.ShardOfAnnotation]).To(Equal("ping-pong"))
+			Expect(shard.Annotations["pool"]).To(Equal("workers"))
+			Expect(shard.Spec.Clients).To(Equal(test.Spec.Clients))
+			Expect(shard.Spec.Servers).To(Equal(test.Spec.Servers))
+		}
+	})
+
+	It("sets a single scenario object on each shard", func() {
+		shards, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+
+		var scenarios struct {
+			Scenarios struct {
+				Name             string `json:"name"`
+				BenchmarkSeconds int    `json:"benchmark_seconds"`
+			} `json:"scenarios"`
+		}
+		Expect(json.Unmarshal([]byte(shards[1].Spec.ScenariosJSON), &scenarios)).To(Succeed())
+		Expect(scenarios.Scenarios.Name).To(Equal("streaming"))
+		Expect(scenarios.Scenarios.BenchmarkSeconds).To(Equal(30))
+	})
+
+	It("does not modify the original test", func() {
+		original := test.DeepCopy()
+		_, err := ShardByScenario(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(test).To(Equal(original))
+	})
+
+	It("returns an error when the scenarios cannot be parsed", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": [`
+		_, err := ShardByScenario(test)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when a scenario is not an object", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": [{"name": "unary"}, "streaming"]}`
+		_, err := ShardByScenario(test)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ScenarioCount", func() {
+	It("returns zero for a test without scenarios", func() {
+		test := &grpcv1.LoadTest{}
+		Expect(ScenarioCount(test)).To(Equal(0))
+	})
+
+	It("returns one for a single scenario object", func() {
+		test := &grpcv1.LoadTest{Spec: grpcv1.LoadTestSpec{
+			ScenariosJSON: `{"scenarios": {"name": "unary"}}`,
+		}}
+		Expect(ScenarioCount(test)).To(Equal(1))
+	})
+
+	It("returns the length of a list of scenarios", func() {
+		test := &grpcv1.LoadTest{Spec: grpcv1.LoadTestSpec{
+			ScenariosJSON: `{"scenarios": [{"name": "unary"}, {"name": "streaming"}]}`,
+		}}
+		Expect(ScenarioCount(test)).To(Equal(2))
+	})
+
+	It("returns an error when the scenarios cannot be parsed", func() {
+		test := &grpcv1.LoadTest{Spec: grpcv1.LoadTestSpec{
+			ScenariosJSON: `{"scenarios": [`,
+		}}
+		_, err := ScenarioCount(test)
+		Expect(err).To(HaveOccurred())
+	})
+})
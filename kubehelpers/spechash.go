@@ -0,0 +1,85 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// specHashLength is the number of hexadecimal digits kept from the SHA-256
+// digest of a spec, so that the hash fits in the value of a label.
+const specHashLength = 40
+
+// SpecHash returns a canonical hash of the spec of a test. The ScenariosJSON
+// is normalized first, so that differences in whitespace and in the order of
+// keys do not change the hash. The annotations that override the warmup and
+// benchmark durations of the scenarios are included, since they change the
+// test that runs. An error is returned if the ScenariosJSON cannot be parsed.
+func SpecHash(test *grpcv1.LoadTest) (string, error) {
+	spec := test.Spec.DeepCopy()
+	if spec.ScenariosJSON != "" {
+		var scenarios interface{}
+		if err := json.Unmarshal([]byte(spec.ScenariosJSON), &scenarios); err != nil {
+			return "", fmt.Errorf("failed to parse scenarios of test %s: %v", test.Name, err)
+		}
+		normalized, err := json.Marshal(scenarios)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode scenarios of test %s: %v", test.Name, err)
+		}
+		spec.ScenariosJSON = string(normalized)
+	}
+
+	canonical, err := json.Marshal(struct {
+		Spec             *grpcv1.LoadTestSpec `json:"spec"`
+		WarmupSeconds    string               `json:"warmupSeconds,omitempty"`
+		BenchmarkSeconds string               `json:"benchmarkSeconds,omitempty"`
+	}{
+		Spec:             spec,
+		WarmupSeconds:    test.Annotations[config.WarmupSecondsAnnotation],
+		BenchmarkSeconds: test.Annotations[config.BenchmarkSecondsAnnotation],
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode spec of test %s: %v", test.Name, err)
+	}
+
+	digest := sha256.Sum256(canonical)
+	return hex.EncodeToString(digest[:])[:specHashLength], nil
+}
+
+// SetSpecHash stores the hash of the spec of a test in its annotations and
+// labels. An error is returned if the hash cannot be computed.
+func SetSpecHash(test *grpcv1.LoadTest) error {
+	hash, err := SpecHash(test)
+	if err != nil {
+		return err
+	}
+	if test.Annotations == nil {
+		test.Annotations = make(map[string]string)
+	}
+	test.Annotations[config.SpecHashAnnotation] = hash
+	if test.Labels == nil {
+		test.Labels = make(map[string]string)
+	}
+	test.Labels[config.SpecHashLabel] = hash
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubehelpers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("SpecHash", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ping-pong",
+				Annotations: map[string]string{"pool": "workers"},
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Clients:       []grpcv1.Client{{Name: optional.StringPtr("client-1")}},
+				Servers:       []grpcv1.Server{{Name: optional.StringPtr("server-1")}},
+				ScenariosJSON: `{"scenarios": {"name": "unary", "benchmark_seconds": 30}}`,
+			},
+		}
+	})
+
+	It("ignores whitespace and key order in the scenarios", func() {
+		hash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hash).To(HaveLen(specHashLength))
+
+		other := test.DeepCopy()
+		other.Name = "other"
+		other.Spec.ScenariosJSON = `{
+			"scenarios": {
+				"benchmark_seconds": 30,
+				"name": "unary"
+			}
+		}`
+		otherHash, err := SpecHash(other)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherHash).To(Equal(hash))
+	})
+
+	It("ignores annotations that do not change the test", func() {
+		hash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+
+		test.Annotations["pool"] = "other-workers"
+		otherHash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherHash).To(Equal(hash))
+	})
+
+	It("changes with the spec", func() {
+		hash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+
+		test.Spec.Clients[0].Name = optional.StringPtr("client-2")
+		otherHash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherHash).ToNot(Equal(hash))
+	})
+
+	It("changes with the duration overrides", func() {
+		hash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+
+		test.Annotations[config.BenchmarkSecondsAnnotation] = "5"
+		otherHash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherHash).ToNot(Equal(hash))
+	})
+
+	It("returns an error for invalid scenarios", func() {
+		test.Spec.ScenariosJSON = "{"
+		_, err := SpecHash(test)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("SetSpecHash", func() {
+	It("sets the hash in an annotation and a label", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{Name: "ping-pong"},
+		}
+		Expect(SetSpecHash(test)).To(Succeed())
+
+		hash, err := SpecHash(test)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(test.Annotations).To(HaveKeyWithValue(config.SpecHashAnnotation, hash))
+		Expect(test.Labels).To(HaveKeyWithValue(config.SpecHashLabel, hash))
+	})
+})
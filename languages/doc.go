@@ -0,0 +1,24 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package languages is the registry of the languages that load tests are
+// written in. Each language has a canonical name, which names its default
+// images in the defaults of the controller, its prebuilt worker images and its
+// examples, and may have aliases, such as the names used by gRPC scenarios.
+// The controller, the tools that prepare prebuilt workers and the generator of
+// smoke tests all resolve names through this package, so a language is handled
+// the same way everywhere.
+package languages
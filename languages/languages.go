@@ -0,0 +1,107 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package languages
+
+// Language describes a language that load tests are written in.
+type Language struct {
+	// Name is the canonical name of the language. It names the default
+	// images of the language in the defaults of the controller and its
+	// example load test.
+	Name string
+
+	// Aliases lists other names of the language, such as the names used by
+	// gRPC scenarios. They resolve to the canonical name.
+	Aliases []string
+
+	// Image is the name of the prebuilt worker image of the language, which
+	// is also the name of the directory containing its Dockerfile. Variants
+	// of a language, such as python_asyncio, share the image of the
+	// language.
+	Image string
+
+	// Repo is the default GitHub repository of the code under test, in the
+	// form owner/name.
+	Repo string
+
+	// BuildHint describes how the code of the language is built, so that
+	// messages can tell users which build image is expected.
+	BuildHint string
+}
+
+// registry lists the known languages, sorted by canonical name.
+var registry = []Language{
+	{Name: "csharp", Image: "csharp", Repo: "grpc/grpc", BuildHint: "the .NET SDK"},
+	{Name: "cxx", Aliases: []string{"c++"}, Image: "cxx", Repo: "grpc/grpc", BuildHint: "Bazel"},
+	{Name: "dotnet", Image: "dotnet", Repo: "grpc/grpc-dotnet", BuildHint: "the .NET SDK"},
+	{Name: "go", Image: "go", Repo: "grpc/grpc-go", BuildHint: "the Go toolchain"},
+	{Name: "java", Image: "java", Repo: "grpc/grpc-java", BuildHint: "Gradle"},
+	{Name: "node", Aliases: []string{"node_purejs"}, Image: "node", Repo: "grpc/grpc-node", BuildHint: "npm"},
+	{Name: "php7", Image: "php7", Repo: "grpc/grpc", BuildHint: "Composer and PECL"},
+	{Name: "php7_protobuf_c", Image: "php7", Repo: "grpc/grpc", BuildHint: "Composer and PECL"},
+	{Name: "python", Image: "python", Repo: "grpc/grpc", BuildHint: "Bazel"},
+	{Name: "python_asyncio", Image: "python", Repo: "grpc/grpc", BuildHint: "Bazel"},
+	{Name: "ruby", Image: "ruby", Repo: "grpc/grpc", BuildHint: "Bundler"},
+}
+
+// byName maps canonical names and aliases to the known languages.
+var byName = func() map[string]*Language {
+	m := make(map[string]*Language)
+	for i := range registry {
+		l := &registry[i]
+		m[l.Name] = l
+		for _, alias := range l.Aliases {
+			m[alias] = l
+		}
+	}
+	return m
+}()
+
+// All returns the known languages, sorted by canonical name.
+func All() []Language {
+	all := make([]Language, len(registry))
+	copy(all, registry)
+	return all
+}
+
+// Lookup returns the language with a canonical name or alias. The second
+// return value is false if the language is unknown.
+func Lookup(name string) (Language, bool) {
+	l, ok := byName[name]
+	if !ok {
+		return Language{}, false
+	}
+	return *l, true
+}
+
+// Canonical returns the canonical name of a language given its canonical name
+// or an alias. Unknown names are returned unchanged, so that languages that
+// are not in the registry can still be configured.
+func Canonical(name string) string {
+	if l, ok := byName[name]; ok {
+		return l.Name
+	}
+	return name
+}
+
+// ImageName returns the name of the prebuilt worker image of a language given
+// its canonical name or an alias. Unknown names are returned unchanged.
+func ImageName(name string) string {
+	if l, ok := byName[name]; ok {
+		return l.Image
+	}
+	return name
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package languages
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Languages", func() {
+	It("resolves aliases to canonical names", func() {
+		Expect(Canonical("c++")).To(Equal("cxx"))
+		Expect(Canonical("node_purejs")).To(Equal("node"))
+		Expect(Canonical("cxx")).To(Equal("cxx"))
+	})
+
+	It("returns unknown names unchanged", func() {
+		Expect(Canonical("fortran")).To(Equal("fortran"))
+		Expect(ImageName("fortran")).To(Equal("fortran"))
+
+		_, ok := Lookup("fortran")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("maps variants to the image of their language", func() {
+		Expect(ImageName("python_asyncio")).To(Equal("python"))
+		Expect(ImageName("php7_protobuf_c")).To(Equal("php7"))
+		Expect(ImageName("c++")).To(Equal("cxx"))
+		Expect(Canonical("python_asyncio")).To(Equal("python_asyncio"))
+	})
+
+	It("looks up languages by alias", func() {
+		l, ok := Lookup("c++")
+		Expect(ok).To(BeTrue())
+		Expect(l.Name).To(Equal("cxx"))
+		Expect(l.Repo).To(Equal("grpc/grpc"))
+	})
+
+	It("has unique names and aliases", func() {
+		seen := make(map[string]bool)
+		for _, l := range All() {
+			for _, name := range append([]string{l.Name}, l.Aliases...) {
+				Expect(seen).ToNot(HaveKey(name))
+				seen[name] = true
+			}
+			Expect(l.Image).ToNot(BeEmpty())
+			Expect(l.Repo).ToNot(BeEmpty())
+		}
+	})
+})
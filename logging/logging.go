@@ -0,0 +1,126 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// TextFormat is the format for human-readable logs.
+	TextFormat = "text"
+
+	// JSONFormat is the format for structured logs, with one JSON object per
+	// line. The keys match those expected by Cloud Logging.
+	JSONFormat = "json"
+
+	// FormatEnv specifies the name of the env variable that sets the default
+	// log format.
+	FormatEnv = "LOG_FORMAT"
+
+	// VerbosityEnv specifies the name of the env variable that sets the
+	// default log verbosity.
+	VerbosityEnv = "LOG_VERBOSITY"
+)
+
+// Options configures a logger.
+type Options struct {
+	// Verbosity enables debug messages when greater than zero. Higher values
+	// enable more detailed messages.
+	Verbosity int
+
+	// Format is either TextFormat or JSONFormat.
+	Format string
+}
+
+// OptionsFromEnv returns options with values taken from the FormatEnv and
+// VerbosityEnv env variables. Invalid or missing values are replaced by
+// defaults, which are text output with no debug messages.
+func OptionsFromEnv() *Options {
+	o := &Options{
+		Format: TextFormat,
+	}
+	if format, ok := os.LookupEnv(FormatEnv); ok {
+		o.Format = format
+	}
+	if verbosity, err := strconv.Atoi(os.Getenv(VerbosityEnv)); err == nil {
+		o.Verbosity = verbosity
+	}
+	return o
+}
+
+// BindFlags registers flags for the options on a flag set. The defaults of the
+// flags are taken from the environment, see OptionsFromEnv.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	defaults := OptionsFromEnv()
+	fs.IntVar(&o.Verbosity, "log-verbosity", defaults.Verbosity, "verbosity of logs, debug messages are enabled when greater than zero")
+	fs.StringVar(&o.Format, "log-format", defaults.Format, "format of logs, either text or json")
+}
+
+// New creates a logger with the given options, which writes to stderr.
+func New(o *Options) (*zap.Logger, error) {
+	return newWithOutput(o, zapcore.Lock(os.Stderr))
+}
+
+// newWithOutput creates a logger with the given options, which writes to the
+// given output.
+func newWithOutput(o *Options, output zapcore.WriteSyncer) (*zap.Logger, error) {
+	var encoder zapcore.Encoder
+	switch o.Format {
+	case "", TextFormat:
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case JSONFormat:
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.TimeKey = "time"
+		encoderConfig.LevelKey = "severity"
+		encoderConfig.MessageKey = "message"
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %q or %q", o.Format, TextFormat, JSONFormat)
+	}
+
+	if o.Verbosity < 0 {
+		return nil, fmt.Errorf("log verbosity must not be negative, got %d", o.Verbosity)
+	}
+	level := zap.NewAtomicLevelAt(zapcore.Level(-o.Verbosity))
+
+	core := zapcore.NewCore(encoder, output, level)
+	return zap.New(core, zap.AddCaller()), nil
+}
+
+// Setup creates a logger with the given options and installs it as the global
+// zap logger. The output of the standard library log package is also
+// redirected to it, so existing log calls produce structured logs.
+func Setup(o *Options) (*zap.Logger, error) {
+	logger, err := New(o)
+	if err != nil {
+		return nil, err
+	}
+	zap.ReplaceGlobals(logger)
+	zap.RedirectStdLog(logger)
+	return logger, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ = Describe("New", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		buf = new(bytes.Buffer)
+	})
+
+	It("writes JSON with keys expected by Cloud Logging", func() {
+		logger, err := newWithOutput(&Options{Format: JSONFormat}, zapcore.AddSync(buf))
+		Expect(err).ToNot(HaveOccurred())
+
+		logger.Info("test started", zap.String("test", "example"))
+
+		var entry map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &entry)).To(Succeed())
+		Expect(entry).To(HaveKeyWithValue("severity", "INFO"))
+		Expect(entry).To(HaveKeyWithValue("message", "test started"))
+		Expect(entry).To(HaveKeyWithValue("test", "example"))
+		Expect(entry).To(HaveKey("time"))
+	})
+
+	It("omits debug messages by default", func() {
+		logger, err := newWithOutput(&Options{Format: TextFormat}, zapcore.AddSync(buf))
+		Expect(err).ToNot(HaveOccurred())
+
+		logger.Debug("hidden")
+		Expect(buf.String()).To(BeEmpty())
+	})
+
+	It("includes debug messages when verbosity is increased", func() {
+		logger, err := newWithOutput(&Options{Format: TextFormat, Verbosity: 1}, zapcore.AddSync(buf))
+		Expect(err).ToNot(HaveOccurred())
+
+		logger.Debug("shown")
+		Expect(buf.String()).To(ContainSubstring("shown"))
+	})
+
+	It("returns an error for an unknown format", func() {
+		_, err := New(&Options{Format: "xml"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error for a negative verbosity", func() {
+		_, err := New(&Options{Verbosity: -1})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Options", func() {
+	AfterEach(func() {
+		os.Unsetenv(FormatEnv)
+		os.Unsetenv(VerbosityEnv)
+	})
+
+	It("uses text format without debug messages by default", func() {
+		o := OptionsFromEnv()
+		Expect(o.Format).To(Equal(TextFormat))
+		Expect(o.Verbosity).To(BeZero())
+	})
+
+	It("reads defaults from the environment", func() {
+		os.Setenv(FormatEnv, JSONFormat)
+		os.Setenv(VerbosityEnv, "2")
+
+		o := OptionsFromEnv()
+		Expect(o.Format).To(Equal(JSONFormat))
+		Expect(o.Verbosity).To(Equal(2))
+	})
+
+	It("binds flags that override the environment", func() {
+		os.Setenv(FormatEnv, JSONFormat)
+
+		o := new(Options)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		o.BindFlags(fs)
+		Expect(o.Format).To(Equal(JSONFormat))
+
+		Expect(fs.Parse([]string{"-log-format=text", "-log-verbosity=1"})).To(Succeed())
+		Expect(o.Format).To(Equal(TextFormat))
+		Expect(o.Verbosity).To(Equal(1))
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CounterAggregate summarizes the change of a counter over a window.
+type CounterAggregate struct {
+	// Total is the increase of the counter over the window.
+	Total uint64 `json:"total"`
+
+	// MeanRate is the average increase of the counter per second over the
+	// window.
+	MeanRate float64 `json:"meanRate"`
+
+	// MaxRate is the highest increase of the counter per second between
+	// consecutive samples.
+	MaxRate float64 `json:"maxRate"`
+}
+
+// NodeMetrics contains the aggregates of the samples of a node taken during a
+// window, such as the benchmark of a load test.
+type NodeMetrics struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+
+	// Start is the time of the first sample in the window.
+	Start time.Time `json:"start"`
+
+	// End is the time of the last sample in the window.
+	End time.Time `json:"end"`
+
+	// Samples is the number of samples in the window.
+	Samples int `json:"samples"`
+
+	// Counters maps the name of each counter to its aggregate.
+	Counters map[string]CounterAggregate `json:"counters"`
+}
+
+// Aggregate accepts the samples of a node, in the order they were taken, and
+// returns the aggregate of each counter over the window they cover. Only the
+// counters present in every sample are aggregated. A counter that decreases
+// between two samples, such as the counter of an interface that was
+// recreated, is treated as unchanged between them. An error is returned if
+// there are fewer than two samples or the samples do not cover any time.
+func Aggregate(node string, samples []Sample) (*NodeMetrics, error) {
+	if len(samples) < 2 {
+		return nil, errors.Errorf("at least 2 samples are required, got %d", len(samples))
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	window := last.Time.Sub(first.Time).Seconds()
+	if window <= 0 {
+		return nil, errors.New("samples do not cover any time")
+	}
+
+	metrics := &NodeMetrics{
+		Node:     node,
+		Start:    first.Time,
+		End:      last.Time,
+		Samples:  len(samples),
+		Counters: make(map[string]CounterAggregate),
+	}
+
+counters:
+	for name := range first.Counters {
+		var aggregate CounterAggregate
+		for i := 1; i < len(samples); i++ {
+			previous, ok := samples[i-1].Counters[name]
+			if !ok {
+				continue counters
+			}
+			current, ok := samples[i].Counters[name]
+			if !ok {
+				continue counters
+			}
+			if current < previous {
+				continue
+			}
+			delta := current - previous
+			aggregate.Total += delta
+			if seconds := samples[i].Time.Sub(samples[i-1].Time).Seconds(); seconds > 0 {
+				if rate := float64(delta) / seconds; rate > aggregate.MaxRate {
+					aggregate.MaxRate = rate
+				}
+			}
+		}
+		aggregate.MeanRate = float64(aggregate.Total) / window
+		metrics.Counters[name] = aggregate
+	}
+	return metrics, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aggregate", func() {
+	var start time.Time
+
+	BeforeEach(func() {
+		start = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	})
+
+	It("returns the total, mean rate and highest rate of each counter", func() {
+		samples := []Sample{
+			{Time: start, Counters: map[string]uint64{ContextSwitchesCounter: 1000}},
+			{Time: start.Add(5 * time.Second), Counters: map[string]uint64{ContextSwitchesCounter: 1500}},
+			{Time: start.Add(10 * time.Second), Counters: map[string]uint64{ContextSwitchesCounter: 3000}},
+		}
+
+		metrics, err := Aggregate("node-1", samples)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics.Node).To(Equal("node-1"))
+		Expect(metrics.Start).To(Equal(start))
+		Expect(metrics.End).To(Equal(start.Add(10 * time.Second)))
+		Expect(metrics.Samples).To(Equal(3))
+		Expect(metrics.Counters).To(Equal(map[string]CounterAggregate{
+			ContextSwitchesCounter: {Total: 2000, MeanRate: 200, MaxRate: 300},
+		}))
+	})
+
+	It("ignores decreases of a counter", func() {
+		samples := []Sample{
+			{Time: start, Counters: map[string]uint64{"net_eth0_rx_bytes": 1000}},
+			{Time: start.Add(5 * time.Second), Counters: map[string]uint64{"net_eth0_rx_bytes": 10}},
+			{Time: start.Add(10 * time.Second), Counters: map[string]uint64{"net_eth0_rx_bytes": 510}},
+		}
+
+		metrics, err := Aggregate("node-1", samples)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics.Counters["net_eth0_rx_bytes"]).To(Equal(CounterAggregate{Total: 500, MeanRate: 50, MaxRate: 100}))
+	})
+
+	It("skips counters missing from some samples", func() {
+		samples := []Sample{
+			{Time: start, Counters: map[string]uint64{InterruptsCounter: 10, "net_veth1_rx_bytes": 10}},
+			{Time: start.Add(5 * time.Second), Counters: map[string]uint64{InterruptsCounter: 20}},
+		}
+
+		metrics, err := Aggregate("node-1", samples)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics.Counters).To(HaveKey(InterruptsCounter))
+		Expect(metrics.Counters).ToNot(HaveKey("net_veth1_rx_bytes"))
+	})
+
+	It("returns an error with fewer than two samples", func() {
+		_, err := Aggregate("node-1", []Sample{{Time: start, Counters: map[string]uint64{}}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the samples do not cover any time", func() {
+		samples := []Sample{
+			{Time: start, Counters: map[string]uint64{}},
+			{Time: start, Counters: map[string]uint64{}},
+		}
+		_, err := Aggregate("node-1", samples)
+		Expect(err).To(HaveOccurred())
+	})
+})
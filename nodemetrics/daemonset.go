@@ -0,0 +1,122 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// DaemonSet returns the DaemonSet that runs the node agent in a namespace with
+// the given settings. The agent uses the network of the node, so that it reads
+// the counters of the interfaces of the node. It tolerates all taints, since
+// the nodes that run load tests are often tainted to keep other workloads
+// away, and it is limited to the nodes with one of the pool labels in the
+// settings, if any.
+func DaemonSet(namespace string, settings *config.NodeAgent) *appsv1.DaemonSet {
+	port := settings.AgentPort()
+
+	podSpec := corev1.PodSpec{
+		HostNetwork: true,
+		DNSPolicy:   corev1.DNSClusterFirstWithHostNet,
+		Tolerations: []corev1.Toleration{
+			{Operator: corev1.TolerationOpExists},
+		},
+		Containers: []corev1.Container{
+			{
+				Name:    config.NodeAgentName,
+				Image:   settings.Image,
+				Command: []string{"node-agent"},
+				Args: []string{
+					fmt.Sprintf("-port=%d", port),
+					fmt.Sprintf("-interval=%v", settings.SampleInterval()),
+				},
+				Ports: []corev1.ContainerPort{
+					{
+						Name:          config.NodeAgentPortName,
+						ContainerPort: port,
+						Protocol:      corev1.ProtocolTCP,
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("10m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+				},
+			},
+		},
+	}
+
+	if len(settings.PoolLabels) > 0 {
+		var terms []corev1.NodeSelectorTerm
+		for _, label := range settings.PoolLabels {
+			terms = append(terms, corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{
+						Key:      label,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{"true"},
+					},
+				},
+			})
+		}
+		podSpec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: terms,
+				},
+			},
+		}
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.NodeAgentName,
+			Namespace: namespace,
+			Labels:    agentLabels(),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: agentLabels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: agentLabels()},
+				Spec:       podSpec,
+			},
+		},
+	}
+}
+
+// agentLabels returns the labels of the node agent, which select its pods.
+func agentLabels() map[string]string {
+	return map[string]string{"app": config.NodeAgentName}
+}
+
+// AgentSelector returns the label selector that matches the pods of the node
+// agent.
+func AgentSelector() string {
+	return labels.SelectorFromSet(agentLabels()).String()
+}
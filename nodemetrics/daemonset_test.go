@@ -0,0 +1,59 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("DaemonSet", func() {
+	It("runs the agent with the network of the node", func() {
+		daemonSet := DaemonSet("test-infra", &config.NodeAgent{Image: "node-agent:latest"})
+
+		Expect(daemonSet.Name).To(Equal(config.NodeAgentName))
+		Expect(daemonSet.Namespace).To(Equal("test-infra"))
+		Expect(daemonSet.Spec.Selector.MatchLabels).To(Equal(daemonSet.Spec.Template.Labels))
+
+		podSpec := daemonSet.Spec.Template.Spec
+		Expect(podSpec.HostNetwork).To(BeTrue())
+		Expect(podSpec.Affinity).To(BeNil())
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Containers[0].Image).To(Equal("node-agent:latest"))
+		Expect(podSpec.Containers[0].Args).To(ConsistOf("-port=9102", "-interval=5s"))
+		Expect(podSpec.Containers[0].Ports[0].ContainerPort).To(BeEquivalentTo(config.DefaultNodeAgentPort))
+	})
+
+	It("limits the agent to nodes with a pool label", func() {
+		daemonSet := DaemonSet("test-infra", &config.NodeAgent{
+			Image:      "node-agent:latest",
+			Port:       9200,
+			PoolLabels: []string{"pool-a", "pool-b"},
+		})
+
+		podSpec := daemonSet.Spec.Template.Spec
+		Expect(podSpec.Containers[0].Args).To(ContainElement("-port=9200"))
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(2))
+		Expect(terms[1].MatchExpressions).To(Equal([]corev1.NodeSelectorRequirement{
+			{Key: "pool-b", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+		}))
+	})
+})
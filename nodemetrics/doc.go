@@ -0,0 +1,22 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodemetrics contains code for sampling system metrics of the nodes
+// that run load tests, such as softirqs, context switches and NIC counters,
+// and for aggregating the samples taken during the benchmark of a test. The
+// samples are taken by a node agent, which runs on each node as part of a
+// DaemonSet.
+package nodemetrics
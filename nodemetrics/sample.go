@@ -0,0 +1,209 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ContextSwitchesCounter is the name of the counter of context switches
+	// across all CPUs.
+	ContextSwitchesCounter = "context_switches"
+
+	// InterruptsCounter is the name of the counter of interrupts serviced
+	// across all CPUs.
+	InterruptsCounter = "interrupts"
+
+	// CPUIRQTicksCounter is the name of the counter of the time that all
+	// CPUs spent servicing interrupts, in clock ticks.
+	CPUIRQTicksCounter = "cpu_irq_ticks"
+
+	// CPUSoftIRQTicksCounter is the name of the counter of the time that all
+	// CPUs spent servicing softirqs, in clock ticks.
+	CPUSoftIRQTicksCounter = "cpu_softirq_ticks"
+
+	// SoftIRQCounterPrefix is the prefix of the names of the counters of
+	// each type of softirq across all CPUs, such as softirq_net_rx.
+	SoftIRQCounterPrefix = "softirq_"
+
+	// NetCounterPrefix is the prefix of the names of the counters of each
+	// network interface, such as net_eth0_rx_bytes.
+	NetCounterPrefix = "net_"
+)
+
+// netDevFields maps the position of each field of an interface in
+// /proc/net/dev to the suffix of the name of its counter. Fields that are not
+// listed are ignored.
+var netDevFields = map[int]string{
+	0:  "rx_bytes",
+	1:  "rx_packets",
+	2:  "rx_errors",
+	3:  "rx_dropped",
+	8:  "tx_bytes",
+	9:  "tx_packets",
+	10: "tx_errors",
+	11: "tx_dropped",
+}
+
+// Sample contains the values of the cumulative counters of a node at a point
+// in time.
+type Sample struct {
+	// Time is the time when the sample was taken.
+	Time time.Time `json:"time"`
+
+	// Counters maps the name of each counter to its value.
+	Counters map[string]uint64 `json:"counters"`
+}
+
+// ReadSample reads the counters of a node from a proc filesystem mounted at
+// procDir, which is usually /proc. The counters of network interfaces are
+// those of the network namespace of the process, so the agent must use the
+// network of the node. The loopback interface is ignored.
+func ReadSample(procDir string) (*Sample, error) {
+	sample := &Sample{
+		Time:     time.Now(),
+		Counters: make(map[string]uint64),
+	}
+
+	parsers := []struct {
+		name  string
+		parse func(io.Reader, map[string]uint64) error
+	}{
+		{"stat", parseStat},
+		{"softirqs", parseSoftIRQs},
+		{filepath.Join("net", "dev"), parseNetDev},
+	}
+	for _, p := range parsers {
+		fileName := filepath.Join(procDir, p.name)
+		file, err := os.Open(fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open %s", fileName)
+		}
+		err = p.parse(file, sample.Counters)
+		file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", fileName)
+		}
+	}
+	return sample, nil
+}
+
+// parseStat reads the counters of context switches, interrupts and the time
+// spent servicing interrupts from the format of /proc/stat.
+func parseStat(r io.Reader, counters map[string]uint64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "ctxt":
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "invalid context switch count")
+			}
+			counters[ContextSwitchesCounter] = value
+		case "intr":
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "invalid interrupt count")
+			}
+			counters[InterruptsCounter] = value
+		case "cpu":
+			// The fields are user, nice, system, idle, iowait, irq
+			// and softirq, followed by fields added in later kernels.
+			if len(fields) < 8 {
+				return errors.Errorf("expected at least 7 CPU times, got %d", len(fields)-1)
+			}
+			irq, err := strconv.ParseUint(fields[6], 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "invalid CPU irq time")
+			}
+			softirq, err := strconv.ParseUint(fields[7], 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "invalid CPU softirq time")
+			}
+			counters[CPUIRQTicksCounter] = irq
+			counters[CPUSoftIRQTicksCounter] = softirq
+		}
+	}
+	return scanner.Err()
+}
+
+// parseSoftIRQs reads the count of each type of softirq, summed across all
+// CPUs, from the format of /proc/softirqs.
+func parseSoftIRQs(r io.Reader, counters map[string]uint64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		name, values, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			// The header lists the CPUs.
+			continue
+		}
+		var total uint64
+		for _, field := range strings.Fields(values) {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid count of softirq %s", strings.TrimSpace(name))
+			}
+			total += value
+		}
+		counters[SoftIRQCounterPrefix+strings.ToLower(strings.TrimSpace(name))] = total
+	}
+	return scanner.Err()
+}
+
+// parseNetDev reads the counters of bytes, packets, errors and drops received
+// and transmitted by each network interface from the format of /proc/net/dev.
+func parseNetDev(r io.Reader, counters map[string]uint64) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		iface, values, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			// The header names the fields.
+			continue
+		}
+		iface = strings.TrimSpace(iface)
+		if iface == "lo" {
+			continue
+		}
+		fields := strings.Fields(values)
+		if len(fields) < 16 {
+			return errors.Errorf("expected 16 counters for interface %s, got %d", iface, len(fields))
+		}
+		for i, suffix := range netDevFields {
+			value, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s counter for interface %s", suffix, iface)
+			}
+			counters[NetCounterPrefix+iface+"_"+suffix] = value
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const testStat = `cpu  4705 356 584 3699176 23060 12 342 0 0 0
+cpu0 1393 280 283 1837585 11479 8 191 0 0 0
+cpu1 3312 76 301 1861591 11581 4 151 0 0 0
+intr 8688370 45 2 0 0 0 0 0 0 1
+ctxt 20146872
+btime 1654070400
+processes 10582
+`
+
+const testSoftIRQs = `                    CPU0       CPU1
+          HI:          1          2
+       TIMER:     100000     200000
+      NET_TX:         10         20
+      NET_RX:       3000       4000
+`
+
+const testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:  123456     100    0    0    0     0          0         0   123456     100    0    0    0     0       0          0
+  eth0: 9876543    7000    1    2    0     0          0         0  5432100    6000    3    4    0     0       0          0
+`
+
+var _ = Describe("ReadSample", func() {
+	var procDir string
+
+	BeforeEach(func() {
+		var err error
+		procDir, err = os.MkdirTemp("", "proc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(procDir, "net"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(procDir, "stat"), []byte(testStat), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(procDir, "softirqs"), []byte(testSoftIRQs), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(procDir, "net", "dev"), []byte(testNetDev), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(procDir)
+	})
+
+	It("reads the counters of the node", func() {
+		sample, err := ReadSample(procDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sample.Time).ToNot(BeZero())
+		Expect(sample.Counters).To(Equal(map[string]uint64{
+			ContextSwitchesCounter: 20146872,
+			InterruptsCounter:      8688370,
+			CPUIRQTicksCounter:     12,
+			CPUSoftIRQTicksCounter: 342,
+			"softirq_hi":           3,
+			"softirq_timer":        300000,
+			"softirq_net_tx":       30,
+			"softirq_net_rx":       7000,
+			"net_eth0_rx_bytes":    9876543,
+			"net_eth0_rx_packets":  7000,
+			"net_eth0_rx_errors":   1,
+			"net_eth0_rx_dropped":  2,
+			"net_eth0_tx_bytes":    5432100,
+			"net_eth0_tx_packets":  6000,
+			"net_eth0_tx_errors":   3,
+			"net_eth0_tx_dropped":  4,
+		}))
+	})
+
+	It("returns an error when a file is missing", func() {
+		Expect(os.Remove(filepath.Join(procDir, "softirqs"))).To(Succeed())
+		_, err := ReadSample(procDir)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when a counter is malformed", func() {
+		Expect(os.WriteFile(filepath.Join(procDir, "stat"), []byte("ctxt many\n"), 0644)).To(Succeed())
+		_, err := ReadSample(procDir)
+		Expect(err).To(HaveOccurred())
+	})
+})
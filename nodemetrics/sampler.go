@@ -0,0 +1,132 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SamplesPath is the path where the node agent serves its samples.
+const SamplesPath = "/samples"
+
+const (
+	// SinceParam is the name of the query parameter with the time of the
+	// earliest sample to return, in RFC 3339 format.
+	SinceParam = "since"
+
+	// UntilParam is the name of the query parameter with the time of the
+	// latest sample to return, in RFC 3339 format.
+	UntilParam = "until"
+)
+
+// Sampler takes samples of the counters of a node periodically, and keeps the
+// samples taken within a retention period. It serves the samples that it keeps
+// as JSON over HTTP.
+type Sampler struct {
+	// ProcDir is the directory where the proc filesystem is mounted.
+	ProcDir string
+
+	// Interval is the time between samples.
+	Interval time.Duration
+
+	// Retention is the time that samples are kept.
+	Retention time.Duration
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// Run takes samples until the context is cancelled. Errors encountered while
+// taking a sample are passed to onError, and the sample is skipped.
+func (s *Sampler) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		sample, err := ReadSample(s.ProcDir)
+		if err != nil {
+			onError(err)
+		} else {
+			s.add(*sample)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// add keeps a sample and drops the samples that are older than the retention
+// period.
+func (s *Sampler) add(sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = append(s.samples, sample)
+	cutoff := sample.Time.Add(-s.Retention)
+	i := 0
+	for i < len(s.samples) && s.samples[i].Time.Before(cutoff) {
+		i++
+	}
+	s.samples = s.samples[i:]
+}
+
+// Samples returns the samples taken between two times, inclusive, in the
+// order they were taken. A zero time does not limit the samples.
+func (s *Sampler) Samples(since, until time.Time) []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := []Sample{}
+	for _, sample := range s.samples {
+		if !since.IsZero() && sample.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && sample.Time.After(until) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// ServeHTTP responds with the samples taken between the times in the
+// SinceParam and UntilParam query parameters, encoded as a JSON array.
+func (s *Sampler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var since, until time.Time
+	for param, t := range map[string]*time.Time{SinceParam: &since, UntilParam: &until} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "invalid "+param+" parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		*t = parsed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Samples(since, until))
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sampler", func() {
+	var sampler *Sampler
+	var start time.Time
+
+	BeforeEach(func() {
+		sampler = &Sampler{Interval: time.Second, Retention: time.Minute}
+		start = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+		for i := 0; i < 5; i++ {
+			sampler.add(Sample{
+				Time:     start.Add(time.Duration(i) * 10 * time.Second),
+				Counters: map[string]uint64{ContextSwitchesCounter: uint64(i)},
+			})
+		}
+	})
+
+	It("returns the samples between two times", func() {
+		samples := sampler.Samples(start.Add(10*time.Second), start.Add(30*time.Second))
+		Expect(samples).To(HaveLen(3))
+		Expect(samples[0].Counters[ContextSwitchesCounter]).To(BeEquivalentTo(1))
+		Expect(samples[2].Counters[ContextSwitchesCounter]).To(BeEquivalentTo(3))
+	})
+
+	It("drops samples older than the retention", func() {
+		sampler.add(Sample{Time: start.Add(90 * time.Second)})
+		samples := sampler.Samples(time.Time{}, time.Time{})
+		Expect(samples).To(HaveLen(3))
+		Expect(samples[0].Time).To(Equal(start.Add(30 * time.Second)))
+	})
+
+	It("serves the samples between the times in the query", func() {
+		req := httptest.NewRequest(http.MethodGet, SamplesPath+"?since=2022-06-01T10:00:30Z", nil)
+		rec := httptest.NewRecorder()
+		sampler.ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var samples []Sample
+		Expect(json.Unmarshal(rec.Body.Bytes(), &samples)).To(Succeed())
+		Expect(samples).To(HaveLen(2))
+	})
+
+	It("rejects malformed times", func() {
+		req := httptest.NewRequest(http.MethodGet, SamplesPath+"?until=yesterday", nil)
+		rec := httptest.NewRecorder()
+		sampler.ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+})
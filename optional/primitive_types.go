@@ -25,3 +25,13 @@ func StringPtr(str string) *string {
 func Int32Ptr(n int32) *int32 {
 	return &n
 }
+
+// IntPtr accepts an integer and returns a pointer to it.
+func IntPtr(n int) *int {
+	return &n
+}
+
+// Int64Ptr accepts a 64-bit integer and returns a pointer to it.
+func Int64Ptr(n int64) *int64 {
+	return &n
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// terminationGracePeriod returns the time given to the pods of a load test to
+// stop after the test is deleted. The grace period set on the test takes
+// precedence over the defaults.
+func terminationGracePeriod(defs *config.Defaults, test *grpcv1.LoadTest) *int64 {
+	gracePeriod := int64(config.DefaultTerminationGracePeriodSeconds)
+	if test.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *test.Spec.TerminationGracePeriodSeconds
+	} else if defs.TerminationGracePeriodSeconds > 0 {
+		gracePeriod = defs.TerminationGracePeriodSeconds
+	}
+	return &gracePeriod
+}
+
+// killAfter returns the time allowed for the containers of a load test to
+// respond after the timeout, in seconds. The value set on the test takes
+// precedence over the defaults.
+func killAfter(defs *config.Defaults, test *grpcv1.LoadTest) float64 {
+	if test.Spec.KillAfterSeconds != nil {
+		return float64(*test.Spec.KillAfterSeconds)
+	}
+	return defs.KillAfter
+}
+
+// addDriverCancellation prepares the driver to be cancelled when its load test
+// is deleted. The preStop hook of the run container creates a marker file
+// before the driver receives SIGTERM, so the driver can tell a cancellation
+// from a timeout. The driver then has the termination grace period to ask the
+// workers to quit and to flush partial results marked as cancelled. A preStop
+// hook set in the test configuration is left unchanged.
+func addDriverCancellation(defs *config.Defaults, test *grpcv1.LoadTest, pod *corev1.Pod, container *corev1.Container) {
+	pod.Spec.TerminationGracePeriodSeconds = terminationGracePeriod(defs, test)
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.CancelledMarkerFileEnv,
+		Value: config.CancelledMarkerFile,
+	})
+	setPreStopCommand(container, "touch", config.CancelledMarkerFile)
+}
+
+// addWorkerCancellation prepares a client or server to be stopped when its load
+// test is deleted. The preStop hook of the run container delays SIGTERM, so the
+// worker remains available while the driver asks it to quit. A preStop hook set
+// in the test configuration is left unchanged.
+func addWorkerCancellation(defs *config.Defaults, test *grpcv1.LoadTest, pod *corev1.Pod, container *corev1.Container) {
+	pod.Spec.TerminationGracePeriodSeconds = terminationGracePeriod(defs, test)
+	setPreStopCommand(container, "sleep", fmt.Sprint(config.WorkerStopDelaySeconds))
+}
+
+// setPreStopCommand sets a command as the preStop hook of a container, unless
+// the container already has a preStop hook.
+func setPreStopCommand(container *corev1.Container, command ...string) {
+	lifecycle := corev1.Lifecycle{}
+	if container.Lifecycle != nil {
+		if container.Lifecycle.PreStop != nil {
+			return
+		}
+		lifecycle = *container.Lifecycle
+	}
+	lifecycle.PreStop = &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{
+			Command: command,
+		},
+	}
+	container.Lifecycle = &lifecycle
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("Cancellation", func() {
+	var defaults *config.Defaults
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		defaults = newDefaults()
+		test = newLoadTest()
+	})
+
+	It("marks the driver as cancelled before it is stopped", func() {
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(pod.Spec.TerminationGracePeriodSeconds).ToNot(BeNil())
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(config.DefaultTerminationGracePeriodSeconds))
+
+		runContainer := pod.Spec.Containers[0]
+		Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{
+			Name:  config.CancelledMarkerFileEnv,
+			Value: config.CancelledMarkerFile,
+		}))
+		Expect(runContainer.Lifecycle).ToNot(BeNil())
+		Expect(runContainer.Lifecycle.PreStop.Exec.Command).To(Equal([]string{"touch", config.CancelledMarkerFile}))
+	})
+
+	It("delays stopping the workers", func() {
+		for i := range test.Spec.Servers {
+			pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.TerminationGracePeriodSeconds).ToNot(BeNil())
+			Expect(pod.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"sleep", fmt.Sprint(config.WorkerStopDelaySeconds)}))
+		}
+		for i := range test.Spec.Clients {
+			pod, err := New(defaults, test).PodForClient(&test.Spec.Clients[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.TerminationGracePeriodSeconds).ToNot(BeNil())
+			Expect(pod.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"sleep", fmt.Sprint(config.WorkerStopDelaySeconds)}))
+		}
+	})
+
+	It("uses the grace period from the defaults", func() {
+		defaults.TerminationGracePeriodSeconds = 120
+
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(120))
+	})
+
+	It("prefers the grace period of the test to the defaults", func() {
+		defaults.TerminationGracePeriodSeconds = 120
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(30)
+
+		pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(30))
+	})
+
+	It("prefers the kill-after of the test to the defaults", func() {
+		defaults.KillAfter = 20
+		test.Spec.KillAfterSeconds = optional.Int32Ptr(45)
+
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  config.KillAfterEnv,
+			Value: fmt.Sprintf("%f", 45.0),
+		}))
+	})
+
+	It("keeps a preStop hook from the test configuration", func() {
+		preStop := &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{"/bin/stop"}},
+		}
+		test.Spec.Driver.Run[0].Lifecycle = &corev1.Lifecycle{PreStop: preStop}
+
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Lifecycle.PreStop).To(Equal(preStop))
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// errNoInteropImage is the base error when a load test is an interop test, but
+// the defaults do not provide an image with the interop program.
+var errNoInteropImage = errors.New("interop image is missing")
+
+// errNoInteropCommand is the base error when the run container of the driver
+// of an interop test does not set a command that can be wrapped.
+var errNoInteropCommand = errors.New("interop client command is missing")
+
+// errNoInteropServer is the base error when the server that the interop client
+// should connect to cannot be found.
+var errNoInteropServer = errors.New("interop server is missing")
+
+// interopServerName returns the name of the server that the interop client of
+// a test connects to.
+func interopServerName(test *grpcv1.LoadTest) (string, error) {
+	if name := test.Spec.Interop.Server; name != nil {
+		for i := range test.Spec.Servers {
+			if safeStrUnwrap(test.Spec.Servers[i].Name) == *name {
+				return *name, nil
+			}
+		}
+		return "", errors.Wrapf(errNoInteropServer, "test has no server named %q", *name)
+	}
+	if len(test.Spec.Servers) == 0 {
+		return "", errors.Wrap(errNoInteropServer, "test has no servers")
+	}
+	return safeStrUnwrap(test.Spec.Servers[0].Name), nil
+}
+
+// addInteropRunner turns the run container of a driver into the interop client
+// of an interop test. An init container copies the interop program into a
+// volume, and the command of the run container is wrapped by the program. The
+// program runs the command once for each test case, adding the flags that
+// select the server and the test case, and writes the result of each case to
+// the termination message.
+//
+// The environment variables of the run container describe the server and the
+// test cases, so they can also be used by a command that does not accept the
+// standard flags of the interop client.
+func addInteropRunner(defs *config.Defaults, test *grpcv1.LoadTest, podspec *corev1.PodSpec, container *corev1.Container) error {
+	if defs.InteropImage == "" {
+		return errors.Wrapf(errNoInteropImage, "cannot run interop test %q", test.Name)
+	}
+	if len(container.Command) == 0 {
+		return errors.Wrapf(errNoInteropCommand, "the run container of the driver of interop test %q must set a command", test.Name)
+	}
+
+	serverName, err := interopServerName(test)
+	if err != nil {
+		return err
+	}
+
+	podspec.InitContainers = append(podspec.InitContainers, corev1.Container{
+		Name:    config.InteropInitContainerName,
+		Image:   defs.InteropImage,
+		Command: []string{"cp", "/usr/local/bin/interop", config.InteropBinaryPath},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      config.InteropVolumeName,
+				MountPath: config.InteropMountPath,
+			},
+		},
+	})
+	podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+		Name: config.InteropVolumeName,
+	})
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      config.InteropVolumeName,
+		MountPath: config.InteropMountPath,
+		ReadOnly:  true,
+	})
+	container.Args = append(append([]string{"--"}, container.Command...), container.Args...)
+	container.Command = []string{config.InteropBinaryPath}
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name:  config.InteropServerHostEnv,
+			Value: fmt.Sprintf("%s.%s", kubehelpers.ComponentHostname(config.ServerRole, serverName), test.Name),
+		},
+		corev1.EnvVar{
+			Name:  config.InteropServerPortEnv,
+			Value: fmt.Sprint(config.ServerPort),
+		},
+		corev1.EnvVar{
+			Name:  config.InteropTestCasesEnv,
+			Value: strings.Join(test.Spec.Interop.TestCases, ","),
+		},
+		corev1.EnvVar{
+			Name:  config.InteropUseTLSEnv,
+			Value: fmt.Sprint(test.Spec.Interop.UseTLS),
+		})
+	return nil
+}
+
+// addInteropServerPort sets the port where the server of an interop test should
+// listen in the environment of its run container, so the command of the server
+// can refer to it as $(INTEROP_SERVER_PORT).
+func addInteropServerPort(container *corev1.Container) {
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.InteropServerPortEnv,
+		Value: fmt.Sprint(config.ServerPort),
+	})
+}
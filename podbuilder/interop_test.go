@@ -0,0 +1,119 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("Interop tests", func() {
+	var test *grpcv1.LoadTest
+	var builder *PodBuilder
+
+	BeforeEach(func() {
+		test = newLoadTest()
+		test.Spec.Interop = &grpcv1.Interop{
+			TestCases: []string{"empty_unary", "large_unary"},
+		}
+		test.Spec.Driver.Run[0].Command = []string{"interop_client"}
+		test.Spec.Driver.Run[0].Args = []string{"--verbose"}
+
+		defaults := newDefaults()
+		defaults.InteropImage = "gcr.io/grpc-fake-project/test-infra/interop"
+		builder = New(defaults, test)
+	})
+
+	Describe("PodForDriver", func() {
+		It("wraps the command of the run container with the interop program", func() {
+			pod, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).ToNot(HaveOccurred())
+
+			runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+			Expect(runContainer.Command).To(Equal([]string{config.InteropBinaryPath}))
+			Expect(runContainer.Args).To(Equal([]string{"--", "interop_client", "--verbose"}))
+			Expect(getNames(runContainer.VolumeMounts)).To(ContainElement(config.InteropVolumeName))
+		})
+
+		It("adds an init container that provides the interop program", func() {
+			pod, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).ToNot(HaveOccurred())
+
+			initContainer := kubehelpers.ContainerForName(config.InteropInitContainerName, pod.Spec.InitContainers)
+			Expect(initContainer).ToNot(BeNil())
+			Expect(initContainer.Image).To(Equal(builder.defaults.InteropImage))
+			Expect(getNames(pod.Spec.Volumes)).To(ContainElement(config.InteropVolumeName))
+		})
+
+		It("describes the server and test cases in the environment", func() {
+			pod, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).ToNot(HaveOccurred())
+
+			runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+			serverName := *test.Spec.Servers[0].Name
+			Expect(runContainer.Env).To(ContainElements(
+				corev1.EnvVar{
+					Name:  config.InteropServerHostEnv,
+					Value: kubehelpers.ComponentHostname(config.ServerRole, serverName) + "." + test.Name,
+				},
+				corev1.EnvVar{Name: config.InteropServerPortEnv, Value: "10010"},
+				corev1.EnvVar{Name: config.InteropTestCasesEnv, Value: "empty_unary,large_unary"},
+				corev1.EnvVar{Name: config.InteropUseTLSEnv, Value: "false"},
+			))
+		})
+
+		It("returns an error when the interop server does not exist", func() {
+			test.Spec.Interop.Server = optional.StringPtr("missing")
+
+			_, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the run container has no command", func() {
+			test.Spec.Driver.Run[0].Command = nil
+
+			_, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error when the defaults have no interop image", func() {
+			builder.defaults.InteropImage = ""
+
+			_, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("PodForServer", func() {
+		It("sets the port where the server should listen", func() {
+			pod, err := builder.PodForServer(&test.Spec.Servers[0])
+			Expect(err).ToNot(HaveOccurred())
+
+			runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+			Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{
+				Name:  config.InteropServerPortEnv,
+				Value: "10010",
+			}))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// istioProxyConfig holds the configuration of the Istio sidecar of a worker.
+// The run container is held until the sidecar has started, so the worker does
+// not report that it is ready before its traffic can flow through the mesh.
+const istioProxyConfig = `{"holdApplicationUntilProxyStarts":true}`
+
+// configureServiceMesh sets the labels and annotations that a service mesh
+// reads to decide whether to inject its sidecar proxy into a pod. Worker pods
+// are injected, while the driver is explicitly excluded, since a proxy would
+// keep running after the driver exits. The driver port of workers bypasses
+// the proxy, so the driver can reach workers from outside the mesh. The pod is
+// left unchanged if the test does not use a service mesh.
+func configureServiceMesh(pod *corev1.Pod, mesh grpcv1.ServiceMesh, inject bool, port int32) {
+	if mesh == "" {
+		return
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	driverPort := fmt.Sprint(port)
+
+	switch mesh {
+	case grpcv1.IstioServiceMesh:
+		pod.Labels[config.IstioInjectLabel] = fmt.Sprint(inject)
+		if inject {
+			pod.Annotations[config.IstioProxyConfigAnnotation] = istioProxyConfig
+			pod.Annotations[config.IstioExcludeInboundPortsAnnotation] = driverPort
+		}
+	case grpcv1.LinkerdServiceMesh:
+		if !inject {
+			pod.Annotations[config.LinkerdInjectAnnotation] = "disabled"
+			return
+		}
+		pod.Annotations[config.LinkerdInjectAnnotation] = "enabled"
+		pod.Annotations[config.LinkerdSkipInboundPortsAnnotation] = driverPort
+	}
+}
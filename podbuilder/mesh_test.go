@@ -0,0 +1,96 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("ServiceMesh", func() {
+	var test *grpcv1.LoadTest
+	var builder *PodBuilder
+
+	BeforeEach(func() {
+		test = newLoadTest()
+		builder = New(newDefaults(), test)
+	})
+
+	It("does not configure a mesh by default", func() {
+		pods, err := builder.RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, pod := range pods {
+			Expect(pod.Labels).ToNot(HaveKey(config.IstioInjectLabel))
+			Expect(pod.Annotations).ToNot(HaveKey(config.LinkerdInjectAnnotation))
+		}
+	})
+
+	Context("with Istio", func() {
+		BeforeEach(func() {
+			test.Spec.ServiceMesh = grpcv1.IstioServiceMesh
+		})
+
+		It("injects the sidecar into workers", func() {
+			for i := range test.Spec.Servers {
+				pod, err := builder.PodForServer(&test.Spec.Servers[i])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Labels).To(HaveKeyWithValue(config.IstioInjectLabel, "true"))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.IstioProxyConfigAnnotation, istioProxyConfig))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.IstioExcludeInboundPortsAnnotation, fmt.Sprint(config.DriverPort)))
+			}
+			for i := range test.Spec.Clients {
+				pod, err := builder.PodForClient(&test.Spec.Clients[i])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Labels).To(HaveKeyWithValue(config.IstioInjectLabel, "true"))
+			}
+		})
+
+		It("excludes the driver", func() {
+			pod, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).To(HaveKeyWithValue(config.IstioInjectLabel, "false"))
+			Expect(pod.Annotations).ToNot(HaveKey(config.IstioProxyConfigAnnotation))
+		})
+	})
+
+	Context("with Linkerd", func() {
+		BeforeEach(func() {
+			test.Spec.ServiceMesh = grpcv1.LinkerdServiceMesh
+		})
+
+		It("injects the proxy into workers", func() {
+			for i := range test.Spec.Servers {
+				pod, err := builder.PodForServer(&test.Spec.Servers[i])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.LinkerdInjectAnnotation, "enabled"))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.LinkerdSkipInboundPortsAnnotation, fmt.Sprint(config.DriverPort)))
+			}
+		})
+
+		It("excludes the driver", func() {
+			pod, err := builder.PodForDriver(test.Spec.Driver)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Annotations).To(HaveKeyWithValue(config.LinkerdInjectAnnotation, "disabled"))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// sharedPlacement returns true if the pods of the test may share nodes.
+func (pb *PodBuilder) sharedPlacement() bool {
+	return pb.test.Spec.PlacementPolicy == grpcv1.SharedPlacement
+}
+
+// workerDriverPort returns the port where the worker being built listens for
+// the driver. With exclusive placement, every worker uses DriverPort. With
+// shared placement, each worker of the test is allocated its own port, counting
+// up from DriverPort in the order of the servers followed by the clients. The
+// allocation is deterministic, so a pod that is rebuilt keeps its port.
+func (pb *PodBuilder) workerDriverPort() int32 {
+	if !pb.sharedPlacement() {
+		return config.DriverPort
+	}
+
+	index := 0
+	for i := range pb.test.Spec.Servers {
+		if pb.role == config.ServerRole && pb.test.Spec.Servers[i].Name != nil && *pb.test.Spec.Servers[i].Name == pb.name {
+			return config.DriverPort + int32(index)
+		}
+		index++
+	}
+	for _, client := range kubehelpers.ExpandClients(pb.test.Spec.Clients) {
+		if pb.role == config.ClientRole && client.Name != nil && *client.Name == pb.name {
+			return config.DriverPort + int32(index)
+		}
+		index++
+	}
+	return config.DriverPort + int32(index)
+}
+
+// exposeDriverPort sets the driver port of a worker in the environment of its
+// run container and declares it as the port named "driver", which the ready
+// container uses to find the worker. With shared placement, the port is
+// recorded in an annotation of the pod and the environment variable is
+// populated from it through the downward API.
+func (pb *PodBuilder) exposeDriverPort(pod *corev1.Pod, container *corev1.Container, port int32) {
+	env := corev1.EnvVar{
+		Name:  config.DriverPortEnv,
+		Value: fmt.Sprint(port),
+	}
+	if pb.sharedPlacement() {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[config.DriverPortAnnotation] = fmt.Sprint(port)
+		env = corev1.EnvVar{
+			Name: config.DriverPortEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", config.DriverPortAnnotation),
+				},
+			},
+		}
+	}
+	container.Env = append(container.Env, env)
+
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          "driver",
+		Protocol:      corev1.ProtocolTCP,
+		ContainerPort: port,
+	})
+}
+
+// podAffinity returns the affinity of the pods of the test. With exclusive
+// placement, a pod is not placed on a node that runs a pod of any load test.
+// Pods of tests with shared placement have no affinity, but the scheduler also
+// honors the anti-affinity of the pods already on a node, so they still avoid
+// nodes that run pods of tests with exclusive placement.
+func (pb *PodBuilder) podAffinity() *corev1.Affinity {
+	if pb.sharedPlacement() {
+		return nil
+	}
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      config.RoleLabel,
+								Operator: metav1.LabelSelectorOpExists,
+							},
+						},
+					},
+					TopologyKey: "kubernetes.io/hostname",
+				},
+			},
+		},
+	}
+}
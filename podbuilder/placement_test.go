@@ -0,0 +1,132 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("Placement", func() {
+	var defaults *config.Defaults
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		defaults = newDefaults()
+		test = newLoadTest()
+	})
+
+	driverPort := func(pod *corev1.Pod) int32 {
+		for _, port := range pod.Spec.Containers[0].Ports {
+			if port.Name == "driver" {
+				return port.ContainerPort
+			}
+		}
+		return 0
+	}
+
+	Context("with exclusive placement", func() {
+		It("keeps pods on nodes of their own", func() {
+			pods, err := New(defaults, test).RenderAll()
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, pod := range pods {
+				Expect(pod.Spec.Affinity).ToNot(BeNil())
+				Expect(pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			}
+		})
+
+		It("uses the fixed driver port for every worker", func() {
+			server, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(driverPort(server)).To(BeEquivalentTo(config.DriverPort))
+			Expect(server.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  config.DriverPortEnv,
+				Value: fmt.Sprint(config.DriverPort),
+			}))
+			Expect(server.Annotations).ToNot(HaveKey(config.DriverPortAnnotation))
+
+			client, err := New(defaults, test).PodForClient(&test.Spec.Clients[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(driverPort(client)).To(BeEquivalentTo(config.DriverPort))
+		})
+	})
+
+	Context("with shared placement", func() {
+		BeforeEach(func() {
+			test.Spec.PlacementPolicy = grpcv1.SharedPlacement
+			replicas := int32(2)
+			test.Spec.Clients[0].Replicas = &replicas
+		})
+
+		It("does not keep pods on nodes of their own", func() {
+			pods, err := New(defaults, test).RenderAll()
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, pod := range pods {
+				Expect(pod.Spec.Affinity).To(BeNil())
+			}
+		})
+
+		It("allocates a distinct driver port to each worker", func() {
+			pods, err := New(defaults, test).RenderAll()
+			Expect(err).ToNot(HaveOccurred())
+
+			var ports []int32
+			for _, pod := range pods {
+				if pod.Labels[config.RoleLabel] == config.DriverRole {
+					continue
+				}
+				ports = append(ports, driverPort(pod))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.DriverPortAnnotation, fmt.Sprint(driverPort(pod))))
+			}
+			Expect(ports).To(Equal([]int32{config.DriverPort, config.DriverPort + 1, config.DriverPort + 2}))
+		})
+
+		It("publishes the driver port through the downward API", func() {
+			pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name: config.DriverPortEnv,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.annotations['e2etest.grpc.io/driver-port']",
+					},
+				},
+			}))
+		})
+
+		It("allocates the same port when a pod is rebuilt", func() {
+			clients := test.Spec.Clients
+			first, err := New(defaults, test).RenderAll()
+			Expect(err).ToNot(HaveOccurred())
+			second, err := New(defaults, test).RenderAll()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(test.Spec.Clients).To(Equal(clients))
+			for i := range first {
+				Expect(driverPort(second[i])).To(Equal(driverPort(first[i])))
+			}
+		})
+	})
+})
@@ -17,11 +17,13 @@ limitations under the License.
 package podbuilder
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
@@ -33,6 +35,10 @@ import (
 // a pod.
 var errNoPool = errors.New("pool is missing")
 
+// errNoNetemImage is the base error when a load test requests network
+// emulation, but the defaults do not provide an image to configure it.
+var errNoNetemImage = errors.New("netem image is missing")
+
 // addReadyInitContainer configures a ready init container. This container is
 // meant to wait for workers to become ready, writing the IP address and port of
 // these workers to a file. This file is then shared over a volume with the
@@ -74,12 +80,22 @@ func newReadyContainer(defs *config.Defaults, test *grpcv1.LoadTest) corev1.Cont
 	var args []string
 	args = append(args, test.GetName())
 
+	var env []corev1.EnvVar
+	if len(defs.MachineHourlyPrices) > 0 {
+		// A map of strings to numbers can always be encoded.
+		prices, _ := json.Marshal(defs.MachineHourlyPrices)
+		env = append(env, corev1.EnvVar{
+			Name:  config.MachineHourlyPricesEnv,
+			Value: string(prices),
+		})
+	}
+
 	return corev1.Container{
 		Name:    config.ReadyInitContainerName,
 		Image:   defs.ReadyImage,
 		Command: []string{"ready"},
 		Args:    args,
-		Env: []corev1.EnvVar{
+		Env: append([]corev1.EnvVar{
 			{
 				Name:  "READY_OUTPUT_FILE",
 				Value: config.ReadyOutputFile,
@@ -96,7 +112,7 @@ func newReadyContainer(defs *config.Defaults, test *grpcv1.LoadTest) corev1.Cont
 				Name:  "NODE_INFO_OUTPUT_FILE",
 				Value: config.ReadyNodeInfoOutputFile,
 			},
-		},
+		}, env...),
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				Name:      config.ReadyVolumeName,
@@ -106,16 +122,117 @@ func newReadyContainer(defs *config.Defaults, test *grpcv1.LoadTest) corev1.Cont
 	}
 }
 
-// PodBuilder constructs pods for a test's driver, server and client.
+// addNetemInitContainer configures a netem init container on a worker pod when
+// the test has a network profile. The container shapes the egress traffic of
+// the pod network namespace, which is shared by all containers in the pod.
+//
+// Only this container is granted the NET_ADMIN capability, which is required to
+// modify the queueing discipline of the network interface. Pods for tests
+// without a network profile are left unchanged.
+func addNetemInitContainer(defs *config.Defaults, test *grpcv1.LoadTest, podspec *corev1.PodSpec) error {
+	args := netemArgs(test.Spec.NetworkProfile)
+	if len(args) == 0 {
+		return nil
+	}
+
+	if defs.NetemImage == "" {
+		return errors.Wrapf(errNoNetemImage, "cannot emulate network profile for test %q", test.Name)
+	}
+
+	podspec.InitContainers = append(podspec.InitContainers, corev1.Container{
+		Name:  config.NetemInitContainerName,
+		Image: defs.NetemImage,
+		Args:  args,
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add: []corev1.Capability{"NET_ADMIN"},
+			},
+		},
+	})
+	return nil
+}
+
+// netemArgs converts a network profile into the arguments of a netem qdisc, as
+// accepted by the tc command. It returns nil when the profile is nil or does
+// not emulate any conditions.
+func netemArgs(profile *grpcv1.NetworkProfile) []string {
+	if profile == nil {
+		return nil
+	}
+
+	var args []string
+	if profile.LatencyMs > 0 {
+		args = append(args, "delay", fmt.Sprintf("%dms", profile.LatencyMs))
+		if profile.JitterMs > 0 {
+			args = append(args, fmt.Sprintf("%dms", profile.JitterMs))
+		}
+	}
+	if profile.LossPct != "" {
+		args = append(args, "loss", profile.LossPct+"%")
+	}
+	if profile.Rate != "" {
+		args = append(args, "rate", profile.Rate)
+	}
+	return args
+}
+
+// addPprofPort exposes the port where a worker serves pprof profiles on its run
+// container, and sets the $PPROF_PORT environment variable to its number. The
+// container is left unchanged if the port is zero.
+func addPprofPort(container *corev1.Container, port int32) {
+	if port == 0 {
+		return
+	}
+
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.PprofPortEnv,
+		Value: fmt.Sprint(port),
+	})
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          config.PprofPortName,
+		Protocol:      corev1.ProtocolTCP,
+		ContainerPort: port,
+	})
+}
+
+// applyResourceBudget sets the memory and ephemeral storage of a budget as
+// limits of the container, overriding any limits that the container already
+// specifies. When a request for the same resource exceeds the budget, the
+// request is lowered to the budget, so the pod remains valid.
+func applyResourceBudget(container *corev1.Container, budget *grpcv1.ResourceBudget) {
+	if budget == nil {
+		return
+	}
+
+	limit := func(name corev1.ResourceName, quantity *resource.Quantity) {
+		if quantity == nil {
+			return
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Limits[name] = quantity.DeepCopy()
+		if request, ok := container.Resources.Requests[name]; ok && request.Cmp(*quantity) > 0 {
+			container.Resources.Requests[name] = quantity.DeepCopy()
+		}
+	}
+	limit(corev1.ResourceMemory, budget.Memory)
+	limit(corev1.ResourceEphemeralStorage, budget.EphemeralStorage)
+}
+
+// PodBuilder constructs pods for a test's driver, servers, clients and
+// generators.
 type PodBuilder struct {
-	test     *grpcv1.LoadTest
-	defaults *config.Defaults
-	name     string
-	role     string
-	pool     string
-	clone    *grpcv1.Clone
-	build    *grpcv1.Build
-	run      []corev1.Container
+	test        *grpcv1.LoadTest
+	defaults    *config.Defaults
+	name        string
+	role        string
+	pool        string
+	clone       *grpcv1.Clone
+	build       *grpcv1.Build
+	run         []corev1.Container
+	hostAliases []corev1.HostAlias
+	dnsConfig   *corev1.PodDNSConfig
 }
 
 // New creates a PodBuilder instance. It accepts and uses defaults and a test to
@@ -135,6 +252,8 @@ func (pb *PodBuilder) PodForClient(client *grpcv1.Client) (*corev1.Pod, error) {
 	pb.clone = client.Clone
 	pb.build = client.Build
 	pb.run = client.Run
+	pb.hostAliases = client.HostAliases
+	pb.dnsConfig = client.DNSConfig
 
 	pod := pb.newPod()
 
@@ -148,41 +267,33 @@ func (pb *PodBuilder) PodForClient(client *grpcv1.Client) (*corev1.Pod, error) {
 	}
 	pod.Spec.NodeSelector = nodeSelector
 
-	runContainer := &pod.Spec.Containers[0]
+	driverPort := pb.workerDriverPort()
+	pb.exposeWorker(pod)
+	configureServiceMesh(pod, pb.test.Spec.ServiceMesh, true, driverPort)
 
-	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
-		Name:  config.DriverPortEnv,
-		Value: fmt.Sprint(config.DriverPort)})
+	if err := addNetemInitContainer(pb.defaults, pb.test, &pod.Spec); err != nil {
+		return nil, err
+	}
 
-	if xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers); xdsServer != nil {
-		if sidecar := kubehelpers.ContainerForName(config.SidecarContainerName, pod.Spec.Containers); sidecar == nil {
-			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: "grpc-xds-bootstrap"})
+	runContainer := &pod.Spec.Containers[0]
+	pb.exposeDriverPort(pod, runContainer, driverPort)
 
-			runContainer.VolumeMounts = append(runContainer.VolumeMounts, corev1.VolumeMount{
-				Name:      "grpc-xds-bootstrap",
-				MountPath: "/bootstrap",
-				ReadOnly:  true,
-			})
-			xdsServer.VolumeMounts = append(xdsServer.VolumeMounts, corev1.VolumeMount{
-				Name:      "grpc-xds-bootstrap",
-				MountPath: "/bootstrap",
-				ReadOnly:  false,
-			})
-		}
+	addXdsNodeID(pb.test, pod)
+	if err := addXdsBootstrap(client, pod, runContainer); err != nil {
+		return nil, errors.Wrapf(err, "could not set xDS bootstrap for client %q", pb.name)
 	}
+	addCSDSPort(pod, runContainer, client.CSDSPort)
 
-	runContainer.Ports = append(runContainer.Ports, corev1.ContainerPort{
-		Name:          "driver",
-		Protocol:      corev1.ProtocolTCP,
-		ContainerPort: config.DriverPort,
-	})
+	addPprofPort(runContainer, client.PprofPort)
+	applyResourceBudget(runContainer, client.Budget)
+	addWorkerCancellation(pb.defaults, pb.test, pod, runContainer)
+	if err := addWorkerRestarts(pb.test, runContainer); err != nil {
+		return nil, errors.Wrapf(err, "could not restart client %q between scenarios", pb.name)
+	}
+	addSupervisor(pb.defaults, &pod.Spec, runContainer)
 
-	if client.MetricsPort != 0 {
-		runContainer.Ports = append(runContainer.Ports, corev1.ContainerPort{
-			Name:          "metrics",
-			Protocol:      corev1.ProtocolTCP,
-			ContainerPort: client.MetricsPort,
-		})
+	if err := addMetricsPort(pod, runContainer, client.MetricsPort); err != nil {
+		return nil, errors.Wrapf(err, "could not expose metrics port for client %q", pb.name)
 	}
 
 	return pod, nil
@@ -196,6 +307,8 @@ func (pb *PodBuilder) PodForDriver(driver *grpcv1.Driver) (*corev1.Pod, error) {
 	pb.clone = driver.Clone
 	pb.build = driver.Build
 	pb.run = driver.Run
+	pb.hostAliases = driver.HostAliases
+	pb.dnsConfig = driver.DNSConfig
 
 	pod := pb.newPod()
 
@@ -208,9 +321,21 @@ func (pb *PodBuilder) PodForDriver(driver *grpcv1.Driver) (*corev1.Pod, error) {
 		return nil, errors.Wrapf(errNoPool, "could not determine pool for driver (no explicit value or default)")
 	}
 	pod.Spec.NodeSelector = nodeSelector
+	configureServiceMesh(pod, pb.test.Spec.ServiceMesh, false, config.DriverPort)
 
 	runContainer := &pod.Spec.Containers[0]
 	addReadyInitContainer(pb.defaults, pb.test, &pod.Spec, runContainer)
+	addDriverCancellation(pb.defaults, pb.test, pod, runContainer)
+	applyResourceBudget(runContainer, driver.Budget)
+	if err := addDriverRestarts(pb.test, runContainer); err != nil {
+		return nil, errors.Wrap(err, "could not restart workers between scenarios")
+	}
+
+	if pb.test.Spec.Interop != nil {
+		if err := addInteropRunner(pb.defaults, pb.test, &pod.Spec, runContainer); err != nil {
+			return nil, err
+		}
+	}
 
 	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
 		Name: "scenarios",
@@ -247,6 +372,12 @@ func (pb *PodBuilder) PodForDriver(driver *grpcv1.Driver) (*corev1.Pod, error) {
 				Value: *bigQueryTable,
 			})
 		}
+		if resultsURI := kubehelpers.ResultsURIForLoadTest(pb.test); resultsURI != "" {
+			runContainer.Env = append(runContainer.Env, corev1.EnvVar{
+				Name:  config.ResultsURIEnv,
+				Value: resultsURI,
+			})
+		}
 	}
 
 	enablePrometheus, ok := pb.test.Annotations["enablePrometheus"]
@@ -268,6 +399,8 @@ func (pb *PodBuilder) PodForServer(server *grpcv1.Server) (*corev1.Pod, error) {
 	pb.clone = server.Clone
 	pb.build = server.Build
 	pb.run = server.Run
+	pb.hostAliases = server.HostAliases
+	pb.dnsConfig = server.DNSConfig
 
 	pod := pb.newPod()
 
@@ -281,29 +414,79 @@ func (pb *PodBuilder) PodForServer(server *grpcv1.Server) (*corev1.Pod, error) {
 	}
 	pod.Spec.NodeSelector = nodeSelector
 
+	driverPort := pb.workerDriverPort()
+	pb.exposeWorker(pod)
+	configureServiceMesh(pod, pb.test.Spec.ServiceMesh, true, driverPort)
+
+	if err := addNetemInitContainer(pb.defaults, pb.test, &pod.Spec); err != nil {
+		return nil, err
+	}
+
 	runContainer := &pod.Spec.Containers[0]
+	pb.exposeDriverPort(pod, runContainer, driverPort)
 
-	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{
-		Name:  config.DriverPortEnv,
-		Value: fmt.Sprintf("%d", config.DriverPort)})
+	if pb.test.Spec.Interop != nil {
+		addInteropServerPort(runContainer)
+	}
+	addPprofPort(runContainer, server.PprofPort)
+	applyResourceBudget(runContainer, server.Budget)
+	addWorkerCancellation(pb.defaults, pb.test, pod, runContainer)
+	if err := addWorkerRestarts(pb.test, runContainer); err != nil {
+		return nil, errors.Wrapf(err, "could not restart server %q between scenarios", pb.name)
+	}
+	addSupervisor(pb.defaults, &pod.Spec, runContainer)
 
-	runContainer.Ports = append(runContainer.Ports, corev1.ContainerPort{
-		Name:          "driver",
-		Protocol:      corev1.ProtocolTCP,
-		ContainerPort: config.DriverPort,
-	})
+	if err := addMetricsPort(pod, runContainer, server.MetricsPort); err != nil {
+		return nil, errors.Wrapf(err, "could not expose metrics port for server %q", pb.name)
+	}
 
-	if server.MetricsPort != 0 {
-		runContainer.Ports = append(runContainer.Ports, corev1.ContainerPort{
-			Name:          "metrics",
-			Protocol:      corev1.ProtocolTCP,
-			ContainerPort: server.MetricsPort,
-		})
+	return pod, nil
+}
+
+// PodForGenerator accepts a pointer to a generator and returns a pod for it.
+// Generators do not take instructions from the driver, so the pod is not
+// exposed as a worker. Generators are scheduled in the default pool for
+// clients when they do not specify a pool.
+func (pb *PodBuilder) PodForGenerator(generator *grpcv1.Generator) (*corev1.Pod, error) {
+	pb.name = safeStrUnwrap(generator.Name)
+	pb.role = config.GeneratorRole
+	pb.pool = safeStrUnwrap(generator.Pool)
+	pb.clone = nil
+	pb.build = nil
+	pb.run = generator.Run
+	pb.hostAliases = generator.HostAliases
+	pb.dnsConfig = generator.DNSConfig
+
+	pod := pb.newPod()
+
+	nodeSelector := make(map[string]string)
+	if generator.Pool != nil {
+		nodeSelector["pool"] = *generator.Pool
+	} else if pb.defaults.DefaultPoolLabels != nil && pb.defaults.DefaultPoolLabels.Client != "" {
+		nodeSelector[pb.defaults.DefaultPoolLabels.Client] = "true"
+	} else {
+		return nil, errors.Wrapf(errNoPool, "could not determine pool for generator %q (no explicit value or default)", pb.name)
+	}
+	pod.Spec.NodeSelector = nodeSelector
+
+	if err := addNetemInitContainer(pb.defaults, pb.test, &pod.Spec); err != nil {
+		return nil, err
 	}
 
+	applyResourceBudget(&pod.Spec.Containers[0], generator.Budget)
+
 	return pod, nil
 }
 
+// exposeWorker labels a worker pod, so it is selected by the headless Service
+// of the test. It also sets the hostname and subdomain of the pod, which give
+// the pod a stable DNS name.
+func (pb *PodBuilder) exposeWorker(pod *corev1.Pod) {
+	pod.Labels[config.WorkerLabel] = "true"
+	pod.Spec.Hostname = kubehelpers.ComponentHostname(pb.role, pb.name)
+	pod.Spec.Subdomain = pb.test.Name
+}
+
 // newPod creates a base pod for any client, driver or server. It is designed to
 // be decorated by more specific methods for each of these.
 func (pb *PodBuilder) newPod() *corev1.Pod {
@@ -386,7 +569,7 @@ func (pb *PodBuilder) newPod() *corev1.Pod {
 		r.Env = append(r.Env, []corev1.EnvVar{
 			{
 				Name:  config.KillAfterEnv,
-				Value: fmt.Sprintf("%f", pb.defaults.KillAfter),
+				Value: fmt.Sprintf("%f", killAfter(pb.defaults, pb.test)),
 			},
 			{
 				Name:  config.PodTimeoutEnv,
@@ -396,36 +579,33 @@ func (pb *PodBuilder) newPod() *corev1.Pod {
 		runContainers = append(runContainers, r)
 	}
 
+	name := PodName(pb.test, pb.role, pb.name)
+	labels := map[string]string{
+		config.LoadTestLabel:      pb.test.Name,
+		config.RoleLabel:          pb.role,
+		config.ComponentNameLabel: pb.name,
+	}
+	if pb.test.Spec.SoakHours != nil {
+		labels[config.SoakIterationLabel] = fmt.Sprint(pb.test.Status.SoakIteration)
+	}
+	if pb.test.Status.Run > 0 {
+		labels[config.RunLabel] = fmt.Sprint(pb.test.Status.Run)
+	}
+
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s-%s", pb.test.Name, pb.role, pb.name),
+			Name:      name,
 			Namespace: pb.test.Namespace,
-			Labels: map[string]string{
-				config.RoleLabel:          pb.role,
-				config.ComponentNameLabel: pb.name,
-			},
+			Labels:    labels,
 		},
 		Spec: corev1.PodSpec{
-			InitContainers: initContainers,
-			Containers:     runContainers,
-			RestartPolicy:  corev1.RestartPolicyNever,
-			Affinity: &corev1.Affinity{
-				PodAntiAffinity: &corev1.PodAntiAffinity{
-					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-						{
-							LabelSelector: &metav1.LabelSelector{
-								MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      config.RoleLabel,
-										Operator: metav1.LabelSelectorOpExists,
-									},
-								},
-							},
-							TopologyKey: "kubernetes.io/hostname",
-						},
-					},
-				},
-			},
+			InitContainers:    initContainers,
+			Containers:        runContainers,
+			RestartPolicy:     corev1.RestartPolicyNever,
+			PriorityClassName: pb.defaults.PriorityClassName,
+			HostAliases:       pb.hostAliases,
+			DNSConfig:         pb.dnsConfig,
+			Affinity:          pb.podAffinity(),
 			Volumes: []corev1.Volume{
 				{
 					Name: config.WorkspaceVolumeName,
@@ -438,6 +618,27 @@ func (pb *PodBuilder) newPod() *corev1.Pod {
 	}
 }
 
+// PodName accepts a test, the role of a component and the name of the
+// component, and returns the name of the pod for the component. The name is
+// deterministic, so it can be used to find the pod of a component without
+// listing pods.
+//
+// For soak tests, the name ends with the current soak iteration. Each soak
+// iteration creates new pods, so the pods of previous iterations (and their
+// logs) can remain until they are rotated. Likewise, the pods of a test that
+// was rerun are suffixed with the index of the run, so the pods of previous
+// runs remain until the test is deleted.
+func PodName(test *grpcv1.LoadTest, role string, componentName string) string {
+	name := fmt.Sprintf("%s-%s-%s", test.Name, role, componentName)
+	if test.Status.Run > 0 {
+		name = fmt.Sprintf("%s-run%d", name, test.Status.Run)
+	}
+	if test.Spec.SoakHours != nil {
+		name = fmt.Sprintf("%s-%d", name, test.Status.SoakIteration)
+	}
+	return name
+}
+
 // safeStrUnwrap accepts a string pointer, returning the dereferenced string or
 // an empty string if the pointer is nil.
 func safeStrUnwrap(strPtr *string) string {
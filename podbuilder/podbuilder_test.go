@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
@@ -402,6 +403,115 @@ var _ = Describe("PodBuilder", func() {
 				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
 				Expect(getNames(runContainer.Ports)).To(ContainElement("metrics"))
 				Expect(getValue("metrics", "ContainerPort", runContainer.Ports)).To(BeEquivalentTo(client.MetricsPort))
+				Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{Name: config.MetricsPortEnv, Value: "4242"}))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.MetricsPortAnnotation, "4242"))
+			})
+
+			It("reassigns the metrics port if it conflicts with another port", func() {
+				client.Run = []corev1.Container{{}, {}}
+				client.Run[0].Name = config.RunContainerName
+				client.Run[1].Name = "sidecar"
+				client.Run[1].Ports = []corev1.ContainerPort{
+					{Name: "admin", ContainerPort: 4242},
+					{Name: "stats", ContainerPort: 4243},
+				}
+				client.MetricsPort = 4242
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(getValue("metrics", "ContainerPort", runContainer.Ports)).To(BeEquivalentTo(4244))
+				Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{Name: config.MetricsPortEnv, Value: "4244"}))
+				Expect(pod.Annotations).To(HaveKeyWithValue(config.MetricsPortAnnotation, "4244"))
+			})
+
+			It("does not reassign the metrics port if the other port uses another protocol", func() {
+				client.Run = []corev1.Container{{}, {}}
+				client.Run[0].Name = config.RunContainerName
+				client.Run[1].Name = "sidecar"
+				client.Run[1].Ports = []corev1.ContainerPort{
+					{Name: "dns", ContainerPort: 4242, Protocol: corev1.ProtocolUDP},
+				}
+				client.MetricsPort = 4242
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(getValue("metrics", "ContainerPort", runContainer.Ports)).To(BeEquivalentTo(4242))
+			})
+
+			It("returns an error if other containers declare the same port", func() {
+				client.Run = []corev1.Container{{}, {}}
+				client.Run[0].Name = config.RunContainerName
+				client.Run[1].Name = "sidecar"
+				client.Run[1].Ports = []corev1.ContainerPort{
+					{Name: "admin", ContainerPort: config.DriverPort},
+				}
+
+				_, err := builder.PodForClient(client)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("does not expose the pprof port if not set", func() {
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(getNames(runContainer.Ports)).ToNot(ContainElement(config.PprofPortName))
+				Expect(getNames(runContainer.Env)).ToNot(ContainElement(config.PprofPortEnv))
+			})
+
+			It("exposes the pprof port and sets its env variable if set", func() {
+				client.PprofPort = 6060
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(getValue(config.PprofPortName, "ContainerPort", runContainer.Ports)).To(BeEquivalentTo(client.PprofPort))
+				Expect(getValue(config.PprofPortEnv, "Value", runContainer.Env)).To(Equal("6060"))
+			})
+
+			It("does not set limits if no budget is set", func() {
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(runContainer.Resources.Limits).To(BeEmpty())
+			})
+
+			It("sets limits from the budget", func() {
+				memory := resource.MustParse("2Gi")
+				ephemeralStorage := resource.MustParse("10Gi")
+				client.Budget = &grpcv1.ResourceBudget{
+					Memory:           &memory,
+					EphemeralStorage: &ephemeralStorage,
+				}
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(runContainer.Resources.Limits.Memory().Equal(memory)).To(BeTrue())
+				Expect(runContainer.Resources.Limits.StorageEphemeral().Equal(ephemeralStorage)).To(BeTrue())
+			})
+
+			It("lowers requests that exceed the budget", func() {
+				memory := resource.MustParse("1Gi")
+				client.Budget = &grpcv1.ResourceBudget{Memory: &memory}
+				client.Run[0].Resources.Requests = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+					corev1.ResourceCPU:    resource.MustParse("2"),
+				}
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(runContainer.Resources.Requests.Memory().Equal(memory)).To(BeTrue())
+				Expect(runContainer.Resources.Requests.Cpu().String()).To(Equal("2"))
 			})
 
 			It("attached the env to other run containers", func() {
@@ -445,6 +555,134 @@ var _ = Describe("PodBuilder", func() {
 			Expect(pod.Spec.Affinity).ToNot(BeNil())
 			Expect(pod.Spec.Affinity.PodAntiAffinity).ToNot((BeNil()))
 		})
+
+		It("sets a label with the name of the test", func() {
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).To(HaveKeyWithValue(config.LoadTestLabel, test.Name))
+		})
+
+		It("sets the priority class name from defaults", func() {
+			builder.defaults.PriorityClassName = "benchmark-priority"
+
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.PriorityClassName).To(Equal("benchmark-priority"))
+		})
+
+		It("does not set a priority class name without a default", func() {
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.PriorityClassName).To(BeEmpty())
+		})
+
+		It("does not set a soak iteration label for tests that are not soak tests", func() {
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).ToNot(HaveKey(config.SoakIterationLabel))
+		})
+
+		It("names and labels pods with the soak iteration for soak tests", func() {
+			test.Spec.SoakHours = optional.Int32Ptr(12)
+			test.Status.SoakIteration = 3
+
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Name).To(HaveSuffix("-3"))
+			Expect(pod.Labels).To(HaveKeyWithValue(config.SoakIterationLabel, "3"))
+		})
+
+		It("names and labels pods with the run of rerun tests", func() {
+			test.Status.Run = 1
+
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Name).To(HaveSuffix("-run1"))
+			Expect(pod.Labels).To(HaveKeyWithValue(config.RunLabel, "1"))
+		})
+
+		It("does not set a run label for tests that were not rerun", func() {
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).ToNot(HaveKey(config.RunLabel))
+		})
+
+		It("sets a hostname and the subdomain of the test service", func() {
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.Hostname).To(Equal(config.ClientRole + "-" + *client.Name))
+			Expect(pod.Spec.Subdomain).To(Equal(test.Name))
+			Expect(pod.Labels).To(HaveKeyWithValue(config.WorkerLabel, "true"))
+		})
+
+		It("sets host aliases and DNS config from the client", func() {
+			client.HostAliases = []corev1.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"server.example.com"}},
+			}
+			client.DNSConfig = &corev1.PodDNSConfig{
+				Searches: []string{"example.com"},
+			}
+
+			pod, err := builder.PodForClient(client)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.HostAliases).To(Equal(client.HostAliases))
+			Expect(pod.Spec.DNSConfig).To(Equal(client.DNSConfig))
+		})
+
+		Context("netem init container", func() {
+			It("does not contain an init container named netem without a network profile", func() {
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getNames(pod.Spec.InitContainers)).ToNot(ContainElement(config.NetemInitContainerName))
+			})
+
+			It("configures netem with the network profile", func() {
+				testSpec.NetworkProfile = &grpcv1.NetworkProfile{
+					LatencyMs: 50,
+					JitterMs:  5,
+					LossPct:   "0.1",
+					Rate:      "100mbit",
+				}
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				netem := kubehelpers.ContainerForName(config.NetemInitContainerName, pod.Spec.InitContainers)
+				Expect(netem).ToNot(BeNil())
+				Expect(netem.Image).To(Equal(defaults.NetemImage))
+				Expect(netem.Args).To(Equal([]string{"delay", "50ms", "5ms", "loss", "0.1%", "rate", "100mbit"}))
+			})
+
+			It("adds the NET_ADMIN capability to the netem container only", func() {
+				testSpec.NetworkProfile = &grpcv1.NetworkProfile{LatencyMs: 50}
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+
+				netem := kubehelpers.ContainerForName(config.NetemInitContainerName, pod.Spec.InitContainers)
+				Expect(netem).ToNot(BeNil())
+				Expect(netem.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_ADMIN")))
+				for _, container := range pod.Spec.Containers {
+					Expect(container.SecurityContext).To(BeNil())
+				}
+			})
+
+			It("does not contain an init container named netem for an empty network profile", func() {
+				testSpec.NetworkProfile = &grpcv1.NetworkProfile{}
+
+				pod, err := builder.PodForClient(client)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getNames(pod.Spec.InitContainers)).ToNot(ContainElement(config.NetemInitContainerName))
+			})
+
+			It("errors when a network profile is set and no netem image is set", func() {
+				testSpec.NetworkProfile = &grpcv1.NetworkProfile{LatencyMs: 50}
+				builder.defaults.NetemImage = ""
+
+				_, err := builder.PodForClient(client)
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("PodForServer", func() {
@@ -509,6 +747,36 @@ var _ = Describe("PodBuilder", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("sets a hostname and the subdomain of the test service", func() {
+			pod, err := builder.PodForServer(server)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.Hostname).To(Equal(config.ServerRole + "-" + *server.Name))
+			Expect(pod.Spec.Subdomain).To(Equal(test.Name))
+			Expect(pod.Labels).To(HaveKeyWithValue(config.WorkerLabel, "true"))
+		})
+
+		It("sets host aliases and DNS config from the server", func() {
+			server.HostAliases = []corev1.HostAlias{
+				{IP: "10.0.0.2", Hostnames: []string{"backend.example.com"}},
+			}
+			server.DNSConfig = &corev1.PodDNSConfig{
+				Nameservers: []string{"10.0.0.53"},
+			}
+
+			pod, err := builder.PodForServer(server)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.HostAliases).To(Equal(server.HostAliases))
+			Expect(pod.Spec.DNSConfig).To(Equal(server.DNSConfig))
+		})
+
+		It("contains an init container named netem when a network profile is set", func() {
+			testSpec.NetworkProfile = &grpcv1.NetworkProfile{LatencyMs: 50}
+
+			pod, err := builder.PodForServer(server)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getNames(pod.Spec.InitContainers)).To(ContainElement(config.NetemInitContainerName))
+		})
+
 		Context("clone init container", func() {
 			It("contains an init container named clone when clone instructions are present", func() {
 				server.Clone = new(grpcv1.Clone)
@@ -702,6 +970,14 @@ var _ = Describe("PodBuilder", func() {
 			driver = testSpec.Driver
 		})
 
+		It("does not contain an init container named netem when a network profile is set", func() {
+			testSpec.NetworkProfile = &grpcv1.NetworkProfile{LatencyMs: 50}
+
+			pod, err := builder.PodForDriver(driver)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getNames(pod.Spec.InitContainers)).ToNot(ContainElement(config.NetemInitContainerName))
+		})
+
 		It("sets the namespace to match the test", func() {
 			pod, err := builder.PodForDriver(driver)
 			Expect(err).ToNot(HaveOccurred())
@@ -725,6 +1001,36 @@ var _ = Describe("PodBuilder", func() {
 			Expect(componentName).To(Equal(*driver.Name))
 		})
 
+		It("does not label the driver as a worker", func() {
+			pod, err := builder.PodForDriver(driver)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).ToNot(HaveKey(config.WorkerLabel))
+			Expect(pod.Spec.Subdomain).To(BeEmpty())
+		})
+
+		It("sets an environment variable with the results URI when a GCS prefix is set", func() {
+			testSpec.Results.GCSPrefix = optional.StringPtr("gs://example-bucket")
+
+			pod, err := builder.PodForDriver(driver)
+			Expect(err).ToNot(HaveOccurred())
+
+			runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+			Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{
+				Name:  config.ResultsURIEnv,
+				Value: kubehelpers.ResultsURIForLoadTest(test),
+			}))
+		})
+
+		It("does not set an environment variable with the results URI when no GCS prefix is set", func() {
+			testSpec.Results.GCSPrefix = nil
+
+			pod, err := builder.PodForDriver(driver)
+			Expect(err).ToNot(HaveOccurred())
+
+			runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+			Expect(getNames(runContainer.Env)).ToNot(ContainElement(config.ResultsURIEnv))
+		})
+
 		It("sets node selector to match pool", func() {
 			driver.Pool = optional.StringPtr("testing-pool")
 
@@ -915,6 +1221,18 @@ var _ = Describe("PodBuilder", func() {
 					MountPath: config.WorkspaceMountPath,
 				}))
 			})
+
+			It("sets limits from the budget", func() {
+				memory := resource.MustParse("512Mi")
+				driver.Budget = &grpcv1.ResourceBudget{Memory: &memory}
+
+				pod, err := builder.PodForDriver(driver)
+				Expect(err).ToNot(HaveOccurred())
+
+				runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+				Expect(runContainer.Resources.Limits.Memory().Equal(memory)).To(BeTrue())
+				Expect(runContainer.Resources.Limits).ToNot(HaveKey(corev1.ResourceEphemeralStorage))
+			})
 		})
 
 		It("sets a pod anti-affinity", func() {
@@ -927,4 +1245,75 @@ var _ = Describe("PodBuilder", func() {
 			Expect(pod.Spec.Affinity.PodAntiAffinity).ToNot((BeNil()))
 		})
 	})
+
+	Describe("PodForGenerator", func() {
+		var generator *grpcv1.Generator
+
+		BeforeEach(func() {
+			generator = &grpcv1.Generator{
+				Name:    optional.StringPtr("ghz"),
+				Adapter: grpcv1.GhzAdapter,
+				Run: []corev1.Container{{
+					Name:  "ghz",
+					Image: "ghcr.io/bojand/ghz:latest",
+					Args:  []string{"--insecure", "--format=json", "server.example:443"},
+				}},
+			}
+		})
+
+		It("sets a label indicating it is a generator", func() {
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels[config.RoleLabel]).To(Equal(config.GeneratorRole))
+			Expect(pod.Labels[config.ComponentNameLabel]).To(Equal(*generator.Name))
+			Expect(pod.Name).To(Equal(PodName(test, config.GeneratorRole, *generator.Name)))
+		})
+
+		It("runs the containers of the generator", func() {
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getNames(pod.Spec.Containers)).To(Equal([]string{"ghz"}))
+			Expect(pod.Spec.Containers[0].Args).To(Equal(generator.Run[0].Args))
+		})
+
+		It("does not expose the generator as a worker", func() {
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Labels).ToNot(HaveKey(config.WorkerLabel))
+			Expect(pod.Spec.Subdomain).To(BeEmpty())
+			Expect(pod.Spec.Containers[0].Ports).To(BeEmpty())
+		})
+
+		It("sets node selector to match pool", func() {
+			generator.Pool = optional.StringPtr("testing-pool")
+
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.NodeSelector["pool"]).To(Equal(*generator.Pool))
+		})
+
+		It("sets node selector to the default client pool when applicable", func() {
+			defaultPool := "default-client-pool"
+			builder.defaults.DefaultPoolLabels.Client = defaultPool
+
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pod.Spec.NodeSelector[defaultPool]).To(Equal("true"))
+		})
+
+		It("returns an error when no pool can be determined", func() {
+			builder.defaults.DefaultPoolLabels = nil
+
+			_, err := builder.PodForGenerator(generator)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("contains an init container named netem when a network profile is set", func() {
+			testSpec.NetworkProfile = &grpcv1.NetworkProfile{LatencyMs: 50}
+
+			pod, err := builder.PodForGenerator(generator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getNames(pod.Spec.InitContainers)).To(ContainElement(config.NetemInitContainerName))
+		})
+	})
 })
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// errPortConflict is the base error when two containers in a pod declare the
+// same port. Containers in a pod share a network namespace, so only one of
+// them can listen on the port.
+var errPortConflict = errors.New("port conflict")
+
+// errNoFreePort is the base error when the metrics port of a worker conflicts
+// with another port, and no other port is available.
+var errNoFreePort = errors.New("no free port")
+
+// maxPort is the largest valid port number.
+const maxPort = 65535
+
+// portKey identifies a port in the network namespace of a pod.
+type portKey struct {
+	port     int32
+	protocol corev1.Protocol
+}
+
+// newPortKey returns the key for a container port. The protocol defaults to
+// TCP when it is unset.
+func newPortKey(port corev1.ContainerPort) portKey {
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	return portKey{port: port.ContainerPort, protocol: protocol}
+}
+
+// declaredPorts returns the ports declared by the containers of a pod, mapped
+// to the name of the container that declares them. An error wrapping
+// errPortConflict is returned if two containers, or the same container
+// twice, declare the same port.
+func declaredPorts(pod *corev1.Pod) (map[portKey]string, error) {
+	ports := make(map[portKey]string)
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			key := newPortKey(port)
+			if other, ok := ports[key]; ok {
+				return nil, errors.Wrapf(errPortConflict, "port %d/%s is declared by containers %q and %q", key.port, key.protocol, other, container.Name)
+			}
+			ports[key] = container.Name
+		}
+	}
+	return ports, nil
+}
+
+// addMetricsPort exposes the port where a worker serves metrics on its run
+// container, and sets the $METRICS_PORT environment variable to its number.
+// If the requested port is already declared by a container in the pod, the
+// next free port is used instead. The number of the port is recorded in the
+// MetricsPortAnnotation on the pod, so that scrapers can find it. The pod is
+// left unchanged if the port is zero.
+//
+// An error is returned if the ports declared by the containers of the pod
+// conflict with each other, or if no free port could be found.
+func addMetricsPort(pod *corev1.Pod, container *corev1.Container, port int32) error {
+	ports, err := declaredPorts(pod)
+	if err != nil {
+		return err
+	}
+	if port == 0 {
+		return nil
+	}
+
+	assigned := port
+	for {
+		if _, ok := ports[portKey{port: assigned, protocol: corev1.ProtocolTCP}]; !ok {
+			break
+		}
+		if assigned == maxPort {
+			return errors.Wrapf(errNoFreePort, "metrics port %d conflicts with another port", port)
+		}
+		assigned++
+	}
+
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.MetricsPortEnv,
+		Value: fmt.Sprint(assigned),
+	})
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          config.MetricsPortName,
+		Protocol:      corev1.ProtocolTCP,
+		ContainerPort: assigned,
+	})
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[config.MetricsPortAnnotation] = fmt.Sprint(assigned)
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// RenderAll returns the pods for all components of the test. The pods of the
+// servers are returned first, followed by the pods of the clients, the pods of
+// the generators and the pod of the driver, each in the order they appear in
+// the spec. Clients with
+// replicas are expanded into one pod per replica. The defaults must already be
+// set on the test. An error is returned if a pod cannot be constructed for any
+// component.
+//
+// RenderAll allows tools to construct every pod of a test without repeating
+// the loop over its components.
+func (pb *PodBuilder) RenderAll() ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+
+	for i := range pb.test.Spec.Servers {
+		pod, err := pb.PodForServer(&pb.test.Spec.Servers[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to construct a pod for server at index %d", i)
+		}
+		pods = append(pods, pod)
+	}
+
+	clients := kubehelpers.ExpandClients(pb.test.Spec.Clients)
+	for i := range clients {
+		pod, err := pb.PodForClient(&clients[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to construct a pod for client at index %d", i)
+		}
+		pods = append(pods, pod)
+	}
+
+	for i := range pb.test.Spec.Generators {
+		pod, err := pb.PodForGenerator(&pb.test.Spec.Generators[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to construct a pod for generator at index %d", i)
+		}
+		pods = append(pods, pod)
+	}
+
+	if pb.test.Spec.Driver != nil {
+		pod, err := pb.PodForDriver(pb.test.Spec.Driver)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to construct a pod for driver")
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+// RenderPods accepts a list of pods and returns a map of file names to the
+// pods formatted as YAML. Each file is named after its pod, so the result can
+// be stored as the data of a ConfigMap. The type of each pod is included, so
+// the YAML can be applied to a cluster.
+func RenderPods(pods []*corev1.Pod) (map[string]string, error) {
+	rendered := make(map[string]string)
+
+	for _, pod := range pods {
+		pod = pod.DeepCopy()
+		pod.APIVersion = "v1"
+		pod.Kind = "Pod"
+
+		podYAML, err := yaml.Marshal(pod)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render pod %q", pod.Name)
+		}
+		rendered[pod.Name+".yaml"] = string(podYAML)
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("RenderAll", func() {
+	var test *grpcv1.LoadTest
+	var builder *PodBuilder
+
+	BeforeEach(func() {
+		test = newLoadTest()
+		builder = New(newDefaults(), test)
+	})
+
+	It("returns a pod for each server, client and the driver", func() {
+		pods, err := builder.RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pods).To(HaveLen(len(test.Spec.Servers) + len(test.Spec.Clients) + 1))
+
+		var roles []string
+		for _, pod := range pods {
+			roles = append(roles, pod.Labels[config.RoleLabel])
+		}
+		Expect(roles).To(Equal([]string{config.ServerRole, config.ClientRole, config.DriverRole}))
+	})
+
+	It("returns the pods of the generators before the pod of the driver", func() {
+		test.Spec.Generators = []grpcv1.Generator{{
+			Name: optional.StringPtr("ghz"),
+			Run:  []corev1.Container{{Name: "ghz", Image: "ghz"}},
+		}}
+
+		pods, err := builder.RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		var roles []string
+		for _, pod := range pods {
+			roles = append(roles, pod.Labels[config.RoleLabel])
+		}
+		Expect(roles).To(Equal([]string{config.ServerRole, config.ClientRole, config.GeneratorRole, config.DriverRole}))
+	})
+
+	It("names each pod with PodName", func() {
+		pods, err := builder.RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, pod := range pods {
+			Expect(pod.Name).To(Equal(PodName(test, pod.Labels[config.RoleLabel], pod.Labels[config.ComponentNameLabel])))
+		}
+	})
+
+	It("returns a pod for each replica of a client", func() {
+		replicas := int32(3)
+		test.Spec.Clients[0].Replicas = &replicas
+		clientName := *test.Spec.Clients[0].Name
+
+		pods, err := builder.RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		var names []string
+		for _, pod := range pods {
+			if pod.Labels[config.RoleLabel] == config.ClientRole {
+				names = append(names, pod.Labels[config.ComponentNameLabel])
+			}
+		}
+		Expect(names).To(Equal([]string{clientName + "-0", clientName + "-1", clientName + "-2"}))
+	})
+
+	It("returns an error when a pod cannot be constructed", func() {
+		test.Spec.Driver.Pool = nil
+		builder.defaults.DefaultPoolLabels = nil
+
+		_, err := builder.RenderAll()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PodName", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = newLoadTest()
+	})
+
+	It("combines the test name, role and component name", func() {
+		Expect(PodName(test, config.ServerRole, "0")).To(Equal(test.Name + "-server-0"))
+	})
+
+	It("appends the soak iteration for soak tests", func() {
+		test.Spec.SoakHours = optional.Int32Ptr(2)
+		test.Status.SoakIteration = 3
+		Expect(PodName(test, config.ClientRole, "0")).To(Equal(test.Name + "-client-0-3"))
+	})
+})
+
+var _ = Describe("RenderPods", func() {
+	It("renders each pod as YAML named after the pod", func() {
+		test := newLoadTest()
+		pods, err := New(newDefaults(), test).RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		rendered, err := RenderPods(pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rendered).To(HaveLen(len(pods)))
+
+		for _, pod := range pods {
+			podYAML, ok := rendered[pod.Name+".yaml"]
+			Expect(ok).To(BeTrue())
+
+			parsed := new(corev1.Pod)
+			Expect(yaml.Unmarshal([]byte(podYAML), parsed)).To(Succeed())
+			Expect(parsed.Kind).To(Equal("Pod"))
+			Expect(parsed.APIVersion).To(Equal("v1"))
+			Expect(parsed.Name).To(Equal(pod.Name))
+			Expect(parsed.Spec.Containers).To(HaveLen(len(pod.Spec.Containers)))
+		}
+	})
+
+	It("does not modify the pods", func() {
+		test := newLoadTest()
+		pods, err := New(newDefaults(), test).RenderAll()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = RenderPods(pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pods[0].Kind).To(BeEmpty())
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// errNoCommand is the base error when the run container of a worker must be
+// restarted between scenarios, but it does not set a command to restart.
+var errNoCommand = errors.New("command is missing")
+
+// workerSupervisorScript starts the command given in its arguments once for
+// each scenario of a test. The driver asks the worker to quit after each
+// scenario, so the next scenario starts with a fresh worker process. The
+// script stops as soon as the command fails, and forwards SIGTERM to the
+// command, so a cancelled test stops the worker as usual.
+const workerSupervisorScript = `runs="${` + config.WorkerRunsEnv + `}"
+trap 'kill -TERM "$pid"; wait "$pid"; exit 143' TERM
+while [ "$runs" -gt 0 ]; do
+  "$@" &
+  pid=$!
+  wait "$pid" || exit
+  runs=$((runs - 1))
+done
+`
+
+// restartedRuns returns the number of times the workers of a test start their
+// command, or zero if the workers of the test are not restarted between
+// scenarios.
+func restartedRuns(test *grpcv1.LoadTest) (int, error) {
+	if !test.Spec.RestartWorkers {
+		return 0, nil
+	}
+	count, err := kubehelpers.ScenarioCount(test)
+	if err != nil {
+		return 0, err
+	}
+	if count <= 1 {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// addWorkerRestarts wraps the command of the run container of a client or
+// server in a supervisor script, which starts the command again each time it
+// exits successfully, until it has run once for each scenario. The container
+// is left unchanged unless the test restarts its workers and has several
+// scenarios. An error wrapping errNoCommand is returned if the container does
+// not set a command, since the entrypoint of its image cannot be wrapped.
+func addWorkerRestarts(test *grpcv1.LoadTest, container *corev1.Container) error {
+	runs, err := restartedRuns(test)
+	if err != nil {
+		return err
+	}
+	if runs == 0 {
+		return nil
+	}
+	if len(container.Command) == 0 {
+		return errors.Wrapf(errNoCommand, "container %q must set a command to be restarted between scenarios", container.Name)
+	}
+
+	command := []string{"/bin/sh", "-c", workerSupervisorScript, "supervisor"}
+	command = append(command, container.Command...)
+	command = append(command, container.Args...)
+	container.Command = command
+	container.Args = nil
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.WorkerRunsEnv,
+		Value: fmt.Sprint(runs),
+	})
+	return nil
+}
+
+// addDriverRestarts asks the driver to run each scenario on its own, and to
+// restart the workers between scenarios. The container is left unchanged
+// unless the test restarts its workers and has several scenarios.
+func addDriverRestarts(test *grpcv1.LoadTest, container *corev1.Container) error {
+	runs, err := restartedRuns(test)
+	if err != nil {
+		return err
+	}
+	if runs == 0 {
+		return nil
+	}
+
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  config.RestartWorkersEnv,
+		Value: "true",
+	})
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("Worker restarts", func() {
+	var defaults *config.Defaults
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		defaults = newDefaults()
+		test = newLoadTest()
+		test.Spec.RestartWorkers = true
+		test.Spec.ScenariosJSON = `{"scenarios": [{"name": "unary"}, {"name": "streaming"}]}`
+	})
+
+	It("wraps the command of the workers in a supervisor", func() {
+		server := &test.Spec.Servers[0]
+		command := append(append([]string{}, server.Run[0].Command...), server.Run[0].Args...)
+
+		pod, err := New(defaults, test).PodForServer(server)
+		Expect(err).ToNot(HaveOccurred())
+
+		runContainer := pod.Spec.Containers[0]
+		Expect(runContainer.Command).To(Equal(append([]string{"/bin/sh", "-c", workerSupervisorScript, "supervisor"}, command...)))
+		Expect(runContainer.Args).To(BeEmpty())
+		Expect(runContainer.Env).To(ContainElement(corev1.EnvVar{
+			Name:  config.WorkerRunsEnv,
+			Value: "2",
+		}))
+
+		pod, err = New(defaults, test).PodForClient(&test.Spec.Clients[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Command[:4]).To(Equal([]string{"/bin/sh", "-c", workerSupervisorScript, "supervisor"}))
+	})
+
+	It("asks the driver to restart the workers", func() {
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  config.RestartWorkersEnv,
+			Value: "true",
+		}))
+	})
+
+	It("leaves the workers unchanged when the option is not set", func() {
+		test.Spec.RestartWorkers = false
+		server := &test.Spec.Servers[0]
+
+		pod, err := New(defaults, test).PodForServer(server)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Command).To(Equal(server.Run[0].Command))
+
+		pod, err = New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal(config.RestartWorkersEnv))
+		}
+	})
+
+	It("leaves the workers unchanged when the test has a single scenario", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": {"name": "unary"}}`
+		server := &test.Spec.Servers[0]
+
+		pod, err := New(defaults, test).PodForServer(server)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Command).To(Equal(server.Run[0].Command))
+	})
+
+	It("returns an error when a worker does not set a command", func() {
+		test.Spec.Servers[0].Run[0].Command = nil
+
+		_, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, errNoCommand)).To(BeTrue())
+	})
+})
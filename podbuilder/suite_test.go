@@ -87,6 +87,7 @@ func newDefaults() *config.Defaults {
 		},
 		CloneImage:  "gcr.io/grpc-fake-project/test-infra/clone",
 		ReadyImage:  "gcr.io/grpc-fake-project/test-infra/ready",
+		NetemImage:  "gcr.io/grpc-fake-project/test-infra/netem",
 		DriverImage: "gcr.io/grpc-fake-project/test-infra/driver",
 		Languages: []config.LanguageDefault{
 			{
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// addSupervisor wraps the command of the run container of a client or server
+// with the supervisor program, which sends SIGTERM to the command when the
+// timeout of the test is exceeded, and SIGKILL if it is still running after
+// the time allowed to respond. The program reads both from the environment of
+// the run container, and reports its action in the termination message. An
+// init container copies the program into a volume shared with the run
+// container.
+//
+// The container is left unchanged if the defaults do not provide an image
+// with the supervisor, or if the container does not set a command, since the
+// entrypoint of its image cannot be wrapped. The worker must then honor the
+// timeout on its own.
+func addSupervisor(defs *config.Defaults, podspec *corev1.PodSpec, container *corev1.Container) {
+	if defs.SupervisorImage == "" || len(container.Command) == 0 {
+		return
+	}
+
+	podspec.InitContainers = append(podspec.InitContainers, corev1.Container{
+		Name:    config.SupervisorInitContainerName,
+		Image:   defs.SupervisorImage,
+		Command: []string{"cp", "/usr/local/bin/supervisor", config.SupervisorBinaryPath},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      config.SupervisorVolumeName,
+				MountPath: config.SupervisorMountPath,
+			},
+		},
+	})
+	podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+		Name: config.SupervisorVolumeName,
+	})
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      config.SupervisorVolumeName,
+		MountPath: config.SupervisorMountPath,
+		ReadOnly:  true,
+	})
+	container.Args = append(append([]string{"--"}, container.Command...), container.Args...)
+	container.Command = []string{config.SupervisorBinaryPath}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+var _ = Describe("Supervisor", func() {
+	var defaults *config.Defaults
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		defaults = newDefaults()
+		defaults.SupervisorImage = "gcr.io/grpc-fake-project/test-infra/supervisor"
+		test = newLoadTest()
+	})
+
+	It("wraps the command of the workers with the supervisor program", func() {
+		server := &test.Spec.Servers[0]
+		command := append(append([]string{"--"}, server.Run[0].Command...), server.Run[0].Args...)
+
+		pod, err := New(defaults, test).PodForServer(server)
+		Expect(err).ToNot(HaveOccurred())
+
+		runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+		Expect(runContainer.Command).To(Equal([]string{config.SupervisorBinaryPath}))
+		Expect(runContainer.Args).To(Equal(command))
+		Expect(getNames(runContainer.VolumeMounts)).To(ContainElement(config.SupervisorVolumeName))
+
+		pod, err = New(defaults, test).PodForClient(&test.Spec.Clients[0])
+		Expect(err).ToNot(HaveOccurred())
+		runContainer = kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+		Expect(runContainer.Command).To(Equal([]string{config.SupervisorBinaryPath}))
+	})
+
+	It("adds an init container that provides the supervisor program", func() {
+		pod, err := New(defaults, test).PodForClient(&test.Spec.Clients[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		initContainer := kubehelpers.ContainerForName(config.SupervisorInitContainerName, pod.Spec.InitContainers)
+		Expect(initContainer).ToNot(BeNil())
+		Expect(initContainer.Image).To(Equal(defaults.SupervisorImage))
+		Expect(getNames(pod.Spec.Volumes)).To(ContainElement(config.SupervisorVolumeName))
+	})
+
+	It("wraps the restarted command of the workers", func() {
+		test.Spec.RestartWorkers = true
+		test.Spec.ScenariosJSON = `{"scenarios": [{"name": "unary"}, {"name": "streaming"}]}`
+
+		pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+		Expect(runContainer.Command).To(Equal([]string{config.SupervisorBinaryPath}))
+		Expect(runContainer.Args[:5]).To(Equal([]string{"--", "/bin/sh", "-c", workerSupervisorScript, "supervisor"}))
+	})
+
+	It("leaves the driver unchanged", func() {
+		pod, err := New(defaults, test).PodForDriver(test.Spec.Driver)
+		Expect(err).ToNot(HaveOccurred())
+
+		runContainer := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+		Expect(runContainer.Command).ToNot(Equal([]string{config.SupervisorBinaryPath}))
+		Expect(kubehelpers.ContainerForName(config.SupervisorInitContainerName, pod.Spec.InitContainers)).To(BeNil())
+	})
+
+	It("leaves the workers unchanged without a supervisor image", func() {
+		defaults.SupervisorImage = ""
+
+		pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(pod.Spec.Containers[0].Command).To(Equal(test.Spec.Servers[0].Run[0].Command))
+		Expect(kubehelpers.ContainerForName(config.SupervisorInitContainerName, pod.Spec.InitContainers)).To(BeNil())
+	})
+
+	It("leaves workers without a command unchanged", func() {
+		test.Spec.Servers[0].Run[0].Command = nil
+
+		pod, err := New(defaults, test).PodForServer(&test.Spec.Servers[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(pod.Spec.Containers[0].Command).To(BeEmpty())
+		Expect(kubehelpers.ContainerForName(config.SupervisorInitContainerName, pod.Spec.InitContainers)).To(BeNil())
+	})
+})
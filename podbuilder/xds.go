@@ -0,0 +1,146 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// xdsBootstrapVolumeName is the name of the volume where the xds-server
+// container writes the bootstrap file of a proxyless client.
+const xdsBootstrapVolumeName = "grpc-xds-bootstrap"
+
+// xdsBootstrapMountPath is the path where the bootstrap volume is mounted in
+// the run container and the xds-server container of a proxyless client.
+const xdsBootstrapMountPath = "/bootstrap"
+
+// addXdsNodeID sets the $XDS_NODE_ID environment variable on the xds-server
+// container of a client, and on its sidecar container if it is proxied, so
+// that the configuration of each test is served to a node ID of its own. The
+// node ID is derived from the test, which allows concurrent tests to share an
+// xDS server. The pod is left unchanged if the client has no xds-server
+// container, or if the node ID is set with the -node-ID flag of the
+// xds-server container or in its environment.
+func addXdsNodeID(test *grpcv1.LoadTest, pod *corev1.Pod) {
+	xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+	if xdsServer == nil {
+		return
+	}
+	for _, arg := range xdsServer.Args {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		if strings.HasPrefix(arg, "-") && name == "node-ID" {
+			return
+		}
+	}
+	for _, env := range xdsServer.Env {
+		if env.Name == config.XdsNodeIDEnv {
+			return
+		}
+	}
+
+	env := corev1.EnvVar{
+		Name:  config.XdsNodeIDEnv,
+		Value: kubehelpers.XdsNodeID(test),
+	}
+	xdsServer.Env = append(xdsServer.Env, env)
+	if sidecar := kubehelpers.ContainerForName(config.SidecarContainerName, pod.Spec.Containers); sidecar != nil {
+		sidecar.Env = append(sidecar.Env, env)
+	}
+}
+
+// addXdsBootstrap gives the run container of a proxyless client access to its
+// xDS bootstrap configuration. By default, a volume is shared between the run
+// container and the xds-server container, which writes the bootstrap file to
+// it. With the Env mode, the contents of the bootstrap file are set in the
+// GRPC_XDS_BOOTSTRAP_CONFIG environment variable of the run container instead,
+// and the GRPC_XDS_BOOTSTRAP environment variable is removed, since it would
+// take precedence and point at a file that does not exist.
+//
+// The pod is left unchanged if the client has no xds-server container, or has
+// a sidecar container, since proxied clients receive their configuration
+// through the sidecar. An error is returned if the bootstrap configuration
+// cannot be generated.
+func addXdsBootstrap(client *grpcv1.Client, pod *corev1.Pod, runContainer *corev1.Container) error {
+	xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+	if xdsServer == nil {
+		return nil
+	}
+	if sidecar := kubehelpers.ContainerForName(config.SidecarContainerName, pod.Spec.Containers); sidecar != nil {
+		return nil
+	}
+
+	if client.XdsBootstrap == grpcv1.EnvXdsBootstrap {
+		bootstrap, err := kubehelpers.XdsBootstrapConfig(xdsServer)
+		if err != nil {
+			return err
+		}
+		var env []corev1.EnvVar
+		for _, envVar := range runContainer.Env {
+			if envVar.Name != config.XdsBootstrapEnv {
+				env = append(env, envVar)
+			}
+		}
+		runContainer.Env = append(env, corev1.EnvVar{
+			Name:  config.XdsBootstrapConfigEnv,
+			Value: bootstrap,
+		})
+		return nil
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: xdsBootstrapVolumeName})
+
+	runContainer.VolumeMounts = append(runContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      xdsBootstrapVolumeName,
+		MountPath: xdsBootstrapMountPath,
+		ReadOnly:  true,
+	})
+	xdsServer.VolumeMounts = append(xdsServer.VolumeMounts, corev1.VolumeMount{
+		Name:      xdsBootstrapVolumeName,
+		MountPath: xdsBootstrapMountPath,
+		ReadOnly:  false,
+	})
+	return nil
+}
+
+// addCSDSPort sets the $CSDS_PORT environment variable on the run container
+// and the xds-server container of a proxyless client, so the client can serve
+// CSDS on the port and the xds-server container can fetch the configuration
+// of the client from it. The pod is left unchanged if the port is zero or the
+// client has no xds-server container.
+func addCSDSPort(pod *corev1.Pod, runContainer *corev1.Container, port int32) {
+	if port == 0 {
+		return
+	}
+	xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+	if xdsServer == nil {
+		return
+	}
+
+	env := corev1.EnvVar{
+		Name:  config.CSDSPortEnv,
+		Value: fmt.Sprint(port),
+	}
+	runContainer.Env = append(runContainer.Env, env)
+	xdsServer.Env = append(xdsServer.Env, env)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbuilder
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+var _ = Describe("xDS bootstrap", func() {
+	var defaults *config.Defaults
+	var test *grpcv1.LoadTest
+	var client *grpcv1.Client
+
+	BeforeEach(func() {
+		defaults = newDefaults()
+		test = newLoadTest()
+		client = &test.Spec.Clients[0]
+		client.Run[0].Env = append(client.Run[0].Env, corev1.EnvVar{
+			Name:  config.XdsBootstrapEnv,
+			Value: "/bootstrap/bootstrap.json",
+		})
+		client.Run = append(client.Run, corev1.Container{
+			Name:    config.XdsServerContainerName,
+			Image:   "xds-image",
+			Command: []string{"main"},
+			Args:    []string{"-node-ID", "node", "-xds-server-port=18001"},
+		})
+	})
+
+	It("sets the bootstrap in an environment variable with the Env mode", func() {
+		client.XdsBootstrap = grpcv1.EnvXdsBootstrap
+
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Volumes).ToNot(ContainElement(corev1.Volume{Name: xdsBootstrapVolumeName}))
+
+		runContainer := pod.Spec.Containers[0]
+		Expect(runContainer.VolumeMounts).ToNot(ContainElement(HaveField("Name", xdsBootstrapVolumeName)))
+
+		var bootstrap string
+		for _, env := range runContainer.Env {
+			Expect(env.Name).ToNot(Equal(config.XdsBootstrapEnv))
+			if env.Name == config.XdsBootstrapConfigEnv {
+				bootstrap = env.Value
+			}
+		}
+		Expect(bootstrap).ToNot(BeEmpty())
+
+		var parsed struct {
+			XdsServers []struct {
+				ServerURI string `json:"server_uri"`
+			} `json:"xds_servers"`
+			Node struct {
+				ID string `json:"id"`
+			} `json:"node"`
+		}
+		Expect(json.Unmarshal([]byte(bootstrap), &parsed)).To(Succeed())
+		Expect(parsed.Node.ID).To(Equal("node"))
+		Expect(parsed.XdsServers).To(HaveLen(1))
+		Expect(parsed.XdsServers[0].ServerURI).To(Equal("localhost:18001"))
+	})
+
+	It("shares a volume with the Volume mode", func() {
+		client.XdsBootstrap = grpcv1.VolumeXdsBootstrap
+
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{Name: xdsBootstrapVolumeName}))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(HaveField("Name", config.XdsBootstrapEnv)))
+	})
+
+	It("returns an error when the bootstrap cannot be generated", func() {
+		client.XdsBootstrap = grpcv1.EnvXdsBootstrap
+		xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, client.Run)
+		xdsServer.Args = append(xdsServer.Args, "-bootstrap-template", "/etc/bootstrap.tmpl")
+
+		_, err := New(defaults, test).PodForClient(client)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("exposes the CSDS port to the client and the xds-server", func() {
+		client.CSDSPort = 50052
+
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		env := corev1.EnvVar{Name: config.CSDSPortEnv, Value: "50052"}
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(env))
+		xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+		Expect(xdsServer.Env).To(ContainElement(env))
+	})
+
+	It("does not expose a CSDS port when it is not set", func() {
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].Env).ToNot(ContainElement(HaveField("Name", config.CSDSPortEnv)))
+	})
+
+	It("sets a node ID derived from the test when no node ID flag is set", func() {
+		client.XdsBootstrap = grpcv1.EnvXdsBootstrap
+		xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, client.Run)
+		xdsServer.Args = []string{"-xds-server-port=18001"}
+
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		nodeID := kubehelpers.XdsNodeID(test)
+		xdsServer = kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+		Expect(xdsServer.Env).To(ContainElement(corev1.EnvVar{Name: config.XdsNodeIDEnv, Value: nodeID}))
+
+		var bootstrap string
+		for _, env := range pod.Spec.Containers[0].Env {
+			if env.Name == config.XdsBootstrapConfigEnv {
+				bootstrap = env.Value
+			}
+		}
+		Expect(bootstrap).To(ContainSubstring(nodeID))
+	})
+
+	It("sets the node ID on the sidecar of a proxied client", func() {
+		xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, client.Run)
+		xdsServer.Args = nil
+		client.Run = append(client.Run, corev1.Container{
+			Name:  config.SidecarContainerName,
+			Image: "sidecar-image",
+		})
+
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		env := corev1.EnvVar{Name: config.XdsNodeIDEnv, Value: kubehelpers.XdsNodeID(test)}
+		sidecar := kubehelpers.ContainerForName(config.SidecarContainerName, pod.Spec.Containers)
+		Expect(sidecar.Env).To(ContainElement(env))
+	})
+
+	It("does not set a node ID when the node ID flag is set", func() {
+		pod, err := New(defaults, test).PodForClient(client)
+		Expect(err).ToNot(HaveOccurred())
+
+		xdsServer := kubehelpers.ContainerForName(config.XdsServerContainerName, pod.Spec.Containers)
+		Expect(xdsServer.Env).ToNot(ContainElement(HaveField("Name", config.XdsNodeIDEnv)))
+	})
+})
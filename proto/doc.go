@@ -21,3 +21,6 @@ package proto
 
 // Generate endpointupdater package
 //go:generate protoc -Iendpointupdater --go_out=endpointupdater --go-grpc_out=endpointupdater --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative endpointupdater/endpoint.proto
+
+// Generate loadtestapi package
+//go:generate protoc -Iloadtestapi --go_out=loadtestapi --go-grpc_out=loadtestapi --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative loadtestapi/loadtest_api.proto
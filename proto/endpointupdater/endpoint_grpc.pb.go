@@ -22,8 +22,12 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TestUpdaterClient interface {
-	// Sends an update
+	// Sends an update. The update replaces any update that was sent before and
+	// is not applied until it is committed, so it can be retried safely.
 	UpdateTest(ctx context.Context, in *TestUpdateRequest, opts ...grpc.CallOption) (*TestUpdateReply, error)
+	// Applies the last update. Commit can be retried safely, the update is
+	// applied once.
+	Commit(ctx context.Context, in *Void, opts ...grpc.CallOption) (*TestUpdateReply, error)
 	QuitTestUpdateServer(ctx context.Context, in *Void, opts ...grpc.CallOption) (*Void, error)
 }
 
@@ -44,6 +48,15 @@ func (c *testUpdaterClient) UpdateTest(ctx context.Context, in *TestUpdateReques
 	return out, nil
 }
 
+func (c *testUpdaterClient) Commit(ctx context.Context, in *Void, opts ...grpc.CallOption) (*TestUpdateReply, error) {
+	out := new(TestUpdateReply)
+	err := c.cc.Invoke(ctx, "/endpointupdater.TestUpdater/Commit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *testUpdaterClient) QuitTestUpdateServer(ctx context.Context, in *Void, opts ...grpc.CallOption) (*Void, error) {
 	out := new(Void)
 	err := c.cc.Invoke(ctx, "/endpointupdater.TestUpdater/QuitTestUpdateServer", in, out, opts...)
@@ -57,8 +70,12 @@ func (c *testUpdaterClient) QuitTestUpdateServer(ctx context.Context, in *Void,
 // All implementations must embed UnimplementedTestUpdaterServer
 // for forward compatibility
 type TestUpdaterServer interface {
-	// Sends an update
+	// Sends an update. The update replaces any update that was sent before and
+	// is not applied until it is committed, so it can be retried safely.
 	UpdateTest(context.Context, *TestUpdateRequest) (*TestUpdateReply, error)
+	// Applies the last update. Commit can be retried safely, the update is
+	// applied once.
+	Commit(context.Context, *Void) (*TestUpdateReply, error)
 	QuitTestUpdateServer(context.Context, *Void) (*Void, error)
 	mustEmbedUnimplementedTestUpdaterServer()
 }
@@ -70,6 +87,9 @@ type UnimplementedTestUpdaterServer struct {
 func (UnimplementedTestUpdaterServer) UpdateTest(context.Context, *TestUpdateRequest) (*TestUpdateReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateTest not implemented")
 }
+func (UnimplementedTestUpdaterServer) Commit(context.Context, *Void) (*TestUpdateReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
 func (UnimplementedTestUpdaterServer) QuitTestUpdateServer(context.Context, *Void) (*Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QuitTestUpdateServer not implemented")
 }
@@ -104,6 +124,24 @@ func _TestUpdater_UpdateTest_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TestUpdater_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Void)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TestUpdaterServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/endpointupdater.TestUpdater/Commit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TestUpdaterServer).Commit(ctx, req.(*Void))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TestUpdater_QuitTestUpdateServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Void)
 	if err := dec(in); err != nil {
@@ -133,6 +171,10 @@ var TestUpdater_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateTest",
 			Handler:    _TestUpdater_UpdateTest_Handler,
 		},
+		{
+			MethodName: "Commit",
+			Handler:    _TestUpdater_Commit_Handler,
+		},
 		{
 			MethodName: "QuitTestUpdateServer",
 			Handler:    _TestUpdater_QuitTestUpdateServer_Handler,
@@ -0,0 +1,284 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: loadtest_api.proto
+
+package loadtestapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// LoadTestServiceClient is the client API for LoadTestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LoadTestServiceClient interface {
+	// Returns the summary of a test.
+	GetLoadTest(ctx context.Context, in *GetLoadTestRequest, opts ...grpc.CallOption) (*LoadTestSummary, error)
+	// Returns the summaries of the tests that match a label selector.
+	ListLoadTests(ctx context.Context, in *ListLoadTestsRequest, opts ...grpc.CallOption) (*ListLoadTestsResponse, error)
+	// Streams the summaries of the tests that match a label selector. An ADDED
+	// event is sent for each existing test first, followed by an event each
+	// time the summary of a test changes.
+	WatchLoadTests(ctx context.Context, in *ListLoadTestsRequest, opts ...grpc.CallOption) (LoadTestService_WatchLoadTestsClient, error)
+	// Streams the log of the driver of a test, so its progress can be watched
+	// while it runs. The stream ends when the whole log has been sent or, when
+	// the request follows the log, when the driver stops.
+	StreamDriverLogs(ctx context.Context, in *StreamDriverLogsRequest, opts ...grpc.CallOption) (LoadTestService_StreamDriverLogsClient, error)
+}
+
+type loadTestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoadTestServiceClient(cc grpc.ClientConnInterface) LoadTestServiceClient {
+	return &loadTestServiceClient{cc}
+}
+
+func (c *loadTestServiceClient) GetLoadTest(ctx context.Context, in *GetLoadTestRequest, opts ...grpc.CallOption) (*LoadTestSummary, error) {
+	out := new(LoadTestSummary)
+	err := c.cc.Invoke(ctx, "/loadtestapi.LoadTestService/GetLoadTest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loadTestServiceClient) ListLoadTests(ctx context.Context, in *ListLoadTestsRequest, opts ...grpc.CallOption) (*ListLoadTestsResponse, error) {
+	out := new(ListLoadTestsResponse)
+	err := c.cc.Invoke(ctx, "/loadtestapi.LoadTestService/ListLoadTests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loadTestServiceClient) WatchLoadTests(ctx context.Context, in *ListLoadTestsRequest, opts ...grpc.CallOption) (LoadTestService_WatchLoadTestsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LoadTestService_ServiceDesc.Streams[0], "/loadtestapi.LoadTestService/WatchLoadTests", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &loadTestServiceWatchLoadTestsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LoadTestService_WatchLoadTestsClient interface {
+	Recv() (*LoadTestEvent, error)
+	grpc.ClientStream
+}
+
+type loadTestServiceWatchLoadTestsClient struct {
+	grpc.ClientStream
+}
+
+func (x *loadTestServiceWatchLoadTestsClient) Recv() (*LoadTestEvent, error) {
+	m := new(LoadTestEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *loadTestServiceClient) StreamDriverLogs(ctx context.Context, in *StreamDriverLogsRequest, opts ...grpc.CallOption) (LoadTestService_StreamDriverLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LoadTestService_ServiceDesc.Streams[1], "/loadtestapi.LoadTestService/StreamDriverLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &loadTestServiceStreamDriverLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LoadTestService_StreamDriverLogsClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type loadTestServiceStreamDriverLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *loadTestServiceStreamDriverLogsClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadTestServiceServer is the server API for LoadTestService service.
+// All implementations must embed UnimplementedLoadTestServiceServer
+// for forward compatibility
+type LoadTestServiceServer interface {
+	// Returns the summary of a test.
+	GetLoadTest(context.Context, *GetLoadTestRequest) (*LoadTestSummary, error)
+	// Returns the summaries of the tests that match a label selector.
+	ListLoadTests(context.Context, *ListLoadTestsRequest) (*ListLoadTestsResponse, error)
+	// Streams the summaries of the tests that match a label selector. An ADDED
+	// event is sent for each existing test first, followed by an event each
+	// time the summary of a test changes.
+	WatchLoadTests(*ListLoadTestsRequest, LoadTestService_WatchLoadTestsServer) error
+	// Streams the log of the driver of a test, so its progress can be watched
+	// while it runs. The stream ends when the whole log has been sent or, when
+	// the request follows the log, when the driver stops.
+	StreamDriverLogs(*StreamDriverLogsRequest, LoadTestService_StreamDriverLogsServer) error
+	mustEmbedUnimplementedLoadTestServiceServer()
+}
+
+// UnimplementedLoadTestServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLoadTestServiceServer struct {
+}
+
+func (UnimplementedLoadTestServiceServer) GetLoadTest(context.Context, *GetLoadTestRequest) (*LoadTestSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoadTest not implemented")
+}
+func (UnimplementedLoadTestServiceServer) ListLoadTests(context.Context, *ListLoadTestsRequest) (*ListLoadTestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoadTests not implemented")
+}
+func (UnimplementedLoadTestServiceServer) WatchLoadTests(*ListLoadTestsRequest, LoadTestService_WatchLoadTestsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLoadTests not implemented")
+}
+func (UnimplementedLoadTestServiceServer) StreamDriverLogs(*StreamDriverLogsRequest, LoadTestService_StreamDriverLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDriverLogs not implemented")
+}
+func (UnimplementedLoadTestServiceServer) mustEmbedUnimplementedLoadTestServiceServer() {}
+
+// UnsafeLoadTestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoadTestServiceServer will
+// result in compilation errors.
+type UnsafeLoadTestServiceServer interface {
+	mustEmbedUnimplementedLoadTestServiceServer()
+}
+
+func RegisterLoadTestServiceServer(s grpc.ServiceRegistrar, srv LoadTestServiceServer) {
+	s.RegisterService(&LoadTestService_ServiceDesc, srv)
+}
+
+func _LoadTestService_GetLoadTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoadTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoadTestServiceServer).GetLoadTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loadtestapi.LoadTestService/GetLoadTest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoadTestServiceServer).GetLoadTest(ctx, req.(*GetLoadTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoadTestService_ListLoadTests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoadTestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoadTestServiceServer).ListLoadTests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loadtestapi.LoadTestService/ListLoadTests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoadTestServiceServer).ListLoadTests(ctx, req.(*ListLoadTestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoadTestService_WatchLoadTests_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListLoadTestsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoadTestServiceServer).WatchLoadTests(m, &loadTestServiceWatchLoadTestsServer{stream})
+}
+
+type LoadTestService_WatchLoadTestsServer interface {
+	Send(*LoadTestEvent) error
+	grpc.ServerStream
+}
+
+type loadTestServiceWatchLoadTestsServer struct {
+	grpc.ServerStream
+}
+
+func (x *loadTestServiceWatchLoadTestsServer) Send(m *LoadTestEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LoadTestService_StreamDriverLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDriverLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoadTestServiceServer).StreamDriverLogs(m, &loadTestServiceStreamDriverLogsServer{stream})
+}
+
+type LoadTestService_StreamDriverLogsServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type loadTestServiceStreamDriverLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *loadTestServiceStreamDriverLogsServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// LoadTestService_ServiceDesc is the grpc.ServiceDesc for LoadTestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LoadTestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loadtestapi.LoadTestService",
+	HandlerType: (*LoadTestServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLoadTest",
+			Handler:    _LoadTestService_GetLoadTest_Handler,
+		},
+		{
+			MethodName: "ListLoadTests",
+			Handler:    _LoadTestService_ListLoadTests_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLoadTests",
+			Handler:       _LoadTestService_WatchLoadTests_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDriverLogs",
+			Handler:       _LoadTestService_StreamDriverLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "loadtest_api.proto",
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// ClusterSummary describes the load tests in a cluster, so that dashboards
+// and tools can monitor the cluster without listing every test.
+type ClusterSummary struct {
+	// Tests is the number of load tests.
+	Tests int `json:"tests"`
+
+	// States maps each state to the number of tests in the state. Tests
+	// that have not been reconciled yet are counted with the Unknown
+	// state.
+	States map[grpcv1.LoadTestState]int `json:"states"`
+
+	// PendingTests is the number of tests that have not terminated and
+	// have no pods yet, usually because they wait for nodes.
+	PendingTests int `json:"pendingTests"`
+
+	// OldestPendingSeconds is the age of the oldest pending test, or zero
+	// if no test is pending.
+	OldestPendingSeconds float64 `json:"oldestPendingSeconds"`
+
+	// Pools maps the name of each pool to its usage.
+	Pools map[string]*PoolSummary `json:"pools"`
+}
+
+// PoolSummary describes the usage of a pool.
+type PoolSummary struct {
+	// Capacity is the number of nodes in the pool that may be used by
+	// tests. It is omitted if the capacity of the pool is not known.
+	Capacity *int `json:"capacity,omitempty"`
+
+	// ActivePods is the number of pods in the pool that have not
+	// terminated, each occupying a node unless the test shares nodes.
+	ActivePods int `json:"activePods"`
+
+	// ActiveTests is the number of tests that have not terminated and have
+	// pods in the pool.
+	ActiveTests int `json:"activeTests"`
+
+	// PendingTests is the number of pending tests that request the pool.
+	PendingTests int `json:"pendingTests"`
+}
+
+// SummarizeCluster accepts all load tests and pods in a cluster and the
+// capacity of each pool, and returns a summary of the tests. Pending tests
+// are those that have not terminated and have no pods, as in IsFairShareTurn.
+// Components of pending tests without a pool are counted in the default pool
+// keys, such as DefaultClientPool.
+func SummarizeCluster(tests []grpcv1.LoadTest, allPods []corev1.Pod, capacities map[string]int, now time.Time) *ClusterSummary {
+	summary := &ClusterSummary{
+		Tests:  len(tests),
+		States: make(map[grpcv1.LoadTestState]int),
+		Pools:  make(map[string]*PoolSummary),
+	}
+	pool := func(name string) *PoolSummary {
+		p, ok := summary.Pools[name]
+		if !ok {
+			p = &PoolSummary{}
+			summary.Pools[name] = p
+		}
+		return p
+	}
+	for name, capacity := range capacities {
+		pool(name).Capacity = optional.IntPtr(capacity)
+	}
+
+	var oldestPending *grpcv1.LoadTest
+	for i := range tests {
+		test := &tests[i]
+		state := test.Status.State
+		if state == "" {
+			state = grpcv1.Unknown
+		}
+		summary.States[state]++
+		if state.IsTerminated() {
+			continue
+		}
+
+		pods := ownedPods(test, allPods)
+		if len(pods) == 0 {
+			summary.PendingTests++
+			for name := range poolsForLoadTest(test) {
+				pool(name).PendingTests++
+			}
+			if oldestPending == nil || isOlder(test, oldestPending) {
+				oldestPending = test
+			}
+			continue
+		}
+
+		activePools := make(map[string]bool)
+		for _, pod := range pods {
+			if name, ok := activePool(pod); ok {
+				activePools[name] = true
+			}
+		}
+		for name := range activePools {
+			pool(name).ActiveTests++
+		}
+	}
+
+	for i := range allPods {
+		if name, ok := activePool(&allPods[i]); ok {
+			pool(name).ActivePods++
+		}
+	}
+
+	if oldestPending != nil {
+		summary.OldestPendingSeconds = now.Sub(oldestPending.CreationTimestamp.Time).Seconds()
+	}
+	return summary
+}
+
+// activePool returns the pool of a pod that has not terminated, and false if
+// the pod has terminated or has no pool.
+func activePool(pod *corev1.Pod) (string, bool) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return "", false
+	}
+	name, ok := pod.Labels[config.PoolLabel]
+	return name, ok
+}
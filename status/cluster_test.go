@@ -0,0 +1,131 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("SummarizeCluster", func() {
+	var now time.Time
+
+	newTest := func(name string, state grpcv1.LoadTestState, age time.Duration) grpcv1.LoadTest {
+		return grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver:  &grpcv1.Driver{Pool: optional.StringPtr("drivers")},
+				Servers: []grpcv1.Server{{Pool: optional.StringPtr("workers")}},
+				Clients: []grpcv1.Client{{}},
+			},
+			Status: grpcv1.LoadTestStatus{State: state},
+		}
+	}
+
+	newPod := func(test grpcv1.LoadTest, name string, pool string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            test.Name + "-" + name,
+				Namespace:       test.Namespace,
+				Labels:          map[string]string{config.PoolLabel: pool},
+				OwnerReferences: []metav1.OwnerReference{{UID: test.UID}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+	})
+
+	It("counts tests by state and finds the oldest pending test", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("new", "", time.Minute),
+			newTest("waiting", grpcv1.Initializing, 5*time.Minute),
+			newTest("running", grpcv1.Running, 10*time.Minute),
+			newTest("done", grpcv1.Succeeded, time.Hour),
+		}
+		pods := []corev1.Pod{
+			newPod(tests[2], "driver-0", "drivers", corev1.PodRunning),
+			newPod(tests[2], "server-0", "workers", corev1.PodRunning),
+			newPod(tests[2], "client-0", "workers", corev1.PodRunning),
+			newPod(tests[3], "driver-0", "drivers", corev1.PodSucceeded),
+		}
+
+		summary := SummarizeCluster(tests, pods, map[string]int{"drivers": 2, "workers": 4}, now)
+		Expect(summary.Tests).To(Equal(4))
+		Expect(summary.States).To(Equal(map[grpcv1.LoadTestState]int{
+			grpcv1.Unknown:      1,
+			grpcv1.Initializing: 1,
+			grpcv1.Running:      1,
+			grpcv1.Succeeded:    1,
+		}))
+		Expect(summary.PendingTests).To(Equal(2))
+		Expect(summary.OldestPendingSeconds).To(BeNumerically("~", 300, 1))
+	})
+
+	It("reports the usage of each pool", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("waiting", grpcv1.Initializing, 5*time.Minute),
+			newTest("running", grpcv1.Running, 10*time.Minute),
+			newTest("done", grpcv1.Succeeded, time.Hour),
+		}
+		pods := []corev1.Pod{
+			newPod(tests[1], "driver-0", "drivers", corev1.PodRunning),
+			newPod(tests[1], "server-0", "workers", corev1.PodRunning),
+			newPod(tests[1], "client-0", "workers", corev1.PodPending),
+			newPod(tests[2], "driver-0", "drivers", corev1.PodSucceeded),
+		}
+
+		summary := SummarizeCluster(tests, pods, map[string]int{"drivers": 2, "workers": 4}, now)
+		Expect(*summary.Pools["drivers"]).To(Equal(PoolSummary{
+			Capacity:     optional.IntPtr(2),
+			ActivePods:   1,
+			ActiveTests:  1,
+			PendingTests: 1,
+		}))
+		Expect(*summary.Pools["workers"]).To(Equal(PoolSummary{
+			Capacity:     optional.IntPtr(4),
+			ActivePods:   2,
+			ActiveTests:  1,
+			PendingTests: 1,
+		}))
+		Expect(summary.Pools[DefaultClientPool].PendingTests).To(Equal(1))
+		Expect(summary.Pools[DefaultClientPool].Capacity).To(BeNil())
+	})
+
+	It("reports no pending age when no test is pending", func() {
+		summary := SummarizeCluster(nil, nil, nil, now)
+		Expect(summary.Tests).To(BeZero())
+		Expect(summary.OldestPendingSeconds).To(BeZero())
+	})
+})
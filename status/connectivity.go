@@ -0,0 +1,71 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// ConnectivityReport is the report that the ready init container of the driver
+// writes to its termination message when it cannot connect to the driver port
+// of some of the workers.
+type ConnectivityReport struct {
+	// UnreachableWorkers lists the workers that could not be reached.
+	UnreachableWorkers []grpcv1.UnreachableWorker `json:"unreachableWorkers"`
+}
+
+// UnreachableWorkersForDriverPod accepts the driver pod of a load test and
+// returns the workers that its ready init container reported as unreachable in
+// its termination message. If the ready container has not terminated or did
+// not report any workers, nil is returned. An error is returned if the report
+// cannot be parsed.
+func UnreachableWorkersForDriverPod(pod *corev1.Pod) ([]grpcv1.UnreachableWorker, error) {
+	var message string
+	for i := range pod.Status.InitContainerStatuses {
+		contStat := &pod.Status.InitContainerStatuses[i]
+		if contStat.Name != config.ReadyInitContainerName || contStat.State.Terminated == nil {
+			continue
+		}
+		message = contStat.State.Terminated.Message
+	}
+	if message == "" {
+		return nil, nil
+	}
+
+	var report ConnectivityReport
+	if err := json.Unmarshal([]byte(message), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse connectivity report from termination message: %v", err)
+	}
+	return report.UnreachableWorkers, nil
+}
+
+// unreachableWorkersMessage returns a message that names the workers that the
+// driver could not reach.
+func unreachableWorkersMessage(workers []grpcv1.UnreachableWorker) string {
+	var names []string
+	for _, worker := range workers {
+		names = append(names, fmt.Sprintf("%s (%s)", worker.Name, worker.Address))
+	}
+	return fmt.Sprintf("driver could not reach %d worker(s) before the benchmark: %s", len(workers), strings.Join(names, ", "))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// newUnreachableDriverPod returns a driver pod, whose ready init container has
+// failed with the termination message.
+func newUnreachableDriverPod(message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "driver",
+			Labels: map[string]string{
+				config.RoleLabel:          config.DriverRole,
+				config.ComponentNameLabel: "driver",
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: config.ReadyInitContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 1,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("UnreachableWorkersForDriverPod", func() {
+	It("returns nil when the driver did not report workers", func() {
+		workers, err := UnreachableWorkersForDriverPod(newUnreachableDriverPod(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(workers).To(BeNil())
+	})
+
+	It("returns the reported workers", func() {
+		pod := newUnreachableDriverPod(`{"unreachableWorkers": [{"name": "client-0", "address": "10.0.0.2:10000", "message": "connection refused"}]}`)
+
+		workers, err := UnreachableWorkersForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(workers).To(Equal([]grpcv1.UnreachableWorker{
+			{Name: "client-0", Address: "10.0.0.2:10000", Message: "connection refused"},
+		}))
+	})
+
+	It("returns an error when the report is malformed", func() {
+		_, err := UnreachableWorkersForDriverPod(newUnreachableDriverPod(`{"unreachableWorkers": [`))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ForLoadTest with unreachable workers", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver:         &grpcv1.Driver{Name: optional.StringPtr("driver")},
+				TTLSeconds:     int32(120),
+				TimeoutSeconds: int32(30),
+			},
+		}
+	})
+
+	It("sets errored state naming the unreachable workers", func() {
+		pod := newUnreachableDriverPod(`{"unreachableWorkers": [{"name": "client-0", "address": "10.0.0.2:10000"}]}`)
+
+		status := ForLoadTest(test, []*corev1.Pod{pod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.WorkersUnreachableError))
+		Expect(status.Message).To(ContainSubstring("client-0 (10.0.0.2:10000)"))
+		Expect(status.UnreachableWorkers).To(HaveLen(1))
+	})
+
+	It("reports an init container error when the driver did not report workers", func() {
+		status := ForLoadTest(test, []*corev1.Pod{newUnreachableDriverPod("")}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.InitContainerError))
+		Expect(status.UnreachableWorkers).To(BeNil())
+	})
+})
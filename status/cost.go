@@ -0,0 +1,99 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// UnknownMachineType is used in place of the machine type of nodes that could
+// not be found.
+const UnknownMachineType = "unknown"
+
+// HourlyCost returns the total price per hour of a set of nodes, given their
+// machine types and the price of each machine type. Machine types without a
+// price are not included in the total, and are returned in a sorted list.
+func HourlyCost(machineTypes []string, prices map[string]float64) (float64, []string) {
+	var total float64
+	unpriced := make(map[string]bool)
+	for _, machineType := range machineTypes {
+		price, ok := prices[machineType]
+		if !ok {
+			unpriced[machineType] = true
+			continue
+		}
+		total += price
+	}
+	return total, sortedKeys(unpriced)
+}
+
+// CostForLoadTest estimates the cost of the nodes used by a load test. Each pod
+// is assumed to use its node from the time it started until the test stopped.
+// The machineTypes map accepts the name of a node and returns its machine
+// type, and the prices map accepts a machine type and returns its price per
+// hour. Nil is returned if the test has not stopped.
+func CostForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod, machineTypes map[string]string, prices map[string]float64) *grpcv1.CostEstimate {
+	if test.Status.StopTime == nil {
+		return nil
+	}
+
+	var nodeHours, estimate float64
+	unpriced := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Status.StartTime == nil || pod.Spec.NodeName == "" {
+			continue
+		}
+		hours := test.Status.StopTime.Sub(pod.Status.StartTime.Time).Hours()
+		if hours <= 0 {
+			continue
+		}
+		nodeHours += hours
+
+		machineType, ok := machineTypes[pod.Spec.NodeName]
+		if !ok || machineType == "" {
+			machineType = UnknownMachineType
+		}
+		price, ok := prices[machineType]
+		if !ok {
+			unpriced[machineType] = true
+			continue
+		}
+		estimate += hours * price
+	}
+
+	return &grpcv1.CostEstimate{
+		NodeHours:            fmt.Sprintf("%.3f", nodeHours),
+		Estimate:             fmt.Sprintf("%.4f", estimate),
+		UnpricedMachineTypes: sortedKeys(unpriced),
+	}
+}
+
+// sortedKeys returns the keys of a set in sorted order, or nil if the set is
+// empty.
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
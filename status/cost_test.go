@@ -0,0 +1,103 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("HourlyCost", func() {
+	It("sums the prices of the machine types", func() {
+		total, unpriced := HourlyCost([]string{"a", "b", "a"}, map[string]float64{"a": 1.5, "b": 0.25})
+		Expect(total).To(BeNumerically("~", 3.25))
+		Expect(unpriced).To(BeEmpty())
+	})
+
+	It("lists machine types without a price", func() {
+		total, unpriced := HourlyCost([]string{"a", "c", UnknownMachineType, "c"}, map[string]float64{"a": 1})
+		Expect(total).To(BeNumerically("~", 1))
+		Expect(unpriced).To(Equal([]string{"c", UnknownMachineType}))
+	})
+})
+
+var _ = Describe("CostForLoadTest", func() {
+	var stopTime time.Time
+	var test *grpcv1.LoadTest
+	var pods []*corev1.Pod
+	var prices map[string]float64
+
+	newPod := func(nodeName string, runFor time.Duration) *corev1.Pod {
+		startTime := metav1.NewTime(stopTime.Add(-runFor))
+		return &corev1.Pod{
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{StartTime: &startTime},
+		}
+	}
+
+	BeforeEach(func() {
+		stopTime = time.Now()
+		metaStopTime := metav1.NewTime(stopTime)
+		test = &grpcv1.LoadTest{
+			Status: grpcv1.LoadTestStatus{StopTime: &metaStopTime},
+		}
+		pods = []*corev1.Pod{
+			newPod("node-1", 2*time.Hour),
+			newPod("node-2", time.Hour),
+			newPod("node-3", 30*time.Minute),
+		}
+		prices = map[string]float64{"small": 0.5, "large": 2}
+	})
+
+	It("returns nil when the test has not stopped", func() {
+		test.Status.StopTime = nil
+		Expect(CostForLoadTest(test, pods, nil, prices)).To(BeNil())
+	})
+
+	It("multiplies the price of each node by the time its pod ran", func() {
+		machineTypes := map[string]string{"node-1": "small", "node-2": "large", "node-3": "large"}
+
+		cost := CostForLoadTest(test, pods, machineTypes, prices)
+		Expect(cost).ToNot(BeNil())
+		Expect(cost.NodeHours).To(Equal("3.500"))
+		Expect(cost.Estimate).To(Equal("4.0000"))
+		Expect(cost.UnpricedMachineTypes).To(BeEmpty())
+	})
+
+	It("lists the machine types without a price", func() {
+		machineTypes := map[string]string{"node-1": "small", "node-2": "huge"}
+
+		cost := CostForLoadTest(test, pods, machineTypes, prices)
+		Expect(cost.NodeHours).To(Equal("3.500"))
+		Expect(cost.Estimate).To(Equal("1.0000"))
+		Expect(cost.UnpricedMachineTypes).To(Equal([]string{"huge", UnknownMachineType}))
+	})
+
+	It("ignores pods that have not started", func() {
+		pods = append(pods, &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-4"}})
+		machineTypes := map[string]string{"node-1": "small", "node-2": "large", "node-3": "large", "node-4": "large"}
+
+		cost := CostForLoadTest(test, pods, machineTypes, prices)
+		Expect(cost.NodeHours).To(Equal("3.500"))
+	})
+})
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// OwnerForLoadTest returns the owner of a load test for the purpose of fair
+// share scheduling. The owner is the value of the OwnerLabel on the test, or
+// the namespace of the test when the label is not set.
+func OwnerForLoadTest(test *grpcv1.LoadTest) string {
+	if owner, ok := test.Labels[config.OwnerLabel]; ok && owner != "" {
+		return owner
+	}
+	return test.Namespace
+}
+
+// IsFairShareTurn accepts a load test, all load tests in the cluster and all
+// pods in the cluster. It returns true if the load test may claim capacity
+// under a fair share policy, where pending tests from different owners take
+// turns.
+//
+// A test is pending when it is not terminated and none of its pods have been
+// created, and active when it is not terminated and some of its pods exist.
+// Among the owners of pending tests that compete for at least one pool with the
+// given test, only the owners with the fewest active tests may claim capacity,
+// and each of them may only schedule its oldest pending test. This makes a
+// large batch from one owner alternate with the tests of other owners, rather
+// than delaying them until the entire batch has run.
+//
+// A test that already has pods is always allowed to claim capacity, so that
+// partially scheduled tests are not blocked.
+func IsFairShareTurn(test *grpcv1.LoadTest, tests []grpcv1.LoadTest, allPods []corev1.Pod) bool {
+	if len(ownedPods(test, allPods)) > 0 {
+		return true
+	}
+
+	testPools := poolsForLoadTest(test)
+	activeCount := make(map[string]int)
+	oldestPending := make(map[string]*grpcv1.LoadTest)
+
+	for i := range tests {
+		other := &tests[i]
+		if other.Status.State.IsTerminated() {
+			continue
+		}
+
+		owner := OwnerForLoadTest(other)
+		if len(ownedPods(other, allPods)) > 0 {
+			activeCount[owner]++
+			continue
+		}
+
+		if other.UID != test.UID && !sharesPool(testPools, poolsForLoadTest(other)) {
+			continue
+		}
+		if oldest, ok := oldestPending[owner]; !ok || isOlder(other, oldest) {
+			oldestPending[owner] = other
+		}
+	}
+
+	owner := OwnerForLoadTest(test)
+	if oldest, ok := oldestPending[owner]; ok && oldest.UID != test.UID {
+		return false
+	}
+
+	for otherOwner := range oldestPending {
+		if activeCount[otherOwner] < activeCount[owner] {
+			return false
+		}
+	}
+	return true
+}
+
+// poolsForLoadTest returns the set of pools that the components of a load test
+// run in. Components without a pool are counted in one of the default pool
+// keys. See the DefaultClientPool, DefaultDriverPool and DefaultServerPool
+// constants.
+func poolsForLoadTest(test *grpcv1.LoadTest) map[string]bool {
+	pools := make(map[string]bool)
+
+	if driver := test.Spec.Driver; driver != nil && driver.Pool != nil {
+		pools[*driver.Pool] = true
+	} else if driver != nil {
+		pools[DefaultDriverPool] = true
+	}
+	for _, server := range test.Spec.Servers {
+		if server.Pool != nil {
+			pools[*server.Pool] = true
+		} else {
+			pools[DefaultServerPool] = true
+		}
+	}
+	for _, client := range test.Spec.Clients {
+		if client.Pool != nil {
+			pools[*client.Pool] = true
+		} else {
+			pools[DefaultClientPool] = true
+		}
+	}
+	for _, generator := range test.Spec.Generators {
+		if generator.Pool != nil {
+			pools[*generator.Pool] = true
+		} else {
+			pools[DefaultClientPool] = true
+		}
+	}
+
+	return pools
+}
+
+// sharesPool returns true if two sets of pools have at least one pool in
+// common.
+func sharesPool(a, b map[string]bool) bool {
+	for pool := range a {
+		if b[pool] {
+			return true
+		}
+	}
+	return false
+}
+
+// isOlder returns true if load test a was created before load test b. Tests
+// created at the same time are ordered by name.
+func isOlder(a, b *grpcv1.LoadTest) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("IsFairShareTurn", func() {
+	var baseTime time.Time
+
+	newTest := func(name, owner string, age time.Duration) grpcv1.LoadTest {
+		return grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				UID:               types.UID(name),
+				Labels:            map[string]string{config.OwnerLabel: owner},
+				CreationTimestamp: metav1.NewTime(baseTime.Add(-age)),
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver:  &grpcv1.Driver{Pool: optional.StringPtr("drivers")},
+				Servers: []grpcv1.Server{{Pool: optional.StringPtr("workers")}},
+				Clients: []grpcv1.Client{{Pool: optional.StringPtr("workers")}},
+			},
+		}
+	}
+
+	newPod := func(test grpcv1.LoadTest) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      test.Name + "-server-0",
+				Namespace: test.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{UID: test.UID},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		baseTime = time.Now()
+	})
+
+	It("allows the oldest pending test when there is a single owner", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 2*time.Minute),
+			newTest("a-2", "team-a", time.Minute),
+		}
+
+		Expect(IsFairShareTurn(&tests[0], tests, nil)).To(BeTrue())
+		Expect(IsFairShareTurn(&tests[1], tests, nil)).To(BeFalse())
+	})
+
+	It("gives a turn to owners with fewer active tests", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 3*time.Minute),
+			newTest("a-2", "team-a", 2*time.Minute),
+			newTest("b-1", "team-b", time.Minute),
+		}
+		pods := []corev1.Pod{newPod(tests[0])}
+
+		Expect(IsFairShareTurn(&tests[1], tests, pods)).To(BeFalse())
+		Expect(IsFairShareTurn(&tests[2], tests, pods)).To(BeTrue())
+	})
+
+	It("allows owners with the same number of active tests to compete", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 2*time.Minute),
+			newTest("b-1", "team-b", time.Minute),
+		}
+
+		Expect(IsFairShareTurn(&tests[0], tests, nil)).To(BeTrue())
+		Expect(IsFairShareTurn(&tests[1], tests, nil)).To(BeTrue())
+	})
+
+	It("always allows tests that already have pods", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 3*time.Minute),
+			newTest("a-2", "team-a", 2*time.Minute),
+			newTest("b-1", "team-b", time.Minute),
+		}
+		pods := []corev1.Pod{newPod(tests[0]), newPod(tests[1])}
+
+		Expect(IsFairShareTurn(&tests[1], tests, pods)).To(BeTrue())
+	})
+
+	It("ignores terminated tests", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 3*time.Minute),
+			newTest("a-2", "team-a", 2*time.Minute),
+			newTest("b-1", "team-b", time.Minute),
+		}
+		tests[0].Status.State = grpcv1.Succeeded
+		pods := []corev1.Pod{newPod(tests[0])}
+
+		Expect(IsFairShareTurn(&tests[1], tests, pods)).To(BeTrue())
+	})
+
+	It("ignores pending tests that do not share a pool", func() {
+		tests := []grpcv1.LoadTest{
+			newTest("a-1", "team-a", 3*time.Minute),
+			newTest("a-2", "team-a", 2*time.Minute),
+			newTest("b-1", "team-b", time.Minute),
+		}
+		tests[2].Spec.Driver.Pool = optional.StringPtr("other-drivers")
+		tests[2].Spec.Servers[0].Pool = optional.StringPtr("other-workers")
+		tests[2].Spec.Clients[0].Pool = optional.StringPtr("other-workers")
+		pods := []corev1.Pod{newPod(tests[0])}
+
+		Expect(IsFairShareTurn(&tests[1], tests, pods)).To(BeTrue())
+	})
+})
+
+var _ = Describe("OwnerForLoadTest", func() {
+	It("returns the value of the owner label", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Labels:    map[string]string{config.OwnerLabel: "team-a"},
+			},
+		}
+		Expect(OwnerForLoadTest(test)).To(Equal("team-a"))
+	})
+
+	It("returns the namespace when the owner label is not set", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team-b",
+			},
+		}
+		Expect(OwnerForLoadTest(test)).To(Equal("team-b"))
+	})
+})
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// ghzReport mirrors the fields of the JSON report of ghz that are summarized.
+// Latencies are in nanoseconds.
+type ghzReport struct {
+	RPS                 *float64 `json:"rps"`
+	LatencyDistribution []struct {
+		Percentage float64 `json:"percentage"`
+		Latency    float64 `json:"latency"`
+	} `json:"latencyDistribution"`
+}
+
+// parseGhzReport converts the JSON report of ghz into a driver summary. ghz
+// only reports the percentiles it was asked for, so latencies that are absent
+// from its distribution are left unset.
+func parseGhzReport(message string) (*driverSummary, error) {
+	var report ghzReport
+	if err := json.Unmarshal([]byte(message), &report); err != nil {
+		return nil, err
+	}
+
+	ds := &driverSummary{QPS: report.RPS}
+	for i := range report.LatencyDistribution {
+		latency := report.LatencyDistribution[i].Latency
+		switch report.LatencyDistribution[i].Percentage {
+		case 50:
+			ds.Latency50 = &latency
+		case 99:
+			ds.Latency99 = &latency
+		case 99.9:
+			ds.Latency999 = &latency
+		}
+	}
+	return ds, nil
+}
+
+// generatorAdapters maps each adapter to the function that parses the reports
+// in its format.
+var generatorAdapters = map[grpcv1.GeneratorAdapter]func(string) (*driverSummary, error){
+	grpcv1.GhzAdapter: parseGhzReport,
+}
+
+// SummaryForGeneratorPods accepts a test and its pods, and returns a summary of
+// the reports that its generators wrote to the termination message of their
+// first run container. Only generators with an adapter are considered. The
+// queries per second of all generators are added up, while each latency is the
+// highest that any generator reported. If no generator reported results, nil
+// is returned. An error is returned if a report cannot be parsed.
+func SummaryForGeneratorPods(test *grpcv1.LoadTest, pods []*corev1.Pod) (*grpcv1.ResultSummary, error) {
+	adapters := make(map[string]grpcv1.GeneratorAdapter)
+	for i := range test.Spec.Generators {
+		generator := &test.Spec.Generators[i]
+		if generator.Name != nil && generator.Adapter != "" {
+			adapters[*generator.Name] = generator.Adapter
+		}
+	}
+
+	var total *driverSummary
+	for _, pod := range pods {
+		if pod.Labels[config.RoleLabel] != config.GeneratorRole {
+			continue
+		}
+		name := pod.Labels[config.ComponentNameLabel]
+		adapter, ok := adapters[name]
+		if !ok {
+			continue
+		}
+		parse, ok := generatorAdapters[adapter]
+		if !ok {
+			return nil, fmt.Errorf("generator %q has unknown adapter %q", name, adapter)
+		}
+
+		message := generatorMessage(pod)
+		if message == "" {
+			continue
+		}
+		ds, err := parse(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s report of generator %q: %v", adapter, name, err)
+		}
+
+		if total == nil {
+			total = new(driverSummary)
+		}
+		total.QPS = addFloat(total.QPS, ds.QPS)
+		total.Latency50 = maxFloat(total.Latency50, ds.Latency50)
+		total.Latency99 = maxFloat(total.Latency99, ds.Latency99)
+		total.Latency999 = maxFloat(total.Latency999, ds.Latency999)
+	}
+	if total == nil {
+		return nil, nil
+	}
+
+	return &grpcv1.ResultSummary{
+		QPS:        formatFloat(total.QPS, formatQPS),
+		Latency50:  formatFloat(total.Latency50, formatLatency),
+		Latency99:  formatFloat(total.Latency99, formatLatency),
+		Latency999: formatFloat(total.Latency999, formatLatency),
+	}, nil
+}
+
+// generatorMessage returns the termination message of the first run container
+// of a generator pod, or an empty string if it has not terminated.
+func generatorMessage(pod *corev1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	for i := range pod.Status.ContainerStatuses {
+		contStat := &pod.Status.ContainerStatuses[i]
+		if contStat.Name == pod.Spec.Containers[0].Name && contStat.State.Terminated != nil {
+			return contStat.State.Terminated.Message
+		}
+	}
+	return ""
+}
+
+// addFloat returns the sum of two values, treating nil as absent.
+func addFloat(a, b *float64) *float64 {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	sum := *a + *b
+	return &sum
+}
+
+// maxFloat returns the larger of two values, treating nil as absent.
+func maxFloat(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
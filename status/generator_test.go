@@ -0,0 +1,211 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// newGeneratorPod returns a pod for the named generator, whose run container
+// has terminated with the exit code and termination message.
+func newGeneratorPod(name string, exitCode int32, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				config.RoleLabel:          config.GeneratorRole,
+				config.ComponentNameLabel: name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "ghz"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "ghz",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("SummaryForGeneratorPods", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			Spec: grpcv1.LoadTestSpec{
+				Generators: []grpcv1.Generator{
+					{
+						Name:    optional.StringPtr("ghz-1"),
+						Adapter: grpcv1.GhzAdapter,
+						Run:     []corev1.Container{{Name: "ghz", Image: "ghz"}},
+					},
+					{
+						Name:    optional.StringPtr("ghz-2"),
+						Adapter: grpcv1.GhzAdapter,
+						Run:     []corev1.Container{{Name: "ghz", Image: "ghz"}},
+					},
+				},
+			},
+		}
+	})
+
+	It("returns nil when no generator reported results", func() {
+		pods := []*corev1.Pod{newGeneratorPod("ghz-1", 0, "")}
+
+		summary, err := SummaryForGeneratorPods(test, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(BeNil())
+	})
+
+	It("formats the report of a ghz generator", func() {
+		pods := []*corev1.Pod{
+			newGeneratorPod("ghz-1", 0, `{"rps": 1999.6, "average": 500000, "latencyDistribution": [{"percentage": 50, "latency": 245312}, {"percentage": 99, "latency": 1234567}]}`),
+		}
+
+		summary, err := SummaryForGeneratorPods(test, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(&grpcv1.ResultSummary{
+			QPS:       "2000",
+			Latency50: "245.3µs",
+			Latency99: "1.23ms",
+		}))
+	})
+
+	It("adds up the rate and keeps the highest latencies of all generators", func() {
+		pods := []*corev1.Pod{
+			newGeneratorPod("ghz-1", 0, `{"rps": 1000, "latencyDistribution": [{"percentage": 50, "latency": 2000000}, {"percentage": 99, "latency": 3000000}]}`),
+			newGeneratorPod("ghz-2", 0, `{"rps": 500, "latencyDistribution": [{"percentage": 50, "latency": 1000000}, {"percentage": 99, "latency": 5000000}]}`),
+		}
+
+		summary, err := SummaryForGeneratorPods(test, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary.QPS).To(Equal("1500"))
+		Expect(summary.Latency50).To(Equal("2ms"))
+		Expect(summary.Latency99).To(Equal("5ms"))
+	})
+
+	It("ignores generators without an adapter", func() {
+		test.Spec.Generators[1].Adapter = ""
+		pods := []*corev1.Pod{
+			newGeneratorPod("ghz-1", 0, `{"rps": 1000}`),
+			newGeneratorPod("ghz-2", 0, `not a ghz report`),
+		}
+
+		summary, err := SummaryForGeneratorPods(test, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary.QPS).To(Equal("1000"))
+	})
+
+	It("returns an error when a report is malformed", func() {
+		pods := []*corev1.Pod{newGeneratorPod("ghz-1", 0, `{"rps":`)}
+
+		_, err := SummaryForGeneratorPods(test, pods)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ForLoadTest with generators", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-with-generators",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Generators: []grpcv1.Generator{
+					{
+						Name:    optional.StringPtr("ghz-1"),
+						Adapter: grpcv1.GhzAdapter,
+						Run:     []corev1.Container{{Name: "ghz", Image: "ghz"}},
+					},
+					{
+						Name: optional.StringPtr("ghz-2"),
+						Run:  []corev1.Container{{Name: "ghz", Image: "ghz"}},
+					},
+				},
+				TTLSeconds:     int32(120),
+				TimeoutSeconds: int32(30),
+			},
+		}
+	})
+
+	It("sets succeeded state with a summary when all generators succeeded", func() {
+		pods := []*corev1.Pod{
+			newGeneratorPod("ghz-1", 0, `{"rps": 750}`),
+			newGeneratorPod("ghz-2", 0, ""),
+		}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Succeeded))
+		Expect(status.StopTime).ToNot(BeNil())
+		Expect(status.Summary).ToNot(BeNil())
+		Expect(status.Summary.QPS).To(Equal("750"))
+	})
+
+	It("does not set succeeded state while a generator is running", func() {
+		running := newGeneratorPod("ghz-2", 0, "")
+		running.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{},
+		}
+		pods := []*corev1.Pod{newGeneratorPod("ghz-1", 0, ""), running}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Running))
+	})
+
+	It("sets errored state when a generator failed", func() {
+		pods := []*corev1.Pod{
+			newGeneratorPod("ghz-1", 0, ""),
+			newGeneratorPod("ghz-2", 1, ""),
+		}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.ContainerError))
+	})
+
+	It("waits for the pods of all generators", func() {
+		pods := []*corev1.Pod{newGeneratorPod("ghz-1", 0, "")}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Initializing))
+		Expect(status.Reason).To(Equal(grpcv1.PodsMissing))
+		Expect(status.Message).To(ContainSubstring("1/2"))
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// interopReport mirrors the report that the interop program writes to the
+// termination message of the run container of the driver in interop tests.
+type interopReport struct {
+	InteropCases []grpcv1.InteropCaseResult `json:"interopCases"`
+}
+
+// InteropCasesForDriverPod accepts the driver pod of an interop test and
+// returns the results of the test cases that its run container reported in its
+// termination message. If the run container has not terminated or did not
+// report any results, nil is returned. An error is returned if the report
+// cannot be parsed.
+func InteropCasesForDriverPod(pod *corev1.Pod) ([]grpcv1.InteropCaseResult, error) {
+	var message string
+	for i := range pod.Status.ContainerStatuses {
+		contStat := &pod.Status.ContainerStatuses[i]
+		if contStat.Name != config.RunContainerName || contStat.State.Terminated == nil {
+			continue
+		}
+		message = contStat.State.Terminated.Message
+	}
+	if message == "" {
+		return nil, nil
+	}
+
+	var report interopReport
+	if err := json.Unmarshal([]byte(message), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse interop results from termination message: %v", err)
+	}
+	return report.InteropCases, nil
+}
+
+// interopFailureMessage returns a message that names the failed cases among
+// the results of an interop test, or an empty string if every case passed.
+func interopFailureMessage(results []grpcv1.InteropCaseResult) string {
+	var failed []string
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d interop test cases failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// newInteropDriverPod returns a driver pod, whose run container has terminated
+// with the exit code and termination message.
+func newInteropDriverPod(exitCode int32, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "driver",
+			Labels: map[string]string{
+				config.RoleLabel:          config.DriverRole,
+				config.ComponentNameLabel: "driver",
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: config.RunContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("InteropCasesForDriverPod", func() {
+	It("returns nil when the driver did not report results", func() {
+		cases, err := InteropCasesForDriverPod(newInteropDriverPod(0, ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cases).To(BeNil())
+	})
+
+	It("returns the reported results", func() {
+		pod := newInteropDriverPod(1, `{"interopCases": [{"name": "empty_unary", "passed": true}, {"name": "large_unary", "passed": false, "message": "exit code 1"}]}`)
+
+		cases, err := InteropCasesForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cases).To(Equal([]grpcv1.InteropCaseResult{
+			{Name: "empty_unary", Passed: true},
+			{Name: "large_unary", Passed: false, Message: "exit code 1"},
+		}))
+	})
+
+	It("returns an error when the results are malformed", func() {
+		_, err := InteropCasesForDriverPod(newInteropDriverPod(0, `{"interopCases": [`))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ForLoadTest with interop", func() {
+	var test *grpcv1.LoadTest
+	var serverPod *corev1.Pod
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "interop-test",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver: &grpcv1.Driver{Name: optional.StringPtr("driver")},
+				Servers: []grpcv1.Server{
+					{Name: optional.StringPtr("server-1")},
+				},
+				Interop: &grpcv1.Interop{
+					TestCases: []string{"empty_unary", "large_unary"},
+				},
+				TTLSeconds:     int32(120),
+				TimeoutSeconds: int32(30),
+			},
+		}
+		serverPod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "server-1",
+				Labels: map[string]string{
+					config.RoleLabel:          config.ServerRole,
+					config.ComponentNameLabel: "server-1",
+				},
+			},
+		}
+	})
+
+	It("sets succeeded state with the results when all cases passed", func() {
+		driverPod := newInteropDriverPod(0, `{"interopCases": [{"name": "empty_unary", "passed": true}, {"name": "large_unary", "passed": true}]}`)
+
+		status := ForLoadTest(test, []*corev1.Pod{driverPod, serverPod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Succeeded))
+		Expect(status.InteropCases).To(HaveLen(2))
+		Expect(status.Summary).To(BeNil())
+	})
+
+	It("sets errored state naming the failed cases", func() {
+		driverPod := newInteropDriverPod(1, `{"interopCases": [{"name": "empty_unary", "passed": true}, {"name": "large_unary", "passed": false}]}`)
+
+		status := ForLoadTest(test, []*corev1.Pod{driverPod, serverPod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.InteropCaseFailedError))
+		Expect(status.Message).To(Equal("1 of 2 interop test cases failed: large_unary"))
+	})
+
+	It("sets errored state when a case failed even if the client exited with a zero code", func() {
+		driverPod := newInteropDriverPod(0, `{"interopCases": [{"name": "empty_unary", "passed": false}]}`)
+
+		status := ForLoadTest(test, []*corev1.Pod{driverPod, serverPod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.InteropCaseFailedError))
+	})
+
+	It("reports a crash of the client when it did not report results", func() {
+		driverPod := newInteropDriverPod(1, "")
+
+		status := ForLoadTest(test, []*corev1.Pod{driverPod, serverPod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.DriverCrashedError))
+	})
+
+	It("does not require clients", func() {
+		status := ForLoadTest(test, []*corev1.Pod{serverPod}, nil)
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Initializing))
+		Expect(status.Message).To(ContainSubstring("1/2"))
+	})
+})
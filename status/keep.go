@@ -0,0 +1,45 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// IsKept returns true if a load test has the KeepAnnotation set to "true",
+// which exempts it from deletion when its TTL expires.
+func IsKept(test *grpcv1.LoadTest) bool {
+	return test.Annotations[config.KeepAnnotation] == "true"
+}
+
+// IsFirstFailureOfItsKind accepts an errored load test and all load tests in
+// its namespace. It returns true if none of the other tests is kept and
+// errored with the same reason, meaning that the test is the only example of
+// its failure that would remain on the cluster after its TTL.
+func IsFirstFailureOfItsKind(test *grpcv1.LoadTest, tests []grpcv1.LoadTest) bool {
+	for i := range tests {
+		other := &tests[i]
+		if other.UID == test.UID {
+			continue
+		}
+		if other.Status.State == grpcv1.Errored && other.Status.Reason == test.Status.Reason && IsKept(other) {
+			return false
+		}
+	}
+	return true
+}
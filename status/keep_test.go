@@ -0,0 +1,94 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("IsKept", func() {
+	It("returns true when the keep annotation is true", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{config.KeepAnnotation: "true"},
+			},
+		}
+		Expect(IsKept(test)).To(BeTrue())
+	})
+
+	It("returns false when the keep annotation is false", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{config.KeepAnnotation: "false"},
+			},
+		}
+		Expect(IsKept(test)).To(BeFalse())
+	})
+
+	It("returns false when the keep annotation is missing", func() {
+		Expect(IsKept(&grpcv1.LoadTest{})).To(BeFalse())
+	})
+})
+
+var _ = Describe("IsFirstFailureOfItsKind", func() {
+	newErroredTest := func(name, reason string, kept bool) grpcv1.LoadTest {
+		test := grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				UID:  types.UID(name),
+			},
+			Status: grpcv1.LoadTestStatus{
+				State:  grpcv1.Errored,
+				Reason: reason,
+			},
+		}
+		if kept {
+			test.Annotations = map[string]string{config.KeepAnnotation: "true"}
+		}
+		return test
+	}
+
+	It("returns true when no other test is kept", func() {
+		test := newErroredTest("test", grpcv1.ConfigurationError, false)
+		tests := []grpcv1.LoadTest{test, newErroredTest("other", grpcv1.ConfigurationError, false)}
+		Expect(IsFirstFailureOfItsKind(&test, tests)).To(BeTrue())
+	})
+
+	It("returns false when another test with the same reason is kept", func() {
+		test := newErroredTest("test", grpcv1.ConfigurationError, false)
+		tests := []grpcv1.LoadTest{test, newErroredTest("other", grpcv1.ConfigurationError, true)}
+		Expect(IsFirstFailureOfItsKind(&test, tests)).To(BeFalse())
+	})
+
+	It("returns true when only tests with other reasons are kept", func() {
+		test := newErroredTest("test", grpcv1.ConfigurationError, false)
+		tests := []grpcv1.LoadTest{test, newErroredTest("other", grpcv1.TimeoutErrored, true)}
+		Expect(IsFirstFailureOfItsKind(&test, tests)).To(BeTrue())
+	})
+
+	It("ignores the test itself", func() {
+		test := newErroredTest("test", grpcv1.ConfigurationError, true)
+		Expect(IsFirstFailureOfItsKind(&test, []grpcv1.LoadTest{test})).To(BeTrue())
+	})
+})
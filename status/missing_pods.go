@@ -19,6 +19,7 @@ package status
 import (
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -48,6 +49,10 @@ type LoadTestMissing struct {
 	// indicates the Clients still in need.
 	Clients []grpcv1.Client
 
+	// Generators are a list of components that send load without the
+	// driver. The list indicates the Generators still in need.
+	Generators []grpcv1.Generator
+
 	// NodeCountPyPool is a map which gives the number of nodes required from
 	// each pool to run the test. These counts will not include any pods from
 	// the test that have already been scheduled. The names of the required node
@@ -55,17 +60,18 @@ type LoadTestMissing struct {
 	// named pool.
 	//
 	// If a test does not require any nodes from a pool, the pool name will not
-	// be present as a key in the map. If a driver, client or server has not
-	// been scheduled for a test and does not specify a pool, it will be counted
-	// in one of the default pool keys. See the DefaultClientPool,
-	// DefaultDriverPool and DefaultServerPool constants.
+	// be present as a key in the map. If a driver, client, generator or server
+	// has not been scheduled for a test and does not specify a pool, it will
+	// be counted in one of the default pool keys. Generators are counted in
+	// the default client pool. See the DefaultClientPool, DefaultDriverPool
+	// and DefaultServerPool constants.
 	NodeCountByPool map[string]int
 }
 
-// IsEmpty returns true if there are no missing driver, servers or clients on a
-// LoadTestMissing struct. Otherwise, it returns false.
+// IsEmpty returns true if there are no missing driver, servers, clients or
+// generators on a LoadTestMissing struct. Otherwise, it returns false.
 func (ltm *LoadTestMissing) IsEmpty() bool {
-	return ltm.Driver == nil && len(ltm.Servers) == 0 && len(ltm.Clients) == 0
+	return ltm.Driver == nil && len(ltm.Servers) == 0 && len(ltm.Clients) == 0 && len(ltm.Generators) == 0
 }
 
 // CheckMissingPods attempts to check if any required component is missing from
@@ -74,8 +80,9 @@ func (ltm *LoadTestMissing) IsEmpty() bool {
 // components required from the current load test with their roles.
 func CheckMissingPods(test *grpcv1.LoadTest, ownedPods []*corev1.Pod) *LoadTestMissing {
 	currentMissing := &LoadTestMissing{
-		Servers: []grpcv1.Server{},
-		Clients: []grpcv1.Client{},
+		Servers:    []grpcv1.Server{},
+		Clients:    []grpcv1.Client{},
+		Generators: []grpcv1.Generator{},
 		NodeCountByPool: map[string]int{
 			DefaultClientPool: 0,
 			DefaultDriverPool: 0,
@@ -85,14 +92,19 @@ func CheckMissingPods(test *grpcv1.LoadTest, ownedPods []*corev1.Pod) *LoadTestM
 
 	requiredClientMap := make(map[string]*grpcv1.Client)
 	requiredServerMap := make(map[string]*grpcv1.Server)
+	requiredGeneratorMap := make(map[string]*grpcv1.Generator)
 	foundDriver := false
 
-	for i := 0; i < len(test.Spec.Clients); i++ {
-		requiredClientMap[*test.Spec.Clients[i].Name] = &test.Spec.Clients[i]
+	clients := kubehelpers.ExpandClients(test.Spec.Clients)
+	for i := 0; i < len(clients); i++ {
+		requiredClientMap[*clients[i].Name] = &clients[i]
 	}
 	for i := 0; i < len(test.Spec.Servers); i++ {
 		requiredServerMap[*test.Spec.Servers[i].Name] = &test.Spec.Servers[i]
 	}
+	for i := 0; i < len(test.Spec.Generators); i++ {
+		requiredGeneratorMap[*test.Spec.Generators[i].Name] = &test.Spec.Generators[i]
+	}
 
 	if ownedPods != nil {
 
@@ -106,7 +118,7 @@ func CheckMissingPods(test *grpcv1.LoadTest, ownedPods []*corev1.Pod) *LoadTestM
 			componentNameLabel := eachPod.Labels[config.ComponentNameLabel]
 
 			if roleLabel == config.DriverRole {
-				if *test.Spec.Driver.Name == componentNameLabel {
+				if test.Spec.Driver != nil && *test.Spec.Driver.Name == componentNameLabel {
 					foundDriver = true
 				}
 			} else if roleLabel == config.ClientRole {
@@ -117,6 +129,10 @@ func CheckMissingPods(test *grpcv1.LoadTest, ownedPods []*corev1.Pod) *LoadTestM
 				if _, ok := requiredServerMap[componentNameLabel]; ok {
 					delete(requiredServerMap, componentNameLabel)
 				}
+			} else if roleLabel == config.GeneratorRole {
+				if _, ok := requiredGeneratorMap[componentNameLabel]; ok {
+					delete(requiredGeneratorMap, componentNameLabel)
+				}
 			}
 		}
 	}
@@ -146,7 +162,16 @@ func CheckMissingPods(test *grpcv1.LoadTest, ownedPods []*corev1.Pod) *LoadTestM
 		}
 	}
 
-	if !foundDriver {
+	for _, eachMissingGenerator := range requiredGeneratorMap {
+		currentMissing.Generators = append(currentMissing.Generators, *eachMissingGenerator)
+		if eachMissingGenerator.Pool == nil {
+			currentMissing.NodeCountByPool[DefaultClientPool]++
+		} else {
+			incNodeCount(*eachMissingGenerator.Pool)
+		}
+	}
+
+	if !foundDriver && test.Spec.Driver != nil {
 		currentMissing.Driver = test.Spec.Driver
 		if test.Spec.Driver.Pool == nil {
 			currentMissing.NodeCountByPool[DefaultDriverPool]++
@@ -18,6 +18,7 @@ package status
 import (
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -156,4 +157,78 @@ var _ = Describe("CheckMissingPods", func() {
 			))
 		})
 	})
+
+	Context("a client with replicas is partially running", func() {
+		var clientName string
+
+		BeforeEach(func() {
+			clientName = *test.Spec.Clients[0].Name
+			allRunningPods = populatePodListWithCurrentLoadTestPod(test)
+
+			replicas := int32(3)
+			test.Spec.Clients[0].Replicas = &replicas
+			allRunningPods = append(allRunningPods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "random-name",
+					Labels: map[string]string{
+						config.RoleLabel:          config.ClientRole,
+						config.ComponentNameLabel: clientName + "-1",
+					},
+				},
+			})
+		})
+
+		It("returns the replicas that are not running", func() {
+			actualReturn = CheckMissingPods(test, allRunningPods)
+
+			var names []string
+			for _, client := range actualReturn.Clients {
+				names = append(names, *client.Name)
+			}
+			Expect(names).To(ConsistOf(clientName+"-0", clientName+"-2"))
+			Expect(actualReturn.Servers).To(BeEmpty())
+			Expect(actualReturn.Driver).To(BeNil())
+		})
+	})
+
+	Context("a test has generators and no driver", func() {
+		BeforeEach(func() {
+			test.Spec.Driver = nil
+			test.Spec.Servers = nil
+			test.Spec.Clients = nil
+			test.Spec.Generators = []grpcv1.Generator{
+				{Name: optional.StringPtr("ghz-1")},
+				{Name: optional.StringPtr("ghz-2"), Pool: optional.StringPtr("generators")},
+			}
+			allRunningPods = append(allRunningPods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "random-name",
+					Labels: map[string]string{
+						config.RoleLabel:          config.GeneratorRole,
+						config.ComponentNameLabel: "ghz-1",
+					},
+				},
+			})
+		})
+
+		It("returns the generators that are not running", func() {
+			actualReturn = CheckMissingPods(test, allRunningPods)
+
+			Expect(actualReturn.Generators).To(HaveLen(1))
+			Expect(*actualReturn.Generators[0].Name).To(Equal("ghz-2"))
+			Expect(actualReturn.Driver).To(BeNil())
+			Expect(actualReturn.IsEmpty()).To(BeFalse())
+		})
+
+		It("counts the missing generators in their pools", func() {
+			actualReturn = CheckMissingPods(test, allRunningPods)
+
+			Expect(actualReturn.NodeCountByPool).To(Equal(map[string]int{
+				DefaultClientPool: 0,
+				DefaultDriverPool: 0,
+				DefaultServerPool: 0,
+				"generators":      1,
+			}))
+		})
+	})
 })
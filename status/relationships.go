@@ -17,20 +17,79 @@ limitations under the License.
 package status
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
 )
 
 // PodsForLoadTest returns a slice of pointers to pods which belong to a
 // specific load test. It accepts the load test to match and a list of all pods
 // to consider. If none of the pods match, an empty slice is returned.
+//
+// For soak tests, only the pods created for the current soak iteration are
+// returned. For tests that were rerun, only the pods created for the current
+// run are returned.
 func PodsForLoadTest(loadtest *grpcv1.LoadTest, allPods []corev1.Pod) []*corev1.Pod {
 	if loadtest == nil {
 		return nil
 	}
 	var pods []*corev1.Pod
 
+	currentIteration := strconv.Itoa(int(loadtest.Status.SoakIteration))
+	currentRun := strconv.Itoa(int(loadtest.Status.Run))
+	for _, pod := range ownedPods(loadtest, allPods) {
+		if iteration, ok := pod.Labels[config.SoakIterationLabel]; ok && iteration != currentIteration {
+			continue
+		}
+		// Pods created before the test was first rerun have no run label.
+		run, ok := pod.Labels[config.RunLabel]
+		if !ok {
+			run = "0"
+		}
+		if run != currentRun {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods
+}
+
+// ExpiredSoakPods returns a slice of pointers to pods which belong to a
+// specific soak test, but were created for an iteration that is older than the
+// number of iterations to retain. Deleting these pods rotates the logs of the
+// test, while the logs of recent iterations remain available.
+func ExpiredSoakPods(loadtest *grpcv1.LoadTest, allPods []corev1.Pod, retainedIterations int32) []*corev1.Pod {
+	if loadtest == nil {
+		return nil
+	}
+	var pods []*corev1.Pod
+
+	for _, pod := range ownedPods(loadtest, allPods) {
+		label, ok := pod.Labels[config.SoakIterationLabel]
+		if !ok {
+			continue
+		}
+		iteration, err := strconv.Atoi(label)
+		if err != nil {
+			continue
+		}
+		if int32(iteration) < loadtest.Status.SoakIteration-retainedIterations {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods
+}
+
+// ownedPods returns a slice of pointers to pods which have an owner reference
+// to the load test.
+func ownedPods(loadtest *grpcv1.LoadTest, allPods []corev1.Pod) []*corev1.Pod {
+	var pods []*corev1.Pod
+
 	for i := range allPods {
 		pod := &allPods[i]
 
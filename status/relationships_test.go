@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -95,4 +96,84 @@ var _ = Describe("PodsForLoadTest", func() {
 		pods := PodsForLoadTest(test, allPods)
 		Expect(pods).To(ConsistOf(&allPods[0], &allPods[2]))
 	})
+
+	It("includes only pods from the current run of a rerun test", func() {
+		test := new(grpcv1.LoadTest)
+		test.SetUID(types.UID("rerun-test-uid"))
+		test.Status.Run = 1
+
+		runPod := func(name string, labels map[string]string) corev1.Pod {
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: labels,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							UID: types.UID("rerun-test-uid"),
+						},
+					},
+				},
+			}
+		}
+		allPods := []corev1.Pod{
+			runPod("first-run-pod", nil),
+			runPod("second-run-pod", map[string]string{config.RunLabel: "1"}),
+		}
+
+		Expect(PodsForLoadTest(test, allPods)).To(ConsistOf(&allPods[1]))
+
+		test.Status.Run = 0
+		Expect(PodsForLoadTest(test, allPods)).To(ConsistOf(&allPods[0]))
+	})
+})
+
+var _ = Describe("ExpiredSoakPods", func() {
+	var test *grpcv1.LoadTest
+	var allPods []corev1.Pod
+
+	soakPod := func(name string, iteration string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					config.SoakIterationLabel: iteration,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						UID: types.UID("soak-test-uid"),
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		test = new(grpcv1.LoadTest)
+		test.SetUID(types.UID("soak-test-uid"))
+		test.Status.SoakIteration = 3
+
+		allPods = []corev1.Pod{
+			soakPod("pod-0", "0"),
+			soakPod("pod-1", "1"),
+			soakPod("pod-2", "2"),
+			soakPod("pod-3", "3"),
+		}
+	})
+
+	It("returns pods from iterations older than the retained iterations", func() {
+		pods := ExpiredSoakPods(test, allPods, 1)
+		Expect(pods).To(ConsistOf(&allPods[0], &allPods[1]))
+	})
+
+	It("ignores pods owned by other tests", func() {
+		allPods[0].OwnerReferences[0].UID = types.UID("other-test-uid")
+
+		pods := ExpiredSoakPods(test, allPods, 1)
+		Expect(pods).To(ConsistOf(&allPods[1]))
+	})
+
+	It("returns only pods from the current iteration from PodsForLoadTest", func() {
+		pods := PodsForLoadTest(test, allPods)
+		Expect(pods).To(ConsistOf(&allPods[3]))
+	})
 })
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// MaxRuns is the number of previous runs recorded in the status of a test
+// that was rerun. Older runs are dropped, since a test may be rerun any number
+// of times and the status of a test must fit in a single object.
+const MaxRuns = 20
+
+// RerunRequested returns true if a terminated test has been annotated with a
+// rerun request that has not started a run yet.
+func RerunRequested(test *grpcv1.LoadTest) bool {
+	if !test.Status.State.IsTerminated() {
+		return false
+	}
+	request := test.Annotations[config.RerunAnnotation]
+	return request != "" && request != test.Status.RerunRequest
+}
+
+// StartRerun records the outcome of the current run of a terminated test in
+// its status, keeping at most MaxRuns, and resets the status for the next run. The start time is
+// cleared, so the timeout and the time to live of the test count from the
+// start of the next run. The caller is expected to update the status, and new
+// pods are then created for the next run.
+func StartRerun(test *grpcv1.LoadTest) {
+	previous := test.Status
+	run := previous.Run + 1
+
+	runs := make([]grpcv1.LoadTestRun, 0, len(previous.Runs)+1)
+	runs = append(runs, previous.Runs...)
+	runs = append(runs, grpcv1.LoadTestRun{
+		Run:        previous.Run,
+		State:      previous.State,
+		Reason:     previous.Reason,
+		Message:    previous.Message,
+		StartTime:  previous.StartTime,
+		StopTime:   previous.StopTime,
+		Summary:    previous.Summary,
+		ResultsURI: previous.ResultsURI,
+	})
+	if excess := len(runs) - MaxRuns; excess > 0 {
+		runs = runs[excess:]
+	}
+
+	test.Status = grpcv1.LoadTestStatus{
+		State:        grpcv1.Initializing,
+		Reason:       grpcv1.PodsMissing,
+		Message:      fmt.Sprintf("starting run %d", run),
+		Run:          run,
+		RerunRequest: test.Annotations[config.RerunAnnotation],
+		Runs:         runs,
+	}
+}
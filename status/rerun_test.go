@@ -0,0 +1,108 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("Rerun", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = new(grpcv1.LoadTest)
+		test.Annotations = map[string]string{config.RerunAnnotation: "1700000000"}
+		test.Status.State = grpcv1.Errored
+		test.Status.Reason = grpcv1.TimeoutErrored
+		test.Status.Message = "timeout exceeded"
+		test.Status.StartTime = optional.CurrentTimePtr()
+		test.Status.StopTime = optional.CurrentTimePtr()
+	})
+
+	It("is requested when a terminated test has a new rerun annotation", func() {
+		Expect(RerunRequested(test)).To(BeTrue())
+	})
+
+	It("is not requested without a rerun annotation", func() {
+		delete(test.Annotations, config.RerunAnnotation)
+		Expect(RerunRequested(test)).To(BeFalse())
+	})
+
+	It("is not requested for a test that has not terminated", func() {
+		test.Status.State = grpcv1.Running
+		Expect(RerunRequested(test)).To(BeFalse())
+	})
+
+	It("is not requested again once the run has started", func() {
+		StartRerun(test)
+		test.Status.State = grpcv1.Succeeded
+		Expect(RerunRequested(test)).To(BeFalse())
+
+		test.Annotations[config.RerunAnnotation] = "1700000100"
+		Expect(RerunRequested(test)).To(BeTrue())
+	})
+
+	It("records the previous run and resets the status", func() {
+		startTime := test.Status.StartTime
+		StartRerun(test)
+
+		Expect(test.Status.Run).To(Equal(int32(1)))
+		Expect(test.Status.RerunRequest).To(Equal("1700000000"))
+		Expect(test.Status.State).To(Equal(grpcv1.Initializing))
+		Expect(test.Status.StartTime).To(BeNil())
+		Expect(test.Status.StopTime).To(BeNil())
+		Expect(test.Status.Runs).To(HaveLen(1))
+		Expect(test.Status.Runs[0].Run).To(Equal(int32(0)))
+		Expect(test.Status.Runs[0].State).To(Equal(grpcv1.Errored))
+		Expect(test.Status.Runs[0].Reason).To(Equal(grpcv1.TimeoutErrored))
+		Expect(test.Status.Runs[0].StartTime).To(Equal(startTime))
+
+		test.Status.State = grpcv1.Succeeded
+		test.Annotations[config.RerunAnnotation] = "1700000100"
+		StartRerun(test)
+		Expect(test.Status.Run).To(Equal(int32(2)))
+		Expect(test.Status.Runs).To(HaveLen(2))
+		Expect(test.Status.Runs[1].Run).To(Equal(int32(1)))
+		Expect(test.Status.Runs[1].State).To(Equal(grpcv1.Succeeded))
+	})
+
+	It("keeps only the most recent runs", func() {
+		for i := 0; i < MaxRuns+5; i++ {
+			StartRerun(test)
+			test.Status.State = grpcv1.Succeeded
+		}
+
+		Expect(test.Status.Run).To(Equal(int32(MaxRuns + 5)))
+		Expect(test.Status.Runs).To(HaveLen(MaxRuns))
+		Expect(test.Status.Runs[0].Run).To(Equal(int32(5)))
+		Expect(test.Status.Runs[MaxRuns-1].Run).To(Equal(int32(MaxRuns + 4)))
+	})
+
+	It("does not modify the runs of the previous status", func() {
+		test.Status.Runs = make([]grpcv1.LoadTestRun, 1, 2)
+		previous := test.Status
+		StartRerun(test)
+
+		Expect(test.Status.Runs).To(HaveLen(2))
+		Expect(previous.Runs[:2][1]).To(Equal(grpcv1.LoadTestRun{}))
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// oomKilledReason is the reason the kubelet reports when a container is
+// terminated for exceeding its memory limit.
+const oomKilledReason = "OOMKilled"
+
+// evictedReason is the reason the kubelet sets on a pod that was evicted,
+// such as when a container exceeds its ephemeral storage limit.
+const evictedReason = "Evicted"
+
+// CheckResourceExceeded accepts a pod and returns true with a reason and
+// message if any of its containers was killed for exceeding its memory
+// limit, or if the pod was evicted. Otherwise, it returns false and empty
+// strings. This allows these failures to be reported as a resource budget
+// that was exceeded, rather than a generic container failure.
+func CheckResourceExceeded(pod *corev1.Pod) (exceeded bool, reason string, message string) {
+	if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == evictedReason {
+		message := fmt.Sprintf("pod %q was evicted", pod.Name)
+		if pod.Status.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, pod.Status.Message)
+		}
+		return true, grpcv1.ResourceExceededError, message
+	}
+
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		contStat := &statuses[i]
+		terminated := contStat.State.Terminated
+		if terminated == nil || terminated.Reason != oomKilledReason {
+			terminated = contStat.LastTerminationState.Terminated
+		}
+		if terminated != nil && terminated.Reason == oomKilledReason {
+			return true, grpcv1.ResourceExceededError, fmt.Sprintf("container %q was killed for exceeding its memory limit", contStat.Name)
+		}
+	}
+
+	return false, "", ""
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("CheckResourceExceeded", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "client-1",
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "main",
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("does not report an error for a running pod", func() {
+		exceeded, reason, message := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeFalse())
+		Expect(reason).To(BeEmpty())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("does not report an error for a container that failed for other reasons", func() {
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+		}
+		exceeded, _, _ := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeFalse())
+	})
+
+	It("reports an error for a container that was killed for exceeding its memory", func() {
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+		}
+		exceeded, reason, message := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ResourceExceededError))
+		Expect(message).To(ContainSubstring(`"main"`))
+	})
+
+	It("reports an error for a restarted container that was previously killed for exceeding its memory", func() {
+		pod.Status.ContainerStatuses[0].RestartCount = 1
+		pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+		}
+		exceeded, reason, _ := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ResourceExceededError))
+	})
+
+	It("checks init containers", func() {
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: "build",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+				},
+			},
+		}
+		exceeded, _, message := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeTrue())
+		Expect(message).To(ContainSubstring(`"build"`))
+	})
+
+	It("reports an error for an evicted pod", func() {
+		pod.Status.Phase = corev1.PodFailed
+		pod.Status.Reason = "Evicted"
+		pod.Status.Message = "Pod ephemeral local storage usage exceeds the total limit of containers 1Gi."
+		exceeded, reason, message := CheckResourceExceeded(pod)
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ResourceExceededError))
+		Expect(message).To(ContainSubstring(`pod "client-1" was evicted`))
+		Expect(message).To(ContainSubstring("ephemeral local storage"))
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// podRunningTime returns the time when the first container of a pod started
+// running, and false if no container of the pod has started.
+func podRunningTime(pod *corev1.Pod) (time.Time, bool) {
+	var started time.Time
+	for _, cstat := range pod.Status.ContainerStatuses {
+		var t time.Time
+		switch {
+		case cstat.State.Running != nil:
+			t = cstat.State.Running.StartedAt.Time
+		case cstat.State.Terminated != nil:
+			t = cstat.State.Terminated.StartedAt.Time
+		default:
+			continue
+		}
+		if started.IsZero() || t.Before(started) {
+			started = t
+		}
+	}
+	return started, !started.IsZero()
+}
+
+// schedulingStart returns the time from which the scheduling latency of the
+// current run of a load test is measured. This is the creation of the test
+// for its first run, and the start of the run when the test is rerun.
+func schedulingStart(test *grpcv1.LoadTest) time.Time {
+	if test.Status.Run > 0 && test.Status.StartTime != nil {
+		return test.Status.StartTime.Time
+	}
+	return test.CreationTimestamp.Time
+}
+
+// SchedulingLatencies returns the scheduling latency of each pool used by a
+// load test, which is the time from the creation of the test until all of its
+// pods in the pool were running. The pool of each pod is read from its pool
+// label. False is returned until all pods required by the test have been
+// created and are running.
+func SchedulingLatencies(test *grpcv1.LoadTest, pods []*corev1.Pod) (map[string]time.Duration, bool) {
+	if len(pods) < requiredPodCount(test) {
+		return nil, false
+	}
+
+	start := schedulingStart(test)
+	latencies := make(map[string]time.Duration)
+	for _, pod := range pods {
+		running, ok := podRunningTime(pod)
+		if !ok {
+			return nil, false
+		}
+		pool := pod.Labels[config.PoolLabel]
+		latency := running.Sub(start)
+		if latency < 0 {
+			latency = 0
+		}
+		if current, ok := latencies[pool]; !ok || latency > current {
+			latencies[pool] = latency
+		}
+	}
+	return latencies, true
+}
+
+// RecordScheduling sets the ScheduledTime in the status of a load test once
+// all of its pods are running, and returns the scheduling latency of each
+// pool. When the latency of a pool exceeds the SLO, the
+// SchedulingSLOExceeded condition is also added to the status. The SLO is
+// ignored when it is zero.
+//
+// Nil is returned, and the status is left unchanged, if the ScheduledTime is
+// already set or the pods are not all running yet. This ensures the latencies
+// of each run of a test are returned once.
+func RecordScheduling(test *grpcv1.LoadTest, pods []*corev1.Pod, slo time.Duration) map[string]time.Duration {
+	if test.Status.ScheduledTime != nil {
+		return nil
+	}
+	latencies, ok := SchedulingLatencies(test, pods)
+	if !ok {
+		return nil
+	}
+
+	var latest time.Duration
+	var slowPools []string
+	for _, pool := range sortedPools(latencies) {
+		latency := latencies[pool]
+		if latency > latest {
+			latest = latency
+		}
+		if slo > 0 && latency > slo {
+			slowPools = append(slowPools, fmt.Sprintf("%s (%v)", pool, latency.Round(time.Second)))
+		}
+	}
+	scheduledTime := metav1.NewTime(schedulingStart(test).Add(latest))
+	test.Status.ScheduledTime = &scheduledTime
+
+	if len(slowPools) > 0 {
+		apimeta.SetStatusCondition(&test.Status.Conditions, metav1.Condition{
+			Type:               grpcv1.SchedulingSLOExceededCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: test.Generation,
+			Reason:             grpcv1.SlowScheduling,
+			Message:            fmt.Sprintf("pods took longer than %v to be running in pools: %s", slo, strings.Join(slowPools, ", ")),
+		})
+	}
+	return latencies
+}
+
+// sortedPools returns the names of the pools in a map of latencies in
+// alphabetical order.
+func sortedPools(latencies map[string]time.Duration) []string {
+	set := make(map[string]bool, len(latencies))
+	for pool := range latencies {
+		set[pool] = true
+	}
+	return sortedKeys(set)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("RecordScheduling", func() {
+	var created time.Time
+	var test *grpcv1.LoadTest
+	var pods []*corev1.Pod
+
+	runningPod := func(pool string, after time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{config.PoolLabel: pool},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{
+								StartedAt: metav1.NewTime(created.Add(after)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		created = time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver:  &grpcv1.Driver{},
+				Servers: []grpcv1.Server{{}},
+				Clients: []grpcv1.Client{{}},
+			},
+		}
+		pods = []*corev1.Pod{
+			runningPod("drivers", 30*time.Second),
+			runningPod("workers", time.Minute),
+			runningPod("workers", 3*time.Minute),
+		}
+	})
+
+	It("returns the latency of each pool once all pods are running", func() {
+		latencies := RecordScheduling(test, pods, 0)
+		Expect(latencies).To(Equal(map[string]time.Duration{
+			"drivers": 30 * time.Second,
+			"workers": 3 * time.Minute,
+		}))
+		Expect(test.Status.ScheduledTime.Time).To(Equal(created.Add(3 * time.Minute)))
+		Expect(test.Status.Conditions).To(BeEmpty())
+	})
+
+	It("returns nil while pods are missing", func() {
+		Expect(RecordScheduling(test, pods[:2], 0)).To(BeNil())
+		Expect(test.Status.ScheduledTime).To(BeNil())
+	})
+
+	It("returns nil while a pod is not running", func() {
+		pods[1].Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+		}
+		Expect(RecordScheduling(test, pods, 0)).To(BeNil())
+		Expect(test.Status.ScheduledTime).To(BeNil())
+	})
+
+	It("uses the start time of terminated containers", func() {
+		pods[0].Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				StartedAt: metav1.NewTime(created.Add(45 * time.Second)),
+			},
+		}
+		Expect(RecordScheduling(test, pods, 0)).To(HaveKeyWithValue("drivers", 45*time.Second))
+	})
+
+	It("returns nil once the scheduled time is set", func() {
+		Expect(RecordScheduling(test, pods, 0)).ToNot(BeNil())
+		Expect(RecordScheduling(test, pods, 0)).To(BeNil())
+	})
+
+	It("measures reruns from their start", func() {
+		test.Status.Run = 1
+		test.Status.StartTime = &metav1.Time{Time: created.Add(time.Minute)}
+
+		Expect(RecordScheduling(test, pods, 0)).To(HaveKeyWithValue("workers", 2*time.Minute))
+	})
+
+	It("adds a condition when a pool exceeds the SLO", func() {
+		RecordScheduling(test, pods, 2*time.Minute)
+
+		condition := apimeta.FindStatusCondition(test.Status.Conditions, grpcv1.SchedulingSLOExceededCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(grpcv1.SlowScheduling))
+		Expect(condition.Message).To(ContainSubstring("workers (3m0s)"))
+		Expect(condition.Message).ToNot(ContainSubstring("drivers"))
+	})
+
+	It("does not add a condition when all pools meet the SLO", func() {
+		RecordScheduling(test, pods, 5*time.Minute)
+		Expect(test.Status.Conditions).To(BeEmpty())
+	})
+})
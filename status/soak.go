@@ -0,0 +1,83 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// MaxCheckpoints is the number of checkpoints kept in the status of a soak
+// test. Older checkpoints are dropped, since soak tests may run for thousands
+// of iterations and the status of a test must fit in a single object.
+const MaxCheckpoints = 100
+
+// SoakDuration returns how long a soak test should keep re-running its
+// scenario. It returns zero for tests that are not soak tests.
+func SoakDuration(test *grpcv1.LoadTest) time.Duration {
+	if test.Spec.SoakHours == nil {
+		return 0
+	}
+	return time.Duration(*test.Spec.SoakHours) * time.Hour
+}
+
+// ContinueSoak accepts a soak test after its status has been updated with
+// ForLoadTest. When the current iteration has terminated, it records a
+// checkpoint in the status, keeping at most MaxCheckpoints. If the iteration
+// succeeded and the soak duration has not elapsed since the test started, it
+// also advances the status to the next iteration and returns true. The caller
+// is expected to create new pods for the next iteration.
+//
+// Tests that are not soak tests, or have not terminated, are not modified.
+func ContinueSoak(test *grpcv1.LoadTest, now time.Time) bool {
+	soakDuration := SoakDuration(test)
+	if soakDuration == 0 || !test.Status.State.IsTerminated() {
+		return false
+	}
+
+	iteration := test.Status.SoakIteration
+	checkpoints := test.Status.Checkpoints
+	if len(checkpoints) == 0 || checkpoints[len(checkpoints)-1].Iteration != iteration {
+		test.Status.Checkpoints = append(checkpoints, grpcv1.SoakCheckpoint{
+			Iteration: iteration,
+			State:     test.Status.State,
+			Message:   test.Status.Message,
+			Time:      metav1.NewTime(now),
+		})
+		if excess := len(test.Status.Checkpoints) - MaxCheckpoints; excess > 0 {
+			test.Status.Checkpoints = append([]grpcv1.SoakCheckpoint(nil), test.Status.Checkpoints[excess:]...)
+		}
+	}
+
+	if test.Status.State != grpcv1.Succeeded {
+		return false
+	}
+	if test.Status.StartTime != nil && now.Sub(test.Status.StartTime.Time) >= soakDuration {
+		return false
+	}
+
+	test.Status.SoakIteration = iteration + 1
+	test.Status.State = grpcv1.Initializing
+	test.Status.Reason = grpcv1.PodsMissing
+	test.Status.Message = fmt.Sprintf("starting soak iteration %d", iteration+1)
+	test.Status.StopTime = nil
+	return true
+}
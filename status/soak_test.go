@@ -0,0 +1,104 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ContinueSoak", func() {
+	var test *grpcv1.LoadTest
+	var startTime time.Time
+
+	BeforeEach(func() {
+		startTime = time.Now().Add(-time.Hour)
+
+		test = new(grpcv1.LoadTest)
+		test.Spec.SoakHours = optional.Int32Ptr(2)
+		test.Status.StartTime = optional.TimePtr(metav1.NewTime(startTime))
+		test.Status.State = grpcv1.Succeeded
+		test.Status.StopTime = optional.CurrentTimePtr()
+	})
+
+	It("does not modify tests that are not soak tests", func() {
+		test.Spec.SoakHours = nil
+
+		Expect(ContinueSoak(test, time.Now())).To(BeFalse())
+		Expect(test.Status.State).To(Equal(grpcv1.Succeeded))
+		Expect(test.Status.Checkpoints).To(BeEmpty())
+	})
+
+	It("does not modify tests that have not terminated", func() {
+		test.Status.State = grpcv1.Running
+
+		Expect(ContinueSoak(test, time.Now())).To(BeFalse())
+		Expect(test.Status.Checkpoints).To(BeEmpty())
+	})
+
+	It("advances to the next iteration when the soak has not finished", func() {
+		Expect(ContinueSoak(test, time.Now())).To(BeTrue())
+		Expect(test.Status.SoakIteration).To(Equal(int32(1)))
+		Expect(test.Status.State).To(Equal(grpcv1.Initializing))
+		Expect(test.Status.StopTime).To(BeNil())
+		Expect(test.Status.Checkpoints).To(HaveLen(1))
+		Expect(test.Status.Checkpoints[0].Iteration).To(Equal(int32(0)))
+		Expect(test.Status.Checkpoints[0].State).To(Equal(grpcv1.Succeeded))
+	})
+
+	It("stops when the soak duration has elapsed", func() {
+		Expect(ContinueSoak(test, startTime.Add(3*time.Hour))).To(BeFalse())
+		Expect(test.Status.State).To(Equal(grpcv1.Succeeded))
+		Expect(test.Status.Checkpoints).To(HaveLen(1))
+	})
+
+	It("stops when an iteration errors", func() {
+		test.Status.State = grpcv1.Errored
+		test.Status.Message = "container \"main\" terminated with exit code 1"
+
+		Expect(ContinueSoak(test, time.Now())).To(BeFalse())
+		Expect(test.Status.State).To(Equal(grpcv1.Errored))
+		Expect(test.Status.Checkpoints).To(HaveLen(1))
+		Expect(test.Status.Checkpoints[0].Message).To(Equal(test.Status.Message))
+	})
+
+	It("records only one checkpoint per iteration", func() {
+		test.Status.State = grpcv1.Errored
+
+		ContinueSoak(test, time.Now())
+		ContinueSoak(test, time.Now())
+		Expect(test.Status.Checkpoints).To(HaveLen(1))
+	})
+
+	It("keeps only the most recent checkpoints", func() {
+		for i := 0; i < MaxCheckpoints+5; i++ {
+			test.Status.State = grpcv1.Succeeded
+			Expect(ContinueSoak(test, time.Now())).To(BeTrue())
+		}
+
+		Expect(test.Status.Checkpoints).To(HaveLen(MaxCheckpoints))
+		Expect(test.Status.Checkpoints[0].Iteration).To(Equal(int32(5)))
+		Expect(test.Status.Checkpoints[MaxCheckpoints-1].Iteration).To(Equal(int32(MaxCheckpoints + 4)))
+	})
+})
@@ -27,6 +27,7 @@ import (
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
 	"github.com/grpc/test-infra/optional"
 )
 
@@ -82,8 +83,16 @@ func StateForPodStatus(status *corev1.PodStatus) (state State, reason string, me
 		initContStat := &status.InitContainerStatuses[i]
 		contState, exitCode := StateForContainerStatus(initContStat)
 
+		if contState == Errored && exitCode == nil {
+			message := fmt.Sprintf("init container %q is crashing after %d restarts", initContStat.Name, initContStat.RestartCount)
+			return Errored, grpcv1.CrashLoopError, message
+		}
+
 		if contState == Errored {
 			message := fmt.Sprintf("init container %q terminated with exit code %d", initContStat.Name, *exitCode)
+			if initContStat.Name == config.BuildInitContainerName {
+				return Errored, grpcv1.BuildFailedError, message
+			}
 			return Errored, grpcv1.InitContainerError, message
 		}
 	}
@@ -92,6 +101,11 @@ func StateForPodStatus(status *corev1.PodStatus) (state State, reason string, me
 		contStat := &status.ContainerStatuses[i]
 		contState, exitCode := StateForContainerStatus(contStat)
 
+		if contState == Errored && exitCode == nil {
+			message := fmt.Sprintf("container %q is crashing after %d restarts", contStat.Name, contStat.RestartCount)
+			return Errored, grpcv1.CrashLoopError, message
+		}
+
 		if contState == Errored {
 			message := fmt.Sprintf("container %q terminated with exit code %d", contStat.Name, *exitCode)
 			return Errored, grpcv1.ContainerError, message
@@ -109,8 +123,24 @@ func StateForPodStatus(status *corev1.PodStatus) (state State, reason string, me
 // pods it owns. This sets the state, reason and message for the load test. In
 // addition, it attempts to set the start and stop times based on what has been
 // previously encountered.
-func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod) grpcv1.LoadTestStatus {
-	status := grpcv1.LoadTestStatus{}
+//
+// If limits are provided, the load test is also marked as errored when any of
+// its containers cannot pull an image or restart too often. A nil value
+// disables these checks, leaving the timeout to catch these failures.
+//
+// Regardless of limits, the load test is marked as errored with the
+// ResourceExceeded reason when a container is killed for exceeding its
+// memory limit or a pod is evicted.
+func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod, limits *WaitingLimits) grpcv1.LoadTestStatus {
+	status := grpcv1.LoadTestStatus{
+		SoakIteration: test.Status.SoakIteration,
+		Checkpoints:   test.Status.Checkpoints,
+		Run:           test.Status.Run,
+		RerunRequest:  test.Status.RerunRequest,
+		Runs:          test.Status.Runs,
+		ScheduledTime: test.Status.ScheduledTime,
+		Conditions:    test.Status.Conditions,
+	}
 
 	if test.Status.StartTime == nil {
 		status.StartTime = optional.CurrentTimePtr()
@@ -130,6 +160,7 @@ func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod) grpcv1.LoadTestStatu
 		return status
 	}
 
+	succeededGenerators := 0
 	for _, pod := range pods {
 		role, ok := pod.Labels[config.RoleLabel]
 		if !ok {
@@ -137,22 +168,75 @@ func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod) grpcv1.LoadTestStatu
 		}
 
 		podState, reason, message := StateForPodStatus(&pod.Status)
+		if exceeded, resourceReason, resourceMessage := CheckResourceExceeded(pod); exceeded {
+			podState, reason, message = Errored, resourceReason, resourceMessage
+		} else if podState == Pending {
+			if exceeded, limitReason, limitMessage := CheckWaitingLimits(pod, limits, time.Now()); exceeded {
+				podState, reason, message = Errored, limitReason, limitMessage
+			}
+		}
 
 		if podState != Succeeded && podState != Errored {
 			continue
 		}
 
+		// The interop client of an interop test reports the result of each
+		// case, so a failed case is not mistaken for a crash of the driver.
+		if role == config.DriverRole && test.Spec.Interop != nil {
+			// The results are informational, so a malformed termination
+			// message should not change the outcome of the test.
+			if cases, err := InteropCasesForDriverPod(pod); err == nil {
+				status.InteropCases = cases
+				if failureMessage := interopFailureMessage(cases); failureMessage != "" {
+					podState, reason, message = Errored, grpcv1.InteropCaseFailedError, failureMessage
+				}
+			}
+		}
+
+		// The ready container of the driver reports the workers that it
+		// could not connect to, which is more useful than its exit code.
+		if role == config.DriverRole && reason == grpcv1.InitContainerError {
+			if workers, err := UnreachableWorkersForDriverPod(pod); err == nil && len(workers) > 0 {
+				status.UnreachableWorkers = workers
+				reason, message = grpcv1.WorkersUnreachableError, unreachableWorkersMessage(workers)
+			}
+		}
+
+		// The supervisor of a worker reports when it stopped the worker
+		// after the timeout, so the worker is not mistaken for a crash.
+		if role != config.DriverRole && reason == grpcv1.ContainerError {
+			if report, err := SupervisorReportForPod(pod); err == nil && report != nil {
+				reason, message = grpcv1.TimeoutErrored, supervisorTimeoutMessage(pod, report)
+			}
+		}
+
+		if role == config.DriverRole && reason == grpcv1.ContainerError {
+			reason = grpcv1.DriverCrashedError
+		}
 		status.Reason = reason
 		status.Message = message
 
 		if role == config.DriverRole {
 			if podState == Succeeded {
 				status.State = grpcv1.Succeeded
+
+				// The summary is informational, so a malformed termination
+				// message should not change the outcome of the test.
+				if test.Spec.Interop == nil {
+					if summary, err := SummaryForDriverPod(pod); err == nil {
+						status.Summary = summary
+					}
+				}
+				status.ResultsURI = kubehelpers.ResultsURIForLoadTest(test)
 			} else {
 				status.State = grpcv1.Errored
 			}
 		} else {
 			if podState == Succeeded {
+				if role == config.GeneratorRole {
+					succeededGenerators++
+				}
+
 				// ignore workers that complete "successfully" for now
 				continue
 			}
@@ -170,7 +254,7 @@ func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod) grpcv1.LoadTestStatu
 	}
 
 	currentPods := len(pods)
-	requiredPods := len(test.Spec.Servers) + len(test.Spec.Clients) + 1
+	requiredPods := requiredPodCount(test)
 
 	if currentPods < requiredPods {
 		status.State = grpcv1.Initializing
@@ -179,6 +263,33 @@ func ForLoadTest(test *grpcv1.LoadTest, pods []*corev1.Pod) grpcv1.LoadTestStatu
 		return status
 	}
 
+	// Without a driver, the generators decide the outcome of the test.
+	if test.Spec.Driver == nil && len(test.Spec.Generators) > 0 && succeededGenerators >= len(test.Spec.Generators) {
+		status.State = grpcv1.Succeeded
+
+		// The summary is informational, so a malformed report should not
+		// change the outcome of the test.
+		if summary, err := SummaryForGeneratorPods(test, pods); err == nil {
+			status.Summary = summary
+		}
+
+		if test.Status.StopTime == nil {
+			status.StopTime = optional.CurrentTimePtr()
+		} else {
+			status.StopTime = test.Status.StopTime
+		}
+		return status
+	}
+
 	status.State = grpcv1.Running
 	return status
 }
+
+// requiredPodCount returns the number of pods that a load test requires.
+func requiredPodCount(test *grpcv1.LoadTest) int {
+	requiredPods := len(test.Spec.Servers) + kubehelpers.ClientCount(test.Spec.Clients) + len(test.Spec.Generators)
+	if test.Spec.Driver != nil {
+		requiredPods++
+	}
+	return requiredPods
+}
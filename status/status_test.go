@@ -172,6 +172,17 @@ var _ = Describe("StateForPodStatus", func() {
 			Expect(state).To(Equal(Errored))
 			Expect(reason).To(Equal(grpcv1.InitContainerError))
 		})
+
+		It("marks pod as errored when the build init container errored", func() {
+			initContainer1.State.Terminated = &corev1.ContainerStateTerminated{ExitCode: 0}
+
+			initContainer2.Name = config.BuildInitContainerName
+			initContainer2.State.Terminated = &corev1.ContainerStateTerminated{ExitCode: 1}
+
+			state, reason, _ := StateForPodStatus(podStatus)
+			Expect(state).To(Equal(Errored))
+			Expect(reason).To(Equal(grpcv1.BuildFailedError))
+		})
 	})
 
 	Context("init containers succeeded", func() {
@@ -200,6 +211,14 @@ var _ = Describe("StateForPodStatus", func() {
 			Expect(reason).To(Equal(grpcv1.ContainerError))
 		})
 
+		It("marks pod as errored when containers are crashing", func() {
+			container.State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+
+			state, reason, _ := StateForPodStatus(podStatus)
+			Expect(state).To(Equal(Errored))
+			Expect(reason).To(Equal(grpcv1.CrashLoopError))
+		})
+
 		It("marks a pod as pending if not all containers have finished", func() {
 			container.State.Terminated = &corev1.ContainerStateTerminated{ExitCode: 0}
 			podStatus.ContainerStatuses = append(podStatus.ContainerStatuses, corev1.ContainerStatus{
@@ -299,7 +318,7 @@ var _ = Describe("ForLoadTest", func() {
 	It("sets start time when unset", func() {
 		testStart := metav1.Now()
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.StartTime).ToNot(BeNil())
 		Expect(testStart.Before(status.StartTime)).To(BeTrue())
@@ -309,7 +328,7 @@ var _ = Describe("ForLoadTest", func() {
 		fakeStartTime := metav1.Now()
 		test.Status.StartTime = &fakeStartTime
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.StartTime).To(Equal(&fakeStartTime))
 	})
@@ -317,7 +336,7 @@ var _ = Describe("ForLoadTest", func() {
 	It("sets error state when running longer than timeout", func() {
 		fakeStartTime := metav1.Time{Time: time.Date(2020, time.October, 23, 15, 0, 0, 0, time.UTC)}
 		test.Status.StartTime = &fakeStartTime
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.StartTime).ToNot(BeNil())
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
@@ -348,9 +367,67 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Succeeded))
+	})
+
+	It("sets the result summary reported by a succeeded driver pod", func() {
+		driverPod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: config.RunContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 0,
+						Message:  `{"qps": 2500}`,
+					},
+				},
+			},
+		}
+
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Succeeded))
+		Expect(status.Summary).ToNot(BeNil())
+		Expect(status.Summary.QPS).To(Equal("2500"))
+	})
+
+	It("sets the results URI when a succeeded driver uploads its output", func() {
+		test.Spec.Results = &grpcv1.Results{
+			GCSPrefix: optional.StringPtr("gs://example-bucket"),
+		}
+		driverPod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: config.RunContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+				},
+			},
+		}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Succeeded))
+		Expect(status.ResultsURI).To(HavePrefix("gs://example-bucket/"))
+	})
+
+	It("does not set the results URI when the driver has not succeeded", func() {
+		test.Spec.Results = &grpcv1.Results{
+			GCSPrefix: optional.StringPtr("gs://example-bucket"),
+		}
+		driverPod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: config.RunContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+				},
+			},
+		}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.ResultsURI).To(BeEmpty())
 	})
 
 	It("does not set succeeded state when worker pods succeeded", func() {
@@ -378,7 +455,7 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).ToNot(BeEquivalentTo(grpcv1.Succeeded))
 	})
@@ -408,9 +485,10 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.DriverCrashedError))
 	})
 
 	It("sets errored state when driver pod init container errored", func() {
@@ -446,7 +524,7 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
 	})
@@ -476,9 +554,10 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.ContainerError))
 	})
 
 	It("sets stop time when unset", func() {
@@ -508,7 +587,7 @@ var _ = Describe("ForLoadTest", func() {
 			},
 		}
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.StopTime).ToNot(BeNil())
 		Expect(testStart.Before(status.StopTime)).To(BeTrue())
@@ -542,7 +621,7 @@ var _ = Describe("ForLoadTest", func() {
 		stopTime := optional.CurrentTimePtr()
 		test.Status.StopTime = stopTime
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.StopTime).ToNot(BeNil())
 		Expect(*status.StopTime).To(Equal(*stopTime))
@@ -551,8 +630,59 @@ var _ = Describe("ForLoadTest", func() {
 	It("sets initializing state when pods are missing", func() {
 		pods = pods[1:] // remove the driver from the world
 
-		status := ForLoadTest(test, pods)
+		status := ForLoadTest(test, pods, nil)
 
 		Expect(status.State).To(BeEquivalentTo(grpcv1.Initializing))
 	})
+
+	It("sets errored state when a worker exceeds waiting limits", func() {
+		clientPod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:         "main",
+				RestartCount: 3,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				},
+			},
+		}
+
+		status := ForLoadTest(test, pods, &WaitingLimits{MaxRestarts: 3})
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.CrashLoopError))
+		Expect(status.StopTime).ToNot(BeNil())
+	})
+
+	It("sets errored state when the driver is killed for exceeding its memory", func() {
+		driverPod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: "main",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 137,
+						Reason:   "OOMKilled",
+					},
+				},
+			},
+		}
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.ResourceExceededError))
+		Expect(status.Message).To(ContainSubstring("memory"))
+	})
+
+	It("sets errored state when a worker pod is evicted", func() {
+		serverPod.Status.Phase = corev1.PodFailed
+		serverPod.Status.Reason = "Evicted"
+		serverPod.Status.Message = "Container main exceeded its local ephemeral storage limit"
+
+		status := ForLoadTest(test, pods, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.ResourceExceededError))
+		Expect(status.Message).To(ContainSubstring("ephemeral storage"))
+		Expect(status.StopTime).ToNot(BeNil())
+	})
 })
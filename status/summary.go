@@ -0,0 +1,107 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// driverSummary mirrors the fields of the summary in a ScenarioResult message
+// that the driver reports in the termination message of its run container.
+// Latencies are in nanoseconds and system times are percentages.
+type driverSummary struct {
+	QPS              *float64 `json:"qps"`
+	Latency50        *float64 `json:"latency50"`
+	Latency99        *float64 `json:"latency99"`
+	Latency999       *float64 `json:"latency999"`
+	ClientSystemTime *float64 `json:"clientSystemTime"`
+	ServerSystemTime *float64 `json:"serverSystemTime"`
+}
+
+// SummaryForDriverPod accepts a driver pod and returns a summary of the
+// results that its run container reported in its termination message. If the
+// run container has not terminated or did not report a summary, nil is
+// returned. An error is returned if the summary cannot be parsed.
+func SummaryForDriverPod(pod *corev1.Pod) (*grpcv1.ResultSummary, error) {
+	var message string
+	for i := range pod.Status.ContainerStatuses {
+		contStat := &pod.Status.ContainerStatuses[i]
+		if contStat.Name != config.RunContainerName || contStat.State.Terminated == nil {
+			continue
+		}
+		message = contStat.State.Terminated.Message
+	}
+	if message == "" {
+		return nil, nil
+	}
+
+	var ds driverSummary
+	if err := json.Unmarshal([]byte(message), &ds); err != nil {
+		return nil, fmt.Errorf("failed to parse result summary from termination message: %v", err)
+	}
+
+	return &grpcv1.ResultSummary{
+		QPS:              formatFloat(ds.QPS, formatQPS),
+		Latency50:        formatFloat(ds.Latency50, formatLatency),
+		Latency99:        formatFloat(ds.Latency99, formatLatency),
+		Latency999:       formatFloat(ds.Latency999, formatLatency),
+		ClientSystemTime: formatFloat(ds.ClientSystemTime, formatPercentage),
+		ServerSystemTime: formatFloat(ds.ServerSystemTime, formatPercentage),
+	}, nil
+}
+
+// formatFloat applies a format to a value, returning an empty string if the
+// value is nil.
+func formatFloat(value *float64, format func(float64) string) string {
+	if value == nil {
+		return ""
+	}
+	return format(*value)
+}
+
+// formatQPS formats queries per second as a whole number.
+func formatQPS(qps float64) string {
+	return strconv.FormatFloat(qps, 'f', 0, 64)
+}
+
+// formatLatency formats a latency in nanoseconds as a duration, rounded to a
+// precision suitable for display.
+func formatLatency(nanoseconds float64) string {
+	d := time.Duration(nanoseconds)
+	switch {
+	case d < time.Millisecond:
+		d = d.Round(100 * time.Nanosecond)
+	case d < time.Second:
+		d = d.Round(10 * time.Microsecond)
+	default:
+		d = d.Round(10 * time.Millisecond)
+	}
+	return d.String()
+}
+
+// formatPercentage formats a percentage with one decimal place.
+func formatPercentage(percentage float64) string {
+	return fmt.Sprintf("%.1f%%", percentage)
+}
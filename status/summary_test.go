@@ -0,0 +1,93 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("SummaryForDriverPod", func() {
+	var pod *corev1.Pod
+
+	setTerminationMessage := func(message string) {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: config.RunContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 0,
+						Message:  message,
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		pod = new(corev1.Pod)
+	})
+
+	It("returns nil when the run container has not terminated", func() {
+		summary, err := SummaryForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(BeNil())
+	})
+
+	It("returns nil when the driver did not report a summary", func() {
+		setTerminationMessage("")
+
+		summary, err := SummaryForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(BeNil())
+	})
+
+	It("formats the reported numbers", func() {
+		setTerminationMessage(`{"qps": 123456.78, "latency50": 245312.5, "latency99": 1234567, "latency999": 2500000000, "clientSystemTime": 12.345, "serverSystemTime": 7}`)
+
+		summary, err := SummaryForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(&grpcv1.ResultSummary{
+			QPS:              "123457",
+			Latency50:        "245.3µs",
+			Latency99:        "1.23ms",
+			Latency999:       "2.5s",
+			ClientSystemTime: "12.3%",
+			ServerSystemTime: "7.0%",
+		}))
+	})
+
+	It("omits numbers that were not reported", func() {
+		setTerminationMessage(`{"qps": 1000}`)
+
+		summary, err := SummaryForDriverPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(&grpcv1.ResultSummary{QPS: "1000"}))
+	})
+
+	It("returns an error when the summary is malformed", func() {
+		setTerminationMessage("not json")
+
+		_, err := SummaryForDriverPod(pod)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// SupervisorReport is the report that the supervisor program writes to the
+// termination message of the run container of a worker when it stops the
+// command of the worker after the timeout of the load test.
+type SupervisorReport struct {
+	// TimeoutAction is "terminated" when the command exited after SIGTERM,
+	// and "killed" when it had to be killed with SIGKILL.
+	TimeoutAction string `json:"timeoutAction"`
+
+	// TimeoutSeconds is the timeout that was exceeded.
+	TimeoutSeconds float64 `json:"timeoutSeconds"`
+
+	// KillAfterSeconds is the time the command was allowed to respond to
+	// SIGTERM before it was killed.
+	KillAfterSeconds float64 `json:"killAfterSeconds"`
+}
+
+// SupervisorReportForPod accepts the pod of a worker and returns the report
+// that the supervisor wrote to the termination message of its run container.
+// If the run container has not terminated, or the supervisor did not stop its
+// command, nil is returned. An error is returned if the termination message
+// cannot be parsed.
+func SupervisorReportForPod(pod *corev1.Pod) (*SupervisorReport, error) {
+	var message string
+	for i := range pod.Status.ContainerStatuses {
+		contStat := &pod.Status.ContainerStatuses[i]
+		if contStat.Name != config.RunContainerName || contStat.State.Terminated == nil {
+			continue
+		}
+		message = contStat.State.Terminated.Message
+	}
+	if message == "" {
+		return nil, nil
+	}
+
+	var report SupervisorReport
+	if err := json.Unmarshal([]byte(message), &report); err != nil {
+		return nil, fmt.Errorf("failed to parse supervisor report from termination message: %v", err)
+	}
+	if report.TimeoutAction == "" {
+		return nil, nil
+	}
+	return &report, nil
+}
+
+// supervisorTimeoutMessage returns a message that describes how the supervisor
+// stopped the run container of a pod after the timeout.
+func supervisorTimeoutMessage(pod *corev1.Pod, report *SupervisorReport) string {
+	return fmt.Sprintf("timeout of %gs exceeded, container %q of pod %q was %s by the supervisor", report.TimeoutSeconds, config.RunContainerName, pod.Name, report.TimeoutAction)
+}
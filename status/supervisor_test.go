@@ -0,0 +1,111 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+// newSupervisedServerPod returns a server pod, whose run container has
+// terminated with the exit code and termination message.
+func newSupervisedServerPod(exitCode int32, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "server",
+			Labels: map[string]string{
+				config.RoleLabel:          config.ServerRole,
+				config.ComponentNameLabel: "server",
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: config.RunContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+							Message:  message,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("SupervisorReportForPod", func() {
+	It("returns nil when the supervisor did not stop the command", func() {
+		report, err := SupervisorReportForPod(newSupervisedServerPod(1, ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report).To(BeNil())
+	})
+
+	It("returns the reported action", func() {
+		pod := newSupervisedServerPod(124, `{"timeoutAction": "killed", "timeoutSeconds": 900, "killAfterSeconds": 30}`)
+
+		report, err := SupervisorReportForPod(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report).To(Equal(&SupervisorReport{TimeoutAction: "killed", TimeoutSeconds: 900, KillAfterSeconds: 30}))
+	})
+
+	It("returns an error when the report is malformed", func() {
+		_, err := SupervisorReportForPod(newSupervisedServerPod(124, `{"timeoutAction": `))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ForLoadTest with supervised workers", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Driver:         &grpcv1.Driver{Name: optional.StringPtr("driver")},
+				TTLSeconds:     int32(120),
+				TimeoutSeconds: int32(30),
+			},
+		}
+	})
+
+	It("sets errored state with the timeout reason when the supervisor stopped a worker", func() {
+		pod := newSupervisedServerPod(124, `{"timeoutAction": "killed", "timeoutSeconds": 900, "killAfterSeconds": 30}`)
+
+		status := ForLoadTest(test, []*corev1.Pod{pod}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.TimeoutErrored))
+		Expect(status.Message).To(ContainSubstring("was killed by the supervisor"))
+	})
+
+	It("reports a container error when the worker crashed", func() {
+		status := ForLoadTest(test, []*corev1.Pod{newSupervisedServerPod(1, "")}, nil)
+
+		Expect(status.State).To(BeEquivalentTo(grpcv1.Errored))
+		Expect(status.Reason).To(Equal(grpcv1.ContainerError))
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// imagePullWaitingReasons are the reasons the kubelet reports when a container
+// is waiting because its image could not be pulled. These may be transient,
+// so they are only treated as failures after a timeout.
+var imagePullWaitingReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+}
+
+// fatalImageWaitingReasons are the reasons the kubelet reports when a container
+// will never be able to pull its image, regardless of how long it waits.
+var fatalImageWaitingReasons = map[string]bool{
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// WaitingLimits configures how long containers may wait or how often they may
+// restart before the load test that owns them is considered errored. This
+// allows a test to fail promptly, instead of waiting for its timeout.
+type WaitingLimits struct {
+	// ImagePullTimeout is the longest duration a pod may wait for the
+	// image of any of its containers to be pulled. A zero value disables
+	// the check.
+	ImagePullTimeout time.Duration
+
+	// MaxRestarts is the number of restarts of any container at which the
+	// pod is considered errored. A zero value disables the check.
+	MaxRestarts int32
+}
+
+// CheckWaitingLimits accepts a pod, a set of limits and the current time. It
+// returns true with a reason and message if any container on the pod has
+// exceeded the limits. Otherwise, it returns false and empty strings.
+func CheckWaitingLimits(pod *corev1.Pod, limits *WaitingLimits, now time.Time) (exceeded bool, reason string, message string) {
+	if pod == nil || limits == nil {
+		return false, "", ""
+	}
+
+	waitingSince := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		waitingSince = pod.Status.StartTime.Time
+	}
+
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for i := range statuses {
+		contStat := &statuses[i]
+
+		if waitState := contStat.State.Waiting; waitState != nil {
+			if fatalImageWaitingReasons[waitState.Reason] {
+				message := fmt.Sprintf("container %q cannot pull its image (%s): %s", contStat.Name, waitState.Reason, waitState.Message)
+				return true, grpcv1.ImagePullError, message
+			}
+
+			if imagePullWaitingReasons[waitState.Reason] && limits.ImagePullTimeout > 0 && !waitingSince.IsZero() {
+				if waited := now.Sub(waitingSince); waited >= limits.ImagePullTimeout {
+					message := fmt.Sprintf("container %q could not pull its image within %v (%s): %s", contStat.Name, limits.ImagePullTimeout, waitState.Reason, waitState.Message)
+					return true, grpcv1.ImagePullError, message
+				}
+			}
+		}
+
+		if limits.MaxRestarts > 0 && contStat.RestartCount >= limits.MaxRestarts {
+			message := fmt.Sprintf("container %q restarted %d times, reaching the limit of %d", contStat.Name, contStat.RestartCount, limits.MaxRestarts)
+			return true, grpcv1.CrashLoopError, message
+		}
+	}
+
+	return false, "", ""
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("CheckWaitingLimits", func() {
+	var pod *corev1.Pod
+	var limits *WaitingLimits
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Time{Time: now.Add(-time.Minute)},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "main",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{},
+						},
+					},
+				},
+			},
+		}
+		limits = &WaitingLimits{
+			ImagePullTimeout: 2 * time.Minute,
+			MaxRestarts:      2,
+		}
+	})
+
+	It("does not report an error when limits are nil", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "InvalidImageName"
+		exceeded, _, _ := CheckWaitingLimits(pod, nil, now)
+		Expect(exceeded).To(BeFalse())
+	})
+
+	It("does not report an error for a pod that is creating", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ContainerCreating"
+		exceeded, _, _ := CheckWaitingLimits(pod, limits, now)
+		Expect(exceeded).To(BeFalse())
+	})
+
+	It("reports an error immediately for an invalid image name", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "InvalidImageName"
+		exceeded, reason, _ := CheckWaitingLimits(pod, limits, now)
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ImagePullError))
+	})
+
+	It("does not report an error for a pull back-off within the timeout", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+		exceeded, _, _ := CheckWaitingLimits(pod, limits, now)
+		Expect(exceeded).To(BeFalse())
+	})
+
+	It("reports an error for a pull back-off after the timeout", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+		exceeded, reason, _ := CheckWaitingLimits(pod, limits, now.Add(2*time.Minute))
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ImagePullError))
+	})
+
+	It("does not report an error for a pull back-off when the timeout is disabled", func() {
+		limits.ImagePullTimeout = 0
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+		exceeded, _, _ := CheckWaitingLimits(pod, limits, now.Add(time.Hour))
+		Expect(exceeded).To(BeFalse())
+	})
+
+	It("checks init containers", func() {
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: "clone",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"},
+				},
+			},
+		}
+		exceeded, reason, _ := CheckWaitingLimits(pod, limits, now.Add(time.Hour))
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.ImagePullError))
+	})
+
+	It("reports an error when restarts reach the limit", func() {
+		pod.Status.ContainerStatuses[0].RestartCount = 2
+		exceeded, reason, _ := CheckWaitingLimits(pod, limits, now)
+		Expect(exceeded).To(BeTrue())
+		Expect(reason).To(Equal(grpcv1.CrashLoopError))
+	})
+
+	It("does not report an error when restarts are below the limit", func() {
+		pod.Status.ContainerStatuses[0].RestartCount = 1
+		exceeded, _, _ := CheckWaitingLimits(pod, limits, now)
+		Expect(exceeded).To(BeFalse())
+	})
+})
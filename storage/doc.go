@@ -0,0 +1,25 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage contains code for retaining the artifacts of load tests,
+// such as pod logs, scenario results and profiles, in Google Cloud Storage.
+//
+// Artifacts are uploaded under a canonical layout that places the artifacts
+// of each test under its namespace, name and UID. Reports refer to uploaded
+// artifacts with signed URLs, so that they can be opened without access to
+// the bucket until the URLs expire. Artifacts are deleted by a lifecycle rule
+// of the bucket after a retention period.
+package storage
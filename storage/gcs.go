@@ -0,0 +1,150 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// GCSBucket is a bucket in Google Cloud Storage.
+type GCSBucket struct {
+	// handle is used to access the bucket.
+	handle *gcs.BucketHandle
+	// name is the name of the bucket.
+	name string
+	// googleAccessID is the email address of the service account that signs
+	// URLs.
+	googleAccessID string
+	// privateKey is the private key of the service account that signs URLs.
+	// It is nil when URLs cannot be signed.
+	privateKey []byte
+}
+
+// NewGCSBucket creates a new GCSBucket for the bucket with the given name,
+// using the application default credentials. The JSON key of the service
+// account in signingKeyFile is used to sign URLs. The signing key file may be
+// empty, in which case URLs cannot be signed.
+func NewGCSBucket(ctx context.Context, name string, signingKeyFile string) (*GCSBucket, error) {
+	b := &GCSBucket{name: name}
+
+	if signingKeyFile != "" {
+		data, err := os.ReadFile(signingKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read signing key file %q: %v", signingKeyFile, err)
+		}
+		var key struct {
+			ClientEmail string `json:"client_email"`
+			PrivateKey  string `json:"private_key"`
+		}
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("could not parse signing key file %q: %v", signingKeyFile, err)
+		}
+		if key.ClientEmail == "" || key.PrivateKey == "" {
+			return nil, fmt.Errorf("signing key file %q is not a service account key", signingKeyFile)
+		}
+		b.googleAccessID = key.ClientEmail
+		b.privateKey = []byte(key.PrivateKey)
+	}
+
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create storage client: %v", err)
+	}
+	b.handle = client.Bucket(name)
+	return b, nil
+}
+
+// Name implements the Bucket interface.
+func (b *GCSBucket) Name() string {
+	return b.name
+}
+
+// NewWriter implements the Bucket interface.
+func (b *GCSBucket) NewWriter(ctx context.Context, object string) io.WriteCloser {
+	return b.handle.Object(object).NewWriter(ctx)
+}
+
+// SignedURL implements the Bucket interface. It returns an error if the
+// bucket was created without a signing key.
+func (b *GCSBucket) SignedURL(object string, expires time.Time) (string, error) {
+	if b.privateKey == nil {
+		return "", fmt.Errorf("no signing key for bucket %s", b.name)
+	}
+	return gcs.SignedURL(b.name, object, &gcs.SignedURLOptions{
+		GoogleAccessID: b.googleAccessID,
+		PrivateKey:     b.privateKey,
+		Method:         http.MethodGet,
+		Expires:        expires,
+		Scheme:         gcs.SigningSchemeV4,
+	})
+}
+
+// SetRetention updates the lifecycle configuration of the bucket, so that
+// objects are deleted after the given number of days. Other lifecycle rules of
+// the bucket are preserved. Objects are not deleted by age when days is zero.
+func (b *GCSBucket) SetRetention(ctx context.Context, days int) error {
+	attrs, err := b.handle.Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get attributes of bucket %s: %v", b.name, err)
+	}
+
+	lifecycle := RetentionLifecycle(attrs.Lifecycle, days)
+	if _, err := b.handle.Update(ctx, gcs.BucketAttrsToUpdate{Lifecycle: &lifecycle}); err != nil {
+		return fmt.Errorf("could not update lifecycle of bucket %s: %v", b.name, err)
+	}
+	return nil
+}
+
+// RetentionLifecycle returns a copy of a lifecycle configuration, where the
+// rules that delete objects by age alone are replaced with a rule that deletes
+// objects after the given number of days. No such rule is added when days is
+// zero.
+func RetentionLifecycle(lifecycle gcs.Lifecycle, days int) gcs.Lifecycle {
+	var rules []gcs.LifecycleRule
+	for _, rule := range lifecycle.Rules {
+		if !isRetentionRule(rule) {
+			rules = append(rules, rule)
+		}
+	}
+	if days > 0 {
+		rules = append(rules, gcs.LifecycleRule{
+			Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+			Condition: gcs.LifecycleCondition{AgeInDays: int64(days)},
+		})
+	}
+	return gcs.Lifecycle{Rules: rules}
+}
+
+// isRetentionRule returns true if a lifecycle rule deletes objects based on
+// their age alone.
+func isRetentionRule(rule gcs.LifecycleRule) bool {
+	c := rule.Condition
+	return rule.Action.Type == gcs.DeleteAction &&
+		c.AgeInDays > 0 &&
+		c.CreatedBefore.IsZero() &&
+		c.Liveness == gcs.LiveAndArchived &&
+		len(c.MatchesStorageClasses) == 0 &&
+		c.NumNewerVersions == 0
+}
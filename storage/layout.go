@@ -0,0 +1,67 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// Kind is the kind of an artifact. It determines the directory that the
+// artifact is placed in under the directory of its test.
+type Kind string
+
+const (
+	// LogKind is the kind of the logs of the containers of a test.
+	LogKind Kind = "logs"
+
+	// NodeMetricsKind is the kind of the aggregates of the system metrics
+	// of the nodes that ran a test.
+	NodeMetricsKind Kind = "node-metrics"
+
+	// ProfileKind is the kind of the pprof profiles collected from the
+	// workers of a test.
+	ProfileKind Kind = "profiles"
+
+	// ProxyKind is the kind of the stats and config dumps collected from
+	// the Envoy sidecars of a proxied test.
+	ProxyKind Kind = "proxy"
+
+	// ResultKind is the kind of the results of a test, such as the scenario
+	// result printed by the driver.
+	ResultKind Kind = "results"
+)
+
+// ObjectName returns the name of the object that an artifact of a load test
+// is stored in. The name is built from the prefix, followed by the namespace,
+// name and UID of the test, the kind of the artifact and the name of its file.
+// This matches the layout of the results that the driver uploads itself, so
+// all the output of a test is found under the same directory.
+func ObjectName(prefix string, test *grpcv1.LoadTest, kind Kind, fileName string) string {
+	elems := []string{test.Namespace, test.Name, string(test.UID), string(kind), fileName}
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		elems = append([]string{prefix}, elems...)
+	}
+	return strings.Join(elems, "/")
+}
+
+// URI returns the gs:// URI of an object in a bucket.
+func URI(bucket string, object string) string {
+	return fmt.Sprintf("gs://%s/%s", bucket, object)
+}
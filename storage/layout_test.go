@@ -0,0 +1,57 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+var _ = Describe("ObjectName", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+				UID:       "1234",
+			},
+		}
+	})
+
+	It("places the artifact under the namespace, name, UID and kind", func() {
+		Expect(ObjectName("artifacts", test, LogKind, "example-test-driver-0-main.log")).To(Equal("artifacts/example-namespace/example-test/1234/logs/example-test-driver-0-main.log"))
+	})
+
+	It("ignores slashes around the prefix", func() {
+		Expect(ObjectName("/artifacts/", test, ProfileKind, "example-test-server-0-cpu.pprof")).To(Equal("artifacts/example-namespace/example-test/1234/profiles/example-test-server-0-cpu.pprof"))
+	})
+
+	It("places the artifact at the root of the bucket without a prefix", func() {
+		Expect(ObjectName("", test, ResultKind, "scenario_result.json")).To(Equal("example-namespace/example-test/1234/results/scenario_result.json"))
+	})
+})
+
+var _ = Describe("URI", func() {
+	It("returns a gs:// URI", func() {
+		Expect(URI("example-bucket", "a/b.log")).To(Equal("gs://example-bucket/a/b.log"))
+	})
+})
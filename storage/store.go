@@ -0,0 +1,187 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// Bucket is the bucket that artifacts are uploaded to. It is implemented by
+// GCSBucket, and may be replaced with a fake for testing.
+type Bucket interface {
+	// Name returns the name of the bucket.
+	Name() string
+
+	// NewWriter returns a writer that uploads an object to the bucket. The
+	// upload is complete when the writer is closed without error. It is
+	// aborted if the context is cancelled before the writer is closed.
+	NewWriter(ctx context.Context, object string) io.WriteCloser
+
+	// SignedURL returns a URL that grants read access to an object until
+	// the given expiration time.
+	SignedURL(object string, expires time.Time) (string, error)
+}
+
+// Artifact contains information about an uploaded artifact.
+type Artifact struct {
+	// Kind is the kind of the artifact.
+	Kind Kind `json:"kind"`
+
+	// Name is the name of the file of the artifact.
+	Name string `json:"name"`
+
+	// URI is the gs:// URI of the uploaded object.
+	URI string `json:"uri"`
+
+	// SignedURL is a URL that grants read access to the object until it
+	// expires. It is empty if URLs are not signed.
+	SignedURL string `json:"signedURL,omitempty"`
+
+	// Expires is the time when SignedURL expires. It is nil if URLs are not
+	// signed.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// URL returns the URL used to refer to the artifact in reports. This is the
+// signed URL of the artifact when there is one, or its URI otherwise.
+func (a *Artifact) URL() string {
+	if a.SignedURL != "" {
+		return a.SignedURL
+	}
+	return a.URI
+}
+
+// Store uploads the artifacts of load tests to a bucket.
+type Store struct {
+	// bucket is the bucket that artifacts are uploaded to.
+	bucket Bucket
+	// prefix is prepended to the names of the uploaded objects.
+	prefix string
+	// signedURLExpiry is the time that signed URLs remain valid. URLs are
+	// not signed when it is zero.
+	signedURLExpiry time.Duration
+	// now returns the current time. It is replaced with a fake for testing.
+	now func() time.Time
+}
+
+// NewStore creates a new Store that uploads artifacts to a bucket, under the
+// given prefix. Signed URLs that remain valid for signedURLExpiry are created
+// for each artifact, unless signedURLExpiry is zero.
+func NewStore(bucket Bucket, prefix string, signedURLExpiry time.Duration) *Store {
+	return &Store{
+		bucket:          bucket,
+		prefix:          prefix,
+		signedURLExpiry: signedURLExpiry,
+		now:             time.Now,
+	}
+}
+
+// Upload uploads a file as an artifact of a load test. The object is named
+// after the test, the kind of the artifact and the base name of the file.
+// Information about the uploaded artifact is returned. If the upload succeeds
+// but the URL cannot be signed, the artifact is returned along with an error.
+func (s *Store) Upload(ctx context.Context, test *grpcv1.LoadTest, kind Kind, filePath string) (*Artifact, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	fileName := filepath.Base(filePath)
+	object := ObjectName(s.prefix, test, kind, fileName)
+	uri := URI(s.bucket.Name(), object)
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := s.bucket.NewWriter(uploadCtx, object)
+	if _, err := io.Copy(w, file); err != nil {
+		cancel()
+		w.Close()
+		return nil, fmt.Errorf("could not upload %s to %s: %v", filePath, uri, err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("could not upload %s to %s: %v", filePath, uri, err)
+	}
+
+	artifact := &Artifact{
+		Kind: kind,
+		Name: fileName,
+		URI:  uri,
+	}
+
+	if s.signedURLExpiry > 0 {
+		expires := s.now().Add(s.signedURLExpiry).UTC()
+		signedURL, err := s.bucket.SignedURL(object, expires)
+		if err != nil {
+			return artifact, fmt.Errorf("could not sign URL for %s: %v", uri, err)
+		}
+		artifact.SignedURL = signedURL
+		artifact.Expires = &expires
+	}
+
+	return artifact, nil
+}
+
+// Manifest lists the artifacts uploaded for a load test. It is written as the
+// JSON report of the test.
+type Manifest struct {
+	// Namespace is the namespace of the test.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the test.
+	Name string `json:"name"`
+
+	// UID is the UID of the test.
+	UID string `json:"uid"`
+
+	// Artifacts lists the uploaded artifacts.
+	Artifacts []*Artifact `json:"artifacts"`
+}
+
+// NewManifest creates an empty manifest for a load test.
+func NewManifest(test *grpcv1.LoadTest) *Manifest {
+	return &Manifest{
+		Namespace: test.Namespace,
+		Name:      test.Name,
+		UID:       string(test.UID),
+		Artifacts: []*Artifact{},
+	}
+}
+
+// Add adds an artifact to the manifest.
+func (m *Manifest) Add(artifact *Artifact) {
+	m.Artifacts = append(m.Artifacts, artifact)
+}
+
+// Write writes the manifest as indented JSON.
+func (m *Manifest) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(m); err != nil {
+		return fmt.Errorf("could not encode manifest: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// fakeBucket is a Bucket that keeps uploaded objects in memory.
+type fakeBucket struct {
+	objects map[string][]byte
+	signErr error
+}
+
+func newFakeBucket() *fakeBucket {
+	return &fakeBucket{objects: make(map[string][]byte)}
+}
+
+func (b *fakeBucket) Name() string {
+	return "example-bucket"
+}
+
+func (b *fakeBucket) NewWriter(ctx context.Context, object string) io.WriteCloser {
+	return &fakeWriter{bucket: b, object: object}
+}
+
+func (b *fakeBucket) SignedURL(object string, expires time.Time) (string, error) {
+	if b.signErr != nil {
+		return "", b.signErr
+	}
+	return fmt.Sprintf("https://storage.example.com/%s?expires=%d", object, expires.Unix()), nil
+}
+
+// fakeWriter stores an object in a fakeBucket when it is closed.
+type fakeWriter struct {
+	bytes.Buffer
+	bucket *fakeBucket
+	object string
+}
+
+func (w *fakeWriter) Close() error {
+	w.bucket.objects[w.object] = w.Bytes()
+	return nil
+}
+
+var _ = Describe("Store", func() {
+	var bucket *fakeBucket
+	var test *grpcv1.LoadTest
+	var dir string
+	var filePath string
+	var now time.Time
+
+	BeforeEach(func() {
+		bucket = newFakeBucket()
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+				UID:       "1234",
+			},
+		}
+
+		var err error
+		dir, err = os.MkdirTemp("", "storage")
+		Expect(err).ToNot(HaveOccurred())
+		filePath = filepath.Join(dir, "example-test-driver-0-main.log")
+		Expect(os.WriteFile(filePath, []byte("driver log"), 0644)).To(Succeed())
+
+		now = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("uploads the file under the canonical layout", func() {
+		store := NewStore(bucket, "artifacts", 0)
+		artifact, err := store.Upload(context.Background(), test, LogKind, filePath)
+		Expect(err).ToNot(HaveOccurred())
+
+		object := "artifacts/example-namespace/example-test/1234/logs/example-test-driver-0-main.log"
+		Expect(bucket.objects).To(HaveKeyWithValue(object, []byte("driver log")))
+		Expect(artifact.Kind).To(Equal(LogKind))
+		Expect(artifact.Name).To(Equal("example-test-driver-0-main.log"))
+		Expect(artifact.URI).To(Equal("gs://example-bucket/" + object))
+	})
+
+	It("refers to the URI when URLs are not signed", func() {
+		store := NewStore(bucket, "artifacts", 0)
+		artifact, err := store.Upload(context.Background(), test, LogKind, filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(artifact.SignedURL).To(BeEmpty())
+		Expect(artifact.Expires).To(BeNil())
+		Expect(artifact.URL()).To(Equal(artifact.URI))
+	})
+
+	It("signs a URL that expires after the expiry", func() {
+		store := NewStore(bucket, "artifacts", time.Hour)
+		store.now = func() time.Time { return now }
+		artifact, err := store.Upload(context.Background(), test, LogKind, filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(artifact.Expires).ToNot(BeNil())
+		Expect(*artifact.Expires).To(Equal(now.Add(time.Hour)))
+		Expect(artifact.SignedURL).To(ContainSubstring(fmt.Sprint(now.Add(time.Hour).Unix())))
+		Expect(artifact.URL()).To(Equal(artifact.SignedURL))
+	})
+
+	It("returns the uploaded artifact when the URL cannot be signed", func() {
+		bucket.signErr = errors.New("no signing key")
+		store := NewStore(bucket, "artifacts", time.Hour)
+		artifact, err := store.Upload(context.Background(), test, LogKind, filePath)
+		Expect(err).To(HaveOccurred())
+		Expect(artifact).ToNot(BeNil())
+		Expect(artifact.URL()).To(Equal(artifact.URI))
+	})
+
+	It("returns an error when the file does not exist", func() {
+		store := NewStore(bucket, "artifacts", 0)
+		_, err := store.Upload(context.Background(), test, LogKind, filepath.Join(dir, "missing.log"))
+		Expect(err).To(HaveOccurred())
+		Expect(bucket.objects).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Manifest", func() {
+	It("writes the test and its artifacts as JSON", func() {
+		test := &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example-test",
+				Namespace: "example-namespace",
+				UID:       "1234",
+			},
+		}
+		manifest := NewManifest(test)
+		manifest.Add(&Artifact{
+			Kind: ResultKind,
+			Name: "scenario_result.json",
+			URI:  "gs://example-bucket/scenario_result.json",
+		})
+
+		var buf bytes.Buffer
+		Expect(manifest.Write(&buf)).To(Succeed())
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("namespace", "example-namespace"))
+		Expect(decoded).To(HaveKeyWithValue("name", "example-test"))
+		Expect(decoded).To(HaveKeyWithValue("uid", "1234"))
+		Expect(decoded["artifacts"]).To(ConsistOf(map[string]interface{}{
+			"kind": "results",
+			"name": "scenario_result.json",
+			"uri":  "gs://example-bucket/scenario_result.json",
+		}))
+	})
+
+	It("writes an empty list without artifacts", func() {
+		var buf bytes.Buffer
+		Expect(NewManifest(&grpcv1.LoadTest{}).Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`"artifacts": []`))
+	})
+})
+
+var _ = Describe("RetentionLifecycle", func() {
+	var storageClassRule gcs.LifecycleRule
+
+	BeforeEach(func() {
+		storageClassRule = gcs.LifecycleRule{
+			Action: gcs.LifecycleAction{
+				Type:         gcs.SetStorageClassAction,
+				StorageClass: "NEARLINE",
+			},
+			Condition: gcs.LifecycleCondition{AgeInDays: 7},
+		}
+	})
+
+	It("adds a rule that deletes objects after the retention", func() {
+		lifecycle := RetentionLifecycle(gcs.Lifecycle{}, 30)
+		Expect(lifecycle.Rules).To(ConsistOf(gcs.LifecycleRule{
+			Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+			Condition: gcs.LifecycleCondition{AgeInDays: 30},
+		}))
+	})
+
+	It("replaces an existing retention rule and keeps other rules", func() {
+		lifecycle := RetentionLifecycle(gcs.Lifecycle{
+			Rules: []gcs.LifecycleRule{
+				storageClassRule,
+				{
+					Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+					Condition: gcs.LifecycleCondition{AgeInDays: 90},
+				},
+			},
+		}, 30)
+		Expect(lifecycle.Rules).To(ConsistOf(storageClassRule, gcs.LifecycleRule{
+			Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+			Condition: gcs.LifecycleCondition{AgeInDays: 30},
+		}))
+	})
+
+	It("keeps delete rules with other conditions", func() {
+		versionRule := gcs.LifecycleRule{
+			Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+			Condition: gcs.LifecycleCondition{AgeInDays: 1, NumNewerVersions: 3},
+		}
+		lifecycle := RetentionLifecycle(gcs.Lifecycle{Rules: []gcs.LifecycleRule{versionRule}}, 0)
+		Expect(lifecycle.Rules).To(ConsistOf(versionRule))
+	})
+
+	It("removes the retention rule when the retention is zero", func() {
+		lifecycle := RetentionLifecycle(gcs.Lifecycle{
+			Rules: []gcs.LifecycleRule{{
+				Action:    gcs.LifecycleAction{Type: gcs.DeleteAction},
+				Condition: gcs.LifecycleCondition{AgeInDays: 90},
+			}},
+		}, 0)
+		Expect(lifecycle.Rules).To(BeEmpty())
+	})
+})
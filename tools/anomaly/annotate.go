@@ -0,0 +1,103 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// ScenarioName returns the name of the scenario of a test. It is read from the
+// scenario annotation, which the runner sets on each test, or from the
+// ScenariosJSON of tests with a single scenario. An empty string is returned
+// if the name cannot be found.
+func ScenarioName(test *grpcv1.LoadTest) string {
+	if name := test.Annotations["scenario"]; name != "" {
+		return name
+	}
+	var jsonScenarioMap map[string]struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(test.Spec.ScenariosJSON), &jsonScenarioMap); err != nil {
+		return ""
+	}
+	return jsonScenarioMap["scenarios"].Name
+}
+
+// Annotate sets the anomaly label and the anomaly metrics annotation on a test
+// with anomalous metrics, and removes them from a test without any. It
+// returns true if the test was changed.
+func Annotate(test *grpcv1.LoadTest, findings []Finding) bool {
+	if len(findings) == 0 {
+		_, labeled := test.Labels[config.AnomalyLabel]
+		_, annotated := test.Annotations[config.AnomalyMetricsAnnotation]
+		delete(test.Labels, config.AnomalyLabel)
+		delete(test.Annotations, config.AnomalyMetricsAnnotation)
+		return labeled || annotated
+	}
+
+	var metrics []string
+	for _, finding := range findings {
+		metrics = append(metrics, fmt.Sprintf("%s:%.2f", finding.Metric, finding.Score))
+	}
+	annotation := strings.Join(metrics, ",")
+	if test.Labels[config.AnomalyLabel] == "true" && test.Annotations[config.AnomalyMetricsAnnotation] == annotation {
+		return false
+	}
+
+	if test.Labels == nil {
+		test.Labels = make(map[string]string)
+	}
+	test.Labels[config.AnomalyLabel] = "true"
+	if test.Annotations == nil {
+		test.Annotations = make(map[string]string)
+	}
+	test.Annotations[config.AnomalyMetricsAnnotation] = annotation
+	return true
+}
+
+// Rows returns a row for each anomalous metric of a test, to be inserted into
+// a table of anomalies. The rows are joined with the rows of results by the
+// name of the scenario and the name of the test. Infinite scores, which
+// cannot be inserted, are left empty.
+func Rows(test *grpcv1.LoadTest, scenario string, method Method, findings []Finding, created time.Time) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for _, finding := range findings {
+		var score interface{} = finding.Score
+		if math.IsInf(finding.Score, 0) {
+			score = nil
+		}
+		rows = append(rows, map[string]interface{}{
+			"created":   created.UTC().Format(time.RFC3339),
+			"testName":  test.Name,
+			"namespace": test.Namespace,
+			"scenario":  scenario,
+			"metric":    finding.Metric,
+			"value":     finding.Value,
+			"score":     score,
+			"samples":   finding.Samples,
+			"method":    string(method),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = Describe("Annotate", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		}
+	})
+
+	It("labels a test with the anomalous metrics", func() {
+		findings := []Finding{
+			{Metric: "qps", Score: -3.8},
+			{Metric: "latency50", Score: 4.214},
+		}
+		Expect(Annotate(test, findings)).To(BeTrue())
+		Expect(test.Labels).To(HaveKeyWithValue(config.AnomalyLabel, "true"))
+		Expect(test.Annotations).To(HaveKeyWithValue(config.AnomalyMetricsAnnotation, "qps:-3.80,latency50:4.21"))
+
+		Expect(Annotate(test, findings)).To(BeFalse())
+	})
+
+	It("removes the label from a test without anomalies", func() {
+		Expect(Annotate(test, nil)).To(BeFalse())
+
+		Annotate(test, []Finding{{Metric: "qps", Score: 5}})
+		Expect(Annotate(test, nil)).To(BeTrue())
+		Expect(test.Labels).ToNot(HaveKey(config.AnomalyLabel))
+		Expect(test.Annotations).ToNot(HaveKey(config.AnomalyMetricsAnnotation))
+	})
+
+	It("returns a row for each anomalous metric", func() {
+		created := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+		rows := Rows(test, "scenario", ZScore, []Finding{
+			{Metric: "qps", Value: 10, Score: -4, Samples: 30},
+			{Metric: "latency50", Value: 20, Score: math.Inf(1), Samples: 30},
+		}, created)
+		Expect(rows).To(HaveLen(2))
+		Expect(rows[0]).To(HaveKeyWithValue("created", "2022-06-01T12:00:00Z"))
+		Expect(rows[0]).To(HaveKeyWithValue("testName", "test"))
+		Expect(rows[0]).To(HaveKeyWithValue("metric", "qps"))
+		Expect(rows[0]).To(HaveKeyWithValue("score", -4.0))
+		Expect(rows[0]).To(HaveKeyWithValue("method", "zscore"))
+		Expect(rows[1]["score"]).To(BeNil())
+	})
+})
+
+var _ = Describe("ScenarioName", func() {
+	It("reads the name from the scenario annotation or the scenarios", func() {
+		test := &grpcv1.LoadTest{}
+		test.Spec.ScenariosJSON = `{"scenarios": {"name": "from-json"}}`
+		Expect(ScenarioName(test)).To(Equal("from-json"))
+
+		test.Annotations = map[string]string{"scenario": "from-annotation"}
+		Expect(ScenarioName(test)).To(Equal("from-annotation"))
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// Method is a method for deciding whether a value is anomalous.
+type Method string
+
+const (
+	// ZScore flags values that are more than a threshold of standard
+	// deviations away from the mean of the window.
+	ZScore Method = "zscore"
+
+	// IQR flags values that are more than a threshold of interquartile
+	// ranges below the first quartile or above the third quartile of the
+	// window. It is less sensitive to outliers within the window.
+	IQR Method = "iqr"
+)
+
+// DefaultThreshold returns the threshold that is commonly used with a method.
+func DefaultThreshold(method Method) float64 {
+	if method == IQR {
+		return 1.5
+	}
+	return 3
+}
+
+// Metrics lists the metrics of a result summary that are checked, in order.
+var Metrics = []string{"qps", "latency50", "latency99", "latency999", "clientSystemTime", "serverSystemTime"}
+
+// SummaryValues returns the values of the metrics in a result summary. Metrics
+// that are missing or not numbers are omitted.
+func SummaryValues(summary *grpcv1.ResultSummary) map[string]float64 {
+	values := make(map[string]float64)
+	if summary == nil {
+		return values
+	}
+	fields := map[string]string{
+		"qps":              summary.QPS,
+		"latency50":        summary.Latency50,
+		"latency99":        summary.Latency99,
+		"latency999":       summary.Latency999,
+		"clientSystemTime": summary.ClientSystemTime,
+		"serverSystemTime": summary.ServerSystemTime,
+	}
+	for metric, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		values[metric] = value
+	}
+	return values
+}
+
+// Detector decides whether values are anomalous.
+type Detector struct {
+	// Method is the method used to score values.
+	Method Method
+
+	// Threshold is the score above which a value is anomalous.
+	Threshold float64
+
+	// MinSamples is the smallest window that a value is compared with.
+	// Values with a smaller window are never anomalous.
+	MinSamples int
+}
+
+// Validate returns an error if the detector is not configured correctly.
+func (d *Detector) Validate() error {
+	if d.Method != ZScore && d.Method != IQR {
+		return fmt.Errorf("unknown method %q", d.Method)
+	}
+	if d.Threshold <= 0 {
+		return fmt.Errorf("threshold must be positive")
+	}
+	if d.MinSamples < 2 {
+		return fmt.Errorf("minimum number of samples must be at least 2")
+	}
+	return nil
+}
+
+// Score returns the score of a value compared with a window of earlier
+// values, and whether the value is anomalous. The score is negative when the
+// value is below the window. With the z-score method, it is the number of
+// standard deviations from the mean. With the IQR method, it is the number of
+// interquartile ranges outside the range between the quartiles, and zero
+// within it. A value that differs from a window without spread has an
+// infinite score.
+func (d *Detector) Score(window []float64, value float64) (float64, bool) {
+	if len(window) < d.MinSamples || len(window) == 0 {
+		return 0, false
+	}
+
+	var score float64
+	switch d.Method {
+	case IQR:
+		sorted := append([]float64(nil), window...)
+		sort.Float64s(sorted)
+		q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+		var distance float64
+		if value < q1 {
+			distance = value - q1
+		} else if value > q3 {
+			distance = value - q3
+		}
+		score = spreadScore(distance, q3-q1)
+	default:
+		var mean float64
+		for _, v := range window {
+			mean += v
+		}
+		mean /= float64(len(window))
+		var variance float64
+		for _, v := range window {
+			variance += (v - mean) * (v - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(window)))
+		score = spreadScore(value-mean, stddev)
+	}
+	return score, math.Abs(score) > d.Threshold
+}
+
+// spreadScore divides a distance by a spread, returning an infinite score
+// when the spread is zero and the distance is not.
+func spreadScore(distance, spread float64) float64 {
+	if spread == 0 {
+		if distance == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), distance)
+	}
+	return distance / spread
+}
+
+// quantile returns a quantile of sorted values, interpolating linearly
+// between the closest values.
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	fraction := position - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*fraction
+}
+
+// Finding describes an anomalous metric.
+type Finding struct {
+	// Metric is the name of the metric.
+	Metric string
+
+	// Value is the value of the metric in the test.
+	Value float64
+
+	// Score is the score of the value.
+	Score float64
+
+	// Samples is the number of earlier values the value was compared with.
+	Samples int
+}
+
+// Detect compares the value of each metric with a window of earlier values of
+// the same metric, and returns the anomalous metrics in the order of Metrics.
+func (d *Detector) Detect(values map[string]float64, windows map[string][]float64) []Finding {
+	var findings []Finding
+	for _, metric := range Metrics {
+		value, ok := values[metric]
+		if !ok {
+			continue
+		}
+		window := windows[metric]
+		if score, anomalous := d.Score(window, value); anomalous {
+			findings = append(findings, Finding{
+				Metric:  metric,
+				Value:   value,
+				Score:   score,
+				Samples: len(window),
+			})
+		}
+	}
+	return findings
+}
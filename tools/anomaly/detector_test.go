@@ -0,0 +1,123 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// fakeHistory returns the same window for every metric of a scenario.
+type fakeHistory struct {
+	windows map[string][]float64
+	sizes   []int
+}
+
+// Window implements the History interface.
+func (h *fakeHistory) Window(ctx context.Context, scenario, metric string, before time.Time, size int) ([]float64, error) {
+	h.sizes = append(h.sizes, size)
+	return h.windows[metric], nil
+}
+
+var _ = Describe("Detector", func() {
+	window := []float64{98, 99, 100, 100, 101, 102, 100, 99, 101, 100}
+
+	Describe("Score", func() {
+		It("flags values far from the mean with the z-score method", func() {
+			detector := &Detector{Method: ZScore, Threshold: 3, MinSamples: 5}
+
+			score, anomalous := detector.Score(window, 100.5)
+			Expect(anomalous).To(BeFalse())
+			Expect(score).To(BeNumerically("~", 0.46, 0.01))
+
+			score, anomalous = detector.Score(window, 90)
+			Expect(anomalous).To(BeTrue())
+			Expect(score).To(BeNumerically("<", -3))
+		})
+
+		It("flags values outside the quartiles with the IQR method", func() {
+			detector := &Detector{Method: IQR, Threshold: 1.5, MinSamples: 5}
+
+			score, anomalous := detector.Score(window, 100.5)
+			Expect(anomalous).To(BeFalse())
+			Expect(score).To(BeZero())
+
+			score, anomalous = detector.Score(window, 104)
+			Expect(anomalous).To(BeTrue())
+			Expect(score).To(BeNumerically("~", 2.17, 0.01))
+		})
+
+		It("does not score values with too few samples", func() {
+			detector := &Detector{Method: ZScore, Threshold: 3, MinSamples: 20}
+			_, anomalous := detector.Score(window, 1000)
+			Expect(anomalous).To(BeFalse())
+		})
+
+		It("flags any change from a window without spread", func() {
+			detector := &Detector{Method: ZScore, Threshold: 3, MinSamples: 2}
+
+			score, anomalous := detector.Score([]float64{5, 5, 5}, 5)
+			Expect(anomalous).To(BeFalse())
+			Expect(score).To(BeZero())
+
+			score, anomalous = detector.Score([]float64{5, 5, 5}, 6)
+			Expect(anomalous).To(BeTrue())
+			Expect(math.IsInf(score, 1)).To(BeTrue())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("rejects unknown methods and invalid settings", func() {
+			Expect((&Detector{Method: ZScore, Threshold: 3, MinSamples: 10}).Validate()).To(Succeed())
+			Expect((&Detector{Method: "mad", Threshold: 3, MinSamples: 10}).Validate()).ToNot(Succeed())
+			Expect((&Detector{Method: IQR, Threshold: 0, MinSamples: 10}).Validate()).ToNot(Succeed())
+			Expect((&Detector{Method: IQR, Threshold: 1.5, MinSamples: 1}).Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Detect", func() {
+		It("returns the anomalous metrics read from the history", func() {
+			detector := &Detector{Method: ZScore, Threshold: 3, MinSamples: 5}
+			values := SummaryValues(&grpcv1.ResultSummary{
+				QPS:       "100",
+				Latency50: "120",
+				Latency99: "not a number",
+			})
+			Expect(values).To(HaveLen(2))
+
+			history := &fakeHistory{windows: map[string][]float64{
+				"qps":       window,
+				"latency50": window,
+			}}
+			windows, err := Windows(context.Background(), history, "scenario", values, time.Now(), 30)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(history.sizes).To(Equal([]int{30, 30}))
+
+			findings := detector.Detect(values, windows)
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Metric).To(Equal("latency50"))
+			Expect(findings[0].Value).To(Equal(120.0))
+			Expect(findings[0].Samples).To(Equal(len(window)))
+		})
+	})
+})
@@ -0,0 +1,23 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package anomaly detects load tests whose results deviate from the results of
+// previous runs of the same scenario. The results of each test are compared
+// with a rolling window of earlier results read from BigQuery, using a z-score
+// or an interquartile range. Anomalous tests are labeled, and each anomalous
+// metric is recorded in a table, so that dashboards and alerts can pick up
+// regressions as soon as a test completes.
+package anomaly
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+
+	"github.com/grpc/test-infra/tools/bqupload"
+)
+
+// History returns earlier values of the metrics of a scenario.
+type History interface {
+	// Window returns the values of a metric in the latest results of a
+	// scenario that were created before a given time, newest first. At
+	// most size values are returned.
+	Window(ctx context.Context, scenario, metric string, before time.Time, size int) ([]float64, error)
+}
+
+// BigQueryHistory reads earlier values from a table of results uploaded by
+// the driver, where each row has the name of its scenario in scenario.name,
+// its creation time in metadata.created and its metrics in summary.
+type BigQueryHistory struct {
+	// Client is the client used to run queries.
+	Client *bigquery.Client
+
+	// Table is the table of results.
+	Table bqupload.TableID
+}
+
+// Window implements the History interface.
+func (h *BigQueryHistory) Window(ctx context.Context, scenario, metric string, before time.Time, size int) ([]float64, error) {
+	if !knownMetric(metric) {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	// The metric cannot be passed as a parameter, so it is checked against
+	// the known metrics above before it is added to the query.
+	query := h.Client.Query(fmt.Sprintf(
+		"SELECT SAFE_CAST(summary.%[1]s AS FLOAT64) AS value "+
+			"FROM `%[2]s.%[3]s.%[4]s` "+
+			"WHERE scenario.name = @scenario "+
+			"AND SAFE_CAST(metadata.created AS TIMESTAMP) < @before "+
+			"AND SAFE_CAST(summary.%[1]s AS FLOAT64) IS NOT NULL "+
+			"ORDER BY SAFE_CAST(metadata.created AS TIMESTAMP) DESC "+
+			"LIMIT @size",
+		metric, h.Table.Project, h.Table.Dataset, h.Table.Table))
+	query.Parameters = []bigquery.QueryParameter{
+		{Name: "scenario", Value: scenario},
+		{Name: "before", Value: before},
+		{Name: "size", Value: size},
+	}
+
+	rows, err := query.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s of scenario %q: %v", metric, scenario, err)
+	}
+	var values []float64
+	for {
+		var row struct {
+			Value float64 `bigquery:"value"`
+		}
+		err := rows.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of scenario %q: %v", metric, scenario, err)
+		}
+		values = append(values, row.Value)
+	}
+	return values, nil
+}
+
+// knownMetric returns true if a metric is one of Metrics.
+func knownMetric(metric string) bool {
+	for _, known := range Metrics {
+		if metric == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows reads a window of earlier values for each metric that has a value.
+func Windows(ctx context.Context, history History, scenario string, values map[string]float64, before time.Time, size int) (map[string][]float64, error) {
+	windows := make(map[string][]float64)
+	for _, metric := range Metrics {
+		if _, ok := values[metric]; !ok {
+			continue
+		}
+		window, err := history.Window(ctx, scenario, metric, before, size)
+		if err != nil {
+			return nil, err
+		}
+		windows[metric] = window
+	}
+	return windows, nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	container "google.golang.org/api/container/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodePoolService lists and creates the node pools of a cluster.
+type NodePoolService interface {
+	// List returns the node pools of the cluster.
+	List(ctx context.Context) ([]*container.NodePool, error)
+
+	// Create creates a node pool in the cluster, and returns once the node
+	// pool has been created or the creation has failed.
+	Create(ctx context.Context, nodePool *container.NodePool) error
+}
+
+// GKENodePoolService manages the node pools of a GKE cluster through the GKE
+// API.
+type GKENodePoolService struct {
+	service *container.Service
+
+	// cluster is the full name of the cluster, in the form
+	// projects/<project>/locations/<location>/clusters/<cluster>.
+	cluster string
+
+	// parent is the full name of the location of the cluster, which owns
+	// the operations of the cluster.
+	parent string
+
+	// pollInterval is the time between checks of the status of an
+	// operation.
+	pollInterval time.Duration
+}
+
+// NewGKENodePoolService creates a service that manages the node pools of a GKE
+// cluster with Application Default Credentials.
+func NewGKENodePoolService(ctx context.Context, project, location, cluster string) (*GKENodePoolService, error) {
+	service, err := container.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GKE client: %v", err)
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", project, location)
+	return &GKENodePoolService{
+		service:      service,
+		cluster:      fmt.Sprintf("%s/clusters/%s", parent, cluster),
+		parent:       parent,
+		pollInterval: 10 * time.Second,
+	}, nil
+}
+
+// List implements the NodePoolService interface.
+func (s *GKENodePoolService) List(ctx context.Context) ([]*container.NodePool, error) {
+	response, err := s.service.Projects.Locations.Clusters.NodePools.List(s.cluster).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node pools of cluster %s: %v", s.cluster, err)
+	}
+	return response.NodePools, nil
+}
+
+// Create implements the NodePoolService interface.
+func (s *GKENodePoolService) Create(ctx context.Context, nodePool *container.NodePool) error {
+	op, err := s.service.Projects.Locations.Clusters.NodePools.Create(s.cluster, &container.CreateNodePoolRequest{
+		NodePool: nodePool,
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to create node pool %s: %v", nodePool.Name, err)
+	}
+
+	for op.Status != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+		op, err = s.service.Projects.Locations.Operations.Get(fmt.Sprintf("%s/operations/%s", s.parent, op.Name)).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to check creation of node pool %s: %v", nodePool.Name, err)
+		}
+	}
+	if op.Error != nil {
+		return fmt.Errorf("failed to create node pool %s: %s", nodePool.Name, op.Error.Message)
+	}
+	return nil
+}
+
+// gkeTaintEffects maps the effects of Kubernetes taints to the effects of
+// taints in the GKE API.
+var gkeTaintEffects = map[corev1.TaintEffect]string{
+	corev1.TaintEffectNoSchedule:       "NO_SCHEDULE",
+	corev1.TaintEffectPreferNoSchedule: "PREFER_NO_SCHEDULE",
+	corev1.TaintEffectNoExecute:        "NO_EXECUTE",
+}
+
+// NodePool returns the GKE node pool for the pool.
+func (p *PoolSpec) NodePool() *container.NodePool {
+	var taints []*container.NodeTaint
+	for _, taint := range p.Taints {
+		taints = append(taints, &container.NodeTaint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: gkeTaintEffects[taint.Effect],
+		})
+	}
+	return &container.NodePool{
+		Name:             p.Name,
+		InitialNodeCount: int64(p.NodeCount),
+		Config: &container.NodeConfig{
+			MachineType: p.MachineType,
+			Labels:      p.Labels(),
+			Taints:      taints,
+		},
+	}
+}
+
+// checkNodePool returns an error if an existing node pool does not match the
+// machine type, labels and taints of the pool.
+func (p *PoolSpec) checkNodePool(existing *container.NodePool) error {
+	expected := p.NodePool().Config
+	actual := existing.Config
+	if actual == nil {
+		actual = &container.NodeConfig{}
+	}
+	if actual.MachineType != expected.MachineType {
+		return fmt.Errorf("node pool %s has machine type %s instead of %s", p.Name, actual.MachineType, expected.MachineType)
+	}
+	for key, value := range expected.Labels {
+		if actual.Labels[key] != value {
+			return fmt.Errorf("node pool %s does not have label %s:%s", p.Name, key, value)
+		}
+	}
+	if len(actual.Taints) != len(expected.Taints) {
+		return fmt.Errorf("node pool %s has %d taint(s) instead of %d", p.Name, len(actual.Taints), len(expected.Taints))
+	}
+	for i, taint := range expected.Taints {
+		other := actual.Taints[i]
+		if other.Key != taint.Key || other.Value != taint.Value || other.Effect != taint.Effect {
+			return fmt.Errorf("node pool %s does not have taint %s=%s:%s", p.Name, taint.Key, taint.Value, taint.Effect)
+		}
+	}
+	return nil
+}
+
+// Provision creates the pools that do not exist in the cluster. Pools that
+// already exist are not changed, but an error is returned if their machine
+// type, labels or taints differ from the specification, since tests could not
+// rely on them. Pools are created one at a time, since GKE does not allow
+// concurrent operations on a cluster.
+func Provision(ctx context.Context, service NodePoolService, pools []PoolSpec) error {
+	existing, err := service.List(ctx)
+	if err != nil {
+		return err
+	}
+	existingByName := make(map[string]*container.NodePool)
+	for _, nodePool := range existing {
+		existingByName[nodePool.Name] = nodePool
+	}
+
+	for i := range pools {
+		pool := &pools[i]
+		if nodePool, ok := existingByName[pool.Name]; ok {
+			if err := pool.checkNodePool(nodePool); err != nil {
+				return err
+			}
+			log.Printf("Node pool %s already exists", pool.Name)
+			continue
+		}
+
+		log.Printf("Creating node pool %s with %d %s node(s)", pool.Name, pool.NodeCount, pool.MachineType)
+		if err := service.Create(ctx, pool.NodePool()); err != nil {
+			return err
+		}
+		log.Printf("Created node pool %s", pool.Name)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// DefaultNodeCount is the number of nodes in each default pool.
+const DefaultNodeCount = 8
+
+// PoolSpec describes a node pool that runs load tests.
+type PoolSpec struct {
+	// Name is the name of the node pool, which is also the value of the
+	// pool label on its nodes.
+	Name string
+
+	// Purpose is a human legible description of what the pool is used for.
+	Purpose string
+
+	// MachineType is the type of machine of the nodes in the pool.
+	MachineType string
+
+	// NodeCount is the number of nodes in the pool.
+	NodeCount int
+
+	// DefaultFor lists the roles (client, driver or server) of components
+	// that run in the pool when they do not specify a pool. The default
+	// pool label of each role is set on the nodes of the pool.
+	DefaultFor []string
+
+	// Taints lists the taints of the nodes in the pool. The pods of load
+	// tests do not tolerate taints, so pools that run load tests should
+	// not have taints with the NoSchedule or NoExecute effect.
+	Taints []corev1.Taint
+}
+
+// DefaultPools returns the pools for ad hoc tests described in the deployment
+// documentation: a pool for drivers and pools of workers on 8-core and 32-core
+// machines, each with the given number of nodes. The drivers pool is the
+// default for drivers, and the 8-core pool is the default for clients and
+// servers.
+func DefaultPools(nodeCount int) []PoolSpec {
+	return []PoolSpec{
+		{
+			Name:        "drivers",
+			Purpose:     "Drivers for ad hoc tests.",
+			MachineType: "e2-standard-8",
+			NodeCount:   nodeCount,
+			DefaultFor:  []string{config.DriverRole},
+		},
+		{
+			Name:        "workers-8core",
+			Purpose:     "Workers for ad hoc tests on 8-core machines.",
+			MachineType: "e2-standard-8",
+			NodeCount:   nodeCount,
+			DefaultFor:  []string{config.ClientRole, config.ServerRole},
+		},
+		{
+			Name:        "workers-32core",
+			Purpose:     "Workers for ad hoc tests on 32-core machines.",
+			MachineType: "e2-standard-32",
+			NodeCount:   nodeCount,
+		},
+	}
+}
+
+// defaultPoolLabels maps each role to the label that marks the default pool
+// for the role, as in the default pool labels of the controller
+// configuration template.
+var defaultPoolLabels = map[string]string{
+	config.ClientRole: "default-client-pool",
+	config.DriverRole: "default-driver-pool",
+	config.ServerRole: "default-server-pool",
+}
+
+// Labels returns the Kubernetes labels of the nodes in the pool: the pool
+// label, and the default pool label of each role that the pool is the default
+// for.
+func (p *PoolSpec) Labels() map[string]string {
+	labels := map[string]string{
+		config.PoolLabel: p.Name,
+	}
+	for _, role := range p.DefaultFor {
+		labels[defaultPoolLabels[role]] = "true"
+	}
+	return labels
+}
+
+// validate returns an error if the pool is incomplete or is the default for
+// an unknown role.
+func (p *PoolSpec) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("pool is unnamed")
+	}
+	if p.MachineType == "" {
+		return fmt.Errorf("pool %q has no machine type", p.Name)
+	}
+	if p.NodeCount < 1 {
+		return fmt.Errorf("pool %q must have at least one node", p.Name)
+	}
+	for _, role := range p.DefaultFor {
+		if _, ok := defaultPoolLabels[role]; !ok {
+			return fmt.Errorf("pool %q is the default for unknown role %q", p.Name, role)
+		}
+	}
+	return nil
+}
+
+// ValidatePools returns an error if a pool is invalid, if two pools have the
+// same name, or if two pools are the default for the same role.
+func ValidatePools(pools []PoolSpec) error {
+	names := make(map[string]bool)
+	defaults := make(map[string]string)
+	for i := range pools {
+		pool := &pools[i]
+		if err := pool.validate(); err != nil {
+			return err
+		}
+		if names[pool.Name] {
+			return fmt.Errorf("pool %q is declared more than once", pool.Name)
+		}
+		names[pool.Name] = true
+		for _, role := range pool.DefaultFor {
+			if other, ok := defaults[role]; ok {
+				return fmt.Errorf("pools %q and %q are both the default for role %q", other, pool.Name, role)
+			}
+			defaults[role] = pool.Name
+		}
+	}
+	return nil
+}
+
+// NodePools returns the declarations of the pools for the controller
+// configuration, sorted by name.
+func NodePools(pools []PoolSpec) []config.NodePool {
+	var nodePools []config.NodePool
+	for _, pool := range pools {
+		nodePools = append(nodePools, config.NodePool{
+			Name:        pool.Name,
+			Purpose:     pool.Purpose,
+			MachineType: pool.MachineType,
+			Capacity:    pool.NodeCount,
+			DefaultFor:  pool.DefaultFor,
+		})
+	}
+	sort.Slice(nodePools, func(i, j int) bool {
+		return nodePools[i].Name < nodePools[j].Name
+	})
+	return nodePools
+}
+
+// WriteDefaultsFragment writes a fragment of the controller configuration
+// that declares the pools. The fragment can be used as an overlay of the
+// defaults file, or pasted into it.
+func WriteDefaultsFragment(w io.Writer, pools []PoolSpec) error {
+	fragment := struct {
+		NodePools []config.NodePool `json:"nodePools"`
+	}{
+		NodePools: NodePools(pools),
+	}
+	output, err := yaml.Marshal(fragment)
+	if err != nil {
+		return fmt.Errorf("failed to encode defaults fragment: %v", err)
+	}
+	_, err = w.Write(output)
+	return err
+}
+
+// SelectPools returns the pools with the given names, in the order of the
+// names. An error is returned if a name does not match any pool.
+func SelectPools(pools []PoolSpec, names []string) ([]PoolSpec, error) {
+	var selected []PoolSpec
+	for _, name := range names {
+		found := false
+		for _, pool := range pools {
+			if pool.Name == name {
+				selected = append(selected, pool)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown pool %q", name)
+		}
+	}
+	return selected, nil
+}
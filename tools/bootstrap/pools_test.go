@@ -0,0 +1,162 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	container "google.golang.org/api/container/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// fakeNodePoolService records the node pools that are created.
+type fakeNodePoolService struct {
+	existing  []*container.NodePool
+	created   []*container.NodePool
+	createErr error
+}
+
+func (s *fakeNodePoolService) List(ctx context.Context) ([]*container.NodePool, error) {
+	return s.existing, nil
+}
+
+func (s *fakeNodePoolService) Create(ctx context.Context, nodePool *container.NodePool) error {
+	if s.createErr != nil {
+		return s.createErr
+	}
+	s.created = append(s.created, nodePool)
+	return nil
+}
+
+var _ = Describe("DefaultPools", func() {
+	It("returns valid pools with the default pool labels", func() {
+		pools := DefaultPools(4)
+		Expect(ValidatePools(pools)).To(Succeed())
+		Expect(pools).To(HaveLen(3))
+
+		Expect(pools[0].Labels()).To(Equal(map[string]string{
+			config.PoolLabel:      "drivers",
+			"default-driver-pool": "true",
+		}))
+		Expect(pools[1].Labels()).To(Equal(map[string]string{
+			config.PoolLabel:      "workers-8core",
+			"default-client-pool": "true",
+			"default-server-pool": "true",
+		}))
+		Expect(pools[2].Labels()).To(Equal(map[string]string{
+			config.PoolLabel: "workers-32core",
+		}))
+	})
+})
+
+var _ = Describe("ValidatePools", func() {
+	It("returns an error when two pools are the default for a role", func() {
+		pools := DefaultPools(4)
+		pools[2].DefaultFor = []string{config.ClientRole}
+		Expect(ValidatePools(pools)).ToNot(Succeed())
+	})
+
+	It("returns an error when two pools have the same name", func() {
+		pools := DefaultPools(4)
+		pools[2].Name = pools[1].Name
+		pools[2].DefaultFor = nil
+		Expect(ValidatePools(pools)).ToNot(Succeed())
+	})
+
+	It("returns an error when a pool has no nodes", func() {
+		Expect(ValidatePools(DefaultPools(0))).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("SelectPools", func() {
+	It("returns the named pools", func() {
+		pools, err := SelectPools(DefaultPools(4), []string{"workers-32core", "drivers"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pools).To(HaveLen(2))
+		Expect(pools[0].Name).To(Equal("workers-32core"))
+		Expect(pools[1].Name).To(Equal("drivers"))
+	})
+
+	It("returns an error for an unknown pool", func() {
+		_, err := SelectPools(DefaultPools(4), []string{"workers-64core"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("WriteDefaultsFragment", func() {
+	It("declares the pools in the controller configuration", func() {
+		var buf bytes.Buffer
+		Expect(WriteDefaultsFragment(&buf, DefaultPools(4))).To(Succeed())
+
+		defaults := new(config.Defaults)
+		Expect(yaml.Unmarshal(buf.Bytes(), defaults)).To(Succeed())
+		Expect(defaults.NodePools).To(HaveLen(3))
+		Expect(defaults.DefaultNodePoolName(config.DriverRole)).To(Equal("drivers"))
+		Expect(defaults.DefaultNodePoolName(config.ClientRole)).To(Equal("workers-8core"))
+		Expect(defaults.NodePoolForName("workers-32core").Capacity).To(Equal(4))
+	})
+})
+
+var _ = Describe("Provision", func() {
+	It("creates the pools that do not exist", func() {
+		pools := DefaultPools(4)
+		service := &fakeNodePoolService{
+			existing: []*container.NodePool{pools[0].NodePool()},
+		}
+
+		Expect(Provision(context.Background(), service, pools)).To(Succeed())
+		Expect(service.created).To(HaveLen(2))
+		Expect(service.created[0].Name).To(Equal("workers-8core"))
+		Expect(service.created[0].InitialNodeCount).To(Equal(int64(4)))
+		Expect(service.created[0].Config.MachineType).To(Equal("e2-standard-8"))
+		Expect(service.created[0].Config.Labels).To(HaveKeyWithValue(config.PoolLabel, "workers-8core"))
+	})
+
+	It("sets the taints of the pools", func() {
+		pools := DefaultPools(4)[:1]
+		pools[0].Taints = []corev1.Taint{{Key: "dedicated", Value: "drivers", Effect: corev1.TaintEffectNoSchedule}}
+		service := &fakeNodePoolService{}
+
+		Expect(Provision(context.Background(), service, pools)).To(Succeed())
+		Expect(service.created[0].Config.Taints).To(ConsistOf(&container.NodeTaint{
+			Key:    "dedicated",
+			Value:  "drivers",
+			Effect: "NO_SCHEDULE",
+		}))
+	})
+
+	It("returns an error when an existing pool differs from its specification", func() {
+		pools := DefaultPools(4)
+		existing := pools[1].NodePool()
+		existing.Config.MachineType = "e2-standard-4"
+		service := &fakeNodePoolService{existing: []*container.NodePool{existing}}
+
+		Expect(Provision(context.Background(), service, pools)).ToNot(Succeed())
+	})
+
+	It("returns an error when a pool cannot be created", func() {
+		service := &fakeNodePoolService{createErr: errors.New("quota exceeded")}
+		Expect(Provision(context.Background(), service, DefaultPools(4))).ToNot(Succeed())
+	})
+})
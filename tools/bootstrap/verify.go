@@ -0,0 +1,113 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// CheckNodes returns the problems that prevent the nodes of the pools from
+// running load tests: pools with fewer ready nodes than their node count,
+// nodes that are missing a label of their pool, and nodes with a taint that
+// the pool does not declare and that keeps pods from being scheduled. No
+// problem is returned when the nodes are ready.
+func CheckNodes(pools []PoolSpec, nodes []corev1.Node) []string {
+	var problems []string
+	for i := range pools {
+		pool := &pools[i]
+		declaredTaints := make(map[string]bool)
+		for _, taint := range pool.Taints {
+			declaredTaints[taint.ToString()] = true
+		}
+
+		readyCount := 0
+		for j := range nodes {
+			node := &nodes[j]
+			if node.Labels[config.PoolLabel] != pool.Name {
+				continue
+			}
+			if nodeReady(node) {
+				readyCount++
+			}
+			for key, value := range pool.Labels() {
+				if node.Labels[key] != value {
+					problems = append(problems, fmt.Sprintf("node %s of pool %s does not have label %s:%s", node.Name, pool.Name, key, value))
+				}
+			}
+			for _, taint := range node.Spec.Taints {
+				if taint.Effect == corev1.TaintEffectPreferNoSchedule || declaredTaints[taint.ToString()] {
+					continue
+				}
+				// Nodes that are not ready are tainted until they are,
+				// which is already reported by the count of ready nodes.
+				if strings.HasPrefix(taint.Key, "node.kubernetes.io/") {
+					continue
+				}
+				problems = append(problems, fmt.Sprintf("node %s of pool %s has undeclared taint %s", node.Name, pool.Name, taint.ToString()))
+			}
+		}
+		if readyCount < pool.NodeCount {
+			problems = append(problems, fmt.Sprintf("pool %s has %d ready node(s) instead of %d", pool.Name, readyCount, pool.NodeCount))
+		}
+	}
+	return problems
+}
+
+// nodeReady returns true if the node has a Ready condition that is true.
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Verify checks the nodes of the pools once per interval, until the nodes of
+// every pool are ready to run load tests or the context is done. An error
+// listing the remaining problems is returned if the context is done first.
+func Verify(ctx context.Context, nodesGetter corev1types.NodesGetter, pools []PoolSpec, interval time.Duration) error {
+	var problems []string
+	for {
+		nodes, err := nodesGetter.Nodes().List(ctx, metav1.ListOptions{LabelSelector: config.PoolLabel})
+		if err != nil {
+			problems = []string{fmt.Sprintf("failed to list nodes: %v", err)}
+		} else {
+			problems = CheckNodes(pools, nodes.Items)
+		}
+		if len(problems) == 0 {
+			return nil
+		}
+		log.Printf("Waiting for nodes: %s", problems[0])
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("nodes are not ready to run load tests: %s", strings.Join(problems, "; "))
+		case <-time.After(interval):
+		}
+	}
+}
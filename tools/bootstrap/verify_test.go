@@ -0,0 +1,113 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newNode returns a node with the labels of a pool.
+func newNode(name string, pool *PoolSpec, ready bool) corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: pool.Labels(),
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: status},
+			},
+		},
+	}
+}
+
+var _ = Describe("CheckNodes", func() {
+	var pools []PoolSpec
+
+	BeforeEach(func() {
+		pools = DefaultPools(2)[:1]
+	})
+
+	It("returns no problem when the nodes are ready", func() {
+		nodes := []corev1.Node{
+			newNode("node-a", &pools[0], true),
+			newNode("node-b", &pools[0], true),
+		}
+		Expect(CheckNodes(pools, nodes)).To(BeEmpty())
+	})
+
+	It("reports pools with too few ready nodes", func() {
+		nodes := []corev1.Node{
+			newNode("node-a", &pools[0], true),
+			newNode("node-b", &pools[0], false),
+		}
+		Expect(CheckNodes(pools, nodes)).To(ConsistOf(ContainSubstring("1 ready node(s) instead of 2")))
+	})
+
+	It("reports nodes without the default pool label", func() {
+		nodes := []corev1.Node{
+			newNode("node-a", &pools[0], true),
+			newNode("node-b", &pools[0], true),
+		}
+		delete(nodes[1].Labels, "default-driver-pool")
+		Expect(CheckNodes(pools, nodes)).To(ConsistOf(ContainSubstring("node node-b")))
+	})
+
+	It("reports undeclared taints that keep pods from being scheduled", func() {
+		nodes := []corev1.Node{
+			newNode("node-a", &pools[0], true),
+			newNode("node-b", &pools[0], true),
+		}
+		nodes[0].Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "other", Effect: corev1.TaintEffectNoSchedule}}
+		nodes[1].Spec.Taints = []corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectPreferNoSchedule}}
+		Expect(CheckNodes(pools, nodes)).To(ConsistOf(ContainSubstring("undeclared taint dedicated=other:NoSchedule")))
+
+		pools[0].Taints = nodes[0].Spec.Taints
+		Expect(CheckNodes(pools, nodes)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Verify", func() {
+	It("returns once the nodes are ready", func() {
+		pools := DefaultPools(1)[:1]
+		node := newNode("node-a", &pools[0], true)
+		clientset := fake.NewSimpleClientset(&node)
+
+		Expect(Verify(context.Background(), clientset.CoreV1(), pools, time.Millisecond)).To(Succeed())
+	})
+
+	It("returns an error with the problems when the context is done", func() {
+		pools := DefaultPools(1)[:1]
+		clientset := fake.NewSimpleClientset()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := Verify(ctx, clientset.CoreV1(), pools, time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("pool drivers has 0 ready node(s)")))
+	})
+})
@@ -0,0 +1,22 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bqupload uploads rows to BigQuery with streaming inserts. Rows are
+// validated against the schema of the table before they are inserted, and
+// inserted in batches. Batches that fail because of quota errors or transient
+// errors are retried with an exponential backoff, so that results are not
+// dropped during brief BigQuery outages.
+package bqupload
@@ -0,0 +1,378 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// MaxClusteringColumns is the largest number of columns a BigQuery table can
+// be clustered by.
+const MaxClusteringColumns = 4
+
+// columnNamePattern matches the names of top-level BigQuery columns.
+var columnNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// timestampLayouts lists the layouts accepted for timestamps given as
+// strings, in the order they are tried.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 MST",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Column is a top-level column of a table. BigQuery can only partition and
+// cluster tables by top-level columns, so the value of the column can be
+// copied from a nested field of each row, such as scenario.name.
+type Column struct {
+	// Name is the name of the column.
+	Name string
+
+	// Source is the field the value of the column is copied from, with the
+	// names of nested fields separated by dots. When it is empty, rows are
+	// expected to set the column themselves.
+	Source string
+}
+
+// ParseColumn parses a column in the form <name>[=<source>].
+func ParseColumn(s string) (Column, error) {
+	name, source, ok := strings.Cut(s, "=")
+	if ok && source == "" {
+		return Column{}, fmt.Errorf("column %q has an empty source", s)
+	}
+	column := Column{Name: name, Source: source}
+	if err := column.validate(); err != nil {
+		return Column{}, err
+	}
+	return column, nil
+}
+
+// String returns the column in the form accepted by ParseColumn.
+func (c Column) String() string {
+	if c.Source == "" {
+		return c.Name
+	}
+	return c.Name + "=" + c.Source
+}
+
+// validate checks the name and the source of the column.
+func (c Column) validate() error {
+	if !columnNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid column name %q", c.Name)
+	}
+	if c.Source != "" {
+		for _, part := range strings.Split(c.Source, ".") {
+			if !columnNamePattern.MatchString(part) {
+				return fmt.Errorf("invalid source %q for column %s", c.Source, c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Layout describes how a table is partitioned and clustered. Tables of
+// results are partitioned by day on a timestamp column and clustered by
+// columns such as the scenario name, so that queries which filter on these
+// columns only scan the matching partitions and blocks.
+type Layout struct {
+	// Partition is the TIMESTAMP column the table is partitioned by. The
+	// table is not partitioned when it is nil.
+	Partition *Column
+
+	// Clustering lists the STRING columns the table is clustered by, in
+	// order.
+	Clustering []Column
+
+	// RequirePartitionFilter rejects queries of the table that do not filter
+	// on the partition column.
+	RequirePartitionFilter bool
+}
+
+// IsZero returns true if the layout neither partitions nor clusters a table.
+func (l *Layout) IsZero() bool {
+	return l.Partition == nil && len(l.Clustering) == 0
+}
+
+// Validate checks that the columns of the layout are valid and distinct.
+func (l *Layout) Validate() error {
+	if len(l.Clustering) > MaxClusteringColumns {
+		return fmt.Errorf("tables can be clustered by at most %d columns, got %d", MaxClusteringColumns, len(l.Clustering))
+	}
+	if l.RequirePartitionFilter && l.Partition == nil {
+		return errors.New("a partition filter can only be required when the table is partitioned")
+	}
+	seen := make(map[string]bool)
+	for _, column := range l.columns() {
+		if err := column.validate(); err != nil {
+			return err
+		}
+		if seen[column.Name] {
+			return fmt.Errorf("column %s is used more than once", column.Name)
+		}
+		seen[column.Name] = true
+	}
+	return nil
+}
+
+// columns returns the partition column followed by the clustering columns.
+func (l *Layout) columns() []Column {
+	var columns []Column
+	if l.Partition != nil {
+		columns = append(columns, *l.Partition)
+	}
+	return append(columns, l.Clustering...)
+}
+
+// Apply copies the values of the columns of the layout from their sources in
+// a row. Timestamps given as strings or as seconds since the epoch are
+// copied to the partition column in RFC 3339 format. Rows with no value for
+// the partition column are rejected with a *ValidationError, since they
+// would be stored in a partition that queries filtering on the column do
+// not read.
+func (l *Layout) Apply(row map[string]interface{}) error {
+	for _, column := range l.Clustering {
+		if column.Source == "" {
+			continue
+		}
+		switch value := lookup(row, column.Source).(type) {
+		case nil:
+			delete(row, column.Name)
+		case string:
+			row[column.Name] = value
+		case map[string]interface{}, []interface{}:
+			return &ValidationError{Problems: []string{fmt.Sprintf("field %s of column %s is not a scalar", column.Source, column.Name)}}
+		default:
+			row[column.Name] = fmt.Sprint(value)
+		}
+	}
+
+	if l.Partition == nil {
+		return nil
+	}
+	column := *l.Partition
+	value := row[column.Name]
+	if column.Source != "" {
+		value = lookup(row, column.Source)
+	}
+	if value == nil {
+		return &ValidationError{Problems: []string{fmt.Sprintf("missing value for partition column %s", column)}}
+	}
+	timestamp, err := parseTimestamp(value)
+	if err != nil {
+		return &ValidationError{Problems: []string{fmt.Sprintf("invalid value for partition column %s: %v", column, err)}}
+	}
+	row[column.Name] = timestamp.UTC().Format(time.RFC3339Nano)
+	return nil
+}
+
+// lookup returns the value of a field of a row, with the names of nested
+// fields separated by dots. It returns nil when the field is missing.
+func lookup(row map[string]interface{}, path string) interface{} {
+	var value interface{} = row
+	for _, name := range strings.Split(path, ".") {
+		record, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = record[name]
+	}
+	return value
+}
+
+// parseTimestamp parses a timestamp given as a string or as seconds since
+// the epoch.
+func parseTimestamp(value interface{}) (time.Time, error) {
+	var seconds float64
+	switch v := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%q is not a timestamp", v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		seconds = f
+	case float64:
+		seconds = v
+	case int64:
+		seconds = float64(v)
+	case int:
+		seconds = float64(v)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected %T", value)
+	}
+	whole := int64(seconds)
+	return time.Unix(whole, int64((seconds-float64(whole))*float64(time.Second))), nil
+}
+
+// Schema returns a schema that extends the given schema with the columns of
+// the layout that it does not contain. Added columns are nullable, so that
+// they can be added to an existing table.
+func (l *Layout) Schema(schema bigquery.Schema) bigquery.Schema {
+	existing := make(map[string]bool)
+	for _, field := range schema {
+		existing[field.Name] = true
+	}
+	extended := append(bigquery.Schema{}, schema...)
+	if l.Partition != nil && !existing[l.Partition.Name] {
+		extended = append(extended, &bigquery.FieldSchema{Name: l.Partition.Name, Type: bigquery.TimestampFieldType})
+	}
+	for _, column := range l.Clustering {
+		if !existing[column.Name] {
+			extended = append(extended, &bigquery.FieldSchema{Name: column.Name, Type: bigquery.StringFieldType})
+		}
+	}
+	return extended
+}
+
+// TableMetadata returns the metadata of a new table with the given schema
+// and the layout.
+func (l *Layout) TableMetadata(schema bigquery.Schema) *bigquery.TableMetadata {
+	metadata := &bigquery.TableMetadata{
+		Schema:                 l.Schema(schema),
+		RequirePartitionFilter: l.RequirePartitionFilter,
+	}
+	if l.Partition != nil {
+		metadata.TimePartitioning = &bigquery.TimePartitioning{
+			Type:  bigquery.DayPartitioningType,
+			Field: l.Partition.Name,
+		}
+	}
+	if len(l.Clustering) > 0 {
+		metadata.Clustering = &bigquery.Clustering{}
+		for _, column := range l.Clustering {
+			metadata.Clustering.Fields = append(metadata.Clustering.Fields, column.Name)
+		}
+	}
+	return metadata
+}
+
+// MigrationQuery returns a query that copies the rows of an existing table
+// into a new table with the layout. The columns of the layout are computed
+// from their sources, so the existing table must not contain them.
+func (l *Layout) MigrationQuery(src, dst TableID) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s", quoteTableID(dst))
+	if l.Partition != nil {
+		fmt.Fprintf(&b, " PARTITION BY DATE(%s)", l.Partition.Name)
+	}
+	if len(l.Clustering) > 0 {
+		var names []string
+		for _, column := range l.Clustering {
+			names = append(names, column.Name)
+		}
+		fmt.Fprintf(&b, " CLUSTER BY %s", strings.Join(names, ", "))
+	}
+	if l.RequirePartitionFilter {
+		b.WriteString(" OPTIONS(require_partition_filter=true)")
+	}
+	b.WriteString(" AS SELECT *")
+	if l.Partition != nil && l.Partition.Source != "" {
+		fmt.Fprintf(&b, ", SAFE_CAST(%s AS TIMESTAMP) AS %s", l.Partition.Source, l.Partition.Name)
+	}
+	for _, column := range l.Clustering {
+		if column.Source != "" {
+			fmt.Fprintf(&b, ", SAFE_CAST(%s AS STRING) AS %s", column.Source, column.Name)
+		}
+	}
+	fmt.Fprintf(&b, " FROM %s", quoteTableID(src))
+	return b.String()
+}
+
+// quoteTableID returns the name of a table as a quoted identifier of
+// standard SQL.
+func quoteTableID(id TableID) string {
+	if id.Project == "" {
+		return fmt.Sprintf("`%s.%s`", id.Dataset, id.Table)
+	}
+	return fmt.Sprintf("`%s.%s.%s`", id.Project, id.Dataset, id.Table)
+}
+
+// Table reads, creates and updates the metadata of a table. It is
+// implemented by *bigquery.Table.
+type Table interface {
+	Metadata(ctx context.Context) (*bigquery.TableMetadata, error)
+	Create(ctx context.Context, metadata *bigquery.TableMetadata) error
+	Update(ctx context.Context, metadata bigquery.TableMetadataToUpdate, etag string) (*bigquery.TableMetadata, error)
+}
+
+// EnsureTable checks that a table has the layout, and returns its schema.
+// When the table does not exist, it is created with the given schema.
+// Columns of the layout that are missing from an existing table are added,
+// but a table that is not partitioned or clustered as expected must be
+// migrated to a new table, since the partitioning and clustering of a table
+// cannot be changed in place.
+func EnsureTable(ctx context.Context, table Table, schema bigquery.Schema, layout *Layout) (bigquery.Schema, error) {
+	metadata, err := table.Metadata(ctx)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		if schema == nil {
+			return nil, errors.New("table does not exist and no schema was given to create it")
+		}
+		metadata = layout.TableMetadata(schema)
+		if err := table.Create(ctx, metadata); err != nil {
+			return nil, fmt.Errorf("failed to create table: %v", err)
+		}
+		return metadata.Schema, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if layout.Partition != nil {
+		partitioning := metadata.TimePartitioning
+		if partitioning == nil || partitioning.Field != layout.Partition.Name {
+			return nil, fmt.Errorf("table is not partitioned by %s and must be migrated to a new table", layout.Partition.Name)
+		}
+	}
+	var clustering []string
+	if metadata.Clustering != nil {
+		clustering = metadata.Clustering.Fields
+	}
+	want := layout.TableMetadata(nil).Clustering
+	if want != nil && strings.Join(clustering, ",") != strings.Join(want.Fields, ",") {
+		return nil, fmt.Errorf("table is clustered by [%s] instead of [%s] and must be migrated to a new table", strings.Join(clustering, ", "), strings.Join(want.Fields, ", "))
+	}
+
+	extended := layout.Schema(metadata.Schema)
+	if len(extended) == len(metadata.Schema) {
+		return metadata.Schema, nil
+	}
+	metadata, err = table.Update(ctx, bigquery.TableMetadataToUpdate{Schema: extended}, metadata.ETag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add columns to table: %v", err)
+	}
+	return metadata.Schema, nil
+}
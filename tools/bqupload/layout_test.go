@@ -0,0 +1,243 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"cloud.google.com/go/bigquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+// fakeTable serves the metadata it is given, and records the metadata of
+// calls to Create and Update.
+type fakeTable struct {
+	metadata *bigquery.TableMetadata
+	created  *bigquery.TableMetadata
+	updated  *bigquery.TableMetadataToUpdate
+}
+
+func (f *fakeTable) Metadata(_ context.Context) (*bigquery.TableMetadata, error) {
+	if f.metadata == nil {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return f.metadata, nil
+}
+
+func (f *fakeTable) Create(_ context.Context, metadata *bigquery.TableMetadata) error {
+	f.created = metadata
+	return nil
+}
+
+func (f *fakeTable) Update(_ context.Context, metadata bigquery.TableMetadataToUpdate, _ string) (*bigquery.TableMetadata, error) {
+	f.updated = &metadata
+	updated := *f.metadata
+	updated.Schema = metadata.Schema
+	return &updated, nil
+}
+
+var _ = Describe("ParseColumn", func() {
+	It("parses a column with a source", func() {
+		column, err := ParseColumn("scenario_name=scenario.name")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(column).To(Equal(Column{Name: "scenario_name", Source: "scenario.name"}))
+		Expect(column.String()).To(Equal("scenario_name=scenario.name"))
+	})
+
+	It("parses a column without a source", func() {
+		column, err := ParseColumn("language")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(column).To(Equal(Column{Name: "language"}))
+	})
+
+	It("rejects invalid names and sources", func() {
+		for _, s := range []string{"", "scenario.name", "name=", "name=scenario..name", "name=a-b"} {
+			_, err := ParseColumn(s)
+			Expect(err).To(HaveOccurred(), "column %q", s)
+		}
+	})
+})
+
+var _ = Describe("Layout", func() {
+	var layout *Layout
+
+	BeforeEach(func() {
+		layout = &Layout{
+			Partition: &Column{Name: "created", Source: "metadata.created"},
+			Clustering: []Column{
+				{Name: "scenario_name", Source: "scenario.name"},
+				{Name: "language"},
+			},
+			RequirePartitionFilter: true,
+		}
+	})
+
+	Describe("Validate", func() {
+		It("accepts a valid layout", func() {
+			Expect(layout.Validate()).To(Succeed())
+		})
+
+		It("rejects too many clustering columns", func() {
+			layout.Clustering = append(layout.Clustering, Column{Name: "a"}, Column{Name: "b"}, Column{Name: "c"})
+			Expect(layout.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects columns used more than once", func() {
+			layout.Clustering = append(layout.Clustering, Column{Name: "created"})
+			Expect(layout.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects a required partition filter without a partition", func() {
+			layout.Partition = nil
+			Expect(layout.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Apply", func() {
+		It("copies nested fields to top-level columns", func() {
+			row := map[string]interface{}{
+				"metadata": map[string]interface{}{"created": "2022-03-01T12:00:00.5+01:00"},
+				"scenario": map[string]interface{}{"name": "cpp_protobuf_async_unary_ping_pong"},
+				"language": "cxx",
+			}
+			Expect(layout.Apply(row)).To(Succeed())
+			Expect(row).To(HaveKeyWithValue("created", "2022-03-01T11:00:00.5Z"))
+			Expect(row).To(HaveKeyWithValue("scenario_name", "cpp_protobuf_async_unary_ping_pong"))
+			Expect(row).To(HaveKeyWithValue("language", "cxx"))
+		})
+
+		It("accepts timestamps in seconds since the epoch", func() {
+			row := map[string]interface{}{
+				"metadata": map[string]interface{}{"created": json.Number("1646136000.25")},
+			}
+			Expect(layout.Apply(row)).To(Succeed())
+			Expect(row).To(HaveKeyWithValue("created", "2022-03-01T12:00:00.25Z"))
+			Expect(row).ToNot(HaveKey("scenario_name"))
+		})
+
+		It("rejects rows without a partition value", func() {
+			row := map[string]interface{}{
+				"scenario": map[string]interface{}{"name": "cpp_protobuf_async_unary_ping_pong"},
+			}
+			err := layout.Apply(row)
+			var validationErr *ValidationError
+			Expect(err).To(BeAssignableToTypeOf(validationErr))
+		})
+
+		It("rejects rows with an invalid partition value", func() {
+			row := map[string]interface{}{
+				"metadata": map[string]interface{}{"created": "yesterday"},
+			}
+			err := layout.Apply(row)
+			var validationErr *ValidationError
+			Expect(err).To(BeAssignableToTypeOf(validationErr))
+		})
+	})
+
+	Describe("Schema", func() {
+		It("adds missing columns as nullable columns", func() {
+			schema := bigquery.Schema{
+				{Name: "metadata", Type: bigquery.RecordFieldType},
+				{Name: "language", Type: bigquery.StringFieldType},
+			}
+			extended := layout.Schema(schema)
+			Expect(extended).To(HaveLen(4))
+			Expect(extended[2]).To(Equal(&bigquery.FieldSchema{Name: "created", Type: bigquery.TimestampFieldType}))
+			Expect(extended[3]).To(Equal(&bigquery.FieldSchema{Name: "scenario_name", Type: bigquery.StringFieldType}))
+			Expect(schema).To(HaveLen(2))
+		})
+	})
+
+	Describe("MigrationQuery", func() {
+		It("creates a partitioned and clustered copy of a table", func() {
+			src := TableID{Project: "grpc-testing", Dataset: "e2e_benchmarks", Table: "results"}
+			dst := TableID{Dataset: "e2e_benchmarks", Table: "results_partitioned"}
+			Expect(layout.MigrationQuery(src, dst)).To(Equal(
+				"CREATE TABLE `e2e_benchmarks.results_partitioned` " +
+					"PARTITION BY DATE(created) CLUSTER BY scenario_name, language " +
+					"OPTIONS(require_partition_filter=true) " +
+					"AS SELECT *, SAFE_CAST(metadata.created AS TIMESTAMP) AS created, " +
+					"SAFE_CAST(scenario.name AS STRING) AS scenario_name " +
+					"FROM `grpc-testing.e2e_benchmarks.results`"))
+		})
+	})
+})
+
+var _ = Describe("EnsureTable", func() {
+	var layout *Layout
+	var table *fakeTable
+	var schema bigquery.Schema
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		layout = &Layout{
+			Partition:  &Column{Name: "created", Source: "metadata.created"},
+			Clustering: []Column{{Name: "scenario_name", Source: "scenario.name"}},
+		}
+		table = &fakeTable{}
+		schema = bigquery.Schema{{Name: "metadata", Type: bigquery.RecordFieldType}}
+	})
+
+	It("creates a missing table", func() {
+		got, err := EnsureTable(ctx, table, schema, layout)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got).To(HaveLen(3))
+		Expect(table.created).ToNot(BeNil())
+		Expect(table.created.TimePartitioning.Field).To(Equal("created"))
+		Expect(table.created.Clustering.Fields).To(Equal([]string{"scenario_name"}))
+	})
+
+	It("requires a schema to create a missing table", func() {
+		_, err := EnsureTable(ctx, table, nil, layout)
+		Expect(err).To(HaveOccurred())
+		Expect(table.created).To(BeNil())
+	})
+
+	It("adds missing columns to an existing table", func() {
+		table.metadata = layout.TableMetadata(schema)
+		table.metadata.Schema = schema
+		got, err := EnsureTable(ctx, table, nil, layout)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got).To(HaveLen(3))
+		Expect(table.updated).ToNot(BeNil())
+	})
+
+	It("leaves a table with the layout unchanged", func() {
+		table.metadata = layout.TableMetadata(schema)
+		got, err := EnsureTable(ctx, table, nil, layout)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(got).To(HaveLen(3))
+		Expect(table.updated).To(BeNil())
+	})
+
+	It("rejects a table that is not partitioned", func() {
+		table.metadata = &bigquery.TableMetadata{Schema: schema}
+		_, err := EnsureTable(ctx, table, nil, layout)
+		Expect(err).To(MatchError(ContainSubstring("must be migrated")))
+	})
+
+	It("rejects a table that is clustered differently", func() {
+		table.metadata = layout.TableMetadata(schema)
+		table.metadata.Clustering.Fields = []string{"language"}
+		_, err := EnsureTable(ctx, table, nil, layout)
+		Expect(err).To(MatchError(ContainSubstring("must be migrated")))
+	})
+})
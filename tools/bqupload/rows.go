@@ -0,0 +1,58 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ReadRows reads rows from a stream of JSON values. Each value is either an
+// object, which is read as a single row, or an array of objects. This accepts
+// a single JSON object, a JSON array and newline-delimited JSON. Numbers are
+// read as json.Number, so that their precision is preserved.
+func ReadRows(r io.Reader) ([]map[string]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var rows []map[string]interface{}
+	for {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			rows = append(rows, v)
+		case []interface{}:
+			for i, element := range v {
+				row, ok := element.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("element %d of array is not an object", i)
+				}
+				rows = append(rows, row)
+			}
+		default:
+			return nil, fmt.Errorf("expected an object or an array of objects, got %T", value)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReadRows", func() {
+	It("reads a single object", func() {
+		rows, err := ReadRows(strings.NewReader(`{"name": "a", "qps": 1.5}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(Equal([]map[string]interface{}{
+			{"name": "a", "qps": json.Number("1.5")},
+		}))
+	})
+
+	It("reads arrays and newline-delimited objects", func() {
+		rows, err := ReadRows(strings.NewReader("[{\"name\": \"a\"}, {\"name\": \"b\"}]\n{\"name\": \"c\"}\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(HaveLen(3))
+		Expect(rows[2]["name"]).To(Equal("c"))
+	})
+
+	It("rejects values that are not objects", func() {
+		_, err := ReadRows(strings.NewReader(`[{"name": "a"}, 1]`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = ReadRows(strings.NewReader(`"row"`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableID identifies a BigQuery table.
+type TableID struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// ParseTableID parses a table name in the form [<project>:]<dataset>.<table>
+// or <project>.<dataset>.<table>. The project is empty when it is not given.
+func ParseTableID(name string) (TableID, error) {
+	var id TableID
+	rest := name
+	if project, tableName, ok := strings.Cut(name, ":"); ok {
+		id.Project = project
+		rest = tableName
+	}
+	parts := strings.Split(rest, ".")
+	switch {
+	case len(parts) == 3 && id.Project == "":
+		id.Project, id.Dataset, id.Table = parts[0], parts[1], parts[2]
+	case len(parts) == 2:
+		id.Dataset, id.Table = parts[0], parts[1]
+	default:
+		return TableID{}, fmt.Errorf("table %q is not in the form [<project>:]<dataset>.<table>", name)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return TableID{}, fmt.Errorf("table %q is not in the form [<project>:]<dataset>.<table>", name)
+		}
+	}
+	return id, nil
+}
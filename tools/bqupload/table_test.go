@@ -0,0 +1,46 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseTableID", func() {
+	It("parses a dataset and table", func() {
+		id, err := ParseTableID("e2e_benchmarks.results")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(TableID{Dataset: "e2e_benchmarks", Table: "results"}))
+	})
+
+	It("parses a project followed by a colon or a dot", func() {
+		want := TableID{Project: "grpc-testing", Dataset: "e2e_benchmarks", Table: "results"}
+		for _, name := range []string{"grpc-testing:e2e_benchmarks.results", "grpc-testing.e2e_benchmarks.results"} {
+			id, err := ParseTableID(name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal(want))
+		}
+	})
+
+	It("rejects invalid names", func() {
+		for _, name := range []string{"results", ".results", "grpc-testing:a.b.c"} {
+			_, err := ParseTableID(name)
+			Expect(err).To(HaveOccurred(), "name %q", name)
+		}
+	})
+})
@@ -0,0 +1,279 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/google/uuid"
+	"google.golang.org/api/googleapi"
+)
+
+// Inserter inserts rows into a table. It is implemented by *bigquery.Inserter.
+type Inserter interface {
+	Put(ctx context.Context, src interface{}) error
+}
+
+// Options configures the batching and retries of an Uploader.
+type Options struct {
+	// BatchSize is the largest number of rows inserted with one request.
+	BatchSize int
+
+	// MaxRetries is the number of times a batch is retried after a quota
+	// error or a transient error.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry of a batch. The
+	// delay doubles after each retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the longest delay between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultOptions returns the default options of an Uploader.
+func DefaultOptions() Options {
+	return Options{
+		BatchSize:      500,
+		MaxRetries:     5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Row is a row to insert. Its insert ID allows BigQuery to discard duplicate
+// rows when a batch is retried after an insert that succeeded but appeared to
+// fail.
+type Row struct {
+	InsertID string
+	Values   map[string]bigquery.Value
+}
+
+// Save implements the bigquery.ValueSaver interface.
+func (r *Row) Save() (map[string]bigquery.Value, string, error) {
+	return r.Values, r.InsertID, nil
+}
+
+// InsertError reports the rows that could not be inserted, because BigQuery
+// rejected them or because retries were exhausted.
+type InsertError struct {
+	// Failed is the number of rows that were not inserted.
+	Failed int
+
+	// Errors lists the errors returned for the rows.
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *InsertError) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("could not insert %d rows: %s", e.Failed, strings.Join(messages, "; "))
+}
+
+// Uploader validates rows and inserts them in batches.
+type Uploader struct {
+	inserter Inserter
+	schema   bigquery.Schema
+	options  Options
+	pending  []*Row
+	inserted int
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewUploader creates an Uploader that inserts rows with the given inserter,
+// after validating them against the given schema. Rows are not validated
+// when the schema is nil.
+func NewUploader(inserter Inserter, schema bigquery.Schema, options Options) *Uploader {
+	defaults := DefaultOptions()
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaults.BatchSize
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaults.InitialBackoff
+	}
+	if options.MaxBackoff < options.InitialBackoff {
+		options.MaxBackoff = options.InitialBackoff
+	}
+	return &Uploader{
+		inserter: inserter,
+		schema:   schema,
+		options:  options,
+		sleep:    sleepContext,
+	}
+}
+
+// Inserted returns the number of rows inserted so far.
+func (u *Uploader) Inserted() int {
+	return u.inserted
+}
+
+// Add validates a row and adds it to the current batch. The batch is
+// inserted when it is full. Rows that do not match the schema are rejected
+// with a *ValidationError, and are not inserted.
+func (u *Uploader) Add(ctx context.Context, row map[string]interface{}) error {
+	if u.schema != nil {
+		if err := Validate(u.schema, row); err != nil {
+			return err
+		}
+	}
+	values := make(map[string]bigquery.Value, len(row))
+	for name, value := range row {
+		values[name] = value
+	}
+	u.pending = append(u.pending, &Row{InsertID: uuid.New().String(), Values: values})
+	if len(u.pending) >= u.options.BatchSize {
+		return u.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush inserts the rows in the current batch. Rows that fail because of
+// quota errors or transient errors are retried with an exponential backoff.
+// Rows that are rejected by BigQuery, or that still fail once retries are
+// exhausted, are reported in an *InsertError.
+func (u *Uploader) Flush(ctx context.Context) error {
+	rows := u.pending
+	u.pending = nil
+
+	var errs []error
+	failed := 0
+	backoff := u.options.InitialBackoff
+	for attempt := 0; len(rows) > 0; attempt++ {
+		err := u.inserter.Put(ctx, rows)
+		if err == nil {
+			u.inserted += len(rows)
+			break
+		}
+
+		var putErr bigquery.PutMultiError
+		if errors.As(err, &putErr) {
+			retry, rejected := splitRowErrors(rows, putErr)
+			u.inserted += len(rows) - len(retry) - len(rejected)
+			failed += len(rejected)
+			errs = append(errs, rejected...)
+			rows = retry
+			if len(rows) == 0 {
+				break
+			}
+		} else if !isRetryable(err) {
+			return &InsertError{Failed: failed + len(rows), Errors: append(errs, err)}
+		}
+
+		if attempt >= u.options.MaxRetries {
+			err = fmt.Errorf("retries exhausted: %v", err)
+			return &InsertError{Failed: failed + len(rows), Errors: append(errs, err)}
+		}
+		log.Printf("Retrying insert of %d rows in %v: %v", len(rows), backoff, err)
+		if err := u.sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+		if backoff > u.options.MaxBackoff {
+			backoff = u.options.MaxBackoff
+		}
+	}
+
+	if failed > 0 {
+		return &InsertError{Failed: failed, Errors: errs}
+	}
+	return nil
+}
+
+// splitRowErrors separates the rows of a failed insert into rows that should
+// be retried and errors for the rows that BigQuery rejected. Rows without an
+// error were inserted.
+func splitRowErrors(rows []*Row, putErr bigquery.PutMultiError) ([]*Row, []error) {
+	var retry []*Row
+	var rejected []error
+	for i := range putErr {
+		rowErr := &putErr[i]
+		if rowErr.RowIndex < 0 || rowErr.RowIndex >= len(rows) {
+			continue
+		}
+		if isRetryableRowError(rowErr) {
+			retry = append(retry, rows[rowErr.RowIndex])
+			continue
+		}
+		rejected = append(rejected, rowErr)
+	}
+	return retry, rejected
+}
+
+// retryableReasons lists the reasons of errors that are expected to succeed
+// when retried. Rows are "stopped" when another row in the same request is
+// invalid.
+var retryableReasons = map[string]bool{
+	"backendError":      true,
+	"internalError":     true,
+	"quotaExceeded":     true,
+	"rateLimitExceeded": true,
+	"stopped":           true,
+	"timeout":           true,
+}
+
+// isRetryableRowError returns true when all errors of a row can be retried.
+func isRetryableRowError(rowErr *bigquery.RowInsertionError) bool {
+	for _, err := range rowErr.Errors {
+		var bqErr *bigquery.Error
+		if !errors.As(err, &bqErr) || !retryableReasons[bqErr.Reason] {
+			return false
+		}
+	}
+	return len(rowErr.Errors) > 0
+}
+
+// isRetryable returns true for errors of a whole request that are expected to
+// succeed when retried, such as quota errors and server errors.
+func isRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	for _, item := range apiErr.Errors {
+		if retryableReasons[item.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for the given duration, or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+// fakeInserter records the rows of each call to Put, and returns the errors
+// it is given in order.
+type fakeInserter struct {
+	calls [][]*Row
+	errs  []error
+}
+
+func (f *fakeInserter) Put(_ context.Context, src interface{}) error {
+	f.calls = append(f.calls, src.([]*Row))
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+var _ = Describe("Uploader", func() {
+	var inserter *fakeInserter
+	var uploader *Uploader
+	var sleeps []time.Duration
+	ctx := context.Background()
+
+	newRow := func(name string) map[string]interface{} {
+		return map[string]interface{}{"name": name}
+	}
+
+	BeforeEach(func() {
+		inserter = &fakeInserter{}
+		schema := bigquery.Schema{{Name: "name", Type: bigquery.StringFieldType, Required: true}}
+		uploader = NewUploader(inserter, schema, Options{
+			BatchSize:      2,
+			MaxRetries:     2,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Second,
+		})
+		sleeps = nil
+		uploader.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+	})
+
+	It("inserts rows in batches", func() {
+		for _, name := range []string{"a", "b", "c"} {
+			Expect(uploader.Add(ctx, newRow(name))).To(Succeed())
+		}
+		Expect(inserter.calls).To(HaveLen(1))
+		Expect(uploader.Flush(ctx)).To(Succeed())
+		Expect(inserter.calls).To(HaveLen(2))
+		Expect(inserter.calls[0]).To(HaveLen(2))
+		Expect(inserter.calls[1]).To(HaveLen(1))
+		Expect(inserter.calls[1][0].Values).To(HaveKeyWithValue("name", "c"))
+		Expect(inserter.calls[0][0].InsertID).ToNot(Equal(inserter.calls[0][1].InsertID))
+		Expect(uploader.Inserted()).To(Equal(3))
+	})
+
+	It("rejects rows that do not match the schema", func() {
+		err := uploader.Add(ctx, map[string]interface{}{"other": "a"})
+		Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+		Expect(uploader.Flush(ctx)).To(Succeed())
+		Expect(inserter.calls).To(BeEmpty())
+	})
+
+	It("retries quota errors with the same insert IDs", func() {
+		inserter.errs = []error{
+			&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}},
+			&googleapi.Error{Code: http.StatusServiceUnavailable},
+		}
+		Expect(uploader.Add(ctx, newRow("a"))).To(Succeed())
+		Expect(uploader.Flush(ctx)).To(Succeed())
+		Expect(inserter.calls).To(HaveLen(3))
+		Expect(inserter.calls[2][0].InsertID).To(Equal(inserter.calls[0][0].InsertID))
+		Expect(sleeps).To(Equal([]time.Duration{time.Second, time.Second}))
+		Expect(uploader.Inserted()).To(Equal(1))
+	})
+
+	It("gives up after the maximum number of retries", func() {
+		quotaErr := &googleapi.Error{Code: http.StatusTooManyRequests}
+		inserter.errs = []error{quotaErr, quotaErr, quotaErr}
+		Expect(uploader.Add(ctx, newRow("a"))).To(Succeed())
+		err := uploader.Flush(ctx)
+		Expect(err).To(BeAssignableToTypeOf(&InsertError{}))
+		Expect(err.(*InsertError).Failed).To(Equal(1))
+		Expect(inserter.calls).To(HaveLen(3))
+	})
+
+	It("does not retry other errors", func() {
+		inserter.errs = []error{&googleapi.Error{Code: http.StatusNotFound}}
+		Expect(uploader.Add(ctx, newRow("a"))).To(Succeed())
+		Expect(uploader.Flush(ctx)).ToNot(Succeed())
+		Expect(inserter.calls).To(HaveLen(1))
+	})
+
+	It("retries only the rows that were stopped", func() {
+		inserter.errs = []error{bigquery.PutMultiError{
+			{RowIndex: 0, Errors: bigquery.MultiError{&bigquery.Error{Reason: "invalid"}}},
+			{RowIndex: 1, Errors: bigquery.MultiError{&bigquery.Error{Reason: "stopped"}}},
+		}}
+		Expect(uploader.Add(ctx, newRow("a"))).To(Succeed())
+		Expect(uploader.Add(ctx, newRow("b"))).To(HaveOccurred())
+
+		Expect(inserter.calls).To(HaveLen(2))
+		Expect(inserter.calls[1]).To(HaveLen(1))
+		Expect(inserter.calls[1][0].Values).To(HaveKeyWithValue("name", "b"))
+		Expect(uploader.Inserted()).To(Equal(1))
+	})
+
+	It("stops retrying when the context is done", func() {
+		inserter.errs = []error{&googleapi.Error{Code: http.StatusServiceUnavailable}}
+		uploader.sleep = sleepContext
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(uploader.Add(cancelled, newRow("a"))).To(Succeed())
+		Expect(errors.Is(uploader.Flush(cancelled), context.Canceled)).To(BeTrue())
+	})
+})
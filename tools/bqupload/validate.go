@@ -0,0 +1,161 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// ValidationError lists the problems found when a row was validated against
+// a schema.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("row does not match the schema: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks that a row matches a schema. The row is expected to be
+// decoded from JSON, with numbers decoded either as float64 or as
+// json.Number. Fields that are not in the schema, required fields that are
+// missing and values of the wrong type are reported in a *ValidationError.
+func Validate(schema bigquery.Schema, row map[string]interface{}) error {
+	var problems []string
+	validateRecord(schema, row, "", &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateRecord checks the fields of a record, appending any problems found.
+func validateRecord(schema bigquery.Schema, record map[string]interface{}, prefix string, problems *[]string) {
+	fields := make(map[string]*bigquery.FieldSchema)
+	for _, field := range schema {
+		fields[field.Name] = field
+		if _, ok := record[field.Name]; !ok && field.Required {
+			*problems = append(*problems, fmt.Sprintf("missing required field %s%s", prefix, field.Name))
+		}
+	}
+
+	var names []string
+	for name := range record {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := prefix + name
+		field, ok := fields[name]
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("field %s is not in the schema", path))
+			continue
+		}
+		value := record[name]
+		if value == nil {
+			if field.Required {
+				*problems = append(*problems, fmt.Sprintf("required field %s is null", path))
+			}
+			continue
+		}
+		if !field.Repeated {
+			validateValue(field, value, path, problems)
+			continue
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("field %s is repeated, got %T", path, value))
+			continue
+		}
+		for i, element := range values {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			if element == nil {
+				*problems = append(*problems, fmt.Sprintf("field %s is null", elementPath))
+				continue
+			}
+			validateValue(field, element, elementPath, problems)
+		}
+	}
+}
+
+// validateValue checks that a single value matches the type of a field.
+func validateValue(field *bigquery.FieldSchema, value interface{}, path string, problems *[]string) {
+	var ok bool
+	switch field.Type {
+	case bigquery.RecordFieldType:
+		var record map[string]interface{}
+		if record, ok = value.(map[string]interface{}); ok {
+			validateRecord(field.Schema, record, path+".", problems)
+		}
+	case bigquery.IntegerFieldType:
+		ok = isInteger(value)
+	case bigquery.FloatFieldType, bigquery.NumericFieldType:
+		ok = isNumber(value)
+	case bigquery.BooleanFieldType:
+		_, ok = value.(bool)
+	case bigquery.TimestampFieldType:
+		// Timestamps may be strings or seconds since the epoch.
+		_, ok = value.(string)
+		ok = ok || isNumber(value)
+	default:
+		_, ok = value.(string)
+	}
+	if !ok {
+		*problems = append(*problems, fmt.Sprintf("field %s must be of type %s, got %T", path, field.Type, value))
+	}
+}
+
+// isInteger returns true for integral numbers and for strings that contain
+// an integer, which is how JSON encodes 64-bit integers.
+func isInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case json.Number:
+		_, err := v.Int64()
+		return err == nil
+	case float64:
+		return v == float64(int64(v))
+	case int, int32, int64:
+		return true
+	case string:
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	}
+	return false
+}
+
+// isNumber returns true for numbers and for strings that contain a number.
+func isNumber(value interface{}) bool {
+	switch v := value.(type) {
+	case json.Number:
+		_, err := v.Float64()
+		return err == nil
+	case float64, float32, int, int32, int64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	}
+	return false
+}
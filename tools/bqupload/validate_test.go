@@ -0,0 +1,93 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bqupload
+
+import (
+	"encoding/json"
+
+	"cloud.google.com/go/bigquery"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	schema := bigquery.Schema{
+		{Name: "name", Type: bigquery.StringFieldType, Required: true},
+		{Name: "count", Type: bigquery.IntegerFieldType},
+		{Name: "qps", Type: bigquery.FloatFieldType},
+		{Name: "cancelled", Type: bigquery.BooleanFieldType},
+		{Name: "created", Type: bigquery.TimestampFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "summary", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "latency50", Type: bigquery.FloatFieldType},
+		}},
+	}
+
+	It("accepts rows that match the schema", func() {
+		row := map[string]interface{}{
+			"name":      "cpp_unary",
+			"count":     json.Number("12"),
+			"qps":       json.Number("1000.5"),
+			"cancelled": false,
+			"created":   "2022-03-01T12:00:00Z",
+			"tags":      []interface{}{"a", "b"},
+			"summary":   map[string]interface{}{"latency50": 250.5},
+		}
+		Expect(Validate(schema, row)).To(Succeed())
+	})
+
+	It("accepts null values of nullable fields and integers encoded as strings", func() {
+		row := map[string]interface{}{
+			"name":    "cpp_unary",
+			"count":   "9007199254740993",
+			"summary": nil,
+		}
+		Expect(Validate(schema, row)).To(Succeed())
+	})
+
+	It("reports every problem in the row", func() {
+		row := map[string]interface{}{
+			"count":   json.Number("1.5"),
+			"unknown": "value",
+			"tags":    "a",
+			"summary": map[string]interface{}{"latency50": "fast", "extra": 1.0},
+		}
+		err := Validate(schema, row)
+		Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+		Expect(err.(*ValidationError).Problems).To(ConsistOf(
+			"missing required field name",
+			"field count must be of type INTEGER, got json.Number",
+			"field tags is repeated, got string",
+			"field summary.extra is not in the schema",
+			"field summary.latency50 must be of type FLOAT, got string",
+			"field unknown is not in the schema",
+		))
+	})
+
+	It("reports invalid elements of repeated fields", func() {
+		row := map[string]interface{}{
+			"name": "cpp_unary",
+			"tags": []interface{}{"a", 1.0, nil},
+		}
+		err := Validate(schema, row)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*ValidationError).Problems).To(ConsistOf(
+			"field tags[1] must be of type STRING, got float64",
+			"field tags[2] is null",
+		))
+	})
+})
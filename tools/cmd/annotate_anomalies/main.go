@@ -0,0 +1,196 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Annotate_anomalies is an executable that compares the results of a load test
+// with the results of previous runs of the same scenario in BigQuery. If any
+// metric is anomalous, the test is labeled and the anomalous metrics are
+// recorded in a table. It is intended to run as a hook of the test runner
+// after each test, in which case the test is read from the hook event on the
+// standard input.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/anomaly"
+	"github.com/grpc/test-infra/tools/bqupload"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func main() {
+	var testName, namespace, resultsTable, anomalyTable, project, method string
+	var threshold float64
+	var windowSize, minSamples int
+
+	flag.StringVar(&testName, "test", "", "name of the load test (default: read from the hook event on standard input)")
+	flag.StringVar(&namespace, "namespace", corev1.NamespaceDefault, "namespace of the load test")
+	flag.StringVar(&resultsTable, "results-table", "", "table of results uploaded by the driver, in the form [<project>:]<dataset>.<table>")
+	flag.StringVar(&anomalyTable, "anomaly-table", "", "table to record anomalous metrics into, in the form [<project>:]<dataset>.<table> (default: do not record)")
+	flag.StringVar(&project, "project", os.Getenv("GOOGLE_CLOUD_PROJECT"), "project of the tables, when not included in their names")
+	flag.StringVar(&method, "method", string(anomaly.ZScore), "method used to score results, either zscore or iqr")
+	flag.Float64Var(&threshold, "threshold", 0, "score above which a result is anomalous (default: 3 for zscore and 1.5 for iqr)")
+	flag.IntVar(&windowSize, "window", 30, "number of previous results that each result is compared with")
+	flag.IntVar(&minSamples, "min-samples", 10, "smallest number of previous results needed to score a result")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "annotate_anomalies", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	detector := &anomaly.Detector{
+		Method:     anomaly.Method(method),
+		Threshold:  threshold,
+		MinSamples: minSamples,
+	}
+	if detector.Threshold == 0 {
+		detector.Threshold = anomaly.DefaultThreshold(detector.Method)
+	}
+	if err := detector.Validate(); err != nil {
+		log.Fatalf("Invalid detector: %v", err)
+	}
+	if windowSize < minSamples {
+		log.Fatalf("The window (%d) must not be smaller than the minimum number of samples (%d)", windowSize, minSamples)
+	}
+
+	resultsTableID, err := parseTableID(resultsTable, project)
+	if err != nil {
+		log.Fatalf("Invalid results table: %v", err)
+	}
+	var anomalyTableID bqupload.TableID
+	if anomalyTable != "" {
+		anomalyTableID, err = parseTableID(anomalyTable, project)
+		if err != nil {
+			log.Fatalf("Invalid anomaly table: %v", err)
+		}
+	}
+
+	if testName == "" {
+		var event runner.HookEvent
+		if err := json.NewDecoder(os.Stdin).Decode(&event); err != nil {
+			log.Fatalf("No test: specify the test with -test or pass a hook event on standard input: %v", err)
+		}
+		if event.Phase != runner.AfterTest {
+			log.Printf("Skipping %s test hook event", event.Phase)
+			return
+		}
+		testName, namespace = event.Name, event.Namespace
+	}
+
+	ctx := context.Background()
+	loadTestGetter := runner.NewLoadTestGetterForNamespace(namespace)
+	test, err := loadTestGetter.Get(ctx, testName, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to get test %s/%s: %v", namespace, testName, err)
+	}
+	if test.Status.State != grpcv1.Succeeded {
+		log.Printf("Skipping test %s, which did not succeed", testName)
+		return
+	}
+	values := anomaly.SummaryValues(test.Status.Summary)
+	if len(values) == 0 {
+		log.Printf("Skipping test %s, which has no result summary", testName)
+		return
+	}
+	scenario := anomaly.ScenarioName(test)
+	if scenario == "" {
+		log.Fatalf("Failed to find the scenario of test %s", testName)
+	}
+
+	before := test.CreationTimestamp.Time
+	if test.Status.StartTime != nil {
+		before = test.Status.StartTime.Time
+	}
+
+	client, err := bigquery.NewClient(ctx, resultsTableID.Project)
+	if err != nil {
+		log.Fatalf("Failed to create BigQuery client: %v", err)
+	}
+	defer client.Close()
+
+	history := &anomaly.BigQueryHistory{Client: client, Table: resultsTableID}
+	windows, err := anomaly.Windows(ctx, history, scenario, values, before, windowSize)
+	if err != nil {
+		log.Fatalf("Failed to read previous results: %v", err)
+	}
+	findings := detector.Detect(values, windows)
+	for _, finding := range findings {
+		log.Printf("Test %s has anomalous %s: %g scores %.2f against %d previous results", testName, finding.Metric, finding.Value, finding.Score, finding.Samples)
+	}
+
+	if anomaly.Annotate(test, findings) {
+		if _, err := loadTestGetter.Update(ctx, test, metav1.UpdateOptions{}); err != nil {
+			log.Fatalf("Failed to label test %s: %v", testName, err)
+		}
+	}
+
+	if anomalyTable != "" && len(findings) > 0 {
+		table := client.DatasetInProject(anomalyTableID.Project, anomalyTableID.Dataset).Table(anomalyTableID.Table)
+		uploader := bqupload.NewUploader(table.Inserter(), nil, bqupload.DefaultOptions())
+		for _, row := range anomaly.Rows(test, scenario, detector.Method, findings, time.Now()) {
+			if err := uploader.Add(ctx, row); err != nil {
+				log.Fatalf("Failed to record anomalies: %v", err)
+			}
+		}
+		if err := uploader.Flush(ctx); err != nil {
+			log.Fatalf("Failed to record anomalies: %v", err)
+		}
+	}
+
+	log.Printf("Found %d anomalous metrics in test %s", len(findings), testName)
+}
+
+// parseTableID parses the name of a table, using a default project when the
+// name does not include one.
+func parseTableID(name, project string) (bqupload.TableID, error) {
+	tableID, err := bqupload.ParseTableID(name)
+	if err != nil {
+		return tableID, err
+	}
+	if tableID.Project == "" {
+		tableID.Project = project
+	}
+	if tableID.Project == "" {
+		return tableID, fmt.Errorf("no project: specify the project in the table name or with -project")
+	}
+	return tableID, nil
+}
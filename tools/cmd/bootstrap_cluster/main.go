@@ -0,0 +1,119 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Bootstrap_cluster is an executable that provisions the node pools that run
+// load tests on a GKE cluster, verifies that their nodes are ready, and writes
+// the node pool declarations of the controller configuration.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/bootstrap"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func main() {
+	var project, location, cluster, poolNames, outputFile string
+	var nodeCount int
+	var verifyTimeout time.Duration
+	var skipProvision, skipVerify bool
+
+	flag.StringVar(&project, "project", "", "GCP project of the cluster")
+	flag.StringVar(&location, "location", "", "zone or region of the cluster")
+	flag.StringVar(&cluster, "cluster", "", "name of the cluster")
+	flag.StringVar(&poolNames, "pools", "", "comma-separated names of the pools to provision (default: all pools)")
+	flag.IntVar(&nodeCount, "node-count", bootstrap.DefaultNodeCount, "number of nodes in each pool")
+	flag.BoolVar(&skipProvision, "skip-provision", false, "do not create node pools, only verify them and write the configuration")
+	flag.BoolVar(&skipVerify, "skip-verify", false, "do not verify that the nodes of the pools are ready")
+	flag.DurationVar(&verifyTimeout, "verify-timeout", 20*time.Minute, "time allowed for the nodes of the pools to become ready")
+	flag.StringVar(&outputFile, "o", "", "name of the output file for the defaults fragment (default: standard output)")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "bootstrap_cluster", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	pools := bootstrap.DefaultPools(nodeCount)
+	if poolNames != "" {
+		pools, err = bootstrap.SelectPools(pools, strings.Split(poolNames, ","))
+		if err != nil {
+			log.Fatalf("Failed to select pools: %v", err)
+		}
+	}
+	if err := bootstrap.ValidatePools(pools); err != nil {
+		log.Fatalf("Invalid pools: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if !skipProvision {
+		if project == "" || location == "" || cluster == "" {
+			log.Fatalf("The project, location and cluster are required to provision node pools")
+		}
+		service, err := bootstrap.NewGKENodePoolService(ctx, project, location, cluster)
+		if err != nil {
+			log.Fatalf("Failed to connect to GKE: %v", err)
+		}
+		if err := bootstrap.Provision(ctx, service, pools); err != nil {
+			log.Fatalf("Failed to provision node pools: %v", err)
+		}
+	}
+
+	if !skipVerify {
+		verifyCtx, cancel := context.WithTimeout(ctx, verifyTimeout)
+		err := bootstrap.Verify(verifyCtx, runner.NewK8sClientset().CoreV1(), pools, 15*time.Second)
+		cancel()
+		if err != nil {
+			log.Fatalf("Failed to verify node pools: %v", err)
+		}
+		log.Printf("Verified %d node pool(s)", len(pools))
+	}
+
+	output := os.Stdout
+	if outputFile != "" {
+		output, err = os.Create(outputFile)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer output.Close()
+	}
+
+	if err := bootstrap.WriteDefaultsFragment(output, pools); err != nil {
+		log.Fatalf("Failed to write defaults fragment: %v", err)
+	}
+}
@@ -25,10 +25,12 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"log"
-	"os/exec"
-	"strings"
+	"os"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/prebuilt"
 )
 
 func main() {
@@ -38,66 +40,26 @@ func main() {
 	flag.StringVar(&imagePrefix, "p", "", "set the root repository for search")
 	flag.StringVar(&tagOfImagesToDelete, "t", "", "images with this tag will be deleted")
 
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	if len(imagePrefix) == 0 {
-		log.Fatalln("no root repository is provided")
-	}
-
-	if len(tagOfImagesToDelete) == 0 {
-		log.Fatalln("no image tag is provided")
+	if ok, err := schemaOpts.Handle(os.Stdout, "delete_prebuilt_workers", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
 	}
 
-	log.Printf("start to process all images within %s having tag: %s", imagePrefix, tagOfImagesToDelete)
-
-	getRepository := exec.Command("gcloud", "container", "images", "list", fmt.Sprintf("--repository=%s", imagePrefix))
-	getRepositoryOutput, err := getRepository.CombinedOutput()
+	logger, err := logging.Setup(&logOpts)
 	if err != nil {
-		log.Printf("Failed getting repositories within %s: %s\n", imagePrefix, string(getRepositoryOutput))
+		log.Fatalf("Failed to set up logging: %v", err)
 	}
+	defer logger.Sync()
 
-	log.Printf("All image repositories within specified registry: %s\n", imagePrefix)
-	log.Println(string(getRepositoryOutput))
-
-	allRepositories := strings.Split(string(getRepositoryOutput), "\n")
-	for i, curRepository := range allRepositories {
-		if i == 0 || curRepository == "" {
-			continue
-		}
-		log.Printf("Processing image repository: %s\n", curRepository)
-
-		curImageToProcess := fmt.Sprintf("%s:%s", curRepository, tagOfImagesToDelete)
-
-		getImageHaveTheTag := exec.Command("gcloud", "container", "images", "list-tags", curRepository, fmt.Sprintf("--filter=%s", tagOfImagesToDelete))
-		getImageHaveTheTagOutput, err := getImageHaveTheTag.CombinedOutput()
-		if err != nil {
-			log.Printf("Failed getting image: %s with tag %s: %s\n", curRepository, tagOfImagesToDelete, string(getImageHaveTheTagOutput))
-		}
-
-		imageFullLine := strings.Split(string(getImageHaveTheTagOutput), "\n")
-		if len(imageFullLine) <= 2 {
-			log.Printf("Tag: %s is not presented.\n", tagOfImagesToDelete)
-			continue
-		}
-
-		numbersOfTagsOfCurrentImage := len(strings.Split(strings.Fields(imageFullLine[1])[1], ","))
-
-		if numbersOfTagsOfCurrentImage > 1 {
-			log.Printf("Image have multiple tags, including %s, untag the image with tag %s instead of deleting image\n", tagOfImagesToDelete, tagOfImagesToDelete)
-			untagImages := exec.Command("gcloud", "-q", "container", "images", "untag", curImageToProcess)
-			unTagImageOutput, err := untagImages.CombinedOutput()
-			if err != nil {
-				log.Printf("Failed untagging %s: %s\n", curImageToProcess, string(unTagImageOutput))
-			}
-			log.Printf("Succeeded untagging %s:%s\n", curRepository, tagOfImagesToDelete)
-		} else {
-			deleteImage := exec.Command("gcloud", "-q", "container", "images", "delete", curImageToProcess)
-			deleteImageOutput, err := deleteImage.CombinedOutput()
-			if err != nil {
-				log.Printf("Failed deleting image %s : %s\n", curImageToProcess, string(deleteImageOutput))
-			}
-			log.Printf("Succeeded deleting delete %s\n", curImageToProcess)
-		}
+	if err := prebuilt.Delete(imagePrefix, tagOfImagesToDelete); err != nil {
+		log.Fatalf("Failed deleting prebuilt images: %v", err)
 	}
-	log.Printf("All images with tag: %s within container registry: %s are processed.\n", tagOfImagesToDelete, imagePrefix)
 }
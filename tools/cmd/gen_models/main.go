@@ -0,0 +1,90 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Gen_models is an executable that generates Python and TypeScript client
+// models for the LoadTest API from the schema of its CustomResourceDefinition.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/models"
+)
+
+// generator is the name of the generator recorded in the generated files.
+const generator = "gen_models"
+
+func main() {
+	var crdFile, pythonFile, typeScriptFile string
+
+	flag.StringVar(&crdFile, "crd", "config/crd/bases/e2etest.grpc.io_loadtests.yaml", "file containing the LoadTest CustomResourceDefinition")
+	flag.StringVar(&pythonFile, "python", "", "name of the output file for the Python models (optional)")
+	flag.StringVar(&typeScriptFile, "typescript", "", "name of the output file for the TypeScript models (optional)")
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "gen_models", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if pythonFile == "" && typeScriptFile == "" {
+		log.Fatalf("At least one of -python and -typescript must be set")
+	}
+
+	schema, err := models.LoadSchema(crdFile, grpcv1.GroupVersion.Version)
+	if err != nil {
+		log.Fatalf("Failed to load schema: %v", err)
+	}
+	loadTestModels, err := models.Build(schema, "LoadTest")
+	if err != nil {
+		log.Fatalf("Failed to build models: %v", err)
+	}
+
+	writeFile := func(fileName string, write func(io.Writer, []*models.Model, string) error) {
+		if fileName == "" {
+			return
+		}
+		output, err := os.Create(fileName)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer output.Close()
+		if err := write(output, loadTestModels, generator); err != nil {
+			log.Fatalf("Failed to write models to %s: %v", fileName, err)
+		}
+		log.Printf("Wrote %d models to %s", len(loadTestModels), fileName)
+	}
+	writeFile(pythonFile, models.WritePython)
+	writeFile(typeScriptFile, models.WriteTypeScript)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Gen_smoke is an executable that generates minimal ping-pong load tests for a
+// set of languages and gitrefs, suitable for presubmit checks. The output can
+// be used as input to the runner.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/prebuilt"
+	"github.com/grpc/test-infra/tools/smoke"
+)
+
+type langFlags []string
+
+func (l *langFlags) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *langFlags) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *langFlags) Type() string {
+	return "stringArray"
+}
+
+func main() {
+	var languagesSelected langFlags
+	var defaultsFile, outputFile string
+
+	o := smoke.DefaultOptions()
+
+	flag.Var(&languagesSelected, "l", "languages, its repository and GITREF to test, example: cxx:<commit-sha> or cxx:grpc/grpc:<commit-sha>")
+	flag.StringVar(&defaultsFile, "defaults", "", "defaults file of the controller, which provides the container images")
+	flag.StringVar(&o.SamplesDir, "samples", o.SamplesDir, "directory containing the example load tests for each language")
+	flag.StringVar(&o.NamePrefix, "prefix", o.NamePrefix, "prefix of the names of the generated tests")
+	flag.IntVar(&o.WarmupSeconds, "warmup-seconds", o.WarmupSeconds, "warmup duration of each scenario, in seconds")
+	flag.IntVar(&o.BenchmarkSeconds, "benchmark-seconds", o.BenchmarkSeconds, "benchmark duration of each scenario, in seconds")
+	flag.StringVar(&outputFile, "o", "", "name of the output file (default: standard output)")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "gen_smoke", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if defaultsFile == "" {
+		log.Fatalf("No defaults file provided")
+	}
+	o.Defaults, err = smoke.LoadDefaults(defaultsFile)
+	if err != nil {
+		log.Fatalf("Failed to load defaults: %v", err)
+	}
+
+	o.Languages, err = prebuilt.ParseLanguageSpecs(languagesSelected)
+	if err != nil {
+		log.Fatalf("Failed to parse languages: %v", err)
+	}
+
+	tests, err := smoke.Generate(o)
+	if err != nil {
+		log.Fatalf("Failed to generate smoke tests: %v", err)
+	}
+
+	output := os.Stdout
+	if outputFile != "" {
+		output, err = os.Create(outputFile)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer output.Close()
+	}
+
+	if err := smoke.Write(output, tests); err != nil {
+		log.Fatalf("Failed to write smoke tests: %v", err)
+	}
+	log.Printf("Generated %d smoke tests", len(tests))
+}
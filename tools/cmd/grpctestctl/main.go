@@ -0,0 +1,41 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Grpctestctl is a command line tool that runs and manages gRPC load tests.
+// It consolidates the runner, prebuilt image and triage tools as subcommands
+// that share kubeconfig handling and logging flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+
+	"github.com/grpc/test-infra/tools/grpctestctl"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func main() {
+	if err := grpctestctl.NewRootCommand().ExecuteContext(context.Background()); err != nil {
+		var testsFailedErr *runner.TestsFailedError
+		if errors.As(err, &testsFailedErr) {
+			log.Printf("Error: %v", err)
+			os.Exit(testsFailedErr.ExitCode())
+		}
+		log.Fatalf("Error: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Poke is an executable that probes the workers of a load test. It forwards a
+// local port to the driver port of each server and client pod of the test,
+// and calls the CoreCount RPC of the WorkerService on it, to check whether the
+// worker responds. Optionally, it also asks each worker to quit. This helps
+// debug tests that appear to be running but make no progress.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/poke"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func main() {
+	var testName, namespace string
+	var timeout time.Duration
+	var quit bool
+
+	flag.StringVar(&testName, "test", "", "name of the load test")
+	flag.StringVar(&namespace, "namespace", corev1.NamespaceDefault, "namespace of the load test")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "time allowed for each RPC")
+	flag.BoolVar(&quit, "quit", false, "ask each worker to quit after it responds, which stops the test")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "poke", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if testName == "" {
+		log.Fatalf("No test: specify the test with -test")
+	}
+
+	ctx := context.Background()
+	test, err := runner.NewLoadTestGetterForNamespace(namespace).Get(ctx, testName, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to get test %s/%s: %v", namespace, testName, err)
+	}
+
+	clientset := runner.NewK8sClientset()
+	pods, err := runner.GetTestPods(ctx, test, clientset.CoreV1())
+	if err != nil {
+		log.Fatalf("Failed to get pods of test %s: %v", testName, err)
+	}
+	if len(pods) == 0 {
+		log.Fatalf("Test %s has no pods", testName)
+	}
+
+	prober := &poke.Prober{
+		Forwarder: &poke.PortForwarder{
+			Config: runner.NewKubernetesConfig(),
+			Client: clientset.CoreV1().RESTClient(),
+		},
+		Timeout: timeout,
+		Quit:    quit,
+	}
+	results := prober.ProbeAll(ctx, poke.Targets(pods))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tROLE\tPHASE\tPORT\tCORES\tLATENCY\tRESULT")
+	failed := 0
+	for _, result := range results {
+		port, cores, latency, outcome := "-", "-", "-", "not probed"
+		if result.Port != 0 {
+			port = fmt.Sprint(result.Port)
+			outcome = "ok"
+		}
+		if result.Latency > 0 {
+			cores = fmt.Sprint(result.Cores)
+			latency = result.Latency.Round(time.Millisecond).String()
+		}
+		if result.Quit {
+			outcome = "quit"
+		}
+		if result.Err != nil {
+			outcome = result.Err.Error()
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.Pod, result.Role, result.Phase, port, cores, latency, outcome)
+	}
+	w.Flush()
+
+	if failed > 0 {
+		log.Fatalf("%d worker(s) of test %s did not respond", failed, testName)
+	}
+}
@@ -18,125 +18,86 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log"
 	"os"
-	"path"
-	"time"
 
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
 	"github.com/grpc/test-infra/tools/runner"
-	"github.com/grpc/test-infra/tools/runner/xunit"
 )
 
 func main() {
+	o := runner.DefaultOptions()
 	var i runner.FileNames
-	var o string
-	var c runner.ConcurrencyLevels
-	var a string
-	var p time.Duration
-	var retries uint
-	var deleteSuccessfulTests bool
-	var logURLPrefix string
+	var defaultsFiles runner.FileNames
+	var beforeTestHooks, afterTestHooks runner.HookSpecs
 
 	flag.Var(&i, "i", "input files containing load test configurations")
-	flag.StringVar(&o, "o", "", "name of the output file for xunit xml report")
-	flag.Var(&c, "c", "concurrency level, in the form [<queue name>:]<concurrency level>")
-	flag.StringVar(&a, "annotation-key", "pool", "annotation key to parse for queue assignment")
-	flag.DurationVar(&p, "polling-interval", 20*time.Second, "polling interval for load test status")
-	flag.UintVar(&retries, "polling-retries", 2, "Maximum retries in case of communication failure")
-	flag.BoolVar(&deleteSuccessfulTests, "delete-successful-tests", false, "Delete tests immediately in case of successful termination")
-	flag.StringVar(&logURLPrefix, "log-url-prefix", "", "prefix for log urls")
+	flag.StringVar(&o.OutputFile, "o", "", "name of the output file for xunit xml report")
+	flag.StringVar(&o.JSONOutputFile, "json-o", "", "name of the output file for json report")
+	flag.StringVar(&o.Namespace, "namespace", o.Namespace, "namespace to create load tests in")
+	flag.BoolVar(&o.NamespacePerRun, "namespace-per-run", false, "create tests in a new namespace with a generated name, which is deleted at the end of the run")
+	flag.BoolVar(&o.ShardScenarios, "shard-scenarios", false, "split configurations with several scenarios into one test per scenario")
+	flag.Var(&o.ConcurrencyLevels, "c", "concurrency level, in the form [<queue name>:]<concurrency level>")
+	flag.StringVar(&o.AnnotationKey, "annotation-key", o.AnnotationKey, "annotation key to parse for queue assignment")
+	flag.StringVar(&o.RoutingRulesFile, "routing-rules", "", "file containing rules that assign tests to queues (optional)")
+	flag.DurationVar(&o.PollingInterval, "polling-interval", o.PollingInterval, "polling interval for load test status")
+	flag.UintVar(&o.PollingRetries, "polling-retries", o.PollingRetries, "Maximum retries in case of communication failure")
+	flag.Float64Var(&o.APIQPS, "api-qps", o.APIQPS, "rate of requests per second to the Kubernetes API, shared by all clients of the runner")
+	flag.IntVar(&o.APIBurst, "api-burst", o.APIBurst, "number of requests that may be sent to the Kubernetes API at once, above the rate set by -api-qps")
+	flag.BoolVar(&o.DeleteSuccessfulTests, "delete-successful-tests", false, "Delete tests immediately in case of successful termination")
+	flag.StringVar(&o.LogURLPrefix, "log-url-prefix", "", "prefix for log urls")
+	flag.StringVar(&o.DashboardURLTemplate, "dashboard-url", "", "Go template for a link to the dashboard of each test, such as https://grafana.example.com/d/abc?var-test={{.Name}}&from={{.From}}&to={{.To}} (defaults to the dashboardURLTemplate of the defaults)")
+	flag.Var(&defaultsFiles, "defaults-file", "defaults files of the controller, whose artifacts and node agent settings configure the upload of artifacts and the collection of node metrics (optional, repeatable)")
+	flag.StringVar(&o.PushgatewayURL, "pushgateway-url", "", "URL of a Prometheus pushgateway to receive metrics (optional)")
+	flag.StringVar(&o.PushgatewayJob, "pushgateway-job", o.PushgatewayJob, "job name used to group metrics in the pushgateway")
+	flag.DurationVar(&o.PushInterval, "push-interval", o.PushInterval, "interval between pushes of metrics to the pushgateway")
+	flag.IntVar(&o.WarmupSeconds, "warmup-seconds", o.WarmupSeconds, "override for the warmup duration of each scenario, in seconds (optional)")
+	flag.IntVar(&o.BenchmarkSeconds, "benchmark-seconds", o.BenchmarkSeconds, "override for the benchmark duration of each scenario, in seconds (optional)")
+	flag.BoolVar(&o.CollectProfiles, "collect-profiles", false, "collect CPU and heap profiles from workers with a pprof port during the benchmark")
+	flag.BoolVar(&o.AdaptiveConcurrency, "adaptive-concurrency", false, "reduce the concurrency level of a queue after consecutive infrastructure failures")
+	flag.IntVar(&o.AdaptiveFailureThreshold, "adaptive-failure-threshold", o.AdaptiveFailureThreshold, "consecutive infrastructure failures that halve the concurrency level of a queue")
+	flag.IntVar(&o.AdaptiveRecoveryThreshold, "adaptive-recovery-threshold", o.AdaptiveRecoveryThreshold, "consecutive tests without infrastructure failures that increase the concurrency level of a queue by one")
+	flag.Var(&beforeTestHooks, "before-test", "hook invoked before each test is created, either a shell command or an http(s) URL (optional, repeatable)")
+	flag.Var(&afterTestHooks, "after-test", "hook invoked after each test completes, either a shell command or an http(s) URL (optional, repeatable)")
+	flag.DurationVar(&o.HookTimeout, "hook-timeout", o.HookTimeout, "time allowed for each hook to complete")
+	flag.DurationVar(&o.SkipSucceededWithin, "skip-succeeded-within", 0, "skip tests with an identical spec to a test that succeeded within this duration (optional)")
+	flag.StringVar(&o.Duplicates, "duplicates", o.Duplicates, "policy for tests with the same spec as an earlier test in the run: allow, skip or rename")
+	flag.IntVar(&o.RetainRuns, "retain-runs", 0, "number of most recent runs whose artifacts are kept in each output directory (optional)")
+	flag.DurationVar(&o.RetainRunsFor, "retain-runs-for", 0, "age after which the artifacts of runs are removed from each output directory (optional)")
+	flag.BoolVar(&o.ArchiveRuns, "archive-runs", false, "compress the test directories of each queue into a single archive at the end of the run")
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	inputConfigs, err := runner.DecodeFromFiles(i)
-	if err != nil {
-		log.Fatalf("Failed to decode: %v", err)
-	}
-
-	configQueueMap := runner.CreateQueueMap(inputConfigs, runner.QueueSelectorFromAnnotation(a))
-	err = runner.ValidateConcurrencyLevels(configQueueMap, c)
-	if err != nil {
-		log.Fatalf("Failed to validate concurrency levels: %v", err)
-	}
-
-	outputPath := xunit.OutputPath(o)
-
-	outputDirMap := make(map[string]string)
-	for qName := range configQueueMap {
-		outputFilePath := outputPath(qName)
-		outputDir := path.Dir(outputFilePath)
-		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-			log.Fatalf("Failed to create output directory %q: %v", outputDir, err)
+	if ok, err := schemaOpts.Handle(os.Stdout, "runner", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
 		}
-		outputDirMap[qName] = outputDir
+		return
 	}
 
-	log.Printf("Annotation key for queue assignment: %s", a)
-	log.Printf("Polling interval: %v", p)
-	log.Printf("Polling retries: %d", retries)
-	log.Printf("Test counts per queue: %v", runner.CountConfigs(configQueueMap))
-	log.Printf("Queue concurrency levels: %v", c)
-	log.Printf("Output directories: %v", outputDirMap)
-	if logURLPrefix != "" {
-		log.Printf("Prefix for log urls: %s", logURLPrefix)
-	}
-
-	r := runner.NewRunner(runner.NewLoadTestGetter(), runner.NewPodsGetter(), runner.AfterIntervalFunction(p), retries, deleteSuccessfulTests, logURLPrefix)
-
-	logPrefixFmt := runner.LogPrefixFmt(configQueueMap)
-
-	report := xunit.Report{}
-
-	reporter := runner.NewReporter(&report)
-	reporter.SetStartTime(time.Now())
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	done := make(chan *runner.TestSuiteReporter)
-
-	for qName, configs := range configQueueMap {
-		testSuiteReporter := reporter.NewTestSuiteReporter(qName, logPrefixFmt, runner.TestCaseNameFromAnnotations("scenario"))
-		testSuiteReporter.SetStartTime(time.Now())
-		go r.Run(ctx, configs, testSuiteReporter, c[qName], outputDirMap[qName], done)
-	}
-
-	for range configQueueMap {
-		testSuiteReporter := <-done
-		testSuiteReporter.SetEndTime(time.Now())
-		log.Printf("Done running tests for queue %q in %s", testSuiteReporter.Queue(), testSuiteReporter.Duration())
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
 	}
-
-	reporter.SetEndTime(time.Now())
-
-	report.Finalize()
-
-	if o != "" {
-		for suiteName, suiteReport := range report.Split() {
-			outputFilePath := outputPath(suiteName)
-
-			outputFile, err := os.Create(outputFilePath)
-			if err != nil {
-				log.Fatalf("Failed to create output file %q: %v", outputFilePath, err)
-			}
-
-			err = suiteReport.WriteToStream(outputFile, xunit.ReportWritingOptions{
-				IndentSize: 2,
-				MaxRetries: 3,
-			})
-			if err != nil {
-				log.Fatalf("Failed to write XML report to file %q: %v", outputFilePath, err)
-			}
-
-			if err := outputFile.Close(); err != nil {
-				log.Fatalf("Failed to close output file %q: %v", outputFilePath, err)
-			}
-
-			log.Printf("Wrote XML report to file %q", outputFilePath)
+	defer logger.Sync()
+
+	o.FileNames = i
+	o.DefaultsFiles = defaultsFiles
+	o.BeforeTestHooks = beforeTestHooks
+	o.AfterTestHooks = afterTestHooks
+	if err := runner.RunTests(context.Background(), o); err != nil {
+		var testsFailedErr *runner.TestsFailedError
+		if errors.As(err, &testsFailedErr) {
+			log.Printf("Failed to run tests: %v", err)
+			logger.Sync()
+			os.Exit(testsFailedErr.ExitCode())
 		}
-	}
-
-	if report.ErrorCount > 0 {
-		log.Fatalf("Errors found during test run: %d", report.ErrorCount)
+		log.Fatalf("Failed to run tests: %v", err)
 	}
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Triage is an executable that classifies the logs of failed load tests,
+// grouping the failures by their probable cause.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/runner"
+	"github.com/grpc/test-infra/tools/triage"
+)
+
+func main() {
+	var i runner.FileNames
+	var t runner.FileNames
+	var classifiersFile string
+	var outputFormat string
+
+	flag.Var(&i, "i", "log files to classify (the parent directory is used as the test name)")
+	flag.Var(&t, "t", "names of load tests in the cluster whose logs should be classified")
+	flag.StringVar(&classifiersFile, "classifiers", "", "YAML file with additional classifiers, as a list of {cause, pattern} objects")
+	flag.StringVar(&outputFormat, "format", "text", "output format, either text or json")
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "triage", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if len(i) == 0 && len(t) == 0 {
+		log.Fatalf("No logs to classify: specify log files with -i or tests with -t")
+	}
+	if outputFormat != "text" && outputFormat != "json" {
+		log.Fatalf("Unknown output format %q", outputFormat)
+	}
+
+	var classifiers []triage.Classifier
+	if classifiersFile != "" {
+		custom, err := triage.LoadClassifiers(classifiersFile)
+		if err != nil {
+			log.Fatalf("Failed to load classifiers: %v", err)
+		}
+		classifiers = append(classifiers, custom...)
+	}
+	classifiers = append(classifiers, triage.DefaultClassifiers()...)
+
+	logs, err := triage.LogsFromFiles(i)
+	if err != nil {
+		log.Fatalf("Failed to read logs: %v", err)
+	}
+
+	if len(t) > 0 {
+		clusterLogs, err := triage.LogsFromCluster(context.Background(), runner.NewLoadTestGetter(), runner.NewPodsGetter(), t)
+		if err != nil {
+			log.Fatalf("Failed to fetch logs from cluster: %v", err)
+		}
+		logs = append(logs, clusterLogs...)
+	}
+
+	summary := triage.Classify(logs, classifiers)
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summary); err != nil {
+			log.Fatalf("Failed to write summary: %v", err)
+		}
+		return
+	}
+
+	if err := summary.WriteText(os.Stdout); err != nil {
+		log.Fatalf("Failed to write summary: %v", err)
+	}
+}
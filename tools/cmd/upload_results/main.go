@@ -0,0 +1,254 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Upload_results is an executable that uploads rows of results to a BigQuery
+// table with streaming inserts. It is used by the driver container to upload
+// results. Rows are validated against the schema of the table before they are
+// inserted, and batches that fail because of quota errors or transient errors
+// are retried. Tables can be partitioned by the creation time of the results
+// and clustered by fields such as the scenario name, to keep the cost of
+// queries down as results accumulate.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/bqupload"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+// columnFlags collects the clustering columns of a table.
+type columnFlags []bqupload.Column
+
+func (c *columnFlags) String() string {
+	var columns []string
+	for _, column := range *c {
+		columns = append(columns, column.String())
+	}
+	return strings.Join(columns, " ")
+}
+
+func (c *columnFlags) Set(value string) error {
+	column, err := bqupload.ParseColumn(value)
+	if err != nil {
+		return err
+	}
+	*c = append(*c, column)
+	return nil
+}
+
+func (c *columnFlags) Type() string {
+	return "stringArray"
+}
+
+func main() {
+	var i runner.FileNames
+	var tableName, project, schemaFile, migrateFrom string
+	var partitionColumn, partitionSource string
+	var clustering columnFlags
+	var requirePartitionFilter bool
+	o := bqupload.DefaultOptions()
+
+	flag.Var(&i, "i", "input files containing rows, as JSON objects, arrays of objects or newline-delimited JSON")
+	flag.StringVar(&tableName, "table", "", "table to insert rows into, in the form [<project>:]<dataset>.<table>")
+	flag.StringVar(&project, "project", os.Getenv("GOOGLE_CLOUD_PROJECT"), "project of the table, when not included in the table name")
+	flag.StringVar(&schemaFile, "schema", "", "file containing the expected schema of the table as JSON (defaults to the schema of the table)")
+	flag.StringVar(&partitionColumn, "partition-column", "", "TIMESTAMP column to partition the table by day (the table is not partitioned when empty)")
+	flag.StringVar(&partitionSource, "partition-source", "metadata.created", "field of each row that the partition column is copied from (empty when rows set the column themselves)")
+	flag.Var(&clustering, "cluster", "column to cluster the table by, in the form <name>[=<source>], where the column is copied from the source field of each row (may be repeated)")
+	flag.BoolVar(&requirePartitionFilter, "require-partition-filter", false, "reject queries of a new table that do not filter on the partition column")
+	flag.StringVar(&migrateFrom, "migrate-from", "", "existing table to copy into the table with its partitioning and clustering, in the form [<project>:]<dataset>.<table>")
+	flag.IntVar(&o.BatchSize, "batch-size", o.BatchSize, "largest number of rows inserted with one request")
+	flag.IntVar(&o.MaxRetries, "max-retries", o.MaxRetries, "number of times a batch is retried after a quota error or a transient error")
+	flag.DurationVar(&o.InitialBackoff, "initial-backoff", o.InitialBackoff, "delay before the first retry of a batch, which doubles after each retry")
+	flag.DurationVar(&o.MaxBackoff, "max-backoff", o.MaxBackoff, "longest delay between retries")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "upload_results", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	if len(i) == 0 && migrateFrom == "" {
+		log.Fatalf("No rows to upload: specify input files with -i")
+	}
+	tableID, err := parseTableID(tableName, project)
+	if err != nil {
+		log.Fatalf("Invalid table: %v", err)
+	}
+
+	layout := &bqupload.Layout{
+		Clustering:             clustering,
+		RequirePartitionFilter: requirePartitionFilter,
+	}
+	if partitionColumn != "" {
+		layout.Partition = &bqupload.Column{Name: partitionColumn, Source: partitionSource}
+	}
+	if err := layout.Validate(); err != nil {
+		log.Fatalf("Invalid partitioning or clustering: %v", err)
+	}
+	if migrateFrom != "" && layout.IsZero() {
+		log.Fatalf("Nothing to migrate: specify -partition-column or -cluster")
+	}
+
+	var rows []map[string]interface{}
+	for _, fileName := range i {
+		fileRows, err := readRowsFile(fileName)
+		if err != nil {
+			log.Fatalf("Failed to read rows from %s: %v", fileName, err)
+		}
+		rows = append(rows, fileRows...)
+	}
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, tableID.Project)
+	if err != nil {
+		log.Fatalf("Failed to create BigQuery client: %v", err)
+	}
+	defer client.Close()
+	table := client.Dataset(tableID.Dataset).Table(tableID.Table)
+
+	if migrateFrom != "" {
+		srcID, err := parseTableID(migrateFrom, tableID.Project)
+		if err != nil {
+			log.Fatalf("Invalid table to migrate: %v", err)
+		}
+		if err := runQuery(ctx, client, layout.MigrationQuery(srcID, tableID)); err != nil {
+			log.Fatalf("Failed to migrate %s:%s.%s: %v", srcID.Project, srcID.Dataset, srcID.Table, err)
+		}
+		log.Printf("Migrated %s:%s.%s to %s:%s.%s", srcID.Project, srcID.Dataset, srcID.Table, tableID.Project, tableID.Dataset, tableID.Table)
+		if len(i) == 0 {
+			return
+		}
+	}
+
+	var schema bigquery.Schema
+	if schemaFile != "" {
+		data, err := os.ReadFile(schemaFile)
+		if err != nil {
+			log.Fatalf("Failed to read schema: %v", err)
+		}
+		schema, err = bigquery.SchemaFromJSON(data)
+		if err != nil {
+			log.Fatalf("Failed to parse schema: %v", err)
+		}
+	}
+	if !layout.IsZero() {
+		tableSchema, err := bqupload.EnsureTable(ctx, table, schema, layout)
+		if err != nil {
+			log.Fatalf("Failed to prepare table %s.%s: %v", tableID.Dataset, tableID.Table, err)
+		}
+		if schema != nil {
+			schema = layout.Schema(schema)
+		} else {
+			schema = tableSchema
+		}
+	} else if schema == nil {
+		metadata, err := table.Metadata(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get schema of table %s.%s: %v", tableID.Dataset, tableID.Table, err)
+		}
+		schema = metadata.Schema
+	}
+
+	uploader := bqupload.NewUploader(table.Inserter(), schema, o)
+	var invalid int
+	for index, row := range rows {
+		err := layout.Apply(row)
+		if err == nil {
+			err = uploader.Add(ctx, row)
+		}
+		var validationErr *bqupload.ValidationError
+		if errors.As(err, &validationErr) {
+			log.Printf("Skipping row %d: %v", index, err)
+			invalid++
+			continue
+		}
+		if err != nil {
+			log.Fatalf("Failed to upload rows: %v", err)
+		}
+	}
+	if err := uploader.Flush(ctx); err != nil {
+		log.Fatalf("Failed to upload rows: %v", err)
+	}
+
+	log.Printf("Uploaded %d rows to %s:%s.%s", uploader.Inserted(), tableID.Project, tableID.Dataset, tableID.Table)
+	if invalid > 0 {
+		log.Fatalf("Failed to upload %d rows that do not match the schema", invalid)
+	}
+}
+
+// parseTableID parses the name of a table, using the given project when the
+// name does not include one.
+func parseTableID(name, project string) (bqupload.TableID, error) {
+	id, err := bqupload.ParseTableID(name)
+	if err != nil {
+		return bqupload.TableID{}, err
+	}
+	if id.Project == "" {
+		id.Project = project
+	}
+	if id.Project == "" {
+		return bqupload.TableID{}, fmt.Errorf("no project for table %q: specify the project in the table name or with -project", name)
+	}
+	return id, nil
+}
+
+// runQuery runs a query job and waits for it to complete.
+func runQuery(ctx context.Context, client *bigquery.Client, query string) error {
+	job, err := client.Query(query).Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
+// readRowsFile reads the rows in a file.
+func readRowsFile(fileName string) ([]map[string]interface{}, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return bqupload.ReadRows(file)
+}
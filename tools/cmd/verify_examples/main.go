@@ -0,0 +1,118 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Verify_examples is an executable that checks the example load tests against
+// the current API, defaults and pod builder, so that examples do not fall out
+// of date. It can also write the examples annotated for a dry run, to be
+// created in a cluster with the runner.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/examples"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/smoke"
+)
+
+type variableFlags map[string]string
+
+func (v variableFlags) String() string {
+	var pairs []string
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+func (v variableFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("variable %q is not in the form <name>=<value>", value)
+	}
+	v[name] = val
+	return nil
+}
+
+func (v variableFlags) Type() string {
+	return "stringToString"
+}
+
+func main() {
+	var defaultsTemplate, dryRunOutputFile string
+
+	o := examples.DefaultOptions()
+	data := examples.DefaultsData{Version: "latest", KillAfter: 20}
+
+	flag.StringVar(&o.SamplesDir, "samples", o.SamplesDir, "directory containing the example load tests")
+	flag.StringVar(&o.CRDFile, "crd", o.CRDFile, "file containing the LoadTest CustomResourceDefinition")
+	flag.StringVar(&defaultsTemplate, "defaults-template", "config/defaults_template.yaml", "defaults template of the controller")
+	flag.StringVar(&data.Version, "version", data.Version, "version of the images in the defaults template")
+	flag.Var(variableFlags(o.Variables), "variable", "value of a placeholder in the example templates, in the form <name>=<value>")
+	flag.StringVar(&dryRunOutputFile, "dry-run-output", "", "name of a file to write the examples annotated for a dry run (optional)")
+
+	var logOpts logging.Options
+	logOpts.BindFlags(flag.CommandLine)
+	var schemaOpts flagschema.Options
+	schemaOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	if ok, err := schemaOpts.Handle(os.Stdout, "verify_examples", flag.CommandLine); ok {
+		if err != nil {
+			log.Fatalf("Failed to describe flags: %v", err)
+		}
+		return
+	}
+
+	logger, err := logging.Setup(&logOpts)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	defer logger.Sync()
+
+	o.Defaults, err = examples.RenderDefaultsTemplate(defaultsTemplate, data)
+	if err != nil {
+		log.Fatalf("Failed to load defaults: %v", err)
+	}
+
+	verified, err := examples.VerifyAll(o)
+	if err != nil {
+		log.Fatalf("Failed to verify examples: %v", err)
+	}
+	log.Printf("Verified %d examples", len(verified))
+
+	if dryRunOutputFile == "" {
+		return
+	}
+	tests, err := examples.DryRunTests(verified)
+	if err != nil {
+		log.Fatalf("Failed to prepare examples for a dry run: %v", err)
+	}
+	output, err := os.Create(dryRunOutputFile)
+	if err != nil {
+		log.Fatalf("Failed to create output file: %v", err)
+	}
+	defer output.Close()
+	if err := smoke.Write(output, tests); err != nil {
+		log.Fatalf("Failed to write examples: %v", err)
+	}
+	log.Printf("Wrote %d examples for a dry run to %s", len(tests), dryRunOutputFile)
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package examples verifies the example load tests in config/samples, so they
+// do not fall out of date as the API evolves. Each example is decoded strictly,
+// validated against the schema of the LoadTest CRD and checked for problems
+// that would cause it to fail at run time. The defaults of the controller are
+// then set on the example and its pods are rendered, as the controller does for
+// a dry run.
+package examples
@@ -0,0 +1,352 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/podbuilder"
+	"github.com/grpc/test-infra/tools/grpctestctl"
+)
+
+// Options contains the settings used to verify the examples.
+type Options struct {
+	// SamplesDir is the directory containing the examples. YAML files in
+	// the directory and its subdirectories are verified.
+	SamplesDir string
+
+	// CRDFile is the file containing the LoadTest CustomResourceDefinition,
+	// whose schema the examples are validated against.
+	CRDFile string
+
+	// Defaults are the defaults of the controller, which are set on each
+	// example before its pods are rendered.
+	Defaults *config.Defaults
+
+	// Variables maps the names of placeholders in the example templates,
+	// written as ${name}, to the values that replace them. Placeholders
+	// with upper case names are environment variables of the containers,
+	// and are left unchanged.
+	Variables map[string]string
+}
+
+// DefaultOptions returns the options used when no settings are specified.
+func DefaultOptions() *Options {
+	return &Options{
+		SamplesDir: "config/samples",
+		CRDFile:    "config/crd/bases/e2etest.grpc.io_loadtests.yaml",
+		Variables:  DefaultVariables(),
+	}
+}
+
+// DefaultVariables returns values for the placeholders used by the example
+// templates. The values are only meant to produce valid load tests.
+func DefaultVariables() map[string]string {
+	return map[string]string{
+		"big_query_table":       "example-project.example_dataset.results",
+		"driver_image":          "example.registry/driver:latest",
+		"driver_pool":           "drivers",
+		"prebuilt_image_prefix": "example.registry/prebuilt",
+		"prebuilt_image_tag":    "latest",
+		"psm_image_prefix":      "example.registry/psm",
+		"psm_image_tag":         "latest",
+		"workers_pool":          "workers",
+	}
+}
+
+// Validate checks that all required options are set.
+func (o *Options) Validate() error {
+	if o.SamplesDir == "" {
+		return errors.New("no samples directory provided")
+	}
+	if o.CRDFile == "" {
+		return errors.New("no CRD file provided")
+	}
+	if o.Defaults == nil {
+		return errors.New("no defaults provided")
+	}
+	return nil
+}
+
+// DefaultsData contains the values of the placeholders in the defaults
+// template of the controller.
+type DefaultsData struct {
+	Version          string
+	InitImagePrefix  string
+	BuildImagePrefix string
+	RunImagePrefix   string
+	KillAfter        float64
+}
+
+// RenderDefaultsTemplate fills the defaults template of the controller with
+// the given data, and returns the validated defaults.
+func RenderDefaultsTemplate(fileName string, data DefaultsData) (*config.Defaults, error) {
+	templ, err := template.ParseFiles(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse defaults template %q: %v", fileName, err)
+	}
+	var b strings.Builder
+	if err := templ.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render defaults template %q: %v", fileName, err)
+	}
+	defaults := new(config.Defaults)
+	if err := yaml.UnmarshalStrict([]byte(b.String()), defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered defaults template %q: %v", fileName, err)
+	}
+	if err := defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid defaults in template %q: %v", fileName, err)
+	}
+	return defaults, nil
+}
+
+// Example is a load test read from an example file.
+type Example struct {
+	// FileName is the name of the file containing the example.
+	FileName string
+
+	// Index is the position of the example in its file.
+	Index int
+
+	// Data is the YAML of the example, after placeholders are replaced.
+	Data []byte
+}
+
+// String returns the file name of the example and its position in the file.
+func (e *Example) String() string {
+	return fmt.Sprintf("%s[%d]", e.FileName, e.Index)
+}
+
+// FindExamples returns the examples in the YAML files of a directory and its
+// subdirectories, sorted by file name.
+func FindExamples(dir string, variables map[string]string) ([]*Example, error) {
+	var fileNames []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".yaml" {
+			fileNames = append(fileNames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list examples in %q: %v", dir, err)
+	}
+	sort.Strings(fileNames)
+
+	var examples []*Example
+	for _, fileName := range fileNames {
+		docs, err := readDocuments(fileName)
+		if err != nil {
+			return nil, err
+		}
+		for i, doc := range docs {
+			examples = append(examples, &Example{
+				FileName: fileName,
+				Index:    i,
+				Data:     []byte(expandVariables(doc, variables)),
+			})
+		}
+	}
+	return examples, nil
+}
+
+// readDocuments splits a multipart YAML file into its documents.
+func readDocuments(fileName string) ([]string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var docs []string
+	var lines []string
+	flush := func() {
+		doc := strings.Join(lines, "\n")
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, doc)
+		}
+		lines = nil
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line == "---" {
+			flush()
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %q: %v", fileName, err)
+	}
+	flush()
+	return docs, nil
+}
+
+// placeholderPattern matches the placeholders of the example templates.
+var placeholderPattern = regexp.MustCompile(`\$\{([a-z_]+)\}`)
+
+// expandVariables replaces the placeholders of a template that have values.
+func expandVariables(s string, variables map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// NewSchemaValidator returns a validator for the schema of the LoadTest
+// CustomResourceDefinition in a file.
+func NewSchemaValidator(crdFile string) (*validate.SchemaValidator, error) {
+	data, err := os.ReadFile(crdFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRD file %q: %v", crdFile, err)
+	}
+	crd := new(apiextensionsv1.CustomResourceDefinition)
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		return nil, fmt.Errorf("failed to parse CRD file %q: %v", crdFile, err)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Name != grpcv1.GroupVersion.Version || version.Schema == nil {
+			continue
+		}
+		schema := new(apiextensions.JSONSchemaProps)
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+			return nil, fmt.Errorf("failed to convert schema in %q: %v", crdFile, err)
+		}
+		validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: schema})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create schema validator for %q: %v", crdFile, err)
+		}
+		return validator, nil
+	}
+	return nil, fmt.Errorf("no schema for version %q in %q", grpcv1.GroupVersion.Version, crdFile)
+}
+
+// Verify checks a single example. It returns the problems that were found,
+// or nil if there were none.
+func Verify(o *Options, validator *validate.SchemaValidator, example *Example) []string {
+	var problems []string
+	addProblem := func(format string, v ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, v...))
+	}
+
+	if placeholders := placeholderPattern.FindAllString(string(example.Data), -1); len(placeholders) > 0 {
+		addProblem("placeholders without values: %s", strings.Join(placeholders, ", "))
+	}
+
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(example.Data, &obj); err != nil {
+		addProblem("not valid YAML: %v", err)
+		return problems
+	}
+	for _, err := range validation.ValidateCustomResource(nil, obj, validator) {
+		addProblem("schema: %v", err)
+	}
+
+	test := new(grpcv1.LoadTest)
+	if err := yaml.UnmarshalStrict(example.Data, test); err != nil {
+		addProblem("not a valid load test: %v", err)
+		return problems
+	}
+	if test.APIVersion != grpcv1.GroupVersion.String() || test.Kind != "LoadTest" {
+		addProblem("unexpected type %s, %s", test.APIVersion, test.Kind)
+	}
+	if err := grpctestctl.ValidateLoadTest(test); err != nil {
+		addProblem("%v", err)
+	}
+
+	if err := o.Defaults.SetLoadTestDefaults(test); err != nil {
+		addProblem("failed to set defaults: %v", err)
+		return problems
+	}
+	if _, err := podbuilder.New(o.Defaults, test).RenderAll(); err != nil {
+		addProblem("failed to render pods: %v", err)
+	}
+
+	return problems
+}
+
+// VerifyAll checks all examples in the samples directory. It returns the
+// examples that were checked, and an error listing the problems of each
+// example, or nil if there were none.
+func VerifyAll(o *Options) ([]*Example, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	validator, err := NewSchemaValidator(o.CRDFile)
+	if err != nil {
+		return nil, err
+	}
+	examples, err := FindExamples(o.SamplesDir, o.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples found in %q", o.SamplesDir)
+	}
+
+	var problems []string
+	for _, example := range examples {
+		for _, problem := range Verify(o, validator, example) {
+			problems = append(problems, fmt.Sprintf("%s: %s", example, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return examples, fmt.Errorf("found %d problems:\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+	return examples, nil
+}
+
+// DryRunTests returns the examples as load tests annotated for a dry run. When
+// they are created in a cluster, the controller renders their pods to a
+// ConfigMap and marks them as succeeded instead of running them, so they can
+// be checked against a deployed controller without workers.
+func DryRunTests(examples []*Example) ([]*grpcv1.LoadTest, error) {
+	var tests []*grpcv1.LoadTest
+	for _, example := range examples {
+		test := new(grpcv1.LoadTest)
+		if err := yaml.Unmarshal(example.Data, test); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", example, err)
+		}
+		if test.Annotations == nil {
+			test.Annotations = make(map[string]string)
+		}
+		test.Annotations[config.DryRunAnnotation] = "true"
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/grpc/test-infra/config"
+)
+
+func newOptions() *Options {
+	o := DefaultOptions()
+	o.SamplesDir = "../../config/samples"
+	o.CRDFile = "../../config/crd/bases/e2etest.grpc.io_loadtests.yaml"
+	defaults, err := RenderDefaultsTemplate("../../config/defaults_template.yaml", DefaultsData{
+		Version:   "latest",
+		KillAfter: 20,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	o.Defaults = defaults
+	return o
+}
+
+var _ = Describe("VerifyAll", func() {
+	It("accepts every example in config/samples", func() {
+		examples, err := VerifyAll(newOptions())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(examples).ToNot(BeEmpty())
+	})
+
+	It("returns an error when the directory has no examples", func() {
+		dir, err := os.MkdirTemp("", "examples")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		o := newOptions()
+		o.SamplesDir = dir
+		_, err = VerifyAll(o)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Verify", func() {
+	var o *Options
+	var validator *validate.SchemaValidator
+	var example *Example
+
+	BeforeEach(func() {
+		o = newOptions()
+		var err error
+		validator, err = NewSchemaValidator(o.CRDFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		examples, err := FindExamples(filepath.Join(o.SamplesDir, "go_example_loadtest.yaml"), o.Variables)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(examples).To(HaveLen(1))
+		example = examples[0]
+	})
+
+	It("accepts a valid example", func() {
+		Expect(Verify(o, validator, example)).To(BeEmpty())
+	})
+
+	It("rejects fields that are not in the schema", func() {
+		example.Data = append(example.Data, []byte("\n  unknownField: true\n")...)
+		problems := Verify(o, validator, example)
+		Expect(problems).ToNot(BeEmpty())
+		Expect(problems[len(problems)-1]).To(ContainSubstring("unknownField"))
+	})
+
+	It("rejects values that do not match the schema", func() {
+		example.Data = append(example.Data, []byte("\n  ipFamily: IPv5\n")...)
+		problems := Verify(o, validator, example)
+		Expect(problems).To(ContainElement(ContainSubstring("schema:")))
+	})
+
+	It("reports placeholders without values", func() {
+		example.Data = append(example.Data, []byte("\n  # ${missing_value}\n")...)
+		problems := Verify(o, validator, example)
+		Expect(problems).To(ContainElement(ContainSubstring("${missing_value}")))
+	})
+})
+
+var _ = Describe("FindExamples", func() {
+	It("splits files into documents and replaces placeholders", func() {
+		dir, err := os.MkdirTemp("", "examples")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		content := "pool: ${workers_pool}\nport: ${DRIVER_PORT}\n---\nname: second\n"
+		Expect(os.WriteFile(filepath.Join(dir, "tests.yaml"), []byte(content), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not yaml"), 0644)).To(Succeed())
+
+		examples, err := FindExamples(dir, map[string]string{"workers_pool": "workers"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(examples).To(HaveLen(2))
+		Expect(string(examples[0].Data)).To(Equal("pool: workers\nport: ${DRIVER_PORT}"))
+		Expect(examples[1].String()).To(HaveSuffix("tests.yaml[1]"))
+	})
+})
+
+var _ = Describe("DryRunTests", func() {
+	It("annotates each example for a dry run", func() {
+		o := newOptions()
+		examples, err := FindExamples(o.SamplesDir, o.Variables)
+		Expect(err).ToNot(HaveOccurred())
+
+		tests, err := DryRunTests(examples)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tests).To(HaveLen(len(examples)))
+		for _, test := range tests {
+			Expect(test.Annotations).To(HaveKeyWithValue(config.DryRunAnnotation, "true"))
+		}
+	})
+})
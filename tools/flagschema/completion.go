@@ -0,0 +1,90 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flagschema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Shells lists the shells for which completion scripts can be written.
+var Shells = []string{"bash", "zsh"}
+
+// completionTemplate is the template of a bash completion script. The zsh
+// script loads the same function through bashcompinit.
+var completionTemplate = template.Must(template.New("completion").Parse(`{{if .Zsh}}#compdef {{.Name}}
+
+autoload -U +X bashcompinit && bashcompinit
+
+{{end}}# {{.Shell}} completion for {{.Name}}
+_{{.Function}}() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+{{- if .ValueFlags}}
+
+    case "${prev}" in
+        {{.ValueFlags}})
+            COMPREPLY=($(compgen -f -- "${cur}"))
+            return
+            ;;
+    esac
+{{- end}}
+
+    COMPREPLY=($(compgen -W "{{.Flags}}" -- "${cur}"))
+}
+
+complete -o default -F _{{.Function}} {{.Name}}
+`))
+
+// WriteCompletion writes a script that completes the flags of the command in
+// the given shell. Flags are completed in the single dash form used by the
+// flag package, and flags that take a value are completed with file names.
+// Subcommands are not completed, since cobra commands provide their own
+// completion command.
+func (c *Command) WriteCompletion(w io.Writer, shell string) error {
+	if shell != "bash" && shell != "zsh" {
+		return fmt.Errorf("unsupported shell %q, expected one of %s", shell, strings.Join(Shells, ", "))
+	}
+
+	var flags, valueFlags []string
+	for _, f := range c.Flags {
+		name := "-" + f.Name
+		flags = append(flags, name)
+		if f.TakesValue() {
+			valueFlags = append(valueFlags, name)
+		}
+	}
+
+	return completionTemplate.Execute(w, struct {
+		Name       string
+		Function   string
+		Shell      string
+		Zsh        bool
+		Flags      string
+		ValueFlags string
+	}{
+		Name:       c.Name,
+		Function:   strings.NewReplacer("-", "_", ".", "_").Replace(c.Name),
+		Shell:      shell,
+		Zsh:        shell == "zsh",
+		Flags:      strings.Join(flags, " "),
+		ValueFlags: strings.Join(valueFlags, "|"),
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flagschema
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteCompletion", func() {
+	var c *Command
+
+	BeforeEach(func() {
+		c = &Command{
+			Name: "prepare-workers",
+			Flags: []*Flag{
+				{Name: "build-only", Type: "bool"},
+				{Name: "l", Type: "stringArray", Repeatable: true},
+				{Name: "p", Type: "string"},
+			},
+		}
+	})
+
+	It("completes all flags and the values of flags that take one", func() {
+		buf := new(bytes.Buffer)
+		Expect(c.WriteCompletion(buf, "bash")).To(Succeed())
+
+		script := buf.String()
+		Expect(script).ToNot(ContainSubstring("compdef"))
+		Expect(script).To(ContainSubstring(`compgen -W "-build-only -l -p"`))
+		Expect(script).To(ContainSubstring("-l|-p)"))
+		Expect(script).To(ContainSubstring("complete -o default -F _prepare_workers prepare-workers"))
+	})
+
+	It("loads bash completion in zsh", func() {
+		buf := new(bytes.Buffer)
+		Expect(c.WriteCompletion(buf, "zsh")).To(Succeed())
+		Expect(buf.String()).To(HavePrefix("#compdef prepare-workers\n"))
+		Expect(buf.String()).To(ContainSubstring("bashcompinit"))
+	})
+
+	It("omits the value case when no flag takes a value", func() {
+		c.Flags = c.Flags[:1]
+		buf := new(bytes.Buffer)
+		Expect(c.WriteCompletion(buf, "bash")).To(Succeed())
+		Expect(buf.String()).ToNot(ContainSubstring("case"))
+	})
+
+	It("rejects unsupported shells", func() {
+		Expect(c.WriteCompletion(new(bytes.Buffer), "fish")).ToNot(Succeed())
+	})
+})
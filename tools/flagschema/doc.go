@@ -0,0 +1,21 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flagschema describes the flags of the command line tools in a
+// machine-readable form. It emits the flags of each tool as JSON and as shell
+// completion scripts, so that wrapper tooling can discover the supported flags
+// without parsing the output of -help.
+package flagschema
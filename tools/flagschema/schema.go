@@ -0,0 +1,210 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flagschema
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Flag describes a single flag of a command.
+type Flag struct {
+	// Name is the name of the flag, without leading dashes.
+	Name string `json:"name"`
+
+	// Shorthand is the single letter abbreviation of the flag, if any.
+	Shorthand string `json:"shorthand,omitempty"`
+
+	// Type is the type of the value of the flag, such as "string", "bool",
+	// "int", "duration" or "stringArray".
+	Type string `json:"type"`
+
+	// Default is the default value of the flag, as shown by -help.
+	Default string `json:"default,omitempty"`
+
+	// Usage is the help text of the flag.
+	Usage string `json:"usage"`
+
+	// Repeatable is true when the flag may be set more than once, with each
+	// value accumulated.
+	Repeatable bool `json:"repeatable,omitempty"`
+
+	// Required is true when the command fails if the flag is not set.
+	Required bool `json:"required,omitempty"`
+}
+
+// TakesValue returns true when the flag must be followed by a value, that
+// is, when it is not a boolean flag.
+func (f *Flag) TakesValue() bool {
+	return f.Type != "bool"
+}
+
+// Command describes the flags of a command and its subcommands.
+type Command struct {
+	// Name is the name of the command.
+	Name string `json:"name"`
+
+	// Flags lists the flags accepted by the command, sorted by name.
+	Flags []*Flag `json:"flags"`
+
+	// Commands lists the subcommands of the command, if any.
+	Commands []*Command `json:"commands,omitempty"`
+}
+
+// WriteJSON writes the command as indented JSON.
+func (c *Command) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// Typed is implemented by flag values that report their type. It matches the
+// Type method of pflag.Value, so custom flag values can be shared between the
+// flag and pflag packages.
+type Typed interface {
+	Type() string
+}
+
+// FromFlagSet describes the flags in a flag set of the standard library.
+func FromFlagSet(name string, fs *flag.FlagSet) *Command {
+	c := &Command{Name: name, Flags: []*Flag{}}
+	fs.VisitAll(func(f *flag.Flag) {
+		flagType := valueType(f.Value)
+		c.Flags = append(c.Flags, &Flag{
+			Name:       f.Name,
+			Type:       flagType,
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Repeatable: isRepeatableType(flagType),
+		})
+	})
+	return c
+}
+
+// FromCobraCommand describes the flags of a cobra command and of all its
+// available subcommands. The flags of each command include the persistent
+// flags inherited from its parents.
+func FromCobraCommand(cmd *cobra.Command) *Command {
+	c := &Command{Name: cmd.Name(), Flags: []*Flag{}}
+	visit := func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flagType := f.Value.Type()
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		c.Flags = append(c.Flags, &Flag{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       flagType,
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Repeatable: isRepeatableType(flagType),
+			Required:   required,
+		})
+	}
+	cmd.LocalFlags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	sort.Slice(c.Flags, func(i, j int) bool {
+		return c.Flags[i].Name < c.Flags[j].Name
+	})
+
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		c.Commands = append(c.Commands, FromCobraCommand(sub))
+	}
+	return c
+}
+
+// valueType returns the type of a flag value of the standard library.
+func valueType(value flag.Value) string {
+	if typed, ok := value.(Typed); ok {
+		return typed.Type()
+	}
+	if boolFlag, ok := value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+		return "bool"
+	}
+	getter, ok := value.(flag.Getter)
+	if !ok {
+		return "string"
+	}
+	switch getter.Get().(type) {
+	case bool:
+		return "bool"
+	case int:
+		return "int"
+	case int64:
+		return "int64"
+	case uint:
+		return "uint"
+	case uint64:
+		return "uint64"
+	case float64:
+		return "float64"
+	case time.Duration:
+		return "duration"
+	default:
+		return "string"
+	}
+}
+
+// isRepeatableType returns true for the types of flags that accumulate their
+// values, following the naming of pflag.
+func isRepeatableType(flagType string) bool {
+	return strings.HasSuffix(flagType, "Array") || strings.HasSuffix(flagType, "Slice") || flagType == "stringToString"
+}
+
+// Options contains the flags that make a tool print its flag schema or a
+// completion script instead of running.
+type Options struct {
+	// Schema prints the flags of the tool as JSON when set.
+	Schema bool
+
+	// Completion is the name of a shell. When set, a completion script for
+	// the shell is printed.
+	Completion string
+}
+
+// BindFlags registers flags for the options on a flag set.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.Schema, "flag-schema", false, "print the flags of the tool as JSON and exit")
+	fs.StringVar(&o.Completion, "completion", "", fmt.Sprintf("print a completion script for a shell and exit, one of %s", strings.Join(Shells, ", ")))
+}
+
+// Handle prints the flag schema or completion script requested by the
+// options, describing the flags in the given flag set. It returns true when
+// something was requested, in which case the tool should exit after it
+// returns. It should be called after the flag set is parsed.
+func (o *Options) Handle(w io.Writer, name string, fs *flag.FlagSet) (bool, error) {
+	if !o.Schema && o.Completion == "" {
+		return false, nil
+	}
+	c := FromFlagSet(name, fs)
+	if o.Schema {
+		return true, c.WriteJSON(w)
+	}
+	return true, c.WriteCompletion(w, o.Completion)
+}
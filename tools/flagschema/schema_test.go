@@ -0,0 +1,131 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flagschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// names is an accumulator flag that reports its type.
+type names []string
+
+func (n *names) String() string {
+	return ""
+}
+
+func (n *names) Set(value string) error {
+	*n = append(*n, value)
+	return nil
+}
+
+func (n *names) Type() string {
+	return "stringArray"
+}
+
+var _ = Describe("FromFlagSet", func() {
+	var fs *flag.FlagSet
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("o", "report.xml", "output file")
+		fs.Bool("verbose", false, "verbose output")
+		fs.Duration("timeout", time.Minute, "timeout")
+		fs.Uint("retries", 3, "retries")
+		fs.Var(new(names), "i", "input files")
+	})
+
+	It("describes each flag sorted by name", func() {
+		c := FromFlagSet("tool", fs)
+		Expect(c.Name).To(Equal("tool"))
+		Expect(c.Flags).To(Equal([]*Flag{
+			{Name: "i", Type: "stringArray", Usage: "input files", Repeatable: true},
+			{Name: "o", Type: "string", Default: "report.xml", Usage: "output file"},
+			{Name: "retries", Type: "uint", Default: "3", Usage: "retries"},
+			{Name: "timeout", Type: "duration", Default: "1m0s", Usage: "timeout"},
+			{Name: "verbose", Type: "bool", Default: "false", Usage: "verbose output"},
+		}))
+	})
+
+	It("returns an empty list for a flag set without flags", func() {
+		c := FromFlagSet("tool", flag.NewFlagSet("empty", flag.ContinueOnError))
+		Expect(c.Flags).To(BeEmpty())
+
+		buf := new(bytes.Buffer)
+		Expect(c.WriteJSON(buf)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(`"flags": []`))
+	})
+})
+
+var _ = Describe("Options", func() {
+	var fs *flag.FlagSet
+	var o Options
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("o", "", "output file")
+		o = Options{}
+		o.BindFlags(fs)
+	})
+
+	It("does nothing when neither flag is set", func() {
+		Expect(fs.Parse(nil)).To(Succeed())
+		buf := new(bytes.Buffer)
+		ok, err := o.Handle(buf, "tool", fs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+		Expect(buf.Len()).To(BeZero())
+	})
+
+	It("prints the schema of the flag set", func() {
+		Expect(fs.Parse([]string{"-flag-schema"})).To(Succeed())
+		buf := new(bytes.Buffer)
+		ok, err := o.Handle(buf, "tool", fs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		c := new(Command)
+		Expect(json.Unmarshal(buf.Bytes(), c)).To(Succeed())
+		Expect(c.Name).To(Equal("tool"))
+		var flagNames []string
+		for _, f := range c.Flags {
+			flagNames = append(flagNames, f.Name)
+		}
+		Expect(flagNames).To(Equal([]string{"completion", "flag-schema", "o"}))
+	})
+
+	It("prints a completion script", func() {
+		Expect(fs.Parse([]string{"-completion", "bash"})).To(Succeed())
+		buf := new(bytes.Buffer)
+		ok, err := o.Handle(buf, "tool", fs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(buf.String()).To(ContainSubstring("complete -o default -F _tool tool"))
+	})
+
+	It("returns an error for an unknown shell", func() {
+		Expect(fs.Parse([]string{"-completion", "tcsh"})).To(Succeed())
+		ok, err := o.Handle(new(bytes.Buffer), "tool", fs)
+		Expect(ok).To(BeTrue())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+func newCleanCommand() *cobra.Command {
+	var terminated bool
+
+	cmd := &cobra.Command{
+		Use:   "clean [NAME...]",
+		Short: "Delete load tests",
+		Long: `Clean deletes the named load tests. With --terminated, it deletes every load
+test that has succeeded or errored instead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if terminated == (len(args) > 0) {
+				return errors.New("specify either the names of tests to delete or --terminated")
+			}
+
+			ctx := cmd.Context()
+			loadTestGetter := newLoadTestGetter(cmd)
+
+			names := args
+			if terminated {
+				list, err := loadTestGetter.List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return fmt.Errorf("could not list tests: %v", err)
+				}
+				names = terminatedTestNames(list.Items)
+			}
+
+			for _, name := range names {
+				if err := loadTestGetter.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+					return fmt.Errorf("could not delete test %q: %v", name, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "loadtest %q deleted\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&terminated, "terminated", false, "delete all tests that have succeeded or errored")
+	return cmd
+}
+
+// terminatedTestNames returns the names of the tests that have terminated.
+func terminatedTestNames(tests []grpcv1.LoadTest) []string {
+	var names []string
+	for _, test := range tests {
+		if test.Status.State.IsTerminated() {
+			names = append(names, test.Name)
+		}
+	}
+	return names
+}
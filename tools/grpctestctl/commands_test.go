@@ -0,0 +1,206 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+	"github.com/grpc/test-infra/tools/flagschema"
+	"github.com/grpc/test-infra/tools/triage"
+)
+
+var _ = Describe("writeStatusTable", func() {
+	It("writes one row per test", func() {
+		now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+		tests := []grpcv1.LoadTest{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-a",
+					CreationTimestamp: metav1.NewTime(now.Add(-5 * time.Minute)),
+				},
+				Status: grpcv1.LoadTestStatus{
+					State: grpcv1.Running,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-b",
+					CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+				},
+				Status: grpcv1.LoadTestStatus{
+					State:   grpcv1.Errored,
+					Reason:  grpcv1.FailedSettingDefaultsError,
+					Message: "something went wrong",
+				},
+			},
+		}
+
+		buf := new(bytes.Buffer)
+		Expect(writeStatusTable(buf, tests, now)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"NAME", "STATE", "REASON", "AGE", "MESSAGE"}))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{"test-a", "Running", "5m"}))
+		Expect(lines[2]).To(HavePrefix("test-b"))
+		Expect(lines[2]).To(ContainSubstring("Errored"))
+		Expect(lines[2]).To(ContainSubstring("something went wrong"))
+	})
+})
+
+var _ = Describe("writeLogs", func() {
+	It("writes a header before each log and terminates it with a newline", func() {
+		logs := []triage.Log{
+			{Test: "test-a", Name: "driver/main", Content: []byte("line 1\nline 2")},
+			{Test: "test-a", Name: "server/main", Content: []byte("line 3\n")},
+		}
+
+		buf := new(bytes.Buffer)
+		Expect(writeLogs(buf, logs)).To(Succeed())
+		Expect(buf.String()).To(Equal("==> test-a/driver/main <==\nline 1\nline 2\n==> test-a/server/main <==\nline 3\n"))
+	})
+})
+
+// fakeLogClient streams fixed chunks of log from StreamDriverLogs. Only the
+// methods used by streamDriverLogs are implemented.
+type fakeLogClient struct {
+	pb.LoadTestServiceClient
+
+	req    *pb.StreamDriverLogsRequest
+	chunks []*pb.LogChunk
+}
+
+func (f *fakeLogClient) StreamDriverLogs(ctx context.Context, req *pb.StreamDriverLogsRequest, opts ...grpc.CallOption) (pb.LoadTestService_StreamDriverLogsClient, error) {
+	f.req = req
+	return &fakeLogStream{chunks: f.chunks}, nil
+}
+
+type fakeLogStream struct {
+	grpc.ClientStream
+
+	chunks []*pb.LogChunk
+}
+
+func (f *fakeLogStream) Recv() (*pb.LogChunk, error) {
+	if len(f.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := f.chunks[0]
+	f.chunks = f.chunks[1:]
+	return chunk, nil
+}
+
+var _ = Describe("streamDriverLogs", func() {
+	It("writes a header before the log of the driver and terminates it with a newline", func() {
+		client := &fakeLogClient{
+			chunks: []*pb.LogChunk{
+				{Pod: "test-a-driver", Content: []byte("line 1\nli")},
+				{Pod: "test-a-driver", Content: []byte("ne 2")},
+			},
+		}
+		req := &pb.StreamDriverLogsRequest{Name: "test-a", Follow: true}
+
+		buf := new(bytes.Buffer)
+		Expect(streamDriverLogs(context.Background(), buf, client, req)).To(Succeed())
+		Expect(buf.String()).To(Equal("==> test-a/test-a-driver <==\nline 1\nline 2\n"))
+		Expect(client.req).To(Equal(req))
+	})
+
+	It("rejects following without a gateway", func() {
+		cmd := NewRootCommand()
+		cmd.SetArgs([]string{"logs", "--follow", "test-a"})
+		Expect(cmd.Execute()).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("testNamespace", func() {
+	It("returns the namespace given to the root command", func() {
+		for _, name := range []string{"status", "logs", "clean"} {
+			cmd, _, err := NewRootCommand().Find([]string{name})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cmd.ParseFlags([]string{"-n", "tests"})).To(Succeed())
+			Expect(testNamespace(cmd)).To(Equal("tests"), name)
+		}
+	})
+
+	It("defaults to the default namespace", func() {
+		cmd, _, err := NewRootCommand().Find([]string{"status"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		Expect(testNamespace(cmd)).To(Equal("default"))
+	})
+})
+
+var _ = Describe("terminatedTestNames", func() {
+	It("returns only tests that succeeded or errored", func() {
+		tests := []grpcv1.LoadTest{
+			{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Status: grpcv1.LoadTestStatus{State: grpcv1.Running}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "succeeded"}, Status: grpcv1.LoadTestStatus{State: grpcv1.Succeeded}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "errored"}, Status: grpcv1.LoadTestStatus{State: grpcv1.Errored}},
+		}
+		Expect(terminatedTestNames(tests)).To(Equal([]string{"succeeded", "errored"}))
+	})
+})
+
+var _ = Describe("flag-schema", func() {
+	It("describes the flags of each subcommand", func() {
+		cmd := NewRootCommand()
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetArgs([]string{"flag-schema"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		schema := new(flagschema.Command)
+		Expect(json.Unmarshal(buf.Bytes(), schema)).To(Succeed())
+		Expect(schema.Name).To(Equal("grpctestctl"))
+
+		var run *flagschema.Command
+		for _, sub := range schema.Commands {
+			if sub.Name == "run" {
+				run = sub
+			}
+		}
+		Expect(run).ToNot(BeNil())
+
+		flags := make(map[string]*flagschema.Flag)
+		for _, f := range run.Flags {
+			flags[f.Name] = f
+		}
+		Expect(flags).To(HaveKey("kubeconfig"))
+		Expect(flags["file"]).To(Equal(&flagschema.Flag{
+			Name:       "file",
+			Shorthand:  "f",
+			Type:       "stringArray",
+			Default:    "[]",
+			Usage:      "input files containing load test configurations",
+			Repeatable: true,
+			Required:   true,
+		}))
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/grpc/test-infra/tools/flagschema"
+)
+
+func newFlagSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flag-schema",
+		Short: "Print the flags of all commands as JSON",
+		Long: `Flag-schema prints the flags of grpctestctl and of each of its subcommands as
+JSON, so that wrapper tooling can discover the supported flags. Shell
+completion scripts are printed by the completion command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flagschema.FromCobraCommand(cmd.Root()).WriteJSON(cmd.OutOrStdout())
+		},
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/grpc/test-infra/tools/prebuilt"
+)
+
+func newPrepareImagesCommand() *cobra.Command {
+	var o prebuilt.PrepareOptions
+	var languages []string
+
+	cmd := &cobra.Command{
+		Use:   "prepare-images -p PREFIX -t TAG -r DIR -l LANGUAGE:GITREF... [flags]",
+		Short: "Build and push prebuilt worker images",
+		Long: `Prepare-images builds the worker images for the given languages and pushes
+them to a container registry, so that load tests can use them without
+building the workers at run time.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			o.Languages, err = prebuilt.ParseLanguageSpecs(languages)
+			if err != nil {
+				return err
+			}
+			return prebuilt.Prepare(&o)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&o.ImagePrefix, "prefix", "p", "", "image registry to push images")
+	flags.StringVarP(&o.Tag, "tag", "t", "", "tag that identifies the images built and pushed for the current test")
+	flags.StringVarP(&o.DockerfileRoot, "dockerfile-root", "r", "", "root directory of Dockerfiles to build prebuilt images")
+	flags.StringArrayVarP(&languages, "language", "l", nil, "language, repository and gitref to build, for example cxx:<commit-sha> or cxx:grpc/grpc:<commit-sha>")
+	flags.BoolVar(&o.BuildOnly, "build-only", false, "build the images without pushing them to a container registry")
+	return cmd
+}
+
+func newDeleteImagesCommand() *cobra.Command {
+	var imagePrefix string
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "delete-images -p PREFIX -t TAG",
+		Short: "Delete prebuilt worker images",
+		Long: `Delete-images deletes the images with the given tag from all repositories
+under a prefix. Images that have other tags are untagged instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prebuilt.Delete(imagePrefix, tag)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&imagePrefix, "prefix", "p", "", "root repository to search for images")
+	flags.StringVarP(&tag, "tag", "t", "", "tag of the images to delete")
+	return cmd
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/grpc/test-infra/proto/loadtestapi"
+	"github.com/grpc/test-infra/tools/runner"
+	"github.com/grpc/test-infra/tools/triage"
+)
+
+func newLogsCommand() *cobra.Command {
+	var gateway string
+	var gatewayTokenFile string
+	var gatewayPlaintext bool
+	var follow bool
+	var tail int64
+
+	cmd := &cobra.Command{
+		Use:   "logs NAME...",
+		Short: "Print the container logs of load tests",
+		Long: `Logs prints the logs of every container in the pods of the named load
+tests, each preceded by a header naming the test and the container.
+
+With --gateway, only the log of the driver is printed, and it is read through
+the LoadTest API gateway, so no Kubernetes credentials are needed. The log of a
+running test can then be followed with --follow. Without --namespace, tests are
+read from the namespace of the gateway instead of the default namespace.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if gateway == "" {
+				if follow || tail != 0 {
+					return errors.New("--follow and --tail require --gateway")
+				}
+				logs, err := triage.LogsFromCluster(cmd.Context(), newLoadTestGetter(cmd), runner.NewPodsGetter(), args)
+				if err != nil {
+					return err
+				}
+				return writeLogs(cmd.OutOrStdout(), logs)
+			}
+
+			if follow && len(args) > 1 {
+				return errors.New("--follow accepts a single test")
+			}
+			if tail < 0 {
+				return errors.New("--tail must not be negative")
+			}
+			conn, ctx, err := dialGateway(cmd.Context(), gateway, gatewayTokenFile, gatewayPlaintext)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			client := pb.NewLoadTestServiceClient(conn)
+			namespace, _ := cmd.Flags().GetString("namespace")
+			for _, name := range args {
+				req := &pb.StreamDriverLogsRequest{
+					Namespace: namespace,
+					Name:      name,
+					Follow:    follow,
+					TailLines: tail,
+				}
+				if err := streamDriverLogs(ctx, cmd.OutOrStdout(), client, req); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&gateway, "gateway", "", "address of the LoadTest API gateway, through which the log of the driver is read instead of the Kubernetes API")
+	flags.StringVar(&gatewayTokenFile, "gateway-token-file", "", "file containing the bearer token sent to the gateway")
+	flags.BoolVar(&gatewayPlaintext, "gateway-plaintext", false, "connect to the gateway without TLS")
+	flags.BoolVarP(&follow, "follow", "f", false, "keep printing the log of the driver until it stops (requires --gateway)")
+	flags.Int64Var(&tail, "tail", 0, "number of lines at the end of the log of the driver to start from, the whole log is printed if zero (requires --gateway)")
+	return cmd
+}
+
+// dialGateway connects to the LoadTest API gateway. The returned context
+// carries the bearer token read from the token file, when one is given.
+func dialGateway(ctx context.Context, address, tokenFile string, plaintext bool) (*grpc.ClientConn, context.Context, error) {
+	creds := credentials.NewTLS(&tls.Config{})
+	if plaintext {
+		creds = insecure.NewCredentials()
+	}
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read gateway token: %v", err)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to gateway %s: %v", address, err)
+	}
+	return conn, ctx, nil
+}
+
+// streamDriverLogs writes the log of the driver of a test as it is received
+// from the gateway, preceded by a header like those written by writeLogs.
+func streamDriverLogs(ctx context.Context, w io.Writer, client pb.LoadTestServiceClient, req *pb.StreamDriverLogsRequest) error {
+	stream, err := client.StreamDriverLogs(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to stream log of test %s: %v", req.GetName(), err)
+	}
+	header := false
+	lastByte := byte('\n')
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stream log of test %s: %v", req.GetName(), err)
+		}
+		if !header {
+			if _, err := fmt.Fprintf(w, "==> %s/%s <==\n", req.GetName(), chunk.GetPod()); err != nil {
+				return err
+			}
+			header = true
+		}
+		if len(chunk.GetContent()) == 0 {
+			continue
+		}
+		if _, err := w.Write(chunk.GetContent()); err != nil {
+			return err
+		}
+		lastByte = chunk.GetContent()[len(chunk.GetContent())-1]
+	}
+	if lastByte != '\n' {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLogs writes the content of each log, preceded by a header.
+func writeLogs(w io.Writer, logs []triage.Log) error {
+	for _, l := range logs {
+		if _, err := fmt.Fprintf(w, "==> %s/%s <==\n", l.Test, l.Name); err != nil {
+			return err
+		}
+		if _, err := w.Write(l.Content); err != nil {
+			return err
+		}
+		if len(l.Content) > 0 && l.Content[len(l.Content)-1] != '\n' {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"flag"
+	"os"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientset "github.com/grpc/test-infra/clientset"
+	"github.com/grpc/test-infra/logging"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+// NewRootCommand returns the grpctestctl command, with all subcommands
+// attached.
+func NewRootCommand() *cobra.Command {
+	var kubeconfig string
+	var namespace string
+	logOpts := logging.OptionsFromEnv()
+
+	cmd := &cobra.Command{
+		Use:           "grpctestctl",
+		Short:         "Run and manage gRPC load tests",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if kubeconfig != "" {
+				if err := os.Setenv(clientcmd.RecommendedConfigPathEnvVar, kubeconfig); err != nil {
+					return err
+				}
+			}
+			_, err := logging.Setup(logOpts)
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "path to the kubeconfig file (defaults to $KUBECONFIG, the in-cluster configuration or ~/.kube/config)")
+	cmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "namespace of the load tests (defaults to the default namespace, or to the namespace of the gateway with logs --gateway)")
+	logFlags := flag.NewFlagSet("logging", flag.ContinueOnError)
+	logOpts.BindFlags(logFlags)
+	cmd.PersistentFlags().AddGoFlagSet(logFlags)
+
+	cmd.AddCommand(
+		newRunCommand(),
+		newStatusCommand(),
+		newLogsCommand(),
+		newCleanCommand(),
+		newPrepareImagesCommand(),
+		newDeleteImagesCommand(),
+		newValidateCommand(),
+		newFlagSchemaCommand(),
+	)
+	return cmd
+}
+
+// testNamespace returns the namespace given by the --namespace flag of a
+// command, or the default namespace when the flag is not set.
+func testNamespace(cmd *cobra.Command) string {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	if namespace == "" {
+		return corev1.NamespaceDefault
+	}
+	return namespace
+}
+
+// newLoadTestGetter returns a client for the LoadTest resources in the
+// namespace given by the --namespace flag of a command.
+func newLoadTestGetter(cmd *cobra.Command) clientset.LoadTestGetter {
+	return runner.NewLoadTestGetterForNamespace(testNamespace(cmd))
+}
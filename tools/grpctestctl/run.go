@@ -0,0 +1,80 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func newRunCommand() *cobra.Command {
+	o := runner.DefaultOptions()
+	var concurrencyLevels []string
+
+	cmd := &cobra.Command{
+		Use:   "run -f FILE... [flags]",
+		Short: "Run load tests and report their results",
+		Long: `Run creates the load tests in the given files, waits for them to finish and
+writes an xunit XML report. Tests are assigned to queues based on an
+annotation, and each queue runs its tests with a separate concurrency level.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, c := range concurrencyLevels {
+				if err := o.ConcurrencyLevels.Set(c); err != nil {
+					return err
+				}
+			}
+			return runner.RunTests(cmd.Context(), o)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVarP(&o.FileNames, "file", "f", nil, "input files containing load test configurations")
+	flags.StringVarP(&o.OutputFile, "output", "o", "", "name of the output file for xunit xml report")
+	flags.StringVar(&o.JSONOutputFile, "json-output", "", "name of the output file for json report")
+	flags.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "namespace to create load tests in")
+	flags.BoolVar(&o.NamespacePerRun, "namespace-per-run", false, "create tests in a new namespace with a generated name, which is deleted at the end of the run")
+	flags.BoolVar(&o.ShardScenarios, "shard-scenarios", false, "split configurations with several scenarios into one test per scenario")
+	flags.StringArrayVarP(&concurrencyLevels, "concurrency", "c", nil, "concurrency level, in the form [<queue name>:]<concurrency level>")
+	flags.StringVar(&o.AnnotationKey, "annotation-key", o.AnnotationKey, "annotation key to parse for queue assignment")
+	flags.StringVar(&o.RoutingRulesFile, "routing-rules", "", "file containing rules that assign tests to queues")
+	flags.DurationVar(&o.PollingInterval, "polling-interval", o.PollingInterval, "polling interval for load test status")
+	flags.UintVar(&o.PollingRetries, "polling-retries", o.PollingRetries, "maximum retries in case of communication failure")
+	flags.Float64Var(&o.APIQPS, "api-qps", o.APIQPS, "rate of requests per second to the Kubernetes API, shared by all clients of the runner")
+	flags.IntVar(&o.APIBurst, "api-burst", o.APIBurst, "number of requests that may be sent to the Kubernetes API at once, above the rate set by --api-qps")
+	flags.BoolVar(&o.DeleteSuccessfulTests, "delete-successful-tests", false, "delete tests immediately in case of successful termination")
+	flags.StringVar(&o.LogURLPrefix, "log-url-prefix", "", "prefix for log urls")
+	flags.StringVar(&o.DashboardURLTemplate, "dashboard-url", "", "Go template for a link to the dashboard of each test, such as https://grafana.example.com/d/abc?var-test={{.Name}}&from={{.From}}&to={{.To}} (defaults to the dashboardURLTemplate of the defaults)")
+	flags.StringArrayVar(&o.DefaultsFiles, "defaults-file", nil, "defaults files of the controller, whose artifacts and node agent settings configure the upload of artifacts and the collection of node metrics (optional)")
+	flags.StringVar(&o.PushgatewayURL, "pushgateway-url", "", "URL of a Prometheus pushgateway to receive metrics (optional)")
+	flags.StringVar(&o.PushgatewayJob, "pushgateway-job", o.PushgatewayJob, "job name used to group metrics in the pushgateway")
+	flags.DurationVar(&o.PushInterval, "push-interval", o.PushInterval, "interval between pushes of metrics to the pushgateway")
+	flags.IntVar(&o.WarmupSeconds, "warmup-seconds", o.WarmupSeconds, "override for the warmup duration of each scenario, in seconds (optional)")
+	flags.IntVar(&o.BenchmarkSeconds, "benchmark-seconds", o.BenchmarkSeconds, "override for the benchmark duration of each scenario, in seconds (optional)")
+	flags.BoolVar(&o.CollectProfiles, "collect-profiles", false, "collect CPU and heap profiles from workers with a pprof port during the benchmark")
+	flags.StringArrayVar(&o.BeforeTestHooks, "before-test", nil, "hook invoked before each test is created, either a shell command or an http(s) URL (optional)")
+	flags.StringArrayVar(&o.AfterTestHooks, "after-test", nil, "hook invoked after each test completes, either a shell command or an http(s) URL (optional)")
+	flags.DurationVar(&o.HookTimeout, "hook-timeout", o.HookTimeout, "time allowed for each hook to complete")
+	flags.DurationVar(&o.SkipSucceededWithin, "skip-succeeded-within", 0, "skip tests with an identical spec to a test that succeeded within this duration (optional)")
+	flags.StringVar(&o.Duplicates, "duplicates", o.Duplicates, "policy for tests with the same spec as an earlier test in the run: allow, skip or rename")
+	flags.IntVar(&o.RetainRuns, "retain-runs", 0, "number of most recent runs whose artifacts are kept in each output directory (optional)")
+	flags.DurationVar(&o.RetainRunsFor, "retain-runs-for", 0, "age after which the artifacts of runs are removed from each output directory (optional)")
+	flags.BoolVar(&o.ArchiveRuns, "archive-runs", false, "compress the test directories of each queue into a single archive at the end of the run")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
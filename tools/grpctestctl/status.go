@@ -0,0 +1,73 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+func newStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [NAME...]",
+		Short: "Show the status of load tests",
+		Long: `Status prints the state of the named load tests, or of all load tests when
+no names are given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			loadTestGetter := newLoadTestGetter(cmd)
+
+			var tests []grpcv1.LoadTest
+			if len(args) == 0 {
+				list, err := loadTestGetter.List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return fmt.Errorf("could not list tests: %v", err)
+				}
+				tests = list.Items
+			}
+			for _, name := range args {
+				test, err := loadTestGetter.Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return fmt.Errorf("could not get test %q: %v", name, err)
+				}
+				tests = append(tests, *test)
+			}
+
+			return writeStatusTable(cmd.OutOrStdout(), tests, time.Now())
+		},
+	}
+}
+
+// writeStatusTable writes the name, state, reason, age and message of each
+// test as an aligned table.
+func writeStatusTable(w io.Writer, tests []grpcv1.LoadTest, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATE\tREASON\tAGE\tMESSAGE")
+	for _, test := range tests {
+		age := duration.HumanDuration(now.Sub(test.CreationTimestamp.Time))
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", test.Name, test.Status.State, test.Status.Reason, age, test.Status.Message)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func newValidateCommand() *cobra.Command {
+	var fileNames []string
+	var concurrencyLevels []string
+	var annotationKey string
+	var routingRulesFile string
+
+	cmd := &cobra.Command{
+		Use:   "validate -f FILE... [flags]",
+		Short: "Check load test configurations without running them",
+		Long: `Validate decodes the load tests in the given files and checks them for
+problems that would cause the tests to fail at run time. When concurrency
+levels are given, it also checks that every queue has a concurrency level.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configs, err := runner.DecodeFromFiles(fileNames)
+			if err != nil {
+				return fmt.Errorf("failed to decode: %v", err)
+			}
+
+			var problems []string
+			for _, config := range configs {
+				if err := ValidateLoadTest(config); err != nil {
+					problems = append(problems, err.Error())
+				}
+			}
+
+			if len(concurrencyLevels) > 0 {
+				c := runner.ConcurrencyLevels{}
+				for _, level := range concurrencyLevels {
+					if err := c.Set(level); err != nil {
+						return err
+					}
+				}
+				queueSelector := runner.QueueSelectorFromAnnotation(annotationKey)
+				if routingRulesFile != "" {
+					rules, err := runner.LoadRoutingRules(routingRulesFile)
+					if err != nil {
+						return err
+					}
+					queueSelector = runner.QueueSelectorFromRules(rules, annotationKey)
+				}
+				configQueueMap := runner.CreateQueueMap(configs, queueSelector)
+				if err := runner.ValidateConcurrencyLevels(configQueueMap, c); err != nil {
+					problems = append(problems, err.Error())
+				}
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("found %d problems:\n%s", len(problems), strings.Join(problems, "\n"))
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d load tests are valid\n", len(configs))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVarP(&fileNames, "file", "f", nil, "input files containing load test configurations")
+	flags.StringArrayVarP(&concurrencyLevels, "concurrency", "c", nil, "concurrency level, in the form [<queue name>:]<concurrency level>")
+	flags.StringVar(&annotationKey, "annotation-key", "pool", "annotation key to parse for queue assignment")
+	flags.StringVar(&routingRulesFile, "routing-rules", "", "file containing rules that assign tests to queues")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// ValidateLoadTest checks a load test for configuration problems that would
+// cause it to fail once it is created. It returns an error describing all of
+// the problems that were found, or nil if there were none.
+func ValidateLoadTest(test *grpcv1.LoadTest) error {
+	var problems []string
+	addProblem := func(format string, v ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, v...))
+	}
+
+	if test.Name == "" {
+		addProblem("missing name")
+	}
+	// Tests with generators send load without the driver, so they need
+	// neither servers, clients nor scenarios. Interop tests need a server,
+	// but the driver acts as their client.
+	hasGenerators := len(test.Spec.Generators) > 0
+	isInterop := test.Spec.Interop != nil
+	if len(test.Spec.Servers) == 0 && !hasGenerators {
+		addProblem("missing servers")
+	}
+	if len(test.Spec.Clients) == 0 && !hasGenerators && !isInterop {
+		addProblem("missing clients")
+	}
+	if isInterop {
+		if len(test.Spec.Interop.TestCases) == 0 {
+			addProblem("missing interop test cases")
+		}
+		if server := test.Spec.Interop.Server; server != nil {
+			found := false
+			for _, s := range test.Spec.Servers {
+				found = found || (s.Name != nil && *s.Name == *server)
+			}
+			if !found {
+				addProblem("interop server %q is not a server of the test", *server)
+			}
+		}
+	}
+	for i, client := range test.Spec.Clients {
+		if client.Replicas != nil && *client.Replicas < 1 {
+			addProblem("replicas of client at index %d must be positive", i)
+		}
+	}
+	clientNames := make(map[string]bool)
+	for _, client := range kubehelpers.ExpandClients(test.Spec.Clients) {
+		if client.Name == nil {
+			continue
+		}
+		if clientNames[*client.Name] {
+			addProblem("duplicate client name %q", *client.Name)
+		}
+		clientNames[*client.Name] = true
+	}
+	generatorNames := make(map[string]bool)
+	for i, generator := range test.Spec.Generators {
+		if len(generator.Run) == 0 || generator.Run[0].Image == "" {
+			addProblem("generator at index %d is missing a run image", i)
+		}
+		if generator.Name == nil {
+			continue
+		}
+		if generatorNames[*generator.Name] {
+			addProblem("duplicate generator name %q", *generator.Name)
+		}
+		generatorNames[*generator.Name] = true
+	}
+
+	if test.Spec.ScenariosJSON == "" {
+		if !hasGenerators && !isInterop {
+			addProblem("missing scenariosJSON")
+		}
+	} else if !json.Valid([]byte(test.Spec.ScenariosJSON)) {
+		addProblem("scenariosJSON is not valid JSON")
+	} else if _, err := kubehelpers.UpdateConfigMapWithScenarioOverrides(test.Annotations, test.Spec.ScenariosJSON); err != nil {
+		addProblem("invalid scenario overrides: %v", err)
+	}
+	if test.Spec.LoadProfile != nil {
+		if test.Spec.ScenariosJSON == "" {
+			addProblem("loadProfile requires scenariosJSON")
+		} else if json.Valid([]byte(test.Spec.ScenariosJSON)) {
+			if _, err := kubehelpers.UpdateConfigMapWithLoadProfile(test.Spec.LoadProfile, test.Spec.ScenariosJSON); err != nil {
+				addProblem("invalid load profile: %v", err)
+			}
+		}
+	}
+
+	// A timeout of zero is computed from the scenarios by the controller.
+	timeoutSet := test.Spec.TimeoutSeconds != 0
+	if test.Spec.TimeoutSeconds < 0 {
+		addProblem("timeoutSeconds must be positive")
+	}
+	if timeoutSet && test.Spec.TTLSeconds < test.Spec.TimeoutSeconds {
+		addProblem("ttlSeconds (%d) must not be less than timeoutSeconds (%d)", test.Spec.TTLSeconds, test.Spec.TimeoutSeconds)
+	}
+	if killAfter := test.Spec.KillAfterSeconds; killAfter != nil {
+		if *killAfter < 0 {
+			addProblem("killAfterSeconds must not be negative")
+		} else if timeoutSet && *killAfter >= test.Spec.TimeoutSeconds {
+			addProblem("killAfterSeconds (%d) must be less than timeoutSeconds (%d)", *killAfter, test.Spec.TimeoutSeconds)
+		}
+	}
+	if gracePeriod := test.Spec.TerminationGracePeriodSeconds; gracePeriod != nil {
+		if *gracePeriod <= config.WorkerStopDelaySeconds {
+			addProblem("terminationGracePeriodSeconds must be longer than the worker stop delay of %ds", config.WorkerStopDelaySeconds)
+		} else if timeoutSet && *gracePeriod > int64(test.Spec.TimeoutSeconds) {
+			addProblem("terminationGracePeriodSeconds (%d) must not exceed timeoutSeconds (%d)", *gracePeriod, test.Spec.TimeoutSeconds)
+		}
+	}
+	if test.Spec.SoakHours != nil && *test.Spec.SoakHours < 1 {
+		addProblem("soakHours must be positive")
+	}
+
+	if len(problems) > 0 {
+		name := test.Name
+		if name == "" {
+			name = "<unnamed>"
+		}
+		return fmt.Errorf("load test %q: %s", name, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpctestctl
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/optional"
+)
+
+var _ = Describe("ValidateLoadTest", func() {
+	var test *grpcv1.LoadTest
+
+	BeforeEach(func() {
+		test = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "example-test",
+			},
+			Spec: grpcv1.LoadTestSpec{
+				Servers:        []grpcv1.Server{{}},
+				Clients:        []grpcv1.Client{{}},
+				ScenariosJSON:  `{"scenarios": [{"warmup_seconds": 5, "benchmark_seconds": 30}]}`,
+				TimeoutSeconds: 900,
+				TTLSeconds:     1800,
+			},
+		}
+	})
+
+	It("accepts a valid load test", func() {
+		Expect(ValidateLoadTest(test)).To(Succeed())
+	})
+
+	It("requires servers and clients", func() {
+		test.Spec.Servers = nil
+		test.Spec.Clients = nil
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("missing servers"))
+		Expect(err.Error()).To(ContainSubstring("missing clients"))
+	})
+
+	It("does not require servers, clients or scenarios with generators", func() {
+		test.Spec.Servers = nil
+		test.Spec.Clients = nil
+		test.Spec.ScenariosJSON = ""
+		test.Spec.Generators = []grpcv1.Generator{
+			{Run: []corev1.Container{{Image: "ghz"}}},
+		}
+		Expect(ValidateLoadTest(test)).To(Succeed())
+	})
+
+	It("rejects generators without a run image or with duplicate names", func() {
+		test.Spec.Generators = []grpcv1.Generator{
+			{Name: optional.StringPtr("ghz")},
+			{Name: optional.StringPtr("ghz"), Run: []corev1.Container{{Image: "ghz"}}},
+		}
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("generator at index 0 is missing a run image"))
+		Expect(err.Error()).To(ContainSubstring(`duplicate generator name "ghz"`))
+	})
+
+	It("does not require clients or scenarios for interop tests", func() {
+		test.Spec.Clients = nil
+		test.Spec.ScenariosJSON = ""
+		test.Spec.Interop = &grpcv1.Interop{TestCases: []string{"empty_unary"}}
+		Expect(ValidateLoadTest(test)).To(Succeed())
+	})
+
+	It("rejects interop tests without test cases or with an unknown server", func() {
+		test.Spec.Interop = &grpcv1.Interop{Server: optional.StringPtr("missing")}
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("missing interop test cases"))
+		Expect(err.Error()).To(ContainSubstring(`interop server "missing" is not a server of the test`))
+	})
+
+	It("rejects clients without replicas", func() {
+		replicas := int32(0)
+		test.Spec.Clients[0].Replicas = &replicas
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("replicas of client at index 0 must be positive"))
+	})
+
+	It("rejects replicas that share a name with another client", func() {
+		replicas := int32(2)
+		test.Spec.Clients = []grpcv1.Client{
+			{Name: optional.StringPtr("scale"), Replicas: &replicas},
+			{Name: optional.StringPtr("scale-1")},
+		}
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`duplicate client name "scale-1"`))
+	})
+
+	It("rejects invalid scenario JSON", func() {
+		test.Spec.ScenariosJSON = `{"scenarios": [`
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not valid JSON"))
+	})
+
+	It("rejects invalid scenario overrides", func() {
+		test.Annotations = map[string]string{
+			config.BenchmarkSecondsAnnotation: "zero",
+		}
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid scenario overrides"))
+	})
+
+	It("rejects an invalid load profile", func() {
+		test.Spec.LoadProfile = &grpcv1.LoadProfile{Type: grpcv1.PoissonLoad}
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid load profile"))
+	})
+
+	It("rejects a TTL shorter than the timeout", func() {
+		test.Spec.TTLSeconds = 60
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ttlSeconds"))
+	})
+
+	It("rejects a kill-after that is not less than the timeout", func() {
+		test.Spec.KillAfterSeconds = optional.Int32Ptr(900)
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("killAfterSeconds"))
+	})
+
+	It("rejects a grace period shorter than the worker stop delay or longer than the timeout", func() {
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(config.WorkerStopDelaySeconds)
+		Expect(ValidateLoadTest(test)).ToNot(Succeed())
+
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(901)
+		Expect(ValidateLoadTest(test)).ToNot(Succeed())
+
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(120)
+		test.Spec.KillAfterSeconds = optional.Int32Ptr(30)
+		Expect(ValidateLoadTest(test)).To(Succeed())
+	})
+
+	It("accepts a test without a timeout", func() {
+		test.Spec.TimeoutSeconds = 0
+		test.Spec.TerminationGracePeriodSeconds = optional.Int64Ptr(120)
+		test.Spec.KillAfterSeconds = optional.Int32Ptr(30)
+		Expect(ValidateLoadTest(test)).To(Succeed())
+	})
+
+	It("names the test in the error", func() {
+		test.Spec.TimeoutSeconds = -1
+		err := ValidateLoadTest(test)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(`load test "example-test":`))
+	})
+})
@@ -0,0 +1,370 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Kind identifies the kind of a type in a model.
+type Kind string
+
+const (
+	// StringKind is the kind of strings.
+	StringKind Kind = "string"
+
+	// IntegerKind is the kind of integers.
+	IntegerKind Kind = "integer"
+
+	// NumberKind is the kind of floating point numbers.
+	NumberKind Kind = "number"
+
+	// BooleanKind is the kind of booleans.
+	BooleanKind Kind = "boolean"
+
+	// IntOrStringKind is the kind of values that are either an integer or
+	// a string, such as resource quantities.
+	IntOrStringKind Kind = "int-or-string"
+
+	// AnyKind is the kind of values whose schema is not known.
+	AnyKind Kind = "any"
+
+	// ArrayKind is the kind of lists, whose elements have the Elem type.
+	ArrayKind Kind = "array"
+
+	// MapKind is the kind of objects with arbitrary keys, whose values have
+	// the Elem type.
+	MapKind Kind = "map"
+
+	// ModelKind is the kind of objects described by the model named by the
+	// Model field.
+	ModelKind Kind = "model"
+)
+
+// Type describes the type of a field.
+type Type struct {
+	// Kind is the kind of the type.
+	Kind Kind
+
+	// Elem is the type of the elements of arrays and the values of maps.
+	Elem *Type
+
+	// Model is the name of the model of objects with the ModelKind.
+	Model string
+
+	// Enum lists the allowed values of strings. Any string is allowed when
+	// it is empty.
+	Enum []string
+}
+
+// Field describes a field of a model.
+type Field struct {
+	// Name is the name of the field in JSON.
+	Name string
+
+	// Description describes the field.
+	Description string
+
+	// Type is the type of the field.
+	Type *Type
+
+	// Required is true if the field must be set.
+	Required bool
+}
+
+// Model describes an object with a fixed set of fields.
+type Model struct {
+	// Name is the name of the model, which is unique in a set of models.
+	Name string
+
+	// Description describes the model.
+	Description string
+
+	// Fields lists the fields of the model, sorted by name.
+	Fields []*Field
+}
+
+// LoadSchema reads the OpenAPI schema of a version of a
+// CustomResourceDefinition from a file.
+func LoadSchema(crdFile string, version string) (*apiextensionsv1.JSONSchemaProps, error) {
+	data, err := os.ReadFile(crdFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRD file %q: %v", crdFile, err)
+	}
+	crd := new(apiextensionsv1.CustomResourceDefinition)
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		return nil, fmt.Errorf("failed to parse CRD file %q: %v", crdFile, err)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+			return v.Schema.OpenAPIV3Schema, nil
+		}
+	}
+	return nil, fmt.Errorf("no schema for version %q in %q", version, crdFile)
+}
+
+// builder collects the models of a schema.
+type builder struct {
+	models []*Model
+	// names contains the names of the models that were added.
+	names map[string]bool
+	// keys maps the structural key of each schema to the name of its model.
+	keys map[string]string
+}
+
+// Build returns the models of the objects in a schema, starting with the
+// model of the root object, which has the given name. Objects with identical
+// schemas, such as the containers of clients and servers, share a model.
+// Models are named after the field that holds them, singularized for
+// elements of arrays, and prefixed with the name of the parent model when the
+// name is already taken. The fields of the root object are always prefixed.
+func Build(schema *apiextensionsv1.JSONSchemaProps, rootName string) ([]*Model, error) {
+	b := &builder{
+		names: make(map[string]bool),
+		keys:  make(map[string]string),
+	}
+	if !isModel(schema) {
+		return nil, fmt.Errorf("root schema of %s does not describe an object with properties", rootName)
+	}
+	if _, err := b.model(schema, rootName, ""); err != nil {
+		return nil, err
+	}
+	return b.models, nil
+}
+
+// isModel returns true if a schema describes an object with properties.
+func isModel(schema *apiextensionsv1.JSONSchemaProps) bool {
+	return schema.Type == "object" && len(schema.Properties) > 0
+}
+
+// model returns the name of the model of an object schema, and adds the model
+// if it was not added yet.
+func (b *builder) model(schema *apiextensionsv1.JSONSchemaProps, name string, parent string) (string, error) {
+	key, err := structuralKey(schema)
+	if err != nil {
+		return "", err
+	}
+	if existing, ok := b.keys[key]; ok {
+		return existing, nil
+	}
+
+	candidates := []string{name, parent + name}
+	name = ""
+	for _, candidate := range candidates {
+		if !b.names[candidate] {
+			name = candidate
+			break
+		}
+	}
+	for i := 2; name == ""; i++ {
+		candidate := fmt.Sprintf("%s%s%d", parent, candidates[0], i)
+		if !b.names[candidate] {
+			name = candidate
+		}
+	}
+	b.names[name] = true
+	b.keys[key] = name
+
+	model := &Model{Name: name, Description: schema.Description}
+	b.models = append(b.models, model)
+
+	required := make(map[string]bool)
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	var properties []string
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	for _, property := range properties {
+		propertySchema := schema.Properties[property]
+		childName := pascalCase(property)
+		if parent == "" {
+			childName = name + childName
+		}
+		fieldType, err := b.fieldType(&propertySchema, childName, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to build field %s of %s: %v", property, name, err)
+		}
+		model.Fields = append(model.Fields, &Field{
+			Name:        property,
+			Description: propertySchema.Description,
+			Type:        fieldType,
+			Required:    required[property],
+		})
+	}
+	return name, nil
+}
+
+// fieldType returns the type of a field with a schema, adding models for the
+// objects in the schema.
+func (b *builder) fieldType(schema *apiextensionsv1.JSONSchemaProps, name string, parent string) (*Type, error) {
+	if schema.XIntOrString {
+		return &Type{Kind: IntOrStringKind}, nil
+	}
+	switch schema.Type {
+	case "string":
+		var enum []string
+		for _, value := range schema.Enum {
+			var s string
+			if err := json.Unmarshal(value.Raw, &s); err != nil {
+				return nil, fmt.Errorf("failed to parse enum value %s: %v", value.Raw, err)
+			}
+			enum = append(enum, s)
+		}
+		return &Type{Kind: StringKind, Enum: enum}, nil
+	case "integer":
+		return &Type{Kind: IntegerKind}, nil
+	case "number":
+		return &Type{Kind: NumberKind}, nil
+	case "boolean":
+		return &Type{Kind: BooleanKind}, nil
+	case "array":
+		elem := &Type{Kind: AnyKind}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			var err error
+			elem, err = b.fieldType(schema.Items.Schema, singular(name), parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &Type{Kind: ArrayKind, Elem: elem}, nil
+	case "object":
+		if isModel(schema) {
+			model, err := b.model(schema, name, parent)
+			if err != nil {
+				return nil, err
+			}
+			return &Type{Kind: ModelKind, Model: model}, nil
+		}
+		elem := &Type{Kind: AnyKind}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			var err error
+			elem, err = b.fieldType(schema.AdditionalProperties.Schema, singular(name), parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &Type{Kind: MapKind, Elem: elem}, nil
+	}
+	return &Type{Kind: AnyKind}, nil
+}
+
+// structuralKey returns a key that is equal for schemas that describe the
+// same object, regardless of the description of the object itself.
+func structuralKey(schema *apiextensionsv1.JSONSchemaProps) (string, error) {
+	s := *schema
+	s.Description = ""
+	data, err := json.Marshal(&s)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema: %v", err)
+	}
+	return string(data), nil
+}
+
+// pascalCase converts a field name such as "scenariosJSON" to a type name
+// such as "ScenariosJSON".
+func pascalCase(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// snakeCase converts a field name such as "scenariosJSON" to a name such as
+// "scenarios_json".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		if r == '-' || r == '.' {
+			r = '_'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// singular returns the singular form of a type name derived from the name of
+// a list, such as "Client" for "Clients".
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "Aliases"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// wrap splits text into lines of at most width characters, keeping line
+// breaks. Words longer than the width are kept on their own line. Nil is
+// returned if the text is empty.
+func wrap(text string, width int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
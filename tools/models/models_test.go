@@ -0,0 +1,162 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// containerSchema returns the schema of a container with a name and an image.
+func containerSchema(description string) apiextensionsv1.JSONSchemaProps {
+	return apiextensionsv1.JSONSchemaProps{
+		Type:        "object",
+		Description: description,
+		Required:    []string{"name"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"name":  {Type: "string"},
+			"image": {Type: "string"},
+		},
+	}
+}
+
+var _ = Describe("Build", func() {
+	var schema *apiextensionsv1.JSONSchemaProps
+
+	BeforeEach(func() {
+		clientContainer := containerSchema("Run lists the containers of the client.")
+		serverContainer := containerSchema("Run lists the containers of the server.")
+		schema = &apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"metadata": {Type: "object"},
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"clients": {
+							Type: "array",
+							Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"run": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &clientContainer}},
+								},
+							}},
+						},
+						"mode":   {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"Volume"`)}, {Raw: []byte(`"Env"`)}}},
+						"memory": {XIntOrString: true},
+						"server": {
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"run": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &serverContainer}},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("names models after their fields and shares identical models", func() {
+		models, err := Build(schema, "LoadTest")
+		Expect(err).ToNot(HaveOccurred())
+
+		var names []string
+		for _, model := range models {
+			names = append(names, model.Name)
+		}
+		Expect(names).To(Equal([]string{"LoadTest", "LoadTestSpec", "Client", "Run", "Server"}))
+
+		spec := models[1]
+		Expect(spec.Fields).To(HaveLen(4))
+		Expect(spec.Fields[0].Name).To(Equal("clients"))
+		Expect(spec.Fields[0].Type).To(Equal(&Type{Kind: ArrayKind, Elem: &Type{Kind: ModelKind, Model: "Client"}}))
+		Expect(spec.Fields[1].Type.Kind).To(Equal(IntOrStringKind))
+		Expect(spec.Fields[2].Type.Enum).To(Equal([]string{"Volume", "Env"}))
+
+		run := models[3]
+		Expect(run.Fields[1].Name).To(Equal("name"))
+		Expect(run.Fields[1].Required).To(BeTrue())
+	})
+
+	It("returns an error for a root schema without properties", func() {
+		_, err := Build(&apiextensionsv1.JSONSchemaProps{Type: "string"}, "LoadTest")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("builds the models of the LoadTest CRD", func() {
+		crd, err := LoadSchema("../../config/crd/bases/e2etest.grpc.io_loadtests.yaml", grpcv1.GroupVersion.Version)
+		Expect(err).ToNot(HaveOccurred())
+		models, err := Build(crd, "LoadTest")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(models[0].Name).To(Equal("LoadTest"))
+	})
+})
+
+var _ = Describe("WritePython", func() {
+	It("writes dataclasses with snake case attributes", func() {
+		models := []*Model{{
+			Name:        "LoadTestSpec",
+			Description: "LoadTestSpec defines the desired state of LoadTest",
+			Fields: []*Field{
+				{Name: "scenariosJSON", Type: &Type{Kind: StringKind}},
+				{Name: "timeoutSeconds", Type: &Type{Kind: IntegerKind}, Required: true},
+				{Name: "from", Type: &Type{Kind: MapKind, Elem: &Type{Kind: StringKind}}},
+			},
+		}}
+		var buf bytes.Buffer
+		Expect(WritePython(&buf, models, "test")).To(Succeed())
+		output := buf.String()
+		Expect(output).To(HavePrefix("# Code generated by test. DO NOT EDIT."))
+		Expect(output).To(ContainSubstring("class LoadTestSpec(_Model):\n    \"\"\"LoadTestSpec defines the desired state of LoadTest\"\"\"\n\n    timeout_seconds: int = "))
+		Expect(output).To(ContainSubstring(`scenarios_json: Optional[str] = dataclasses.field(default=None, metadata={"json": "scenariosJSON"})`))
+		Expect(output).To(ContainSubstring(`from_: Optional[Dict[str, str]] = `))
+	})
+})
+
+var _ = Describe("WriteTypeScript", func() {
+	It("writes interfaces with the JSON names of the fields", func() {
+		models := []*Model{{
+			Name: "Client",
+			Fields: []*Field{
+				{Name: "name", Type: &Type{Kind: StringKind}, Required: true, Description: "Name is the name of the client."},
+				{Name: "xdsBootstrap", Type: &Type{Kind: StringKind, Enum: []string{"Volume", "Env"}}},
+				{Name: "ports", Type: &Type{Kind: ArrayKind, Elem: &Type{Kind: IntOrStringKind}}},
+			},
+		}}
+		var buf bytes.Buffer
+		Expect(WriteTypeScript(&buf, models, "test")).To(Succeed())
+		output := buf.String()
+		Expect(output).To(ContainSubstring("export interface Client {\n  /**\n   * Name is the name of the client.\n   */\n  name: string;\n"))
+		Expect(output).To(ContainSubstring(`  xdsBootstrap?: "Volume" | "Env";`))
+		Expect(output).To(ContainSubstring(`  ports?: (number | string)[];`))
+	})
+})
+
+var _ = Describe("snakeCase", func() {
+	It("converts camel case names", func() {
+		Expect(snakeCase("scenariosJSON")).To(Equal("scenarios_json"))
+		Expect(snakeCase("csdsPort")).To(Equal("csds_port"))
+		Expect(snakeCase("ttlSeconds")).To(Equal("ttl_seconds"))
+		Expect(snakeCase("JSONPath")).To(Equal("json_path"))
+		Expect(snakeCase("name")).To(Equal("name"))
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// pythonKeywords lists the Python keywords, which cannot be used as names of
+// attributes.
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true,
+	"while": true, "with": true, "yield": true,
+}
+
+// pythonPrelude contains the imports and the helpers that convert models to
+// and from dictionaries that can be encoded as JSON or YAML.
+const pythonPrelude = `from __future__ import annotations
+
+import dataclasses
+import typing
+from typing import Any, Dict, List, Literal, Optional, Union
+
+
+def _to_json(value: Any) -> Any:
+    if isinstance(value, _Model):
+        return value.to_dict()
+    if isinstance(value, list):
+        return [_to_json(v) for v in value]
+    if isinstance(value, dict):
+        return {k: _to_json(v) for k, v in value.items()}
+    return value
+
+
+def _from_json(tp: Any, value: Any) -> Any:
+    if value is None:
+        return None
+    origin = typing.get_origin(tp)
+    if origin is Union:
+        args = [a for a in typing.get_args(tp) if a is not type(None)]
+        if len(args) == 1:
+            return _from_json(args[0], value)
+        return value
+    if origin is list:
+        (elem,) = typing.get_args(tp)
+        return [_from_json(elem, v) for v in value]
+    if origin is dict:
+        _, elem = typing.get_args(tp)
+        return {k: _from_json(elem, v) for k, v in value.items()}
+    if isinstance(tp, type) and issubclass(tp, _Model):
+        return tp.from_dict(value)
+    return value
+
+
+class _Model:
+    """Base class of the models, converting them to and from dictionaries."""
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Returns the fields that are set, keyed by their names in JSON."""
+        result = {}
+        for f in dataclasses.fields(self):
+            value = getattr(self, f.name)
+            if value is not None:
+                result[f.metadata["json"]] = _to_json(value)
+        return result
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]):
+        """Creates a model from a dictionary decoded from JSON or YAML."""
+        hints = typing.get_type_hints(cls)
+        kwargs = {}
+        for f in dataclasses.fields(cls):
+            if f.metadata["json"] in data:
+                kwargs[f.name] = _from_json(hints[f.name], data[f.metadata["json"]])
+        return cls(**kwargs)
+`
+
+// WritePython writes the models as Python dataclasses. Attributes are named
+// after the fields in snake case, and each model can be converted to and from
+// a dictionary keyed by the names of the fields in JSON with its to_dict and
+// from_dict methods.
+func WritePython(w io.Writer, models []*Model, generator string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Code generated by %s. DO NOT EDIT.\n\n", generator)
+	bw.WriteString(pythonPrelude)
+
+	for _, model := range models {
+		bw.WriteString("\n\n@dataclasses.dataclass\n")
+		fmt.Fprintf(bw, "class %s(_Model):\n", model.Name)
+		description := model.Description
+		if description == "" {
+			description = model.Name + " is a model of the LoadTest API."
+		}
+		writePythonDocstring(bw, description)
+
+		// Dataclasses require fields without defaults to come first.
+		var fields []*Field
+		for _, field := range model.Fields {
+			if field.Required {
+				fields = append(fields, field)
+			}
+		}
+		for _, field := range model.Fields {
+			if !field.Required {
+				fields = append(fields, field)
+			}
+		}
+		for _, field := range fields {
+			bw.WriteString("\n")
+			for _, line := range wrap(field.Description, 74) {
+				bw.WriteString(strings.TrimRight("    # "+line, " ") + "\n")
+			}
+			typeName := pythonType(field.Type)
+			var options string
+			if field.Required {
+				options = fmt.Sprintf("metadata={\"json\": %q}", field.Name)
+			} else {
+				typeName = "Optional[" + typeName + "]"
+				options = fmt.Sprintf("default=None, metadata={\"json\": %q}", field.Name)
+			}
+			fmt.Fprintf(bw, "    %s: %s = dataclasses.field(%s)\n", pythonName(field.Name), typeName, options)
+		}
+	}
+	return bw.Flush()
+}
+
+// writePythonDocstring writes the docstring of a class.
+func writePythonDocstring(w io.StringWriter, description string) {
+	lines := wrap(description, 72)
+	if len(lines) == 1 {
+		w.WriteString("    \"\"\"" + escapePythonDocstring(lines[0]) + "\"\"\"\n")
+		return
+	}
+	w.WriteString("    \"\"\"" + escapePythonDocstring(lines[0]) + "\n\n")
+	for _, line := range lines[1:] {
+		w.WriteString(strings.TrimRight("    "+escapePythonDocstring(line), " ") + "\n")
+	}
+	w.WriteString("    \"\"\"\n")
+}
+
+// escapePythonDocstring escapes the characters of a line that cannot appear
+// in a docstring.
+func escapePythonDocstring(line string) string {
+	line = strings.ReplaceAll(line, "\\", "\\\\")
+	return strings.ReplaceAll(line, "\"\"\"", "\\\"\\\"\\\"")
+}
+
+// pythonName returns the name of the attribute for a field.
+func pythonName(name string) string {
+	name = snakeCase(name)
+	if pythonKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// pythonType returns the type annotation for a type.
+func pythonType(t *Type) string {
+	switch t.Kind {
+	case StringKind:
+		if len(t.Enum) > 0 {
+			var values []string
+			for _, value := range t.Enum {
+				values = append(values, fmt.Sprintf("%q", value))
+			}
+			return "Literal[" + strings.Join(values, ", ") + "]"
+		}
+		return "str"
+	case IntegerKind:
+		return "int"
+	case NumberKind:
+		return "float"
+	case BooleanKind:
+		return "bool"
+	case IntOrStringKind:
+		return "Union[int, str]"
+	case ArrayKind:
+		return "List[" + pythonType(t.Elem) + "]"
+	case MapKind:
+		return "Dict[str, " + pythonType(t.Elem) + "]"
+	case ModelKind:
+		return t.Model
+	}
+	return "Any"
+}
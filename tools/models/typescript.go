@@ -0,0 +1,105 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// typeScriptIdentifier matches field names that do not need to be quoted.
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// WriteTypeScript writes the models as TypeScript interfaces, whose
+// properties have the names of the fields in JSON, so that objects decoded
+// from JSON or YAML can be used directly.
+func WriteTypeScript(w io.Writer, models []*Model, generator string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// Code generated by %s. DO NOT EDIT.\n", generator)
+
+	for _, model := range models {
+		bw.WriteString("\n")
+		writeTypeScriptComment(bw, "", model.Description)
+		fmt.Fprintf(bw, "export interface %s {\n", model.Name)
+		for i, field := range model.Fields {
+			if i > 0 {
+				bw.WriteString("\n")
+			}
+			writeTypeScriptComment(bw, "  ", field.Description)
+			name := field.Name
+			if !typeScriptIdentifier.MatchString(name) {
+				name = fmt.Sprintf("%q", name)
+			}
+			optional := "?"
+			if field.Required {
+				optional = ""
+			}
+			fmt.Fprintf(bw, "  %s%s: %s;\n", name, optional, typeScriptType(field.Type))
+		}
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
+
+// writeTypeScriptComment writes a documentation comment with a given indent.
+// Nothing is written if the description is empty.
+func writeTypeScriptComment(w io.StringWriter, indent string, description string) {
+	if strings.TrimSpace(description) == "" {
+		return
+	}
+	w.WriteString(indent + "/**\n")
+	for _, line := range wrap(description, 76-len(indent)) {
+		line = strings.ReplaceAll(line, "*/", "*\\/")
+		w.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	w.WriteString(indent + " */\n")
+}
+
+// typeScriptType returns the type of a property.
+func typeScriptType(t *Type) string {
+	switch t.Kind {
+	case StringKind:
+		if len(t.Enum) > 0 {
+			var values []string
+			for _, value := range t.Enum {
+				values = append(values, fmt.Sprintf("%q", value))
+			}
+			return strings.Join(values, " | ")
+		}
+		return "string"
+	case IntegerKind, NumberKind:
+		return "number"
+	case BooleanKind:
+		return "boolean"
+	case IntOrStringKind:
+		return "number | string"
+	case ArrayKind:
+		elem := typeScriptType(t.Elem)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case MapKind:
+		return "{ [key: string]: " + typeScriptType(t.Elem) + " }"
+	case ModelKind:
+		return t.Model
+	}
+	return "unknown"
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poke probes the workers of a load test through the WorkerService
+// that the driver uses to control them. Each worker is reached through a port
+// forwarded by the Kubernetes API server, so the probes can be run from
+// outside the cluster. This helps debug tests that appear to be running but
+// make no progress, by telling apart workers that respond from workers that
+// are stuck or unreachable.
+package poke
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poke
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwarder forwards ports of pods through the portforward subresource of
+// the Kubernetes API server, like kubectl port-forward.
+type PortForwarder struct {
+	// Config is the configuration used to connect to the API server.
+	Config *rest.Config
+
+	// Client is a REST client for the core API group, such as the one
+	// returned by the RESTClient method of a CoreV1 client.
+	Client rest.Interface
+}
+
+// Forward implements the Forwarder interface. A free local port on localhost
+// is chosen.
+func (f *PortForwarder) Forward(ctx context.Context, pod *corev1.Pod, port int32) (string, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(f.Config)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create round tripper: %v", err)
+	}
+	url := f.Client.Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", port)}, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		return "", nil, err
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyChan:
+	case err := <-errChan:
+		return "", nil, err
+	case <-ctx.Done():
+		close(stopChan)
+		return "", nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return "", nil, err
+	}
+	address := net.JoinHostPort("localhost", fmt.Sprint(ports[0].Local))
+	return address, func() { close(stopChan) }, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poke
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	testpb "google.golang.org/grpc/interop/grpc_testing"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// Target is a pod of a load test that can be probed.
+type Target struct {
+	// Pod is the pod.
+	Pod *corev1.Pod
+
+	// Role is the role of the pod, such as server, client or driver.
+	Role string
+
+	// Port is the driver port where a worker serves the WorkerService. It
+	// is zero for pods that do not serve it, such as the driver.
+	Port int32
+}
+
+// Targets returns the targets for the pods of a load test. The driver port of
+// each server and client is the container port named "driver", or
+// config.DriverPort if there is no such port.
+func Targets(pods []*corev1.Pod) []Target {
+	var targets []Target
+	for _, pod := range pods {
+		role := pod.Labels[config.RoleLabel]
+		target := Target{Pod: pod, Role: role}
+		if role == config.ServerRole || role == config.ClientRole {
+			target.Port = driverPort(pod)
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// driverPort returns the port named "driver" of a pod, or config.DriverPort if
+// the pod has no such port.
+func driverPort(pod *corev1.Pod) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == "driver" {
+				return port.ContainerPort
+			}
+		}
+	}
+	return config.DriverPort
+}
+
+// Forwarder forwards a local port to a port of a pod.
+type Forwarder interface {
+	// Forward forwards a local port to a port of a pod, and returns the
+	// local address and a function that stops forwarding. An error is
+	// returned if the port cannot be forwarded.
+	Forward(ctx context.Context, pod *corev1.Pod, port int32) (address string, stop func(), err error)
+}
+
+// Result is the outcome of probing a target.
+type Result struct {
+	// Pod is the name of the pod.
+	Pod string
+
+	// Role is the role of the pod.
+	Role string
+
+	// Phase is the phase of the pod.
+	Phase corev1.PodPhase
+
+	// Port is the port that was probed. It is zero if the pod was not
+	// probed.
+	Port int32
+
+	// Cores is the number of cores reported by the worker.
+	Cores int32
+
+	// Latency is the time taken by the CoreCount RPC.
+	Latency time.Duration
+
+	// Quit is true if the worker was asked to quit.
+	Quit bool
+
+	// Err is the error encountered while probing the pod, or nil if the
+	// worker responded.
+	Err error
+}
+
+// Prober probes the workers of load tests.
+type Prober struct {
+	// Forwarder forwards a local port to the driver port of each worker.
+	Forwarder Forwarder
+
+	// Timeout is the time allowed for each RPC.
+	Timeout time.Duration
+
+	// Quit enables asking each worker to quit with the QuitWorker RPC after
+	// it responded to the CoreCount RPC. This stops the test.
+	Quit bool
+}
+
+// Probe probes a target. The pod is not probed if it has no port, or if it is
+// not running, in which case only its phase is reported.
+func (p *Prober) Probe(ctx context.Context, target Target) *Result {
+	result := &Result{
+		Pod:   target.Pod.Name,
+		Role:  target.Role,
+		Phase: target.Pod.Status.Phase,
+	}
+	if target.Port == 0 || target.Pod.Status.Phase != corev1.PodRunning {
+		return result
+	}
+	result.Port = target.Port
+
+	address, stop, err := p.Forwarder.Forward(ctx, target.Pod, target.Port)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to forward port %d: %v", target.Port, err)
+		return result
+	}
+	defer stop()
+
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to connect to %s: %v", address, err)
+		return result
+	}
+	defer conn.Close()
+	client := testpb.NewWorkerServiceClient(conn)
+
+	rpcCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	start := time.Now()
+	response, err := client.CoreCount(rpcCtx, &testpb.CoreRequest{})
+	if err != nil {
+		result.Err = fmt.Errorf("CoreCount failed: %v", err)
+		return result
+	}
+	result.Latency = time.Since(start)
+	result.Cores = response.Cores
+
+	if p.Quit {
+		quitCtx, quitCancel := context.WithTimeout(ctx, p.Timeout)
+		defer quitCancel()
+		if _, err := client.QuitWorker(quitCtx, &testpb.Void{}); err != nil {
+			result.Err = fmt.Errorf("QuitWorker failed: %v", err)
+			return result
+		}
+		result.Quit = true
+	}
+	return result
+}
+
+// ProbeAll probes each target in order, and returns the results in the same
+// order.
+func (p *Prober) ProbeAll(ctx context.Context, targets []Target) []*Result {
+	var results []*Result
+	for _, target := range targets {
+		results = append(results, p.Probe(ctx, target))
+	}
+	return results
+}
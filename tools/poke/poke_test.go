@@ -0,0 +1,167 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poke
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	testpb "google.golang.org/grpc/interop/grpc_testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// fakeWorker serves the WorkerService, and records whether it was asked to
+// quit.
+type fakeWorker struct {
+	testpb.UnimplementedWorkerServiceServer
+	quit chan struct{}
+}
+
+func (w *fakeWorker) CoreCount(context.Context, *testpb.CoreRequest) (*testpb.CoreResponse, error) {
+	return &testpb.CoreResponse{Cores: 8}, nil
+}
+
+func (w *fakeWorker) QuitWorker(context.Context, *testpb.Void) (*testpb.Void, error) {
+	close(w.quit)
+	return &testpb.Void{}, nil
+}
+
+// fakeForwarder forwards every port to a fixed address, and records the ports
+// that were forwarded.
+type fakeForwarder struct {
+	address   string
+	err       error
+	forwarded []int32
+	stopped   int
+}
+
+func (f *fakeForwarder) Forward(ctx context.Context, pod *corev1.Pod, port int32) (string, func(), error) {
+	if f.err != nil {
+		return "", nil, f.err
+	}
+	f.forwarded = append(f.forwarded, port)
+	return f.address, func() { f.stopped++ }, nil
+}
+
+func newPod(name, role string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{config.RoleLabel: role},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+var _ = Describe("Targets", func() {
+	It("sets the driver port of workers only", func() {
+		server := newPod("server-0", config.ServerRole, corev1.PodRunning)
+		server.Spec.Containers = []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "driver", ContainerPort: 10123}},
+		}}
+		client := newPod("client-0", config.ClientRole, corev1.PodRunning)
+		driver := newPod("driver", config.DriverRole, corev1.PodRunning)
+
+		targets := Targets([]*corev1.Pod{server, client, driver})
+		Expect(targets).To(HaveLen(3))
+		Expect(targets[0].Port).To(Equal(int32(10123)))
+		Expect(targets[1].Port).To(Equal(int32(config.DriverPort)))
+		Expect(targets[2].Port).To(BeZero())
+	})
+})
+
+var _ = Describe("Prober", func() {
+	var worker *fakeWorker
+	var srv *grpc.Server
+	var forwarder *fakeForwarder
+	var prober *Prober
+
+	BeforeEach(func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		worker = &fakeWorker{quit: make(chan struct{})}
+		srv = grpc.NewServer()
+		testpb.RegisterWorkerServiceServer(srv, worker)
+		go srv.Serve(lis)
+
+		forwarder = &fakeForwarder{address: lis.Addr().String()}
+		prober = &Prober{Forwarder: forwarder, Timeout: 5 * time.Second}
+	})
+
+	AfterEach(func() {
+		srv.Stop()
+	})
+
+	It("reports the cores of workers that respond", func() {
+		target := Target{Pod: newPod("server-0", config.ServerRole, corev1.PodRunning), Role: config.ServerRole, Port: 10000}
+
+		result := prober.Probe(context.Background(), target)
+		Expect(result.Err).ToNot(HaveOccurred())
+		Expect(result.Cores).To(Equal(int32(8)))
+		Expect(result.Quit).To(BeFalse())
+		Expect(forwarder.forwarded).To(Equal([]int32{10000}))
+		Expect(forwarder.stopped).To(Equal(1))
+		Consistently(worker.quit, 100*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	It("asks workers to quit when enabled", func() {
+		prober.Quit = true
+		target := Target{Pod: newPod("client-0", config.ClientRole, corev1.PodRunning), Role: config.ClientRole, Port: 10000}
+
+		result := prober.Probe(context.Background(), target)
+		Expect(result.Err).ToNot(HaveOccurred())
+		Expect(result.Quit).To(BeTrue())
+		Eventually(worker.quit).Should(BeClosed())
+	})
+
+	It("does not probe pods without a port or that are not running", func() {
+		results := prober.ProbeAll(context.Background(), []Target{
+			{Pod: newPod("driver", config.DriverRole, corev1.PodRunning), Role: config.DriverRole},
+			{Pod: newPod("server-0", config.ServerRole, corev1.PodPending), Role: config.ServerRole, Port: 10000},
+		})
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Port).To(BeZero())
+		Expect(results[1].Phase).To(Equal(corev1.PodPending))
+		Expect(results[1].Err).ToNot(HaveOccurred())
+		Expect(forwarder.forwarded).To(BeEmpty())
+	})
+
+	It("reports workers that cannot be reached", func() {
+		forwarder.err = errors.New("pod not found")
+		target := Target{Pod: newPod("server-0", config.ServerRole, corev1.PodRunning), Role: config.ServerRole, Port: 10000}
+
+		result := prober.Probe(context.Background(), target)
+		Expect(result.Err).To(MatchError(ContainSubstring("pod not found")))
+	})
+
+	It("reports workers that do not respond", func() {
+		srv.Stop()
+		prober.Timeout = 200 * time.Millisecond
+		target := Target{Pod: newPod("server-0", config.ServerRole, corev1.PodRunning), Role: config.ServerRole, Port: 10000}
+
+		result := prober.Probe(context.Background(), target)
+		Expect(result.Err).To(MatchError(ContainSubstring("CoreCount failed")))
+	})
+})
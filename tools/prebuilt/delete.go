@@ -0,0 +1,92 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prebuilt
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// Delete deletes all images within the repositories under imagePrefix that
+// have the given tag. Images that have other tags are untagged instead of
+// deleted. Failures to process individual images are logged and do not stop
+// processing of the remaining images.
+func Delete(imagePrefix string, tag string) error {
+	if imagePrefix == "" {
+		return errors.New("no root repository is provided")
+	}
+	if tag == "" {
+		return errors.New("no image tag is provided")
+	}
+
+	log.Printf("start to process all images within %s having tag: %s", imagePrefix, tag)
+
+	getRepository := exec.Command("gcloud", "container", "images", "list", fmt.Sprintf("--repository=%s", imagePrefix))
+	getRepositoryOutput, err := getRepository.CombinedOutput()
+	if err != nil {
+		log.Printf("Failed getting repositories within %s: %s\n", imagePrefix, string(getRepositoryOutput))
+	}
+
+	log.Printf("All image repositories within specified registry: %s\n", imagePrefix)
+	log.Println(string(getRepositoryOutput))
+
+	allRepositories := strings.Split(string(getRepositoryOutput), "\n")
+	for i, curRepository := range allRepositories {
+		if i == 0 || curRepository == "" {
+			continue
+		}
+		log.Printf("Processing image repository: %s\n", curRepository)
+
+		curImageToProcess := fmt.Sprintf("%s:%s", curRepository, tag)
+
+		getImageHaveTheTag := exec.Command("gcloud", "container", "images", "list-tags", curRepository, fmt.Sprintf("--filter=%s", tag))
+		getImageHaveTheTagOutput, err := getImageHaveTheTag.CombinedOutput()
+		if err != nil {
+			log.Printf("Failed getting image: %s with tag %s: %s\n", curRepository, tag, string(getImageHaveTheTagOutput))
+		}
+
+		imageFullLine := strings.Split(string(getImageHaveTheTagOutput), "\n")
+		if len(imageFullLine) <= 2 {
+			log.Printf("Tag: %s is not presented.\n", tag)
+			continue
+		}
+
+		numbersOfTagsOfCurrentImage := len(strings.Split(strings.Fields(imageFullLine[1])[1], ","))
+
+		if numbersOfTagsOfCurrentImage > 1 {
+			log.Printf("Image have multiple tags, including %s, untag the image with tag %s instead of deleting image\n", tag, tag)
+			untagImages := exec.Command("gcloud", "-q", "container", "images", "untag", curImageToProcess)
+			unTagImageOutput, err := untagImages.CombinedOutput()
+			if err != nil {
+				log.Printf("Failed untagging %s: %s\n", curImageToProcess, string(unTagImageOutput))
+			}
+			log.Printf("Succeeded untagging %s:%s\n", curRepository, tag)
+		} else {
+			deleteImage := exec.Command("gcloud", "-q", "container", "images", "delete", curImageToProcess)
+			deleteImageOutput, err := deleteImage.CombinedOutput()
+			if err != nil {
+				log.Printf("Failed deleting image %s : %s\n", curImageToProcess, string(deleteImageOutput))
+			}
+			log.Printf("Succeeded deleting delete %s\n", curImageToProcess)
+		}
+	}
+	log.Printf("All images with tag: %s within container registry: %s are processed.\n", tag, imagePrefix)
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prebuilt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grpc/test-infra/languages"
+)
+
+// buildCommandTimeout is the maximum time allowed to build each image. It
+// should be enough for all languages.
+const buildCommandTimeout = 30 * time.Minute
+
+// LanguageSpec containers the specs of each tested language.
+type LanguageSpec struct {
+	Name   string `json:"name"`
+	Repo   string `json:"repo"`
+	Gitref string `json:"gitref"`
+}
+
+// ParseLanguageSpecs accepts a list of strings in the form language:gitref or
+// language:repository:gitref and returns a map from image language names to
+// the corresponding specs. Languages may be given by their canonical names or
+// aliases, such as the names used in scenarios, which are resolved to the
+// names of their images by the languages package.
+func ParseLanguageSpecs(langs []string) (map[string]LanguageSpec, error) {
+	if len(langs) == 0 {
+		return nil, errors.New("no language and its gitref pair specified, please provide languages and the GITREF as cxx:master")
+	}
+
+	specs := map[string]LanguageSpec{}
+	for _, s := range langs {
+		split := strings.SplitN(s, ":", 3)
+
+		// C++:master will be split to 2 items, c++:grpc/grpc:master will be
+		// split to 3 items.
+		if len(split) < 2 || split[len(split)-1] == "" {
+			return nil, fmt.Errorf("input error in language and gitref selection %q, please follow the format language:gitref or language:repository:gitref, for example c++:master or c++:grpc/grpc:master", s)
+		}
+		spec := LanguageSpec{Name: languages.ImageName(split[0])}
+		if len(split) == 3 {
+			spec.Repo = split[1]
+			spec.Gitref = split[2]
+		} else {
+			spec.Gitref = split[1]
+		}
+		specs[spec.Name] = spec
+	}
+	return specs, nil
+}
+
+// PrepareOptions contains the settings used to prepare prebuilt images.
+type PrepareOptions struct {
+	// ImagePrefix is the registry (or naming prefix) of the images.
+	ImagePrefix string
+
+	// Tag is the tag that identifies the images built for a test run.
+	Tag string
+
+	// DockerfileRoot is the root directory of the Dockerfiles, which
+	// contains one subdirectory per language.
+	DockerfileRoot string
+
+	// BuildOnly skips pushing the images to the registry.
+	BuildOnly bool
+
+	// Languages maps image language names to their specs.
+	Languages map[string]LanguageSpec
+}
+
+// Validate checks that all required options are set.
+func (o *PrepareOptions) Validate() error {
+	if o.ImagePrefix == "" {
+		return errors.New("no registry provided, please provide a container registry. If the images are not intended to be pushed to a registry, please provide a prefix for naming the built images")
+	}
+	if o.Tag == "" {
+		return errors.New("no image tag provided")
+	}
+	if len(o.Tag) > 128 {
+		return errors.New("invalid tag name, a tag name may not start with a period or a dash and may contain a maximum of 128 characters")
+	}
+	if o.DockerfileRoot == "" {
+		return errors.New("no root directory for Dockerfiles provided")
+	}
+	if len(o.Languages) == 0 {
+		return errors.New("no languages specified")
+	}
+	return nil
+}
+
+// Image returns the name of the image built for a language.
+func (o *PrepareOptions) Image(lang string) string {
+	return fmt.Sprintf("%s/%s:%s", o.ImagePrefix, lang, o.Tag)
+}
+
+// Prepare builds the images for all languages concurrently and pushes them
+// to the registry, unless BuildOnly is set. It returns an error listing
+// every language that failed.
+func Prepare(o *PrepareOptions) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	log.Println("Selected language : REPOSITORY: GITREF")
+	formattedMap, _ := json.MarshalIndent(o.Languages, "", "  ")
+	log.Print(string(formattedMap))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failures []string
+
+	uniqueCacheBreaker := time.Now().String()
+
+	for lang, spec := range o.Languages {
+		wg.Add(1)
+		go func(lang string, spec LanguageSpec) {
+			defer wg.Done()
+			if err := prepareImage(o, lang, spec, uniqueCacheBreaker); err != nil {
+				log.Print(err)
+				mu.Lock()
+				failures = append(failures, lang)
+				mu.Unlock()
+			}
+		}(lang, spec)
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("failed preparing images for languages: %s", strings.Join(failures, ", "))
+	}
+
+	log.Printf("All images are processed")
+	return nil
+}
+
+// prepareImage builds and optionally pushes the image for one language.
+func prepareImage(o *PrepareOptions, lang string, spec LanguageSpec, cacheBreaker string) error {
+	image := o.Image(lang)
+	dockerfileLocation := fmt.Sprintf("%s/%s/", o.DockerfileRoot, lang)
+
+	// Build image
+	log.Printf("building %s image\n", lang)
+	buildDockerImage := exec.Command("timeout", fmt.Sprintf("%ds", int(buildCommandTimeout.Seconds())), "docker", "build", dockerfileLocation, "-t", image, "--build-arg", fmt.Sprintf("GITREF=%s", spec.Gitref), "--build-arg", fmt.Sprintf("BREAK_CACHE=%s", cacheBreaker))
+	if spec.Repo != "" {
+		buildDockerImage.Args = append(buildDockerImage.Args, "--build-arg", fmt.Sprintf("REPOSITORY=%s", spec.Repo))
+	}
+	log.Printf("Running command: %s", strings.Join(buildDockerImage.Args, " "))
+	buildOutput, err := buildDockerImage.CombinedOutput()
+	if err != nil {
+		log.Printf("Failed building %s image. Dump of command's output will follow:\n", lang)
+		log.Println(string(buildOutput))
+		return fmt.Errorf("failed building %s image: %v", lang, err)
+	}
+	log.Printf("Succeeded building %s image. Dump of command's output will follow:\n", lang)
+	log.Println(string(buildOutput))
+	log.Printf("Succeeded building %s image: %s\n", lang, image)
+
+	if o.BuildOnly {
+		return nil
+	}
+
+	// Push image
+	log.Printf("pushing %s image\n", lang)
+	pushDockerImage := exec.Command("docker", "push", image)
+	pushOutput, err := pushDockerImage.CombinedOutput()
+	if err != nil {
+		log.Printf("Failed pushing %s image. Dump of command's output will follow:\n", lang)
+		log.Println(string(pushOutput))
+		return fmt.Errorf("failed pushing %s image: %v", lang, err)
+	}
+	log.Printf("Succeeded pushing %s image. Dump of command's output will follow:\n", lang)
+	log.Println(string(pushOutput))
+	log.Printf("Succeeded pushing %s image to %s\n", lang, image)
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prebuilt
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseLanguageSpecs", func() {
+	It("parses language and gitref pairs", func() {
+		specs, err := ParseLanguageSpecs([]string{"go:master", "java:v1.45.0"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(specs).To(Equal(map[string]LanguageSpec{
+			"go":   {Name: "go", Gitref: "master"},
+			"java": {Name: "java", Gitref: "v1.45.0"},
+		}))
+	})
+
+	It("parses an optional repository", func() {
+		specs, err := ParseLanguageSpecs([]string{"go:example/grpc-go:abc123"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(specs).To(HaveKeyWithValue("go", LanguageSpec{Name: "go", Repo: "example/grpc-go", Gitref: "abc123"}))
+	})
+
+	It("converts scenario language names to image language names", func() {
+		specs, err := ParseLanguageSpecs([]string{"c++:master", "python_asyncio:master"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(specs).To(HaveKey("cxx"))
+		Expect(specs).To(HaveKey("python"))
+	})
+
+	It("returns an error when no languages are specified", func() {
+		_, err := ParseLanguageSpecs(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the gitref is missing", func() {
+		_, err := ParseLanguageSpecs([]string{"go"})
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseLanguageSpecs([]string{"go:"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PrepareOptions", func() {
+	var o *PrepareOptions
+
+	BeforeEach(func() {
+		o = &PrepareOptions{
+			ImagePrefix:    "gcr.io/example/project",
+			Tag:            "test-tag",
+			DockerfileRoot: "containers/pre_built_workers",
+			Languages: map[string]LanguageSpec{
+				"go": {Name: "go", Gitref: "master"},
+			},
+		}
+	})
+
+	It("accepts valid options", func() {
+		Expect(o.Validate()).To(Succeed())
+	})
+
+	It("requires an image prefix", func() {
+		o.ImagePrefix = ""
+		Expect(o.Validate()).ToNot(Succeed())
+	})
+
+	It("rejects tags longer than 128 characters", func() {
+		o.Tag = strings.Repeat("x", 129)
+		Expect(o.Validate()).ToNot(Succeed())
+	})
+
+	It("requires a Dockerfile root", func() {
+		o.DockerfileRoot = ""
+		Expect(o.Validate()).ToNot(Succeed())
+	})
+
+	It("names images by prefix, language and tag", func() {
+		Expect(o.Image("go")).To(Equal("gcr.io/example/project/go:test-tag"))
+	})
+})
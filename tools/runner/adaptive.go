@@ -0,0 +1,82 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+// AdaptiveConcurrency contains the settings used to adjust the concurrency
+// level of a queue based on the outcome of its tests.
+type AdaptiveConcurrency struct {
+	// FailureThreshold is the number of consecutive infrastructure failures
+	// that cause the concurrency level of a queue to be halved.
+	FailureThreshold int
+
+	// RecoveryThreshold is the number of consecutive tests without
+	// infrastructure failures that cause the concurrency level of a queue
+	// to be increased by one, up to the configured level.
+	RecoveryThreshold int
+}
+
+// concurrencyAdjuster tracks the outcome of tests in a queue and computes
+// the concurrency level to use for the next tests.
+type concurrencyAdjuster struct {
+	settings  *AdaptiveConcurrency
+	maxLevel  int
+	level     int
+	failures  int
+	successes int
+}
+
+// newConcurrencyAdjuster creates a concurrencyAdjuster that starts at and
+// never exceeds the given concurrency level. If settings is nil, the
+// concurrency level is never adjusted.
+func newConcurrencyAdjuster(settings *AdaptiveConcurrency, level int) *concurrencyAdjuster {
+	return &concurrencyAdjuster{
+		settings: settings,
+		maxLevel: level,
+		level:    level,
+	}
+}
+
+// Level returns the current concurrency level.
+func (a *concurrencyAdjuster) Level() int {
+	return a.level
+}
+
+// Record records the outcome of a test and returns true if the concurrency
+// level changed as a result.
+func (a *concurrencyAdjuster) Record(infrastructureFailure bool) bool {
+	if a.settings == nil {
+		return false
+	}
+	if infrastructureFailure {
+		a.successes = 0
+		a.failures++
+		if a.failures < a.settings.FailureThreshold || a.level <= 1 {
+			return false
+		}
+		a.failures = 0
+		a.level /= 2
+		return true
+	}
+	a.failures = 0
+	a.successes++
+	if a.successes < a.settings.RecoveryThreshold || a.level >= a.maxLevel {
+		return false
+	}
+	a.successes = 0
+	a.level++
+	return true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("concurrencyAdjuster", func() {
+	var settings *AdaptiveConcurrency
+
+	ginkgo.BeforeEach(func() {
+		settings = &AdaptiveConcurrency{
+			FailureThreshold:  2,
+			RecoveryThreshold: 3,
+		}
+	})
+
+	ginkgo.It("halves the level after consecutive infrastructure failures", func() {
+		adjuster := newConcurrencyAdjuster(settings, 8)
+
+		Expect(adjuster.Record(true)).To(BeFalse())
+		Expect(adjuster.Level()).To(Equal(8))
+		Expect(adjuster.Record(true)).To(BeTrue())
+		Expect(adjuster.Level()).To(Equal(4))
+		Expect(adjuster.Record(true)).To(BeFalse())
+		Expect(adjuster.Record(true)).To(BeTrue())
+		Expect(adjuster.Level()).To(Equal(2))
+	})
+
+	ginkgo.It("does not halve the level after failures that are not consecutive", func() {
+		adjuster := newConcurrencyAdjuster(settings, 8)
+
+		Expect(adjuster.Record(true)).To(BeFalse())
+		Expect(adjuster.Record(false)).To(BeFalse())
+		Expect(adjuster.Record(true)).To(BeFalse())
+		Expect(adjuster.Level()).To(Equal(8))
+	})
+
+	ginkgo.It("does not reduce the level below one", func() {
+		adjuster := newConcurrencyAdjuster(settings, 2)
+
+		Expect(adjuster.Record(true)).To(BeFalse())
+		Expect(adjuster.Record(true)).To(BeTrue())
+		Expect(adjuster.Level()).To(Equal(1))
+		for i := 0; i < 4; i++ {
+			Expect(adjuster.Record(true)).To(BeFalse())
+		}
+		Expect(adjuster.Level()).To(Equal(1))
+	})
+
+	ginkgo.It("increases the level after consecutive successes up to the initial level", func() {
+		adjuster := newConcurrencyAdjuster(settings, 4)
+		adjuster.Record(true)
+		adjuster.Record(true)
+		Expect(adjuster.Level()).To(Equal(2))
+
+		Expect(adjuster.Record(false)).To(BeFalse())
+		Expect(adjuster.Record(false)).To(BeFalse())
+		Expect(adjuster.Record(false)).To(BeTrue())
+		Expect(adjuster.Level()).To(Equal(3))
+		for i := 0; i < 2; i++ {
+			Expect(adjuster.Record(false)).To(BeFalse())
+		}
+		Expect(adjuster.Record(false)).To(BeTrue())
+		Expect(adjuster.Level()).To(Equal(4))
+		for i := 0; i < 6; i++ {
+			Expect(adjuster.Record(false)).To(BeFalse())
+		}
+		Expect(adjuster.Level()).To(Equal(4))
+	})
+
+	ginkgo.It("never changes the level without settings", func() {
+		adjuster := newConcurrencyAdjuster(nil, 4)
+
+		for i := 0; i < 10; i++ {
+			Expect(adjuster.Record(true)).To(BeFalse())
+		}
+		Expect(adjuster.Level()).To(Equal(4))
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/storage"
+)
+
+// ArtifactsManifestFileName is the name of the file that the manifest of the
+// uploaded artifacts of a test is saved to, under the directory named after
+// the test.
+const ArtifactsManifestFileName = "artifacts.json"
+
+// NewArtifactStore creates an artifact store from the artifacts settings in
+// the defaults files of the controller. When the settings specify a retention,
+// the lifecycle configuration of the bucket is updated to delete objects after
+// the retention. Nil is returned if the defaults do not configure artifacts.
+func NewArtifactStore(ctx context.Context, defaultsFiles []string) (*storage.Store, error) {
+	defaults, err := config.LoadDefaultsFiles(defaultsFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load defaults: %v", err)
+	}
+	settings := defaults.Artifacts
+	if settings == nil {
+		return nil, nil
+	}
+
+	bucket, err := storage.NewGCSBucket(ctx, settings.Bucket, settings.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Uploading artifacts to %s", storage.URI(settings.Bucket, settings.Prefix))
+
+	if settings.RetentionDays > 0 {
+		if err := bucket.SetRetention(ctx, int(settings.RetentionDays)); err != nil {
+			return nil, err
+		}
+		log.Printf("Artifact retention: %d days", settings.RetentionDays)
+	}
+	if expiry := settings.SignedURLExpiry(); expiry > 0 {
+		log.Printf("Signed URL expiry for artifacts: %v", expiry)
+	}
+
+	return storage.NewStore(bucket, settings.Prefix, settings.SignedURLExpiry()), nil
+}
+
+// uploadArtifact uploads a file saved for a load test to the artifact store
+// and adds it to the manifest of the test. The URL used to refer to the
+// artifact in reports is returned. An empty string is returned if the runner
+// does not upload artifacts or the upload fails, so the local path of the file
+// can be used instead.
+func (r *Runner) uploadArtifact(ctx context.Context, loadTest *grpcv1.LoadTest, reporter *TestCaseReporter, manifest *storage.Manifest, kind storage.Kind, filePath string) string {
+	if r.artifacts == nil {
+		return ""
+	}
+	artifact, err := r.artifacts.Upload(ctx, loadTest, kind, filePath)
+	if err != nil {
+		reporter.Warning("Could not upload artifact: %v", err)
+	}
+	if artifact == nil {
+		return ""
+	}
+	manifest.Add(artifact)
+	return artifact.URL()
+}
+
+// SaveArtifactsManifest writes the manifest of the uploaded artifacts of a
+// load test to a file. The file is placed in a directory named after the load
+// test under a given directory. The path of the saved file is returned.
+func SaveArtifactsManifest(manifest *storage.Manifest, outputDir string) (string, error) {
+	manifestDir := filepath.Join(outputDir, manifest.Name)
+	if err := os.MkdirAll(manifestDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create manifest output directory %s: %v", manifestDir, err)
+	}
+
+	filePath := filepath.Join(manifestDir, ArtifactsManifestFileName)
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s for writing", filePath)
+	}
+	defer file.Close()
+
+	if err := manifest.Write(file); err != nil {
+		return "", fmt.Errorf("error writing to %s: %v", filePath, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("error writing to %s: %v", filePath, err)
+	}
+	return filePath, nil
+}
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,9 +40,16 @@ import (
 	"github.com/grpc/test-infra/status"
 )
 
-// NewLoadTestGetter returns a client to interact with LoadTest resources. The
-// client can be used to create, query for status and delete LoadTests.
+// NewLoadTestGetter returns a client to interact with LoadTest resources in
+// the default namespace. The client can be used to create, query for status and
+// delete LoadTests.
 func NewLoadTestGetter() clientset.LoadTestGetter {
+	return NewLoadTestGetterForNamespace(corev1.NamespaceDefault)
+}
+
+// NewLoadTestGetterForNamespace returns a client to interact with LoadTest
+// resources in a namespace.
+func NewLoadTestGetterForNamespace(namespace string) clientset.LoadTestGetter {
 	clientset := NewGRPCTestClientset()
 	schemebuilder := runtime.NewSchemeBuilder(func(scheme *runtime.Scheme) error {
 		scheme.AddKnownTypes(grpcv1.GroupVersion,
@@ -59,7 +65,7 @@ func NewLoadTestGetter() clientset.LoadTestGetter {
 	types := scheme.AllKnownTypes()
 	_ = types
 
-	return clientset.LoadTestV1().LoadTests(corev1.NamespaceDefault)
+	return clientset.LoadTestV1().LoadTests(namespace)
 }
 
 // NewGRPCTestClientset returns a new GRPCTestClientset.
@@ -82,17 +88,25 @@ func NewK8sClientset() *kubernetes.Clientset {
 	return k8sClientset
 }
 
+// NewKubernetesConfig returns the configuration that the clients created in
+// this package use to connect to the Kubernetes API server.
+func NewKubernetesConfig() *rest.Config {
+	return getKubernetesConfig()
+}
+
 // NewPodsGetter returns a new PodsGetter.
 func NewPodsGetter() corev1types.PodsGetter {
 	clientset := NewK8sClientset()
 	return clientset.CoreV1()
 }
 
-// GetTestPods retrieves the pods associated with a LoadTest.
+// GetTestPods retrieves the pods associated with a LoadTest. Only the pods in
+// the namespace of the LoadTest are listed, so that access to other namespaces
+// is not required.
 func GetTestPods(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter) ([]*corev1.Pod, error) {
-	podLister := podsGetter.Pods(metav1.NamespaceAll)
+	podLister := podsGetter.Pods(loadTest.Namespace)
 
-	// Get a list of all pods.
+	// Get a list of all pods in the namespace.
 	podList, err := podLister.List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch list of pods: %v", err)
@@ -103,31 +117,39 @@ func GetTestPods(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter core
 	return testPods, nil
 }
 
-// getKubernetesConfig retrieves the kubernetes configuration.
+// getKubernetesConfig retrieves the kubernetes configuration. The files listed
+// in the KUBECONFIG environment variable take precedence, followed by the
+// in-cluster configuration and the default ~/.kube/config file. The
+// configuration is throttled as set by SetAPIThrottling.
 func getKubernetesConfig() *rest.Config {
+	if cfgPath := os.Getenv(clientcmd.RecommendedConfigPathEnvVar); cfgPath != "" {
+		config, err := loadKubeConfig()
+		if err != nil {
+			log.Fatalf("failed to construct config for $%s %q: %v", clientcmd.RecommendedConfigPathEnvVar, cfgPath, err)
+		}
+		applyAPIThrottling(config)
+		return config
+	}
+
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		if err != rest.ErrNotInCluster {
 			log.Fatalf("failed to connect within cluster: %v", err)
 		}
 
-		homeDir, err := os.UserHomeDir()
+		config, err = loadKubeConfig()
 		if err != nil {
-			log.Fatalf("could not find a home directory for user: %v", err)
-		}
-
-		cfgPathBuilder := &strings.Builder{}
-		cfgPathBuilder.WriteString(homeDir)
-		if homeDir[:len(homeDir)-1] != "/" {
-			cfgPathBuilder.WriteString("/")
-		}
-		cfgPathBuilder.WriteString(".kube/config")
-		cfgPath := cfgPathBuilder.String()
-
-		config, err = clientcmd.BuildConfigFromFlags("", cfgPath)
-		if err != nil {
-			log.Fatalf("failed to construct config for path %q: %v", cfgPath, err)
+			log.Fatalf("failed to construct config for path %q: %v", clientcmd.RecommendedHomeFile, err)
 		}
 	}
+	applyAPIThrottling(config)
 	return config
 }
+
+// loadKubeConfig loads the configuration from the kubeconfig files, using the
+// same rules as kubectl. The files listed in the KUBECONFIG environment
+// variable are merged, and ~/.kube/config is used when it is unset.
+func loadKubeConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
@@ -20,11 +20,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"sigs.k8s.io/yaml"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
 )
 
 // DecodeFromFiles reads LoadTest configurations from a set of files.
@@ -81,3 +84,66 @@ func decodeNext(scanner *bufio.Scanner) (*grpcv1.LoadTest, error) {
 	err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), config)
 	return config, err
 }
+
+// SetNamespace places LoadTest configurations in the namespace that the
+// runner creates tests in. An error is returned if a configuration declares a
+// different namespace, since the runner never creates tests across
+// namespaces.
+func SetNamespace(configs []*grpcv1.LoadTest, namespace string) error {
+	for _, loadTest := range configs {
+		if loadTest.Namespace != "" && loadTest.Namespace != namespace {
+			return fmt.Errorf("test %s is in namespace %q, but tests are created in namespace %q", loadTest.Name, loadTest.Namespace, namespace)
+		}
+		loadTest.Namespace = namespace
+	}
+	return nil
+}
+
+// ShardByScenario splits each LoadTest configuration that lists several
+// scenarios into one configuration per scenario, so that each scenario runs
+// with its own workers and is reported as a separate test case.
+func ShardByScenario(configs []*grpcv1.LoadTest) ([]*grpcv1.LoadTest, error) {
+	var shards []*grpcv1.LoadTest
+	for _, loadTest := range configs {
+		s, err := kubehelpers.ShardByScenario(loadTest)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, s...)
+	}
+	return shards, nil
+}
+
+// SetScenarioOverrides annotates LoadTest configurations so that the
+// controller overrides the warmup and benchmark durations of their scenarios.
+// Negative values leave the corresponding duration unchanged.
+func SetScenarioOverrides(configs []*grpcv1.LoadTest, warmupSeconds int, benchmarkSeconds int) {
+	for _, loadTest := range configs {
+		if warmupSeconds >= 0 {
+			setAnnotation(loadTest, config.WarmupSecondsAnnotation, strconv.Itoa(warmupSeconds))
+		}
+		if benchmarkSeconds >= 0 {
+			setAnnotation(loadTest, config.BenchmarkSecondsAnnotation, strconv.Itoa(benchmarkSeconds))
+		}
+	}
+}
+
+// SetSpecHashes annotates and labels LoadTest configurations with the hash of
+// their specs, so that tests with identical specs can be found later. An
+// error is returned if the hash of any configuration cannot be computed.
+func SetSpecHashes(configs []*grpcv1.LoadTest) error {
+	for _, loadTest := range configs {
+		if err := kubehelpers.SetSpecHash(loadTest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAnnotation sets an annotation on a LoadTest configuration.
+func setAnnotation(loadTest *grpcv1.LoadTest, key string, value string) {
+	if loadTest.Annotations == nil {
+		loadTest.Annotations = make(map[string]string)
+	}
+	loadTest.Annotations[key] = value
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// Dependencies tracks the tests of a run that other tests depend on, as
+// declared by the DependsOnAnnotation of each configuration. A dependent test
+// is only created once all of its prerequisites have succeeded.
+type Dependencies struct {
+	// prerequisites maps the name of each test to the names of the tests
+	// that it depends on.
+	prerequisites map[string][]string
+
+	// results maps the name of each test to its result.
+	results map[string]*dependencyResult
+}
+
+// dependencyResult records whether a test has finished and succeeded.
+type dependencyResult struct {
+	once      sync.Once
+	done      chan struct{}
+	succeeded bool
+}
+
+// NewDependencies builds the dependency graph of a set of configurations. A
+// dependency on the name of a test that was sharded by scenario is a
+// dependency on all of its shards. An error is returned if a configuration
+// depends on a test that is not part of the run, or if the dependencies form
+// a cycle. Nil is returned if no configuration has dependencies.
+func NewDependencies(configs []*grpcv1.LoadTest) (*Dependencies, error) {
+	byName := make(map[string][]string)
+	for _, loadTest := range configs {
+		byName[loadTest.Name] = append(byName[loadTest.Name], loadTest.Name)
+		if shardOf := loadTest.Annotations[config.ShardOfAnnotation]; shardOf != "" && shardOf != loadTest.Name {
+			byName[shardOf] = append(byName[shardOf], loadTest.Name)
+		}
+	}
+
+	prerequisites := make(map[string][]string)
+	for _, loadTest := range configs {
+		for _, name := range parseDependsOn(loadTest.Annotations[config.DependsOnAnnotation]) {
+			names, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("test %s depends on test %s, which is not part of the run", loadTest.Name, name)
+			}
+			for _, prerequisite := range names {
+				if prerequisite == loadTest.Name {
+					return nil, fmt.Errorf("test %s depends on itself", loadTest.Name)
+				}
+				prerequisites[loadTest.Name] = append(prerequisites[loadTest.Name], prerequisite)
+			}
+		}
+	}
+	if len(prerequisites) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, loadTest := range configs {
+		if seen[loadTest.Name] {
+			return nil, fmt.Errorf("test name %s is used more than once, so dependencies on it are ambiguous", loadTest.Name)
+		}
+		seen[loadTest.Name] = true
+	}
+
+	d := &Dependencies{
+		prerequisites: prerequisites,
+		results:       make(map[string]*dependencyResult),
+	}
+	for _, loadTest := range configs {
+		d.results[loadTest.Name] = &dependencyResult{done: make(chan struct{})}
+	}
+	if _, err := d.Sort(configs); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parseDependsOn returns the names listed in the value of a
+// DependsOnAnnotation.
+func parseDependsOn(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Sort returns the configurations ordered so that each test comes after the
+// tests it depends on. Tests keep their relative order otherwise. Queues
+// start their tests in this order, which guarantees that every test that a
+// queue waits for is already running or has been started by its own queue.
+// An error naming the tests involved is returned if the dependencies form a
+// cycle. The configurations are returned unchanged if the dependencies are
+// nil.
+func (d *Dependencies) Sort(configs []*grpcv1.LoadTest) ([]*grpcv1.LoadTest, error) {
+	if d == nil {
+		return configs, nil
+	}
+
+	byName := make(map[string]*grpcv1.LoadTest)
+	for _, loadTest := range configs {
+		byName[loadTest.Name] = loadTest
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int)
+	var path []string
+	var sorted []*grpcv1.LoadTest
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return fmt.Errorf("dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+				}
+			}
+		}
+		states[name] = visiting
+		path = append(path, name)
+		for _, prerequisite := range d.prerequisites[name] {
+			if err := visit(prerequisite); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[name] = visited
+		if loadTest, ok := byName[name]; ok {
+			sorted = append(sorted, loadTest)
+		}
+		return nil
+	}
+	for _, loadTest := range configs {
+		if err := visit(loadTest.Name); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// Wait blocks until every test that a test depends on has finished, or the
+// context is cancelled. It returns immediately if the dependencies are nil.
+func (d *Dependencies) Wait(ctx context.Context, name string) error {
+	if d == nil {
+		return nil
+	}
+	for _, prerequisite := range d.prerequisites[name] {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.results[prerequisite].done:
+		}
+	}
+	return nil
+}
+
+// Unsatisfied returns the names of the tests that a test depends on that have
+// not succeeded, including tests that have not finished. Nil is returned if
+// the dependencies are nil.
+func (d *Dependencies) Unsatisfied(name string) []string {
+	if d == nil {
+		return nil
+	}
+	var names []string
+	for _, prerequisite := range d.prerequisites[name] {
+		result := d.results[prerequisite]
+		select {
+		case <-result.done:
+			if result.succeeded {
+				continue
+			}
+		default:
+		}
+		names = append(names, prerequisite)
+	}
+	return names
+}
+
+// Includes reports whether a test depends on other tests, or other tests
+// depend on it. It returns false if the dependencies are nil.
+func (d *Dependencies) Includes(name string) bool {
+	if d == nil {
+		return false
+	}
+	if len(d.prerequisites[name]) > 0 {
+		return true
+	}
+	for _, prerequisites := range d.prerequisites {
+		for _, prerequisite := range prerequisites {
+			if prerequisite == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Finish records the result of a test and releases the tests that depend on
+// it. Only the first result of each test is recorded. It does nothing if the
+// dependencies are nil.
+func (d *Dependencies) Finish(name string, succeeded bool) {
+	if d == nil {
+		return
+	}
+	result, ok := d.results[name]
+	if !ok {
+		return
+	}
+	result.once.Do(func() {
+		result.succeeded = succeeded
+		close(result.done)
+	})
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/failure"
+	"github.com/grpc/test-infra/tools/runner/xunit"
+)
+
+// newDependentTest returns a test that depends on the named tests.
+func newDependentTest(name string, dependsOn string) *grpcv1.LoadTest {
+	loadTest := &grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{},
+		},
+	}
+	if dependsOn != "" {
+		loadTest.Annotations[config.DependsOnAnnotation] = dependsOn
+	}
+	return loadTest
+}
+
+var _ = ginkgo.Describe("Dependencies", func() {
+	ginkgo.Describe("NewDependencies", func() {
+		ginkgo.It("returns nil when no test has dependencies", func() {
+			dependencies, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", ""),
+				newDependentTest("b", ""),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dependencies).To(BeNil())
+		})
+
+		ginkgo.It("rejects dependencies on tests that are not part of the run", func() {
+			_, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", "missing"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("not part of the run")))
+		})
+
+		ginkgo.It("rejects tests that depend on themselves", func() {
+			_, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", "a"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("depends on itself")))
+		})
+
+		ginkgo.It("rejects dependencies that form a cycle", func() {
+			_, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", "c"),
+				newDependentTest("b", "a"),
+				newDependentTest("c", "b"),
+			})
+			Expect(err).To(MatchError("dependencies form a cycle: a -> c -> b -> a"))
+		})
+
+		ginkgo.It("rejects dependencies on names that are used more than once", func() {
+			_, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", ""),
+				newDependentTest("a", ""),
+				newDependentTest("b", "a"),
+			})
+			Expect(err).To(MatchError(ContainSubstring("used more than once")))
+		})
+
+		ginkgo.It("makes dependencies on a sharded test depend on all of its shards", func() {
+			shard0 := newDependentTest("a-0", "")
+			shard0.Annotations[config.ShardOfAnnotation] = "a"
+			shard1 := newDependentTest("a-1", "")
+			shard1.Annotations[config.ShardOfAnnotation] = "a"
+			dependencies, err := NewDependencies([]*grpcv1.LoadTest{
+				shard0,
+				shard1,
+				newDependentTest("b", "a"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dependencies.Unsatisfied("b")).To(Equal([]string{"a-0", "a-1"}))
+			dependencies.Finish("a-0", true)
+			Expect(dependencies.Unsatisfied("b")).To(Equal([]string{"a-1"}))
+			dependencies.Finish("a-1", true)
+			Expect(dependencies.Unsatisfied("b")).To(BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("Sort", func() {
+		ginkgo.It("orders tests after the tests they depend on", func() {
+			configs := []*grpcv1.LoadTest{
+				newDependentTest("c", "b"),
+				newDependentTest("d", ""),
+				newDependentTest("b", "a"),
+				newDependentTest("a", ""),
+				newDependentTest("e", "a, d"),
+			}
+			dependencies, err := NewDependencies(configs)
+			Expect(err).ToNot(HaveOccurred())
+
+			sorted, err := dependencies.Sort(configs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testNames(sorted)).To(Equal([]string{"a", "b", "c", "d", "e"}))
+		})
+
+		ginkgo.It("returns the tests unchanged when the dependencies are nil", func() {
+			var dependencies *Dependencies
+			configs := []*grpcv1.LoadTest{
+				newDependentTest("b", ""),
+				newDependentTest("a", ""),
+			}
+
+			sorted, err := dependencies.Sort(configs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sorted).To(Equal(configs))
+		})
+	})
+
+	ginkgo.Describe("Wait", func() {
+		var dependencies *Dependencies
+
+		ginkgo.BeforeEach(func() {
+			var err error
+			dependencies, err = NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", ""),
+				newDependentTest("b", ""),
+				newDependentTest("c", "a,b"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		ginkgo.It("blocks until every prerequisite has finished", func() {
+			done := make(chan error, 1)
+			go func() {
+				done <- dependencies.Wait(context.Background(), "c")
+			}()
+
+			dependencies.Finish("a", true)
+			Consistently(done, 50*time.Millisecond).ShouldNot(Receive())
+			dependencies.Finish("b", false)
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		ginkgo.It("returns immediately for tests without prerequisites", func() {
+			Expect(dependencies.Wait(context.Background(), "a")).To(Succeed())
+		})
+
+		ginkgo.It("stops waiting when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			Expect(dependencies.Wait(ctx, "c")).To(MatchError(context.Canceled))
+		})
+	})
+
+	ginkgo.Describe("Unsatisfied", func() {
+		ginkgo.It("lists prerequisites that have not finished or did not succeed", func() {
+			dependencies, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", ""),
+				newDependentTest("b", ""),
+				newDependentTest("c", ""),
+				newDependentTest("d", "a,b,c"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			dependencies.Finish("a", true)
+			dependencies.Finish("b", false)
+			Expect(dependencies.Unsatisfied("d")).To(Equal([]string{"b", "c"}))
+		})
+
+		ginkgo.It("records only the first result of each test", func() {
+			dependencies, err := NewDependencies([]*grpcv1.LoadTest{
+				newDependentTest("a", ""),
+				newDependentTest("b", "a"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			dependencies.Finish("a", false)
+			dependencies.Finish("a", true)
+			Expect(dependencies.Unsatisfied("b")).To(Equal([]string{"a"}))
+		})
+	})
+
+	ginkgo.It("skips tests whose prerequisites failed, and the tests that depend on them", func() {
+		configs := []*grpcv1.LoadTest{
+			newDependentTest("a", ""),
+			newDependentTest("b", "a"),
+			newDependentTest("c", "b"),
+		}
+		dependencies, err := NewDependencies(configs)
+		Expect(err).ToNot(HaveOccurred())
+		runner := NewRunner(nil, nil, func() {}, 0, false, "", nil, false, nil, nil, nil, 0, dependencies, nil, nil)
+		suiteReporter := NewReporter(&xunit.Report{}).NewTestSuiteReporter("queue", "", TestCaseNameFromAnnotations())
+		done := make(chan *TestCaseReporter, len(configs))
+
+		dependencies.Finish("a", false)
+		for _, config := range configs[1:] {
+			reporter := suiteReporter.NewTestCaseReporter(config)
+			runner.runTest(context.Background(), config, reporter, "", done)
+
+			var result *TestCaseReporter
+			Expect(done).To(Receive(&result))
+			Expect(result.Failed()).To(BeTrue())
+			Expect(result.Reason()).To(Equal(failure.DependencyFailed))
+		}
+		Expect(dependencies.Unsatisfied("c")).To(Equal([]string{"b"}))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"fmt"
+	"log"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+const (
+	// DuplicatesAllow runs every test, including tests with the same spec
+	// as an earlier test in the run, after logging a warning for them.
+	DuplicatesAllow = "allow"
+
+	// DuplicatesSkip drops tests with the same spec as an earlier test in
+	// the run.
+	DuplicatesSkip = "skip"
+
+	// DuplicatesRename runs tests with the same spec as an earlier test in
+	// the run under a distinct name.
+	DuplicatesRename = "rename"
+)
+
+// ValidateDuplicatesPolicy checks that a policy for duplicate tests is one of
+// DuplicatesAllow, DuplicatesSkip or DuplicatesRename.
+func ValidateDuplicatesPolicy(policy string) error {
+	switch policy {
+	case DuplicatesAllow, DuplicatesSkip, DuplicatesRename:
+		return nil
+	default:
+		return fmt.Errorf("unknown policy for duplicate tests %q, must be one of %q, %q or %q", policy, DuplicatesAllow, DuplicatesSkip, DuplicatesRename)
+	}
+}
+
+// DedupeConfigs finds LoadTest configurations with the same spec hash as an
+// earlier configuration, which usually come from a file that is listed twice
+// or a test that is assigned to two queues, and logs a warning for each of
+// them. Depending on the policy, duplicates are kept, dropped, or renamed by
+// appending a numbered suffix to their names. Duplicates that depend on other
+// tests, or that other tests depend on, are kept unchanged. Spec hashes must
+// be set before this function is called.
+func DedupeConfigs(configs []*grpcv1.LoadTest, policy string, dependencies *Dependencies) []*grpcv1.LoadTest {
+	names := make(map[string]bool)
+	for _, loadTest := range configs {
+		names[loadTest.Name] = true
+	}
+
+	firstByHash := make(map[string]*grpcv1.LoadTest)
+	counts := make(map[string]int)
+	deduped := make([]*grpcv1.LoadTest, 0, len(configs))
+	for _, loadTest := range configs {
+		hash := loadTest.Labels[config.SpecHashLabel]
+		first, ok := firstByHash[hash]
+		if hash == "" || !ok {
+			firstByHash[hash] = loadTest
+			deduped = append(deduped, loadTest)
+			continue
+		}
+
+		switch {
+		case policy == DuplicatesAllow:
+			log.Printf("Warning: test %s has the same spec as test %s (spec hash %s)", loadTest.Name, first.Name, hash)
+		case dependencies.Includes(loadTest.Name):
+			log.Printf("Warning: test %s has the same spec as test %s (spec hash %s), but it is kept because it has dependencies", loadTest.Name, first.Name, hash)
+		case policy == DuplicatesSkip:
+			log.Printf("Warning: skipping test %s, which has the same spec as test %s (spec hash %s)", loadTest.Name, first.Name, hash)
+			continue
+		case policy == DuplicatesRename:
+			name := loadTest.Name
+			for names[name] {
+				counts[loadTest.Name]++
+				name = fmt.Sprintf("%s-dup%d", loadTest.Name, counts[loadTest.Name])
+			}
+			names[name] = true
+			log.Printf("Warning: renaming test %s to %s, since it has the same spec as test %s (spec hash %s)", loadTest.Name, name, first.Name, hash)
+			loadTest = loadTest.DeepCopy()
+			loadTest.Name = name
+		}
+		deduped = append(deduped, loadTest)
+	}
+	return deduped
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// newHashedTest returns a test with the given name and spec hash.
+func newHashedTest(name string, hash string) *grpcv1.LoadTest {
+	return &grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{config.SpecHashLabel: hash},
+			Annotations: map[string]string{},
+		},
+	}
+}
+
+// testNames returns the names of a list of tests.
+func testNames(configs []*grpcv1.LoadTest) []string {
+	var names []string
+	for _, loadTest := range configs {
+		names = append(names, loadTest.Name)
+	}
+	return names
+}
+
+var _ = ginkgo.Describe("DedupeConfigs", func() {
+	ginkgo.It("keeps every test when duplicates are allowed", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("a", "1"),
+		}
+
+		deduped := DedupeConfigs(configs, DuplicatesAllow, nil)
+		Expect(deduped).To(HaveLen(2))
+		Expect(deduped[0]).To(BeIdenticalTo(configs[0]))
+		Expect(deduped[1]).To(BeIdenticalTo(configs[1]))
+	})
+
+	ginkgo.It("allows duplicates by default", func() {
+		Expect(DefaultOptions().Duplicates).To(Equal(DuplicatesAllow))
+	})
+
+	ginkgo.It("drops duplicates when they are skipped", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("b", "2"),
+			newHashedTest("a", "1"),
+			newHashedTest("c", "1"),
+		}
+
+		Expect(testNames(DedupeConfigs(configs, DuplicatesSkip, nil))).To(Equal([]string{"a", "b"}))
+	})
+
+	ginkgo.It("never treats tests without a spec hash as duplicates", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", ""),
+			newHashedTest("b", ""),
+		}
+
+		Expect(testNames(DedupeConfigs(configs, DuplicatesSkip, nil))).To(Equal([]string{"a", "b"}))
+	})
+
+	ginkgo.It("renames duplicates with a numbered suffix", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("a", "1"),
+			newHashedTest("a", "1"),
+		}
+
+		deduped := DedupeConfigs(configs, DuplicatesRename, nil)
+		Expect(testNames(deduped)).To(Equal([]string{"a", "a-dup1", "a-dup2"}))
+		Expect(deduped[0]).To(BeIdenticalTo(configs[0]))
+		Expect(testNames(configs)).To(Equal([]string{"a", "a", "a"}))
+	})
+
+	ginkgo.It("renames duplicates with a different name", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("b", "1"),
+		}
+
+		Expect(testNames(DedupeConfigs(configs, DuplicatesRename, nil))).To(Equal([]string{"a", "b-dup1"}))
+	})
+
+	ginkgo.It("does not rename duplicates to the name of another test", func() {
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("a", "1"),
+			newHashedTest("a-dup1", "2"),
+			newHashedTest("a", "1"),
+		}
+
+		deduped := DedupeConfigs(configs, DuplicatesRename, nil)
+		Expect(testNames(deduped)).To(Equal([]string{"a", "a-dup2", "a-dup1", "a-dup3"}))
+	})
+
+	ginkgo.It("keeps duplicates that have dependencies", func() {
+		dependent := newHashedTest("c", "2")
+		dependent.Annotations[config.DependsOnAnnotation] = "b"
+		configs := []*grpcv1.LoadTest{
+			newHashedTest("a", "1"),
+			newHashedTest("b", "1"),
+			dependent,
+			newHashedTest("d", "2"),
+		}
+		dependencies, err := NewDependencies(configs)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(testNames(DedupeConfigs(configs, DuplicatesSkip, dependencies))).To(Equal([]string{"a", "b", "c"}))
+
+		deduped := DedupeConfigs(configs, DuplicatesRename, dependencies)
+		Expect(testNames(deduped)).To(Equal([]string{"a", "b", "c", "d-dup1"}))
+		Expect(deduped[1]).To(BeIdenticalTo(configs[1]))
+	})
+})
+
+var _ = ginkgo.Describe("ValidateDuplicatesPolicy", func() {
+	ginkgo.It("accepts known policies", func() {
+		for _, policy := range []string{DuplicatesAllow, DuplicatesSkip, DuplicatesRename} {
+			Expect(ValidateDuplicatesPolicy(policy)).To(Succeed())
+		}
+	})
+
+	ginkgo.It("rejects unknown policies", func() {
+		Expect(ValidateDuplicatesPolicy("ignore")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// envoyDumpKinds lists the kinds of dumps collected from the Envoy sidecar of
+// a proxied test. Each kind is served by the admin endpoint with the same
+// name.
+var envoyDumpKinds = []string{"stats", "config_dump"}
+
+// EnvoyDumpInfo contains information for each file dumped from the admin
+// endpoint of an Envoy sidecar.
+type EnvoyDumpInfo struct {
+	// PodNameElem is the element added to the LoadTest name to
+	// construct the pod name, such as client-0.
+	PodNameElem string
+	// Kind is the kind of the dump, either stats or config_dump.
+	Kind string
+	// DumpPath is the path pointing to the dump file.
+	DumpPath string
+	// URL is the URL of the uploaded dump file. It is empty if the dump
+	// was not uploaded.
+	URL string
+}
+
+// CollectEnvoyDumps saves the stats and the config dump of the Envoy sidecar
+// of each client of a proxied test to files under a given directory. Only
+// pods with a sidecar container are dumped.
+//
+// The dumps are taken just before the end of the benchmark window, derived
+// from the start time of the driver and the warmup and benchmark durations of
+// the scenario, so the stats cover the whole benchmark and the sidecar is
+// still running. Information about each saved dump is returned as a pointer to
+// an EnvoyDumpInfo object. An error is returned along with the dumps that were
+// saved if any dump could not be collected.
+func CollectEnvoyDumps(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter, pods []*corev1.Pod, dumpDir string) ([]*EnvoyDumpInfo, error) {
+	warmup, benchmark, err := kubehelpers.ScenarioDurations(loadTest.Annotations, loadTest.Spec.ScenariosJSON)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine benchmark window: %v", err)
+	}
+
+	end := driverStartTime(pods).Add(warmup + benchmark - profileMargin)
+
+	if err := os.MkdirAll(dumpDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create dump output directory %s: %v", dumpDir, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Until(end)):
+	}
+
+	var dumpInfos []*EnvoyDumpInfo
+	var errs []error
+	for _, pod := range pods {
+		if kubehelpers.ContainerForName(config.SidecarContainerName, pod.Spec.Containers) == nil {
+			continue
+		}
+
+		for _, kind := range envoyDumpKinds {
+			filePath := filepath.Join(dumpDir, EnvoyDumpFileName(pod.Name, kind))
+			if err := saveProxyGet(ctx, podsGetter, pod, config.EnvoyAdminPort, "/"+kind, nil, filePath); err != nil {
+				errs = append(errs, fmt.Errorf("could not get Envoy %s from pod %s: %v", kind, pod.Name, err))
+				continue
+			}
+			dumpInfos = append(dumpInfos, &EnvoyDumpInfo{
+				PodNameElem: PodNameElem(pod.Name, loadTest.Name),
+				Kind:        kind,
+				DumpPath:    filePath,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return dumpInfos, fmt.Errorf("failed to collect %d Envoy dump(s), first error: %v", len(errs), errs[0])
+	}
+	return dumpInfos, nil
+}
+
+// EnvoyDumpFileName constructs a dump file name from pod name and the kind of
+// dump.
+func EnvoyDumpFileName(podName string, kind string) string {
+	extension := "txt"
+	if kind == "config_dump" {
+		extension = "json"
+	}
+	return fmt.Sprintf("%s-envoy-%s.%s", podName, kind, extension)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+// fakeProxyResponse is the response to a request proxied to a pod.
+type fakeProxyResponse struct {
+	body string
+	err  error
+}
+
+// DoRaw implements the rest.ResponseWrapper interface.
+func (r *fakeProxyResponse) DoRaw(ctx context.Context) ([]byte, error) {
+	return []byte(r.body), r.err
+}
+
+// Stream implements the rest.ResponseWrapper interface.
+func (r *fakeProxyResponse) Stream(ctx context.Context) (io.ReadCloser, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return io.NopCloser(strings.NewReader(r.body)), nil
+}
+
+// newEnvoyTestPod returns a pod of a test, with an Envoy sidecar if sidecar
+// is true.
+func newEnvoyTestPod(name string, sidecar bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "tests",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: config.RunContainerName}},
+		},
+	}
+	if sidecar {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: config.SidecarContainerName})
+	}
+	return pod
+}
+
+var _ = ginkgo.Describe("CollectEnvoyDumps", func() {
+	var dir string
+	var clientset *fake.Clientset
+	var requests []string
+	var failingPath string
+	var loadTest *grpcv1.LoadTest
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "envoy")
+		Expect(err).ToNot(HaveOccurred())
+
+		requests = nil
+		failingPath = ""
+		clientset = fake.NewSimpleClientset()
+		clientset.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, rest.ResponseWrapper, error) {
+			proxy := action.(k8stesting.ProxyGetAction)
+			requests = append(requests, proxy.GetName()+":"+proxy.GetPort()+proxy.GetPath())
+			if proxy.GetName()+proxy.GetPath() == failingPath {
+				return true, &fakeProxyResponse{err: errors.New("connection reset")}, nil
+			}
+			return true, &fakeProxyResponse{body: proxy.GetName() + proxy.GetPath()}, nil
+		})
+
+		// The benchmark window ends before the dumps are collected, so
+		// they are collected immediately.
+		loadTest = &grpcv1.LoadTest{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: grpcv1.LoadTestSpec{
+				ScenariosJSON: `{"scenarios": {"warmup_seconds": 0, "benchmark_seconds": 1}}`,
+			},
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	ginkgo.It("saves the stats and config dump of pods with a sidecar", func() {
+		pods := []*corev1.Pod{
+			newEnvoyTestPod("test-client-0", true),
+			newEnvoyTestPod("test-server-0", false),
+		}
+
+		dumpInfos, err := CollectEnvoyDumps(context.Background(), loadTest, clientset.CoreV1(), pods, dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(Equal([]string{
+			"test-client-0:9901/stats",
+			"test-client-0:9901/config_dump",
+		}))
+		Expect(dumpInfos).To(Equal([]*EnvoyDumpInfo{
+			{
+				PodNameElem: "client-0",
+				Kind:        "stats",
+				DumpPath:    filepath.Join(dir, "test-client-0-envoy-stats.txt"),
+			},
+			{
+				PodNameElem: "client-0",
+				Kind:        "config_dump",
+				DumpPath:    filepath.Join(dir, "test-client-0-envoy-config_dump.json"),
+			},
+		}))
+		data, err := os.ReadFile(dumpInfos[1].DumpPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("test-client-0/config_dump"))
+	})
+
+	ginkgo.It("returns the dumps that were saved along with an error", func() {
+		failingPath = "test-client-0/stats"
+		pods := []*corev1.Pod{
+			newEnvoyTestPod("test-client-0", true),
+			newEnvoyTestPod("test-client-1", true),
+		}
+
+		dumpInfos, err := CollectEnvoyDumps(context.Background(), loadTest, clientset.CoreV1(), pods, dir)
+		Expect(err).To(MatchError(ContainSubstring("failed to collect 1 Envoy dump(s)")))
+		Expect(err).To(MatchError(ContainSubstring("connection reset")))
+		Expect(dumpInfos).To(HaveLen(3))
+		for _, dumpInfo := range dumpInfos {
+			Expect(dumpInfo.DumpPath).To(BeAnExistingFile())
+		}
+		Expect(filepath.Join(dir, "test-client-0-envoy-stats.txt")).ToNot(BeAnExistingFile())
+	})
+
+	ginkgo.It("returns an error without a valid scenario", func() {
+		loadTest.Spec.ScenariosJSON = "{}"
+
+		_, err := CollectEnvoyDumps(context.Background(), loadTest, clientset.CoreV1(), nil, dir)
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+	})
+})
+
+var _ = ginkgo.Describe("EnvoyDumpFileName", func() {
+	ginkgo.It("names dumps after the pod and their kind", func() {
+		Expect(EnvoyDumpFileName("test-client-0", "stats")).To(Equal("test-client-0-envoy-stats.txt"))
+		Expect(EnvoyDumpFileName("test-client-0", "config_dump")).To(Equal("test-client-0-envoy-config_dump.json"))
+	})
+})
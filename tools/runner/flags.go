@@ -40,6 +40,34 @@ func (f *FileNames) String() string {
 	return fmt.Sprint(*f)
 }
 
+// Type reports the type of the flag, matching the pflag.Value interface.
+func (f *FileNames) Type() string {
+	return "stringArray"
+}
+
+// HookSpecs defines an accumulator flag for hooks. Each value is either a URL
+// starting with http:// or https://, or a shell command.
+type HookSpecs []string
+
+// Set implements the flag.Value interface.
+func (h *HookSpecs) Set(value string) error {
+	if value == "" {
+		return errors.New("value must not be empty")
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+// String implements the flag.Value interface.
+func (h *HookSpecs) String() string {
+	return fmt.Sprint(*h)
+}
+
+// Type reports the type of the flag, matching the pflag.Value interface.
+func (h *HookSpecs) Type() string {
+	return "stringArray"
+}
+
 // ConcurrencyLevels defines an accumulator flag for concurrency levels.
 // Concurrency levels are in the form [<queue name>:]<concurrency level>.
 // These values are parsed and accumulated into a map.
@@ -80,3 +108,8 @@ func (c *ConcurrencyLevels) Set(value string) error {
 func (c *ConcurrencyLevels) String() string {
 	return fmt.Sprint(*c)
 }
+
+// Type reports the type of the flag, matching the pflag.Value interface.
+func (c *ConcurrencyLevels) Type() string {
+	return "stringArray"
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+)
+
+// HookPhase identifies when a hook is invoked.
+type HookPhase string
+
+const (
+	// BeforeTest hooks are invoked before a load test is created.
+	BeforeTest HookPhase = "before"
+
+	// AfterTest hooks are invoked after a load test has terminated and its
+	// artifacts have been saved, or after the runner gave up on it.
+	AfterTest HookPhase = "after"
+)
+
+// HookEvent contains the metadata of a load test that is passed to hooks.
+type HookEvent struct {
+	// Phase is the phase of the test when the hook is invoked.
+	Phase HookPhase `json:"phase"`
+
+	// Name is the name of the test.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the test.
+	Namespace string `json:"namespace"`
+
+	// Queue is the name of the queue that the test was assigned to.
+	Queue string `json:"queue"`
+
+	// Labels are the labels of the test.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the annotations of the test.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// State is the state of the test. It is empty before the test is
+	// created.
+	State string `json:"state,omitempty"`
+
+	// Reason is the reason of the state of the test.
+	Reason string `json:"reason,omitempty"`
+
+	// Message describes the state of the test.
+	Message string `json:"message,omitempty"`
+}
+
+// NewHookEvent creates the event for a hook invoked in a given phase of a
+// load test.
+func NewHookEvent(phase HookPhase, loadTest *grpcv1.LoadTest, queue string) *HookEvent {
+	return &HookEvent{
+		Phase:       phase,
+		Name:        loadTest.Name,
+		Namespace:   loadTest.Namespace,
+		Queue:       queue,
+		Labels:      loadTest.Labels,
+		Annotations: loadTest.Annotations,
+		State:       string(loadTest.Status.State),
+		Reason:      loadTest.Status.Reason,
+		Message:     loadTest.Status.Message,
+	}
+}
+
+// Hook is invoked before a load test is created or after it completes, so
+// that users can integrate external systems, such as cache warmers, database
+// resets or recorders, without changing the runner.
+type Hook interface {
+	// Run invokes the hook with the metadata of a test. An error is
+	// returned if the hook fails.
+	Run(ctx context.Context, event *HookEvent) error
+}
+
+// CommandHook runs a shell command. The metadata of the test is written as
+// JSON to the standard input of the command, and the main fields are also set
+// in environment variables: $LOADTEST_HOOK_PHASE, $LOADTEST_NAME,
+// $LOADTEST_NAMESPACE, $LOADTEST_QUEUE, $LOADTEST_STATE and $LOADTEST_REASON.
+// The hook fails if the command exits with a non-zero code.
+type CommandHook struct {
+	// Command is the command, which is run with "sh -c".
+	Command string
+}
+
+// Run implements the Hook interface.
+func (h *CommandHook) Run(ctx context.Context, event *HookEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook event: %v", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	cmd.Stdin = bytes.NewReader(eventJSON)
+	cmd.Env = append(os.Environ(),
+		"LOADTEST_HOOK_PHASE="+string(event.Phase),
+		"LOADTEST_NAME="+event.Name,
+		"LOADTEST_NAMESPACE="+event.Namespace,
+		"LOADTEST_QUEUE="+event.Queue,
+		"LOADTEST_STATE="+event.State,
+		"LOADTEST_REASON="+event.Reason,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %q failed: %v: %s", h.Command, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// HTTPHook sends the metadata of the test as JSON in a POST request to a URL.
+// The hook fails if the response does not have a 2xx status code.
+type HTTPHook struct {
+	// URL is the URL that receives the request.
+	URL string
+
+	// Client is the client used to send the request. The default client is
+	// used when it is nil.
+	Client *http.Client
+}
+
+// Run implements the Hook interface.
+func (h *HTTPHook) Run(ctx context.Context, event *HookEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook event: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(eventJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %v", h.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %v", h.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request to %s failed with status %s: %s", h.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// NewHook creates a hook from its specification. Specifications that start
+// with http:// or https:// create an HTTPHook, and any other specification
+// creates a CommandHook.
+func NewHook(spec string) Hook {
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		return &HTTPHook{URL: spec}
+	}
+	return &CommandHook{Command: spec}
+}
+
+// Hooks contains the hooks invoked around each load test.
+type Hooks struct {
+	// Before lists the hooks invoked before each test is created.
+	Before []Hook
+
+	// After lists the hooks invoked after each test completes.
+	After []Hook
+
+	// Timeout is the time allowed for each hook to complete. Hooks are not
+	// limited when it is zero.
+	Timeout time.Duration
+}
+
+// NewHooks creates hooks from the specifications of the hooks invoked before
+// and after each test. Nil is returned if no hook is specified.
+func NewHooks(before []string, after []string, timeout time.Duration) *Hooks {
+	if len(before) == 0 && len(after) == 0 {
+		return nil
+	}
+	hooks := &Hooks{Timeout: timeout}
+	for _, spec := range before {
+		hooks.Before = append(hooks.Before, NewHook(spec))
+	}
+	for _, spec := range after {
+		hooks.After = append(hooks.After, NewHook(spec))
+	}
+	return hooks
+}
+
+// Run invokes the hooks of a phase in order, and stops at the first hook that
+// fails. It does nothing if the hooks are nil.
+func (h *Hooks) Run(ctx context.Context, event *HookEvent) error {
+	if h == nil {
+		return nil
+	}
+
+	hooks := h.Before
+	if event.Phase == AfterTest {
+		hooks = h.After
+	}
+	for i, hook := range hooks {
+		hookCtx, cancel := ctx, context.CancelFunc(func() {})
+		if h.Timeout > 0 {
+			hookCtx, cancel = context.WithTimeout(ctx, h.Timeout)
+		}
+		err := hook.Run(hookCtx, event)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s test hook %d failed: %v", event.Phase, i+1, err)
+		}
+	}
+	return nil
+}
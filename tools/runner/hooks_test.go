@@ -0,0 +1,234 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/failure"
+	"github.com/grpc/test-infra/tools/runner/xunit"
+)
+
+// newHookTest returns a terminated test with labels and annotations.
+func newHookTest() *grpcv1.LoadTest {
+	return &grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "tests",
+			Labels:      map[string]string{"language": "go"},
+			Annotations: map[string]string{"scenario": "go_generic"},
+		},
+		Status: grpcv1.LoadTestStatus{
+			State:   grpcv1.Errored,
+			Reason:  grpcv1.TimeoutErrored,
+			Message: "timeout exceeded",
+		},
+	}
+}
+
+var _ = ginkgo.Describe("CommandHook", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "hooks")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	ginkgo.It("passes the metadata of the test on stdin and in the environment", func() {
+		stdinFile := filepath.Join(dir, "stdin.json")
+		envFile := filepath.Join(dir, "env")
+		hook := &CommandHook{
+			Command: "cat > " + stdinFile + ` && echo "$LOADTEST_HOOK_PHASE $LOADTEST_NAME $LOADTEST_NAMESPACE $LOADTEST_QUEUE $LOADTEST_STATE $LOADTEST_REASON" > ` + envFile,
+		}
+
+		Expect(hook.Run(context.Background(), NewHookEvent(AfterTest, newHookTest(), "queue"))).To(Succeed())
+
+		data, err := os.ReadFile(stdinFile)
+		Expect(err).ToNot(HaveOccurred())
+		event := new(HookEvent)
+		Expect(json.Unmarshal(data, event)).To(Succeed())
+		Expect(event).To(Equal(&HookEvent{
+			Phase:       AfterTest,
+			Name:        "test",
+			Namespace:   "tests",
+			Queue:       "queue",
+			Labels:      map[string]string{"language": "go"},
+			Annotations: map[string]string{"scenario": "go_generic"},
+			State:       "Errored",
+			Reason:      grpcv1.TimeoutErrored,
+			Message:     "timeout exceeded",
+		}))
+
+		data, err = os.ReadFile(envFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.TrimSpace(string(data))).To(Equal("after test tests queue Errored " + grpcv1.TimeoutErrored))
+	})
+
+	ginkgo.It("fails when the command exits with a non-zero code", func() {
+		hook := &CommandHook{Command: "echo broken >&2; exit 3"}
+
+		err := hook.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))
+		Expect(err).To(MatchError(ContainSubstring("exit status 3")))
+		Expect(err).To(MatchError(ContainSubstring("broken")))
+	})
+})
+
+var _ = ginkgo.Describe("HTTPHook", func() {
+	var server *httptest.Server
+	var status int
+	var requests chan *http.Request
+	var bodies chan []byte
+
+	ginkgo.BeforeEach(func() {
+		status = http.StatusOK
+		requests = make(chan *http.Request, 1)
+		bodies = make(chan []byte, 1)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- r
+			bodies <- body
+			if r.URL.Path == "/slow" {
+				<-r.Context().Done()
+				return
+			}
+			w.WriteHeader(status)
+			io.WriteString(w, "not ready")
+		}))
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.It("posts the metadata of the test as JSON", func() {
+		hook := &HTTPHook{URL: server.URL + "/hook"}
+
+		Expect(hook.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))).To(Succeed())
+
+		var req *http.Request
+		Expect(requests).To(Receive(&req))
+		Expect(req.Method).To(Equal(http.MethodPost))
+		Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+		var body []byte
+		Expect(bodies).To(Receive(&body))
+		event := new(HookEvent)
+		Expect(json.Unmarshal(body, event)).To(Succeed())
+		Expect(event.Phase).To(Equal(BeforeTest))
+		Expect(event.Name).To(Equal("test"))
+		Expect(event.Queue).To(Equal("queue"))
+		Expect(event.Labels).To(Equal(map[string]string{"language": "go"}))
+	})
+
+	ginkgo.It("fails when the response does not have a 2xx status", func() {
+		status = http.StatusServiceUnavailable
+		hook := &HTTPHook{URL: server.URL + "/hook"}
+
+		err := hook.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))
+		Expect(err).To(MatchError(ContainSubstring("503")))
+		Expect(err).To(MatchError(ContainSubstring("not ready")))
+	})
+
+	ginkgo.It("is cancelled when it exceeds the timeout", func() {
+		hooks := &Hooks{
+			Before:  []Hook{&HTTPHook{URL: server.URL + "/slow"}},
+			Timeout: 100 * time.Millisecond,
+		}
+
+		start := time.Now()
+		err := hooks.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))
+		Expect(err).To(MatchError(ContainSubstring("before test hook 1 failed")))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
+
+var _ = ginkgo.Describe("Hooks", func() {
+	ginkgo.It("are created from commands and URLs", func() {
+		hooks := NewHooks([]string{"true", "https://example.com/hook"}, []string{"http://example.com/hook"}, time.Minute)
+
+		Expect(hooks.Before).To(Equal([]Hook{
+			&CommandHook{Command: "true"},
+			&HTTPHook{URL: "https://example.com/hook"},
+		}))
+		Expect(hooks.After).To(Equal([]Hook{&HTTPHook{URL: "http://example.com/hook"}}))
+		Expect(hooks.Timeout).To(Equal(time.Minute))
+		Expect(NewHooks(nil, nil, time.Minute)).To(BeNil())
+	})
+
+	ginkgo.It("run the hooks of the phase in order and stop at the first failure", func() {
+		dir, err := os.MkdirTemp("", "hooks")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		marker := filepath.Join(dir, "marker")
+		hooks := NewHooks([]string{"exit 1", "touch " + marker}, []string{"touch " + marker}, 0)
+
+		err = hooks.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))
+		Expect(err).To(MatchError(ContainSubstring("before test hook 1 failed")))
+		Expect(marker).ToNot(BeAnExistingFile())
+
+		Expect(hooks.Run(context.Background(), NewHookEvent(AfterTest, newHookTest(), "queue"))).To(Succeed())
+		Expect(marker).To(BeAnExistingFile())
+	})
+
+	ginkgo.It("kill commands that exceed the timeout", func() {
+		hooks := NewHooks([]string{"exec sleep 10"}, nil, 100*time.Millisecond)
+
+		start := time.Now()
+		err := hooks.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))
+		Expect(err).To(MatchError(ContainSubstring("before test hook 1 failed")))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	ginkgo.It("do nothing when they are nil", func() {
+		var hooks *Hooks
+		Expect(hooks.Run(context.Background(), NewHookEvent(BeforeTest, newHookTest(), "queue"))).To(Succeed())
+	})
+
+	ginkgo.It("skip the test when a before hook fails", func() {
+		hooks := NewHooks([]string{"exit 1"}, nil, time.Minute)
+		runner := NewRunner(nil, nil, func() {}, 0, false, "", nil, false, nil, nil, hooks, 0, nil, nil, nil)
+		suiteReporter := NewReporter(&xunit.Report{}).NewTestSuiteReporter("queue", "", TestCaseNameFromAnnotations())
+		loadTest := newHookTest()
+		loadTest.Status = grpcv1.LoadTestStatus{}
+		reporter := suiteReporter.NewTestCaseReporter(loadTest)
+		done := make(chan *TestCaseReporter, 1)
+
+		runner.runTest(context.Background(), loadTest, reporter, "", done)
+
+		var result *TestCaseReporter
+		Expect(done).To(Receive(&result))
+		Expect(result.Failed()).To(BeTrue())
+		Expect(result.Reason()).To(Equal(failure.HookFailed))
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Metrics collects counters and durations for the tests in each queue, and
+// pushes them to a Prometheus pushgateway. This allows long-running jobs to
+// be monitored while they run, and alerts to detect runs that are stuck.
+//
+// All methods are safe to call on a nil pointer, in which case they do
+// nothing. This allows metrics to be disabled by passing nil to the runner.
+type Metrics struct {
+	registry *prometheus.Registry
+	pusher   *push.Pusher
+
+	submitted   *prometheus.CounterVec
+	succeeded   *prometheus.CounterVec
+	failed      *prometheus.CounterVec
+	durations   *prometheus.HistogramVec
+	waitTimes   *prometheus.HistogramVec
+	activeTests *prometheus.GaugeVec
+	concurrency *prometheus.GaugeVec
+	lastPush    prometheus.Gauge
+	apiRetries  prometheus.Counter
+	apiBackoff  prometheus.Counter
+}
+
+// NewMetrics creates a Metrics instance that pushes to the pushgateway at the
+// given URL, grouping all metrics under the given job name.
+func NewMetrics(pushgatewayURL, job string) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		submitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "runner_tests_submitted_total",
+			Help: "Number of LoadTests created by the runner.",
+		}, []string{"queue"}),
+		succeeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "runner_tests_succeeded_total",
+			Help: "Number of LoadTests that terminated successfully.",
+		}, []string{"queue"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "runner_tests_failed_total",
+			Help: "Number of LoadTests that errored or could not be created or polled.",
+		}, []string{"queue"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "runner_test_duration_seconds",
+			Help:    "Time from the start of a test until it terminated.",
+			Buckets: prometheus.ExponentialBuckets(30, 2, 10),
+		}, []string{"queue"}),
+		waitTimes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "runner_test_wait_seconds",
+			Help:    "Time from the creation of a test until it was observed running.",
+			Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+		}, []string{"queue"}),
+		activeTests: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "runner_queue_active_tests",
+			Help: "Number of tests currently running in a queue.",
+		}, []string{"queue"}),
+		concurrency: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "runner_queue_concurrency_level",
+			Help: "Maximum number of tests that may run concurrently in a queue.",
+		}, []string{"queue"}),
+		lastPush: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "runner_last_push_timestamp_seconds",
+			Help: "Unix time of the most recent push, used to detect stuck runs.",
+		}),
+		apiRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "runner_api_retries_total",
+			Help: "Number of requests to the Kubernetes API retried after being throttled.",
+		}),
+		apiBackoff: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "runner_api_backoff_seconds_total",
+			Help: "Time spent waiting to retry throttled requests to the Kubernetes API.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.submitted,
+		m.succeeded,
+		m.failed,
+		m.durations,
+		m.waitTimes,
+		m.activeTests,
+		m.concurrency,
+		m.lastPush,
+		m.apiRetries,
+		m.apiBackoff,
+	)
+
+	m.pusher = push.New(pushgatewayURL, job).Gatherer(m.registry)
+
+	return m
+}
+
+// SetConcurrencyLevel records the concurrency level of a queue.
+func (m *Metrics) SetConcurrencyLevel(qName string, level int) {
+	if m == nil {
+		return
+	}
+	m.concurrency.WithLabelValues(qName).Set(float64(level))
+}
+
+// TestStarted records that a test has begun in a queue.
+func (m *Metrics) TestStarted(qName string) {
+	if m == nil {
+		return
+	}
+	m.activeTests.WithLabelValues(qName).Inc()
+}
+
+// TestSubmitted records that a LoadTest was created in a queue.
+func (m *Metrics) TestSubmitted(qName string) {
+	if m == nil {
+		return
+	}
+	m.submitted.WithLabelValues(qName).Inc()
+}
+
+// TestRunning records the time a test waited before it was observed running.
+func (m *Metrics) TestRunning(qName string, wait time.Duration) {
+	if m == nil {
+		return
+	}
+	m.waitTimes.WithLabelValues(qName).Observe(wait.Seconds())
+}
+
+// TestFinished records that a test has finished in a queue, whether it
+// succeeded and how long it took.
+func (m *Metrics) TestFinished(qName string, succeeded bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.activeTests.WithLabelValues(qName).Dec()
+	m.durations.WithLabelValues(qName).Observe(duration.Seconds())
+	if succeeded {
+		m.succeeded.WithLabelValues(qName).Inc()
+	} else {
+		m.failed.WithLabelValues(qName).Inc()
+	}
+}
+
+// APIRequestRetried records that a throttled request to the Kubernetes API is
+// retried after a delay.
+func (m *Metrics) APIRequestRetried(delay time.Duration) {
+	if m == nil {
+		return
+	}
+	m.apiRetries.Inc()
+	m.apiBackoff.Add(delay.Seconds())
+}
+
+// Push sends the current value of all metrics to the pushgateway.
+func (m *Metrics) Push() error {
+	if m == nil {
+		return nil
+	}
+	m.lastPush.SetToCurrentTime()
+	return m.pusher.Push()
+}
+
+// PushPeriodically pushes metrics at a fixed interval until the context is
+// cancelled. Failures are logged, since they should not interrupt the tests.
+func (m *Metrics) PushPeriodically(ctx context.Context, interval time.Duration) {
+	if m == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Push(); err != nil {
+				log.Printf("Failed to push metrics: %v", err)
+			}
+		}
+	}
+}
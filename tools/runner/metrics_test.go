@@ -0,0 +1,115 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = ginkgo.Describe("Metrics", func() {
+	var server *httptest.Server
+	var pushes chan string
+	var metrics *Metrics
+
+	ginkgo.BeforeEach(func() {
+		pushes = make(chan string, 10)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			select {
+			case pushes <- r.Method + " " + r.URL.Path + "\n" + string(body):
+			default:
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		metrics = NewMetrics(server.URL, "runner")
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.It("counts the tests of each queue", func() {
+		metrics.TestSubmitted("a")
+		metrics.TestSubmitted("a")
+		metrics.TestSubmitted("b")
+		metrics.TestStarted("a")
+		metrics.TestStarted("a")
+		metrics.TestFinished("a", true, time.Minute)
+		metrics.TestFinished("a", false, time.Minute)
+		metrics.SetConcurrencyLevel("a", 3)
+
+		Expect(testutil.ToFloat64(metrics.submitted.WithLabelValues("a"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(metrics.submitted.WithLabelValues("b"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.succeeded.WithLabelValues("a"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.failed.WithLabelValues("a"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.activeTests.WithLabelValues("a"))).To(BeZero())
+		Expect(testutil.ToFloat64(metrics.concurrency.WithLabelValues("a"))).To(Equal(3.0))
+		Expect(testutil.CollectAndCount(metrics.durations)).To(Equal(1))
+	})
+
+	ginkgo.It("pushes metrics under the job name", func() {
+		metrics.TestSubmitted("a")
+
+		Expect(metrics.Push()).To(Succeed())
+		Expect(testutil.ToFloat64(metrics.lastPush)).To(BeNumerically(">", 0))
+
+		var push string
+		Eventually(pushes).Should(Receive(&push))
+		Expect(push).To(HavePrefix("PUT /metrics/job/runner\n"))
+	})
+
+	ginkgo.It("returns errors from the pushgateway", func() {
+		server.Close()
+
+		Expect(metrics.Push()).ToNot(Succeed())
+	})
+
+	ginkgo.It("pushes metrics periodically until the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			metrics.PushPeriodically(ctx, 10*time.Millisecond)
+		}()
+
+		Eventually(pushes).Should(Receive())
+		Eventually(pushes).Should(Receive())
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	ginkgo.It("does nothing when it is nil", func() {
+		var metrics *Metrics
+
+		metrics.TestSubmitted("a")
+		metrics.TestStarted("a")
+		metrics.TestRunning("a", time.Second)
+		metrics.TestFinished("a", true, time.Minute)
+		metrics.SetConcurrencyLevel("a", 1)
+		metrics.APIRequestRetried(time.Second)
+		Expect(metrics.Push()).To(Succeed())
+		metrics.PushPeriodically(context.Background(), time.Millisecond)
+	})
+})
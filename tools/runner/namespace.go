@@ -0,0 +1,94 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/grpc/test-infra/config"
+)
+
+// runNamespacePrefix is the prefix of the generated names of the namespaces
+// created for a single run of tests.
+const runNamespacePrefix = "loadtest-run-"
+
+// componentRoleBindings maps the name of each role binding created in a
+// namespace for a run to the cluster role that it binds to the default
+// service account, so that the pods of tests can view pods and load tests.
+// These match the bindings in config/tenant/component_bindings.yaml.
+var componentRoleBindings = map[string]string{
+	"component-pod-viewer-role-binding":      "pod-viewer-role",
+	"component-loadtest-viewer-role-binding": "loadtest-viewer-role",
+}
+
+// CreateRunNamespace creates a namespace with a generated name for a single
+// run of tests, and binds the roles that the pods of tests need to its
+// default service account. The name of the namespace is returned. If the
+// bindings cannot be created, the namespace is deleted and an error is
+// returned.
+func CreateRunNamespace(ctx context.Context, clientset kubernetes.Interface) (string, error) {
+	namespace, err := clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: runNamespacePrefix,
+			Labels: map[string]string{
+				config.RunNamespaceLabel: "true",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create namespace: %v", err)
+	}
+
+	for bindingName, roleName := range componentRoleBindings {
+		_, err := clientset.RbacV1().RoleBindings(namespace.Name).Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: bindingName,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     roleName,
+			},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "default",
+				Namespace: namespace.Name,
+			}},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			DeleteRunNamespace(context.Background(), clientset, namespace.Name)
+			return "", fmt.Errorf("failed to bind %s in namespace %s: %v", roleName, namespace.Name, err)
+		}
+	}
+	return namespace.Name, nil
+}
+
+// DeleteRunNamespace deletes a namespace created for a single run of tests,
+// along with the tests and pods that remain in it.
+func DeleteRunNamespace(ctx context.Context, clientset kubernetes.Interface, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	if err := clientset.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+		return fmt.Errorf("failed to delete namespace %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/grpc/test-infra/config"
+)
+
+var _ = ginkgo.Describe("CreateRunNamespace", func() {
+	var clientset *fake.Clientset
+
+	ginkgo.BeforeEach(func() {
+		clientset = fake.NewSimpleClientset()
+		// The fake clientset does not generate names.
+		clientset.PrependReactor("create", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			namespace := action.(k8stesting.CreateAction).GetObject().(*corev1.Namespace)
+			if namespace.Name == "" {
+				namespace.Name = namespace.GenerateName + "abcde"
+			}
+			return false, nil, nil
+		})
+	})
+
+	ginkgo.It("creates a labeled namespace with the role bindings of the components", func() {
+		name, err := CreateRunNamespace(context.Background(), clientset)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("loadtest-run-abcde"))
+
+		namespace, err := clientset.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespace.Labels).To(HaveKeyWithValue(config.RunNamespaceLabel, "true"))
+
+		bindings, err := clientset.RbacV1().RoleBindings(name).List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		roles := make(map[string]string)
+		for _, binding := range bindings.Items {
+			roles[binding.Name] = binding.RoleRef.Name
+			Expect(binding.RoleRef.Kind).To(Equal("ClusterRole"))
+			Expect(binding.Subjects).To(Equal([]rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "default",
+				Namespace: name,
+			}}))
+		}
+		Expect(roles).To(Equal(map[string]string{
+			"component-pod-viewer-role-binding":      "pod-viewer-role",
+			"component-loadtest-viewer-role-binding": "loadtest-viewer-role",
+		}))
+	})
+
+	ginkgo.It("deletes the namespace when a role binding cannot be created", func() {
+		clientset.PrependReactor("create", "rolebindings", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+
+		_, err := CreateRunNamespace(context.Background(), clientset)
+		Expect(err).To(MatchError(ContainSubstring("forbidden")))
+
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespaces.Items).To(BeEmpty())
+	})
+
+	ginkgo.It("returns an error when the namespace cannot be created", func() {
+		clientset.PrependReactor("create", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("quota exceeded")
+		})
+
+		_, err := CreateRunNamespace(context.Background(), clientset)
+		Expect(err).To(MatchError(ContainSubstring("quota exceeded")))
+		Expect(clientset.Actions()).To(HaveLen(1))
+	})
+})
+
+var _ = ginkgo.Describe("DeleteRunNamespace", func() {
+	ginkgo.It("deletes the namespace in the background", func() {
+		clientset := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "loadtest-run-abcde"},
+		})
+
+		Expect(DeleteRunNamespace(context.Background(), clientset, "loadtest-run-abcde")).To(Succeed())
+
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespaces.Items).To(BeEmpty())
+		actions := clientset.Actions()
+		Expect(actions[0].GetVerb()).To(Equal("delete"))
+		deleteAction := actions[0].(k8stesting.DeleteAction)
+		Expect(*deleteAction.GetDeleteOptions().PropagationPolicy).To(Equal(metav1.DeletePropagationBackground))
+	})
+
+	ginkgo.It("returns an error when the namespace does not exist", func() {
+		clientset := fake.NewSimpleClientset()
+
+		Expect(DeleteRunNamespace(context.Background(), clientset, "loadtest-run-abcde")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/nodemetrics"
+)
+
+// NodeMetricsFileName is the name of the file that the node metrics of a test
+// are saved to, under the directory named after the test.
+const NodeMetricsFileName = "node_metrics.json"
+
+// NodeAgent locates the node agents that sample the system metrics of each
+// node, which are run by a DaemonSet that the controller creates.
+type NodeAgent struct {
+	// Namespace is the namespace of the DaemonSet.
+	Namespace string
+
+	// Port is the port that the agents serve their samples on.
+	Port int32
+}
+
+// NewNodeAgent creates a NodeAgent from the node agent settings in the
+// defaults files of the controller. Nil is returned if the defaults do not
+// configure a node agent.
+func NewNodeAgent(defaultsFiles []string) (*NodeAgent, error) {
+	defaults, err := config.LoadDefaultsFiles(defaultsFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load defaults: %v", err)
+	}
+	settings := defaults.NodeAgent
+	if settings == nil {
+		return nil, nil
+	}
+	log.Printf("Collecting node metrics from agents in namespace %s", defaults.ComponentNamespace)
+	return &NodeAgent{
+		Namespace: defaults.ComponentNamespace,
+		Port:      settings.AgentPort(),
+	}, nil
+}
+
+// TestNodeMetrics contains the aggregates of the samples of a node taken
+// during the benchmark of a load test, along with the pods of the test that
+// ran on the node.
+type TestNodeMetrics struct {
+	// Pods lists the names of the pods of the test on the node.
+	Pods []string `json:"pods"`
+
+	*nodemetrics.NodeMetrics
+}
+
+// CollectNodeMetrics fetches the samples taken by the node agent on each node
+// that ran a pod of a load test, aggregates the samples taken during the
+// benchmark window and writes the aggregates to a file as JSON. The file is
+// placed in a directory named after the load test under a given directory.
+//
+// The benchmark window is derived from the start time of the driver and the
+// warmup and benchmark durations of the scenario, and ends early if the driver
+// stopped before its end. The path of the saved file is returned. An empty
+// path is returned if the driver did not start. An error is returned along
+// with the path of the saved file if the metrics of some nodes could not be
+// collected.
+func CollectNodeMetrics(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter, pods []*corev1.Pod, agent *NodeAgent, outputDir string) (string, error) {
+	start, stop, ok := driverRunTimes(pods)
+	if !ok {
+		return "", nil
+	}
+	warmup, benchmark, err := kubehelpers.ScenarioDurations(loadTest.Annotations, loadTest.Spec.ScenariosJSON)
+	if err != nil {
+		return "", fmt.Errorf("could not determine benchmark window: %v", err)
+	}
+	start = start.Add(warmup)
+	end := start.Add(benchmark)
+	if !stop.IsZero() && stop.Before(end) {
+		end = stop
+	}
+	if !end.After(start) {
+		return "", fmt.Errorf("driver stopped before the benchmark window")
+	}
+
+	agentList, err := podsGetter.Pods(agent.Namespace).List(ctx, metav1.ListOptions{LabelSelector: nodemetrics.AgentSelector()})
+	if err != nil {
+		return "", fmt.Errorf("failed to list node agents: %v", err)
+	}
+	agentPods := make(map[string]*corev1.Pod)
+	for i := range agentList.Items {
+		agentPod := &agentList.Items[i]
+		agentPods[agentPod.Spec.NodeName] = agentPod
+	}
+
+	podsByNode := make(map[string][]string)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod.Name)
+		}
+	}
+	var nodes []string
+	for node := range podsByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var results []*TestNodeMetrics
+	var errs []error
+	for _, node := range nodes {
+		agentPod, ok := agentPods[node]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no node agent runs on node %s", node))
+			continue
+		}
+		metrics, err := fetchNodeMetrics(ctx, podsGetter, agentPod, agent.Port, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not get metrics of node %s: %v", node, err))
+			continue
+		}
+		sort.Strings(podsByNode[node])
+		results = append(results, &TestNodeMetrics{
+			Pods:        podsByNode[node],
+			NodeMetrics: metrics,
+		})
+	}
+	if len(results) == 0 {
+		if len(errs) > 0 {
+			return "", fmt.Errorf("failed to collect metrics of %d node(s), first error: %v", len(errs), errs[0])
+		}
+		return "", nil
+	}
+
+	metricsDir := filepath.Join(outputDir, loadTest.Name)
+	if err := os.MkdirAll(metricsDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create node metrics output directory %s: %v", metricsDir, err)
+	}
+	body, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode node metrics: %v", err)
+	}
+	filePath := filepath.Join(metricsDir, NodeMetricsFileName)
+	if err := os.WriteFile(filePath, body, 0644); err != nil {
+		return "", fmt.Errorf("error writing to %s: %v", filePath, err)
+	}
+
+	if len(errs) > 0 {
+		return filePath, fmt.Errorf("failed to collect metrics of %d node(s), first error: %v", len(errs), errs[0])
+	}
+	return filePath, nil
+}
+
+// fetchNodeMetrics retrieves the samples taken by a node agent between two
+// times through the API server proxy, and aggregates them.
+func fetchNodeMetrics(ctx context.Context, podsGetter corev1types.PodsGetter, agentPod *corev1.Pod, port int32, start time.Time, end time.Time) (*nodemetrics.NodeMetrics, error) {
+	params := map[string]string{
+		nodemetrics.SinceParam: start.Format(time.RFC3339),
+		nodemetrics.UntilParam: end.Format(time.RFC3339),
+	}
+	body, err := podsGetter.Pods(agentPod.Namespace).ProxyGet("http", agentPod.Name, fmt.Sprint(port), nodemetrics.SamplesPath, params).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var samples []nodemetrics.Sample
+	if err := json.Unmarshal(body, &samples); err != nil {
+		return nil, fmt.Errorf("failed to parse samples: %v", err)
+	}
+	return nodemetrics.Aggregate(agentPod.Spec.NodeName, samples)
+}
+
+// driverRunTimes returns the time when the run container of the driver
+// started and, if it has terminated, the time when it stopped. The last value
+// is false if the driver has not started.
+func driverRunTimes(pods []*corev1.Pod) (time.Time, time.Time, bool) {
+	for _, pod := range pods {
+		if pod.Labels[config.RoleLabel] != config.DriverRole {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != config.RunContainerName {
+				continue
+			}
+			if cs.State.Running != nil {
+				return cs.State.Running.StartedAt.Time, time.Time{}, true
+			}
+			if cs.State.Terminated != nil && !cs.State.Terminated.StartedAt.IsZero() {
+				return cs.State.Terminated.StartedAt.Time, cs.State.Terminated.FinishedAt.Time, true
+			}
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// profileMargin is the time left between the end of a CPU profile and the end
+// of the benchmark window, so the profile completes before the driver stops
+// the workers.
+const profileMargin = 2 * time.Second
+
+// ProfileInfo contains information for each profile file.
+type ProfileInfo struct {
+	// PodNameElem is the element added to the LoadTest name to
+	// construct the pod name. Examples of PodNameElem are client-0
+	// and server-0.
+	PodNameElem string
+	// Kind is the kind of the profile, either cpu or heap.
+	Kind string
+	// ProfilePath is the path pointing to the profile file.
+	ProfilePath string
+	// URL is the URL of the uploaded profile file. It is empty if the
+	// profile was not uploaded.
+	URL string
+}
+
+// CollectProfiles saves pprof profiles from the worker pods of a load test
+// to files under a given directory. Only pods that expose a pprof port are
+// profiled.
+//
+// The benchmark window is derived from the start time of the driver and the
+// warmup and benchmark durations of the scenario. This function waits for the
+// warmup to end, saves a heap profile of each worker and then saves a CPU
+// profile that covers the rest of the benchmark window. Information about each
+// saved profile is returned as a pointer to a ProfileInfo object. An error is
+// returned along with the profiles that were saved if any profile could not be
+// collected.
+func CollectProfiles(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter, pods []*corev1.Pod, profileDir string) ([]*ProfileInfo, error) {
+	warmup, benchmark, err := kubehelpers.ScenarioDurations(loadTest.Annotations, loadTest.Spec.ScenariosJSON)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine benchmark window: %v", err)
+	}
+
+	start := driverStartTime(pods).Add(warmup)
+	end := start.Add(benchmark)
+
+	if err := os.MkdirAll(profileDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create profile output directory %s: %v", profileDir, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Until(start)):
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var profileInfos []*ProfileInfo
+	var errs []error
+	for _, pod := range pods {
+		port := pprofPort(pod)
+		if port == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(pod *corev1.Pod) {
+			defer wg.Done()
+
+			var infos []*ProfileInfo
+			var podErrs []error
+
+			info, err := SaveProfile(ctx, loadTest, podsGetter, pod, port, "heap", "/debug/pprof/heap", nil, profileDir)
+			if err != nil {
+				podErrs = append(podErrs, fmt.Errorf("could not get heap profile from pod %s: %v", pod.Name, err))
+			} else {
+				infos = append(infos, info)
+			}
+
+			seconds := int((time.Until(end) - profileMargin) / time.Second)
+			if seconds < 1 {
+				podErrs = append(podErrs, fmt.Errorf("could not get CPU profile from pod %s: benchmark window has ended", pod.Name))
+			} else {
+				params := map[string]string{"seconds": fmt.Sprint(seconds)}
+				info, err := SaveProfile(ctx, loadTest, podsGetter, pod, port, "cpu", "/debug/pprof/profile", params, profileDir)
+				if err != nil {
+					podErrs = append(podErrs, fmt.Errorf("could not get CPU profile from pod %s: %v", pod.Name, err))
+				} else {
+					infos = append(infos, info)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			profileInfos = append(profileInfos, infos...)
+			errs = append(errs, podErrs...)
+		}(pod)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return profileInfos, fmt.Errorf("failed to collect %d profile(s), first error: %v", len(errs), errs[0])
+	}
+	return profileInfos, nil
+}
+
+// SaveProfile retrieves a single profile from a pod through the API server
+// proxy and writes it to a file. Information about the saved profile is
+// returned as a pointer to a ProfileInfo object.
+func SaveProfile(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter, pod *corev1.Pod, port int32, kind string, path string, params map[string]string, profileDir string) (*ProfileInfo, error) {
+	filePath := filepath.Join(profileDir, ProfileFileName(pod.Name, kind))
+	if err := saveProxyGet(ctx, podsGetter, pod, port, path, params, filePath); err != nil {
+		return nil, err
+	}
+
+	profileInfo := &ProfileInfo{
+		PodNameElem: PodNameElem(pod.Name, loadTest.Name),
+		Kind:        kind,
+		ProfilePath: filePath,
+	}
+
+	return profileInfo, nil
+}
+
+// saveProxyGet retrieves the response to an HTTP GET request sent to a port
+// of a pod through the API server proxy, and writes it to a file.
+func saveProxyGet(ctx context.Context, podsGetter corev1types.PodsGetter, pod *corev1.Pod, port int32, path string, params map[string]string, filePath string) error {
+	req := podsGetter.Pods(pod.Namespace).ProxyGet("http", pod.Name, fmt.Sprint(port), path, params)
+	body, err := req.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("could not open %s for writing", filePath)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		return fmt.Errorf("error writing to %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// ProfileFileName constructs a profile file name from pod name and the kind
+// of profile.
+func ProfileFileName(podName string, kind string) string {
+	return fmt.Sprintf("%s-%s.pprof", podName, kind)
+}
+
+// pprofPort returns the number of the pprof port exposed by the run container
+// of a pod, or zero if there is no such port.
+func pprofPort(pod *corev1.Pod) int32 {
+	container := kubehelpers.ContainerForName(config.RunContainerName, pod.Spec.Containers)
+	if container == nil {
+		return 0
+	}
+	for _, port := range container.Ports {
+		if port.Name == config.PprofPortName {
+			return port.ContainerPort
+		}
+	}
+	return 0
+}
+
+// driverStartTime returns the time when the run container of the driver
+// started, which is when the driver begins the scenario. If the driver has not
+// started, the current time is returned.
+func driverStartTime(pods []*corev1.Pod) time.Time {
+	for _, pod := range pods {
+		if pod.Labels[config.RoleLabel] != config.DriverRole {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == config.RunContainerName && cs.State.Running != nil {
+				return cs.State.Running.StartedAt.Time
+			}
+		}
+	}
+	return time.Now()
+}
@@ -17,10 +17,15 @@ limitations under the License.
 package runner
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
 )
 
 // LogInfo contains infomation for each log file.
@@ -33,14 +38,22 @@ type LogInfo struct {
 	ContainerName string
 	// LogPath is the path pointing to the log file.
 	LogPath string
+	// URL is the URL of the uploaded log file. It is empty if the log was
+	// not uploaded.
+	URL string
 }
 
-// PodLogProperties creates a map of log property keys to log path urls.
+// PodLogProperties creates a map of log property keys to log urls. The URL of
+// an uploaded log is used when there is one. Otherwise, the URL is the log
+// path with the given prefix.
 func PodLogProperties(logInfos []*LogInfo, logURLPrefix string, prefix ...string) map[string]string {
 	properties := make(map[string]string)
 	for _, logInfo := range logInfos {
 		podLogPropertyKey := PodLogPropertyKey(logInfo, prefix...)
-		logURL := logURLPrefix + logInfo.LogPath
+		logURL := logInfo.URL
+		if logURL == "" {
+			logURL = logURLPrefix + logInfo.LogPath
+		}
 		properties[podLogPropertyKey] = logURL
 	}
 	return properties
@@ -52,6 +65,38 @@ func PodLogPropertyKey(logInfo *LogInfo, prefix ...string) string {
 	return key
 }
 
+// PodProfileProperties creates a map of profile property keys to profile
+// urls. The URL of an uploaded profile is used when there is one. Otherwise,
+// the URL is the profile path with the given prefix.
+func PodProfileProperties(profileInfos []*ProfileInfo, logURLPrefix string, prefix ...string) map[string]string {
+	properties := make(map[string]string)
+	for _, profileInfo := range profileInfos {
+		key := strings.Join(append(prefix, profileInfo.PodNameElem, "profile", profileInfo.Kind), ".")
+		profileURL := profileInfo.URL
+		if profileURL == "" {
+			profileURL = logURLPrefix + profileInfo.ProfilePath
+		}
+		properties[key] = profileURL
+	}
+	return properties
+}
+
+// PodEnvoyDumpProperties creates a map of Envoy dump property keys to dump
+// urls. The URL of an uploaded dump is used when there is one. Otherwise, the
+// URL is the dump path with the given prefix.
+func PodEnvoyDumpProperties(dumpInfos []*EnvoyDumpInfo, logURLPrefix string, prefix ...string) map[string]string {
+	properties := make(map[string]string)
+	for _, dumpInfo := range dumpInfos {
+		key := strings.Join(append(prefix, dumpInfo.PodNameElem, "envoy", dumpInfo.Kind), ".")
+		dumpURL := dumpInfo.URL
+		if dumpURL == "" {
+			dumpURL = logURLPrefix + dumpInfo.DumpPath
+		}
+		properties[key] = dumpURL
+	}
+	return properties
+}
+
 // PodNameProperties creates a map of pod name property keys to pod names.
 func PodNameProperties(pods []*corev1.Pod, loadTestName string, prefix ...string) map[string]string {
 	properties := make(map[string]string)
@@ -77,3 +122,139 @@ func PodNamePropertyKey(podNameElem string, prefix ...string) string {
 	key := strings.Join(append(prefix, podNameElem, "name"), ".")
 	return key
 }
+
+// scenarioParams holds the parameters of a scenario that are recorded as
+// properties. Numbers are decoded as json.Number, so they are recorded as they
+// appear in the scenario.
+type scenarioParams struct {
+	Name         string      `json:"name"`
+	NumServers   json.Number `json:"num_servers"`
+	NumClients   json.Number `json:"num_clients"`
+	ClientConfig struct {
+		ClientType                string          `json:"client_type"`
+		RPCType                   string          `json:"rpc_type"`
+		ClientChannels            json.Number     `json:"client_channels"`
+		OutstandingRPCsPerChannel json.Number     `json:"outstanding_rpcs_per_channel"`
+		SecurityParams            json.RawMessage `json:"security_params"`
+		PayloadConfig             struct {
+			BytebufParams *payloadParams `json:"bytebuf_params"`
+			SimpleParams  *payloadParams `json:"simple_params"`
+		} `json:"payload_config"`
+		LoadParams map[string]json.RawMessage `json:"load_params"`
+	} `json:"client_config"`
+	ServerConfig struct {
+		ServerType string `json:"server_type"`
+	} `json:"server_config"`
+}
+
+// payloadParams holds the request and response sizes of a payload.
+type payloadParams struct {
+	ReqSize  json.Number `json:"req_size"`
+	RespSize json.Number `json:"resp_size"`
+}
+
+// properties returns the parameters of the scenario as properties. Parameters
+// that are not set in the scenario are left out.
+func (p *scenarioParams) properties(prefix ...string) map[string]string {
+	properties := make(map[string]string)
+	add := func(name, value string) {
+		if value != "" {
+			properties[strings.Join(append(prefix, name), ".")] = value
+		}
+	}
+
+	add("name", p.Name)
+	add("num_servers", p.NumServers.String())
+	add("num_clients", p.NumClients.String())
+	add("client_type", p.ClientConfig.ClientType)
+	add("server_type", p.ServerConfig.ServerType)
+	add("rpc_type", p.ClientConfig.RPCType)
+	add("client_channels", p.ClientConfig.ClientChannels.String())
+	add("outstanding_rpcs_per_channel", p.ClientConfig.OutstandingRPCsPerChannel.String())
+
+	payload := p.ClientConfig.PayloadConfig.SimpleParams
+	if bytebuf := p.ClientConfig.PayloadConfig.BytebufParams; bytebuf != nil {
+		payload = bytebuf
+		add("payload_type", "bytebuf")
+	} else if payload != nil {
+		add("payload_type", "simple")
+	}
+	if payload != nil {
+		add("req_size", payload.ReqSize.String())
+		add("resp_size", payload.RespSize.String())
+	}
+
+	security := "insecure"
+	if len(p.ClientConfig.SecurityParams) > 0 && !bytes.Equal(p.ClientConfig.SecurityParams, []byte("null")) {
+		security = "secure"
+	}
+	add("security", security)
+
+	var loads []string
+	for load := range p.ClientConfig.LoadParams {
+		loads = append(loads, load)
+	}
+	sort.Strings(loads)
+	add("load", strings.Join(loads, ","))
+
+	return properties
+}
+
+// ScenarioProperties creates a map of property keys to the values of key
+// parameters of the scenarios of a test, such as the RPC type, payload sizes,
+// channel counts and security. The scenarios may be given as a single object
+// or as a list. When a list contains several scenarios, the index of each
+// scenario is added to the keys of its properties. Tests without scenarios,
+// such as interop tests, have no scenario properties.
+func ScenarioProperties(scenariosJSON string, prefix ...string) (map[string]string, error) {
+	if strings.TrimSpace(scenariosJSON) == "" {
+		return map[string]string{}, nil
+	}
+
+	var wrapper struct {
+		Scenarios json.RawMessage `json:"scenarios"`
+	}
+	if err := json.Unmarshal([]byte(scenariosJSON), &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse scenarios: %v", err)
+	}
+
+	var scenarios []*scenarioParams
+	if trimmed := bytes.TrimSpace(wrapper.Scenarios); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &scenarios); err != nil {
+			return nil, fmt.Errorf("failed to parse scenarios: %v", err)
+		}
+	} else if len(trimmed) > 0 {
+		scenario := new(scenarioParams)
+		if err := json.Unmarshal(trimmed, scenario); err != nil {
+			return nil, fmt.Errorf("failed to parse scenario: %v", err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+
+	properties := make(map[string]string)
+	for i, scenario := range scenarios {
+		scenarioPrefix := prefix
+		if len(scenarios) > 1 {
+			scenarioPrefix = append(append([]string{}, prefix...), fmt.Sprint(i))
+		}
+		for key, value := range scenario.properties(scenarioPrefix...) {
+			properties[key] = value
+		}
+	}
+	return properties, nil
+}
+
+// InteropCaseProperties creates a map of property keys to the outcome of each
+// test case of an interop test, which is either "passed" or "failed".
+func InteropCaseProperties(results []grpcv1.InteropCaseResult, prefix ...string) map[string]string {
+	properties := make(map[string]string)
+	for _, result := range results {
+		key := strings.Join(append(prefix, result.Name), ".")
+		if result.Passed {
+			properties[key] = "passed"
+		} else {
+			properties[key] = "failed"
+		}
+	}
+	return properties
+}
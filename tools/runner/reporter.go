@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/failure"
 	"github.com/grpc/test-infra/tools/runner/xunit"
 )
 
@@ -32,6 +34,12 @@ type Reporter struct {
 	report    *xunit.Report
 	startTime time.Time
 	endTime   time.Time
+
+	// mu guards the completed suites, which are filled by the goroutines
+	// of each suite as their test cases complete.
+	mu              sync.Mutex
+	checkpoint      func(*xunit.Report)
+	completedSuites []*xunit.TestSuite
 }
 
 // NewReporter constructs a new reporter instance.
@@ -54,6 +62,16 @@ func (r *Reporter) SetEndTime(t time.Time) {
 	r.report.TimeInSeconds = t.Sub(r.startTime).Seconds()
 }
 
+// SetCheckpointFunc sets a function that receives a partial report each time a
+// test case completes. The partial report contains the test cases that have
+// completed in every suite, so that it can be saved while tests are still
+// running. The function is never called concurrently.
+func (r *Reporter) SetCheckpointFunc(checkpoint func(*xunit.Report)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoint = checkpoint
+}
+
 // Duration returns the elapsed time between the time.Time instances passed to
 // the SetStartTime and SetEndTime methods. Ideally, these should be used at the
 // beginning and end of running all test suites to produce the wall-clock time.
@@ -69,6 +87,7 @@ func (r *Reporter) Duration() time.Duration {
 // NewTestSuiteReporter creates a new suite reporter instance.
 func (r *Reporter) NewTestSuiteReporter(qName string, logPrefixFmt string, testCaseName func(*grpcv1.LoadTest) string) *TestSuiteReporter {
 	suiteReporter := &TestSuiteReporter{
+		reporter:     r,
 		qName:        qName,
 		logPrefixFmt: logPrefixFmt,
 		testCaseName: testCaseName,
@@ -80,6 +99,14 @@ func (r *Reporter) NewTestSuiteReporter(qName string, logPrefixFmt string, testC
 		}
 		r.report.Suites = append(r.report.Suites, testSuite)
 		suiteReporter.testSuite = testSuite
+
+		completedSuite := &xunit.TestSuite{
+			Name: qName,
+		}
+		r.mu.Lock()
+		r.completedSuites = append(r.completedSuites, completedSuite)
+		r.mu.Unlock()
+		suiteReporter.completedSuite = completedSuite
 	}
 
 	return suiteReporter
@@ -87,13 +114,16 @@ func (r *Reporter) NewTestSuiteReporter(qName string, logPrefixFmt string, testC
 
 // TestSuiteReporter manages reports for tests that share a runner queue.
 type TestSuiteReporter struct {
-	testSuite    *xunit.TestSuite
-	testCount    int
-	qName        string
-	logPrefixFmt string
-	testCaseName func(*grpcv1.LoadTest) string
-	startTime    time.Time
-	endTime      time.Time
+	reporter       *Reporter
+	testSuite      *xunit.TestSuite
+	completedSuite *xunit.TestSuite
+	testCount      int
+	qName          string
+	logPrefixFmt   string
+	testCaseName   func(*grpcv1.LoadTest) string
+	caseReporters  []*TestCaseReporter
+	startTime      time.Time
+	endTime        time.Time
 }
 
 // Queue returns the name of the queue containing tests for this test suite.
@@ -139,6 +169,7 @@ func (tsr *TestSuiteReporter) NewTestCaseReporter(config *grpcv1.LoadTest) *Test
 			log.Printf(logPrefix+format, v...)
 		},
 		index: index,
+		qName: tsr.qName,
 	}
 
 	if tsr.testSuite != nil {
@@ -149,14 +180,59 @@ func (tsr *TestSuiteReporter) NewTestCaseReporter(config *grpcv1.LoadTest) *Test
 		caseReporter.testCase = testCase
 	}
 
+	tsr.caseReporters = append(tsr.caseReporters, caseReporter)
 	return caseReporter
 }
 
+// Checkpoint records that a test case of the suite has completed, and passes a
+// partial report with the test cases that have completed so far to the
+// checkpoint function of the reporter, if one is set. It must be called after
+// the end time of the test case is set, and the test case must not be changed
+// afterwards.
+func (tsr *TestSuiteReporter) Checkpoint(tcr *TestCaseReporter) {
+	if tsr.completedSuite == nil || tcr.testCase == nil {
+		return
+	}
+
+	r := tsr.reporter
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tsr.completedSuite.Cases = append(tsr.completedSuite.Cases, tcr.testCase)
+	tsr.completedSuite.TimeInSeconds = time.Since(tsr.startTime).Seconds()
+	if r.checkpoint == nil {
+		return
+	}
+
+	report := &xunit.Report{
+		Name:          r.report.Name,
+		TimeInSeconds: time.Since(r.startTime).Seconds(),
+		Suites:        r.completedSuites,
+	}
+	report.Finalize()
+	r.checkpoint(report)
+}
+
+// FailureReasons returns the reasons of the test cases in the suite that
+// failed.
+func (tsr *TestSuiteReporter) FailureReasons() []failure.Reason {
+	var reasons []failure.Reason
+	for _, caseReporter := range tsr.caseReporters {
+		if caseReporter.Failed() {
+			reasons = append(reasons, caseReporter.Reason())
+		}
+	}
+	return reasons
+}
+
 // TestCaseReporter collects events for logging and reporting during a test.
 type TestCaseReporter struct {
 	testCase  *xunit.TestCase
 	logPrintf func(format string, v ...interface{})
 	index     int
+	qName     string
+	failed    bool
+	reason    failure.Reason
 	startTime time.Time
 	endTime   time.Time
 }
@@ -166,6 +242,32 @@ func (tcr *TestCaseReporter) Index() int {
 	return tcr.index
 }
 
+// Queue returns the name of the queue containing the test case.
+func (tcr *TestCaseReporter) Queue() string {
+	return tcr.qName
+}
+
+// Failed returns true if an error has been recorded for the test case.
+func (tcr *TestCaseReporter) Failed() bool {
+	return tcr.failed
+}
+
+// Reason returns the reason why the test case failed. Unknown is returned for
+// test cases with errors but no reason, and an empty reason is returned for
+// test cases without errors.
+func (tcr *TestCaseReporter) Reason() failure.Reason {
+	if tcr.reason == "" && tcr.failed {
+		return failure.Unknown
+	}
+	return tcr.reason
+}
+
+// InfrastructureFailure returns true if the test case failed because of a
+// problem with the cluster.
+func (tcr *TestCaseReporter) InfrastructureFailure() bool {
+	return tcr.reason.Category() == failure.InfrastructureCategory
+}
+
 // Info records an informational message generated by the test.
 func (tcr *TestCaseReporter) Info(format string, v ...interface{}) {
 	tcr.logPrintf(format, v...)
@@ -181,15 +283,33 @@ func (tcr *TestCaseReporter) Warning(format string, v ...interface{}) {
 // The error that caused the message to be generated is also included.
 func (tcr *TestCaseReporter) Error(format string, v ...interface{}) {
 	tcr.logPrintf(format, v...)
+	tcr.failed = true
 
 	if tcr.testCase == nil {
 		return
 	}
 	tcr.testCase.Errors = append(tcr.testCase.Errors, &xunit.Error{
 		Message: fmt.Sprintf(format, v...),
+		Type:    tcr.xunitType(),
 	})
 }
 
+// Fail records the reason why the test failed, followed by an error message.
+// The reason determines the type of the error in the report.
+func (tcr *TestCaseReporter) Fail(reason failure.Reason, format string, v ...interface{}) {
+	tcr.reason = reason
+	tcr.Error(format, v...)
+}
+
+// xunitType returns the type of errors recorded for the test case, which is
+// empty until a reason is recorded.
+func (tcr *TestCaseReporter) xunitType() string {
+	if tcr.reason == "" {
+		return ""
+	}
+	return tcr.reason.XUnitType()
+}
+
 // SetStartTime records the start time of the test.
 func (tcr *TestCaseReporter) SetStartTime(t time.Time) {
 	tcr.startTime = t
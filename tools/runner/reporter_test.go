@@ -0,0 +1,120 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/failure"
+	"github.com/grpc/test-infra/tools/runner/xunit"
+)
+
+// newScenarioTest returns a test with the given scenario annotation, which
+// names its test case.
+func newScenarioTest(scenario string) *grpcv1.LoadTest {
+	return &grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        scenario,
+			Annotations: map[string]string{"scenario": scenario},
+		},
+	}
+}
+
+// caseNames returns the names of the test cases of each suite of a report.
+func caseNames(report *xunit.Report) map[string][]string {
+	names := make(map[string][]string)
+	for _, suite := range report.Suites {
+		names[suite.Name] = []string{}
+		for _, testCase := range suite.Cases {
+			names[suite.Name] = append(names[suite.Name], testCase.Name)
+		}
+	}
+	return names
+}
+
+var _ = ginkgo.Describe("TestSuiteReporter", func() {
+	ginkgo.Describe("Checkpoint", func() {
+		var report *xunit.Report
+		var reporter *Reporter
+		var checkpoints []map[string][]string
+		var lastCheckpoint *xunit.Report
+
+		ginkgo.BeforeEach(func() {
+			report = &xunit.Report{Name: "run"}
+			reporter = NewReporter(report)
+			checkpoints = nil
+			reporter.SetCheckpointFunc(func(checkpoint *xunit.Report) {
+				checkpoints = append(checkpoints, caseNames(checkpoint))
+				lastCheckpoint = checkpoint
+			})
+		})
+
+		ginkgo.It("passes a report with only the completed cases of every suite", func() {
+			suiteA := reporter.NewTestSuiteReporter("a", "", TestCaseNameFromAnnotations("scenario"))
+			suiteB := reporter.NewTestSuiteReporter("b", "", TestCaseNameFromAnnotations("scenario"))
+			a1 := suiteA.NewTestCaseReporter(newScenarioTest("a1"))
+			a2 := suiteA.NewTestCaseReporter(newScenarioTest("a2"))
+			b1 := suiteB.NewTestCaseReporter(newScenarioTest("b1"))
+
+			suiteA.Checkpoint(a2)
+			b1.Fail(failure.Timeout, "timed out")
+			suiteB.Checkpoint(b1)
+			suiteA.Checkpoint(a1)
+
+			Expect(checkpoints).To(Equal([]map[string][]string{
+				{"a": {"a2"}, "b": {}},
+				{"a": {"a2"}, "b": {"b1"}},
+				{"a": {"a2", "a1"}, "b": {"b1"}},
+			}))
+			Expect(lastCheckpoint.Name).To(Equal("run"))
+			Expect(lastCheckpoint.TestCount).To(Equal(3))
+			Expect(lastCheckpoint.ErrorCount).To(Equal(1))
+		})
+
+		ginkgo.It("passes a last report with the same cases as the final report", func() {
+			suiteA := reporter.NewTestSuiteReporter("a", "", TestCaseNameFromAnnotations("scenario"))
+			suiteB := reporter.NewTestSuiteReporter("b", "", TestCaseNameFromAnnotations("scenario"))
+			a1 := suiteA.NewTestCaseReporter(newScenarioTest("a1"))
+			b1 := suiteB.NewTestCaseReporter(newScenarioTest("b1"))
+			a1.Fail(failure.Timeout, "timed out")
+			suiteA.Checkpoint(a1)
+			suiteB.Checkpoint(b1)
+
+			report.Finalize()
+			Expect(lastCheckpoint.Suites).To(HaveLen(len(report.Suites)))
+			for i, suite := range report.Suites {
+				Expect(lastCheckpoint.Suites[i].Name).To(Equal(suite.Name))
+				Expect(lastCheckpoint.Suites[i].Cases).To(ConsistOf(suite.Cases))
+				Expect(lastCheckpoint.Suites[i].ErrorCount).To(Equal(suite.ErrorCount))
+			}
+			Expect(lastCheckpoint.TestCount).To(Equal(report.TestCount))
+			Expect(lastCheckpoint.ErrorCount).To(Equal(report.ErrorCount))
+		})
+
+		ginkgo.It("does nothing without a report", func() {
+			reporter := NewReporter(nil)
+			reporter.SetCheckpointFunc(func(*xunit.Report) {
+				ginkgo.Fail("unexpected checkpoint")
+			})
+			suite := reporter.NewTestSuiteReporter("a", "", TestCaseNameFromAnnotations("scenario"))
+			suite.Checkpoint(suite.NewTestCaseReporter(newScenarioTest("a1")))
+		})
+	})
+})
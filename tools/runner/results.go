@@ -0,0 +1,127 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+)
+
+const (
+	// ScenarioResultBeginMarker is the line that the driver prints to its log
+	// before the scenario result JSON. See containers/runtime/driver/run.sh.
+	ScenarioResultBeginMarker = "==== BEGIN SCENARIO RESULT ===="
+
+	// ScenarioResultEndMarker is the line that the driver prints to its log
+	// after the scenario result JSON.
+	ScenarioResultEndMarker = "==== END SCENARIO RESULT ===="
+
+	// ScenarioResultFileName is the name of the file that the scenario result
+	// is saved to, under the directory named after the test.
+	ScenarioResultFileName = "scenario_result.json"
+)
+
+// SaveScenarioResult retrieves the scenario result printed by the driver of a
+// load test and writes it to a file. The file is placed in a directory named
+// after the load test under a given directory. The path of the saved file is
+// returned.
+func SaveScenarioResult(ctx context.Context, loadTest *grpcv1.LoadTest, podsGetter corev1types.PodsGetter, pods []*corev1.Pod, outputDir string) (string, error) {
+	driver := driverPod(pods)
+	if driver == nil {
+		return "", fmt.Errorf("could not find driver pod")
+	}
+
+	req := podsGetter.Pods(driver.Namespace).GetLogs(driver.Name, &corev1.PodLogOptions{Container: config.RunContainerName})
+	driverLogs, err := req.Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer driverLogs.Close()
+
+	logBuffer := new(bytes.Buffer)
+	if _, err := logBuffer.ReadFrom(driverLogs); err != nil {
+		return "", fmt.Errorf("could not read log of pod %s: %v", driver.Name, err)
+	}
+
+	result, err := ExtractScenarioResult(logBuffer.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	resultDir := filepath.Join(outputDir, loadTest.Name)
+	if err := os.MkdirAll(resultDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create result output directory %s: %v", resultDir, err)
+	}
+
+	filePath := filepath.Join(resultDir, ScenarioResultFileName)
+	if err := os.WriteFile(filePath, result, 0644); err != nil {
+		return "", fmt.Errorf("error writing to %s: %v", filePath, err)
+	}
+	return filePath, nil
+}
+
+// ExtractScenarioResult returns the scenario result JSON found between the
+// begin and end markers in a driver log. An error is returned if the markers
+// are missing or the result is not valid JSON.
+func ExtractScenarioResult(driverLog []byte) ([]byte, error) {
+	var lines []string
+	var inResult, found bool
+
+	for _, line := range strings.Split(string(driverLog), "\n") {
+		switch {
+		case strings.TrimSpace(line) == ScenarioResultBeginMarker:
+			inResult = true
+			lines = nil
+		case strings.TrimSpace(line) == ScenarioResultEndMarker && inResult:
+			inResult = false
+			found = true
+		case inResult:
+			lines = append(lines, line)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("driver log does not contain a scenario result")
+	}
+
+	result := []byte(strings.Join(lines, "\n"))
+	if !json.Valid(result) {
+		return nil, fmt.Errorf("scenario result in driver log is not valid JSON")
+	}
+	return result, nil
+}
+
+// driverPod returns the driver pod from a list of pods, or nil if the list
+// does not contain a driver.
+func driverPod(pods []*corev1.Pod) *corev1.Pod {
+	for _, pod := range pods {
+		if pod.Labels[config.RoleLabel] == config.DriverRole {
+			return pod
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runIndexPattern matches the names of the index files of runs.
+const runIndexPattern = "run-*.json"
+
+// Retention limits the number of runs whose artifacts are kept in an output
+// directory.
+type Retention struct {
+	// MaxRuns is the number of most recent runs that are kept. Runs are not
+	// removed based on their number when it is zero.
+	MaxRuns int
+
+	// MaxAge is the age after which runs are removed. Runs are not removed
+	// based on their age when it is zero.
+	MaxAge time.Duration
+}
+
+// RunIndex lists the artifacts that a run saved in an output directory.
+type RunIndex struct {
+	// Queue is the name of the queue of the run.
+	Queue string `json:"queue"`
+
+	// Time is the time at which the run started.
+	Time time.Time `json:"time"`
+
+	// Archive is the name of the compressed archive that contains the
+	// artifacts, relative to the output directory. The artifacts are kept
+	// in the directory of each test when it is empty.
+	Archive string `json:"archive,omitempty"`
+
+	// Tests lists the artifacts of each test.
+	Tests []RunIndexTest `json:"tests"`
+}
+
+// RunIndexTest lists the artifacts of a test.
+type RunIndexTest struct {
+	// Name is the name of the test, which is also the name of the directory
+	// that contains its artifacts.
+	Name string `json:"name"`
+
+	// Files lists the artifacts of the test.
+	Files []RunIndexFile `json:"files"`
+}
+
+// RunIndexFile describes an artifact.
+type RunIndexFile struct {
+	// Path is the path of the file, relative to the output directory.
+	Path string `json:"path"`
+
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+// RecordRun writes the index of the artifacts that a run saved in the
+// directories of its tests, and returns the path of the index. When archive
+// is true, the directories are compressed into a single archive next to the
+// index and removed. The index and archive are named after the start time of
+// the run, so that later runs can rotate them.
+func RecordRun(outputDir string, queue string, testNames []string, start time.Time, archive bool) (string, error) {
+	base := "run-" + start.UTC().Format("20060102T150405Z")
+	index := &RunIndex{
+		Queue: queue,
+		Time:  start,
+	}
+
+	for _, name := range testNames {
+		testDir := filepath.Join(outputDir, name)
+		if info, err := os.Stat(testDir); err != nil || !info.IsDir() {
+			continue
+		}
+		test := RunIndexTest{Name: name}
+		err := filepath.WalkDir(testDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(outputDir, path)
+			if err != nil {
+				return err
+			}
+			test.Files = append(test.Files, RunIndexFile{Path: filepath.ToSlash(relPath), Size: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list artifacts of test %s: %v", name, err)
+		}
+		index.Tests = append(index.Tests, test)
+	}
+
+	if archive && len(index.Tests) > 0 {
+		index.Archive = base + ".tar.gz"
+		if err := writeArchive(filepath.Join(outputDir, index.Archive), outputDir, index); err != nil {
+			return "", err
+		}
+		for _, test := range index.Tests {
+			if err := os.RemoveAll(filepath.Join(outputDir, test.Name)); err != nil {
+				return "", fmt.Errorf("failed to remove archived directory of test %s: %v", test.Name, err)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode run index: %v", err)
+	}
+	indexPath := filepath.Join(outputDir, base+".json")
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write run index: %v", err)
+	}
+	return indexPath, nil
+}
+
+// writeArchive writes the files listed in an index, and the index itself as
+// index.json, to a gzip compressed tar archive.
+func writeArchive(archivePath string, outputDir string, index *RunIndex) (err error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %v", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close archive: %v", closeErr)
+		}
+		if err != nil {
+			os.Remove(archivePath)
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run index: %v", err)
+	}
+	header := &tar.Header{
+		Name:    "index.json",
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: index.Time,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write index to archive: %v", err)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return fmt.Errorf("failed to write index to archive: %v", err)
+	}
+
+	for _, test := range index.Tests {
+		for _, indexFile := range test.Files {
+			if err := addToArchive(tarWriter, outputDir, indexFile.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %v", err)
+	}
+	return nil
+}
+
+// addToArchive copies a file, given by its path relative to the output
+// directory, to an archive.
+func addToArchive(tarWriter *tar.Writer, outputDir string, relPath string) error {
+	file, err := os.Open(filepath.Join(outputDir, filepath.FromSlash(relPath)))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", relPath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", relPath, err)
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %v", relPath, err)
+	}
+	header.Name = relPath
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to archive %s: %v", relPath, err)
+	}
+	if _, err := io.Copy(tarWriter, file); err != nil {
+		return fmt.Errorf("failed to archive %s: %v", relPath, err)
+	}
+	return nil
+}
+
+// RotateRuns removes the artifacts of the runs recorded in an output
+// directory that exceed the retention, and returns the index files of the
+// removed runs. Only runs recorded by RecordRun are removed. The directory of
+// a test is kept when a retained run recorded a test with the same name, since
+// the retained run overwrote it.
+func RotateRuns(outputDir string, retention *Retention, now time.Time) ([]string, error) {
+	if retention == nil || (retention.MaxRuns <= 0 && retention.MaxAge <= 0) {
+		return nil, nil
+	}
+
+	indexPaths, err := filepath.Glob(filepath.Join(outputDir, runIndexPattern))
+	if err != nil {
+		return nil, err
+	}
+	type run struct {
+		path  string
+		index RunIndex
+	}
+	var runs []run
+	for _, indexPath := range indexPaths {
+		data, err := os.ReadFile(indexPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run index: %v", err)
+		}
+		r := run{path: indexPath}
+		if err := json.Unmarshal(data, &r.index); err != nil {
+			return nil, fmt.Errorf("failed to parse run index %s: %v", indexPath, err)
+		}
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].index.Time.After(runs[j].index.Time)
+	})
+
+	var expired []run
+	retainedTests := make(map[string]bool)
+	for i, r := range runs {
+		tooMany := retention.MaxRuns > 0 && i >= retention.MaxRuns
+		tooOld := retention.MaxAge > 0 && now.Sub(r.index.Time) > retention.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, r)
+			continue
+		}
+		if r.index.Archive == "" {
+			for _, test := range r.index.Tests {
+				retainedTests[test.Name] = true
+			}
+		}
+	}
+
+	var removed []string
+	for _, r := range expired {
+		if r.index.Archive != "" {
+			if !isPlainName(r.index.Archive) {
+				return removed, fmt.Errorf("invalid archive name %q in run index %s", r.index.Archive, r.path)
+			}
+			if err := os.Remove(filepath.Join(outputDir, r.index.Archive)); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove archive of run: %v", err)
+			}
+		} else {
+			for _, test := range r.index.Tests {
+				if retainedTests[test.Name] || !isPlainName(test.Name) {
+					continue
+				}
+				if err := os.RemoveAll(filepath.Join(outputDir, test.Name)); err != nil {
+					return removed, fmt.Errorf("failed to remove directory of test %s: %v", test.Name, err)
+				}
+			}
+		}
+		if err := os.Remove(r.path); err != nil {
+			return removed, fmt.Errorf("failed to remove run index: %v", err)
+		}
+		removed = append(removed, r.path)
+	}
+	return removed, nil
+}
+
+// isPlainName reports whether a name recorded in a run index names an entry
+// of the output directory itself, so that rotation cannot remove anything
+// outside of it.
+func isPlainName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
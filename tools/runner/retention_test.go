@@ -0,0 +1,198 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Retention", func() {
+	var rootDir string
+	var outputDir string
+	var start time.Time
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		rootDir, err = os.MkdirTemp("", "retention")
+		Expect(err).ToNot(HaveOccurred())
+		outputDir = filepath.Join(rootDir, "output")
+		Expect(os.Mkdir(outputDir, 0755)).To(Succeed())
+		start = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(rootDir)
+	})
+
+	// writeArtifact writes a file to the directory of a test.
+	writeArtifact := func(testName string, fileName string, contents string) {
+		Expect(os.MkdirAll(filepath.Join(outputDir, testName), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(outputDir, testName, fileName), []byte(contents), 0644)).To(Succeed())
+	}
+
+	// recordRun writes an artifact for each test and records a run that
+	// started the given number of hours after the start time.
+	recordRun := func(hours int, archive bool, testNames ...string) string {
+		for _, name := range testNames {
+			writeArtifact(name, "results.json", name)
+		}
+		indexPath, err := RecordRun(outputDir, "queue", testNames, start.Add(time.Duration(hours)*time.Hour), archive)
+		Expect(err).ToNot(HaveOccurred())
+		return indexPath
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(outputDir, name))
+		return err == nil
+	}
+
+	ginkgo.Describe("RecordRun", func() {
+		ginkgo.It("lists the artifacts of the tests of a run", func() {
+			writeArtifact("a", "results.json", "{}")
+			writeArtifact("a", "client.log", "log")
+
+			indexPath, err := RecordRun(outputDir, "queue", []string{"a", "missing"}, start, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indexPath).To(Equal(filepath.Join(outputDir, "run-20220601T120000Z.json")))
+
+			data, err := os.ReadFile(indexPath)
+			Expect(err).ToNot(HaveOccurred())
+			var index RunIndex
+			Expect(json.Unmarshal(data, &index)).To(Succeed())
+			Expect(index.Queue).To(Equal("queue"))
+			Expect(index.Time.Equal(start)).To(BeTrue())
+			Expect(index.Archive).To(BeEmpty())
+			Expect(index.Tests).To(Equal([]RunIndexTest{
+				{
+					Name: "a",
+					Files: []RunIndexFile{
+						{Path: "a/client.log", Size: 3},
+						{Path: "a/results.json", Size: 2},
+					},
+				},
+			}))
+			Expect(exists("a")).To(BeTrue())
+		})
+
+		ginkgo.It("archives the artifacts of the tests of a run", func() {
+			indexPath := recordRun(0, true, "a", "b")
+
+			data, err := os.ReadFile(indexPath)
+			Expect(err).ToNot(HaveOccurred())
+			var index RunIndex
+			Expect(json.Unmarshal(data, &index)).To(Succeed())
+			Expect(index.Archive).To(Equal("run-20220601T120000Z.tar.gz"))
+			Expect(index.Tests).To(HaveLen(2))
+			Expect(exists(index.Archive)).To(BeTrue())
+			Expect(exists("a")).To(BeFalse())
+			Expect(exists("b")).To(BeFalse())
+		})
+	})
+
+	ginkgo.Describe("RotateRuns", func() {
+		ginkgo.It("removes nothing without a retention", func() {
+			indexPath := recordRun(0, false, "a")
+
+			removed, err := RotateRuns(outputDir, nil, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(BeEmpty())
+			Expect(indexPath).To(BeAnExistingFile())
+		})
+
+		ginkgo.It("removes the oldest runs beyond the maximum number of runs", func() {
+			third := recordRun(2, true, "c")
+			first := recordRun(0, true, "a")
+			second := recordRun(1, true, "b")
+
+			removed, err := RotateRuns(outputDir, &Retention{MaxRuns: 2}, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{first}))
+			Expect(first).ToNot(BeAnExistingFile())
+			Expect(exists("run-20220601T120000Z.tar.gz")).To(BeFalse())
+			Expect(second).To(BeAnExistingFile())
+			Expect(third).To(BeAnExistingFile())
+
+			removed, err = RotateRuns(outputDir, &Retention{MaxRuns: 1}, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{second}))
+			Expect(third).To(BeAnExistingFile())
+		})
+
+		ginkgo.It("orders runs by the time in their index", func() {
+			older := recordRun(0, false, "a")
+			newer := recordRun(1, false, "b")
+
+			// Swap the indexes, so that the file names no longer match
+			// the times of the runs.
+			olderData, err := os.ReadFile(older)
+			Expect(err).ToNot(HaveOccurred())
+			newerData, err := os.ReadFile(newer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(older, newerData, 0644)).To(Succeed())
+			Expect(os.WriteFile(newer, olderData, 0644)).To(Succeed())
+
+			removed, err := RotateRuns(outputDir, &Retention{MaxRuns: 1}, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{newer}))
+			Expect(exists("a")).To(BeFalse())
+			Expect(exists("b")).To(BeTrue())
+		})
+
+		ginkgo.It("removes runs older than the maximum age", func() {
+			old := recordRun(0, false, "a")
+			recent := recordRun(10, false, "b")
+
+			removed, err := RotateRuns(outputDir, &Retention{MaxAge: 5 * time.Hour}, start.Add(12*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{old}))
+			Expect(exists("a")).To(BeFalse())
+			Expect(recent).To(BeAnExistingFile())
+			Expect(exists("b")).To(BeTrue())
+		})
+
+		ginkgo.It("keeps the directories of tests recorded by retained runs", func() {
+			recordRun(0, false, "a", "b")
+			recordRun(1, false, "a")
+
+			_, err := RotateRuns(outputDir, &Retention{MaxRuns: 1}, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists("a")).To(BeTrue())
+			Expect(exists("b")).To(BeFalse())
+		})
+
+		ginkgo.It("does not remove files outside of the output directory", func() {
+			indexPath := recordRun(0, false, "a")
+			recordRun(1, false, "b")
+
+			index := RunIndex{Time: start, Tests: []RunIndexTest{{Name: "../outside"}}}
+			data, err := json.Marshal(index)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(indexPath, data, 0644)).To(Succeed())
+			writeArtifact("../outside", "file", "")
+
+			_, err = RotateRuns(outputDir, &Retention{MaxRuns: 1}, start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists("../outside")).To(BeTrue())
+		})
+	})
+})
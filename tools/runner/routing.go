@@ -0,0 +1,175 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/kubehelpers"
+)
+
+// RoutingRule assigns the tests that match all of its conditions to a queue.
+// Conditions that are not set match every test.
+type RoutingRule struct {
+	// Queue is the name of the queue that matching tests are assigned to.
+	Queue string `json:"queue"`
+
+	// Language matches tests with a client or server in this language, or
+	// tests whose language label has this value.
+	Language string `json:"language,omitempty"`
+
+	// Scenario is a regular expression that must match the whole name of
+	// the scenario of the test. The name is taken from the scenario
+	// annotation, or from the scenarios JSON when the annotation is not set.
+	Scenario string `json:"scenario,omitempty"`
+
+	// MinClients is the minimum number of clients of matching tests.
+	MinClients int `json:"minClients,omitempty"`
+
+	// MaxClients is the maximum number of clients of matching tests. Zero
+	// means there is no maximum.
+	MaxClients int `json:"maxClients,omitempty"`
+
+	scenarioRegexp *regexp.Regexp
+}
+
+// RoutingRules assigns tests to queues based on their contents.
+type RoutingRules struct {
+	// Rules lists the routing rules. Each test is assigned to the queue of
+	// the first rule that matches it.
+	Rules []*RoutingRule `json:"rules"`
+
+	// DefaultQueue is the queue of tests that match no rule.
+	DefaultQueue string `json:"defaultQueue,omitempty"`
+}
+
+// LoadRoutingRules reads routing rules from a YAML file.
+func LoadRoutingRules(fileName string) (*RoutingRules, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rules := new(RoutingRules)
+	if err := yaml.UnmarshalStrict(data, rules); err != nil {
+		return nil, fmt.Errorf("error decoding routing rules from %q: %v", fileName, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("invalid routing rules in %q: %v", fileName, err)
+	}
+	return rules, nil
+}
+
+// compile validates the rules and compiles their scenario expressions.
+func (rr *RoutingRules) compile() error {
+	for i, rule := range rr.Rules {
+		if rule.Queue == "" {
+			return fmt.Errorf("rule %d: missing queue", i)
+		}
+		if rule.MinClients < 0 || rule.MaxClients < 0 {
+			return fmt.Errorf("rule %d: client counts must not be negative", i)
+		}
+		if rule.MaxClients > 0 && rule.MaxClients < rule.MinClients {
+			return fmt.Errorf("rule %d: maxClients (%d) is less than minClients (%d)", i, rule.MaxClients, rule.MinClients)
+		}
+		if rule.Scenario != "" {
+			re, err := regexp.Compile("^(?:" + rule.Scenario + ")$")
+			if err != nil {
+				return fmt.Errorf("rule %d: invalid scenario expression: %v", i, err)
+			}
+			rule.scenarioRegexp = re
+		}
+	}
+	return nil
+}
+
+// Matches checks if a test meets all the conditions of the rule.
+func (rule *RoutingRule) Matches(config *grpcv1.LoadTest) bool {
+	if rule.Language != "" && !hasLanguage(config, rule.Language) {
+		return false
+	}
+	if rule.scenarioRegexp != nil && !rule.scenarioRegexp.MatchString(scenarioName(config)) {
+		return false
+	}
+	clients := kubehelpers.ClientCount(config.Spec.Clients)
+	if clients < rule.MinClients {
+		return false
+	}
+	if rule.MaxClients > 0 && clients > rule.MaxClients {
+		return false
+	}
+	return true
+}
+
+// QueueSelectorFromRules sets up queue selection from routing rules.
+// Tests that set the annotation with the given key are assigned to the queue
+// named by the annotation, so rules can be overridden for individual tests.
+// Other tests are assigned to the queue of the first matching rule, or to the
+// default queue if no rule matches.
+func QueueSelectorFromRules(rules *RoutingRules, key string) QueueSelectorFunction {
+	return func(config *grpcv1.LoadTest) string {
+		if qName := config.Annotations[key]; qName != "" {
+			return qName
+		}
+		for _, rule := range rules.Rules {
+			if rule.Matches(config) {
+				return rule.Queue
+			}
+		}
+		return rules.DefaultQueue
+	}
+}
+
+// hasLanguage checks if a test has the given language, either in its language
+// label or in the spec of one of its clients or servers.
+func hasLanguage(config *grpcv1.LoadTest, language string) bool {
+	if config.Labels["language"] == language {
+		return true
+	}
+	for _, client := range config.Spec.Clients {
+		if client.Language == language {
+			return true
+		}
+	}
+	for _, server := range config.Spec.Servers {
+		if server.Language == language {
+			return true
+		}
+	}
+	return false
+}
+
+// scenarioName returns the name of the scenario of a test.
+func scenarioName(config *grpcv1.LoadTest) string {
+	if name := config.Annotations["scenario"]; name != "" {
+		return name
+	}
+	var scenarios struct {
+		Scenarios struct {
+			Name string `json:"name"`
+		} `json:"scenarios"`
+	}
+	if err := json.Unmarshal([]byte(config.Spec.ScenariosJSON), &scenarios); err != nil {
+		return ""
+	}
+	return scenarios.Scenarios.Name
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/optional"
+)
+
+// newRoutedTest returns a test with clients and a server in the given
+// language, and a scenario with the given name.
+func newRoutedTest(language string, scenario string, clients int32) *grpcv1.LoadTest {
+	return &grpcv1.LoadTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{},
+		},
+		Spec: grpcv1.LoadTestSpec{
+			Clients: []grpcv1.Client{
+				{Language: language, Replicas: optional.Int32Ptr(clients)},
+			},
+			Servers: []grpcv1.Server{
+				{Language: language},
+			},
+			ScenariosJSON: `{"scenarios": {"name": "` + scenario + `"}}`,
+		},
+	}
+}
+
+var _ = ginkgo.Describe("RoutingRule", func() {
+	ginkgo.Describe("Matches", func() {
+		ginkgo.It("matches every test when no condition is set", func() {
+			rules := &RoutingRules{Rules: []*RoutingRule{{Queue: "all"}}}
+			Expect(rules.compile()).To(Succeed())
+
+			Expect(rules.Rules[0].Matches(newRoutedTest("go", "go_generic", 1))).To(BeTrue())
+		})
+
+		ginkgo.It("matches the language of clients, servers and the language label", func() {
+			rule := &RoutingRule{Queue: "java", Language: "java"}
+
+			Expect(rule.Matches(newRoutedTest("java", "java_generic", 1))).To(BeTrue())
+			Expect(rule.Matches(newRoutedTest("go", "go_generic", 1))).To(BeFalse())
+
+			test := newRoutedTest("go", "go_generic", 1)
+			test.Spec.Servers[0].Language = "java"
+			Expect(rule.Matches(test)).To(BeTrue())
+
+			test = newRoutedTest("go", "go_generic", 1)
+			test.Labels = map[string]string{"language": "java"}
+			Expect(rule.Matches(test)).To(BeTrue())
+		})
+
+		ginkgo.It("matches the whole scenario name", func() {
+			rules := &RoutingRules{Rules: []*RoutingRule{{Queue: "streaming", Scenario: "go_.*_streaming"}}}
+			Expect(rules.compile()).To(Succeed())
+			rule := rules.Rules[0]
+
+			Expect(rule.Matches(newRoutedTest("go", "go_protobuf_streaming", 1))).To(BeTrue())
+			Expect(rule.Matches(newRoutedTest("go", "go_protobuf_streaming_ping_pong", 1))).To(BeFalse())
+			Expect(rule.Matches(newRoutedTest("go", "cxx_go_protobuf_streaming", 1))).To(BeFalse())
+		})
+
+		ginkgo.It("prefers the scenario annotation to the scenarios JSON", func() {
+			rules := &RoutingRules{Rules: []*RoutingRule{{Queue: "unary", Scenario: ".*_unary"}}}
+			Expect(rules.compile()).To(Succeed())
+
+			test := newRoutedTest("go", "go_protobuf_streaming", 1)
+			test.Annotations["scenario"] = "go_protobuf_unary"
+			Expect(rules.Rules[0].Matches(test)).To(BeTrue())
+		})
+
+		ginkgo.It("matches the number of clients including replicas", func() {
+			rule := &RoutingRule{Queue: "large", MinClients: 2, MaxClients: 4}
+
+			Expect(rule.Matches(newRoutedTest("go", "go_generic", 1))).To(BeFalse())
+			Expect(rule.Matches(newRoutedTest("go", "go_generic", 2))).To(BeTrue())
+			Expect(rule.Matches(newRoutedTest("go", "go_generic", 4))).To(BeTrue())
+			Expect(rule.Matches(newRoutedTest("go", "go_generic", 5))).To(BeFalse())
+		})
+	})
+})
+
+var _ = ginkgo.Describe("QueueSelectorFromRules", func() {
+	var rules *RoutingRules
+
+	ginkgo.BeforeEach(func() {
+		rules = &RoutingRules{
+			Rules: []*RoutingRule{
+				{Queue: "java", Language: "java"},
+				{Queue: "large", MinClients: 2},
+			},
+			DefaultQueue: "default",
+		}
+		Expect(rules.compile()).To(Succeed())
+	})
+
+	ginkgo.It("assigns tests to the queue of the first matching rule", func() {
+		selector := QueueSelectorFromRules(rules, "queue")
+
+		Expect(selector(newRoutedTest("java", "java_generic", 4))).To(Equal("java"))
+		Expect(selector(newRoutedTest("go", "go_generic", 4))).To(Equal("large"))
+	})
+
+	ginkgo.It("assigns tests that match no rule to the default queue", func() {
+		selector := QueueSelectorFromRules(rules, "queue")
+
+		Expect(selector(newRoutedTest("go", "go_generic", 1))).To(Equal("default"))
+	})
+
+	ginkgo.It("assigns tests to the queue named by their annotation", func() {
+		selector := QueueSelectorFromRules(rules, "queue")
+
+		test := newRoutedTest("java", "java_generic", 1)
+		test.Annotations["queue"] = "override"
+		Expect(selector(test)).To(Equal("override"))
+	})
+})
+
+var _ = ginkgo.Describe("LoadRoutingRules", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "routing")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeRules := func(contents string) string {
+		fileName := filepath.Join(dir, "rules.yaml")
+		Expect(os.WriteFile(fileName, []byte(contents), 0644)).To(Succeed())
+		return fileName
+	}
+
+	ginkgo.It("loads and compiles rules", func() {
+		fileName := writeRules(`
+rules:
+- queue: streaming
+  scenario: .*_streaming
+  maxClients: 2
+defaultQueue: default
+`)
+		rules, err := LoadRoutingRules(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules.DefaultQueue).To(Equal("default"))
+		Expect(rules.Rules).To(HaveLen(1))
+		Expect(rules.Rules[0].Matches(newRoutedTest("go", "go_streaming", 1))).To(BeTrue())
+		Expect(rules.Rules[0].Matches(newRoutedTest("go", "go_unary", 1))).To(BeFalse())
+	})
+
+	ginkgo.It("rejects unknown fields", func() {
+		_, err := LoadRoutingRules(writeRules("rules:\n- queue: q\n  lang: go\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	ginkgo.It("rejects invalid rules", func() {
+		for _, contents := range []string{
+			"rules:\n- language: go\n",
+			"rules:\n- queue: q\n  minClients: -1\n",
+			"rules:\n- queue: q\n  minClients: 4\n  maxClients: 2\n",
+			"rules:\n- queue: q\n  scenario: \"(\"\n",
+		} {
+			_, err := LoadRoutingRules(writeRules(contents))
+			Expect(err).To(HaveOccurred(), contents)
+		}
+	})
+})
@@ -0,0 +1,612 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/failure"
+	"github.com/grpc/test-infra/storage"
+	"github.com/grpc/test-infra/tools/runner/xunit"
+)
+
+// Options contains the settings for a run of load tests.
+type Options struct {
+	// FileNames lists the files containing load test configurations.
+	FileNames []string
+
+	// Namespace is the namespace that tests are created in. Configurations
+	// that declare another namespace are rejected.
+	Namespace string
+
+	// NamespacePerRun causes tests to be created in a namespace with a
+	// generated name, which is created at the start of the run and deleted
+	// with everything in it at the end, instead of the Namespace.
+	NamespacePerRun bool
+
+	// ShardScenarios causes configurations that list several scenarios to
+	// be split into one test per scenario.
+	ShardScenarios bool
+
+	// OutputFile is the name of the output file for the xunit XML report.
+	// No report is written when it is empty.
+	OutputFile string
+
+	// JSONOutputFile is the name of the output file for a JSON report,
+	// which contains the test suites of every queue with the same structure
+	// as the XML report. No JSON report is written when it is empty.
+	JSONOutputFile string
+
+	// ConcurrencyLevels maps queue names to the number of tests that may
+	// run concurrently in each queue.
+	ConcurrencyLevels ConcurrencyLevels
+
+	// AnnotationKey is the annotation used to assign tests to queues.
+	AnnotationKey string
+
+	// RoutingRulesFile is the name of a file containing rules that assign
+	// tests to queues. Tests that set the queue annotation are assigned to
+	// the annotated queue instead. Rules are not used when it is empty.
+	RoutingRulesFile string
+
+	// PollingInterval is the interval between load test status checks.
+	PollingInterval time.Duration
+
+	// PollingRetries is the maximum number of retries in case of
+	// communication failure.
+	PollingRetries uint
+
+	// APIQPS is the rate of requests per second that the runner sends to
+	// the Kubernetes API, shared by all of its clients.
+	APIQPS float64
+
+	// APIBurst is the number of requests that the runner may send to the
+	// Kubernetes API at once, above the rate set by APIQPS.
+	APIBurst int
+
+	// DeleteSuccessfulTests causes tests to be deleted as soon as they
+	// terminate successfully.
+	DeleteSuccessfulTests bool
+
+	// LogURLPrefix is the prefix used for log URLs in the report.
+	LogURLPrefix string
+
+	// DashboardURLTemplate is a template for a link to the dashboard that
+	// graphs the results of each test, which is logged and added to the
+	// report when the test terminates. The dashboardURLTemplate of the
+	// defaults is used when it is empty.
+	DashboardURLTemplate string
+
+	// DefaultsFiles lists the files containing the defaults of the
+	// controller. When the defaults configure artifacts, the logs, results
+	// and profiles of tests are uploaded to the configured bucket and the
+	// report links to the uploaded files. When the defaults configure a node
+	// agent, the system metrics of the nodes of each test are collected.
+	// Artifacts are only saved locally and node metrics are not collected
+	// when it is empty.
+	DefaultsFiles []string
+
+	// PushgatewayURL is the URL of a Prometheus pushgateway that receives
+	// metrics. Metrics are not pushed when it is empty.
+	PushgatewayURL string
+
+	// PushgatewayJob is the job name used to group metrics in the
+	// pushgateway.
+	PushgatewayJob string
+
+	// PushInterval is the interval between pushes of metrics.
+	PushInterval time.Duration
+
+	// WarmupSeconds overrides the warmup duration of each scenario when it
+	// is not negative.
+	WarmupSeconds int
+
+	// BenchmarkSeconds overrides the benchmark duration of each scenario
+	// when it is not negative.
+	BenchmarkSeconds int
+
+	// CollectProfiles causes CPU and heap profiles to be collected from
+	// workers that expose a pprof port during the benchmark window. The
+	// profiles are saved next to the pod logs.
+	CollectProfiles bool
+
+	// AdaptiveConcurrency causes the concurrency level of a queue to be
+	// halved after consecutive infrastructure failures, and increased again
+	// after consecutive tests without infrastructure failures.
+	AdaptiveConcurrency bool
+
+	// AdaptiveFailureThreshold is the number of consecutive infrastructure
+	// failures that cause the concurrency level of a queue to be reduced.
+	AdaptiveFailureThreshold int
+
+	// AdaptiveRecoveryThreshold is the number of consecutive tests without
+	// infrastructure failures that cause the concurrency level of a queue to
+	// be increased.
+	AdaptiveRecoveryThreshold int
+
+	// BeforeTestHooks lists the hooks invoked before each test is created.
+	// Each hook is either a URL starting with http:// or https://, which
+	// receives the metadata of the test in a POST request, or a shell
+	// command, which receives it on its standard input. A test that fails
+	// any of these hooks is not created.
+	BeforeTestHooks []string
+
+	// AfterTestHooks lists the hooks invoked after each test completes, in
+	// the same form as BeforeTestHooks.
+	AfterTestHooks []string
+
+	// HookTimeout is the time allowed for each hook to complete.
+	HookTimeout time.Duration
+
+	// SkipSucceededWithin causes tests to be skipped when a test with an
+	// identical spec succeeded within this duration. Tests are never
+	// skipped when it is zero.
+	SkipSucceededWithin time.Duration
+
+	// Duplicates is the policy for tests with the same spec as an earlier
+	// test in the run, which is one of DuplicatesAllow, DuplicatesSkip or
+	// DuplicatesRename.
+	Duplicates string
+
+	// RetainRuns is the number of most recent runs whose artifacts are kept
+	// in each output directory. Runs are not removed based on their number
+	// when it is zero.
+	RetainRuns int
+
+	// RetainRunsFor is the age after which the artifacts of runs are
+	// removed from each output directory. Runs are not removed based on
+	// their age when it is zero.
+	RetainRunsFor time.Duration
+
+	// ArchiveRuns causes the test directories of each queue to be
+	// compressed into a single archive at the end of the run.
+	ArchiveRuns bool
+}
+
+// DefaultOptions returns the options used when no settings are specified.
+func DefaultOptions() *Options {
+	return &Options{
+		Namespace:         corev1.NamespaceDefault,
+		ConcurrencyLevels: ConcurrencyLevels{},
+		AnnotationKey:     "pool",
+		PollingInterval:   20 * time.Second,
+		PollingRetries:    2,
+		APIQPS:            DefaultAPIQPS,
+		APIBurst:          DefaultAPIBurst,
+		PushgatewayJob:    "runner",
+		PushInterval:      time.Minute,
+		WarmupSeconds:     -1,
+		BenchmarkSeconds:  -1,
+		HookTimeout:       5 * time.Minute,
+		Duplicates:        DuplicatesAllow,
+
+		AdaptiveFailureThreshold:  2,
+		AdaptiveRecoveryThreshold: 3,
+	}
+}
+
+// RunTests runs the load tests specified by the options, writes the xunit
+// report and returns an error if any test failed.
+func RunTests(ctx context.Context, o *Options) error {
+	if o.BenchmarkSeconds == 0 {
+		return errors.New("benchmark duration override must be positive")
+	}
+
+	var adaptiveConcurrency *AdaptiveConcurrency
+	if o.AdaptiveConcurrency {
+		if o.AdaptiveFailureThreshold < 1 || o.AdaptiveRecoveryThreshold < 1 {
+			return errors.New("adaptive concurrency thresholds must be positive")
+		}
+		adaptiveConcurrency = &AdaptiveConcurrency{
+			FailureThreshold:  o.AdaptiveFailureThreshold,
+			RecoveryThreshold: o.AdaptiveRecoveryThreshold,
+		}
+	}
+
+	if err := ValidateDuplicatesPolicy(o.Duplicates); err != nil {
+		return err
+	}
+
+	if o.RetainRuns < 0 || o.RetainRunsFor < 0 {
+		return errors.New("retained runs and retention duration must not be negative")
+	}
+	var retention *Retention
+	if o.RetainRuns > 0 || o.RetainRunsFor > 0 {
+		retention = &Retention{
+			MaxRuns: o.RetainRuns,
+			MaxAge:  o.RetainRunsFor,
+		}
+	}
+
+	if o.APIQPS <= 0 || o.APIBurst < 1 {
+		return errors.New("Kubernetes API QPS and burst must be positive")
+	}
+
+	var metrics *Metrics
+	if o.PushgatewayURL != "" {
+		log.Printf("Pushing metrics to %s as job %q every %v", o.PushgatewayURL, o.PushgatewayJob, o.PushInterval)
+		metrics = NewMetrics(o.PushgatewayURL, o.PushgatewayJob)
+	}
+
+	// Clients are throttled when they are created, so this must be set
+	// before any client is created.
+	SetAPIThrottling(&APIThrottling{
+		QPS:     float32(o.APIQPS),
+		Burst:   o.APIBurst,
+		Metrics: metrics,
+	})
+
+	inputConfigs, err := DecodeFromFiles(o.FileNames)
+	if err != nil {
+		return fmt.Errorf("failed to decode: %v", err)
+	}
+
+	if o.ShardScenarios {
+		inputConfigs, err = ShardByScenario(inputConfigs)
+		if err != nil {
+			return fmt.Errorf("failed to shard by scenario: %v", err)
+		}
+	}
+
+	dependencies, err := NewDependencies(inputConfigs)
+	if err != nil {
+		return fmt.Errorf("failed to resolve dependencies: %v", err)
+	}
+	// Queues start their tests in order, so prerequisites must come first
+	// to avoid waiting for tests that a queue has not started.
+	inputConfigs, err = dependencies.Sort(inputConfigs)
+	if err != nil {
+		return fmt.Errorf("failed to order tests by dependencies: %v", err)
+	}
+
+	namespace := o.Namespace
+	if o.NamespacePerRun {
+		k8sClientset := NewK8sClientset()
+		namespace, err = CreateRunNamespace(ctx, k8sClientset)
+		if err != nil {
+			return fmt.Errorf("failed to create namespace for run: %v", err)
+		}
+		log.Printf("Created namespace %s for run", namespace)
+		defer func() {
+			// The run context may already be cancelled, and the
+			// namespace must be deleted regardless.
+			if err := DeleteRunNamespace(context.Background(), k8sClientset, namespace); err != nil {
+				log.Printf("Failed to clean up: %v", err)
+				return
+			}
+			log.Printf("Deleted namespace %s", namespace)
+		}()
+	}
+
+	if err := SetNamespace(inputConfigs, namespace); err != nil {
+		return err
+	}
+
+	SetScenarioOverrides(inputConfigs, o.WarmupSeconds, o.BenchmarkSeconds)
+
+	if err := SetSpecHashes(inputConfigs); err != nil {
+		return fmt.Errorf("failed to hash specs: %v", err)
+	}
+
+	queueSelector := QueueSelectorFromAnnotation(o.AnnotationKey)
+	if o.RoutingRulesFile != "" {
+		rules, err := LoadRoutingRules(o.RoutingRulesFile)
+		if err != nil {
+			return fmt.Errorf("failed to load routing rules: %v", err)
+		}
+		queueSelector = QueueSelectorFromRules(rules, o.AnnotationKey)
+	}
+
+	inputConfigs = DedupeConfigs(inputConfigs, o.Duplicates, dependencies)
+	configQueueMap := CreateQueueMap(inputConfigs, queueSelector)
+	if err := ValidateConcurrencyLevels(configQueueMap, o.ConcurrencyLevels); err != nil {
+		return fmt.Errorf("failed to validate concurrency levels: %v", err)
+	}
+
+	outputPath := xunit.OutputPath(o.OutputFile)
+
+	outputDirMap := make(map[string]string)
+	for qName := range configQueueMap {
+		outputFilePath := outputPath(qName)
+		outputDir := path.Dir(outputFilePath)
+		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create output directory %q: %v", outputDir, err)
+		}
+		outputDirMap[qName] = outputDir
+	}
+
+	if o.JSONOutputFile != "" {
+		jsonOutputDir := path.Dir(o.JSONOutputFile)
+		if err := os.MkdirAll(jsonOutputDir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create output directory %q: %v", jsonOutputDir, err)
+		}
+	}
+
+	log.Printf("Namespace: %s", namespace)
+	if o.ShardScenarios {
+		log.Printf("Sharding tests by scenario")
+	}
+	log.Printf("Annotation key for queue assignment: %s", o.AnnotationKey)
+	if o.RoutingRulesFile != "" {
+		log.Printf("Routing rules for queue assignment: %s", o.RoutingRulesFile)
+	}
+	log.Printf("Policy for tests with duplicate specs: %s", o.Duplicates)
+	log.Printf("Polling interval: %v", o.PollingInterval)
+	log.Printf("Polling retries: %d", o.PollingRetries)
+	log.Printf("Kubernetes API throttling: %v QPS, burst of %d", o.APIQPS, o.APIBurst)
+	log.Printf("Test counts per queue: %v", CountConfigs(configQueueMap))
+	log.Printf("Queue concurrency levels: %v", o.ConcurrencyLevels)
+	log.Printf("Output directories: %v", outputDirMap)
+	if o.LogURLPrefix != "" {
+		log.Printf("Prefix for log urls: %s", o.LogURLPrefix)
+	}
+	if o.WarmupSeconds >= 0 {
+		log.Printf("Warmup duration override: %ds", o.WarmupSeconds)
+	}
+	if o.BenchmarkSeconds >= 0 {
+		log.Printf("Benchmark duration override: %ds", o.BenchmarkSeconds)
+	}
+	if o.CollectProfiles {
+		log.Printf("Collecting profiles from workers with a pprof port")
+	}
+	if adaptiveConcurrency != nil {
+		log.Printf("Adaptive concurrency: halve after %d consecutive infrastructure failures, increase after %d tests without them", adaptiveConcurrency.FailureThreshold, adaptiveConcurrency.RecoveryThreshold)
+	}
+
+	if retention != nil {
+		log.Printf("Retaining runs in output directories: last %d, for %v (zero is unlimited)", retention.MaxRuns, retention.MaxAge)
+	}
+	if o.ArchiveRuns {
+		log.Printf("Archiving the test directories of each queue at the end of the run")
+	}
+	if o.SkipSucceededWithin > 0 {
+		log.Printf("Skipping tests with an identical spec that succeeded within %v", o.SkipSucceededWithin)
+	}
+	if dependencies != nil {
+		log.Printf("Tests with dependencies: %d", len(dependencies.prerequisites))
+	}
+	hooks := NewHooks(o.BeforeTestHooks, o.AfterTestHooks, o.HookTimeout)
+	if hooks != nil {
+		log.Printf("Test hooks: %d before, %d after, timeout %v", len(hooks.Before), len(hooks.After), hooks.Timeout)
+	}
+
+	var artifacts *storage.Store
+	if len(o.DefaultsFiles) > 0 {
+		artifacts, err = NewArtifactStore(ctx, o.DefaultsFiles)
+		if err != nil {
+			return fmt.Errorf("failed to set up artifact uploads: %v", err)
+		}
+	}
+
+	var nodeAgent *NodeAgent
+	if len(o.DefaultsFiles) > 0 {
+		nodeAgent, err = NewNodeAgent(o.DefaultsFiles)
+		if err != nil {
+			return fmt.Errorf("failed to set up node metrics collection: %v", err)
+		}
+	}
+
+	dashboardURLTemplate := o.DashboardURLTemplate
+	if dashboardURLTemplate == "" && len(o.DefaultsFiles) > 0 {
+		defaults, err := config.LoadDefaultsFiles(o.DefaultsFiles)
+		if err != nil {
+			return fmt.Errorf("failed to load defaults: %v", err)
+		}
+		dashboardURLTemplate = defaults.DashboardURLTemplate
+	}
+	var dashboardURL *config.DashboardURL
+	if dashboardURLTemplate != "" {
+		dashboardURL, err = config.ParseDashboardURL(dashboardURLTemplate)
+		if err != nil {
+			return err
+		}
+		log.Printf("Linking tests to dashboards: %s", dashboardURLTemplate)
+	}
+
+	r := NewRunner(NewLoadTestGetterForNamespace(namespace), NewPodsGetter(), AfterIntervalFunction(o.PollingInterval), o.PollingRetries, o.DeleteSuccessfulTests, o.LogURLPrefix, metrics, o.CollectProfiles, adaptiveConcurrency, artifacts, hooks, o.SkipSucceededWithin, dependencies, nodeAgent, dashboardURL)
+
+	logPrefixFmt := LogPrefixFmt(configQueueMap)
+
+	report := xunit.Report{}
+
+	startTime := time.Now()
+	reporter := NewReporter(&report)
+	reporter.SetStartTime(startTime)
+
+	// Reports are rewritten as each test completes, so that the results of
+	// the tests that completed are kept if the runner is interrupted.
+	if o.OutputFile != "" || o.JSONOutputFile != "" {
+		reporter.SetCheckpointFunc(func(checkpoint *xunit.Report) {
+			if err := writeReports(checkpoint, outputPath, o.OutputFile, o.JSONOutputFile); err != nil {
+				log.Printf("Failed to write checkpoint of reports: %v", err)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go metrics.PushPeriodically(ctx, o.PushInterval)
+
+	done := make(chan *TestSuiteReporter)
+
+	for qName, configs := range configQueueMap {
+		testSuiteReporter := reporter.NewTestSuiteReporter(qName, logPrefixFmt, TestCaseNameFromAnnotations("scenario"))
+		testSuiteReporter.SetStartTime(time.Now())
+		go r.Run(ctx, configs, testSuiteReporter, o.ConcurrencyLevels[qName], outputDirMap[qName], done)
+	}
+
+	var failureReasons []failure.Reason
+	for range configQueueMap {
+		testSuiteReporter := <-done
+		testSuiteReporter.SetEndTime(time.Now())
+		failureReasons = append(failureReasons, testSuiteReporter.FailureReasons()...)
+		log.Printf("Done running tests for queue %q in %s", testSuiteReporter.Queue(), testSuiteReporter.Duration())
+	}
+
+	reporter.SetEndTime(time.Now())
+
+	if err := metrics.Push(); err != nil {
+		log.Printf("Failed to push final metrics: %v", err)
+	}
+
+	report.Finalize()
+
+	if err := writeReports(&report, outputPath, o.OutputFile, o.JSONOutputFile); err != nil {
+		return err
+	}
+	if o.OutputFile != "" {
+		log.Printf("Wrote XML reports for %d queue(s)", len(report.Suites))
+	}
+	if o.JSONOutputFile != "" {
+		log.Printf("Wrote JSON report to file %q", o.JSONOutputFile)
+	}
+
+	if retention != nil || o.ArchiveRuns {
+		recordRuns(configQueueMap, outputDirMap, startTime, o.ArchiveRuns, retention)
+	}
+
+	if report.ErrorCount > 0 {
+		return &TestsFailedError{
+			ErrorCount: report.ErrorCount,
+			Reasons:    failureReasons,
+		}
+	}
+	return nil
+}
+
+// TestsFailedError is returned when errors are found during a test run.
+type TestsFailedError struct {
+	// ErrorCount is the number of errors in the report.
+	ErrorCount int
+
+	// Reasons lists the reasons of the tests that failed.
+	Reasons []failure.Reason
+}
+
+func (e *TestsFailedError) Error() string {
+	return fmt.Sprintf("errors found during test run: %d", e.ErrorCount)
+}
+
+// ExitCode returns the exit code that corresponds to the reasons of the
+// failed tests. It returns 1 if no test failed, but errors were found.
+func (e *TestsFailedError) ExitCode() int {
+	if code := failure.ExitCode(e.Reasons); code != 0 {
+		return code
+	}
+	return 1
+}
+
+// recordRuns records the artifacts that the run saved in the output directory
+// of each queue, archiving them if requested, and rotates the runs recorded in
+// each directory. Failures are logged, since the tests have already run.
+func recordRuns(configQueueMap map[string][]*grpcv1.LoadTest, outputDirMap map[string]string, startTime time.Time, archive bool, retention *Retention) {
+	for qName, configs := range configQueueMap {
+		outputDir := outputDirMap[qName]
+		var testNames []string
+		for _, config := range configs {
+			testNames = append(testNames, config.Name)
+		}
+		indexPath, err := RecordRun(outputDir, qName, testNames, startTime, archive)
+		if err != nil {
+			log.Printf("Failed to record run for queue %q: %v", qName, err)
+			continue
+		}
+		log.Printf("Recorded run for queue %q in %s", qName, indexPath)
+
+		removed, err := RotateRuns(outputDir, retention, time.Now())
+		if err != nil {
+			log.Printf("Failed to rotate runs for queue %q: %v", qName, err)
+		}
+		if len(removed) > 0 {
+			log.Printf("Removed %d old run(s) for queue %q", len(removed), qName)
+		}
+	}
+}
+
+// writeReports writes the xunit XML report of each suite of a finalized
+// report to the path given by outputPath, when outputFile is not empty, and
+// the whole report as JSON to jsonOutputFile, when it is not empty.
+func writeReports(report *xunit.Report, outputPath func(string) string, outputFile string, jsonOutputFile string) error {
+	options := xunit.ReportWritingOptions{
+		IndentSize: 2,
+		MaxRetries: 3,
+	}
+
+	if outputFile != "" {
+		for suiteName, suiteReport := range report.Split() {
+			outputFilePath := outputPath(suiteName)
+			err := writeFileAtomically(outputFilePath, func(w io.Writer) error {
+				return suiteReport.WriteToStream(w, options)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to write XML report to file %q: %v", outputFilePath, err)
+			}
+		}
+	}
+
+	if jsonOutputFile != "" {
+		err := writeFileAtomically(jsonOutputFile, func(w io.Writer) error {
+			return report.WriteJSONToStream(w, options)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report to file %q: %v", jsonOutputFile, err)
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomically writes a file through a temporary file in the same
+// directory, which replaces the file once it is complete. Readers of the file
+// never see it partially written, and the previous contents are kept if the
+// write fails.
+func writeFileAtomically(filePath string, write func(io.Writer) error) error {
+	tempFile, err := os.CreateTemp(path.Dir(filePath), path.Base(filePath)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	// Temporary files are only readable by their owner, unlike the files
+	// created by os.Create.
+	if err := tempFile.Chmod(0644); err != nil {
+		return fmt.Errorf("failed to set permissions of temporary file %q: %v", tempFile.Name(), err)
+	}
+	if err := write(tempFile); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file %q: %v", tempFile.Name(), err)
+	}
+	if err := os.Rename(tempFile.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to rename temporary file %q: %v", tempFile.Name(), err)
+	}
+	return nil
+}
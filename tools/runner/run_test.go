@@ -0,0 +1,149 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/grpc/test-infra/tools/runner/xunit"
+)
+
+var _ = ginkgo.Describe("writeReports", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "reports")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	ginkgo.It("writes an XML report for each suite and a JSON report", func() {
+		report := &xunit.Report{
+			Name: "run",
+			Suites: []*xunit.TestSuite{
+				{Name: "a", Cases: []*xunit.TestCase{{Name: "a1"}}},
+				{Name: "b", Cases: []*xunit.TestCase{{Name: "b1"}, {Name: "b2"}}},
+			},
+		}
+		report.Finalize()
+		for _, queue := range []string{"a", "b"} {
+			Expect(os.Mkdir(filepath.Join(dir, queue), 0755)).To(Succeed())
+		}
+		jsonOutputFile := filepath.Join(dir, "report.json")
+
+		Expect(writeReports(report, xunit.OutputPath(dir+"/sponge_log.xml"), "sponge_log.xml", jsonOutputFile)).To(Succeed())
+
+		data, err := os.ReadFile(filepath.Join(dir, "b", "b_sponge_log.xml"))
+		Expect(err).ToNot(HaveOccurred())
+		suiteReport := new(xunit.Report)
+		Expect(xml.Unmarshal(data, suiteReport)).To(Succeed())
+		Expect(caseNames(suiteReport)).To(Equal(map[string][]string{"b": {"b1", "b2"}}))
+
+		data, err = os.ReadFile(jsonOutputFile)
+		Expect(err).ToNot(HaveOccurred())
+		jsonReport := new(xunit.Report)
+		Expect(json.Unmarshal(data, jsonReport)).To(Succeed())
+		Expect(jsonReport.TestCount).To(Equal(3))
+		Expect(caseNames(jsonReport)).To(Equal(map[string][]string{"a": {"a1"}, "b": {"b1", "b2"}}))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(3))
+	})
+
+	ginkgo.It("replaces the report of a checkpoint with the final report", func() {
+		jsonOutputFile := filepath.Join(dir, "report.json")
+		checkpoint := &xunit.Report{
+			Name:   "run",
+			Suites: []*xunit.TestSuite{{Name: "a", Cases: []*xunit.TestCase{{Name: "a2"}}}},
+		}
+		checkpoint.Finalize()
+		Expect(writeReports(checkpoint, nil, "", jsonOutputFile)).To(Succeed())
+
+		final := &xunit.Report{
+			Name:   "run",
+			Suites: []*xunit.TestSuite{{Name: "a", Cases: []*xunit.TestCase{{Name: "a1"}, {Name: "a2"}}}},
+		}
+		final.Finalize()
+		Expect(writeReports(final, nil, "", jsonOutputFile)).To(Succeed())
+
+		data, err := os.ReadFile(jsonOutputFile)
+		Expect(err).ToNot(HaveOccurred())
+		jsonReport := new(xunit.Report)
+		Expect(json.Unmarshal(data, jsonReport)).To(Succeed())
+		Expect(caseNames(jsonReport)).To(Equal(map[string][]string{"a": {"a1", "a2"}}))
+	})
+})
+
+var _ = ginkgo.Describe("writeFileAtomically", func() {
+	var dir string
+	var filePath string
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "reports")
+		Expect(err).ToNot(HaveOccurred())
+		filePath = filepath.Join(dir, "report.xml")
+		Expect(os.WriteFile(filePath, []byte("previous"), 0644)).To(Succeed())
+	})
+
+	ginkgo.AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	ginkgo.It("replaces the file once it is written", func() {
+		err := writeFileAtomically(filePath, func(w io.Writer) error {
+			_, err := io.WriteString(w, "current")
+			return err
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("current"))
+		info, err := os.Stat(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+	})
+
+	ginkgo.It("keeps the previous file when the write fails", func() {
+		err := writeFileAtomically(filePath, func(w io.Writer) error {
+			io.WriteString(w, "partial")
+			return errors.New("disk full")
+		})
+		Expect(err).To(MatchError("disk full"))
+
+		data, err := os.ReadFile(filePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("previous"))
+		entries, err := os.ReadDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+})
@@ -19,13 +19,20 @@ package runner
 import (
 	"context"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	grpcv1 "github.com/grpc/test-infra/api/v1"
 	clientset "github.com/grpc/test-infra/clientset"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/failure"
+	"github.com/grpc/test-infra/kubehelpers"
+	"github.com/grpc/test-infra/storage"
 	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -56,10 +63,39 @@ type Runner struct {
 	deleteSuccessfulTests bool
 	// logURLPrefix  is a prefix to be added to log path urls.
 	logURLPrefix string
+	// metrics records the progress of tests for monitoring. It may be nil,
+	// in which case no metrics are recorded.
+	metrics *Metrics
+	// collectProfiles determines whether pprof profiles are collected from
+	// workers that expose a pprof port while tests are running.
+	collectProfiles bool
+	// adaptiveConcurrency contains the settings used to reduce the
+	// concurrency level of queues with consecutive infrastructure failures.
+	// It may be nil, in which case concurrency levels are not adjusted.
+	adaptiveConcurrency *AdaptiveConcurrency
+	// artifacts uploads the logs, results and profiles of tests. It may be
+	// nil, in which case artifacts are only saved locally.
+	artifacts *storage.Store
+	// hooks are invoked before each test is created and after it completes.
+	// It may be nil, in which case no hooks are invoked.
+	hooks *Hooks
+	// skipSucceededWithin is the duration within which a test with an
+	// identical spec must have succeeded for a test to be skipped. Tests
+	// are never skipped when it is zero.
+	skipSucceededWithin time.Duration
+	// dependencies determines the tests that each test waits for before it
+	// is created. It may be nil, in which case tests do not wait.
+	dependencies *Dependencies
+	// nodeAgent locates the agents that sample the system metrics of nodes.
+	// It may be nil, in which case node metrics are not collected.
+	nodeAgent *NodeAgent
+	// dashboardURL renders links to the dashboards of tests. It may be nil,
+	// in which case the link set by the controller is used if there is one.
+	dashboardURL *config.DashboardURL
 }
 
 // NewRunner creates a new Runner object.
-func NewRunner(loadTestGetter clientset.LoadTestGetter, podsGetter corev1types.PodsGetter, afterInterval func(), retries uint, deleteSuccessfulTests bool, logURLPrefix string) *Runner {
+func NewRunner(loadTestGetter clientset.LoadTestGetter, podsGetter corev1types.PodsGetter, afterInterval func(), retries uint, deleteSuccessfulTests bool, logURLPrefix string, metrics *Metrics, collectProfiles bool, adaptiveConcurrency *AdaptiveConcurrency, artifacts *storage.Store, hooks *Hooks, skipSucceededWithin time.Duration, dependencies *Dependencies, nodeAgent *NodeAgent, dashboardURL *config.DashboardURL) *Runner {
 	return &Runner{
 		loadTestGetter:        loadTestGetter,
 		podsGetter:            podsGetter,
@@ -67,36 +103,59 @@ func NewRunner(loadTestGetter clientset.LoadTestGetter, podsGetter corev1types.P
 		retries:               retries,
 		deleteSuccessfulTests: deleteSuccessfulTests,
 		logURLPrefix:          logURLPrefix,
+		metrics:               metrics,
+		collectProfiles:       collectProfiles,
+		adaptiveConcurrency:   adaptiveConcurrency,
+		artifacts:             artifacts,
+		hooks:                 hooks,
+		skipSucceededWithin:   skipSucceededWithin,
+		dependencies:          dependencies,
+		nodeAgent:             nodeAgent,
+		dashboardURL:          dashboardURL,
 	}
 }
 
-// Run runs a set of LoadTests at a given concurrency level.
+// Run runs a set of LoadTests at a given concurrency level. When adaptive
+// concurrency is enabled, the concurrency level is reduced after consecutive
+// infrastructure failures and restored gradually after tests recover. Tests
+// are started in order, and a test that depends on other tests is not
+// started until they have finished, so it holds back the tests after it.
 func (r *Runner) Run(ctx context.Context, configs []*grpcv1.LoadTest, suiteReporter *TestSuiteReporter, concurrencyLevel int, outputDir string, done chan<- *TestSuiteReporter) {
 	var count, n int
 	qName := suiteReporter.Queue()
 	testDone := make(chan *TestCaseReporter)
+	adjuster := newConcurrencyAdjuster(r.adaptiveConcurrency, concurrencyLevel)
+	r.metrics.SetConcurrencyLevel(qName, adjuster.Level())
+	waitForTest := func() {
+		reporter := <-testDone
+		reporter.SetEndTime(time.Now())
+		suiteReporter.Checkpoint(reporter)
+		r.metrics.TestFinished(qName, !reporter.Failed(), reporter.Duration())
+		log.Printf("Finished test in queue %s after %v", qName, reporter.Duration())
+		n--
+		count++
+		log.Printf("Finished %d tests in queue %s", count, qName)
+		if adjuster.Record(reporter.InfrastructureFailure()) {
+			log.Printf("Adjusted concurrency level of queue %s to %d", qName, adjuster.Level())
+			r.metrics.SetConcurrencyLevel(qName, adjuster.Level())
+		}
+	}
 	for _, config := range configs {
-		for n >= concurrencyLevel {
-			reporter := <-testDone
-			reporter.SetEndTime(time.Now())
-			log.Printf("Finished test in queue %s after %v", qName, reporter.Duration())
-			n--
-			count++
-			log.Printf("Finished %d tests in queue %s", count, qName)
+		if err := r.dependencies.Wait(ctx, config.Name); err != nil {
+			log.Printf("Stopped waiting for the dependencies of test %s in queue %s: %v", config.Name, qName, err)
+		}
+		for n >= adjuster.Level() {
+			waitForTest()
 		}
 		n++
 		reporter := suiteReporter.NewTestCaseReporter(config)
 		log.Printf("Starting test %d in queue %s", reporter.Index(), qName)
 		reporter.SetStartTime(time.Now())
+		r.metrics.TestStarted(qName)
 		go r.runTest(ctx, config, reporter, outputDir, testDone)
 	}
 	for n > 0 {
-		reporter := <-testDone
-		reporter.SetEndTime(time.Now())
-		log.Printf("Finished test in queue %s after %v", qName, reporter.Duration())
-		n--
-		count++
-		log.Printf("Finished %d tests in queue %s", count, qName)
+		waitForTest()
 	}
 	done <- suiteReporter
 }
@@ -105,6 +164,49 @@ func (r *Runner) Run(ctx context.Context, configs []*grpcv1.LoadTest, suiteRepor
 func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter *TestCaseReporter, outputDir string, done chan<- *TestCaseReporter) {
 	var s, status string
 	var retries uint
+	var createdAt time.Time
+	var observedRunning bool
+	var profilesDone chan []*ProfileInfo
+	var envoyDumpsDone chan []*EnvoyDumpInfo
+	profileCtx, stopProfiles := context.WithCancel(ctx)
+	defer stopProfiles()
+
+	scenarioProperties, err := ScenarioProperties(config.Spec.ScenariosJSON, "scenario")
+	if err != nil {
+		reporter.Warning("Could not record scenario parameters: %v", err)
+	}
+	var scenarioKeys []string
+	for key := range scenarioProperties {
+		scenarioKeys = append(scenarioKeys, key)
+	}
+	sort.Strings(scenarioKeys)
+	for _, key := range scenarioKeys {
+		reporter.AddProperty(key, scenarioProperties[key])
+	}
+
+	if unsatisfied := r.dependencies.Unsatisfied(config.Name); len(unsatisfied) > 0 {
+		reporter.Fail(failure.DependencyFailed, "Skipping test %s, since tests it depends on did not succeed: %s", config.Name, strings.Join(unsatisfied, ", "))
+		r.reportDone(config, reporter, done)
+		return
+	}
+
+	if r.skipSucceededWithin > 0 {
+		duplicate, err := r.findSucceededDuplicate(ctx, config)
+		if err != nil {
+			reporter.Warning("Could not look for duplicates of test %s: %v", config.Name, err)
+		} else if duplicate != nil {
+			reporter.Info("Skipping test %s, since test %s with an identical spec succeeded at %v", config.Name, duplicate.Name, duplicate.Status.StopTime.Time)
+			reporter.AddProperty("duplicate_of", duplicate.Name)
+			r.reportDone(config, reporter, done)
+			return
+		}
+	}
+
+	if err := r.hooks.Run(ctx, NewHookEvent(BeforeTest, config, reporter.Queue())); err != nil {
+		reporter.Fail(failure.HookFailed, "Aborting before creating test %s: %v", config.Name, err)
+		r.reportDone(config, reporter, done)
+		return
+	}
 
 	for {
 		loadTest, err := r.loadTestGetter.Create(ctx, config, metav1.CreateOptions{})
@@ -116,18 +218,25 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 				r.afterInterval()
 				continue
 			}
-			reporter.Error("Aborting after %d retries to create test %s: %v", r.retries, config.Name, err)
-			done <- reporter
+			reporter.Fail(failure.KubernetesError, "Aborting after %d retries to create test %s: %v", r.retries, config.Name, err)
+			r.finishTest(ctx, config, reporter, done)
 			return
 		}
 		retries = 0
 		config.Status = loadTest.Status
+		createdAt = time.Now()
+		r.metrics.TestSubmitted(reporter.Queue())
 		reporter.Info("Created test %s", config.Name)
 		break
 	}
 
 	for {
 		loadTest, err := r.loadTestGetter.Get(ctx, config.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			reporter.Fail(failure.Cancelled, "Test %s was deleted before it terminated", config.Name)
+			r.finishTest(ctx, config, reporter, done)
+			return
+		}
 		if err != nil {
 			reporter.Warning("Failed to poll test %s: %v", config.Name, err)
 			if retries < r.retries {
@@ -136,8 +245,8 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 				r.afterInterval()
 				continue
 			}
-			reporter.Error("Aborting test after %d retries to poll test %s: %v", r.retries, config.Name, err)
-			done <- reporter
+			reporter.Fail(failure.KubernetesError, "Aborting test after %d retries to poll test %s: %v", r.retries, config.Name, err)
+			r.finishTest(ctx, config, reporter, done)
 			return
 		}
 		retries = 0
@@ -154,7 +263,19 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 			if err != nil {
 				reporter.Error("Could not save pod logs: %v", err)
 			}
+			var manifest *storage.Manifest
+			if r.artifacts != nil {
+				manifest = storage.NewManifest(loadTest)
+			}
+			for _, logInfo := range savedLogInfos {
+				logInfo.URL = r.uploadArtifact(ctx, loadTest, reporter, manifest, storage.LogKind, logInfo.LogPath)
+			}
 			reporter.AddProperty("name", loadTest.Name)
+			reporter.AddProperty("namespace", loadTest.Namespace)
+			if link := r.dashboardLink(loadTest, reporter); link != "" {
+				reporter.Info("Dashboard: %s", link)
+				reporter.AddProperty("dashboard", link)
+			}
 			for property, value := range PodNameProperties(pods, loadTest.Name, "pod") {
 				reporter.AddProperty(property, value)
 			}
@@ -163,10 +284,64 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 				reporter.AddProperty(property, value)
 			}
 
+			for property, value := range InteropCaseProperties(loadTest.Status.InteropCases, "interop") {
+				reporter.AddProperty(property, value)
+			}
+
+			if profilesDone != nil {
+				stopProfiles()
+				profileInfos := <-profilesDone
+				for _, profileInfo := range profileInfos {
+					profileInfo.URL = r.uploadArtifact(ctx, loadTest, reporter, manifest, storage.ProfileKind, profileInfo.ProfilePath)
+				}
+				for property, value := range PodProfileProperties(profileInfos, r.logURLPrefix, "pod") {
+					reporter.AddProperty(property, value)
+				}
+			}
+
+			if envoyDumpsDone != nil {
+				stopProfiles()
+				dumpInfos := <-envoyDumpsDone
+				for _, dumpInfo := range dumpInfos {
+					dumpInfo.URL = r.uploadArtifact(ctx, loadTest, reporter, manifest, storage.ProxyKind, dumpInfo.DumpPath)
+				}
+				for property, value := range PodEnvoyDumpProperties(dumpInfos, r.logURLPrefix, "pod") {
+					reporter.AddProperty(property, value)
+				}
+			}
+
+			if r.nodeAgent != nil && loadTest.Spec.Interop == nil {
+				metricsPath, err := CollectNodeMetrics(ctx, loadTest, r.podsGetter, pods, r.nodeAgent, outputDir)
+				if err != nil {
+					reporter.Warning("Could not collect all node metrics: %v", err)
+				}
+				if metricsPath != "" {
+					metricsURL := r.uploadArtifact(ctx, loadTest, reporter, manifest, storage.NodeMetricsKind, metricsPath)
+					if metricsURL == "" {
+						metricsURL = r.logURLPrefix + metricsPath
+					}
+					reporter.AddProperty("node_metrics", metricsURL)
+				}
+			}
+
 			if status != "Succeeded" {
-				reporter.Error("Test failed with reason %q: %v", loadTest.Status.Reason, loadTest.Status.Message)
+				reporter.Fail(failure.FromCRDReason(loadTest.Status.Reason), "Test failed with reason %q: %v", loadTest.Status.Reason, loadTest.Status.Message)
 			} else {
 				reporter.Info("Test terminated with a status of %q", status)
+				// Interop tests run the interop client instead of the
+				// benchmark driver, so they have no scenario result.
+				if loadTest.Spec.Interop == nil {
+					resultPath, err := SaveScenarioResult(ctx, loadTest, r.podsGetter, pods, outputDir)
+					if err != nil {
+						reporter.Warning("Could not save scenario result: %v", err)
+					} else {
+						resultURL := r.uploadArtifact(ctx, loadTest, reporter, manifest, storage.ResultKind, resultPath)
+						if resultURL == "" {
+							resultURL = r.logURLPrefix + resultPath
+						}
+						reporter.AddProperty("scenario_result", resultURL)
+					}
+				}
 				if r.deleteSuccessfulTests {
 					err = r.loadTestGetter.Delete(ctx, config.Name, metav1.DeleteOptions{})
 					if err != nil {
@@ -176,9 +351,29 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 					}
 				}
 			}
-			done <- reporter
+			if manifest != nil {
+				manifestPath, err := SaveArtifactsManifest(manifest, outputDir)
+				if err != nil {
+					reporter.Warning("Could not save artifacts manifest: %v", err)
+				} else {
+					reporter.AddProperty("artifacts", r.logURLPrefix+manifestPath)
+				}
+			}
+			r.finishTest(ctx, config, reporter, done)
 			return
 		case loadTest.Status.State == grpcv1.Running:
+			if !observedRunning {
+				observedRunning = true
+				r.metrics.TestRunning(reporter.Queue(), time.Since(createdAt))
+				if r.collectProfiles {
+					profilesDone = make(chan []*ProfileInfo, 1)
+					go r.collectTestProfiles(profileCtx, loadTest, reporter, outputDir, profilesDone)
+				}
+				if kubehelpers.IsProxiedTest(&loadTest.Spec.Clients) {
+					envoyDumpsDone = make(chan []*EnvoyDumpInfo, 1)
+					go r.collectTestEnvoyDumps(profileCtx, loadTest, reporter, outputDir, envoyDumpsDone)
+				}
+			}
 			reporter.Info("%s", status)
 			r.afterInterval()
 		default:
@@ -192,6 +387,107 @@ func (r *Runner) runTest(ctx context.Context, config *grpcv1.LoadTest, reporter
 	}
 }
 
+// findSucceededDuplicate returns the most recent test with the same spec hash
+// as a configuration that succeeded within the skipSucceededWithin duration.
+// Nil is returned if there is no such test.
+func (r *Runner) findSucceededDuplicate(ctx context.Context, test *grpcv1.LoadTest) (*grpcv1.LoadTest, error) {
+	hash := test.Labels[config.SpecHashLabel]
+	if hash == "" {
+		return nil, nil
+	}
+	list, err := r.loadTestGetter.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{config.SpecHashLabel: hash}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var duplicate *grpcv1.LoadTest
+	for i := range list.Items {
+		candidate := &list.Items[i]
+		if candidate.Status.State != grpcv1.Succeeded || candidate.Status.StopTime == nil {
+			continue
+		}
+		if time.Since(candidate.Status.StopTime.Time) > r.skipSucceededWithin {
+			continue
+		}
+		if duplicate == nil || candidate.Status.StopTime.After(duplicate.Status.StopTime.Time) {
+			duplicate = candidate
+		}
+	}
+	return duplicate, nil
+}
+
+// finishTest invokes the hooks for the end of a test, and reports that the
+// test is done. Failures of these hooks are reported as warnings, since the
+// test has already completed.
+func (r *Runner) finishTest(ctx context.Context, config *grpcv1.LoadTest, reporter *TestCaseReporter, done chan<- *TestCaseReporter) {
+	if err := r.hooks.Run(ctx, NewHookEvent(AfterTest, config, reporter.Queue())); err != nil {
+		reporter.Warning("Failed to run hooks after test %s: %v", config.Name, err)
+	}
+	r.reportDone(config, reporter, done)
+}
+
+// reportDone records the result of a test for the tests that depend on it,
+// and reports that the test is done. The result is recorded first, since the
+// queue of the test may be waiting for it instead of receiving from done.
+func (r *Runner) reportDone(config *grpcv1.LoadTest, reporter *TestCaseReporter, done chan<- *TestCaseReporter) {
+	r.dependencies.Finish(config.Name, !reporter.Failed())
+	done <- reporter
+}
+
+// collectTestProfiles collects pprof profiles from the workers of a running
+// LoadTest and sends information about the saved profiles to done.
+func (r *Runner) collectTestProfiles(ctx context.Context, loadTest *grpcv1.LoadTest, reporter *TestCaseReporter, outputDir string, done chan<- []*ProfileInfo) {
+	pods, err := GetTestPods(ctx, loadTest, r.podsGetter)
+	if err != nil {
+		reporter.Warning("Could not list pods to collect profiles: %v", err)
+		done <- nil
+		return
+	}
+	profileInfos, err := CollectProfiles(ctx, loadTest, r.podsGetter, pods, outputDir)
+	if err != nil {
+		reporter.Warning("Could not collect all profiles: %v", err)
+	}
+	if len(profileInfos) > 0 {
+		reporter.Info("Collected %d profile(s)", len(profileInfos))
+	}
+	done <- profileInfos
+}
+
+// collectTestEnvoyDumps collects the stats and config dumps of the Envoy
+// sidecars of a running proxied LoadTest and sends information about the saved
+// dumps to done.
+func (r *Runner) collectTestEnvoyDumps(ctx context.Context, loadTest *grpcv1.LoadTest, reporter *TestCaseReporter, outputDir string, done chan<- []*EnvoyDumpInfo) {
+	pods, err := GetTestPods(ctx, loadTest, r.podsGetter)
+	if err != nil {
+		reporter.Warning("Could not list pods to collect Envoy dumps: %v", err)
+		done <- nil
+		return
+	}
+	dumpInfos, err := CollectEnvoyDumps(ctx, loadTest, r.podsGetter, pods, outputDir)
+	if err != nil {
+		reporter.Warning("Could not collect all Envoy dumps: %v", err)
+	}
+	if len(dumpInfos) > 0 {
+		reporter.Info("Collected %d Envoy dump(s)", len(dumpInfos))
+	}
+	done <- dumpInfos
+}
+
+// dashboardLink returns the link to the dashboard of a terminated test, or an
+// empty string if there is none.
+func (r *Runner) dashboardLink(loadTest *grpcv1.LoadTest, reporter *TestCaseReporter) string {
+	if r.dashboardURL == nil {
+		return loadTest.Annotations[config.DashboardURLAnnotation]
+	}
+	link, err := r.dashboardURL.Render(loadTest, time.Now())
+	if err != nil {
+		reporter.Warning("Could not render dashboard URL: %v", err)
+		return ""
+	}
+	return link
+}
+
 // statusString returns a string to represent the test status in logs.
 // The string consists of state, reason and message (each omitted if empty).
 func statusString(config *grpcv1.LoadTest) string {
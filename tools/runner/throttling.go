@@ -0,0 +1,131 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultAPIQPS is the default rate of requests per second that the
+	// runner sends to the Kubernetes API.
+	DefaultAPIQPS = 20
+
+	// DefaultAPIBurst is the default number of requests that the runner may
+	// send to the Kubernetes API at once, above the rate set by the QPS.
+	DefaultAPIBurst = 40
+)
+
+// APIThrottling configures how the runner limits the rate of its requests to
+// the Kubernetes API. Requests that the API server throttles with a 429 Too
+// Many Requests response are retried by the Kubernetes client, and recorded in
+// the metrics of the runner.
+type APIThrottling struct {
+	// QPS is the rate of requests per second that the runner sends.
+	QPS float32
+
+	// Burst is the number of requests that may be sent at once, above the
+	// rate set by QPS.
+	Burst int
+
+	// Metrics records the throttled requests. No metrics are recorded when
+	// it is nil.
+	Metrics *Metrics
+}
+
+var (
+	apiThrottlingMu sync.Mutex
+	apiThrottling   = &APIThrottling{
+		QPS:   DefaultAPIQPS,
+		Burst: DefaultAPIBurst,
+	}
+	apiRateLimiter flowcontrol.RateLimiter
+)
+
+// SetAPIThrottling sets the throttling of the clients that the runner creates
+// afterwards. All clients share a single rate limiter, so that the settings
+// apply to the total rate of requests of the runner, rather than to the rate
+// of each client.
+func SetAPIThrottling(t *APIThrottling) {
+	apiThrottlingMu.Lock()
+	defer apiThrottlingMu.Unlock()
+	apiThrottling = t
+	apiRateLimiter = nil
+}
+
+// applyAPIThrottling configures the rate limiter of a Kubernetes client
+// configuration, and wraps its transport to record throttled requests. The
+// client retries throttled requests itself, waiting for the time given in the
+// Retry-After header of the response.
+func applyAPIThrottling(config *rest.Config) {
+	apiThrottlingMu.Lock()
+	defer apiThrottlingMu.Unlock()
+
+	if apiRateLimiter == nil {
+		apiRateLimiter = flowcontrol.NewTokenBucketRateLimiter(apiThrottling.QPS, apiThrottling.Burst)
+	}
+	config.QPS = apiThrottling.QPS
+	config.Burst = apiThrottling.Burst
+	config.RateLimiter = apiRateLimiter
+
+	metrics := apiThrottling.Metrics
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttlingRoundTripper{next: rt, metrics: metrics}
+	})
+}
+
+// throttlingRoundTripper logs and records requests that receive a 429 Too
+// Many Requests response. It does not retry them, since the Kubernetes client
+// retries requests that are throttled with a Retry-After header.
+type throttlingRoundTripper struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *throttlingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	if delay, ok := retryDelay(resp); ok {
+		log.Printf("Kubernetes API throttled %s %s, retrying in %v", req.Method, req.URL.Path, delay)
+		t.metrics.APIRequestRetried(delay)
+	} else {
+		log.Printf("Kubernetes API throttled %s %s without a Retry-After header, not retrying", req.Method, req.URL.Path)
+	}
+	return resp, err
+}
+
+// retryDelay returns the time that the Kubernetes client waits before it
+// retries a throttled request, which is given in seconds by the Retry-After
+// header of the response. It returns false if the header is missing or
+// invalid, in which case the request is not retried.
+func retryDelay(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// roundTripperFunc is an http.RoundTripper that calls a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements the http.RoundTripper interface.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newThrottledResponse returns a 429 response with the given Retry-After
+// header, which is not set if it is empty.
+func newThrottledResponse(retryAfter string) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{},
+	}
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return resp
+}
+
+var _ = ginkgo.Describe("retryDelay", func() {
+	ginkgo.It("returns the delay in the Retry-After header", func() {
+		delay, ok := retryDelay(newThrottledResponse("3"))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(3 * time.Second))
+
+		delay, ok = retryDelay(newThrottledResponse("0"))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(BeZero())
+	})
+
+	ginkgo.It("returns false when the Retry-After header is missing or invalid", func() {
+		for _, retryAfter := range []string{"", "soon", "-1", "Wed, 21 Oct 2015 07:28:00 GMT"} {
+			_, ok := retryDelay(newThrottledResponse(retryAfter))
+			Expect(ok).To(BeFalse(), retryAfter)
+		}
+	})
+})
+
+var _ = ginkgo.Describe("throttlingRoundTripper", func() {
+	var metrics *Metrics
+	var responses []*http.Response
+	var requests int
+	var rt *throttlingRoundTripper
+
+	ginkgo.BeforeEach(func() {
+		metrics = NewMetrics("http://localhost:9091", "test")
+		responses = nil
+		requests = 0
+		next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp := responses[requests]
+			requests++
+			return resp, nil
+		})
+		rt = &throttlingRoundTripper{next: next, metrics: metrics}
+	})
+
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "https://kubernetes/api/v1/pods", nil)
+		Expect(err).ToNot(HaveOccurred())
+		return req
+	}
+
+	ginkgo.It("passes through responses that are not throttled", func() {
+		ok := &http.Response{StatusCode: http.StatusOK}
+		responses = []*http.Response{ok}
+
+		resp, err := rt.RoundTrip(newRequest())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp).To(BeIdenticalTo(ok))
+		Expect(testutil.ToFloat64(metrics.apiRetries)).To(BeZero())
+	})
+
+	ginkgo.It("records throttled requests without retrying them", func() {
+		throttled := newThrottledResponse("2")
+		responses = []*http.Response{throttled}
+
+		resp, err := rt.RoundTrip(newRequest())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp).To(BeIdenticalTo(throttled))
+		Expect(requests).To(Equal(1))
+		Expect(testutil.ToFloat64(metrics.apiRetries)).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.apiBackoff)).To(Equal(2.0))
+	})
+
+	ginkgo.It("does not record throttled requests that will not be retried", func() {
+		responses = []*http.Response{newThrottledResponse("")}
+
+		_, err := rt.RoundTrip(newRequest())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(Equal(1))
+		Expect(testutil.ToFloat64(metrics.apiRetries)).To(BeZero())
+	})
+
+	ginkgo.It("returns errors from the next round tripper", func() {
+		rt.next = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})
+
+		_, err := rt.RoundTrip(newRequest())
+		Expect(err).To(MatchError("connection refused"))
+	})
+
+	ginkgo.It("records nothing without metrics", func() {
+		responses = []*http.Response{newThrottledResponse("1")}
+		rt.metrics = nil
+
+		_, err := rt.RoundTrip(newRequest())
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
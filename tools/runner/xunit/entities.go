@@ -17,6 +17,7 @@ limitations under the License.
 package xunit
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -28,12 +29,12 @@ import (
 
 // Report encapsulates the data for a xUnit XML report.
 type Report struct {
-	XMLName       xml.Name     `xml:"testsuites"`
-	Name          string       `xml:"name,attr"`
-	TestCount     int          `xml:"tests,attr"`
-	ErrorCount    int          `xml:"errors,attr"`
-	TimeInSeconds float64      `xml:"time,attr"`
-	Suites        []*TestSuite `xml:"testsuite"`
+	XMLName       xml.Name     `xml:"testsuites" json:"-"`
+	Name          string       `xml:"name,attr" json:"name"`
+	TestCount     int          `xml:"tests,attr" json:"testCount"`
+	ErrorCount    int          `xml:"errors,attr" json:"errorCount"`
+	TimeInSeconds float64      `xml:"time,attr" json:"timeInSeconds"`
+	Suites        []*TestSuite `xml:"testsuite" json:"suites"`
 }
 
 // Finalize iterates over the document object model and recomputes the counter
@@ -94,12 +95,31 @@ func (r *Report) WriteToStream(w io.Writer, opts ReportWritingOptions) error {
 		return errors.Wrapf(err, "failed to write xUnit report to stream")
 	}
 	bytes = append(bytes, '\n')
+	return writeWithRetries(w, bytes, opts.MaxRetries)
+}
+
+// WriteJSONToStream writes the contents of the report to the stream as JSON,
+// with the same structure as the XML report. It accepts the same options as
+// WriteToStream, and r.Finalize() should also be called before writing the
+// report.
+func (r *Report) WriteJSONToStream(w io.Writer, opts ReportWritingOptions) error {
+	bytes, err := json.MarshalIndent(r, "", strings.Repeat(" ", opts.IndentSize))
+	if err != nil {
+		return errors.Wrapf(err, "failed to write JSON report to stream")
+	}
+	bytes = append(bytes, '\n')
+	return writeWithRetries(w, bytes, opts.MaxRetries)
+}
 
+// writeWithRetries writes bytes to a stream, retrying up to maxRetries times
+// when a write fails without making progress.
+func writeWithRetries(w io.Writer, bytes []byte, maxRetries int) error {
 	for n, prevN, retries := 0, 0, 0; n < len(bytes); {
-		n, err = w.Write(bytes[n:])
+		written, err := w.Write(bytes[n:])
+		n += written
 		if err != nil {
-			if n == prevN && retries >= opts.MaxRetries {
-				return errors.Wrapf(err, "failed to write %d bytes of xUnit report to stream", len(bytes)-n)
+			if n == prevN && retries >= maxRetries {
+				return errors.Wrapf(err, "failed to write %d bytes of report to stream", len(bytes)-n)
 			}
 
 			prevN = n
@@ -112,36 +132,37 @@ func (r *Report) WriteToStream(w io.Writer, opts ReportWritingOptions) error {
 
 // TestSuite encapsulates metadata for a collection of test cases.
 type TestSuite struct {
-	XMLName       xml.Name    `xml:"testsuite"`
-	ID            string      `xml:"id,attr"`
-	Name          string      `xml:"name,attr"`
-	TestCount     int         `xml:"tests,attr"`
-	ErrorCount    int         `xml:"errors,attr"`
-	TimeInSeconds float64     `xml:"time,attr"`
-	Cases         []*TestCase `xml:"testcase"`
+	XMLName       xml.Name    `xml:"testsuite" json:"-"`
+	ID            string      `xml:"id,attr" json:"id"`
+	Name          string      `xml:"name,attr" json:"name"`
+	TestCount     int         `xml:"tests,attr" json:"testCount"`
+	ErrorCount    int         `xml:"errors,attr" json:"errorCount"`
+	TimeInSeconds float64     `xml:"time,attr" json:"timeInSeconds"`
+	Cases         []*TestCase `xml:"testcase" json:"cases"`
 }
 
 // TestCase encapsulates metadata regarding a single test.
 type TestCase struct {
-	XMLName       xml.Name    `xml:"testcase"`
-	Name          string      `xml:"name,attr"`
-	TimeInSeconds float64     `xml:"time,attr"`
-	Errors        []*Error    `xml:"error"`
-	Properties    []*Property `xml:"properties>property"`
+	XMLName       xml.Name    `xml:"testcase" json:"-"`
+	Name          string      `xml:"name,attr" json:"name"`
+	TimeInSeconds float64     `xml:"time,attr" json:"timeInSeconds"`
+	Errors        []*Error    `xml:"error" json:"errors,omitempty"`
+	Properties    []*Property `xml:"properties>property" json:"properties,omitempty"`
 }
 
 // Error encapsulates metadata regarding a test error.
 type Error struct {
-	XMLName xml.Name `xml:"error"`
-	Message string   `xml:"message,attr,omitempty"`
-	Text    string   `xml:",chardata"`
+	XMLName xml.Name `xml:"error" json:"-"`
+	Message string   `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Type    string   `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Text    string   `xml:",chardata" json:"text,omitempty"`
 }
 
 // Property encapsulates metadata regarding a test property.
 type Property struct {
-	XMLName xml.Name `xml:"property"`
-	Key     string   `xml:"name,attr"`
-	Value   string   `xml:"value,attr"`
+	XMLName xml.Name `xml:"property" json:"-"`
+	Key     string   `xml:"name,attr" json:"name"`
+	Value   string   `xml:"value,attr" json:"value"`
 }
 
 // sortProperties sorts properties alphabetically by key.
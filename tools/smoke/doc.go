@@ -0,0 +1,22 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smoke generates minimal ping-pong load tests for a set of languages.
+// The tests are derived from the examples in config/samples, built from the
+// requested gitrefs with the images in the defaults of the controller, and run
+// for a short time. They are intended to catch broken builds and workers in
+// presubmit checks, rather than to measure performance.
+package smoke
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smoke
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	grpcv1 "github.com/grpc/test-infra/api/v1"
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/languages"
+	"github.com/grpc/test-infra/tools/prebuilt"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+// Options contains the settings used to generate smoke tests.
+type Options struct {
+	// Languages maps image language names to the repository and gitref of
+	// the code to test. A smoke test is generated for each language.
+	Languages map[string]prebuilt.LanguageSpec
+
+	// Defaults are the defaults of the controller. The images of the clone,
+	// build and run containers are set from these defaults.
+	Defaults *config.Defaults
+
+	// SamplesDir is the directory containing the example load tests for each
+	// language, named <LANGUAGE>_example_loadtest.yaml.
+	SamplesDir string
+
+	// NamePrefix is the prefix of the names of the generated tests. It is
+	// also set as the value of the prefix label of each test.
+	NamePrefix string
+
+	// WarmupSeconds is the warmup duration of each scenario.
+	WarmupSeconds int
+
+	// BenchmarkSeconds is the benchmark duration of each scenario.
+	BenchmarkSeconds int
+}
+
+// DefaultOptions returns the options used when no settings are specified.
+func DefaultOptions() *Options {
+	return &Options{
+		SamplesDir:       "config/samples",
+		NamePrefix:       "smoke",
+		WarmupSeconds:    2,
+		BenchmarkSeconds: 5,
+	}
+}
+
+// Validate checks that all required options are set.
+func (o *Options) Validate() error {
+	if len(o.Languages) == 0 {
+		return errors.New("no languages specified")
+	}
+	if o.Defaults == nil {
+		return errors.New("no defaults provided")
+	}
+	if o.SamplesDir == "" {
+		return errors.New("no samples directory provided")
+	}
+	if o.NamePrefix == "" {
+		return errors.New("no name prefix provided")
+	}
+	if o.WarmupSeconds < 0 {
+		return errors.New("warmup duration must not be negative")
+	}
+	if o.BenchmarkSeconds <= 0 {
+		return errors.New("benchmark duration must be positive")
+	}
+	return nil
+}
+
+// LoadDefaults reads and validates the defaults of the controller from a file.
+func LoadDefaults(fileName string) (*config.Defaults, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read defaults file %q: %v", fileName, err)
+	}
+	defaults := new(config.Defaults)
+	if err := yaml.Unmarshal(data, defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse defaults file %q: %v", fileName, err)
+	}
+	if err := defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid defaults in file %q: %v", fileName, err)
+	}
+	return defaults, nil
+}
+
+// SampleFile returns the path of the example load test for a language.
+func (o *Options) SampleFile(lang string) string {
+	return filepath.Join(o.SamplesDir, fmt.Sprintf("%s_example_loadtest.yaml", lang))
+}
+
+// Generate returns a smoke test for each language, sorted by language.
+func Generate(o *Options) ([]*grpcv1.LoadTest, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	var langs []string
+	for lang := range o.Languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var tests []*grpcv1.LoadTest
+	for _, lang := range langs {
+		test, err := generateTest(o, lang, o.Languages[lang])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate smoke test for language %q: %v", lang, err)
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+// generateTest creates the smoke test for a single language from its example.
+func generateTest(o *Options, lang string, spec prebuilt.LanguageSpec) (*grpcv1.LoadTest, error) {
+	samples, err := runner.DecodeFromFiles([]string{o.SampleFile(lang)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read example: %v", err)
+	}
+	if len(samples) != 1 {
+		return nil, fmt.Errorf("expected one example in %s, found %d", o.SampleFile(lang), len(samples))
+	}
+	test := samples[0]
+
+	test.Name = fmt.Sprintf("%s-%s", o.NamePrefix, lang)
+	if test.Labels == nil {
+		test.Labels = make(map[string]string)
+	}
+	test.Labels["prefix"] = o.NamePrefix
+
+	var clones []*grpcv1.Clone
+	for i := range test.Spec.Servers {
+		clones = append(clones, test.Spec.Servers[i].Clone)
+	}
+	for i := range test.Spec.Clients {
+		clones = append(clones, test.Spec.Clients[i].Clone)
+	}
+	for _, clone := range clones {
+		if clone == nil {
+			continue
+		}
+		gitRef := spec.Gitref
+		clone.GitRef = &gitRef
+
+		// The code is cloned from the default repository of the language
+		// when neither the spec nor the example names a repository.
+		repo := spec.Repo
+		if l, ok := languages.Lookup(lang); ok && repo == "" && clone.Repo == nil {
+			repo = l.Repo
+		}
+		if repo != "" {
+			repoURL := fmt.Sprintf("https://github.com/%s.git", repo)
+			clone.Repo = &repoURL
+		}
+	}
+
+	if err := o.Defaults.SetLoadTestDefaults(test); err != nil {
+		return nil, fmt.Errorf("failed to set images from defaults: %v", err)
+	}
+
+	runner.SetScenarioOverrides([]*grpcv1.LoadTest{test}, o.WarmupSeconds, o.BenchmarkSeconds)
+	return test, nil
+}
+
+// Write writes load tests to a stream as a multi-part YAML file, which can be
+// used as input to the runner.
+func Write(w io.Writer, tests []*grpcv1.LoadTest) error {
+	for i, test := range tests {
+		data, err := yaml.Marshal(test)
+		if err != nil {
+			return fmt.Errorf("failed to encode test %q: %v", test.Name, err)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smoke
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/grpc/test-infra/config"
+	"github.com/grpc/test-infra/tools/prebuilt"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+func newOptions() *Options {
+	o := DefaultOptions()
+	o.SamplesDir = "../../config/samples"
+	o.Defaults = &config.Defaults{
+		CloneImage:  "clone:test",
+		ReadyImage:  "ready:test",
+		DriverImage: "driver:test",
+		Languages: []config.LanguageDefault{
+			{Language: "cxx", BuildImage: "bazel:test", RunImage: "cxx:test"},
+			{Language: "go", BuildImage: "golang:test", RunImage: "go:test"},
+		},
+	}
+	o.Languages = map[string]prebuilt.LanguageSpec{
+		"go":  {Name: "go", Repo: "example/grpc-go", Gitref: "abc123"},
+		"cxx": {Name: "cxx", Gitref: "master"},
+	}
+	return o
+}
+
+var _ = Describe("Generate", func() {
+	var o *Options
+
+	BeforeEach(func() {
+		o = newOptions()
+	})
+
+	It("generates one test per language, sorted by language", func() {
+		tests, err := Generate(o)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tests).To(HaveLen(2))
+		Expect(tests[0].Name).To(Equal("smoke-cxx"))
+		Expect(tests[1].Name).To(Equal("smoke-go"))
+		Expect(tests[1].Labels).To(HaveKeyWithValue("prefix", "smoke"))
+	})
+
+	It("clones the requested repository and gitref", func() {
+		tests, err := Generate(o)
+		Expect(err).ToNot(HaveOccurred())
+
+		goTest := tests[1]
+		for _, clone := range []*string{goTest.Spec.Servers[0].Clone.GitRef, goTest.Spec.Clients[0].Clone.GitRef} {
+			Expect(*clone).To(Equal("abc123"))
+		}
+		Expect(*goTest.Spec.Clients[0].Clone.Repo).To(Equal("https://github.com/example/grpc-go.git"))
+
+		cxxTest := tests[0]
+		Expect(*cxxTest.Spec.Clients[0].Clone.GitRef).To(Equal("master"))
+		Expect(*cxxTest.Spec.Clients[0].Clone.Repo).To(Equal("https://github.com/grpc/grpc.git"))
+	})
+
+	It("uses the images from the defaults", func() {
+		tests, err := Generate(o)
+		Expect(err).ToNot(HaveOccurred())
+
+		goTest := tests[1]
+		Expect(*goTest.Spec.Clients[0].Clone.Image).To(Equal("clone:test"))
+		Expect(*goTest.Spec.Clients[0].Build.Image).To(Equal("golang:test"))
+		Expect(goTest.Spec.Clients[0].Run[0].Image).To(Equal("go:test"))
+		Expect(goTest.Spec.Driver.Run[0].Image).To(Equal("driver:test"))
+	})
+
+	It("shortens the scenario", func() {
+		tests, err := Generate(o)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tests[0].Annotations).To(HaveKeyWithValue(config.WarmupSecondsAnnotation, "2"))
+		Expect(tests[0].Annotations).To(HaveKeyWithValue(config.BenchmarkSecondsAnnotation, "5"))
+	})
+
+	It("returns an error when a language has no example", func() {
+		o.Languages["fortran"] = prebuilt.LanguageSpec{Name: "fortran", Gitref: "master"}
+		_, err := Generate(o)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when a language has no default images", func() {
+		o.Languages["java"] = prebuilt.LanguageSpec{Name: "java", Gitref: "master"}
+		_, err := Generate(o)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the benchmark duration is not positive", func() {
+		o.BenchmarkSeconds = 0
+		_, err := Generate(o)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Write", func() {
+	It("writes tests that can be read by the runner", func() {
+		tests, err := Generate(newOptions())
+		Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(Write(&buf, tests)).To(Succeed())
+
+		dir, err := os.MkdirTemp("", "smoke")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		fileName := filepath.Join(dir, "smoke.yaml")
+		Expect(os.WriteFile(fileName, buf.Bytes(), 0644)).To(Succeed())
+
+		decoded, err := runner.DecodeFromFiles([]string{fileName})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decoded).To(HaveLen(len(tests)))
+		for i := range tests {
+			Expect(decoded[i].Name).To(Equal(tests[i].Name))
+			Expect(decoded[i].Spec.ScenariosJSON).To(Equal(tests[i].Spec.ScenariosJSON))
+		}
+	})
+})
+
+var _ = Describe("LoadDefaults", func() {
+	It("returns an error for invalid defaults", func() {
+		dir, err := os.MkdirTemp("", "smoke")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		fileName := filepath.Join(dir, "defaults.yaml")
+		Expect(os.WriteFile(fileName, []byte("cloneImage: clone:test\n"), 0644)).To(Succeed())
+
+		_, err = LoadDefaults(fileName)
+		Expect(err).To(HaveOccurred())
+	})
+})
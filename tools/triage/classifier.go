@@ -0,0 +1,123 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Classifier recognizes a probable cause of failure from a line of a log.
+type Classifier interface {
+	// Cause returns a short name for the cause of failure that this
+	// classifier recognizes, such as "OOM".
+	Cause() string
+
+	// Match returns true if the line of the log is evidence of the cause.
+	Match(line string) bool
+}
+
+// RegexClassifier is a Classifier that matches lines against a regular
+// expression.
+type RegexClassifier struct {
+	// CauseName is the name returned by the Cause method.
+	CauseName string `json:"cause"`
+
+	// Pattern is the regular expression matched against each line.
+	Pattern string `json:"pattern"`
+
+	regex *regexp.Regexp
+}
+
+// NewRegexClassifier creates a RegexClassifier, returning an error if the
+// pattern is not a valid regular expression.
+func NewRegexClassifier(cause, pattern string) (*RegexClassifier, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for cause %q: %v", cause, err)
+	}
+
+	return &RegexClassifier{
+		CauseName: cause,
+		Pattern:   pattern,
+		regex:     regex,
+	}, nil
+}
+
+// Cause implements the Classifier interface.
+func (rc *RegexClassifier) Cause() string {
+	return rc.CauseName
+}
+
+// Match implements the Classifier interface.
+func (rc *RegexClassifier) Match(line string) bool {
+	return rc.regex.MatchString(line)
+}
+
+// mustRegexClassifier creates a RegexClassifier, panicking if the pattern is
+// not valid. It is only meant for patterns that are defined in code.
+func mustRegexClassifier(cause, pattern string) *RegexClassifier {
+	rc, err := NewRegexClassifier(cause, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+// DefaultClassifiers returns classifiers for common causes of failure in load
+// tests. They are ordered from the most to the least specific, since the
+// first classifier that matches a line determines its cause.
+func DefaultClassifiers() []Classifier {
+	return []Classifier{
+		mustRegexClassifier("OOM", `(?i)(out of memory|OOMKilled|oom-kill|java\.lang\.OutOfMemoryError|std::bad_alloc|Cannot allocate memory)`),
+		mustRegexClassifier("xDS NACK", `\bNACK\b|(?i)xds.*(rejected|validation failed|invalid resource)`),
+		mustRegexClassifier("Connection refused", `(?i)(connection refused|ECONNREFUSED|failed to connect to all addresses)`),
+		mustRegexClassifier("Deadline exceeded", `(?i)(DEADLINE_EXCEEDED|deadline exceeded|context deadline exceeded)`),
+		mustRegexClassifier("Build failure", `(?i)(BUILD FAILED|FAILED: Build did NOT complete|compilation (error|failed)|error: linker command failed|\bmake(\[\d+\])?: \*\*\*)`),
+		mustRegexClassifier("Segmentation fault", `(?i)(segmentation fault|SIGSEGV|core dumped)`),
+	}
+}
+
+// LoadClassifiers reads classifiers from a YAML or JSON file. The file should
+// contain a list of objects with "cause" and "pattern" fields.
+func LoadClassifiers(fileName string) ([]Classifier, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read classifiers from %q: %v", fileName, err)
+	}
+
+	var specs []RegexClassifier
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("could not parse classifiers from %q: %v", fileName, err)
+	}
+
+	var classifiers []Classifier
+	for i, spec := range specs {
+		if spec.CauseName == "" {
+			return nil, fmt.Errorf("classifier at index %d in %q has no cause", i, fileName)
+		}
+		rc, err := NewRegexClassifier(spec.CauseName, spec.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		classifiers = append(classifiers, rc)
+	}
+	return classifiers, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1types "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	clientset "github.com/grpc/test-infra/clientset"
+	"github.com/grpc/test-infra/tools/runner"
+)
+
+// LogsFromFiles reads logs from files. Since files do not identify the test
+// that produced them, the name of the directory that contains each file is
+// used as the name of the test.
+func LogsFromFiles(fileNames []string) ([]Log, error) {
+	var logs []Log
+	for _, fileName := range fileNames {
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("could not read log file %q: %v", fileName, err)
+		}
+		logs = append(logs, Log{
+			Test:    filepath.Base(filepath.Dir(fileName)),
+			Name:    filepath.Base(fileName),
+			Content: content,
+		})
+	}
+	return logs, nil
+}
+
+// LogsFromCluster fetches the logs of every container in the pods of the
+// named load tests.
+func LogsFromCluster(ctx context.Context, loadTestGetter clientset.LoadTestGetter, podsGetter corev1types.PodsGetter, testNames []string) ([]Log, error) {
+	var logs []Log
+	for _, testName := range testNames {
+		loadTest, err := loadTestGetter.Get(ctx, testName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("could not get test %q: %v", testName, err)
+		}
+
+		pods, err := runner.GetTestPods(ctx, loadTest, podsGetter)
+		if err != nil {
+			return nil, fmt.Errorf("could not get pods for test %q: %v", testName, err)
+		}
+
+		for _, pod := range pods {
+			var containers []corev1.Container
+			containers = append(containers, pod.Spec.InitContainers...)
+			containers = append(containers, pod.Spec.Containers...)
+
+			for _, container := range containers {
+				req := podsGetter.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container.Name})
+				stream, err := req.Stream(ctx)
+				if err != nil {
+					// Containers that never started have no logs.
+					continue
+				}
+				buf := new(bytes.Buffer)
+				_, err = buf.ReadFrom(stream)
+				stream.Close()
+				if err != nil {
+					return nil, fmt.Errorf("could not read log of container %q in pod %q: %v", container.Name, pod.Name, err)
+				}
+
+				logs = append(logs, Log{
+					Test:    testName,
+					Name:    runner.LogFileName(pod.Name, container.Name),
+					Content: buf.Bytes(),
+				})
+			}
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxExcerptLength is the longest excerpt of a log line kept as evidence.
+const maxExcerptLength = 200
+
+// UnknownCause is the cause assigned to logs that no classifier matched.
+const UnknownCause = "Unknown"
+
+// Log is the content of a single log, such as the log of one container.
+type Log struct {
+	// Test is the name of the load test that produced the log.
+	Test string `json:"test"`
+
+	// Name identifies the log within the test, such as a pod and container
+	// name or a file path.
+	Name string `json:"name"`
+
+	// Content is the text of the log.
+	Content []byte `json:"-"`
+}
+
+// Finding is evidence of a cause of failure within a log.
+type Finding struct {
+	// Test is the name of the load test that produced the log.
+	Test string `json:"test"`
+
+	// Log identifies the log where the evidence was found.
+	Log string `json:"log"`
+
+	// Line is the one-based number of the line that matched.
+	Line int `json:"line"`
+
+	// Excerpt is the (possibly truncated) text of the matching line.
+	Excerpt string `json:"excerpt"`
+}
+
+// Summary groups the findings for a set of logs by their cause.
+type Summary struct {
+	// Causes maps the name of each cause to the findings for it. Each log
+	// contributes at most one finding per cause.
+	Causes map[string][]Finding `json:"causes"`
+
+	// Tests maps the name of each test to the causes found in its logs. A
+	// test without findings is mapped to UnknownCause.
+	Tests map[string][]string `json:"tests"`
+}
+
+// Classify applies classifiers to each line of the logs, returning a summary
+// of the probable causes of failure. The first classifier that matches a line
+// determines its cause.
+func Classify(logs []Log, classifiers []Classifier) *Summary {
+	summary := &Summary{
+		Causes: make(map[string][]Finding),
+		Tests:  make(map[string][]string),
+	}
+	testCauses := make(map[string]map[string]bool)
+
+	for _, l := range logs {
+		if _, ok := testCauses[l.Test]; !ok {
+			testCauses[l.Test] = make(map[string]bool)
+		}
+		logCauses := make(map[string]bool)
+
+		scanner := bufio.NewScanner(bytes.NewReader(l.Content))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		lineNumber := 0
+		for scanner.Scan() {
+			lineNumber++
+			line := scanner.Text()
+			for _, c := range classifiers {
+				if !c.Match(line) {
+					continue
+				}
+				cause := c.Cause()
+				if !logCauses[cause] {
+					logCauses[cause] = true
+					testCauses[l.Test][cause] = true
+					summary.Causes[cause] = append(summary.Causes[cause], Finding{
+						Test:    l.Test,
+						Log:     l.Name,
+						Line:    lineNumber,
+						Excerpt: excerpt(line),
+					})
+				}
+				break
+			}
+		}
+	}
+
+	for test, causes := range testCauses {
+		var names []string
+		for cause := range causes {
+			names = append(names, cause)
+		}
+		if len(names) == 0 {
+			names = append(names, UnknownCause)
+		}
+		sort.Strings(names)
+		summary.Tests[test] = names
+	}
+
+	return summary
+}
+
+// WriteText writes a human-readable report of the summary, listing causes
+// from the most to the least frequent.
+func (s *Summary) WriteText(w io.Writer) error {
+	var causes []string
+	for cause := range s.Causes {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if len(s.Causes[causes[i]]) != len(s.Causes[causes[j]]) {
+			return len(s.Causes[causes[i]]) > len(s.Causes[causes[j]])
+		}
+		return causes[i] < causes[j]
+	})
+
+	b := &strings.Builder{}
+	for _, cause := range causes {
+		findings := s.Causes[cause]
+		fmt.Fprintf(b, "%s (%d):\n", cause, len(findings))
+		for _, f := range findings {
+			fmt.Fprintf(b, "  %s %s:%d: %s\n", f.Test, f.Log, f.Line, f.Excerpt)
+		}
+	}
+
+	var unknown []string
+	for test, names := range s.Tests {
+		if len(names) == 1 && names[0] == UnknownCause {
+			unknown = append(unknown, test)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Fprintf(b, "%s (%d):\n", UnknownCause, len(unknown))
+		for _, test := range unknown {
+			fmt.Fprintf(b, "  %s\n", test)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// excerpt trims a line and truncates it to the maximum excerpt length.
+func excerpt(line string) string {
+	line = strings.TrimSpace(line)
+	if len(line) > maxExcerptLength {
+		return line[:maxExcerptLength] + "..."
+	}
+	return line
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 gRPC authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package triage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DefaultClassifiers", func() {
+	expectCause := func(line string, cause string) {
+		for _, c := range DefaultClassifiers() {
+			if c.Match(line) {
+				Expect(c.Cause()).To(Equal(cause))
+				return
+			}
+		}
+		Fail("no classifier matched line: " + line)
+	}
+
+	expectNotCause := func(line string, cause string) {
+		for _, c := range DefaultClassifiers() {
+			if c.Match(line) {
+				Expect(c.Cause()).ToNot(Equal(cause), line)
+				return
+			}
+		}
+	}
+
+	It("recognizes out of memory errors", func() {
+		expectCause("terminate called after throwing an instance of 'std::bad_alloc'", "OOM")
+		expectCause("Exception in thread \"main\" java.lang.OutOfMemoryError: Java heap space", "OOM")
+	})
+
+	It("recognizes refused connections", func() {
+		expectCause("E0101 connect failed: Connection refused", "Connection refused")
+	})
+
+	It("recognizes xDS NACKs", func() {
+		expectCause("xds client: ADS sending NACK for resource type listener", "xDS NACK")
+		expectCause("xds: listener resource rejected: missing filter", "xDS NACK")
+	})
+
+	It("does not mistake words containing NACK for xDS NACKs", func() {
+		expectNotCause("warning: 3 messages unacknowledged after shutdown", "xDS NACK")
+		expectNotCause("request was nacked by the queue", "xDS NACK")
+		expectNotCause("Snack bar closed", "xDS NACK")
+	})
+
+	It("recognizes build failures", func() {
+		expectCause("FAILED: Build did NOT complete successfully", "Build failure")
+		expectCause("make[2]: *** [Makefile:12: all] Error 1", "Build failure")
+	})
+})
+
+var _ = Describe("Classify", func() {
+	var classifiers []Classifier
+
+	BeforeEach(func() {
+		classifiers = DefaultClassifiers()
+	})
+
+	It("groups findings by cause", func() {
+		logs := []Log{
+			{Test: "test-a", Name: "driver.log", Content: []byte("starting\nconnect failed: Connection refused\n")},
+			{Test: "test-b", Name: "server.log", Content: []byte("java.lang.OutOfMemoryError\n")},
+			{Test: "test-c", Name: "client.log", Content: []byte("connection refused\n")},
+		}
+
+		summary := Classify(logs, classifiers)
+		Expect(summary.Causes).To(HaveLen(2))
+		Expect(summary.Causes["Connection refused"]).To(HaveLen(2))
+		Expect(summary.Causes["Connection refused"][0]).To(Equal(Finding{
+			Test:    "test-a",
+			Log:     "driver.log",
+			Line:    2,
+			Excerpt: "connect failed: Connection refused",
+		}))
+		Expect(summary.Causes["OOM"]).To(HaveLen(1))
+		Expect(summary.Tests["test-b"]).To(Equal([]string{"OOM"}))
+	})
+
+	It("records at most one finding per cause in each log", func() {
+		logs := []Log{
+			{Test: "test", Name: "client.log", Content: []byte("connection refused\nconnection refused\n")},
+		}
+
+		summary := Classify(logs, classifiers)
+		Expect(summary.Causes["Connection refused"]).To(HaveLen(1))
+	})
+
+	It("marks tests without findings as unknown", func() {
+		logs := []Log{
+			{Test: "test", Name: "client.log", Content: []byte("everything is fine\n")},
+		}
+
+		summary := Classify(logs, classifiers)
+		Expect(summary.Causes).To(BeEmpty())
+		Expect(summary.Tests["test"]).To(Equal([]string{UnknownCause}))
+
+		buf := new(bytes.Buffer)
+		Expect(summary.WriteText(buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("Unknown (1):\n  test\n"))
+	})
+
+	It("uses the first classifier that matches", func() {
+		custom, err := NewRegexClassifier("Custom", "refused")
+		Expect(err).ToNot(HaveOccurred())
+
+		logs := []Log{
+			{Test: "test", Name: "client.log", Content: []byte("connection refused\n")},
+		}
+
+		summary := Classify(logs, append([]Classifier{custom}, classifiers...))
+		Expect(summary.Causes).To(HaveKey("Custom"))
+		Expect(summary.Causes).ToNot(HaveKey("Connection refused"))
+	})
+})
+
+var _ = Describe("LoadClassifiers", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "triage")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("loads classifiers from a YAML file", func() {
+		fileName := filepath.Join(dir, "classifiers.yaml")
+		Expect(os.WriteFile(fileName, []byte("- cause: Quota\n  pattern: quota exceeded\n"), 0644)).To(Succeed())
+
+		classifiers, err := LoadClassifiers(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(classifiers).To(HaveLen(1))
+		Expect(classifiers[0].Cause()).To(Equal("Quota"))
+		Expect(classifiers[0].Match("error: quota exceeded")).To(BeTrue())
+	})
+
+	It("returns an error for invalid patterns", func() {
+		fileName := filepath.Join(dir, "classifiers.yaml")
+		Expect(os.WriteFile(fileName, []byte("- cause: Bad\n  pattern: \"(\"\n"), 0644)).To(Succeed())
+
+		_, err := LoadClassifiers(fileName)
+		Expect(err).To(HaveOccurred())
+	})
+})